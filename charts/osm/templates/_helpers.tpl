@@ -76,6 +76,15 @@ securityContext:
 {{- end -}}
 {{- end -}}
 
+{{/* osm-cni image */}}
+{{- define "osmCNI.image" -}}
+{{- if .Values.osm.image.tag -}}
+{{- printf "%s/osm-cni:%s" .Values.osm.image.registry .Values.osm.image.tag -}}
+{{- else -}}
+{{- printf "%s/osm-cni@%s" .Values.osm.image.registry .Values.osm.image.digest.osmCNI -}}
+{{- end -}}
+{{- end -}}
+
 {{/* osm-crds image */}}
 {{- define "osmCRDs.image" -}}
 {{- if .Values.osm.image.tag -}}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+)
+
+const egressCmdDescription = `
+This command consists of subcommands related to the egress traffic
+of the service mesh.
+`
+
+func newEgressCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "egress",
+		Short: "egress traffic operations",
+		Long:  egressCmdDescription,
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newEgressReportCmd(config, out))
+
+	return cmd
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+)
+
+const egressReportCmdDescription = `
+This command reports the egress traffic of each source service account
+to each external destination over a time window.
+
+The traffic is aggregated from the egress metrics recorded by the Prometheus
+instance deployed by OSM in the OSM namespace, which is port forwarded to.
+Destinations that are not matched on a host, such as TCP destinations matched
+on IP ranges, are reported with the host '*'.
+`
+
+const egressReportCmdExample = `
+# Report the egress traffic of the last hour
+osm egress report
+
+# Report the egress traffic of the last 24 hours
+osm egress report --window 24h
+`
+
+const defaultPrometheusLocalPort = 7070
+
+type egressReportCmd struct {
+	out       io.Writer
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	localPort uint16
+	window    string
+}
+
+func newEgressReportCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	reportCmd := &egressReportCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "report egress traffic per source and destination",
+		Long:  egressReportCmdDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			conf, err := config.RESTClientGetter.ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			reportCmd.config = conf
+
+			clientset, err := kubernetes.NewForConfig(conf)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			reportCmd.clientSet = clientset
+			return reportCmd.run()
+		},
+		Example: egressReportCmdExample,
+	}
+
+	f := cmd.Flags()
+	f.Uint16VarP(&reportCmd.localPort, "local-port", "p", defaultPrometheusLocalPort, "Local port to use for port forwarding")
+	f.StringVarP(&reportCmd.window, "window", "w", "1h", "Time window to report the egress traffic over, as a Prometheus duration")
+
+	return cmd
+}
+
+func (cmd *egressReportCmd) run() error {
+	report, err := cli.GetEgressTrafficReport(cmd.clientSet, cmd.config, settings.Namespace(), cmd.localPort, cmd.window)
+	if err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error generating egress traffic report: %s", err)
+	}
+
+	if len(report) == 0 {
+		fmt.Fprintf(cmd.out, "No egress traffic found in the last %s\n", cmd.window)
+		return nil
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprintln(w, "SOURCE NAMESPACE\tSOURCE SERVICE ACCOUNT\tHOST\tPORT\tREQUESTS\tCONNECTIONS\tBYTES SENT\tBYTES RECEIVED\t")
+	for _, summary := range report {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.0f\t%.0f\t%.0f\t%.0f\t\n",
+			summary.SourceNamespace, summary.SourceServiceAccount, summary.Host, summary.Port,
+			summary.Requests, summary.Connections, summary.BytesSent, summary.BytesReceived)
+	}
+	return w.Flush()
+}
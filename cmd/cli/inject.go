@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gomodules.xyz/jsonpatch/v2"
+	"helm.sh/helm/v3/pkg/action"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/injector"
+)
+
+const injectCmdDescription = `
+This command previews the Envoy proxy sidecar injection of the pods and the
+pod templates of the workloads in the given manifests, such as Deployments,
+StatefulSets, DaemonSets, Jobs and CronJobs.
+
+The manifests are mutated by the sidecar injector deployed by OSM in the OSM
+namespace, with the same logic as the pods admitted by the injector, without
+issuing certificates or creating the bootstrap config secrets of the sidecars.
+Nothing is applied to the cluster. The preview server of the sidecar injector
+only listens on its loopback interface and is port forwarded to, which requires
+permission to port forward to the pods of the OSM namespace.
+
+The mutated manifests are written in YAML, or the JSON patches mutating them
+when '--diff' is set. The reason the sidecar is not injected in a manifest is
+written to the standard error, and the manifest is written unchanged.
+`
+
+const injectCmdExample = `
+# Preview the sidecar injection of the workloads in 'bookstore.yaml'
+osm inject -f bookstore.yaml
+
+# Preview the JSON patches injecting the sidecar in the workloads of the 'bookstore' namespace read from stdin
+cat bookstore.yaml | osm inject -f - -n bookstore --diff
+`
+
+type injectCmd struct {
+	out       io.Writer
+	errOut    io.Writer
+	in        io.Reader
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	filename  string
+	namespace string
+	localPort uint16
+	diff      bool
+}
+
+func newInjectCmd(config *action.Configuration, in io.Reader, out io.Writer, errOut io.Writer) *cobra.Command {
+	inject := &injectCmd{
+		out:    out,
+		errOut: errOut,
+		in:     in,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "preview the sidecar injection of manifests",
+		Long:  injectCmdDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			conf, err := config.RESTClientGetter.ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			inject.config = conf
+
+			clientset, err := kubernetes.NewForConfig(conf)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			inject.clientSet = clientset
+			return inject.run()
+		},
+		Example: injectCmdExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&inject.filename, "filename", "f", "", "File containing the manifests, '-' to read them from stdin")
+	f.StringVarP(&inject.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of the manifests that do not specify one")
+	f.Uint16VarP(&inject.localPort, "local-port", "p", constants.InjectorPreviewPort, "Local port to use for port forwarding")
+	f.BoolVar(&inject.diff, "diff", false, "Write the JSON patches injecting the sidecar instead of the mutated manifests")
+
+	return cmd
+}
+
+func (cmd *injectCmd) run() error {
+	if cmd.filename == "" {
+		return errors.New("The file containing the manifests must be specified with --filename")
+	}
+
+	in := cmd.in
+	if cmd.filename != "-" {
+		file, err := os.Open(cmd.filename)
+		if err != nil {
+			return errors.Errorf("Error opening file %s: %s", cmd.filename, err)
+		}
+		//nolint: errcheck
+		//#nosec G307
+		defer file.Close()
+		in = file
+	}
+
+	manifests, err := readManifests(in)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		return errors.Errorf("No manifest found in %s", cmd.filename)
+	}
+
+	results, err := cli.PreviewSidecarInjection(cmd.clientSet, cmd.config, settings.Namespace(), cmd.localPort, manifests, cmd.namespace)
+	if err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error previewing sidecar injection: %s", err)
+	}
+
+	return cmd.printResults(manifests, results)
+}
+
+// printResults writes the mutated manifests, or the patches mutating them, and the reasons the sidecar is not injected
+func (cmd *injectCmd) printResults(manifests [][]byte, results []injector.InjectionResult) error {
+	for i, result := range results {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifests[i]); err != nil {
+			return err
+		}
+		if !result.Injected {
+			fmt.Fprintf(cmd.errOut, "Sidecar not injected in %s %s: %s\n", obj.GetKind(), obj.GetName(), result.Reason)
+		}
+
+		if cmd.diff {
+			// The patch of a manifest the sidecar is not injected in is empty
+			operations := result.Patch
+			if operations == nil {
+				operations = []jsonpatch.JsonPatchOperation{}
+			}
+			patch, err := json.MarshalIndent(operations, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.out, "# %s %s\n%s\n", obj.GetKind(), obj.GetName(), patch)
+			continue
+		}
+
+		mutated := manifests[i]
+		if result.Injected {
+			mutated = result.Object
+		}
+		manifest, err := yaml.JSONToYAML(mutated)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(cmd.out, "---")
+		}
+		fmt.Fprint(cmd.out, string(manifest))
+	}
+	return nil
+}
+
+// readManifests returns the JSON encoding of each of the YAML or JSON manifests read from the given reader
+func readManifests(in io.Reader) ([][]byte, error) {
+	var manifests [][]byte
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(in))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return manifests, nil
+		}
+		if err != nil {
+			return nil, errors.Errorf("Error reading manifests: %s", err)
+		}
+
+		manifest, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, errors.Errorf("Error decoding manifest: %s", err)
+		}
+		// Skip the empty documents
+		if manifest = bytes.TrimSpace(manifest); len(manifest) == 0 || bytes.Equal(manifest, []byte("null")) {
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+}
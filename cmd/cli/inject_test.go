@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"gomodules.xyz/jsonpatch/v2"
+
+	"github.com/openservicemesh/osm/pkg/injector"
+)
+
+func TestReadManifests(t *testing.T) {
+	assert := tassert.New(t)
+
+	manifests, err := readManifests(strings.NewReader(`---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bookbuyer
+---
+# Comment only
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bookstore
+`))
+	assert.NoError(err)
+	assert.Equal([][]byte{
+		[]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"bookbuyer"}}`),
+		[]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"bookstore"}}`),
+	}, manifests)
+
+	_, err = readManifests(strings.NewReader("kind: [Pod"))
+	assert.Error(err)
+}
+
+func TestInjectPrintResults(t *testing.T) {
+	manifests := [][]byte{
+		[]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"bookbuyer"}}`),
+		[]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"bookstore"}}`),
+	}
+	results := []injector.InjectionResult{
+		{
+			Injected: true,
+			Object:   []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"bookbuyer","labels":{"osm-proxy-uuid":"abc"}}}`),
+			Patch: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: "/metadata/labels", Value: map[string]interface{}{"osm-proxy-uuid": "abc"}},
+			},
+		},
+		{
+			Reason: "The pod is on the host network",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		diff        bool
+		expectedOut string
+	}{
+		{
+			name: "mutated manifests",
+			expectedOut: `apiVersion: v1
+kind: Pod
+metadata:
+  labels:
+    osm-proxy-uuid: abc
+  name: bookbuyer
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bookstore
+`,
+		},
+		{
+			name: "patches",
+			diff: true,
+			expectedOut: `# Pod bookbuyer
+[
+  {
+    "op": "add",
+    "path": "/metadata/labels",
+    "value": {
+      "osm-proxy-uuid": "abc"
+    }
+  }
+]
+# Pod bookstore
+[]
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			errOut := new(bytes.Buffer)
+			cmd := &injectCmd{
+				out:    out,
+				errOut: errOut,
+				diff:   tc.diff,
+			}
+
+			assert.NoError(cmd.printResults(manifests, results))
+			assert.Equal(tc.expectedOut, out.String())
+			assert.Equal("Sidecar not injected in Pod bookstore: The pod is on the host network\n", errOut.String())
+		})
+	}
+}
@@ -43,6 +43,8 @@ func newRootCmd(config *action.Configuration, stdin io.Reader, stdout io.Writer,
 		newMetricsCmd(stdout),
 		newVersionCmd(stdout),
 		newProxyCmd(config, stdout),
+		newEgressCmd(config, stdout),
+		newInjectCmd(config, stdin, stdout, stderr),
 		newPolicyCmd(stdout, stderr),
 		newSupportCmd(config, stdout, stderr),
 		newUninstallCmd(config, stdin, stdout),
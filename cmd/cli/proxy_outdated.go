@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const proxyOutdatedDescription = `
+This command lists the pods running an outdated Envoy proxy sidecar, whose
+configuration differs from the configuration that would be injected with the
+current mesh configuration, such as after an upgrade of the sidecar image.
+
+The pods running an outdated sidecar are detected by the sidecar injector. Their
+workloads are restarted when the automatic rollout of outdated sidecars is
+enabled in the MeshConfig with 'spec.sidecar.rollout.enable'.
+`
+
+const proxyOutdatedExample = `
+# List the pods running an outdated sidecar in all namespaces
+osm proxy outdated
+
+# List the pods running an outdated sidecar in the 'bookstore' namespace
+osm proxy outdated -n bookstore
+`
+
+type proxyOutdatedCmd struct {
+	out       io.Writer
+	clientSet kubernetes.Interface
+	namespace string
+}
+
+func newProxyOutdatedCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	outdatedCmd := &proxyOutdatedCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "list pods running an outdated sidecar",
+		Long:  proxyOutdatedDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			conf, err := config.RESTClientGetter.ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+
+			clientset, err := kubernetes.NewForConfig(conf)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			outdatedCmd.clientSet = clientset
+			return outdatedCmd.run()
+		},
+		Example: proxyOutdatedExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&outdatedCmd.namespace, "namespace", "n", metav1.NamespaceAll, "Namespace of the pods, all namespaces if unspecified")
+
+	return cmd
+}
+
+func (cmd *proxyOutdatedCmd) run() error {
+	pods, err := cmd.clientSet.CoreV1().Pods(cmd.namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: constants.EnvoyUniqueIDLabelName,
+	})
+	if err != nil {
+		return errors.Errorf("Could not list pods: %s", err)
+	}
+
+	w := newTabWriter(cmd.out)
+	found := false
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if _, outdated := pod.Annotations[constants.SidecarOutdatedAnnotation]; !outdated {
+			continue
+		}
+		if !found {
+			fmt.Fprintln(w, "NAMESPACE\tPOD\tCONTROLLER")
+			found = true
+		}
+
+		controller := "-"
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			controller = fmt.Sprintf("%s/%s", owner.Kind, owner.Name)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", pod.Namespace, pod.Name, controller)
+	}
+	_ = w.Flush()
+
+	if !found {
+		fmt.Fprintln(cmd.out, "No pods running an outdated sidecar found")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestProxyOutdated(t *testing.T) {
+	newPod := func(namespace, name string, outdated bool, owners ...metav1.OwnerReference) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				Labels:          map[string]string{constants.EnvoyUniqueIDLabelName: name},
+				Annotations:     map[string]string{constants.SidecarHashAnnotation: "abc"},
+				OwnerReferences: owners,
+			},
+		}
+		if outdated {
+			pod.Annotations[constants.SidecarOutdatedAnnotation] = "true"
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		pods      []*corev1.Pod
+		expected  string
+	}{
+		{
+			name:     "no pods",
+			expected: "No pods running an outdated sidecar found\n",
+		},
+		{
+			name: "no outdated pods",
+			pods: []*corev1.Pod{
+				newPod("ns1", "pod1", false),
+			},
+			expected: "No pods running an outdated sidecar found\n",
+		},
+		{
+			name: "outdated pods in all namespaces",
+			pods: []*corev1.Pod{
+				newPod("ns1", "pod1", true, metav1.OwnerReference{Kind: "ReplicaSet", Name: "bookstore-5ccf77f46d", Controller: pointer.BoolPtr(true)}),
+				newPod("ns1", "pod2", false),
+				newPod("ns2", "pod3", true),
+			},
+			expected: "NAMESPACE\tPOD\tCONTROLLER\n" +
+				"ns1\tpod1\tReplicaSet/bookstore-5ccf77f46d\n" +
+				"ns2\tpod3\t-\n",
+		},
+		{
+			name:      "outdated pods in a namespace",
+			namespace: "ns2",
+			pods: []*corev1.Pod{
+				newPod("ns1", "pod1", true),
+				newPod("ns2", "pod2", true),
+			},
+			expected: "NAMESPACE\tPOD\tCONTROLLER\n" +
+				"ns2\tpod2\t-\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			buf := bytes.NewBuffer(nil)
+
+			objs := make([]runtime.Object, len(test.pods))
+			for i := range test.pods {
+				objs[i] = test.pods[i]
+			}
+
+			cmd := proxyOutdatedCmd{
+				out:       buf,
+				namespace: test.namespace,
+				clientSet: fake.NewSimpleClientset(objs...),
+			}
+
+			assert.Nil(cmd.run())
+
+			expected := bytes.NewBuffer(nil)
+			expTw := newTabWriter(expected)
+			_, err := expTw.Write([]byte(test.expected))
+			assert.Nil(err)
+			assert.Nil(expTw.Flush())
+
+			assert.Equal(expected.String(), buf.String())
+		})
+	}
+}
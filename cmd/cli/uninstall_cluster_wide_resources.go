@@ -148,6 +148,7 @@ func (d *uninstallClusterWideResourcesCmd) uninstallCustomResourceDefinitions()
 		"ingressbackends.policy.openservicemesh.io",
 		"meshconfigs.config.openservicemesh.io",
 		"multiclusterservices.config.openservicemesh.io",
+		"sidecarconfigs.config.openservicemesh.io",
 		"httproutegroups.specs.smi-spec.io",
 		"tcproutes.specs.smi-spec.io",
 		"trafficsplits.split.smi-spec.io",
@@ -0,0 +1,77 @@
+// Package main implements the main entrypoint for osm-cni.
+// osm-cni is the OSM CNI plugin, which programs the traffic interception rules of the pods injected with the
+// sidecar when their network sandbox is created. When invoked by the container runtime, osm-cni runs the given
+// CNI command. Otherwise, osm-cni installs itself on the node it runs on, and uninstalls itself on exit.
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cni"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/signals"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+var (
+	verbosity string
+	installer cni.Installer
+)
+
+var (
+	flags = pflag.NewFlagSet(`osm-cni`, pflag.ExitOnError)
+	log   = logger.New("osm-cni/main")
+)
+
+func init() {
+	flags.StringVarP(&verbosity, "verbosity", "v", "info", "Set log verbosity level")
+	flags.StringVar(&installer.BinDir, "cni-bin-dir", "/host/opt/cni/bin", "Directory of the CNI plugin binaries of the node")
+	flags.StringVar(&installer.ConfDir, "cni-conf-dir", "/host/etc/cni/net.d", "Directory of the CNI network configuration files of the node")
+	flags.StringVar(&installer.OSMNamespace, "osm-namespace", "", "Namespace of the OSM control plane")
+	flags.StringVar(&installer.NodeName, "node-name", "", "Name of the node the CNI plugin is installed on")
+	flags.StringSliceVar(&installer.ExcludeNamespaces, "exclude-namespaces", []string{"kube-system"}, "Namespaces whose pods are ignored by the CNI plugin")
+}
+
+func main() {
+	// The container runtime invokes CNI plugins with the CNI command in the environment
+	if os.Getenv("CNI_COMMAND") != "" {
+		if err := cni.Run(os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	log.Info().Msgf("Starting osm-cni %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+	if err := parseFlags(); err != nil {
+		log.Fatal().Err(err).Msg("Error parsing cmd line arguments")
+	}
+	if err := logger.SetLogLevel(verbosity); err != nil {
+		log.Fatal().Err(err).Msg("Error setting log level")
+	}
+
+	kubeConfig, err := rest.InClusterConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error creating in-cluster kube config")
+	}
+	installer.KubeClient = kubernetes.NewForConfigOrDie(kubeConfig)
+
+	stop := signals.RegisterExitHandlers()
+	if err := installer.Run(stop); err != nil {
+		log.Fatal().Err(err).Msg("Error installing the OSM CNI plugin")
+	}
+
+	log.Info().Msgf("Stopping osm-cni %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
+}
+
+func parseFlags() error {
+	if err := flags.Parse(os.Args); err != nil {
+		return err
+	}
+	_ = flag.CommandLine.Parse([]string{})
+	return nil
+}
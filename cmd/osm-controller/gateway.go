@@ -14,25 +14,46 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
 	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/ingressgateway"
 	"github.com/openservicemesh/osm/pkg/multicluster"
 	"github.com/openservicemesh/osm/pkg/utils"
 )
 
 const (
-	gatewayBootstrapSecretName = "osm-multicluster-gateway-bootstrap-config" // #nosec G101: Potential hardcoded credentials
-	bootstrapConfigKey         = "bootstrap.yaml"
+	gatewayBootstrapSecretName        = "osm-multicluster-gateway-bootstrap-config" // #nosec G101: Potential hardcoded credentials
+	egressGatewayBootstrapSecretName  = "osm-egress-gateway-bootstrap-config"       // #nosec G101: Potential hardcoded credentials
+	ingressGatewayBootstrapSecretName = "osm-ingress-gateway-bootstrap-config"      // #nosec G101: Potential hardcoded credentials
+	bootstrapConfigKey                = "bootstrap.yaml"
 )
 
 func bootstrapOSMMulticlusterGateway(kubeClient kubernetes.Interface, certManager certificate.Manager, osmNamespace string) error {
-	secret, err := kubeClient.CoreV1().Secrets(osmNamespace).Get(context.Background(), gatewayBootstrapSecretName, metav1.GetOptions{})
+	gatewayCN := multicluster.GetMulticlusterGatewaySubjectCommonName(multicluster.GatewayServiceAccountName, osmNamespace)
+	return bootstrapOSMGateway(kubeClient, certManager, osmNamespace, gatewayBootstrapSecretName, gatewayCN)
+}
+
+func bootstrapOSMEgressGateway(kubeClient kubernetes.Interface, certManager certificate.Manager, osmNamespace string) error {
+	gatewayCN := egressgateway.GetEgressGatewaySubjectCommonName(osmServiceAccount, osmNamespace)
+	return bootstrapOSMGateway(kubeClient, certManager, osmNamespace, egressGatewayBootstrapSecretName, gatewayCN)
+}
+
+func bootstrapOSMIngressGateway(kubeClient kubernetes.Interface, certManager certificate.Manager, osmNamespace string) error {
+	gatewayCN := ingressgateway.GetIngressGatewaySubjectCommonName(osmServiceAccount, osmNamespace)
+	return bootstrapOSMGateway(kubeClient, certManager, osmNamespace, ingressGatewayBootstrapSecretName, gatewayCN)
+}
+
+// bootstrapOSMGateway writes the bootstrap config for the OSM gateway with the given certificate common name
+// to the given bootstrap secret, unless the secret already holds a valid bootstrap config.
+func bootstrapOSMGateway(kubeClient kubernetes.Interface, certManager certificate.Manager, osmNamespace string, secretName string, gatewayCN certificate.CommonName) error {
+	secret, err := kubeClient.CoreV1().Secrets(osmNamespace).Get(context.Background(), secretName, metav1.GetOptions{})
 	if err != nil {
-		return errors.Errorf("Error fetching OSM gateway's bootstrap config %s/%s", osmNamespace, gatewayBootstrapSecretName)
+		return errors.Errorf("Error fetching OSM gateway's bootstrap config %s/%s", osmNamespace, secretName)
 	}
 
 	if bootstrapData, ok := secret.Data[bootstrapConfigKey]; !ok {
-		return errors.Errorf("Missing OSM gateway bootstrap config in %s/%s", osmNamespace, gatewayBootstrapSecretName)
+		return errors.Errorf("Missing OSM gateway bootstrap config in %s/%s", osmNamespace, secretName)
 	} else if isValidBootstrapData(bootstrapData) {
 		// If there is a valid bootstrap config, it means we do not need to reconfigure it. It implies
 		// osm-controller restarted after creating the bootstrap config previously.
@@ -40,7 +61,6 @@ func bootstrapOSMMulticlusterGateway(kubeClient kubernetes.Interface, certManage
 		return nil
 	}
 
-	gatewayCN := multicluster.GetMulticlusterGatewaySubjectCommonName(osmServiceAccount, osmNamespace)
 	bootstrapCert, err := certManager.IssueCertificate(gatewayCN, constants.XDSCertificateValidityPeriod)
 	if err != nil {
 		return errors.Errorf("Error issuing bootstrap certificate for OSM gateway: %s", err)
@@ -57,17 +77,17 @@ func bootstrapOSMMulticlusterGateway(kubeClient kubernetes.Interface, certManage
 		PrivateKey:       bootstrapCert.GetPrivateKey(),
 	})
 	if err != nil {
-		return errors.Errorf("Error building OSM gateway's bootstrap config from %s/%s", osmNamespace, gatewayBootstrapSecretName)
+		return errors.Errorf("Error building OSM gateway's bootstrap config from %s/%s", osmNamespace, secretName)
 	}
 
 	bootstrapData, err := utils.ProtoToYAML(bootstrapConfig)
 	if err != nil {
-		return errors.Errorf("Error marshalling updated OSM gateway's bootstrap config from %s/%s", osmNamespace, gatewayBootstrapSecretName)
+		return errors.Errorf("Error marshalling updated OSM gateway's bootstrap config from %s/%s", osmNamespace, secretName)
 	}
 
 	updatedSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      gatewayBootstrapSecretName,
+			Name:      secretName,
 			Namespace: osmNamespace,
 		},
 		Data: map[string][]byte{
@@ -80,8 +100,8 @@ func bootstrapOSMMulticlusterGateway(kubeClient kubernetes.Interface, certManage
 		return err
 	}
 
-	if _, err = kubeClient.CoreV1().Secrets(osmNamespace).Patch(context.Background(), gatewayBootstrapSecretName, types.StrategicMergePatchType, patchJSON, metav1.PatchOptions{}); err != nil {
-		return errors.Errorf("Error patching OSM gateway's bootstrap secret %s/%s: %s", osmNamespace, gatewayBootstrapSecretName, err)
+	if _, err = kubeClient.CoreV1().Secrets(osmNamespace).Patch(context.Background(), secretName, types.StrategicMergePatchType, patchJSON, metav1.PatchOptions{}); err != nil {
+		return errors.Errorf("Error patching OSM gateway's bootstrap secret %s/%s: %s", osmNamespace, secretName, err)
 	}
 
 	return nil
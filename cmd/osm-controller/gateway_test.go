@@ -151,6 +151,66 @@ static_resources:
 	}
 }
 
+func TestBootstrapOSMEgressGateway(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	fakeCertManager := tresor.NewFakeCertManager(mockConfigurator)
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(15 * time.Second).AnyTimes()
+	mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+
+	testNs := "test"
+	fakeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      egressGatewayBootstrapSecretName,
+			Namespace: testNs,
+		},
+		Data: map[string][]byte{
+			bootstrapConfigKey: []byte("-- placeholder --"),
+		},
+	})
+
+	err := bootstrapOSMEgressGateway(fakeClient, fakeCertManager, testNs)
+	assert.Nil(err)
+
+	secret, err := fakeClient.CoreV1().Secrets(testNs).Get(context.Background(), egressGatewayBootstrapSecretName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.True(isValidBootstrapData(secret.Data[bootstrapConfigKey]))
+	assert.Contains(string(secret.Data[bootstrapConfigKey]), ".egress-gateway.")
+}
+
+func TestBootstrapOSMIngressGateway(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	fakeCertManager := tresor.NewFakeCertManager(mockConfigurator)
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(15 * time.Second).AnyTimes()
+	mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+
+	testNs := "test"
+	fakeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ingressGatewayBootstrapSecretName,
+			Namespace: testNs,
+		},
+		Data: map[string][]byte{
+			bootstrapConfigKey: []byte("-- placeholder --"),
+		},
+	})
+
+	err := bootstrapOSMIngressGateway(fakeClient, fakeCertManager, testNs)
+	assert.Nil(err)
+
+	secret, err := fakeClient.CoreV1().Secrets(testNs).Get(context.Background(), ingressGatewayBootstrapSecretName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.True(isValidBootstrapData(secret.Data[bootstrapConfigKey]))
+	assert.Contains(string(secret.Data[bootstrapConfigKey]), ".ingress-gateway.")
+}
+
 func TestIsValidBootstrapData(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -23,10 +23,12 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/tools/clientcmd"
+	gatewayClientset "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
 
 	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
 	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
 	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/multicluster"
 	"github.com/openservicemesh/osm/pkg/reconciler"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
@@ -35,19 +37,23 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/debugger"
+	"github.com/openservicemesh/osm/pkg/egressdns"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy/ads"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/gatewayapi"
 	"github.com/openservicemesh/osm/pkg/health"
 	"github.com/openservicemesh/osm/pkg/httpserver"
 	httpserverconstants "github.com/openservicemesh/osm/pkg/httpserver/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/ingress"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/policystatus"
 	"github.com/openservicemesh/osm/pkg/providers/kube"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/signals"
@@ -192,12 +198,34 @@ func main() {
 		}
 	}
 
+	if cfg.GetFeatureFlags().EnableEgressGateway {
+		log.Info().Msgf("Bootstrapping OSM egress gateway")
+		if err := bootstrapOSMEgressGateway(kubeClient, certManager, osmNamespace); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError,
+				"Error bootstraping OSM egress gateway")
+		}
+	}
+
+	if cfg.GetFeatureFlags().EnableIngressGateway {
+		log.Info().Msgf("Bootstrapping OSM ingress gateway")
+		if err := bootstrapOSMIngressGateway(kubeClient, certManager, osmNamespace); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError,
+				"Error bootstraping OSM ingress gateway")
+		}
+	}
+
 	var configClient config.Controller
 
 	if cfg.GetFeatureFlags().EnableMulticlusterMode {
 		if configClient, err = config.NewConfigController(kubeConfig, k8sClient, stop, msgBroker); err != nil {
 			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes config client")
 		}
+
+		// Import the services exported by the peer clusters registered with the mesh
+		discovery := multicluster.NewDiscovery(kubeClient, configClientset.NewForConfigOrDie(kubeConfig), osmNamespace)
+		k8s.RunWithLeaderElection(kubeClient, osmNamespace, multicluster.DiscoveryLeaseName, stop, func(stop <-chan struct{}) {
+			discovery.Start(multicluster.DefaultDiscoveryInterval, stop)
+		})
 	}
 
 	// A nil configClient is passed in if multi cluster mode is not enabled.
@@ -206,7 +234,8 @@ func main() {
 	endpointsProviders := []endpoint.Provider{kubeProvider}
 	serviceProviders := []service.Provider{kubeProvider}
 
-	if err := ingress.Initialize(kubeClient, k8sClient, stop, cfg, certManager, msgBroker); err != nil {
+	ingressMonitor, err := ingress.Initialize(kubeClient, k8sClient, stop, cfg, certManager, msgBroker)
+	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Ingress client")
 	}
 
@@ -215,11 +244,28 @@ func main() {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating controller for policy.openservicemesh.io")
 	}
 
+	// A nil gatewayAPIController is passed in if the ingress gateway is not enabled.
+	var gatewayAPIController gatewayapi.Controller
+	if cfg.GetFeatureFlags().EnableIngressGateway {
+		if gatewayAPIController, err = gatewayapi.NewGatewayAPIController(k8sClient, gatewayClientset.NewForConfigOrDie(kubeConfig), stop, msgBroker); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating controller for gateway.networking.k8s.io")
+		}
+	}
+
+	// Resolve the hosts of the TCP ports of Egress policies in the background, while the egress policy feature is enabled
+	egressDNSResolver := egressdns.NewResolver(policyController, cfg, msgBroker)
+	go egressDNSResolver.Run(stop)
+
 	meshCatalog := catalog.NewMeshCatalog(
 		k8sClient,
 		meshSpec,
 		certManager,
 		policyController,
+		configClient,
+		gatewayAPIController,
+		ingressMonitor,
+		egressDNSResolver,
+		identity.K8sServiceAccount{Name: osmServiceAccount, Namespace: osmNamespace}.ToServiceIdentity(),
 		stop,
 		cfg,
 		serviceProviders,
@@ -227,10 +273,17 @@ func main() {
 		msgBroker,
 	)
 
-	proxyMapper := &registry.KubeProxyServiceMapper{KubeController: k8sClient}
+	// Watch only the secrets referenced by the resources of the mesh
+	go meshCatalog.WatchReferencedSecrets(msgBroker, stop)
+
+	proxyMapper := &registry.KubeProxyServiceMapper{KubeController: k8sClient, Configurator: cfg}
 	proxyRegistry := registry.NewProxyRegistry(proxyMapper, msgBroker)
 	go proxyRegistry.ReleaseCertificateHandler(certManager, stop)
 
+	// Reconcile the status of the policies on a single replica at a time
+	policyStatusReconciler := policystatus.NewReconciler(kubeClient, k8sClient, policyController, proxyRegistry, cfg, msgBroker)
+	k8s.RunWithLeaderElection(kubeClient, osmNamespace, policystatus.LeaseName, stop, policyStatusReconciler.Run)
+
 	adsCert, err := certManager.IssueCertificate(xdsServerCertificateCommonName, constants.XDSCertificateValidityPeriod)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.CertificateIssuanceFailure, "Error issuing XDS certificate to ADS server")
@@ -26,6 +26,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/reconciler"
 
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
@@ -132,6 +133,9 @@ func main() {
 		log.Fatal().Msg("Error initializing generic event recorder")
 	}
 
+	// The IP family of the pods injected with the sidecar is that of the injector's pod
+	injectorConfig.PodIP = injectorPod.Status.PodIP
+
 	// This ensures CLI parameters (and dependent values) are correct.
 	if err := validateCLIParams(); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InvalidCLIParameters, "Error validating CLI parameters")
@@ -145,6 +149,8 @@ func main() {
 	metricsstore.DefaultMetricsStore.Start(
 		metricsstore.DefaultMetricsStore.InjectorRqTime,
 		metricsstore.DefaultMetricsStore.InjectorSidecarCount,
+		metricsstore.DefaultMetricsStore.InjectorBootstrapSecretCount,
+		metricsstore.DefaultMetricsStore.InjectorBootstrapSecretDeleteCount,
 		metricsstore.DefaultMetricsStore.CertIssuedCount,
 		metricsstore.DefaultMetricsStore.CertIssuedTime,
 		metricsstore.DefaultMetricsStore.ErrCodeCounter,
@@ -156,7 +162,7 @@ func main() {
 	cfg := configurator.NewConfigurator(configClientset.NewForConfigOrDie(kubeConfig), stop, osmNamespace, osmMeshConfigName, msgBroker)
 
 	// Initialize kubernetes.Controller to watch kubernetes resources
-	kubeController, err := k8s.NewKubernetesController(kubeClient, policyClient, meshName, stop, msgBroker, k8s.Namespaces)
+	kubeController, err := k8s.NewKubernetesController(kubeClient, policyClient, meshName, stop, msgBroker, k8s.Namespaces, k8s.Pods)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes Controller")
 	}
@@ -171,8 +177,14 @@ func main() {
 			"Error initializing certificate manager of kind %s", certProviderKind)
 	}
 
+	// Initialize config.Controller to watch the SidecarConfig resources
+	configClient, err := config.NewConfigController(kubeConfig, kubeController, stop, msgBroker)
+	if err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating config client")
+	}
+
 	// Initialize the sidecar injector webhook
-	if err := injector.NewMutatingWebhook(injectorConfig, kubeClient, certManager, kubeController, meshName, osmNamespace, webhookConfigName, osmVersion, webhookTimeout, enableReconciler, stop, cfg); err != nil {
+	if err := injector.NewMutatingWebhook(injectorConfig, kubeClient, certManager, kubeController, configClient, meshName, osmNamespace, webhookConfigName, osmVersion, webhookTimeout, enableReconciler, stop, cfg); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating sidecar injector webhook")
 	}
 
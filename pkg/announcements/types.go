@@ -68,6 +68,17 @@ const (
 
 	// ---
 
+	// SecretAdded is the type of announcement emitted when we observe an addition of a Kubernetes Secret
+	SecretAdded Kind = "secret-added"
+
+	// SecretDeleted the type of announcement emitted when we observe the deletion of a Kubernetes Secret
+	SecretDeleted Kind = "secret-deleted"
+
+	// SecretUpdated is the type of announcement emitted when we observe an update to a Kubernetes Secret
+	SecretUpdated Kind = "secret-updated"
+
+	// ---
+
 	// TrafficSplitAdded is the type of announcement emitted when we observe an addition of a Kubernetes TrafficSplit
 	TrafficSplitAdded Kind = "trafficsplit-added"
 
@@ -123,6 +134,17 @@ const (
 
 	// ---
 
+	// IngressClassAdded is the type of announcement emitted when we observe an addition of a Kubernetes IngressClass
+	IngressClassAdded Kind = "ingressclass-added"
+
+	// IngressClassDeleted the type of announcement emitted when we observe the deletion of a Kubernetes IngressClass
+	IngressClassDeleted Kind = "ingressclass-deleted"
+
+	// IngressClassUpdated is the type of announcement emitted when we observe an update to a Kubernetes IngressClass
+	IngressClassUpdated Kind = "ingressclass-updated"
+
+	// ---
+
 	// CertificateRotated is the type of announcement emitted when a certificate is rotated by the certificate provider
 	CertificateRotated Kind = "certificate-rotated"
 
@@ -167,6 +189,44 @@ const (
 
 	// MultiClusterServiceUpdated is the type of announcement emitted when we observe an update of a multiclusterservice.config.openservicemesh.io
 	MultiClusterServiceUpdated Kind = "multiclusterservice-updated"
+
+	// --- gateway.networking.k8s.io API events
+
+	// GatewayClassAdded is the type of announcement emitted when we observe an addition of gatewayclasses.gateway.networking.k8s.io
+	GatewayClassAdded Kind = "gatewayclass-added"
+
+	// GatewayClassDeleted the type of announcement emitted when we observe a deletion of gatewayclasses.gateway.networking.k8s.io
+	GatewayClassDeleted Kind = "gatewayclass-deleted"
+
+	// GatewayClassUpdated is the type of announcement emitted when we observe an update to gatewayclasses.gateway.networking.k8s.io
+	GatewayClassUpdated Kind = "gatewayclass-updated"
+
+	// GatewayAdded is the type of announcement emitted when we observe an addition of gateways.gateway.networking.k8s.io
+	GatewayAdded Kind = "gateway-added"
+
+	// GatewayDeleted the type of announcement emitted when we observe a deletion of gateways.gateway.networking.k8s.io
+	GatewayDeleted Kind = "gateway-deleted"
+
+	// GatewayUpdated is the type of announcement emitted when we observe an update to gateways.gateway.networking.k8s.io
+	GatewayUpdated Kind = "gateway-updated"
+
+	// GatewayHTTPRouteAdded is the type of announcement emitted when we observe an addition of httproutes.gateway.networking.k8s.io
+	GatewayHTTPRouteAdded Kind = "gateway-httproute-added"
+
+	// GatewayHTTPRouteDeleted the type of announcement emitted when we observe a deletion of httproutes.gateway.networking.k8s.io
+	GatewayHTTPRouteDeleted Kind = "gateway-httproute-deleted"
+
+	// GatewayHTTPRouteUpdated is the type of announcement emitted when we observe an update to httproutes.gateway.networking.k8s.io
+	GatewayHTTPRouteUpdated Kind = "gateway-httproute-updated"
+
+	// GatewayTLSRouteAdded is the type of announcement emitted when we observe an addition of tlsroutes.gateway.networking.k8s.io
+	GatewayTLSRouteAdded Kind = "gateway-tlsroute-added"
+
+	// GatewayTLSRouteDeleted the type of announcement emitted when we observe a deletion of tlsroutes.gateway.networking.k8s.io
+	GatewayTLSRouteDeleted Kind = "gateway-tlsroute-deleted"
+
+	// GatewayTLSRouteUpdated is the type of announcement emitted when we observe an update to tlsroutes.gateway.networking.k8s.io
+	GatewayTLSRouteUpdated Kind = "gateway-tlsroute-updated"
 )
 
 // Announcement is a struct for messages between various components of OSM signaling a need for a change in Envoy proxy configuration
@@ -65,6 +65,47 @@ type SidecarSpec struct {
 
 	// Resources defines the compute resources for the sidecar.
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Concurrency defines the number of worker threads of the sidecar. Defaults to the number of hardware threads of the node.
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// DrainDuration defines the duration for which the sidecar drains its connections when shutting down.
+	// The sidecar drains its listeners in a preStop hook, so the termination grace period of the pods must be longer
+	// than the drain duration.
+	DrainDuration string `json:"drainDuration,omitempty"`
+
+	// HoldApplicationUntilProxyStarts defines a boolean indicating whether the application containers are started only once the sidecar is ready.
+	HoldApplicationUntilProxyStarts bool `json:"holdApplicationUntilProxyStarts,omitempty"`
+
+	// StatsTags defines the tags, and their fixed values, added to all the stats of the sidecar.
+	StatsTags map[string]string `json:"statsTags,omitempty"`
+
+	// Rollout defines the automatic restart of the workloads whose pods run a sidecar injected with an outdated configuration.
+	Rollout SidecarRolloutSpec `json:"rollout,omitempty"`
+}
+
+// SidecarRolloutSpec is the type to represent the automatic restart of the workloads whose pods run an outdated sidecar.
+type SidecarRolloutSpec struct {
+	// Enable defines a boolean indicating if the Deployments, StatefulSets and DaemonSets of the pods running an
+	// outdated sidecar are restarted for the sidecar to be injected again. The pods running an outdated sidecar
+	// are annotated regardless.
+	Enable bool `json:"enable"`
+
+	// RestartInterval defines the minimum duration between two workload restarts. Defaults to 5m if unspecified.
+	RestartInterval string `json:"restartInterval,omitempty"`
+
+	// MaintenanceWindow defines the daily time window during which workloads are restarted.
+	// Workloads are restarted at any time if unspecified.
+	MaintenanceWindow *MaintenanceWindowSpec `json:"maintenanceWindow,omitempty"`
+}
+
+// MaintenanceWindowSpec is the type to represent a daily time window.
+type MaintenanceWindowSpec struct {
+	// Start defines the start of the window, in the 15:04 format in UTC.
+	Start string `json:"start"`
+
+	// End defines the end of the window, in the 15:04 format in UTC. The window spans midnight if End is before Start.
+	End string `json:"end"`
 }
 
 // TrafficSpec is the type used to represent OSM's traffic management configuration.
@@ -81,12 +122,49 @@ type TrafficSpec struct {
 	// InboundPortExclusionList defines a global list of ports to exclude from inbound traffic interception by the sidecar proxy.
 	InboundPortExclusionList []int `json:"inboundPortExclusionList"`
 
+	// OutboundUIDExclusionList defines a global list of user IDs whose outbound traffic is excluded from interception by the sidecar proxy.
+	// +optional
+	OutboundUIDExclusionList []int `json:"outboundUIDExclusionList,omitempty"`
+
+	// OutboundGIDExclusionList defines a global list of group IDs whose outbound traffic is excluded from interception by the sidecar proxy.
+	// +optional
+	OutboundGIDExclusionList []int `json:"outboundGIDExclusionList,omitempty"`
+
 	// EnablePermissiveTrafficPolicyMode defines a boolean indicating if permissive traffic policy mode is enabled mesh-wide.
 	EnablePermissiveTrafficPolicyMode bool `json:"enablePermissiveTrafficPolicyMode"`
 
 	// InboundExternalAuthorization defines a ruleset that, if enabled, will configure a remote external authorization endpoint
 	// for all inbound and ingress traffic in the mesh.
 	InboundExternalAuthorization ExternalAuthzSpec `json:"inboundExternalAuthorization,omitempty"`
+
+	// EgressDenyFeedback defines the feedback given for the egress traffic that is denied because mesh-wide
+	// Egress is disabled and no Egress policy allows the traffic.
+	EgressDenyFeedback EgressDenyFeedbackSpec `json:"egressDenyFeedback,omitempty"`
+
+	// EgressHostResolutionTTL defines the duration for which the IP addresses resolved for the hosts of the TCP ports
+	// of Egress policies are cached before the hosts are resolved again. Defaults to 30s if unspecified.
+	EgressHostResolutionTTL string `json:"egressHostResolutionTTL,omitempty"`
+
+	// ProtocolDetectionTimeout defines the duration for which the outbound listener waits for the first bytes of a
+	// connection to detect its protocol, after which the connection is proxied as TCP. The connections to the ports
+	// of server-first protocols that do not declare their protocol are delayed by this duration. Defaults to 1s if unspecified.
+	ProtocolDetectionTimeout string `json:"protocolDetectionTimeout,omitempty"`
+}
+
+// EgressDenyFeedbackSpec is the type to represent the feedback given for denied egress traffic.
+type EgressDenyFeedbackSpec struct {
+	// Enable defines a boolean indicating if denied egress HTTP requests are responded to with a local reply,
+	// and denied egress TCP connections are logged and counted, instead of being dropped silently.
+	Enable bool `json:"enable"`
+
+	// HTTPStatusCode defines the status code of the local reply to denied egress HTTP requests.
+	// Defaults to 403 if unspecified.
+	HTTPStatusCode int `json:"httpStatusCode,omitempty"`
+
+	// HTTPBody defines the body of the local reply to denied egress HTTP requests. It may contain Envoy
+	// command operators, such as %REQ(:AUTHORITY)%. Defaults to a message naming the Egress policy that
+	// is missing to allow the request if unspecified.
+	HTTPBody string `json:"httpBody,omitempty"`
 }
 
 // ObservabilitySpec is the type to represent OSM's observability configurations.
@@ -200,4 +278,31 @@ type FeatureFlags struct {
 
 	// EnableRetryPolicy defines if retry policy is enabled.
 	EnableRetryPolicy bool `json:"enableRetryPolicy"`
+
+	// EnableMulticlusterHTTPGateway defines if the multicluster gateway terminates mTLS for HTTP services
+	// to enforce their HTTP routes and RBAC policies, instead of passing through the TLS connection.
+	EnableMulticlusterHTTPGateway bool `json:"enableMulticlusterHTTPGateway"`
+
+	// EnableIPv6 defines if IPv6 traffic is intercepted and proxied by the sidecar, for dual-stack and IPv6-only clusters.
+	EnableIPv6 bool `json:"enableIPv6"`
+
+	// EnableEgressGateway defines if the Egress traffic allowed by Egress policies is routed through the egress
+	// gateway, which enforces the Egress policies centrally and originates the traffic from a stable source.
+	// Egress policies for TCP ports, with wildcard hosts or without hosts cannot be enforced by the gateway and are ignored.
+	EnableEgressGateway bool `json:"enableEgressGateway"`
+
+	// EnableIngressGateway defines if OSM deploys and programs its own ingress gateway using the Kubernetes Gateway API
+	// resources whose GatewayClass is managed by OSM. The Gateway API CRDs must be installed in the cluster.
+	EnableIngressGateway bool `json:"enableIngressGateway"`
+
+	// EnableCNI defines if the traffic interception rules of the pods are programmed by the OSM CNI plugin when
+	// their network sandbox is created, instead of by the privileged init container injected into the pods.
+	// The pods injected with the sidecar are only scheduled on the nodes labeled by the OSM CNI plugin once it is
+	// installed, so that their traffic is always intercepted.
+	EnableCNI bool `json:"enableCNI"`
+
+	// EnableProtocolSniffing defines if the protocol of the service ports that declare it neither with their
+	// appProtocol nor with their name is detected by inspecting the outbound traffic, instead of defaulting to HTTP.
+	// The inbound traffic to such ports is proxied as HTTP when HTTPRouteGroup rules restrict it, and as TCP otherwise.
+	EnableProtocolSniffing bool `json:"enableProtocolSniffing"`
 }
@@ -37,6 +37,66 @@ type MultiClusterServiceSpec struct {
 
 	// Ports is the list of ports exported by this service.
 	Ports []PortSpec `json:"ports,omitempty"`
+
+	// Failover defines how traffic fails over from the local cluster to the remote clusters.
+	// Defaults to the FailoverOnly mode when unspecified.
+	// +optional
+	Failover *FailoverSpec `json:"failover,omitempty"`
+}
+
+// FailoverMode is the type used to represent how traffic is distributed between the local and remote clusters.
+type FailoverMode string
+
+const (
+	// FailoverOnlyMode routes traffic to remote clusters only when the local endpoints are unhealthy.
+	// Remote clusters are tried in the order of their priority.
+	FailoverOnlyMode FailoverMode = "FailoverOnly"
+
+	// ActiveActiveMode distributes traffic between the local and remote clusters based on their weights.
+	// The local cluster has a weight of 100, and the remote clusters' weights are relative to it.
+	ActiveActiveMode FailoverMode = "ActiveActive"
+
+	// PreferredClustersMode routes traffic to remote clusters only when the local endpoints are unhealthy.
+	// Remote clusters are tried in the order they are listed in FailoverSpec.PreferredClusters,
+	// followed by the remaining remote clusters.
+	PreferredClustersMode FailoverMode = "PreferredClusters"
+)
+
+// FailoverSpec is the type used to represent the failover policy of a multicluster service.
+type FailoverSpec struct {
+	// Mode defines how traffic is distributed between the local and remote clusters.
+	// +optional
+	Mode FailoverMode `json:"mode,omitempty"`
+
+	// PreferredClusters is the ordered list of remote cluster names to fail over to.
+	// Only applicable to the PreferredClusters mode.
+	// +optional
+	PreferredClusters []string `json:"preferredClusters,omitempty"`
+
+	// OutlierDetection defines the passive health checking used to eject unhealthy
+	// local endpoints and remote gateways from the load balancing pool.
+	// +optional
+	OutlierDetection *OutlierDetectionSpec `json:"outlierDetection,omitempty"`
+}
+
+// OutlierDetectionSpec is the type used to represent the outlier detection configuration of a multicluster service.
+// Unset fields fall back to their defaults.
+type OutlierDetectionSpec struct {
+	// ConsecutiveErrors is the number of consecutive gateway or 5xx errors after which an endpoint is ejected.
+	// +optional
+	ConsecutiveErrors uint32 `json:"consecutiveErrors,omitempty"`
+
+	// Interval is the time interval between ejection analysis sweeps.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// BaseEjectionTime is the base duration an endpoint is ejected for.
+	// +optional
+	BaseEjectionTime *metav1.Duration `json:"baseEjectionTime,omitempty"`
+
+	// MaxEjectionPercent is the maximum percentage of endpoints that can be ejected.
+	// +optional
+	MaxEjectionPercent uint32 `json:"maxEjectionPercent,omitempty"`
 }
 
 // ClusterSpec is the type used to represent a remote cluster in multicluster scenarios.
@@ -48,7 +108,7 @@ type ClusterSpec struct {
 	// Name defines the name of the remote cluster.
 	Name string `json:"name,omitempty"`
 
-	// Weight defines the load balancing weight of the remote cluster
+	// Weight defines the load balancing weight of the remote cluster, at least 1
 	Weight int `json:"weight,omitempty"`
 
 	// Priority defines the priority of the remote cluster in locality based load balancing
@@ -41,6 +41,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&MeshConfigList{},
 		&MultiClusterService{},
 		&MultiClusterServiceList{},
+		&SidecarConfig{},
+		&SidecarConfigList{},
 	)
 
 	metav1.AddToGroupVersion(
@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SidecarConfig is the type used to represent the configuration of the sidecars of a group of pods in a namespace.
+// The settings of a SidecarConfig override the settings of the SidecarSpec of the MeshConfig for the pods it selects.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SidecarConfig struct {
+	// Object's type metadata.
+	metav1.TypeMeta `json:",inline" yaml:",inline"`
+
+	// Object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Spec is the SidecarConfig specification.
+	Spec SidecarConfigSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+// SidecarConfigSpec is the type used to represent the SidecarConfig specification.
+// Unset fields fall back to the SidecarConfig of lower precedence, and ultimately to the MeshConfig.
+type SidecarConfigSpec struct {
+	// Selector selects the pods in the namespace of the SidecarConfig the configuration applies to.
+	// The configuration applies to all the pods in the namespace when unspecified, and is overridden by
+	// the SidecarConfigs with a selector.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// LogLevel defines the logging level for the sidecar's logs.
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Resources defines the compute resources for the sidecar.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Concurrency defines the number of worker threads of the sidecar.
+	// +optional
+	Concurrency *int `json:"concurrency,omitempty"`
+
+	// DrainDuration defines the duration for which the sidecar drains its connections when shutting down.
+	// The sidecar drains its listeners in a preStop hook, so the termination grace period of the pods must be longer
+	// than the drain duration.
+	// +optional
+	DrainDuration *metav1.Duration `json:"drainDuration,omitempty"`
+
+	// HoldApplicationUntilProxyStarts defines a boolean indicating whether the application containers are started
+	// only once the sidecar is ready.
+	// +optional
+	HoldApplicationUntilProxyStarts *bool `json:"holdApplicationUntilProxyStarts,omitempty"`
+
+	// StatsTags defines the tags, and their fixed values, added to all the stats of the sidecar.
+	// +optional
+	StatsTags map[string]string `json:"statsTags,omitempty"`
+
+	// OutboundTrafficCapture defines the outbound traffic that is not intercepted by the sidecar,
+	// in addition to the exclusions of the MeshConfig.
+	// +optional
+	OutboundTrafficCapture *OutboundTrafficCaptureSpec `json:"outboundTrafficCapture,omitempty"`
+}
+
+// OutboundTrafficCaptureSpec is the type used to represent the outbound traffic interception settings of a SidecarConfig.
+type OutboundTrafficCaptureSpec struct {
+	// IPRangeExclusionList defines the IP address ranges to exclude from outbound traffic interception by the sidecar.
+	// +optional
+	IPRangeExclusionList []string `json:"ipRangeExclusionList,omitempty"`
+
+	// PortExclusionList defines the ports to exclude from outbound traffic interception by the sidecar.
+	// +optional
+	PortExclusionList []int `json:"portExclusionList,omitempty"`
+}
+
+// SidecarConfigList defines the list of SidecarConfig objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SidecarConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []SidecarConfig `json:"items"`
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -58,6 +60,22 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressDenyFeedbackSpec) DeepCopyInto(out *EgressDenyFeedbackSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressDenyFeedbackSpec.
+func (in *EgressDenyFeedbackSpec) DeepCopy() *EgressDenyFeedbackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressDenyFeedbackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalAuthzSpec) DeepCopyInto(out *ExternalAuthzSpec) {
 	*out = *in
@@ -74,6 +92,32 @@ func (in *ExternalAuthzSpec) DeepCopy() *ExternalAuthzSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverSpec) DeepCopyInto(out *FailoverSpec) {
+	*out = *in
+	if in.PreferredClusters != nil {
+		in, out := &in.PreferredClusters, &out.PreferredClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(OutlierDetectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverSpec.
+func (in *FailoverSpec) DeepCopy() *FailoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FeatureFlags) DeepCopyInto(out *FeatureFlags) {
 	*out = *in
@@ -112,6 +156,22 @@ func (in *IngressGatewayCertSpec) DeepCopy() *IngressGatewayCertSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MeshConfig) DeepCopyInto(out *MeshConfig) {
 	*out = *in
@@ -266,6 +326,11 @@ func (in *MultiClusterServiceSpec) DeepCopyInto(out *MultiClusterServiceSpec) {
 		*out = make([]PortSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -296,6 +361,58 @@ func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutboundTrafficCaptureSpec) DeepCopyInto(out *OutboundTrafficCaptureSpec) {
+	*out = *in
+	if in.IPRangeExclusionList != nil {
+		in, out := &in.IPRangeExclusionList, &out.IPRangeExclusionList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PortExclusionList != nil {
+		in, out := &in.PortExclusionList, &out.PortExclusionList
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutboundTrafficCaptureSpec.
+func (in *OutboundTrafficCaptureSpec) DeepCopy() *OutboundTrafficCaptureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutboundTrafficCaptureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlierDetectionSpec) DeepCopyInto(out *OutlierDetectionSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.BaseEjectionTime != nil {
+		in, out := &in.BaseEjectionTime, &out.BaseEjectionTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetectionSpec.
+func (in *OutlierDetectionSpec) DeepCopy() *OutlierDetectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlierDetectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PortSpec) DeepCopyInto(out *PortSpec) {
 	*out = *in
@@ -312,10 +429,152 @@ func (in *PortSpec) DeepCopy() *PortSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfig) DeepCopyInto(out *SidecarConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfig.
+func (in *SidecarConfig) DeepCopy() *SidecarConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SidecarConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfigList) DeepCopyInto(out *SidecarConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SidecarConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfigList.
+func (in *SidecarConfigList) DeepCopy() *SidecarConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SidecarConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfigSpec) DeepCopyInto(out *SidecarConfigSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int)
+		**out = **in
+	}
+	if in.DrainDuration != nil {
+		in, out := &in.DrainDuration, &out.DrainDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HoldApplicationUntilProxyStarts != nil {
+		in, out := &in.HoldApplicationUntilProxyStarts, &out.HoldApplicationUntilProxyStarts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StatsTags != nil {
+		in, out := &in.StatsTags, &out.StatsTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OutboundTrafficCapture != nil {
+		in, out := &in.OutboundTrafficCapture, &out.OutboundTrafficCapture
+		*out = new(OutboundTrafficCaptureSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfigSpec.
+func (in *SidecarConfigSpec) DeepCopy() *SidecarConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarRolloutSpec) DeepCopyInto(out *SidecarRolloutSpec) {
+	*out = *in
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarRolloutSpec.
+func (in *SidecarRolloutSpec) DeepCopy() *SidecarRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSpec) DeepCopyInto(out *SidecarSpec) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.StatsTags != nil {
+		in, out := &in.StatsTags, &out.StatsTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Rollout.DeepCopyInto(&out.Rollout)
 	return
 }
 
@@ -363,7 +622,18 @@ func (in *TrafficSpec) DeepCopyInto(out *TrafficSpec) {
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.OutboundUIDExclusionList != nil {
+		in, out := &in.OutboundUIDExclusionList, &out.OutboundUIDExclusionList
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.OutboundGIDExclusionList != nil {
+		in, out := &in.OutboundGIDExclusionList, &out.OutboundGIDExclusionList
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
 	out.InboundExternalAuthorization = in.InboundExternalAuthorization
+	out.EgressDenyFeedback = in.EgressDenyFeedback
 	return
 }
 
@@ -10,7 +10,6 @@ import (
 // external to the service mesh or cluster based on the specified
 // rules in the policy.
 // +genclient
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type Egress struct {
 	// Object's type metadata
@@ -23,6 +22,10 @@ type Egress struct {
 	// Spec is the Egress policy specification
 	// +optional
 	Spec EgressSpec `json:"spec,omitempty"`
+
+	// Status is the status of the Egress configuration.
+	// +optional
+	Status EgressStatus `json:"status,omitempty"`
 }
 
 // EgressSpec is the type used to represent the Egress policy specification.
@@ -39,13 +42,20 @@ type EgressSpec struct {
 	// - For HTTPS traffic, the Server Name Indication (SNI) indicated by the client
 	// in the TLS handshake is matched against the list of Hosts specified.
 	//
-	// - For non-HTTP(s) based protocols, the Hosts field is ignored.
+	// - For TCP based protocols, the destination IP address is matched against the
+	// IP addresses the Hosts specified resolve to. The hosts are resolved by the
+	// control plane, and resolved again periodically to track changes in their IP
+	// addresses.
+	//
+	// A host can be a wildcard host such as '*.example.com' that matches any subdomain
+	// of the domain following the wildcard. A wildcard is only allowed as the leftmost
+	// label, and must be followed by at least 2 labels.
 	// +optional
 	Hosts []string `json:"hosts,omitempty"`
 
 	// IPAddresses defines the list of external IP address ranges the Egress policy
 	// applies to. The destination IP address of the traffic is matched against the
-	// list of IPAddresses specified as a CIDR range.
+	// list of IPAddresses specified as an IPv4 or IPv6 CIDR range or IP address.
 	// +optional
 	IPAddresses []string `json:"ipAddresses,omitempty"`
 
@@ -59,15 +69,28 @@ type EgressSpec struct {
 }
 
 // EgressSourceSpec is the type used to represent the Source in the list of Sources specified in an Egress policy specification.
+// Since the egress configuration is programmed per service identity, a source selecting workloads by namespace or by pod
+// label selector applies to the service accounts of the selected workloads.
 type EgressSourceSpec struct {
-	// Kind defines the kind for the source in the Egress policy, ex. ServiceAccount.
+	// Kind defines the kind for the source in the Egress policy, ex. ServiceAccount, Namespace, Pod.
 	Kind string `json:"kind"`
 
 	// Name defines the name of the source for the given Kind.
-	Name string `json:"name"`
+	// For the Namespace kind, it is the name of the namespace whose workloads are selected.
+	// It is not applicable to the Pod kind.
+	// +optional
+	Name string `json:"name,omitempty"`
 
 	// Namespace defines the namespace for the given source.
-	Namespace string `json:"namespace"`
+	// It is not applicable to the Namespace kind.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// PodSelector defines the label selector of the pods in the source namespace selected by the Pod kind.
+	// The selected pods must not share their service account with pods that are not selected, otherwise
+	// the source is rejected.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
 }
 
 // PortSpec is the type used to represent the Port in the list of Ports specified in an Egress policy specification.
@@ -77,6 +100,47 @@ type PortSpec struct {
 
 	// Protocol defines the protocol served by the port.
 	Protocol string `json:"protocol"`
+
+	// TLS defines the TLS origination configuration for the port, which upgrades the plaintext
+	// HTTP traffic from the application to HTTPS before it is sent to the external host.
+	// It is only applicable to HTTP ports in Egress policies.
+	// +optional
+	TLS *EgressTLSSpec `json:"tls,omitempty"`
+}
+
+// EgressTLSSpec is the type used to represent the TLS origination configuration for a port in an Egress policy.
+// The secrets referenced must exist in the same namespace as the Egress policy.
+type EgressTLSSpec struct {
+	// SNI defines the Server Name Indication used in the TLS handshake with the external host.
+	// Defaults to the host of the request.
+	// +optional
+	SNI string `json:"sni,omitempty"`
+
+	// CASecretName defines the name of the secret containing the CA bundle, in the 'ca.crt' key,
+	// used to validate the certificate presented by the external host.
+	CASecretName string `json:"caSecretName"`
+
+	// ClientCertSecretName defines the name of the secret containing the client certificate and key,
+	// in the 'tls.crt' and 'tls.key' keys, presented to the external host for mutual TLS.
+	// +optional
+	ClientCertSecretName string `json:"clientCertSecretName,omitempty"`
+}
+
+// EgressStatus is the type used to represent the status of an Egress resource.
+type EgressStatus struct {
+	// CurrentStatus defines the current status of an Egress resource.
+	// +optional
+	CurrentStatus string `json:"currentStatus,omitempty"`
+
+	// Reason defines the reason for the current status of an Egress resource.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Conditions defines the latest observations of the Egress resource's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // EgressList defines the list of Egress objects.
@@ -107,4 +107,10 @@ type IngressBackendStatus struct {
 	// Reason defines the reason for the current status of an IngressBackend resource.
 	// +optional
 	Reason string `json:"reason,omitempty"`
+
+	// Conditions defines the latest observations of the IngressBackend resource's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
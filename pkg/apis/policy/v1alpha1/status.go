@@ -0,0 +1,72 @@
+package v1alpha1
+
+// Condition types used in the status of the resources in the policy.openservicemesh.io API group
+const (
+	// ConditionAccepted indicates whether the specification of the policy is valid
+	ConditionAccepted = "Accepted"
+
+	// ConditionResolvedRefs indicates whether all the resources referenced by the policy exist
+	ConditionResolvedRefs = "ResolvedRefs"
+
+	// ConditionConflicted indicates whether the policy conflicts with another policy
+	ConditionConflicted = "Conflicted"
+
+	// ConditionProgrammed indicates whether the configuration resulting from the policy has been
+	// acknowledged by the proxies it applies to
+	ConditionProgrammed = "Programmed"
+)
+
+// Condition reasons used in the status of the resources in the policy.openservicemesh.io API group
+const (
+	// ReasonValid is the reason used when the specification of the policy is valid
+	ReasonValid = "Valid"
+
+	// ReasonInvalid is the reason used when the specification of the policy is invalid
+	ReasonInvalid = "Invalid"
+
+	// ReasonResolved is the reason used when all the resources referenced by the policy exist
+	ReasonResolved = "Resolved"
+
+	// ReasonBackendNotFound is the reason used when a backend referenced by the policy does not exist
+	ReasonBackendNotFound = "BackendNotFound"
+
+	// ReasonInvalidPort is the reason used when a port referenced by the policy is not a port of the backend
+	ReasonInvalidPort = "InvalidPort"
+
+	// ReasonSourceNotFound is the reason used when a source referenced by the policy does not exist
+	ReasonSourceNotFound = "SourceNotFound"
+
+	// ReasonSecretNotFound is the reason used when a secret referenced by the policy does not exist
+	ReasonSecretNotFound = "SecretNotFound"
+
+	// ReasonNoConflicts is the reason used when the policy does not conflict with another policy
+	ReasonNoConflicts = "NoConflicts"
+
+	// ReasonConflicts is the reason used when the policy conflicts with another policy
+	ReasonConflicts = "Conflicts"
+
+	// ReasonProgrammed is the reason used when the proxies the policy applies to have acknowledged
+	// the resulting configuration
+	ReasonProgrammed = "Programmed"
+
+	// ReasonPending is the reason used when the proxies the policy applies to have not yet acknowledged
+	// the resulting configuration
+	ReasonPending = "Pending"
+
+	// ReasonNoProxies is the reason used when no connected proxy is subject to the policy
+	ReasonNoProxies = "NoProxies"
+
+	// ReasonMultipleReplicas is the reason used when the proxies the policy applies to may be connected to
+	// different controller replicas, whose acknowledgements are not aggregated
+	ReasonMultipleReplicas = "MultipleReplicas"
+)
+
+// Values of the CurrentStatus field summarizing the conditions of the resources in the
+// policy.openservicemesh.io API group
+const (
+	// StatusCommitted is the current status of a policy that has been accepted without errors
+	StatusCommitted = "committed"
+
+	// StatusError is the current status of a policy that could not be applied
+	StatusError = "error"
+)
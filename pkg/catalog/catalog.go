@@ -4,8 +4,13 @@ import (
 	"time"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/egressdns"
 	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/gatewayapi"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/ingress"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/policy"
@@ -16,7 +21,9 @@ import (
 
 // NewMeshCatalog creates a new service catalog
 func NewMeshCatalog(kubeController k8s.Controller, meshSpec smi.MeshSpec, certManager certificate.Manager,
-	policyController policy.Controller, stop <-chan struct{},
+	policyController policy.Controller, multiclusterController config.Controller, gatewayAPIController gatewayapi.Controller,
+	ingressMonitor ingress.Monitor, egressDNSResolver egressdns.Resolver,
+	egressGatewayIdentity identity.ServiceIdentity, stop <-chan struct{},
 	cfg configurator.Configurator, serviceProviders []service.Provider, endpointsProviders []endpoint.Provider,
 	msgBroker *messaging.Broker) *MeshCatalog {
 	mc := &MeshCatalog{
@@ -27,7 +34,14 @@ func NewMeshCatalog(kubeController k8s.Controller, meshSpec smi.MeshSpec, certMa
 		policyController:   policyController,
 		configurator:       cfg,
 
+		multiclusterController: multiclusterController,
+		gatewayAPIController:   gatewayAPIController,
+		ingressMonitor:         ingressMonitor,
+		egressGatewayIdentity:  egressGatewayIdentity,
+		egressDNSResolver:      egressDNSResolver,
+
 		kubeController: kubeController,
+		msgBroker:      msgBroker,
 	}
 
 	// Start the Resync ticker to tick based on the resync interval.
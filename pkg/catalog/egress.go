@@ -7,14 +7,17 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	"k8s.io/apimachinery/pkg/types"
 
 	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/egressdns"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
 )
 
 // GetEgressTrafficPolicy returns the Egress traffic policy associated with the given service identity
@@ -26,15 +29,20 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 	var trafficMatches []*trafficpolicy.TrafficMatch
 	var clusterConfigs []*trafficpolicy.EgressClusterConfig
 	portToRouteConfigMap := make(map[int][]*trafficpolicy.EgressHTTPRouteConfig)
+	viaEgressGateway := mc.isEgressGatewayEnabled()
 	egressResources := mc.policyController.ListEgressPoliciesForSourceIdentity(serviceIdentity.ToK8sServiceAccount())
 
 	for _, egress := range egressResources {
+		if viaEgressGateway && !isEnforcedByEgressGateway(egress) {
+			continue
+		}
+
 		for _, portSpec := range egress.Spec.Ports {
 			switch strings.ToLower(portSpec.Protocol) {
 			case constants.ProtocolHTTP:
 				// ---
 				// Build the HTTP route configs for the given Egress policy
-				httpRouteConfigs, httpClusterConfigs := mc.buildHTTPRouteConfigs(egress, portSpec.Number)
+				httpRouteConfigs, httpClusterConfigs := mc.buildHTTPRouteConfigs(egress, portSpec, viaEgressGateway)
 				portToRouteConfigMap[portSpec.Number] = append(portToRouteConfigMap[portSpec.Number], httpRouteConfigs...)
 				clusterConfigs = append(clusterConfigs, httpClusterConfigs...)
 
@@ -45,6 +53,15 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 				})
 
 			case constants.ProtocolTCP, constants.ProtocolTCPServerFirst:
+				// TCP traffic carries no host information, so hosts are matched on the IP addresses they resolve to
+				destinationIPRanges := mc.getEgressTCPDestinationIPRanges(egress)
+				if len(egress.Spec.Hosts) > 0 && len(destinationIPRanges) == 0 {
+					// A TrafficMatch without IP ranges would match any destination on the port
+					log.Warn().Msgf("None of the hosts in Egress policy %s/%s resolved to an IP address, skipping TCP port %d",
+						egress.Namespace, egress.Name, portSpec.Number)
+					continue
+				}
+
 				// ---
 				// Build the TCP cluster config for this port
 				clusterConfigs = append(clusterConfigs, &trafficpolicy.EgressClusterConfig{
@@ -56,11 +73,21 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 				trafficMatches = append(trafficMatches, &trafficpolicy.TrafficMatch{
 					DestinationPort:     portSpec.Number,
 					DestinationProtocol: portSpec.Protocol,
-					DestinationIPRanges: egress.Spec.IPAddresses,
+					DestinationIPRanges: destinationIPRanges,
 					Cluster:             fmt.Sprintf("%d", portSpec.Number),
 				})
 
 			case constants.ProtocolHTTPS:
+				if viaEgressGateway {
+					// ---
+					// Build the HTTPS cluster configs and TrafficMatches per host, so that the egress gateway
+					// can be requested to proxy the TLS stream to the host based on the cluster.
+					httpsTrafficMatches, httpsClusterConfigs := buildEgressGatewayHTTPSConfigs(egress, portSpec)
+					trafficMatches = append(trafficMatches, httpsTrafficMatches...)
+					clusterConfigs = append(clusterConfigs, httpsClusterConfigs...)
+					continue
+				}
+
 				// ---
 				// Build the HTTPS cluster config for this port
 				// HTTPS is TLS encrypted, so will be proxied as a TCP stream
@@ -73,7 +100,7 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 				trafficMatches = append(trafficMatches, &trafficpolicy.TrafficMatch{
 					DestinationPort:     portSpec.Number,
 					DestinationProtocol: portSpec.Protocol,
-					DestinationIPRanges: egress.Spec.IPAddresses,
+					DestinationIPRanges: getEgressDestinationIPRanges(egress),
 					ServerNames:         egress.Spec.Hosts,
 					Cluster:             fmt.Sprintf("%d", portSpec.Number),
 				})
@@ -105,29 +132,19 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 	}, nil
 }
 
-func (mc *MeshCatalog) buildHTTPRouteConfigs(egressPolicy *policyV1alpha1.Egress, port int) ([]*trafficpolicy.EgressHTTPRouteConfig, []*trafficpolicy.EgressClusterConfig) {
+func (mc *MeshCatalog) buildHTTPRouteConfigs(egressPolicy *policyV1alpha1.Egress, portSpec policyV1alpha1.PortSpec, viaEgressGateway bool) ([]*trafficpolicy.EgressHTTPRouteConfig, []*trafficpolicy.EgressClusterConfig) {
 	if egressPolicy == nil {
 		return nil, nil
 	}
 
+	port := portSpec.Number
+
 	var routeConfigs []*trafficpolicy.EgressHTTPRouteConfig
 	var clusterConfigs []*trafficpolicy.EgressClusterConfig
 
 	// Before building the route configs, pre-compute the allowed IP ranges since they
 	// will be the same for every HTTP route config derived from the given Egress policy.
-	var allowedDestinationIPRanges []string
-	destIPSet := mapset.NewSet()
-	for _, ipRange := range egressPolicy.Spec.IPAddresses {
-		if _, _, err := net.ParseCIDR(ipRange); err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrInvalidEgressIPRange)).
-				Msgf("Invalid IP range [%s] specified in egress policy %s/%s; will be skipped", ipRange, egressPolicy.Namespace, egressPolicy.Name)
-			continue
-		}
-		newlyAdded := destIPSet.Add(ipRange)
-		if newlyAdded {
-			allowedDestinationIPRanges = append(allowedDestinationIPRanges, ipRange)
-		}
-	}
+	allowedDestinationIPRanges := getEgressDestinationIPRanges(egressPolicy)
 
 	// Check if there are object references to HTTP routes specified
 	// in the Egress policy's 'matches' attribute. If there are HTTP route
@@ -167,12 +184,20 @@ func (mc *MeshCatalog) buildHTTPRouteConfigs(egressPolicy *policyV1alpha1.Egress
 		hostnameWithPort := fmt.Sprintf("%s:%d", host, port)
 		hostnames := []string{host, hostnameWithPort}
 
-		// Create cluster config for this host and port combination
+		// Create cluster config for this host and port combination.
+		// Wildcard hosts are routed to a dynamic forward proxy cluster by the sidecar, because TLS origination
+		// cannot be configured without knowing the host being requested. Wildcard hosts are rejected while the
+		// egress gateway is enabled.
 		clusterName := hostnameWithPort
 		clusterConfig := &trafficpolicy.EgressClusterConfig{
-			Name: clusterName,
-			Host: host,
-			Port: port,
+			Name:             clusterName,
+			Host:             host,
+			Port:             port,
+			ViaEgressGateway: viaEgressGateway,
+		}
+		if !clusterConfig.ViaEgressGateway && !utils.IsWildcardHost(host) {
+			// When the traffic is routed through the egress gateway, TLS is originated by the gateway
+			clusterConfig.TLS = getEgressTLSConfig(egressPolicy, portSpec, host)
 		}
 		clusterConfigs = append(clusterConfigs, clusterConfig)
 
@@ -205,6 +230,63 @@ func (mc *MeshCatalog) buildHTTPRouteConfigs(egressPolicy *policyV1alpha1.Egress
 	return routeConfigs, clusterConfigs
 }
 
+// buildEgressGatewayHTTPSConfigs returns the TrafficMatches and cluster configs for the hosts in the given Egress policy
+// for the given HTTPS port, with each host's traffic routed through the egress gateway.
+func buildEgressGatewayHTTPSConfigs(egressPolicy *policyV1alpha1.Egress, portSpec policyV1alpha1.PortSpec) ([]*trafficpolicy.TrafficMatch, []*trafficpolicy.EgressClusterConfig) {
+	var trafficMatches []*trafficpolicy.TrafficMatch
+	var clusterConfigs []*trafficpolicy.EgressClusterConfig
+
+	destinationIPRanges := getEgressDestinationIPRanges(egressPolicy)
+	for _, host := range egressPolicy.Spec.Hosts {
+		clusterName := fmt.Sprintf("%s:%d", host, portSpec.Number)
+		clusterConfigs = append(clusterConfigs, &trafficpolicy.EgressClusterConfig{
+			Name:             clusterName,
+			Host:             host,
+			Port:             portSpec.Number,
+			ViaEgressGateway: true,
+		})
+
+		trafficMatches = append(trafficMatches, &trafficpolicy.TrafficMatch{
+			Name:                clusterName,
+			DestinationPort:     portSpec.Number,
+			DestinationProtocol: portSpec.Protocol,
+			DestinationIPRanges: destinationIPRanges,
+			ServerNames:         []string{host},
+			Cluster:             clusterName,
+		})
+	}
+
+	return trafficMatches, clusterConfigs
+}
+
+// getEgressTLSConfig returns the TLS origination config for the given host on the given port of an Egress policy,
+// or nil if TLS origination is not configured for the port. The secrets referenced by the Egress policy must
+// exist in the policy's namespace.
+func getEgressTLSConfig(egressPolicy *policyV1alpha1.Egress, portSpec policyV1alpha1.PortSpec, host string) *trafficpolicy.EgressTLSConfig {
+	if portSpec.TLS == nil || strings.ToLower(portSpec.Protocol) != constants.ProtocolHTTP {
+		return nil
+	}
+
+	tlsConfig := &trafficpolicy.EgressTLSConfig{
+		SNI: portSpec.TLS.SNI,
+		CACertSecret: types.NamespacedName{
+			Namespace: egressPolicy.Namespace,
+			Name:      portSpec.TLS.CASecretName,
+		},
+	}
+	if tlsConfig.SNI == "" {
+		tlsConfig.SNI = host
+	}
+	if portSpec.TLS.ClientCertSecretName != "" {
+		tlsConfig.ClientCertSecret = &types.NamespacedName{
+			Namespace: egressPolicy.Namespace,
+			Name:      portSpec.TLS.ClientCertSecretName,
+		}
+	}
+
+	return tlsConfig
+}
+
 func getHTTPRouteMatchesFromHTTPRouteGroup(httpRouteGroup *smiSpecs.HTTPRouteGroup) []trafficpolicy.HTTPRouteMatch {
 	if httpRouteGroup == nil {
 		return nil
@@ -232,3 +314,56 @@ func getHTTPRouteMatchesFromHTTPRouteGroup(httpRouteGroup *smiSpecs.HTTPRouteGro
 
 	return matches
 }
+
+// getEgressDestinationIPRanges returns the unique destination IP ranges specified in the given Egress policy in CIDR
+// notation. IP addresses are converted to the CIDR range matching the address based on its IP family.
+func getEgressDestinationIPRanges(egressPolicy *policyV1alpha1.Egress) []string {
+	var destinationIPRanges []string
+	destIPSet := mapset.NewSet()
+	for _, ipAddress := range egressPolicy.Spec.IPAddresses {
+		ipRange, err := utils.ParseIPRange(ipAddress)
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrInvalidEgressIPRange)).
+				Msgf("Invalid IP range [%s] specified in egress policy %s/%s; will be skipped", ipAddress, egressPolicy.Namespace, egressPolicy.Name)
+			continue
+		}
+		if newlyAdded := destIPSet.Add(ipRange); newlyAdded {
+			destinationIPRanges = append(destinationIPRanges, ipRange)
+		}
+	}
+
+	return destinationIPRanges
+}
+
+// getEgressTCPDestinationIPRanges returns the destination IP ranges matched on the TCP ports of the given Egress policy,
+// which are the IP ranges specified in the policy and the IP addresses its hosts currently resolve to
+func (mc *MeshCatalog) getEgressTCPDestinationIPRanges(egressPolicy *policyV1alpha1.Egress) []string {
+	destinationIPRanges := getEgressDestinationIPRanges(egressPolicy)
+	destIPSet := mapset.NewSet()
+	for _, ipRange := range destinationIPRanges {
+		destIPSet.Add(ipRange)
+	}
+
+	var hostIPAddresses []string
+	for _, host := range egressPolicy.Spec.Hosts {
+		if net.ParseIP(host) != nil {
+			hostIPAddresses = append(hostIPAddresses, host)
+		}
+	}
+	for _, host := range egressdns.GetTCPHosts(egressPolicy) {
+		hostIPAddresses = append(hostIPAddresses, mc.egressDNSResolver.Resolve(host)...)
+	}
+
+	for _, ipAddress := range hostIPAddresses {
+		ipRange, err := utils.ParseIPRange(ipAddress)
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid IP address [%s] resolved for a host in egress policy %s/%s; will be skipped", ipAddress, egressPolicy.Namespace, egressPolicy.Name)
+			continue
+		}
+		if newlyAdded := destIPSet.Add(ipRange); newlyAdded {
+			destinationIPRanges = append(destinationIPRanges, ipRange)
+		}
+	}
+
+	return destinationIPRanges
+}
@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+
+	mapset "github.com/deckarep/golang-set"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// isEgressGatewayEnabled returns a boolean indicating if Egress traffic is routed through the egress gateway
+func (mc *MeshCatalog) isEgressGatewayEnabled() bool {
+	featureFlags := mc.configurator.GetFeatureFlags()
+	return featureFlags.EnableEgressPolicy && featureFlags.EnableEgressGateway
+}
+
+// isEnforcedByEgressGateway returns a boolean indicating if the given Egress policy can be enforced by the egress
+// gateway. The egress gateway determines the external destination based on the SNI requested by the sidecar for
+// a host, so Egress policies for TCP ports, wildcard hosts or IP ranges are rejected when the egress gateway is enabled, instead
+// of letting their traffic bypass the gateway.
+func isEnforcedByEgressGateway(egress *policyV1alpha1.Egress) bool {
+	if err := policy.ValidateEgressWithEgressGateway(egress); err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrInvalidEgressGatewayPolicy)).
+			Msgf("Ignoring Egress policy %s/%s", egress.Namespace, egress.Name)
+		return false
+	}
+	return true
+}
+
+// isEgressGatewayService returns a boolean indicating if the given service is the egress gateway's service
+func (mc *MeshCatalog) isEgressGatewayService(svc service.MeshService) bool {
+	return svc.Name == egressgateway.ServiceName && svc.Namespace == mc.configurator.GetOSMNamespace()
+}
+
+// GetEgressGatewayTrafficPolicy returns the traffic policy for the egress gateway, or nil if the egress gateway
+// is disabled. The gateway enforces the Egress policies on behalf of the sidecars, so the source identities
+// allowed to access an external destination are derived from the Egress policies for the destination.
+func (mc *MeshCatalog) GetEgressGatewayTrafficPolicy() *trafficpolicy.EgressGatewayTrafficPolicy {
+	if !mc.isEgressGatewayEnabled() {
+		return nil
+	}
+
+	upstreams := make(map[string]*trafficpolicy.EgressGatewayUpstream)
+	allowedSources := make(map[string]mapset.Set)
+
+	for _, egress := range mc.policyController.ListEgressPolicies() {
+		if !isEnforcedByEgressGateway(egress) {
+			continue
+		}
+
+		sourceIdentities := mc.policyController.ListEgressSourceIdentities(egress)
+		if len(sourceIdentities) == 0 {
+			continue
+		}
+
+		for _, portSpec := range egress.Spec.Ports {
+			for _, host := range egress.Spec.Hosts {
+				clusterName := fmt.Sprintf("%s:%d", host, portSpec.Number)
+				if _, ok := upstreams[clusterName]; !ok {
+					upstreams[clusterName] = &trafficpolicy.EgressGatewayUpstream{
+						Host:        host,
+						Port:        portSpec.Number,
+						ClusterName: clusterName,
+						ServerName:  egressgateway.GetServerName(host, portSpec.Number),
+						TLS:         getEgressTLSConfig(egress, portSpec, host),
+					}
+					allowedSources[clusterName] = mapset.NewSet()
+				}
+				for _, sourceIdentity := range sourceIdentities {
+					allowedSources[clusterName].Add(sourceIdentity.ToServiceIdentity())
+				}
+			}
+		}
+	}
+
+	gatewayPolicy := &trafficpolicy.EgressGatewayTrafficPolicy{}
+	for clusterName, upstream := range upstreams {
+		for sourceIdentity := range allowedSources[clusterName].Iter() {
+			upstream.AllowedSourceIdentities = append(upstream.AllowedSourceIdentities, sourceIdentity.(identity.ServiceIdentity))
+		}
+		sort.Slice(upstream.AllowedSourceIdentities, func(i, j int) bool {
+			return upstream.AllowedSourceIdentities[i] < upstream.AllowedSourceIdentities[j]
+		})
+		gatewayPolicy.Upstreams = append(gatewayPolicy.Upstreams, upstream)
+	}
+
+	// Sort the upstreams to generate the same configuration for the same set of policies
+	sort.Slice(gatewayPolicy.Upstreams, func(i, j int) bool {
+		return gatewayPolicy.Upstreams[i].ClusterName < gatewayPolicy.Upstreams[j].ClusterName
+	})
+
+	return gatewayPolicy
+}
@@ -0,0 +1,266 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func newTestEgressPolicy(name string, sources []string, hosts []string, ports ...policyV1alpha1.PortSpec) *policyV1alpha1.Egress {
+	egress := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+		Spec: policyV1alpha1.EgressSpec{
+			Hosts: hosts,
+			Ports: ports,
+		},
+	}
+	for _, source := range sources {
+		egress.Spec.Sources = append(egress.Spec.Sources, policyV1alpha1.EgressSourceSpec{
+			Kind:      "ServiceAccount",
+			Name:      source,
+			Namespace: "test",
+		})
+	}
+	return egress
+}
+
+func TestGetEgressGatewayTrafficPolicy(t *testing.T) {
+	sa1 := identity.K8sServiceAccount{Name: "sa-1", Namespace: "test"}.ToServiceIdentity()
+	sa2 := identity.K8sServiceAccount{Name: "sa-2", Namespace: "test"}.ToServiceIdentity()
+
+	testCases := []struct {
+		name           string
+		featureFlags   v1alpha1.FeatureFlags
+		egressPolicies []*policyV1alpha1.Egress
+		expected       *trafficpolicy.EgressGatewayTrafficPolicy
+	}{
+		{
+			name:         "egress gateway is disabled",
+			featureFlags: v1alpha1.FeatureFlags{EnableEgressPolicy: true},
+			expected:     nil,
+		},
+		{
+			name:         "egress policy is disabled",
+			featureFlags: v1alpha1.FeatureFlags{EnableEgressGateway: true},
+			expected:     nil,
+		},
+		{
+			name:         "HTTP and HTTPS hosts are proxied by the gateway, policies for TCP, wildcard hosts or without hosts are ignored",
+			featureFlags: v1alpha1.FeatureFlags{EnableEgressPolicy: true, EnableEgressGateway: true},
+			egressPolicies: []*policyV1alpha1.Egress{
+				newTestEgressPolicy("egress-1", []string{"sa-2"}, []string{"foo.com"},
+					policyV1alpha1.PortSpec{Number: 80, Protocol: "http"},
+					policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+				),
+				// Egress policies that cannot be enforced by the gateway are ignored
+				newTestEgressPolicy("egress-tcp", []string{"sa-1"}, []string{"bar.com"},
+					policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+					policyV1alpha1.PortSpec{Number: 3306, Protocol: "tcp"},
+				),
+				newTestEgressPolicy("egress-ip-range", []string{"sa-1"}, nil,
+					policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+				),
+				newTestEgressPolicy("egress-wildcard", []string{"sa-2"}, []string{"bar.com", "*.baz.com"},
+					policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+				),
+				newTestEgressPolicy("egress-2", []string{"sa-1"}, []string{"foo.com"},
+					policyV1alpha1.PortSpec{Number: 443, Protocol: "HTTPS"},
+				),
+				// Egress policy without any valid source is ignored
+				newTestEgressPolicy("egress-3", nil, []string{"bar.com"},
+					policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+				),
+			},
+			expected: &trafficpolicy.EgressGatewayTrafficPolicy{
+				Upstreams: []*trafficpolicy.EgressGatewayUpstream{
+					{
+						Host:                    "foo.com",
+						Port:                    443,
+						ClusterName:             "foo.com:443",
+						ServerName:              "foo.com.443.egress.osm",
+						AllowedSourceIdentities: []identity.ServiceIdentity{sa1, sa2},
+					},
+					{
+						Host:                    "foo.com",
+						Port:                    80,
+						ClusterName:             "foo.com:80",
+						ServerName:              "foo.com.80.egress.osm",
+						AllowedSourceIdentities: []identity.ServiceIdentity{sa2},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockCfg.EXPECT().GetFeatureFlags().Return(tc.featureFlags).AnyTimes()
+			mockPolicyController.EXPECT().ListEgressPolicies().Return(tc.egressPolicies).AnyTimes()
+			mockPolicyController.EXPECT().ListEgressSourceIdentities(gomock.Any()).DoAndReturn(
+				func(egress *policyV1alpha1.Egress) []identity.K8sServiceAccount {
+					var sources []identity.K8sServiceAccount
+					for _, source := range egress.Spec.Sources {
+						sources = append(sources, identity.K8sServiceAccount{Name: source.Name, Namespace: source.Namespace})
+					}
+					return sources
+				}).AnyTimes()
+
+			mc := &MeshCatalog{
+				configurator:     mockCfg,
+				policyController: mockPolicyController,
+			}
+
+			assert.Equal(tc.expected, mc.GetEgressGatewayTrafficPolicy())
+		})
+	}
+}
+
+func TestGetEgressTrafficPolicyWithEgressGateway(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockPolicyController := policy.NewMockController(mockCtrl)
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressPolicy: true, EnableEgressGateway: true}).AnyTimes()
+	mockPolicyController.EXPECT().ListEgressPoliciesForSourceIdentity(gomock.Any()).Return([]*policyV1alpha1.Egress{
+		newTestEgressPolicy("egress-1", []string{"sa-1"}, []string{"foo.com", "bar.com"},
+			policyV1alpha1.PortSpec{Number: 80, Protocol: "http"},
+			policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+		),
+	}).Times(1)
+
+	mc := &MeshCatalog{
+		configurator:     mockCfg,
+		policyController: mockPolicyController,
+	}
+
+	actual, err := mc.GetEgressTrafficPolicy(identity.K8sServiceAccount{Name: "sa-1", Namespace: "test"}.ToServiceIdentity())
+	assert.Nil(err)
+	assert.NotNil(actual)
+
+	// HTTPS traffic is matched per host to route it through the egress gateway
+	assert.ElementsMatch([]*trafficpolicy.TrafficMatch{
+		{
+			DestinationPort:     80,
+			DestinationProtocol: "http",
+		},
+		{
+			Name:                "foo.com:443",
+			DestinationPort:     443,
+			DestinationProtocol: "https",
+			ServerNames:         []string{"foo.com"},
+			Cluster:             "foo.com:443",
+		},
+		{
+			Name:                "bar.com:443",
+			DestinationPort:     443,
+			DestinationProtocol: "https",
+			ServerNames:         []string{"bar.com"},
+			Cluster:             "bar.com:443",
+		},
+	}, actual.TrafficMatches)
+
+	assert.ElementsMatch([]*trafficpolicy.EgressClusterConfig{
+		{Name: "foo.com:80", Host: "foo.com", Port: 80, ViaEgressGateway: true},
+		{Name: "bar.com:80", Host: "bar.com", Port: 80, ViaEgressGateway: true},
+		{Name: "foo.com:443", Host: "foo.com", Port: 443, ViaEgressGateway: true},
+		{Name: "bar.com:443", Host: "bar.com", Port: 443, ViaEgressGateway: true},
+	}, actual.ClustersConfigs)
+}
+
+func TestGetEgressTrafficPolicyWithEgressGatewayIgnoresTCP(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockPolicyController := policy.NewMockController(mockCtrl)
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressPolicy: true, EnableEgressGateway: true}).AnyTimes()
+	mockPolicyController.EXPECT().ListEgressPoliciesForSourceIdentity(gomock.Any()).Return([]*policyV1alpha1.Egress{
+		newTestEgressPolicy("egress-1", []string{"sa-1"}, []string{"foo.com"},
+			policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+			policyV1alpha1.PortSpec{Number: 5432, Protocol: "tcp"},
+		),
+	}).Times(1)
+
+	mc := &MeshCatalog{
+		configurator:     mockCfg,
+		policyController: mockPolicyController,
+	}
+
+	// The TCP traffic would bypass the egress gateway, so the whole policy is ignored
+	actual, err := mc.GetEgressTrafficPolicy(identity.K8sServiceAccount{Name: "sa-1", Namespace: "test"}.ToServiceIdentity())
+	assert.Nil(err)
+	assert.NotNil(actual)
+	assert.Empty(actual.TrafficMatches)
+	assert.Empty(actual.ClustersConfigs)
+}
+
+func TestGetEgressTrafficPolicyWithEgressGatewayIgnoresWildcardHosts(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockPolicyController := policy.NewMockController(mockCtrl)
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressPolicy: true, EnableEgressGateway: true}).AnyTimes()
+	mockPolicyController.EXPECT().ListEgressPoliciesForSourceIdentity(gomock.Any()).Return([]*policyV1alpha1.Egress{
+		newTestEgressPolicy("egress-1", []string{"sa-1"}, []string{"foo.com", "*.bar.com"},
+			policyV1alpha1.PortSpec{Number: 80, Protocol: "http"},
+			policyV1alpha1.PortSpec{Number: 443, Protocol: "https"},
+		),
+	}).Times(1)
+
+	mc := &MeshCatalog{
+		configurator:     mockCfg,
+		policyController: mockPolicyController,
+	}
+
+	// The gateway cannot determine the host requested for a wildcard host, so its traffic would bypass
+	// the egress gateway and the whole policy is ignored
+	actual, err := mc.GetEgressTrafficPolicy(identity.K8sServiceAccount{Name: "sa-1", Namespace: "test"}.ToServiceIdentity())
+	assert.Nil(err)
+	assert.NotNil(actual)
+	assert.Empty(actual.TrafficMatches)
+	assert.Empty(actual.ClustersConfigs)
+	assert.Empty(actual.HTTPRouteConfigsPerPort)
+}
+
+func TestListServiceIdentitiesForEgressGatewayService(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockCfg.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	gatewayIdentity := identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity()
+
+	mc := &MeshCatalog{
+		configurator:    mockCfg,
+		egressGatewayIdentity: gatewayIdentity,
+	}
+
+	assert.Equal([]identity.ServiceIdentity{gatewayIdentity}, mc.ListServiceIdentitiesForService(egressgateway.GetService("osm-system")))
+	assert.Nil(mc.ListServiceIdentitiesForService(service.MeshService{Name: egressgateway.ServiceName, Namespace: "other"}))
+}
@@ -11,11 +11,13 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/egressdns"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/policy"
 
@@ -219,6 +221,7 @@ func TestGetEgressTrafficPolicy(t *testing.T) {
 					{
 						DestinationPort:     100, // Used by foo.com
 						DestinationProtocol: "tcp",
+						DestinationIPRanges: []string{"1.1.1.1/32"}, // Resolved for foo.com
 						Cluster:             "100",
 					},
 				},
@@ -334,9 +337,67 @@ func TestGetEgressTrafficPolicy(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "egress policy for TCP port with hosts and IP addresses",
+			egressPolicies: []*policyV1alpha1.Egress{
+				{
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{
+							"foo.com",
+							"2.2.2.2",
+						},
+						IPAddresses: []string{
+							"10.0.0.0/8",
+						},
+						Ports: []policyV1alpha1.PortSpec{
+							{
+								Number:   5432,
+								Protocol: "tcp",
+							},
+						},
+					},
+				},
+				{
+					// The hosts do not resolve to any IP address, so the port is skipped
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{
+							"unresolved.com",
+						},
+						Ports: []policyV1alpha1.PortSpec{
+							{
+								Number:   3306,
+								Protocol: "tcp-server-first",
+							},
+						},
+					},
+				},
+			},
+			httpRouteGroups: nil, // no SMI HTTP route matches
+			expectedEgressPolicy: &trafficpolicy.EgressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.TrafficMatch{
+					{
+						DestinationPort:     5432,
+						DestinationProtocol: "tcp",
+						DestinationIPRanges: []string{"10.0.0.0/8", "2.2.2.2/32", "1.1.1.1/32"},
+						Cluster:             "5432",
+					},
+				},
+				HTTPRouteConfigsPerPort: map[int][]*trafficpolicy.EgressHTTPRouteConfig{},
+				ClustersConfigs: []*trafficpolicy.EgressClusterConfig{
+					{
+						Name: "5432",
+						Port: 5432,
+					},
+				},
+			},
+			expectError: false,
+		},
 	}
 
 	testSourceIdentity := identity.ServiceIdentity("foo.bar.cluster.local")
+	mockResolver := egressdns.NewMockResolver(mockCtrl)
+	mockResolver.EXPECT().Resolve("foo.com").Return([]string{"1.1.1.1"}).AnyTimes()
+	mockResolver.EXPECT().Resolve("unresolved.com").Return(nil).AnyTimes()
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Running test case %d: %s", i, tc.name), func(t *testing.T) {
@@ -349,12 +410,13 @@ func TestGetEgressTrafficPolicy(t *testing.T) {
 			mockPolicyController.EXPECT().ListEgressPoliciesForSourceIdentity(gomock.Any()).Return(tc.egressPolicies).Times(1)
 
 			mc := &MeshCatalog{
-				meshSpec:         mockMeshSpec,
-				configurator:     mockCfg,
-				policyController: mockPolicyController,
+				meshSpec:          mockMeshSpec,
+				configurator:      mockCfg,
+				policyController:  mockPolicyController,
+				egressDNSResolver: mockResolver,
 			}
 
-			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressPolicy: true}).Times(1)
+			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressPolicy: true}).Times(2)
 
 			actual, err := mc.GetEgressTrafficPolicy(testSourceIdentity)
 			assert.Equal(tc.expectError, err != nil)
@@ -662,13 +724,128 @@ func TestBuildHTTPRouteConfigs(t *testing.T) {
 				meshSpec: mockMeshSpec,
 			}
 
-			routeConfigs, clusterConfigs := mc.buildHTTPRouteConfigs(tc.egressPolicy, tc.egressPort)
+			routeConfigs, clusterConfigs := mc.buildHTTPRouteConfigs(tc.egressPolicy, policyV1alpha1.PortSpec{Number: tc.egressPort, Protocol: "http"}, false)
 			assert.ElementsMatch(tc.expectedRouteConfigs, routeConfigs)
 			assert.ElementsMatch(tc.expectedClusterConfigs, clusterConfigs)
 		})
 	}
 }
 
+func TestGetEgressTLSConfig(t *testing.T) {
+	egressPolicy := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "egress-1",
+			Namespace: "test",
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		portSpec          policyV1alpha1.PortSpec
+		expectedTLSConfig *trafficpolicy.EgressTLSConfig
+	}{
+		{
+			name:              "TLS origination not configured",
+			portSpec:          policyV1alpha1.PortSpec{Number: 80, Protocol: "http"},
+			expectedTLSConfig: nil,
+		},
+		{
+			name: "TLS origination is ignored for non HTTP ports",
+			portSpec: policyV1alpha1.PortSpec{Number: 443, Protocol: "https", TLS: &policyV1alpha1.EgressTLSSpec{
+				CASecretName: "ca",
+			}},
+			expectedTLSConfig: nil,
+		},
+		{
+			name: "SNI defaults to the host",
+			portSpec: policyV1alpha1.PortSpec{Number: 80, Protocol: "http", TLS: &policyV1alpha1.EgressTLSSpec{
+				CASecretName: "ca",
+			}},
+			expectedTLSConfig: &trafficpolicy.EgressTLSConfig{
+				SNI:          "foo.com",
+				CACertSecret: types.NamespacedName{Namespace: "test", Name: "ca"},
+			},
+		},
+		{
+			name: "SNI and client certificate specified",
+			portSpec: policyV1alpha1.PortSpec{Number: 80, Protocol: "http", TLS: &policyV1alpha1.EgressTLSSpec{
+				SNI:                  "api.foo.com",
+				CASecretName:         "ca",
+				ClientCertSecretName: "client",
+			}},
+			expectedTLSConfig: &trafficpolicy.EgressTLSConfig{
+				SNI:              "api.foo.com",
+				CACertSecret:     types.NamespacedName{Namespace: "test", Name: "ca"},
+				ClientCertSecret: &types.NamespacedName{Namespace: "test", Name: "client"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual := getEgressTLSConfig(egressPolicy, tc.portSpec, "foo.com")
+			assert.Equal(tc.expectedTLSConfig, actual)
+		})
+	}
+}
+
+func TestBuildHTTPRouteConfigsWithTLSOrigination(t *testing.T) {
+	assert := tassert.New(t)
+
+	egressPolicy := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "egress-1",
+			Namespace: "test",
+		},
+		Spec: policyV1alpha1.EgressSpec{
+			Hosts: []string{"foo.com"},
+		},
+	}
+	portSpec := policyV1alpha1.PortSpec{Number: 80, Protocol: "http", TLS: &policyV1alpha1.EgressTLSSpec{
+		CASecretName: "ca",
+	}}
+	mc := &MeshCatalog{}
+
+	_, clusterConfigs := mc.buildHTTPRouteConfigs(egressPolicy, portSpec, false)
+	assert.Len(clusterConfigs, 1)
+	assert.Equal(&trafficpolicy.EgressTLSConfig{
+		SNI:          "foo.com",
+		CACertSecret: types.NamespacedName{Namespace: "test", Name: "ca"},
+	}, clusterConfigs[0].TLS)
+
+	// TLS is originated by the egress gateway when the traffic is routed through it
+	_, clusterConfigs = mc.buildHTTPRouteConfigs(egressPolicy, portSpec, true)
+	assert.Len(clusterConfigs, 1)
+	assert.True(clusterConfigs[0].ViaEgressGateway)
+	assert.Nil(clusterConfigs[0].TLS)
+}
+
+func TestBuildHTTPRouteConfigsWithWildcardHost(t *testing.T) {
+	assert := tassert.New(t)
+
+	egressPolicy := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "egress-1",
+			Namespace: "test",
+		},
+		Spec: policyV1alpha1.EgressSpec{
+			Hosts: []string{"*.foo.com"},
+		},
+	}
+	portSpec := policyV1alpha1.PortSpec{Number: 80, Protocol: "http"}
+	mc := &MeshCatalog{}
+
+	// Wildcard hosts are proxied by the sidecar without TLS origination, as they are rejected while the egress gateway is enabled
+	routeConfigs, clusterConfigs := mc.buildHTTPRouteConfigs(egressPolicy, portSpec, false)
+	assert.Len(routeConfigs, 1)
+	assert.Equal([]string{"*.foo.com", "*.foo.com:80"}, routeConfigs[0].Hostnames)
+	assert.Equal([]*trafficpolicy.EgressClusterConfig{
+		{Name: "*.foo.com:80", Host: "*.foo.com", Port: 80},
+	}, clusterConfigs)
+}
+
 func TestGetHTTPRouteMatchesFromHTTPRouteGroup(t *testing.T) {
 	assert := tassert.New(t)
 
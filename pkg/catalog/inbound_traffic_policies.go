@@ -45,11 +45,11 @@ func (mc *MeshCatalog) GetInboundMeshTrafficPolicy(upstreamIdentity identity.Ser
 	// Build configurations per upstream service
 	for _, upstreamSvc := range allUpstreamServices {
 		// ---
-		// Create local cluster configs for this upstram service
+		// Create local cluster configs for this upstram service. The address of a local cluster is the loopback
+		// address of the IP family of the proxy, set when the cluster is built for the proxy.
 		clusterConfigForSvc := &trafficpolicy.MeshClusterConfig{
 			Name:    upstreamSvc.EnvoyLocalClusterName(),
 			Service: upstreamSvc,
-			Address: constants.LocalhostIPAddress,
 			Port:    uint32(upstreamSvc.TargetPort),
 		}
 		clusterConfigs = append(clusterConfigs, clusterConfigForSvc)
@@ -59,16 +59,17 @@ func (mc *MeshCatalog) GetInboundMeshTrafficPolicy(upstreamIdentity identity.Ser
 		// The TrafficMatch will be used by LDS to program a filter chain match
 		// for this upstream service on the upstream server to accept inbound
 		// traffic.
+		protocol := getInboundProtocol(upstreamSvc, permissiveMode, trafficTargets)
 		trafficMatchForUpstreamSvc := &trafficpolicy.TrafficMatch{
-			Name:                fmt.Sprintf("%s_%d_%s", upstreamSvc, upstreamSvc.TargetPort, upstreamSvc.Protocol),
+			Name:                fmt.Sprintf("%s_%d_%s", upstreamSvc, upstreamSvc.TargetPort, protocol),
 			DestinationPort:     int(upstreamSvc.TargetPort),
-			DestinationProtocol: upstreamSvc.Protocol,
+			DestinationProtocol: protocol,
 		}
 		trafficMatches = append(trafficMatches, trafficMatchForUpstreamSvc)
 
 		// Build the HTTP route configs for this service and port combination.
 		// If the port's protocol corresponds to TCP, we can skip this step
-		if upstreamSvc.Protocol == constants.ProtocolTCP || upstreamSvc.Protocol == constants.ProtocolTCPServerFirst {
+		if isTCPProtocol(protocol) {
 			continue
 		}
 		// ---
@@ -77,7 +78,14 @@ func (mc *MeshCatalog) GetInboundMeshTrafficPolicy(upstreamIdentity identity.Ser
 		// The routes are derived from SMI TrafficTarget and TrafficSplit policies in SMI mode,
 		// and are wildcarded in permissive mode. The downstreams that can access this upstream
 		// on the configured routes is also determined based on the traffic policy mode.
-		inboundTrafficPolicies := mc.getInboundTrafficPoliciesForUpstream(upstreamIdentity, upstreamSvc, permissiveMode, trafficTargets)
+		localCluster := service.WeightedCluster{
+			ClusterName: service.ClusterName(upstreamSvc.EnvoyLocalClusterName()),
+			Weight:      constants.ClusterWeightAcceptAll,
+		}
+		inboundTrafficPolicies := mc.getInboundTrafficPoliciesForUpstream(upstreamIdentity, upstreamSvc, permissiveMode, trafficTargets, localCluster)
+		if !permissiveMode {
+			mc.allowMulticlusterGateway(inboundTrafficPolicies)
+		}
 		routeConfigPerPort[int(upstreamSvc.TargetPort)] = append(routeConfigPerPort[int(upstreamSvc.TargetPort)], inboundTrafficPolicies)
 	}
 
@@ -88,38 +96,73 @@ func (mc *MeshCatalog) GetInboundMeshTrafficPolicy(upstreamIdentity identity.Ser
 	}
 }
 
-func (mc *MeshCatalog) getInboundTrafficPoliciesForUpstream(upstreamIdentity identity.ServiceIdentity, upstreamSvc service.MeshService, permissiveMode bool, trafficTargets []*access.TrafficTarget) *trafficpolicy.InboundTrafficPolicy {
+// GetInboundServiceProtocol returns the protocol with which the inbound traffic to the given upstream service is proxied
+// by the proxies of the given upstream identity
+func (mc *MeshCatalog) GetInboundServiceProtocol(upstreamIdentity identity.ServiceIdentity, upstreamSvc service.MeshService) string {
+	if upstreamSvc.Protocol != constants.ProtocolAuto {
+		return upstreamSvc.Protocol
+	}
+
+	permissiveMode := mc.configurator.IsPermissiveTrafficPolicyMode()
+	var trafficTargets []*access.TrafficTarget
+	if !permissiveMode {
+		trafficTargets = mc.meshSpec.ListTrafficTargets(smi.WithTrafficTargetDestination(upstreamIdentity.ToK8sServiceAccount()))
+	}
+	return getInboundProtocol(upstreamSvc, permissiveMode, trafficTargets)
+}
+
+// getInboundProtocol returns the protocol with which the inbound traffic to the given upstream service is proxied,
+// given the traffic policy mode and the TrafficTargets whose destination is the identity of the upstream service.
+// The protocol of the ports whose protocol is detected by the downstream cannot be detected on the inbound traffic,
+// which is TLS encrypted. Such ports are proxied as HTTP when HTTPRouteGroup rules restrict the traffic to the
+// upstream service, for the rules to be enforced, and as TCP otherwise.
+func getInboundProtocol(upstreamSvc service.MeshService, permissiveMode bool, trafficTargets []*access.TrafficTarget) string {
+	if upstreamSvc.Protocol != constants.ProtocolAuto {
+		return upstreamSvc.Protocol
+	}
+	if permissiveMode {
+		return constants.ProtocolTCP
+	}
+
+	for _, trafficTarget := range trafficTargets {
+		for _, rule := range trafficTarget.Spec.Rules {
+			if rule.Kind == smi.HTTPRouteGroupKind {
+				log.Warn().Msgf("Port %d of service %s does not declare its protocol and is allowed by HTTPRouteGroup rules of TrafficTarget %s/%s, "+
+					"proxying its inbound traffic as HTTP; set the appProtocol of the port to declare its protocol",
+					upstreamSvc.Port, upstreamSvc, trafficTarget.Namespace, trafficTarget.Name)
+				return constants.ProtocolHTTP
+			}
+		}
+	}
+	return constants.ProtocolTCP
+}
+
+// getInboundTrafficPoliciesForUpstream returns the inbound HTTP traffic policy for the given upstream service,
+// with routes directing traffic to the given routing cluster
+func (mc *MeshCatalog) getInboundTrafficPoliciesForUpstream(upstreamIdentity identity.ServiceIdentity, upstreamSvc service.MeshService, permissiveMode bool, trafficTargets []*access.TrafficTarget, routingCluster service.WeightedCluster) *trafficpolicy.InboundTrafficPolicy {
 	var inboundPolicyForUpstreamSvc *trafficpolicy.InboundTrafficPolicy
 
 	if permissiveMode {
 		// Add a wildcard HTTP route that allows any downstream client to access the upstream service
 		hostnames := k8s.GetHostnamesForService(upstreamSvc, true /* local namespace FQDN should always be allowed for inbound routes*/)
 		inboundPolicyForUpstreamSvc = trafficpolicy.NewInboundTrafficPolicy(upstreamSvc.FQDN(), hostnames)
-		localCluster := service.WeightedCluster{
-			ClusterName: service.ClusterName(upstreamSvc.EnvoyLocalClusterName()),
-			Weight:      constants.ClusterWeightAcceptAll,
-		}
-		inboundPolicyForUpstreamSvc.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{localCluster}), identity.WildcardServiceIdentity)
+		inboundPolicyForUpstreamSvc.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{routingCluster}), identity.WildcardServiceIdentity)
 	} else {
 		// Build the HTTP routes from SMI TrafficTarget and HTTPRouteGroup configurations
-		inboundPolicyForUpstreamSvc = mc.buildInboundHTTPPolicyFromTrafficTarget(upstreamIdentity, upstreamSvc, trafficTargets)
+		inboundPolicyForUpstreamSvc = mc.buildInboundHTTPPolicyFromTrafficTarget(upstreamIdentity, upstreamSvc, trafficTargets, routingCluster)
 	}
 
 	return inboundPolicyForUpstreamSvc
 }
 
-func (mc *MeshCatalog) buildInboundHTTPPolicyFromTrafficTarget(upstreamIdentity identity.ServiceIdentity, upstreamSvc service.MeshService, trafficTargets []*access.TrafficTarget) *trafficpolicy.InboundTrafficPolicy {
+func (mc *MeshCatalog) buildInboundHTTPPolicyFromTrafficTarget(upstreamIdentity identity.ServiceIdentity, upstreamSvc service.MeshService, trafficTargets []*access.TrafficTarget, routingCluster service.WeightedCluster) *trafficpolicy.InboundTrafficPolicy {
 	hostnames := k8s.GetHostnamesForService(upstreamSvc, true /* local namespace FQDN should always be allowed for inbound routes*/)
 	inboundPolicy := trafficpolicy.NewInboundTrafficPolicy(upstreamSvc.FQDN(), hostnames)
-	localCluster := service.WeightedCluster{
-		ClusterName: service.ClusterName(upstreamSvc.EnvoyLocalClusterName()),
-		Weight:      constants.ClusterWeightAcceptAll,
-	}
 
 	var routingRules []*trafficpolicy.Rule
 	// From each TrafficTarget and HTTPRouteGroup configuration associated with this service, build routes for it.
 	for _, trafficTarget := range trafficTargets {
-		rules := mc.getRoutingRulesFromTrafficTarget(*trafficTarget, upstreamSvc, localCluster)
+		rules := mc.getRoutingRulesFromTrafficTarget(*trafficTarget, upstreamSvc, routingCluster)
 		// Multiple TrafficTarget objects can reference the same route, in which case such routes
 		// need to be merged to create a single route that includes all the downstream client identities
 		// this route is authorized for.
@@ -14,7 +14,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -200,13 +202,11 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|8080|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 8080, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    8080,
 					},
 					{
 						Name:    "ns1/s2|9090|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2", Port: 90, TargetPort: 9090, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    9090,
 					},
 				},
@@ -443,13 +443,11 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s2|90|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2", Port: 90, TargetPort: 90, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    90,
 					},
 				},
@@ -688,19 +686,16 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s1-apex|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1-apex", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s2|90|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2", Port: 90, TargetPort: 90, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    90,
 					},
 				},
@@ -858,19 +853,16 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s1-apex|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1-apex", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s2|90|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2", Port: 90, TargetPort: 90, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    90,
 					},
 				},
@@ -1007,19 +999,16 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s2|90|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2", Port: 90, TargetPort: 90, Protocol: "tcp"},
-						Address: "127.0.0.1",
 						Port:    90,
 					},
 					{
 						Name:    "ns1/s3|91|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s3", Port: 91, TargetPort: 91, Protocol: "tcp-server-first"},
-						Address: "127.0.0.1",
 						Port:    91,
 					},
 				},
@@ -1227,13 +1216,11 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s2|90|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2", Port: 90, TargetPort: 90, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    90,
 					},
 				},
@@ -1433,13 +1420,11 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s2|90|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2", Port: 90, TargetPort: 90, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    90,
 					},
 				},
@@ -1568,13 +1553,11 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 					{
 						Name:    "ns1/s1|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s1", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 					{
 						Name:    "ns1/s2-apex|80|local",
 						Service: service.MeshService{Namespace: "ns1", Name: "s2-apex", Port: 80, TargetPort: 80, Protocol: "http"},
-						Address: "127.0.0.1",
 						Port:    80,
 					},
 				},
@@ -1602,6 +1585,7 @@ func TestGetInboundMeshTrafficPolicy(t *testing.T) {
 			}
 
 			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissiveMode)
+			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
 			mockMeshSpec.EXPECT().ListTrafficTargets(gomock.Any()).Return(tc.trafficTargets).AnyTimes()
 			mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return(tc.httpRouteGroups).AnyTimes()
 			tc.prepare(mockMeshSpec, tc.trafficSplits)
@@ -1867,3 +1851,54 @@ func TestGetTrafficSpecName(t *testing.T) {
 	expected := trafficpolicy.TrafficSpecName(fmt.Sprintf("HTTPRouteGroup/%s/%s", tests.Namespace, tests.RouteGroupName))
 	assert.Equal(actual, expected)
 }
+
+func TestGetInboundProtocol(t *testing.T) {
+	autoSvc := service.MeshService{Name: "s1", Namespace: "ns1", Port: 80, TargetPort: 8080, Protocol: constants.ProtocolAuto}
+	newTrafficTarget := func(kind string) *access.TrafficTarget {
+		return &access.TrafficTarget{
+			ObjectMeta: metav1.ObjectMeta{Name: "t1", Namespace: "ns1"},
+			Spec: access.TrafficTargetSpec{
+				Rules: []access.TrafficTargetRule{{Kind: kind, Name: "rule"}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		upstreamSvc      service.MeshService
+		permissiveMode   bool
+		trafficTargets   []*access.TrafficTarget
+		expectedProtocol string
+	}{
+		{
+			name:             "declared protocol",
+			upstreamSvc:      service.MeshService{Name: "s1", Namespace: "ns1", Port: 80, TargetPort: 8080, Protocol: constants.ProtocolGRPC},
+			trafficTargets:   []*access.TrafficTarget{newTrafficTarget(smi.TCPRouteKind)},
+			expectedProtocol: constants.ProtocolGRPC,
+		},
+		{
+			name:             "detected protocol in permissive mode",
+			upstreamSvc:      autoSvc,
+			permissiveMode:   true,
+			expectedProtocol: constants.ProtocolTCP,
+		},
+		{
+			name:             "detected protocol allowed by TCPRoute rules",
+			upstreamSvc:      autoSvc,
+			trafficTargets:   []*access.TrafficTarget{newTrafficTarget(smi.TCPRouteKind)},
+			expectedProtocol: constants.ProtocolTCP,
+		},
+		{
+			name:             "detected protocol allowed by HTTPRouteGroup rules",
+			upstreamSvc:      autoSvc,
+			trafficTargets:   []*access.TrafficTarget{newTrafficTarget(smi.TCPRouteKind), newTrafficTarget(smi.HTTPRouteGroupKind)},
+			expectedProtocol: constants.ProtocolHTTP,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expectedProtocol, getInboundProtocol(tc.upstreamSvc, tc.permissiveMode, tc.trafficTargets))
+		})
+	}
+}
@@ -14,27 +14,24 @@ import (
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
-)
-
-const (
-	// singeIPPrefixLen is the IP prefix length for a single IP address
-	singeIPPrefixLen = "/32"
+	"github.com/openservicemesh/osm/pkg/utils"
 )
 
 // GetIngressTrafficPolicy returns the ingress traffic policy for the given mesh service
 // Depending on if the IngressBackend API is enabled, the policies will be generated either from the IngressBackend
-// or Kubernetes Ingress API.
+// or Kubernetes Ingress API. When the service does not have an IngressBackend policy, the policies are derived from the
+// Kubernetes Ingress resources of the IngressClasses monitored by OSM. Otherwise, the ingress gateway is authorized
+// to access the service if the service is a backend of a route programmed on the ingress gateway.
 func (mc *MeshCatalog) GetIngressTrafficPolicy(svc service.MeshService) (*trafficpolicy.IngressTrafficPolicy, error) {
 	ingressBackendPolicy := mc.policyController.GetIngressBackendPolicy(svc)
 	if ingressBackendPolicy == nil {
 		log.Trace().Msgf("Did not find IngressBackend policy for service %s", svc)
-		return nil, nil
+		if ingressPolicy := mc.getKubernetesIngressTrafficPolicy(svc); ingressPolicy != nil {
+			return ingressPolicy, nil
+		}
+		return mc.getIngressGatewayBackendTrafficPolicy(svc), nil
 	}
 
-	// The status field will be updated after the policy is processed.
-	// Note: The original pointer returned by cache.Store must not be modified for thread safety.
-	ingressBackendWithStatus := *ingressBackendPolicy
-
 	var trafficRoutingRules []*trafficpolicy.Rule
 	sourceServiceIdentities := mapset.NewSet()
 	var trafficMatches []*trafficpolicy.IngressTrafficMatch
@@ -59,19 +56,12 @@ func (mc *MeshCatalog) GetIngressTrafficPolicy(svc service.MeshService) (*traffi
 				sourceMeshSvc := service.MeshService{Name: source.Name, Namespace: source.Namespace}
 				endpoints := mc.listEndpointsForService(sourceMeshSvc)
 				if len(endpoints) == 0 {
-					ingressBackendWithStatus.Status = policyV1alpha1.IngressBackendStatus{
-						CurrentStatus: "error",
-						Reason:        fmt.Sprintf("endpoints not found for service %s/%s", source.Namespace, source.Name),
-					}
-					if _, err := mc.kubeController.UpdateStatus(&ingressBackendWithStatus); err != nil {
-						log.Error().Err(err).Msg("Error updating status for IngressBackend")
-					}
 					return nil, errors.Errorf("Could not list endpoints of the source service %s/%s specified in the IngressBackend %s/%s",
 						source.Namespace, source.Name, ingressBackendPolicy.Namespace, ingressBackendPolicy.Name)
 				}
 
 				for _, ep := range endpoints {
-					sourceCIDR := ep.IP.String() + singeIPPrefixLen
+					sourceCIDR := utils.GetCIDRForIP(ep.IP)
 					if sourceIPSet.Add(sourceCIDR) {
 						sourceIPRanges = append(sourceIPRanges, sourceCIDR)
 					}
@@ -133,14 +123,6 @@ func (mc *MeshCatalog) GetIngressTrafficPolicy(svc service.MeshService) (*traffi
 		return nil, nil
 	}
 
-	ingressBackendWithStatus.Status = policyV1alpha1.IngressBackendStatus{
-		CurrentStatus: "committed",
-		Reason:        "successfully committed by the system",
-	}
-	if _, err := mc.kubeController.UpdateStatus(&ingressBackendWithStatus); err != nil {
-		log.Error().Err(err).Msg("Error updating status for IngressBackend")
-	}
-
 	// Create an inbound traffic policy from the routing rules
 	// TODO(#3779): Implement HTTP route matching from IngressBackend.Spec.Matches
 	httpRoutePolicy := &trafficpolicy.InboundTrafficPolicy{
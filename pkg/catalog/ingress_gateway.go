@@ -0,0 +1,631 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/ingressgateway"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+const (
+	// kindGateway is the kind of a Gateway referenced as the parent of a route
+	kindGateway = "Gateway"
+
+	// kindService is the kind of a Service referenced as the backend of a route
+	kindService = "Service"
+
+	// wildcardHostname is the hostname matching any host
+	wildcardHostname = "*"
+)
+
+// isIngressGatewayEnabled returns a boolean indicating if the ingress gateway is enabled
+func (mc *MeshCatalog) isIngressGatewayEnabled() bool {
+	return mc.gatewayAPIController != nil && mc.configurator.GetFeatureFlags().EnableIngressGateway
+}
+
+// ingressGatewayPolicyBuilder is the type used to merge the listeners of the Gateways managed by OSM per port
+// and to collect the backends referenced by the routes attached to them
+type ingressGatewayPolicyBuilder struct {
+	meshServices []service.MeshService
+	listeners    map[int]*trafficpolicy.IngressGatewayListener
+	routeConfigs map[int]map[string]*trafficpolicy.OutboundTrafficPolicy
+	upstreams    map[string]*trafficpolicy.IngressGatewayUpstream
+}
+
+// GetIngressGatewayTrafficPolicy returns the traffic policy for the ingress gateway, or nil if the ingress gateway
+// is disabled. The listeners of the Gateways managed by OSM are merged per port, and the HTTPRoutes and TLSRoutes
+// attached to the listeners determine how the traffic accepted on each port is routed to the mesh backends.
+// The policy is computed once until the next event updating the proxies, and must not be modified by the caller.
+func (mc *MeshCatalog) GetIngressGatewayTrafficPolicy() *trafficpolicy.IngressGatewayTrafficPolicy {
+	if !mc.isIngressGatewayEnabled() {
+		return nil
+	}
+
+	if mc.msgBroker == nil {
+		return mc.buildIngressGatewayTrafficPolicy()
+	}
+
+	// The event count is read before building the policy, so that an event observed while building the policy
+	// invalidates it
+	eventCount := mc.msgBroker.GetTotalQProxyEventCount()
+
+	c := &mc.ingressGatewayPolicyCache
+	c.Lock()
+	defer c.Unlock()
+	if !c.valid || c.eventCount != eventCount {
+		c.policy = mc.buildIngressGatewayTrafficPolicy()
+		c.eventCount = eventCount
+		c.valid = true
+	}
+	return c.policy
+}
+
+// buildIngressGatewayTrafficPolicy builds the traffic policy for the ingress gateway from the Gateways and routes
+func (mc *MeshCatalog) buildIngressGatewayTrafficPolicy() *trafficpolicy.IngressGatewayTrafficPolicy {
+	b := &ingressGatewayPolicyBuilder{
+		meshServices: mc.listMeshServices(),
+		listeners:    make(map[int]*trafficpolicy.IngressGatewayListener),
+		routeConfigs: make(map[int]map[string]*trafficpolicy.OutboundTrafficPolicy),
+		upstreams:    make(map[string]*trafficpolicy.IngressGatewayUpstream),
+	}
+
+	httpRoutes := mc.gatewayAPIController.ListHTTPRoutes()
+	tlsRoutes := mc.gatewayAPIController.ListTLSRoutes()
+
+	for _, gateway := range mc.gatewayAPIController.ListGateways() {
+		for _, listener := range gateway.Spec.Listeners {
+			switch listener.Protocol {
+			case gatewayv1alpha2.HTTPProtocolType, gatewayv1alpha2.HTTPSProtocolType:
+				mc.addIngressGatewayHTTPListener(b, gateway, listener, httpRoutes)
+
+			case gatewayv1alpha2.TLSProtocolType:
+				mc.addIngressGatewayTLSListener(b, gateway, listener, tlsRoutes)
+
+			default:
+				log.Error().Msgf("Unsupported protocol %s for listener %s of Gateway %s/%s, ignoring listener",
+					listener.Protocol, listener.Name, gateway.Namespace, gateway.Name)
+			}
+		}
+	}
+
+	return b.build()
+}
+
+// addIngressGatewayHTTPListener adds the given HTTP or HTTPS listener and the HTTPRoutes attached to it to the policy
+func (mc *MeshCatalog) addIngressGatewayHTTPListener(b *ingressGatewayPolicyBuilder, gateway *gatewayv1alpha2.Gateway,
+	listener gatewayv1alpha2.Listener, httpRoutes []*gatewayv1alpha2.HTTPRoute) {
+	port := int(listener.Port)
+	filterChain := &trafficpolicy.IngressGatewayFilterChain{
+		Name:     getIngressGatewayFilterChainName(gateway, listener),
+		Protocol: constants.ProtocolHTTP,
+	}
+
+	if listener.Protocol == gatewayv1alpha2.HTTPSProtocolType {
+		if !isTLSTerminated(listener) {
+			log.Error().Msgf("HTTPS listener %s of Gateway %s/%s must terminate TLS, ignoring listener", listener.Name, gateway.Namespace, gateway.Name)
+			return
+		}
+		filterChain.Protocol = constants.ProtocolHTTPS
+		filterChain.CertificateSecrets = getListenerCertificateSecrets(gateway, listener)
+		if len(filterChain.CertificateSecrets) == 0 {
+			log.Error().Msgf("HTTPS listener %s of Gateway %s/%s does not reference any certificate, ignoring listener", listener.Name, gateway.Namespace, gateway.Name)
+			return
+		}
+		if listener.Hostname != nil {
+			filterChain.ServerNames = []string{string(*listener.Hostname)}
+		}
+	}
+
+	for _, route := range httpRoutes {
+		if !mc.isRouteAttachedToListener(route.Namespace, route.Spec.ParentRefs, gateway, listener) {
+			continue
+		}
+
+		hostnames := getIntersectingHostnames(listener.Hostname, route.Spec.Hostnames)
+		if len(hostnames) == 0 {
+			continue
+		}
+
+		for _, rule := range route.Spec.Rules {
+			var backendRefs []gatewayv1alpha2.BackendRef
+			for _, httpBackendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, httpBackendRef.BackendRef)
+			}
+			weightedClusters := b.getWeightedClusters(route.Namespace, backendRefs, constants.ProtocolHTTP)
+			if len(weightedClusters) == 0 {
+				log.Debug().Msgf("No valid backends for a rule in HTTPRoute %s/%s, ignoring rule", route.Namespace, route.Name)
+				continue
+			}
+
+			matches := rule.Matches
+			if len(matches) == 0 {
+				// A rule without matches matches all the requests
+				matches = []gatewayv1alpha2.HTTPRouteMatch{{}}
+			}
+			for _, match := range matches {
+				routeMatch, ok := getIngressGatewayHTTPRouteMatch(match)
+				if !ok {
+					log.Error().Msgf("Unsupported match in HTTPRoute %s/%s, ignoring match", route.Namespace, route.Name)
+					continue
+				}
+				routeClusters := mapset.NewSet()
+				for _, weightedCluster := range weightedClusters {
+					routeClusters.Add(weightedCluster)
+				}
+				for _, hostname := range hostnames {
+					b.addHTTPRoute(port, hostname, &trafficpolicy.RouteWeightedClusters{
+						HTTPRouteMatch:   routeMatch,
+						WeightedClusters: routeClusters,
+					})
+				}
+			}
+		}
+	}
+
+	b.addFilterChain(port, filterChain)
+}
+
+// addIngressGatewayTLSListener adds a filter chain for each TLSRoute attached to the given TLS listener. The TLS traffic
+// is routed based on its SNI, and is passed through to the backend unless the listener terminates TLS.
+func (mc *MeshCatalog) addIngressGatewayTLSListener(b *ingressGatewayPolicyBuilder, gateway *gatewayv1alpha2.Gateway,
+	listener gatewayv1alpha2.Listener, tlsRoutes []*gatewayv1alpha2.TLSRoute) {
+	var certificateSecrets []types.NamespacedName
+	if isTLSTerminated(listener) {
+		certificateSecrets = getListenerCertificateSecrets(gateway, listener)
+		if len(certificateSecrets) == 0 {
+			log.Error().Msgf("TLS listener %s of Gateway %s/%s terminating TLS does not reference any certificate, ignoring listener", listener.Name, gateway.Namespace, gateway.Name)
+			return
+		}
+	}
+
+	for _, route := range tlsRoutes {
+		if !mc.isRouteAttachedToListener(route.Namespace, route.Spec.ParentRefs, gateway, listener) {
+			continue
+		}
+
+		hostnames := getIntersectingHostnames(listener.Hostname, route.Spec.Hostnames)
+		if len(hostnames) == 0 {
+			continue
+		}
+
+		var backendRefs []gatewayv1alpha2.BackendRef
+		for _, rule := range route.Spec.Rules {
+			backendRefs = append(backendRefs, rule.BackendRefs...)
+		}
+		weightedClusters := b.getWeightedClusters(route.Namespace, backendRefs, constants.ProtocolTCP)
+		if len(weightedClusters) == 0 {
+			log.Debug().Msgf("No valid backends for TLSRoute %s/%s, ignoring route", route.Namespace, route.Name)
+			continue
+		}
+
+		filterChain := &trafficpolicy.IngressGatewayFilterChain{
+			Name:               fmt.Sprintf("%s/%s/%s", getIngressGatewayFilterChainName(gateway, listener), route.Namespace, route.Name),
+			Protocol:           constants.ProtocolTCP,
+			CertificateSecrets: certificateSecrets,
+			WeightedClusters:   weightedClusters,
+		}
+		// An empty list of server names matches any SNI
+		if !(len(hostnames) == 1 && hostnames[0] == wildcardHostname) {
+			filterChain.ServerNames = hostnames
+		}
+		b.addFilterChain(int(listener.Port), filterChain)
+	}
+}
+
+// isRouteAttachedToListener returns a boolean indicating if a route in the given namespace with the given parent
+// references is attached to the given listener of the Gateway, and is allowed to attach to the listener
+func (mc *MeshCatalog) isRouteAttachedToListener(routeNamespace string, parentRefs []gatewayv1alpha2.ParentRef,
+	gateway *gatewayv1alpha2.Gateway, listener gatewayv1alpha2.Listener) bool {
+	for _, parentRef := range parentRefs {
+		if parentRef.Group != nil && string(*parentRef.Group) != gatewayv1alpha2.GroupName {
+			continue
+		}
+		if parentRef.Kind != nil && string(*parentRef.Kind) != kindGateway {
+			continue
+		}
+		parentNamespace := routeNamespace
+		if parentRef.Namespace != nil {
+			parentNamespace = string(*parentRef.Namespace)
+		}
+		if parentNamespace != gateway.Namespace || string(parentRef.Name) != gateway.Name {
+			continue
+		}
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+			continue
+		}
+		return mc.isRouteNamespaceAllowed(routeNamespace, gateway, listener)
+	}
+	return false
+}
+
+// isRouteNamespaceAllowed returns a boolean indicating if the routes in the given namespace are allowed to attach
+// to the given listener. By default, only the routes in the namespace of the Gateway are allowed.
+func (mc *MeshCatalog) isRouteNamespaceAllowed(routeNamespace string, gateway *gatewayv1alpha2.Gateway, listener gatewayv1alpha2.Listener) bool {
+	from := gatewayv1alpha2.NamespacesFromSame
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil && listener.AllowedRoutes.Namespaces.From != nil {
+		from = *listener.AllowedRoutes.Namespaces.From
+	}
+
+	switch from {
+	case gatewayv1alpha2.NamespacesFromAll:
+		return true
+
+	case gatewayv1alpha2.NamespacesFromSelector:
+		if listener.AllowedRoutes.Namespaces.Selector == nil {
+			return false
+		}
+		selector, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid namespace selector for listener %s of Gateway %s/%s", listener.Name, gateway.Namespace, gateway.Name)
+			return false
+		}
+		ns := mc.kubeController.GetNamespace(routeNamespace)
+		if ns == nil {
+			return false
+		}
+		return selector.Matches(labels.Set(ns.Labels))
+
+	default:
+		return routeNamespace == gateway.Namespace
+	}
+}
+
+// getWeightedClusters returns the weighted clusters corresponding to the given backend references of a route in the
+// given namespace, and records the backends as upstreams of the ingress gateway. Only Services in the namespace of
+// the route can be referenced as backends.
+func (b *ingressGatewayPolicyBuilder) getWeightedClusters(routeNamespace string, backendRefs []gatewayv1alpha2.BackendRef, protocol string) []service.WeightedCluster {
+	var weightedClusters []service.WeightedCluster
+
+	for _, backendRef := range backendRefs {
+		if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && string(*backendRef.Kind) != kindService) {
+			log.Error().Msgf("Unsupported backend %s in namespace %s, only Services are supported", backendRef.Name, routeNamespace)
+			continue
+		}
+		if backendRef.Namespace != nil && string(*backendRef.Namespace) != routeNamespace {
+			log.Error().Msgf("Backend %s/%s is not in the namespace of the route %s, ignoring backend", *backendRef.Namespace, backendRef.Name, routeNamespace)
+			continue
+		}
+		if backendRef.Port == nil {
+			log.Error().Msgf("Backend %s/%s does not specify a port, ignoring backend", routeNamespace, backendRef.Name)
+			continue
+		}
+		weight := 1
+		if backendRef.Weight != nil {
+			weight = int(*backendRef.Weight)
+		}
+		if weight == 0 {
+			continue
+		}
+
+		svc, ok := b.getMeshService(string(backendRef.Name), routeNamespace, uint16(*backendRef.Port))
+		if !ok {
+			log.Error().Msgf("Backend %s/%s with port %d not found in the mesh, ignoring backend", routeNamespace, backendRef.Name, *backendRef.Port)
+			continue
+		}
+
+		clusterName := svc.EnvoyClusterName()
+		if upstream, ok := b.upstreams[clusterName]; !ok {
+			b.upstreams[clusterName] = &trafficpolicy.IngressGatewayUpstream{
+				Service:  svc,
+				Protocol: protocol,
+			}
+		} else if upstream.Protocol != protocol {
+			// The backend authorizes the ingress gateway for a single protocol
+			log.Error().Msgf("Backend %s is referenced by both HTTP and TLS routes, ignoring backend for %s routes", svc, protocol)
+			continue
+		}
+
+		weightedClusters = append(weightedClusters, service.WeightedCluster{
+			ClusterName: service.ClusterName(clusterName),
+			Weight:      weight,
+		})
+	}
+
+	return weightedClusters
+}
+
+// getMeshService returns the MeshService with the given name, namespace and port
+func (b *ingressGatewayPolicyBuilder) getMeshService(name, namespace string, port uint16) (service.MeshService, bool) {
+	for _, svc := range b.meshServices {
+		if svc.Name == name && svc.Namespace == namespace && svc.Port == port {
+			return svc, true
+		}
+	}
+	return service.MeshService{}, false
+}
+
+// addFilterChain adds the given filter chain to the listener for the given port
+func (b *ingressGatewayPolicyBuilder) addFilterChain(port int, filterChain *trafficpolicy.IngressGatewayFilterChain) {
+	if _, ok := b.listeners[port]; !ok {
+		b.listeners[port] = &trafficpolicy.IngressGatewayListener{Port: port}
+	}
+	b.listeners[port].FilterChains = append(b.listeners[port].FilterChains, filterChain)
+
+	// HTTP filter chains reference the route configuration for the port, which must exist even if no routes are attached
+	if filterChain.Protocol != constants.ProtocolTCP {
+		if _, ok := b.routeConfigs[port]; !ok {
+			b.routeConfigs[port] = make(map[string]*trafficpolicy.OutboundTrafficPolicy)
+		}
+	}
+}
+
+// addHTTPRoute adds the given route to the route configuration of the given hostname on the given port
+func (b *ingressGatewayPolicyBuilder) addHTTPRoute(port int, hostname string, route *trafficpolicy.RouteWeightedClusters) {
+	if _, ok := b.routeConfigs[port]; !ok {
+		b.routeConfigs[port] = make(map[string]*trafficpolicy.OutboundTrafficPolicy)
+	}
+	if _, ok := b.routeConfigs[port][hostname]; !ok {
+		hostnames := []string{hostname}
+		if hostname != wildcardHostname {
+			// The host header may include the port
+			hostnames = append(hostnames, fmt.Sprintf("%s:%d", hostname, port))
+		}
+		b.routeConfigs[port][hostname] = &trafficpolicy.OutboundTrafficPolicy{
+			Name:      hostname,
+			Hostnames: hostnames,
+		}
+	}
+	b.routeConfigs[port][hostname].Routes = append(b.routeConfigs[port][hostname].Routes, route)
+}
+
+// build returns the ingress gateway traffic policy, sorted to generate the same configuration for the same set of resources
+func (b *ingressGatewayPolicyBuilder) build() *trafficpolicy.IngressGatewayTrafficPolicy {
+	policy := &trafficpolicy.IngressGatewayTrafficPolicy{
+		HTTPRouteConfigsPerPort: make(map[int][]*trafficpolicy.OutboundTrafficPolicy),
+	}
+
+	for _, listener := range b.listeners {
+		sort.Slice(listener.FilterChains, func(i, j int) bool {
+			return listener.FilterChains[i].Name < listener.FilterChains[j].Name
+		})
+		policy.Listeners = append(policy.Listeners, listener)
+	}
+	sort.Slice(policy.Listeners, func(i, j int) bool {
+		return policy.Listeners[i].Port < policy.Listeners[j].Port
+	})
+
+	for port, routeConfigs := range b.routeConfigs {
+		policy.HTTPRouteConfigsPerPort[port] = []*trafficpolicy.OutboundTrafficPolicy{}
+		for _, routeConfig := range routeConfigs {
+			sortIngressGatewayRoutes(routeConfig.Routes)
+			policy.HTTPRouteConfigsPerPort[port] = append(policy.HTTPRouteConfigsPerPort[port], routeConfig)
+		}
+		sort.Slice(policy.HTTPRouteConfigsPerPort[port], func(i, j int) bool {
+			return policy.HTTPRouteConfigsPerPort[port][i].Name < policy.HTTPRouteConfigsPerPort[port][j].Name
+		})
+	}
+
+	for _, upstream := range b.upstreams {
+		policy.Upstreams = append(policy.Upstreams, upstream)
+	}
+	sort.Slice(policy.Upstreams, func(i, j int) bool {
+		return policy.Upstreams[i].Service.EnvoyClusterName() < policy.Upstreams[j].Service.EnvoyClusterName()
+	})
+
+	return policy
+}
+
+// sortIngressGatewayRoutes sorts the given routes by precedence, since the first matching route is used for a request:
+// exact path matches precede prefix matches, which precede regex matches, longer paths precede shorter paths, and routes
+// matching more headers or a method precede routes matching fewer headers or any method.
+func sortIngressGatewayRoutes(routes []*trafficpolicy.RouteWeightedClusters) {
+	pathMatchPrecedence := map[trafficpolicy.PathMatchType]int{
+		trafficpolicy.PathMatchExact:  0,
+		trafficpolicy.PathMatchPrefix: 1,
+		trafficpolicy.PathMatchRegex:  2,
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i].HTTPRouteMatch, routes[j].HTTPRouteMatch
+		if pathMatchPrecedence[a.PathMatchType] != pathMatchPrecedence[b.PathMatchType] {
+			return pathMatchPrecedence[a.PathMatchType] < pathMatchPrecedence[b.PathMatchType]
+		}
+		if len(a.Path) != len(b.Path) {
+			return len(a.Path) > len(b.Path)
+		}
+		if len(a.Headers) != len(b.Headers) {
+			return len(a.Headers) > len(b.Headers)
+		}
+		return a.Methods[0] != constants.WildcardHTTPMethod && b.Methods[0] == constants.WildcardHTTPMethod
+	})
+}
+
+// getIngressGatewayHTTPRouteMatch returns the HTTP route match corresponding to the given HTTPRoute match.
+// A boolean is returned to indicate if the match is supported.
+func getIngressGatewayHTTPRouteMatch(match gatewayv1alpha2.HTTPRouteMatch) (trafficpolicy.HTTPRouteMatch, bool) {
+	routeMatch := trafficpolicy.HTTPRouteMatch{
+		Path:          "/",
+		PathMatchType: trafficpolicy.PathMatchPrefix,
+		Methods:       []string{constants.WildcardHTTPMethod},
+	}
+
+	if len(match.QueryParams) > 0 {
+		return routeMatch, false
+	}
+
+	if match.Path != nil {
+		if match.Path.Value != nil {
+			routeMatch.Path = *match.Path.Value
+		}
+		if match.Path.Type != nil {
+			switch *match.Path.Type {
+			case gatewayv1alpha2.PathMatchExact:
+				routeMatch.PathMatchType = trafficpolicy.PathMatchExact
+			case gatewayv1alpha2.PathMatchRegularExpression:
+				routeMatch.PathMatchType = trafficpolicy.PathMatchRegex
+			}
+		}
+	}
+
+	if match.Method != nil {
+		routeMatch.Methods = []string{string(*match.Method)}
+	}
+
+	if len(match.Headers) > 0 {
+		// Header values are matched as regular expressions
+		routeMatch.Headers = make(map[string]string)
+		for _, header := range match.Headers {
+			value := regexp.QuoteMeta(header.Value)
+			if header.Type != nil && *header.Type == gatewayv1alpha2.HeaderMatchRegularExpression {
+				value = header.Value
+			}
+			routeMatch.Headers[strings.ToLower(string(header.Name))] = value
+		}
+	}
+
+	return routeMatch, true
+}
+
+// getIntersectingHostnames returns the hostnames of a route that match the hostname of the listener it is attached to.
+// An empty list is returned if none of the hostnames match, in which case the route is not attached to the listener.
+func getIntersectingHostnames(listenerHostname *gatewayv1alpha2.Hostname, routeHostnames []gatewayv1alpha2.Hostname) []string {
+	if len(routeHostnames) == 0 {
+		if listenerHostname == nil {
+			return []string{wildcardHostname}
+		}
+		return []string{string(*listenerHostname)}
+	}
+
+	hostnameSet := mapset.NewSet()
+	var hostnames []string
+	for _, routeHostname := range routeHostnames {
+		var hostname string
+		switch {
+		case listenerHostname == nil || isHostnameMatch(string(*listenerHostname), string(routeHostname)):
+			hostname = string(routeHostname)
+		case isHostnameMatch(string(routeHostname), string(*listenerHostname)):
+			hostname = string(*listenerHostname)
+		default:
+			continue
+		}
+		if hostnameSet.Add(hostname) {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	return hostnames
+}
+
+// isHostnameMatch returns a boolean indicating if the given hostname matches the given hostname pattern,
+// which may be prefixed with a wildcard label
+func isHostnameMatch(pattern, hostname string) bool {
+	if pattern == hostname {
+		return true
+	}
+	return utils.IsWildcardHost(pattern) && strings.HasSuffix(hostname, strings.TrimPrefix(pattern, "*"))
+}
+
+// isTLSTerminated returns a boolean indicating if the given listener terminates TLS, which is the default TLS mode
+func isTLSTerminated(listener gatewayv1alpha2.Listener) bool {
+	if listener.TLS == nil {
+		return false
+	}
+	return listener.TLS.Mode == nil || *listener.TLS.Mode == gatewayv1alpha2.TLSModeTerminate
+}
+
+// getListenerCertificateSecrets returns the secrets referenced by the given listener to terminate TLS.
+// Only secrets in the namespace of the Gateway can be referenced.
+func getListenerCertificateSecrets(gateway *gatewayv1alpha2.Gateway, listener gatewayv1alpha2.Listener) []types.NamespacedName {
+	var secrets []types.NamespacedName
+	for _, certificateRef := range listener.TLS.CertificateRefs {
+		if certificateRef == nil {
+			continue
+		}
+		if (certificateRef.Group != nil && *certificateRef.Group != "") || (certificateRef.Kind != nil && *certificateRef.Kind != "Secret") {
+			log.Error().Msgf("Unsupported certificate reference %s for listener %s of Gateway %s/%s, only Secrets are supported",
+				certificateRef.Name, listener.Name, gateway.Namespace, gateway.Name)
+			continue
+		}
+		if certificateRef.Namespace != nil && string(*certificateRef.Namespace) != gateway.Namespace {
+			log.Error().Msgf("Certificate %s/%s is not in the namespace of Gateway %s/%s, ignoring certificate",
+				*certificateRef.Namespace, certificateRef.Name, gateway.Namespace, gateway.Name)
+			continue
+		}
+		secrets = append(secrets, types.NamespacedName{Namespace: gateway.Namespace, Name: string(certificateRef.Name)})
+	}
+	return secrets
+}
+
+// getIngressGatewayFilterChainName returns the name of the filter chain for the given listener of the Gateway
+func getIngressGatewayFilterChainName(gateway *gatewayv1alpha2.Gateway, listener gatewayv1alpha2.Listener) string {
+	return fmt.Sprintf("%s/%s/%s", gateway.Namespace, gateway.Name, listener.Name)
+}
+
+// getIngressGatewayBackendTrafficPolicy returns the ingress traffic policy authorizing the ingress gateway to access
+// the given backend service, or nil if the service is not a backend of the ingress gateway. The ingress gateway
+// originates mTLS to the backend using the certificate specified by the MeshConfig's ingress gateway certificate spec.
+func (mc *MeshCatalog) getIngressGatewayBackendTrafficPolicy(svc service.MeshService) *trafficpolicy.IngressTrafficPolicy {
+	if !mc.isIngressGatewayEnabled() {
+		return nil
+	}
+
+	gatewayCN, _, err := ingressgateway.GetClientCertificateSpec(mc.configurator)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting the identity of the ingress gateway, ingress gateway cannot access service %s", svc)
+		return nil
+	}
+	gatewayIdentity := identity.ServiceIdentity(gatewayCN)
+
+	gatewayPolicy := mc.GetIngressGatewayTrafficPolicy()
+	if gatewayPolicy == nil {
+		return nil
+	}
+
+	for _, upstream := range gatewayPolicy.Upstreams {
+		if upstream.Service.Name != svc.Name || upstream.Service.Namespace != svc.Namespace || upstream.Service.TargetPort != svc.TargetPort {
+			continue
+		}
+
+		trafficMatch := &trafficpolicy.IngressTrafficMatch{
+			Name:        fmt.Sprintf("ingress-gateway_%s_%d_%s", svc, svc.TargetPort, upstream.Protocol),
+			Port:        uint32(svc.TargetPort),
+			ServerNames: []string{svc.ServerName()},
+		}
+
+		if upstream.Protocol == constants.ProtocolTCP {
+			trafficMatch.Protocol = constants.ProtocolTCP
+			trafficMatch.AllowedServiceIdentities = []identity.ServiceIdentity{gatewayIdentity}
+			trafficMatch.ClusterName = svc.EnvoyLocalClusterName()
+			return &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{trafficMatch},
+			}
+		}
+
+		trafficMatch.Protocol = constants.ProtocolHTTPS
+		backendCluster := service.WeightedCluster{
+			ClusterName: service.ClusterName(svc.EnvoyLocalClusterName()),
+			Weight:      constants.ClusterWeightAcceptAll,
+		}
+		return &trafficpolicy.IngressTrafficPolicy{
+			TrafficMatches: []*trafficpolicy.IngressTrafficMatch{trafficMatch},
+			HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+				{
+					Name:      fmt.Sprintf("%s_from_%s", svc, ingressgateway.ServiceName),
+					Hostnames: []string{wildcardHostname},
+					Rules: []*trafficpolicy.Rule{
+						{
+							Route: trafficpolicy.RouteWeightedClusters{
+								HTTPRouteMatch:   trafficpolicy.WildCardRouteMatch,
+								WeightedClusters: mapset.NewSet(backendCluster),
+							},
+							AllowedServiceIdentities: mapset.NewSet(gatewayIdentity),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return nil
+}
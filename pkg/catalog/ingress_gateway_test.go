@@ -0,0 +1,494 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/gatewayapi"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+var (
+	testGatewayBackend    = service.MeshService{Name: "backend", Namespace: "test", Port: 80, TargetPort: 8080, Protocol: "http"}
+	testGatewayTCPBackend = service.MeshService{Name: "db", Namespace: "test", Port: 443, TargetPort: 8443, Protocol: "tcp"}
+)
+
+func newTestGateway(listeners ...gatewayv1alpha2.Listener) *gatewayv1alpha2.Gateway {
+	return &gatewayv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "test"},
+		Spec: gatewayv1alpha2.GatewaySpec{
+			GatewayClassName: "osm",
+			Listeners:        listeners,
+		},
+	}
+}
+
+func newTestBackendRef(svc service.MeshService, weight int32) gatewayv1alpha2.BackendRef {
+	port := gatewayv1alpha2.PortNumber(svc.Port)
+	return gatewayv1alpha2.BackendRef{
+		BackendObjectReference: gatewayv1alpha2.BackendObjectReference{
+			Name: gatewayv1alpha2.ObjectName(svc.Name),
+			Port: &port,
+		},
+		Weight: &weight,
+	}
+}
+
+func TestGetIngressGatewayTrafficPolicy(t *testing.T) {
+	hostname := gatewayv1alpha2.Hostname("*.example.com")
+	pathPrefix := gatewayv1alpha2.PathMatchPathPrefix
+	pathExact := gatewayv1alpha2.PathMatchExact
+	terminate := gatewayv1alpha2.TLSModeTerminate
+	passthrough := gatewayv1alpha2.TLSModePassthrough
+	sectionName := gatewayv1alpha2.SectionName("https")
+
+	httpListener := gatewayv1alpha2.Listener{Name: "http", Port: 80, Protocol: gatewayv1alpha2.HTTPProtocolType}
+	httpsListener := gatewayv1alpha2.Listener{
+		Name:     "https",
+		Port:     443,
+		Protocol: gatewayv1alpha2.HTTPSProtocolType,
+		Hostname: &hostname,
+		TLS: &gatewayv1alpha2.GatewayTLSConfig{
+			Mode:            &terminate,
+			CertificateRefs: []*gatewayv1alpha2.SecretObjectReference{{Name: "cert"}},
+		},
+	}
+	tlsListener := gatewayv1alpha2.Listener{
+		Name:     "tls",
+		Port:     8443,
+		Protocol: gatewayv1alpha2.TLSProtocolType,
+		TLS:      &gatewayv1alpha2.GatewayTLSConfig{Mode: &passthrough},
+	}
+
+	httpRoute := &gatewayv1alpha2.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"},
+		Spec: gatewayv1alpha2.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentRef{{Name: "gateway"}},
+			},
+			Hostnames: []gatewayv1alpha2.Hostname{"foo.example.com"},
+			Rules: []gatewayv1alpha2.HTTPRouteRule{
+				{
+					Matches: []gatewayv1alpha2.HTTPRouteMatch{
+						{Path: &gatewayv1alpha2.HTTPPathMatch{Type: &pathPrefix, Value: strPtr("/api")}},
+						{Path: &gatewayv1alpha2.HTTPPathMatch{Type: &pathExact, Value: strPtr("/login")}},
+					},
+					BackendRefs: []gatewayv1alpha2.HTTPBackendRef{{BackendRef: newTestBackendRef(testGatewayBackend, 1)}},
+				},
+			},
+		},
+	}
+	tlsRoute := &gatewayv1alpha2.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"},
+		Spec: gatewayv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentRef{{Name: "gateway"}},
+			},
+			Hostnames: []gatewayv1alpha2.Hostname{"db.example.com"},
+			Rules: []gatewayv1alpha2.TLSRouteRule{
+				{BackendRefs: []gatewayv1alpha2.BackendRef{newTestBackendRef(testGatewayTCPBackend, 1)}},
+			},
+		},
+	}
+
+	backendCluster := service.WeightedCluster{ClusterName: service.ClusterName(testGatewayBackend.EnvoyClusterName()), Weight: 1}
+	tcpBackendCluster := service.WeightedCluster{ClusterName: service.ClusterName(testGatewayTCPBackend.EnvoyClusterName()), Weight: 1}
+
+	testCases := []struct {
+		name       string
+		enabled    bool
+		gateways   []*gatewayv1alpha2.Gateway
+		httpRoutes []*gatewayv1alpha2.HTTPRoute
+		tlsRoutes  []*gatewayv1alpha2.TLSRoute
+		expected   *trafficpolicy.IngressGatewayTrafficPolicy
+	}{
+		{
+			name:     "ingress gateway is disabled",
+			enabled:  false,
+			gateways: []*gatewayv1alpha2.Gateway{newTestGateway(httpListener)},
+			expected: nil,
+		},
+		{
+			name:       "HTTP and TLS routes attached to the listeners of a Gateway",
+			enabled:    true,
+			gateways:   []*gatewayv1alpha2.Gateway{newTestGateway(httpListener, tlsListener)},
+			httpRoutes: []*gatewayv1alpha2.HTTPRoute{httpRoute},
+			tlsRoutes:  []*gatewayv1alpha2.TLSRoute{tlsRoute},
+			expected: &trafficpolicy.IngressGatewayTrafficPolicy{
+				Listeners: []*trafficpolicy.IngressGatewayListener{
+					{
+						Port:         80,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{{Name: "test/gateway/http", Protocol: "http"}},
+					},
+					{
+						Port: 8443,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{
+							{
+								Name:             "test/gateway/tls/test/route",
+								Protocol:         "tcp",
+								ServerNames:      []string{"db.example.com"},
+								WeightedClusters: []service.WeightedCluster{tcpBackendCluster},
+							},
+						},
+					},
+				},
+				HTTPRouteConfigsPerPort: map[int][]*trafficpolicy.OutboundTrafficPolicy{
+					80: {
+						{
+							Name:      "foo.example.com",
+							Hostnames: []string{"foo.example.com", "foo.example.com:80"},
+							Routes: []*trafficpolicy.RouteWeightedClusters{
+								{
+									HTTPRouteMatch:   trafficpolicy.HTTPRouteMatch{Path: "/login", PathMatchType: trafficpolicy.PathMatchExact, Methods: []string{"*"}},
+									WeightedClusters: mapset.NewSet(backendCluster),
+								},
+								{
+									HTTPRouteMatch:   trafficpolicy.HTTPRouteMatch{Path: "/api", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"*"}},
+									WeightedClusters: mapset.NewSet(backendCluster),
+								},
+							},
+						},
+					},
+				},
+				Upstreams: []*trafficpolicy.IngressGatewayUpstream{
+					{Service: testGatewayBackend, Protocol: "http"},
+					{Service: testGatewayTCPBackend, Protocol: "tcp"},
+				},
+			},
+		},
+		{
+			name:     "HTTPS listener terminating TLS, route attached to another listener by section name",
+			enabled:  true,
+			gateways: []*gatewayv1alpha2.Gateway{newTestGateway(httpListener, httpsListener)},
+			httpRoutes: []*gatewayv1alpha2.HTTPRoute{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"},
+					Spec: gatewayv1alpha2.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+							ParentRefs: []gatewayv1alpha2.ParentRef{{Name: "gateway", SectionName: &sectionName}},
+						},
+						Rules: []gatewayv1alpha2.HTTPRouteRule{
+							{BackendRefs: []gatewayv1alpha2.HTTPBackendRef{{BackendRef: newTestBackendRef(testGatewayBackend, 1)}}},
+						},
+					},
+				},
+			},
+			expected: &trafficpolicy.IngressGatewayTrafficPolicy{
+				Listeners: []*trafficpolicy.IngressGatewayListener{
+					{
+						Port:         80,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{{Name: "test/gateway/http", Protocol: "http"}},
+					},
+					{
+						Port: 443,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{
+							{
+								Name:               "test/gateway/https",
+								Protocol:           "https",
+								ServerNames:        []string{"*.example.com"},
+								CertificateSecrets: []types.NamespacedName{{Namespace: "test", Name: "cert"}},
+							},
+						},
+					},
+				},
+				HTTPRouteConfigsPerPort: map[int][]*trafficpolicy.OutboundTrafficPolicy{
+					80: {},
+					443: {
+						{
+							Name:      "*.example.com",
+							Hostnames: []string{"*.example.com", "*.example.com:443"},
+							Routes: []*trafficpolicy.RouteWeightedClusters{
+								{
+									HTTPRouteMatch:   trafficpolicy.HTTPRouteMatch{Path: "/", PathMatchType: trafficpolicy.PathMatchPrefix, Methods: []string{"*"}},
+									WeightedClusters: mapset.NewSet(backendCluster),
+								},
+							},
+						},
+					},
+				},
+				Upstreams: []*trafficpolicy.IngressGatewayUpstream{
+					{Service: testGatewayBackend, Protocol: "http"},
+				},
+			},
+		},
+		{
+			name:     "routes in another namespace are not allowed by default",
+			enabled:  true,
+			gateways: []*gatewayv1alpha2.Gateway{newTestGateway(httpListener)},
+			httpRoutes: []*gatewayv1alpha2.HTTPRoute{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "other"},
+					Spec: gatewayv1alpha2.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+							ParentRefs: []gatewayv1alpha2.ParentRef{{Name: "gateway", Namespace: namespacePtr("test")}},
+						},
+						Rules: []gatewayv1alpha2.HTTPRouteRule{
+							{BackendRefs: []gatewayv1alpha2.HTTPBackendRef{{BackendRef: newTestBackendRef(testGatewayBackend, 1)}}},
+						},
+					},
+				},
+			},
+			expected: &trafficpolicy.IngressGatewayTrafficPolicy{
+				Listeners: []*trafficpolicy.IngressGatewayListener{
+					{
+						Port:         80,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{{Name: "test/gateway/http", Protocol: "http"}},
+					},
+				},
+				HTTPRouteConfigsPerPort: map[int][]*trafficpolicy.OutboundTrafficPolicy{80: {}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockGatewayAPIController := gatewayapi.NewMockController(mockCtrl)
+			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableIngressGateway: tc.enabled}).AnyTimes()
+			mockGatewayAPIController.EXPECT().ListGateways().Return(tc.gateways).AnyTimes()
+			mockGatewayAPIController.EXPECT().ListHTTPRoutes().Return(tc.httpRoutes).AnyTimes()
+			mockGatewayAPIController.EXPECT().ListTLSRoutes().Return(tc.tlsRoutes).AnyTimes()
+			mockServiceProvider.EXPECT().ListServices().Return([]service.MeshService{testGatewayBackend, testGatewayTCPBackend}).AnyTimes()
+
+			mc := &MeshCatalog{
+				configurator:         mockCfg,
+				kubeController:       mockKubeController,
+				gatewayAPIController: mockGatewayAPIController,
+				serviceProviders:     []service.Provider{mockServiceProvider},
+			}
+
+			assert.Equal(tc.expected, mc.GetIngressGatewayTrafficPolicy())
+		})
+	}
+}
+
+func TestIsRouteNamespaceAllowed(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().GetNamespace("selected").Return(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "selected", Labels: map[string]string{"ingress": "allowed"}},
+	}).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace("other").Return(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+	}).AnyTimes()
+
+	mc := &MeshCatalog{kubeController: mockKubeController}
+	gateway := newTestGateway()
+
+	fromAll := gatewayv1alpha2.NamespacesFromAll
+	fromSelector := gatewayv1alpha2.NamespacesFromSelector
+	sameNamespaceListener := gatewayv1alpha2.Listener{Name: "same"}
+	allNamespacesListener := gatewayv1alpha2.Listener{
+		Name:          "all",
+		AllowedRoutes: &gatewayv1alpha2.AllowedRoutes{Namespaces: &gatewayv1alpha2.RouteNamespaces{From: &fromAll}},
+	}
+	selectorListener := gatewayv1alpha2.Listener{
+		Name: "selector",
+		AllowedRoutes: &gatewayv1alpha2.AllowedRoutes{Namespaces: &gatewayv1alpha2.RouteNamespaces{
+			From:     &fromSelector,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"ingress": "allowed"}},
+		}},
+	}
+
+	assert.True(mc.isRouteNamespaceAllowed("test", gateway, sameNamespaceListener))
+	assert.False(mc.isRouteNamespaceAllowed("other", gateway, sameNamespaceListener))
+	assert.True(mc.isRouteNamespaceAllowed("other", gateway, allNamespacesListener))
+	assert.True(mc.isRouteNamespaceAllowed("selected", gateway, selectorListener))
+	assert.False(mc.isRouteNamespaceAllowed("other", gateway, selectorListener))
+}
+
+func TestGetIntersectingHostnames(t *testing.T) {
+	wildcard := gatewayv1alpha2.Hostname("*.example.com")
+	exact := gatewayv1alpha2.Hostname("foo.example.com")
+
+	testCases := []struct {
+		name             string
+		listenerHostname *gatewayv1alpha2.Hostname
+		routeHostnames   []gatewayv1alpha2.Hostname
+		expected         []string
+	}{
+		{
+			name:     "no hostnames",
+			expected: []string{"*"},
+		},
+		{
+			name:             "route without hostnames inherits the listener hostname",
+			listenerHostname: &wildcard,
+			expected:         []string{"*.example.com"},
+		},
+		{
+			name:             "route hostnames matching a wildcard listener hostname",
+			listenerHostname: &wildcard,
+			routeHostnames:   []gatewayv1alpha2.Hostname{"foo.example.com", "bar.example.org"},
+			expected:         []string{"foo.example.com"},
+		},
+		{
+			name:             "wildcard route hostname matching the listener hostname",
+			listenerHostname: &exact,
+			routeHostnames:   []gatewayv1alpha2.Hostname{"*.example.com"},
+			expected:         []string{"foo.example.com"},
+		},
+		{
+			name:             "no intersection",
+			listenerHostname: &exact,
+			routeHostnames:   []gatewayv1alpha2.Hostname{"bar.example.com"},
+			expected:         nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getIntersectingHostnames(tc.listenerHostname, tc.routeHostnames))
+		})
+	}
+}
+
+func TestGetIngressGatewayBackendTrafficPolicy(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	gatewayIdentity := identity.ServiceIdentity("osm-ingress-gateway.osm-system.cluster.local")
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockGatewayAPIController := gatewayapi.NewMockController(mockCtrl)
+	mockServiceProvider := service.NewMockProvider(mockCtrl)
+	mockPolicyController := policy.NewMockController(mockCtrl)
+
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableIngressGateway: true}).AnyTimes()
+	mockCfg.EXPECT().GetMeshConfig().Return(&v1alpha1.MeshConfig{
+		Spec: v1alpha1.MeshConfigSpec{
+			Certificate: v1alpha1.CertificateSpec{
+				IngressGateway: &v1alpha1.IngressGatewayCertSpec{
+					SubjectAltNames:  []string{gatewayIdentity.String()},
+					ValidityDuration: "24h",
+				},
+			},
+		},
+	}).AnyTimes()
+	mockPolicyController.EXPECT().GetIngressBackendPolicy(gomock.Any()).Return(nil).AnyTimes()
+	mockGatewayAPIController.EXPECT().ListGateways().Return([]*gatewayv1alpha2.Gateway{
+		newTestGateway(gatewayv1alpha2.Listener{Name: "http", Port: 80, Protocol: gatewayv1alpha2.HTTPProtocolType}),
+	}).AnyTimes()
+	mockGatewayAPIController.EXPECT().ListHTTPRoutes().Return([]*gatewayv1alpha2.HTTPRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"},
+			Spec: gatewayv1alpha2.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+					ParentRefs: []gatewayv1alpha2.ParentRef{{Name: "gateway"}},
+				},
+				Rules: []gatewayv1alpha2.HTTPRouteRule{
+					{BackendRefs: []gatewayv1alpha2.HTTPBackendRef{{BackendRef: newTestBackendRef(testGatewayBackend, 1)}}},
+				},
+			},
+		},
+	}).AnyTimes()
+	mockGatewayAPIController.EXPECT().ListTLSRoutes().Return(nil).AnyTimes()
+	mockServiceProvider.EXPECT().ListServices().Return([]service.MeshService{testGatewayBackend}).AnyTimes()
+
+	mc := &MeshCatalog{
+		configurator:         mockCfg,
+		policyController:     mockPolicyController,
+		gatewayAPIController: mockGatewayAPIController,
+		serviceProviders:     []service.Provider{mockServiceProvider},
+	}
+
+	// The backend of an HTTPRoute authorizes the ingress gateway
+	actual, err := mc.GetIngressTrafficPolicy(testGatewayBackend)
+	assert.Nil(err)
+	assert.NotNil(actual)
+	assert.Equal([]*trafficpolicy.IngressTrafficMatch{
+		{
+			Name:        "ingress-gateway_test/backend_8080_http",
+			Port:        8080,
+			Protocol:    "https",
+			ServerNames: []string{testGatewayBackend.ServerName()},
+		},
+	}, actual.TrafficMatches)
+	assert.Len(actual.HTTPRoutePolicies, 1)
+	assert.Equal(mapset.NewSet(gatewayIdentity), actual.HTTPRoutePolicies[0].Rules[0].AllowedServiceIdentities)
+
+	// Other services are not accessible to the ingress gateway
+	actual, err = mc.GetIngressTrafficPolicy(testGatewayTCPBackend)
+	assert.Nil(err)
+	assert.Nil(actual)
+}
+
+func TestGetIngressGatewayTrafficPolicyCached(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	msgBroker := messaging.NewBroker(stop)
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockGatewayAPIController := gatewayapi.NewMockController(mockCtrl)
+	mockServiceProvider := service.NewMockProvider(mockCtrl)
+
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableIngressGateway: true}).AnyTimes()
+	mockServiceProvider.EXPECT().ListServices().Return(nil).AnyTimes()
+	mockGatewayAPIController.EXPECT().ListHTTPRoutes().Return(nil).AnyTimes()
+	mockGatewayAPIController.EXPECT().ListTLSRoutes().Return(nil).AnyTimes()
+	// The policy is built once per event updating the proxies
+	mockGatewayAPIController.EXPECT().ListGateways().Return(nil).Times(2)
+
+	mc := &MeshCatalog{
+		configurator:         mockCfg,
+		gatewayAPIController: mockGatewayAPIController,
+		serviceProviders:     []service.Provider{mockServiceProvider},
+		msgBroker:            msgBroker,
+	}
+
+	policy := mc.GetIngressGatewayTrafficPolicy()
+	assert.NotNil(policy)
+	assert.Same(policy, mc.GetIngressGatewayTrafficPolicy())
+
+	eventCount := msgBroker.GetTotalQProxyEventCount()
+	msgBroker.GetQueue().Add(events.PubSubMessage{Kind: announcements.EndpointUpdated})
+	assert.Eventually(func() bool {
+		return msgBroker.GetTotalQProxyEventCount() > eventCount
+	}, 5*time.Second, 10*time.Millisecond)
+
+	rebuilt := mc.GetIngressGatewayTrafficPolicy()
+	assert.NotSame(policy, rebuilt)
+	assert.Same(rebuilt, mc.GetIngressGatewayTrafficPolicy())
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func namespacePtr(ns string) *gatewayv1alpha2.Namespace {
+	namespace := gatewayv1alpha2.Namespace(ns)
+	return &namespace
+}
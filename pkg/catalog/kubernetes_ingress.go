@@ -0,0 +1,307 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/pkg/errors"
+	networkingV1 "k8s.io/api/networking/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+// ingressRoute is the type used to represent an HTTP route of a Kubernetes Ingress rule
+type ingressRoute struct {
+	host     string
+	path     string
+	pathType networkingV1.PathType
+}
+
+// getKubernetesIngressTrafficPolicy returns the ingress traffic policy for the given mesh service derived from the
+// networking.k8s.io/v1 Ingress resources whose IngressClass is annotated to be monitored by OSM. The backend protocol
+// and the allowed sources are configured using annotations on the IngressClass.
+func (mc *MeshCatalog) getKubernetesIngressTrafficPolicy(svc service.MeshService) *trafficpolicy.IngressTrafficPolicy {
+	if mc.ingressMonitor == nil {
+		return nil
+	}
+
+	ingresses := mc.ingressMonitor.GetIngressNetworkingV1(svc)
+	if len(ingresses) == 0 {
+		return nil
+	}
+
+	portName := mc.getServicePortName(svc)
+
+	var trafficMatches []*trafficpolicy.IngressTrafficMatch
+	httpRoutePolicies := make(map[string]*trafficpolicy.InboundTrafficPolicy) // hostname -> policy
+	for _, ingress := range ingresses {
+		ingressClass := mc.ingressMonitor.GetIngressClass(ingress)
+		if ingressClass == nil {
+			continue
+		}
+
+		routes := getIngressRoutes(ingress, svc.Name, svc.Port, portName)
+		if len(routes) == 0 {
+			continue
+		}
+
+		protocol := strings.ToLower(ingressClass.Annotations[constants.IngressBackendProtocolAnnotation])
+		var sourceIdentities []identity.ServiceIdentity
+		switch protocol {
+		case constants.ProtocolHTTP:
+			// The identity cannot be verified for HTTP traffic, HTTP based ingress can only restrict
+			// downstreams based on their endpoint's IP address.
+			sourceIdentities = []identity.ServiceIdentity{identity.WildcardServiceIdentity}
+
+		case constants.ProtocolHTTPS:
+			certSpec := mc.configurator.GetMeshConfig().Spec.Certificate.IngressGateway
+			if certSpec == nil || len(certSpec.SubjectAltNames) == 0 {
+				log.Error().Msgf("Ingress %s/%s of IngressClass %s requires the ingress gateway certificate to be configured in the MeshConfig, ignoring it",
+					ingress.Namespace, ingress.Name, ingressClass.Name)
+				continue
+			}
+			for _, san := range certSpec.SubjectAltNames {
+				sourceIdentities = append(sourceIdentities, identity.ServiceIdentity(san))
+			}
+
+		default:
+			log.Error().Msgf("Invalid value '%s' for annotation %s on IngressClass %s, supported values are 'http' and 'https'",
+				ingressClass.Annotations[constants.IngressBackendProtocolAnnotation], constants.IngressBackendProtocolAnnotation, ingressClass.Name)
+			continue
+		}
+
+		sourceIPRanges, err := mc.getIngressClassSourceIPRanges(ingressClass, protocol)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error getting the sources of Ingress %s/%s, ignoring it", ingress.Namespace, ingress.Name)
+			continue
+		}
+
+		trafficMatch := &trafficpolicy.IngressTrafficMatch{
+			Name:           fmt.Sprintf("ingress_%s_%d_%s", svc, svc.TargetPort, protocol),
+			Port:           uint32(svc.TargetPort),
+			Protocol:       protocol,
+			SourceIPRanges: sourceIPRanges,
+		}
+		if trafficMatches, err = mergeIngressTrafficMatch(trafficMatches, trafficMatch); err != nil {
+			log.Error().Err(err).Msgf("Error applying Ingress %s/%s to service %s, ignoring it", ingress.Namespace, ingress.Name, svc)
+			continue
+		}
+
+		backendCluster := service.WeightedCluster{
+			ClusterName: service.ClusterName(svc.EnvoyLocalClusterName()),
+			Weight:      constants.ClusterWeightAcceptAll,
+		}
+		for _, route := range routes {
+			hostname := route.host
+			if hostname == "" {
+				hostname = wildcardHostname
+			}
+
+			policy, ok := httpRoutePolicies[hostname]
+			if !ok {
+				policy = &trafficpolicy.InboundTrafficPolicy{
+					Name:      fmt.Sprintf("%s_from_ingress_%s", svc, hostname),
+					Hostnames: []string{hostname},
+				}
+				httpRoutePolicies[hostname] = policy
+			}
+
+			allowedIdentities := mapset.NewSet()
+			for _, sourceIdentity := range sourceIdentities {
+				allowedIdentities.Add(sourceIdentity)
+			}
+			policy.Rules = trafficpolicy.MergeRules(policy.Rules, []*trafficpolicy.Rule{
+				{
+					Route: trafficpolicy.RouteWeightedClusters{
+						HTTPRouteMatch:   getIngressHTTPRouteMatch(route),
+						WeightedClusters: mapset.NewSet(backendCluster),
+					},
+					AllowedServiceIdentities: allowedIdentities,
+				},
+			})
+		}
+	}
+
+	if len(trafficMatches) == 0 {
+		return nil
+	}
+
+	var hostnames []string
+	for hostname := range httpRoutePolicies {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	ingressPolicy := &trafficpolicy.IngressTrafficPolicy{
+		TrafficMatches: trafficMatches,
+	}
+	for _, hostname := range hostnames {
+		ingressPolicy.HTTPRoutePolicies = append(ingressPolicy.HTTPRoutePolicies, httpRoutePolicies[hostname])
+	}
+
+	return ingressPolicy
+}
+
+// getServicePortName returns the name of the port of the given mesh service, used by Ingress backends
+// referencing the service port by name
+func (mc *MeshCatalog) getServicePortName(svc service.MeshService) string {
+	k8sSvc := mc.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return ""
+	}
+
+	for _, port := range k8sSvc.Spec.Ports {
+		if uint16(port.Port) == svc.Port {
+			return port.Name
+		}
+	}
+	return ""
+}
+
+// getIngressClassSourceIPRanges returns the source IP ranges allowed by the given IngressClass for the given backend
+// protocol. Nil is returned when the IngressClass does not restrict the sources, which is only allowed for HTTPS since
+// the identity of the downstream cannot be verified for HTTP traffic.
+func (mc *MeshCatalog) getIngressClassSourceIPRanges(ingressClass *networkingV1.IngressClass, protocol string) ([]string, error) {
+	sourceService, ok := ingressClass.Annotations[constants.IngressSourceServiceAnnotation]
+	if !ok {
+		if protocol == constants.ProtocolHTTP {
+			return nil, errors.Errorf("Annotation %s must be specified on IngressClass %s with backend protocol %s to restrict the sources",
+				constants.IngressSourceServiceAnnotation, ingressClass.Name, protocol)
+		}
+		return nil, nil
+	}
+
+	namespacedName := strings.Split(sourceService, "/")
+	if len(namespacedName) != 2 || namespacedName[0] == "" || namespacedName[1] == "" {
+		return nil, errors.Errorf("Invalid value '%s' for annotation %s on IngressClass %s, expected '<namespace>/<name>'",
+			sourceService, constants.IngressSourceServiceAnnotation, ingressClass.Name)
+	}
+
+	sourceMeshSvc := service.MeshService{Namespace: namespacedName[0], Name: namespacedName[1]}
+	endpoints := mc.listEndpointsForService(sourceMeshSvc)
+	if len(endpoints) == 0 {
+		return nil, errors.Errorf("Could not list endpoints of the source service %s specified on IngressClass %s", sourceService, ingressClass.Name)
+	}
+
+	var sourceIPRanges []string
+	sourceIPSet := mapset.NewSet() // Used to avoid duplicate IP ranges
+	for _, ep := range endpoints {
+		sourceCIDR := utils.GetCIDRForIP(ep.IP)
+		if sourceIPSet.Add(sourceCIDR) {
+			sourceIPRanges = append(sourceIPRanges, sourceCIDR)
+		}
+	}
+	sort.Strings(sourceIPRanges)
+
+	return sourceIPRanges, nil
+}
+
+// mergeIngressTrafficMatch merges the given traffic match into the given traffic matches. Traffic matches with the
+// same name are merged such that the sources allowed by either are allowed. An error is returned if the traffic
+// match conflicts with an existing traffic match on the same port.
+func mergeIngressTrafficMatch(trafficMatches []*trafficpolicy.IngressTrafficMatch, latest *trafficpolicy.IngressTrafficMatch) ([]*trafficpolicy.IngressTrafficMatch, error) {
+	for _, existing := range trafficMatches {
+		if existing.Port != latest.Port {
+			continue
+		}
+		if existing.Protocol != latest.Protocol {
+			return trafficMatches, errors.Errorf("Ingress backend protocol %s on port %d conflicts with protocol %s",
+				latest.Protocol, latest.Port, existing.Protocol)
+		}
+
+		if len(existing.SourceIPRanges) == 0 || len(latest.SourceIPRanges) == 0 {
+			// Sources are not restricted by one of the traffic matches
+			existing.SourceIPRanges = nil
+			return trafficMatches, nil
+		}
+		sourceIPSet := mapset.NewSet()
+		for _, ipRange := range existing.SourceIPRanges {
+			sourceIPSet.Add(ipRange)
+		}
+		for _, ipRange := range latest.SourceIPRanges {
+			if sourceIPSet.Add(ipRange) {
+				existing.SourceIPRanges = append(existing.SourceIPRanges, ipRange)
+			}
+		}
+		sort.Strings(existing.SourceIPRanges)
+		return trafficMatches, nil
+	}
+
+	return append(trafficMatches, latest), nil
+}
+
+// getIngressRoutes returns the HTTP routes of the given Ingress whose backend is the service with the given name,
+// referenced by the given port number or name
+func getIngressRoutes(ingress *networkingV1.Ingress, serviceName string, port uint16, portName string) []ingressRoute {
+	isBackend := func(backend networkingV1.IngressBackend) bool {
+		if backend.Service == nil || backend.Service.Name != serviceName {
+			return false
+		}
+		if backend.Service.Port.Name != "" {
+			return backend.Service.Port.Name == portName
+		}
+		return backend.Service.Port.Number == int32(port)
+	}
+
+	var routes []ingressRoute
+	if ingress.Spec.DefaultBackend != nil && isBackend(*ingress.Spec.DefaultBackend) {
+		prefix := networkingV1.PathTypePrefix
+		routes = append(routes, ingressRoute{path: "/", pathType: prefix})
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if !isBackend(path.Backend) {
+				continue
+			}
+			route := ingressRoute{
+				host:     rule.Host,
+				path:     path.Path,
+				pathType: networkingV1.PathTypeImplementationSpecific,
+			}
+			if path.PathType != nil {
+				route.pathType = *path.PathType
+			}
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
+}
+
+// getIngressHTTPRouteMatch returns the HTTP route match for the given Ingress route
+func getIngressHTTPRouteMatch(route ingressRoute) trafficpolicy.HTTPRouteMatch {
+	routeMatch := trafficpolicy.HTTPRouteMatch{
+		Path:          route.path,
+		PathMatchType: trafficpolicy.PathMatchPrefix,
+		Methods:       []string{constants.WildcardHTTPMethod},
+	}
+	if route.path == "" {
+		routeMatch.Path = "/"
+	}
+
+	switch route.pathType {
+	case networkingV1.PathTypeExact:
+		routeMatch.PathMatchType = trafficpolicy.PathMatchExact
+
+	case networkingV1.PathTypePrefix:
+		// Prefix paths are matched element-wise, ex. '/foo' matches '/foo' and '/foo/bar' but not '/foobar'
+		trimmedPath := strings.TrimRight(routeMatch.Path, "/")
+		if trimmedPath != "" {
+			routeMatch.Path = fmt.Sprintf("%s(/.*)?", regexp.QuoteMeta(trimmedPath))
+			routeMatch.PathMatchType = trafficpolicy.PathMatchRegex
+		}
+	}
+
+	return routeMatch
+}
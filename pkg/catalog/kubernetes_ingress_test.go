@@ -0,0 +1,305 @@
+package catalog
+
+import (
+	"net"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/ingress"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetKubernetesIngressTrafficPolicy(t *testing.T) {
+	meshSvc := service.MeshService{Name: "foo", Namespace: "test", Port: 80, TargetPort: 8080, Protocol: "http"}
+	k8sSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "test"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "web", Port: 80}},
+		},
+	}
+	ingressControllerSvc := service.MeshService{Name: "ingress-nginx", Namespace: "ingress-nginx"}
+	ingressControllerEndpoints := []endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.10"), Port: 80},
+		{IP: net.ParseIP("10.0.0.11"), Port: 80},
+	}
+	backendCluster := service.WeightedCluster{
+		ClusterName: service.ClusterName(meshSvc.EnvoyLocalClusterName()),
+		Weight:      constants.ClusterWeightAcceptAll,
+	}
+	prefix := networkingV1.PathTypePrefix
+	exact := networkingV1.PathTypeExact
+
+	newIngressClass := func(annotations map[string]string) *networkingV1.IngressClass {
+		return &networkingV1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Annotations: annotations},
+		}
+	}
+	newIngress := func(tls bool, rules ...networkingV1.IngressRule) *networkingV1.Ingress {
+		ing := &networkingV1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "test"},
+			Spec:       networkingV1.IngressSpec{Rules: rules},
+		}
+		if tls {
+			ing.Spec.TLS = []networkingV1.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "foo-cert"}}
+		}
+		return ing
+	}
+	newRule := func(host string, paths ...networkingV1.HTTPIngressPath) networkingV1.IngressRule {
+		return networkingV1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingV1.IngressRuleValue{
+				HTTP: &networkingV1.HTTPIngressRuleValue{Paths: paths},
+			},
+		}
+	}
+	newPath := func(path string, pathType *networkingV1.PathType, backend string, port networkingV1.ServiceBackendPort) networkingV1.HTTPIngressPath {
+		return networkingV1.HTTPIngressPath{
+			Path:     path,
+			PathType: pathType,
+			Backend: networkingV1.IngressBackend{
+				Service: &networkingV1.IngressServiceBackend{Name: backend, Port: port},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		ingresses      []*networkingV1.Ingress
+		ingressClass   *networkingV1.IngressClass
+		certSpec       *configv1alpha1.IngressGatewayCertSpec
+		expectedPolicy *trafficpolicy.IngressTrafficPolicy
+	}{
+		{
+			name:           "no Ingress resources",
+			ingresses:      nil,
+			ingressClass:   newIngressClass(map[string]string{constants.IngressBackendProtocolAnnotation: "http"}),
+			expectedPolicy: nil,
+		},
+		{
+			name: "HTTP backend referenced by port number and name",
+			ingresses: []*networkingV1.Ingress{
+				newIngress(false,
+					newRule("foo.com",
+						newPath("/api/", &prefix, "foo", networkingV1.ServiceBackendPort{Number: 80}),
+						newPath("/login", &exact, "foo", networkingV1.ServiceBackendPort{Name: "web"}),
+						// Backends of other services and ports are ignored
+						newPath("/other", &prefix, "bar", networkingV1.ServiceBackendPort{Number: 80}),
+						newPath("/other", &prefix, "foo", networkingV1.ServiceBackendPort{Number: 90}),
+					),
+					newRule("", newPath("", nil, "foo", networkingV1.ServiceBackendPort{Number: 80})),
+				),
+			},
+			ingressClass: newIngressClass(map[string]string{
+				constants.IngressBackendProtocolAnnotation: "http",
+				constants.IngressSourceServiceAnnotation:   "ingress-nginx/ingress-nginx",
+			}),
+			expectedPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:           "ingress_test/foo_8080_http",
+						Port:           8080,
+						Protocol:       "http",
+						SourceIPRanges: []string{"10.0.0.10/32", "10.0.0.11/32"},
+					},
+				},
+				HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+					{
+						Name:      "test/foo_from_ingress_*",
+						Hostnames: []string{"*"},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+										Path:          "/",
+										PathMatchType: trafficpolicy.PathMatchPrefix,
+										Methods:       []string{constants.WildcardHTTPMethod},
+									},
+									WeightedClusters: mapset.NewSet(backendCluster),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+						},
+					},
+					{
+						Name:      "test/foo_from_ingress_foo.com",
+						Hostnames: []string{"foo.com"},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+										Path:          "/api(/.*)?",
+										PathMatchType: trafficpolicy.PathMatchRegex,
+										Methods:       []string{constants.WildcardHTTPMethod},
+									},
+									WeightedClusters: mapset.NewSet(backendCluster),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+										Path:          "/login",
+										PathMatchType: trafficpolicy.PathMatchExact,
+										Methods:       []string{constants.WildcardHTTPMethod},
+									},
+									WeightedClusters: mapset.NewSet(backendCluster),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "HTTPS backend authenticated with the ingress gateway certificate",
+			ingresses: []*networkingV1.Ingress{
+				newIngress(true, newRule("foo.com", newPath("/", &prefix, "foo", networkingV1.ServiceBackendPort{Number: 80}))),
+			},
+			ingressClass: newIngressClass(map[string]string{constants.IngressBackendProtocolAnnotation: "https"}),
+			certSpec:     &configv1alpha1.IngressGatewayCertSpec{SubjectAltNames: []string{"ingress-nginx.ingress-nginx.cluster.local"}},
+			expectedPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:     "ingress_test/foo_8080_https",
+						Port:     8080,
+						Protocol: "https",
+					},
+				},
+				HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+					{
+						Name:      "test/foo_from_ingress_foo.com",
+						Hostnames: []string{"foo.com"},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+										Path:          "/",
+										PathMatchType: trafficpolicy.PathMatchPrefix,
+										Methods:       []string{constants.WildcardHTTPMethod},
+									},
+									WeightedClusters: mapset.NewSet(backendCluster),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.ServiceIdentity("ingress-nginx.ingress-nginx.cluster.local")),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "HTTPS backend without an ingress gateway certificate",
+			ingresses: []*networkingV1.Ingress{
+				newIngress(true, newRule("foo.com", newPath("/", &prefix, "foo", networkingV1.ServiceBackendPort{Number: 80}))),
+			},
+			ingressClass:   newIngressClass(map[string]string{constants.IngressBackendProtocolAnnotation: "https"}),
+			expectedPolicy: nil,
+		},
+		{
+			name: "invalid backend protocol",
+			ingresses: []*networkingV1.Ingress{
+				newIngress(false, newRule("foo.com", newPath("/", &prefix, "foo", networkingV1.ServiceBackendPort{Number: 80}))),
+			},
+			ingressClass:   newIngressClass(map[string]string{constants.IngressBackendProtocolAnnotation: "grpc"}),
+			expectedPolicy: nil,
+		},
+		{
+			name: "HTTP backend without a source service",
+			ingresses: []*networkingV1.Ingress{
+				newIngress(false, newRule("foo.com", newPath("/", &prefix, "foo", networkingV1.ServiceBackendPort{Number: 80}))),
+			},
+			// The identity of the downstream cannot be verified for HTTP, so the sources must be restricted
+			ingressClass:   newIngressClass(map[string]string{constants.IngressBackendProtocolAnnotation: "http"}),
+			expectedPolicy: nil,
+		},
+		{
+			name: "invalid source service",
+			ingresses: []*networkingV1.Ingress{
+				newIngress(false, newRule("foo.com", newPath("/", &prefix, "foo", networkingV1.ServiceBackendPort{Number: 80}))),
+			},
+			ingressClass: newIngressClass(map[string]string{
+				constants.IngressBackendProtocolAnnotation: "http",
+				constants.IngressSourceServiceAnnotation:   "ingress-nginx",
+			}),
+			expectedPolicy: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockEndpointsProvider := endpoint.NewMockProvider(mockCtrl)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
+
+			meshCatalog := &MeshCatalog{
+				endpointsProviders: []endpoint.Provider{mockEndpointsProvider},
+				configurator:       mockCfg,
+				kubeController:     mockKubeController,
+				ingressMonitor:     mockIngressMonitor,
+			}
+
+			mockIngressMonitor.EXPECT().GetIngressNetworkingV1(meshSvc).Return(tc.ingresses).Times(1)
+			mockIngressMonitor.EXPECT().GetIngressClass(gomock.Any()).Return(tc.ingressClass).AnyTimes()
+			mockKubeController.EXPECT().GetService(meshSvc).Return(k8sSvc).AnyTimes()
+			mockEndpointsProvider.EXPECT().ListEndpointsForService(ingressControllerSvc).Return(ingressControllerEndpoints).AnyTimes()
+			mockEndpointsProvider.EXPECT().GetID().Return("mock").AnyTimes()
+			mockCfg.EXPECT().GetMeshConfig().Return(&configv1alpha1.MeshConfig{
+				Spec: configv1alpha1.MeshConfigSpec{
+					Certificate: configv1alpha1.CertificateSpec{IngressGateway: tc.certSpec},
+				},
+			}).AnyTimes()
+
+			assert.Equal(tc.expectedPolicy, meshCatalog.getKubernetesIngressTrafficPolicy(meshSvc))
+		})
+	}
+}
+
+func TestMergeIngressTrafficMatch(t *testing.T) {
+	assert := tassert.New(t)
+
+	trafficMatches, err := mergeIngressTrafficMatch(nil, &trafficpolicy.IngressTrafficMatch{
+		Name: "http", Port: 8080, Protocol: "http", SourceIPRanges: []string{"10.0.0.10/32"},
+	})
+	assert.Nil(err)
+	assert.Len(trafficMatches, 1)
+
+	// Sources of traffic matches with the same name are merged
+	trafficMatches, err = mergeIngressTrafficMatch(trafficMatches, &trafficpolicy.IngressTrafficMatch{
+		Name: "http", Port: 8080, Protocol: "http", SourceIPRanges: []string{"10.0.0.11/32", "10.0.0.10/32"},
+	})
+	assert.Nil(err)
+	assert.Len(trafficMatches, 1)
+	assert.Equal([]string{"10.0.0.10/32", "10.0.0.11/32"}, trafficMatches[0].SourceIPRanges)
+
+	// Sources are not restricted if one of the traffic matches does not restrict them
+	trafficMatches, err = mergeIngressTrafficMatch(trafficMatches, &trafficpolicy.IngressTrafficMatch{
+		Name: "http", Port: 8080, Protocol: "http",
+	})
+	assert.Nil(err)
+	assert.Nil(trafficMatches[0].SourceIPRanges)
+
+	// Different protocols on the same port conflict
+	trafficMatches, err = mergeIngressTrafficMatch(trafficMatches, &trafficpolicy.IngressTrafficMatch{
+		Name: "https", Port: 8080, Protocol: "https",
+	})
+	assert.NotNil(err)
+	assert.Len(trafficMatches, 1)
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// getLoadBalancerConfig returns the load balancer configuration for the given upstream service derived
+// from the annotations on its corresponding k8s service. A nil configuration is returned when round
+// robin load balancing is to be used.
+func (mc *MeshCatalog) getLoadBalancerConfig(meshSvc service.MeshService) *trafficpolicy.LoadBalancerConfig {
+	svc := mc.kubeController.GetService(meshSvc)
+	if svc == nil {
+		return nil
+	}
+
+	lbConfig, err := parseLoadBalancerConfig(svc.Annotations)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrInvalidLoadBalancerConfig)).
+			Msgf("Invalid load balancer configuration for service %s, using round robin load balancing", meshSvc)
+		return nil
+	}
+
+	return lbConfig
+}
+
+// isRedisShardingEnabled returns a boolean indicating if the given upstream service opts into the key based sharding
+// of its Redis traffic with the annotation on its corresponding k8s service
+func (mc *MeshCatalog) isRedisShardingEnabled(meshSvc service.MeshService) bool {
+	svc := mc.kubeController.GetService(meshSvc)
+	if svc == nil {
+		return false
+	}
+
+	value, ok := svc.Annotations[constants.RedisShardingAnnotation]
+	if !ok {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Error().Err(err).Msgf("Invalid value %q for annotation %s on service %s, proxying its Redis traffic as TCP", value, constants.RedisShardingAnnotation, meshSvc)
+		return false
+	}
+	return enabled
+}
+
+// parseLoadBalancerConfig parses the load balancer configuration from the given annotations
+func parseLoadBalancerConfig(annotations map[string]string) (*trafficpolicy.LoadBalancerConfig, error) {
+	algorithm := trafficpolicy.LoadBalancerAlgorithm(annotations[constants.LoadBalancerAnnotation])
+	hashKey, hashKeySpecified := annotations[constants.HashKeyAnnotation]
+	cookieTTL, cookieTTLSpecified := annotations[constants.HashCookieTTLAnnotation]
+
+	switch algorithm {
+	case "", trafficpolicy.RoundRobinLoadBalancer:
+		if hashKeySpecified || cookieTTLSpecified {
+			return nil, errors.Errorf("annotations %s and %s require a consistent-hash load balancer", constants.HashKeyAnnotation, constants.HashCookieTTLAnnotation)
+		}
+		return nil, nil
+
+	case trafficpolicy.RingHashLoadBalancer, trafficpolicy.MaglevLoadBalancer:
+		if !hashKeySpecified {
+			return nil, errors.Errorf("annotation %s must be specified for load balancer %s", constants.HashKeyAnnotation, algorithm)
+		}
+
+	default:
+		return nil, errors.Errorf("unsupported load balancer %q", algorithm)
+	}
+
+	hashPolicy, err := parseHashKey(hashKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if cookieTTLSpecified {
+		if hashPolicy.Kind != trafficpolicy.HashKeyCookie {
+			return nil, errors.Errorf("annotation %s is only applicable to a cookie hash key", constants.HashCookieTTLAnnotation)
+		}
+		ttl, err := time.ParseDuration(cookieTTL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cookie TTL %q", cookieTTL)
+		}
+		if ttl <= 0 {
+			return nil, errors.Errorf("cookie TTL %q must be positive", cookieTTL)
+		}
+		hashPolicy.CookieTTL = ttl
+	}
+
+	return &trafficpolicy.LoadBalancerConfig{
+		Algorithm:  algorithm,
+		HashPolicy: hashPolicy,
+	}, nil
+}
+
+// parseHashKey parses a hash key of the form 'header:<name>', 'cookie:<name>' or 'source-ip'
+func parseHashKey(hashKey string) (*trafficpolicy.HashPolicy, error) {
+	if trafficpolicy.HashKeyKind(hashKey) == trafficpolicy.HashKeySourceIP {
+		return &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeySourceIP}, nil
+	}
+
+	parts := strings.SplitN(hashKey, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+		return nil, errors.Errorf("invalid hash key %q, must be one of 'header:<name>', 'cookie:<name>' or 'source-ip'", hashKey)
+	}
+
+	switch trafficpolicy.HashKeyKind(parts[0]) {
+	case trafficpolicy.HashKeyHeader, trafficpolicy.HashKeyCookie:
+		return &trafficpolicy.HashPolicy{
+			Kind: trafficpolicy.HashKeyKind(parts[0]),
+			Name: strings.TrimSpace(parts[1]),
+		}, nil
+
+	default:
+		return nil, errors.Errorf("invalid hash key kind %q, must be one of 'header', 'cookie' or 'source-ip'", parts[0])
+	}
+}
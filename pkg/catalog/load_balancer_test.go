@@ -0,0 +1,194 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestParseLoadBalancerConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    *trafficpolicy.LoadBalancerConfig
+		expectError bool
+	}{
+		{
+			name:        "no annotations implies round robin",
+			annotations: nil,
+			expected:    nil,
+			expectError: false,
+		},
+		{
+			name: "explicit round robin",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "round-robin",
+			},
+			expected:    nil,
+			expectError: false,
+		},
+		{
+			name: "ring hash with header hash key",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "ring-hash",
+				constants.HashKeyAnnotation:      "header:x-user-id",
+			},
+			expected: &trafficpolicy.LoadBalancerConfig{
+				Algorithm: trafficpolicy.RingHashLoadBalancer,
+				HashPolicy: &trafficpolicy.HashPolicy{
+					Kind: trafficpolicy.HashKeyHeader,
+					Name: "x-user-id",
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "maglev with cookie hash key and TTL",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation:  "maglev",
+				constants.HashKeyAnnotation:       "cookie:session",
+				constants.HashCookieTTLAnnotation: "1h",
+			},
+			expected: &trafficpolicy.LoadBalancerConfig{
+				Algorithm: trafficpolicy.MaglevLoadBalancer,
+				HashPolicy: &trafficpolicy.HashPolicy{
+					Kind:      trafficpolicy.HashKeyCookie,
+					Name:      "session",
+					CookieTTL: time.Hour,
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "ring hash with source IP hash key",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "ring-hash",
+				constants.HashKeyAnnotation:      "source-ip",
+			},
+			expected: &trafficpolicy.LoadBalancerConfig{
+				Algorithm: trafficpolicy.RingHashLoadBalancer,
+				HashPolicy: &trafficpolicy.HashPolicy{
+					Kind: trafficpolicy.HashKeySourceIP,
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "unsupported load balancer",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "least-request",
+			},
+			expectError: true,
+		},
+		{
+			name: "consistent-hash load balancer without hash key",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "ring-hash",
+			},
+			expectError: true,
+		},
+		{
+			name: "hash key without consistent-hash load balancer",
+			annotations: map[string]string{
+				constants.HashKeyAnnotation: "source-ip",
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid hash key kind",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "ring-hash",
+				constants.HashKeyAnnotation:      "query:id",
+			},
+			expectError: true,
+		},
+		{
+			name: "hash key without name",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "ring-hash",
+				constants.HashKeyAnnotation:      "header:",
+			},
+			expectError: true,
+		},
+		{
+			name: "cookie TTL with header hash key",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation:  "ring-hash",
+				constants.HashKeyAnnotation:       "header:x-user-id",
+				constants.HashCookieTTLAnnotation: "1h",
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid cookie TTL",
+			annotations: map[string]string{
+				constants.LoadBalancerAnnotation:  "ring-hash",
+				constants.HashKeyAnnotation:       "cookie:session",
+				constants.HashCookieTTLAnnotation: "forever",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := parseLoadBalancerConfig(tc.annotations)
+			assert.Equal(tc.expectError, err != nil)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
+
+func TestGetLoadBalancerConfig(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mc := MeshCatalog{
+		kubeController: mockKubeController,
+	}
+
+	svc := service.MeshService{Name: "s1", Namespace: "ns1", Port: 80, TargetPort: 8080}
+	invalidSvc := service.MeshService{Name: "s2", Namespace: "ns1", Port: 80, TargetPort: 8080}
+	unknownSvc := service.MeshService{Name: "s3", Namespace: "ns1", Port: 80, TargetPort: 8080}
+
+	mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+			Annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "maglev",
+				constants.HashKeyAnnotation:      "source-ip",
+			},
+		},
+	}).Times(1)
+	mockKubeController.EXPECT().GetService(invalidSvc).Return(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      invalidSvc.Name,
+			Namespace: invalidSvc.Namespace,
+			Annotations: map[string]string{
+				constants.LoadBalancerAnnotation: "maglev",
+			},
+		},
+	}).Times(1)
+	mockKubeController.EXPECT().GetService(unknownSvc).Return(nil).Times(1)
+
+	assert.Equal(&trafficpolicy.LoadBalancerConfig{
+		Algorithm:  trafficpolicy.MaglevLoadBalancer,
+		HashPolicy: &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeySourceIP},
+	}, mc.getLoadBalancerConfig(svc))
+	assert.Nil(mc.getLoadBalancerConfig(invalidSvc))
+	assert.Nil(mc.getLoadBalancerConfig(unknownSvc))
+}
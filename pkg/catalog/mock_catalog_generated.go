@@ -38,6 +38,20 @@ func (m *MockMeshCataloger) EXPECT() *MockMeshCatalogerMockRecorder {
 	return m.recorder
 }
 
+// GetEgressGatewayTrafficPolicy mocks base method.
+func (m *MockMeshCataloger) GetEgressGatewayTrafficPolicy() *trafficpolicy.EgressGatewayTrafficPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressGatewayTrafficPolicy")
+	ret0, _ := ret[0].(*trafficpolicy.EgressGatewayTrafficPolicy)
+	return ret0
+}
+
+// GetEgressGatewayTrafficPolicy indicates an expected call of GetEgressGatewayTrafficPolicy.
+func (mr *MockMeshCatalogerMockRecorder) GetEgressGatewayTrafficPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressGatewayTrafficPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetEgressGatewayTrafficPolicy))
+}
+
 // GetEgressTrafficPolicy mocks base method.
 func (m *MockMeshCataloger) GetEgressTrafficPolicy(arg0 identity.ServiceIdentity) (*trafficpolicy.EgressTrafficPolicy, error) {
 	m.ctrl.T.Helper()
@@ -67,6 +81,34 @@ func (mr *MockMeshCatalogerMockRecorder) GetInboundMeshTrafficPolicy(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundMeshTrafficPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetInboundMeshTrafficPolicy), arg0, arg1)
 }
 
+// GetInboundServiceProtocol mocks base method.
+func (m *MockMeshCataloger) GetInboundServiceProtocol(arg0 identity.ServiceIdentity, arg1 service.MeshService) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundServiceProtocol", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetInboundServiceProtocol indicates an expected call of GetInboundServiceProtocol.
+func (mr *MockMeshCatalogerMockRecorder) GetInboundServiceProtocol(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundServiceProtocol", reflect.TypeOf((*MockMeshCataloger)(nil).GetInboundServiceProtocol), arg0, arg1)
+}
+
+// GetIngressGatewayTrafficPolicy mocks base method.
+func (m *MockMeshCataloger) GetIngressGatewayTrafficPolicy() *trafficpolicy.IngressGatewayTrafficPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressGatewayTrafficPolicy")
+	ret0, _ := ret[0].(*trafficpolicy.IngressGatewayTrafficPolicy)
+	return ret0
+}
+
+// GetIngressGatewayTrafficPolicy indicates an expected call of GetIngressGatewayTrafficPolicy.
+func (mr *MockMeshCatalogerMockRecorder) GetIngressGatewayTrafficPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressGatewayTrafficPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetIngressGatewayTrafficPolicy))
+}
+
 // GetIngressTrafficPolicy mocks base method.
 func (m *MockMeshCataloger) GetIngressTrafficPolicy(arg0 service.MeshService) (*trafficpolicy.IngressTrafficPolicy, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +138,20 @@ func (mr *MockMeshCatalogerMockRecorder) GetKubeController() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKubeController", reflect.TypeOf((*MockMeshCataloger)(nil).GetKubeController))
 }
 
+// GetMulticlusterGatewayTrafficPolicy mocks base method.
+func (m *MockMeshCataloger) GetMulticlusterGatewayTrafficPolicy() *trafficpolicy.MulticlusterGatewayTrafficPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMulticlusterGatewayTrafficPolicy")
+	ret0, _ := ret[0].(*trafficpolicy.MulticlusterGatewayTrafficPolicy)
+	return ret0
+}
+
+// GetMulticlusterGatewayTrafficPolicy indicates an expected call of GetMulticlusterGatewayTrafficPolicy.
+func (mr *MockMeshCatalogerMockRecorder) GetMulticlusterGatewayTrafficPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMulticlusterGatewayTrafficPolicy", reflect.TypeOf((*MockMeshCataloger)(nil).GetMulticlusterGatewayTrafficPolicy))
+}
+
 // GetOutboundMeshTrafficPolicy mocks base method.
 func (m *MockMeshCataloger) GetOutboundMeshTrafficPolicy(arg0 identity.ServiceIdentity) *trafficpolicy.OutboundMeshTrafficPolicy {
 	m.ctrl.T.Helper()
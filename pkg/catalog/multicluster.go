@@ -0,0 +1,186 @@
+package catalog
+
+import (
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/multicluster"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	defaultOutlierDetectionConsecutiveErrors  = 5
+	defaultOutlierDetectionInterval           = 10 * time.Second
+	defaultOutlierDetectionBaseEjectionTime   = 30 * time.Second
+	defaultOutlierDetectionMaxEjectionPercent = 100
+
+	// multiclusterGatewayTrafficTargetName is the name of the traffic target allowing the multicluster gateway to
+	// connect to the HTTP ports of an upstream in HTTP mode
+	multiclusterGatewayTrafficTargetName = "osm-multicluster-gateway"
+)
+
+// getMulticlusterFailoverPolicy returns the failover policy specified on the MultiClusterService corresponding
+// to the given upstream service, or nil if multicluster mode is disabled or a failover policy is not specified.
+func (mc *MeshCatalog) getMulticlusterFailoverPolicy(meshSvc service.MeshService) *v1alpha1.FailoverSpec {
+	if mc.multiclusterController == nil {
+		return nil
+	}
+
+	mcs := mc.multiclusterController.GetMultiClusterService(meshSvc.Name, meshSvc.Namespace)
+	if mcs == nil {
+		return nil
+	}
+
+	return mcs.Spec.Failover
+}
+
+// applyMulticlusterFailoverPolicy configures the given cluster config based on the failover policy of the
+// upstream service it corresponds to. Outlier detection is enabled so that unhealthy local endpoints and remote
+// gateways are ejected, which shifts traffic to the next priority level of endpoints. The priority and weight
+// of each remote cluster's endpoints is configured by the endpoints provider.
+func (mc *MeshCatalog) applyMulticlusterFailoverPolicy(clusterConfig *trafficpolicy.MeshClusterConfig) {
+	failover := mc.getMulticlusterFailoverPolicy(clusterConfig.Service)
+	if failover == nil {
+		return
+	}
+
+	clusterConfig.EnableLocalityWeightedLb = failover.Mode == v1alpha1.ActiveActiveMode
+	clusterConfig.OutlierDetection = getOutlierDetection(failover.OutlierDetection)
+}
+
+// getOutlierDetection returns the outlier detection config for the given spec, using defaults for unset fields
+func getOutlierDetection(spec *v1alpha1.OutlierDetectionSpec) *trafficpolicy.OutlierDetection {
+	outlierDetection := &trafficpolicy.OutlierDetection{
+		ConsecutiveErrors:  defaultOutlierDetectionConsecutiveErrors,
+		Interval:           defaultOutlierDetectionInterval,
+		BaseEjectionTime:   defaultOutlierDetectionBaseEjectionTime,
+		MaxEjectionPercent: defaultOutlierDetectionMaxEjectionPercent,
+	}
+
+	if spec == nil {
+		return outlierDetection
+	}
+
+	if spec.ConsecutiveErrors != 0 {
+		outlierDetection.ConsecutiveErrors = spec.ConsecutiveErrors
+	}
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		outlierDetection.Interval = spec.Interval.Duration
+	}
+	if spec.BaseEjectionTime != nil && spec.BaseEjectionTime.Duration > 0 {
+		outlierDetection.BaseEjectionTime = spec.BaseEjectionTime.Duration
+	}
+	if spec.MaxEjectionPercent != 0 {
+		outlierDetection.MaxEjectionPercent = spec.MaxEjectionPercent
+	}
+
+	return outlierDetection
+}
+
+// isMulticlusterHTTPGatewayEnabled returns a boolean indicating if the multicluster gateway terminates mTLS for HTTP services
+func (mc *MeshCatalog) isMulticlusterHTTPGatewayEnabled() bool {
+	featureFlags := mc.configurator.GetFeatureFlags()
+	return featureFlags.EnableMulticlusterMode && featureFlags.EnableMulticlusterHTTPGateway
+}
+
+// GetMulticlusterGatewayTrafficPolicy returns the traffic policy for the multicluster gateway in HTTP mode, or nil
+// if HTTP mode is disabled. The gateway enforces the HTTP routes and RBAC policies of each HTTP service on behalf
+// of the service, so the routes are derived from the inbound traffic policies of the service.
+func (mc *MeshCatalog) GetMulticlusterGatewayTrafficPolicy() *trafficpolicy.MulticlusterGatewayTrafficPolicy {
+	if !mc.isMulticlusterHTTPGatewayEnabled() {
+		return nil
+	}
+
+	permissiveMode := mc.configurator.IsPermissiveTrafficPolicyMode()
+	gatewayPolicy := &trafficpolicy.MulticlusterGatewayTrafficPolicy{}
+
+	for _, upstreamSvc := range mc.ListOutboundServicesForMulticlusterGateway() {
+		if !isHTTPProtocol(upstreamSvc.Protocol) {
+			continue
+		}
+
+		upstreamIdentities := mc.ListServiceIdentitiesForService(upstreamSvc)
+		if len(upstreamIdentities) == 0 {
+			log.Debug().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("No service identities found for service %s, skipping gateway HTTP routes", upstreamSvc)
+			continue
+		}
+
+		gatewayCluster := service.WeightedCluster{
+			ClusterName: service.ClusterName(upstreamSvc.EnvoyClusterName()),
+			Weight:      constants.ClusterWeightAcceptAll,
+		}
+		gatewayPolicy.HTTPUpstreams = append(gatewayPolicy.HTTPUpstreams, &trafficpolicy.MulticlusterGatewayHTTPUpstream{
+			Service: upstreamSvc,
+			// The gateway can present a single certificate per service, so the first identity is used when
+			// a service is backed by multiple identities
+			Identity:    upstreamIdentities[0],
+			ClusterName: gatewayCluster.ClusterName.String(),
+		})
+
+		for _, upstreamIdentity := range upstreamIdentities {
+			var trafficTargets []*access.TrafficTarget
+			if !permissiveMode {
+				trafficTargets = mc.meshSpec.ListTrafficTargets(smi.WithTrafficTargetDestination(upstreamIdentity.ToK8sServiceAccount()))
+			}
+			inboundPolicy := mc.getInboundTrafficPoliciesForUpstream(upstreamIdentity, upstreamSvc, permissiveMode, trafficTargets, gatewayCluster)
+			gatewayPolicy.HTTPRoutePolicies = trafficpolicy.MergeInboundPolicies(AllowPartialHostnamesMatch, gatewayPolicy.HTTPRoutePolicies, inboundPolicy)
+		}
+	}
+
+	return gatewayPolicy
+}
+
+// allowMulticlusterGateway authorizes the multicluster gateway to access the routes of the given inbound
+// traffic policy in HTTP mode, since the gateway enforces the RBAC policies of the routes for remote downstreams
+// before re-originating mTLS using its own identity.
+func (mc *MeshCatalog) allowMulticlusterGateway(inboundPolicy *trafficpolicy.InboundTrafficPolicy) {
+	if inboundPolicy == nil || !mc.isMulticlusterHTTPGatewayEnabled() {
+		return
+	}
+
+	for _, rule := range inboundPolicy.Rules {
+		allowed := mapset.NewSet(mc.getMulticlusterGatewayIdentity())
+		if rule.AllowedServiceIdentities != nil {
+			allowed = allowed.Union(rule.AllowedServiceIdentities)
+		}
+		rule.AllowedServiceIdentities = allowed
+	}
+}
+
+// getMulticlusterGatewayIdentity returns the service identity of the multicluster gateway, derived from the service
+// account of the gateway in the OSM namespace
+func (mc *MeshCatalog) getMulticlusterGatewayIdentity() identity.ServiceIdentity {
+	return identity.K8sServiceAccount{
+		Name:      multicluster.GatewayServiceAccountName,
+		Namespace: mc.configurator.GetOSMNamespace(),
+	}.ToServiceIdentity()
+}
+
+// isHTTPProtocol returns a boolean indicating if the given service protocol is HTTP based
+func isHTTPProtocol(protocol string) bool {
+	switch protocol {
+	case constants.ProtocolHTTP, constants.ProtocolGRPC:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTCPProtocol returns a boolean indicating if the given service protocol is TCP based, such that the traffic to
+// the service is not routed using HTTP routes
+func isTCPProtocol(protocol string) bool {
+	switch protocol {
+	case constants.ProtocolTCP, constants.ProtocolTCPServerFirst,
+		constants.ProtocolMySQL, constants.ProtocolPostgres, constants.ProtocolRedis, constants.ProtocolMongo:
+		return true
+	default:
+		return false
+	}
+}
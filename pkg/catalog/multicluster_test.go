@@ -0,0 +1,203 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/config"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestApplyMulticlusterFailoverPolicy(t *testing.T) {
+	svc := service.MeshService{Name: "s1", Namespace: "ns1", Port: 80, TargetPort: 8080}
+
+	testCases := []struct {
+		name                             string
+		mcs                              *v1alpha1.MultiClusterService
+		expectedEnableLocalityWeightedLb bool
+		expectedOutlierDetection         *trafficpolicy.OutlierDetection
+	}{
+		{
+			name:                             "no MultiClusterService for the service",
+			mcs:                              nil,
+			expectedEnableLocalityWeightedLb: false,
+			expectedOutlierDetection:         nil,
+		},
+		{
+			name: "MultiClusterService without a failover policy",
+			mcs: &v1alpha1.MultiClusterService{
+				Spec: v1alpha1.MultiClusterServiceSpec{},
+			},
+			expectedEnableLocalityWeightedLb: false,
+			expectedOutlierDetection:         nil,
+		},
+		{
+			name: "failover only mode uses default outlier detection",
+			mcs: &v1alpha1.MultiClusterService{
+				Spec: v1alpha1.MultiClusterServiceSpec{
+					Failover: &v1alpha1.FailoverSpec{Mode: v1alpha1.FailoverOnlyMode},
+				},
+			},
+			expectedEnableLocalityWeightedLb: false,
+			expectedOutlierDetection: &trafficpolicy.OutlierDetection{
+				ConsecutiveErrors:  defaultOutlierDetectionConsecutiveErrors,
+				Interval:           defaultOutlierDetectionInterval,
+				BaseEjectionTime:   defaultOutlierDetectionBaseEjectionTime,
+				MaxEjectionPercent: defaultOutlierDetectionMaxEjectionPercent,
+			},
+		},
+		{
+			name: "active-active mode with custom outlier detection",
+			mcs: &v1alpha1.MultiClusterService{
+				Spec: v1alpha1.MultiClusterServiceSpec{
+					Failover: &v1alpha1.FailoverSpec{
+						Mode: v1alpha1.ActiveActiveMode,
+						OutlierDetection: &v1alpha1.OutlierDetectionSpec{
+							ConsecutiveErrors: 3,
+							Interval:          &metav1.Duration{Duration: 5 * time.Second},
+						},
+					},
+				},
+			},
+			expectedEnableLocalityWeightedLb: true,
+			expectedOutlierDetection: &trafficpolicy.OutlierDetection{
+				ConsecutiveErrors:  3,
+				Interval:           5 * time.Second,
+				BaseEjectionTime:   defaultOutlierDetectionBaseEjectionTime,
+				MaxEjectionPercent: defaultOutlierDetectionMaxEjectionPercent,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigController := config.NewMockController(mockCtrl)
+			mockConfigController.EXPECT().GetMultiClusterService(svc.Name, svc.Namespace).Return(tc.mcs).Times(1)
+
+			mc := MeshCatalog{
+				multiclusterController: mockConfigController,
+			}
+
+			clusterConfig := &trafficpolicy.MeshClusterConfig{Service: svc}
+			mc.applyMulticlusterFailoverPolicy(clusterConfig)
+
+			assert.Equal(tc.expectedEnableLocalityWeightedLb, clusterConfig.EnableLocalityWeightedLb)
+			assert.Equal(tc.expectedOutlierDetection, clusterConfig.OutlierDetection)
+		})
+	}
+}
+
+func TestApplyMulticlusterFailoverPolicyDisabled(t *testing.T) {
+	assert := tassert.New(t)
+
+	mc := MeshCatalog{}
+	clusterConfig := &trafficpolicy.MeshClusterConfig{
+		Service: service.MeshService{Name: "s1", Namespace: "ns1"},
+	}
+	mc.applyMulticlusterFailoverPolicy(clusterConfig)
+
+	assert.False(clusterConfig.EnableLocalityWeightedLb)
+	assert.Nil(clusterConfig.OutlierDetection)
+}
+
+func TestGetMulticlusterGatewayTrafficPolicy(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockServiceProvider := service.NewMockProvider(mockCtrl)
+
+	httpSvc := service.MeshService{Name: "s1", Namespace: "ns1", Port: 80, TargetPort: 8080, Protocol: constants.ProtocolHTTP}
+	tcpSvc := service.MeshService{Name: "s2", Namespace: "ns1", Port: 90, TargetPort: 9090, Protocol: constants.ProtocolTCP}
+	svcIdentity := identity.K8sServiceAccount{Name: "sa1", Namespace: "ns1"}.ToServiceIdentity()
+
+	mc := MeshCatalog{
+		configurator:     mockCfg,
+		serviceProviders: []service.Provider{mockServiceProvider},
+	}
+
+	// HTTP mode disabled
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).Times(1)
+	assert.Nil(mc.GetMulticlusterGatewayTrafficPolicy())
+
+	// HTTP mode enabled
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
+		EnableMulticlusterMode:        true,
+		EnableMulticlusterHTTPGateway: true,
+	}).Times(1)
+	mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).Times(1)
+	mockServiceProvider.EXPECT().ListServices().Return([]service.MeshService{httpSvc, tcpSvc}).Times(1)
+	mockServiceProvider.EXPECT().ListServiceIdentitiesForService(httpSvc).Return([]identity.ServiceIdentity{svcIdentity}).Times(1)
+
+	gatewayPolicy := mc.GetMulticlusterGatewayTrafficPolicy()
+	assert.NotNil(gatewayPolicy)
+
+	// Only the HTTP service is terminated by the gateway
+	assert.Equal([]*trafficpolicy.MulticlusterGatewayHTTPUpstream{
+		{
+			Service:     httpSvc,
+			Identity:    svcIdentity,
+			ClusterName: httpSvc.EnvoyClusterName(),
+		},
+	}, gatewayPolicy.HTTPUpstreams)
+
+	// The routes direct traffic to the gateway's upstream cluster for the service
+	assert.Len(gatewayPolicy.HTTPRoutePolicies, 1)
+	assert.Len(gatewayPolicy.HTTPRoutePolicies[0].Rules, 1)
+	assert.Equal(mapset.NewSet(service.WeightedCluster{
+		ClusterName: service.ClusterName(httpSvc.EnvoyClusterName()),
+		Weight:      constants.ClusterWeightAcceptAll,
+	}), gatewayPolicy.HTTPRoutePolicies[0].Rules[0].Route.WeightedClusters)
+}
+
+func TestAllowMulticlusterGateway(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	gatewayIdentity := identity.K8sServiceAccount{Name: "osm-multicluster-gateway", Namespace: "osm-system"}.ToServiceIdentity()
+	downstreamIdentity := identity.K8sServiceAccount{Name: "sa1", Namespace: "ns1"}.ToServiceIdentity()
+
+	mc := MeshCatalog{
+		configurator: mockCfg,
+	}
+	mockCfg.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	newPolicy := func() *trafficpolicy.InboundTrafficPolicy {
+		return &trafficpolicy.InboundTrafficPolicy{
+			Rules: []*trafficpolicy.Rule{
+				{AllowedServiceIdentities: mapset.NewSet(downstreamIdentity)},
+			},
+		}
+	}
+
+	// HTTP mode disabled
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).Times(1)
+	policy := newPolicy()
+	mc.allowMulticlusterGateway(policy)
+	assert.Equal(mapset.NewSet(downstreamIdentity), policy.Rules[0].AllowedServiceIdentities)
+
+	// HTTP mode enabled
+	mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
+		EnableMulticlusterMode:        true,
+		EnableMulticlusterHTTPGateway: true,
+	}).Times(1)
+	policy = newPolicy()
+	mc.allowMulticlusterGateway(policy)
+	assert.Equal(mapset.NewSet(downstreamIdentity, gatewayIdentity), policy.Rules[0].AllowedServiceIdentities)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
 )
 
 // GetOutboundMeshTrafficPolicy returns the outbound mesh traffic policy for the given downstream identity
@@ -42,19 +43,35 @@ func (mc *MeshCatalog) GetOutboundMeshTrafficPolicy(downstreamIdentity identity.
 		var destinationIPRanges []string
 		destinationIPSet := mapset.NewSet()
 		for _, endp := range mc.getDNSResolvableServiceEndpoints(meshSvc) {
-			ipCIDR := endp.IP.String() + "/32"
+			ipCIDR := utils.GetCIDRForIP(endp.IP)
 			if added := destinationIPSet.Add(ipCIDR); added {
 				destinationIPRanges = append(destinationIPRanges, ipCIDR)
 			}
 		}
 
+		// Retrieve the load balancer config for this service. A consistent-hash load balancer
+		// requires the hash policy to be programmed on the routes (HTTP) or TCP proxy (TCP)
+		// used to reach the service.
+		lbConfig := mc.getLoadBalancerConfig(meshSvc)
+		redisSharding := meshSvc.Protocol == constants.ProtocolRedis && mc.isRedisShardingEnabled(meshSvc)
+		var hashPolicy *trafficpolicy.HashPolicy
+		if lbConfig != nil {
+			hashPolicy = lbConfig.HashPolicy
+		} else if redisSharding {
+			// The Redis proxy routes each command to the endpoint selected by hashing its key,
+			// which requires a consistent-hash load balancer
+			lbConfig = &trafficpolicy.LoadBalancerConfig{Algorithm: trafficpolicy.MaglevLoadBalancer}
+		}
+
 		// ---
 		// Create the cluster config for this upstream service
 		clusterConfigForServicePort := &trafficpolicy.MeshClusterConfig{
 			Name:                          meshSvc.EnvoyClusterName(),
 			Service:                       meshSvc,
 			EnableEnvoyActiveHealthChecks: mc.configurator.GetFeatureFlags().EnableEnvoyActiveHealthChecks,
+			LoadBalancer:                  lbConfig,
 		}
+		mc.applyMulticlusterFailoverPolicy(clusterConfigForServicePort)
 		clusterConfigs = append(clusterConfigs, clusterConfigForServicePort)
 
 		var upstreamClusters []service.WeightedCluster
@@ -70,6 +87,13 @@ func (mc *MeshCatalog) GetOutboundMeshTrafficPolicy(downstreamIdentity identity.
 		if len(trafficSplits) != 0 {
 			// Program routes to the backends specified in the traffic split
 			split := trafficSplits[0] // TODO(#2759): support multiple traffic splits per apex service
+			if hashPolicy != nil {
+				// The backend clusters are load balanced as configured on the backend services, so a hash policy
+				// derived from the apex service would be ignored by a round robin backend cluster
+				log.Warn().Msgf("Ignoring the hash policy of apex service %s as its traffic is split to backends by TrafficSplit %s/%s; configure the load balancer on the backend services instead",
+					meshSvc, split.Namespace, split.Name)
+				hashPolicy = nil
+			}
 			for _, backend := range split.Spec.Backends {
 				backendMeshSvc := service.MeshService{
 					Namespace:  meshSvc.Namespace, // Backends belong to the same namespace as the apex service
@@ -102,13 +126,15 @@ func (mc *MeshCatalog) GetOutboundMeshTrafficPolicy(downstreamIdentity identity.
 			DestinationProtocol: meshSvc.Protocol,
 			DestinationIPRanges: destinationIPRanges,
 			WeightedClusters:    upstreamClusters,
+			HashPolicy:          hashPolicy,
+			RedisSharding:       redisSharding,
 		}
 		trafficMatches = append(trafficMatches, trafficMatchForServicePort)
 		log.Trace().Msgf("Built traffic match %s for downstream %s", trafficMatchForServicePort.Name, downstreamIdentity)
 
 		// Build the HTTP route configs for this service and port combination.
 		// If the port's protocol corresponds to TCP, we can skip this step
-		if meshSvc.Protocol == constants.ProtocolTCP || meshSvc.Protocol == constants.ProtocolTCPServerFirst {
+		if isTCPProtocol(meshSvc.Protocol) {
 			continue
 		}
 		// Create a route to access the upstream service via it's hostnames and upstream weighted clusters
@@ -119,6 +145,9 @@ func (mc *MeshCatalog) GetOutboundMeshTrafficPolicy(downstreamIdentity identity.
 				Msgf("Error adding route to outbound mesh HTTP traffic policy for destination %s", meshSvc)
 			continue
 		}
+		for _, route := range outboundTrafficPolicy.Routes {
+			route.HashPolicy = hashPolicy
+		}
 		routeConfigPerPort[int(meshSvc.Port)] = append(routeConfigPerPort[int(meshSvc.Port)], outboundTrafficPolicy)
 	}
 
@@ -16,6 +16,7 @@ import (
 	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -613,6 +614,9 @@ func TestGetOutboundMeshTrafficPolicy(t *testing.T) {
 					return svcToEndpointsMap[svc.String()], nil
 				}).AnyTimes()
 
+			// Mock k8s service lookups used to retrieve the load balancer config
+			mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+
 			actual := mc.GetOutboundMeshTrafficPolicy(downstreamIdentity)
 			assert.NotNil(actual)
 
@@ -627,6 +631,146 @@ func TestGetOutboundMeshTrafficPolicy(t *testing.T) {
 	}
 }
 
+func TestGetOutboundMeshTrafficPolicyProtocols(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	redisSvc := service.MeshService{Name: "redis", Namespace: "ns1", Port: 6379, TargetPort: 6379, Protocol: "redis"}
+	shardedRedisSvc := service.MeshService{Name: "redis-shards", Namespace: "ns1", Port: 6380, TargetPort: 6379, Protocol: "redis"}
+	autoSvc := service.MeshService{Name: "web", Namespace: "ns1", Port: 8080, TargetPort: 80, Protocol: "auto"}
+	downstreamIdentity := identity.ServiceIdentity("sa-x.ns1.cluster.local")
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
+	mockServiceProvider := service.NewMockProvider(mockCtrl)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mc := MeshCatalog{
+		kubeController:     mockKubeController,
+		endpointsProviders: []endpoint.Provider{mockEndpointProvider},
+		serviceProviders:   []service.Provider{mockServiceProvider},
+		configurator:       mockCfg,
+		meshSpec:           mockMeshSpec,
+	}
+
+	mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+	mockCfg.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
+	mockServiceProvider.EXPECT().ListServices().Return([]service.MeshService{redisSvc, shardedRedisSvc, autoSvc}).AnyTimes()
+	mockServiceProvider.EXPECT().GetID().Return("test").AnyTimes()
+	mockEndpointProvider.EXPECT().GetID().Return("test").AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficSplits(gomock.Any()).Return(nil).AnyTimes()
+	mockEndpointProvider.EXPECT().GetResolvableEndpointsForService(gomock.Any()).Return([]endpoint.Endpoint{{IP: net.ParseIP("10.0.1.1")}}).AnyTimes()
+	mockKubeController.EXPECT().GetService(shardedRedisSvc).Return(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        shardedRedisSvc.Name,
+			Namespace:   shardedRedisSvc.Namespace,
+			Annotations: map[string]string{constants.RedisShardingAnnotation: "true"},
+		},
+	}).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+
+	actual := mc.GetOutboundMeshTrafficPolicy(downstreamIdentity)
+	assert.NotNil(actual)
+
+	// The cluster of the Redis service opting into sharding uses a consistent-hash load balancer
+	// for the Redis proxy to route the commands by key
+	for _, clusterConfig := range actual.ClustersConfigs {
+		if clusterConfig.Service == shardedRedisSvc {
+			assert.Equal(&trafficpolicy.LoadBalancerConfig{Algorithm: trafficpolicy.MaglevLoadBalancer}, clusterConfig.LoadBalancer)
+		} else {
+			assert.Nil(clusterConfig.LoadBalancer)
+		}
+	}
+	for _, trafficMatch := range actual.TrafficMatches {
+		assert.Equal(trafficMatch.DestinationPort == int(shardedRedisSvc.Port), trafficMatch.RedisSharding, trafficMatch.Name)
+	}
+
+	// The traffic to the port whose protocol is detected may be HTTP, unlike the Redis traffic
+	assert.Len(actual.HTTPRouteConfigsPerPort[int(autoSvc.Port)], 1)
+	assert.Empty(actual.HTTPRouteConfigsPerPort[int(redisSvc.Port)])
+}
+
+func TestGetOutboundMeshTrafficPolicyHashPolicy(t *testing.T) {
+	apexSvc := service.MeshService{Name: "apex", Namespace: "ns1", Port: 80, TargetPort: 8080, Protocol: "http"}
+	backendSvc := service.MeshService{Name: "backend", Namespace: "ns1", Port: 80, TargetPort: 8080, Protocol: "http"}
+	downstreamIdentity := identity.ServiceIdentity("sa-x.ns1.cluster.local")
+	hashPolicy := &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeySourceIP}
+
+	testCases := []struct {
+		name               string
+		trafficSplits      []*split.TrafficSplit
+		expectedHashPolicy *trafficpolicy.HashPolicy
+	}{
+		{
+			name:               "hash policy of the apex service is programmed on its routes",
+			expectedHashPolicy: hashPolicy,
+		},
+		{
+			name: "hash policy of the apex service is ignored when its traffic is split",
+			trafficSplits: []*split.TrafficSplit{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "split", Namespace: "ns1"},
+					Spec: split.TrafficSplitSpec{
+						Service:  apexSvc.Name,
+						Backends: []split.TrafficSplitBackend{{Service: backendSvc.Name, Weight: 100}},
+					},
+				},
+			},
+			expectedHashPolicy: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
+			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mc := MeshCatalog{
+				kubeController:     mockKubeController,
+				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
+				serviceProviders:   []service.Provider{mockServiceProvider},
+				configurator:       mockCfg,
+				meshSpec:           mockMeshSpec,
+			}
+
+			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+			mockCfg.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
+			mockServiceProvider.EXPECT().ListServices().Return([]service.MeshService{apexSvc}).AnyTimes()
+			mockServiceProvider.EXPECT().GetID().Return("test").AnyTimes()
+			mockEndpointProvider.EXPECT().GetID().Return("test").AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficSplits().Return(tc.trafficSplits).AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficSplits(gomock.Any()).Return(tc.trafficSplits).AnyTimes()
+			mockEndpointProvider.EXPECT().GetResolvableEndpointsForService(gomock.Any()).Return([]endpoint.Endpoint{{IP: net.ParseIP("10.0.1.1")}}).AnyTimes()
+			mockKubeController.EXPECT().GetService(apexSvc).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      apexSvc.Name,
+					Namespace: apexSvc.Namespace,
+					Annotations: map[string]string{
+						constants.LoadBalancerAnnotation: "maglev",
+						constants.HashKeyAnnotation:      "source-ip",
+					},
+				},
+			}).AnyTimes()
+
+			actual := mc.GetOutboundMeshTrafficPolicy(downstreamIdentity)
+			assert.NotNil(actual)
+
+			assert.Len(actual.TrafficMatches, 1)
+			assert.Equal(tc.expectedHashPolicy, actual.TrafficMatches[0].HashPolicy)
+			assert.Len(actual.HTTPRouteConfigsPerPort[int(apexSvc.Port)], 1)
+			for _, route := range actual.HTTPRouteConfigsPerPort[int(apexSvc.Port)][0].Routes {
+				assert.Equal(tc.expectedHashPolicy, route.HashPolicy)
+			}
+		})
+	}
+}
+
 func TestListOutboundServicesForIdentity(t *testing.T) {
 	assert := tassert.New(t)
 
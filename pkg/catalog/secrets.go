@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+// WatchReferencedSecrets keeps the secrets watched by the Kubernetes controller in sync with the secrets referenced
+// by the Egress policies and the Gateways of the mesh, so that only the secrets served to proxies are cached.
+func (mc *MeshCatalog) WatchReferencedSecrets(msgBroker *messaging.Broker, stop <-chan struct{}) {
+	kubePubSub := msgBroker.GetKubeEventPubSub()
+	referencesChan := kubePubSub.Sub(
+		announcements.EgressAdded.String(),
+		announcements.EgressUpdated.String(),
+		announcements.EgressDeleted.String(),
+		announcements.GatewayAdded.String(),
+		announcements.GatewayUpdated.String(),
+		announcements.GatewayDeleted.String(),
+		announcements.GatewayClassAdded.String(),
+		announcements.GatewayClassUpdated.String(),
+		announcements.GatewayClassDeleted.String(),
+		announcements.NamespaceAdded.String(),
+		announcements.NamespaceUpdated.String(),
+		announcements.NamespaceDeleted.String(),
+	)
+	defer msgBroker.Unsub(kubePubSub, referencesChan)
+
+	mc.kubeController.WatchSecrets(mc.listReferencedSecrets())
+
+	for {
+		select {
+		case <-stop:
+			log.Info().Msg("Received stop signal, exiting referenced secrets watch routine")
+			return
+
+		case <-referencesChan:
+			mc.kubeController.WatchSecrets(mc.listReferencedSecrets())
+		}
+	}
+}
+
+// listReferencedSecrets returns the secrets referenced by the TLS origination configs of the Egress policies and
+// by the TLS terminated listeners of the Gateways
+func (mc *MeshCatalog) listReferencedSecrets() []types.NamespacedName {
+	var secrets []types.NamespacedName
+
+	for _, egress := range mc.policyController.ListEgressPolicies() {
+		for _, port := range egress.Spec.Ports {
+			if port.TLS == nil {
+				continue
+			}
+			secrets = append(secrets, types.NamespacedName{Namespace: egress.Namespace, Name: port.TLS.CASecretName})
+			if port.TLS.ClientCertSecretName != "" {
+				secrets = append(secrets, types.NamespacedName{Namespace: egress.Namespace, Name: port.TLS.ClientCertSecretName})
+			}
+		}
+	}
+
+	if mc.gatewayAPIController == nil {
+		return secrets
+	}
+
+	for _, gateway := range mc.gatewayAPIController.ListGateways() {
+		for _, listener := range gateway.Spec.Listeners {
+			if isTLSTerminated(listener) {
+				secrets = append(secrets, getListenerCertificateSecrets(gateway, listener)...)
+			}
+		}
+	}
+
+	return secrets
+}
@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/gatewayapi"
+	"github.com/openservicemesh/osm/pkg/policy"
+)
+
+func TestListReferencedSecrets(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockPolicyController := policy.NewMockController(mockCtrl)
+	mockGatewayAPIController := gatewayapi.NewMockController(mockCtrl)
+
+	mockPolicyController.EXPECT().ListEgressPolicies().Return([]*policyV1alpha1.Egress{
+		newTestEgressPolicy("egress-1", []string{"sa-1"}, []string{"foo.com"},
+			policyV1alpha1.PortSpec{Number: 80, Protocol: "http"},
+			policyV1alpha1.PortSpec{Number: 443, Protocol: "http", TLS: &policyV1alpha1.EgressTLSSpec{SNI: "foo.com", CASecretName: "ca"}},
+			policyV1alpha1.PortSpec{Number: 8443, Protocol: "http", TLS: &policyV1alpha1.EgressTLSSpec{SNI: "foo.com", CASecretName: "ca", ClientCertSecretName: "client"}},
+		),
+	}).AnyTimes()
+
+	terminate := gatewayv1alpha2.TLSModeTerminate
+	passthrough := gatewayv1alpha2.TLSModePassthrough
+	mockGatewayAPIController.EXPECT().ListGateways().Return([]*gatewayv1alpha2.Gateway{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "gw"},
+			Spec: gatewayv1alpha2.GatewaySpec{
+				Listeners: []gatewayv1alpha2.Listener{
+					{Name: "http", Port: 80, Protocol: gatewayv1alpha2.HTTPProtocolType},
+					{
+						Name: "https", Port: 443, Protocol: gatewayv1alpha2.HTTPSProtocolType,
+						TLS: &gatewayv1alpha2.GatewayTLSConfig{
+							Mode:            &terminate,
+							CertificateRefs: []*gatewayv1alpha2.SecretObjectReference{{Name: "cert"}},
+						},
+					},
+					{
+						Name: "tls", Port: 8443, Protocol: gatewayv1alpha2.TLSProtocolType,
+						TLS: &gatewayv1alpha2.GatewayTLSConfig{
+							Mode:            &passthrough,
+							CertificateRefs: []*gatewayv1alpha2.SecretObjectReference{{Name: "unused"}},
+						},
+					},
+				},
+			},
+		},
+	}).AnyTimes()
+
+	mc := &MeshCatalog{
+		policyController: mockPolicyController,
+	}
+	assert.ElementsMatch([]types.NamespacedName{
+		{Namespace: "test", Name: "ca"},
+		{Namespace: "test", Name: "ca"},
+		{Namespace: "test", Name: "client"},
+	}, mc.listReferencedSecrets())
+
+	mc.gatewayAPIController = mockGatewayAPIController
+	assert.ElementsMatch([]types.NamespacedName{
+		{Namespace: "test", Name: "ca"},
+		{Namespace: "test", Name: "ca"},
+		{Namespace: "test", Name: "client"},
+		{Namespace: "gw", Name: "cert"},
+	}, mc.listReferencedSecrets())
+}
@@ -20,6 +20,11 @@ func (mc *MeshCatalog) getServicesForServiceIdentity(svcIdentity identity.Servic
 
 // ListServiceIdentitiesForService lists the service identities associated with the given mesh service.
 func (mc *MeshCatalog) ListServiceIdentitiesForService(svc service.MeshService) []identity.ServiceIdentity {
+	// The egress gateway is not a part of the mesh, so its identity is not known to the service providers
+	if mc.isEgressGatewayService(svc) {
+		return []identity.ServiceIdentity{mc.egressGatewayIdentity}
+	}
+
 	// Currently OSM uses kubernetes service accounts as service identities
 	var serviceIdentities []identity.ServiceIdentity
 	for _, provider := range mc.serviceProviders {
@@ -33,6 +33,7 @@ func (mc *MeshCatalog) ListInboundTrafficTargetsWithRoutes(upstream identity.Ser
 		return nil, nil
 	}
 
+	allowMulticlusterGateway := false
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		destinationSvcIdentity := trafficTargetIdentityToSvcAccount(t.Spec.Destination).ToServiceIdentity()
 		if destinationSvcIdentity != upstream {
@@ -54,6 +55,9 @@ func (mc *MeshCatalog) ListInboundTrafficTargetsWithRoutes(upstream identity.Ser
 			sourceIdentities = append(sourceIdentities, srcIdentity)
 		}
 		trafficTarget.Sources = sourceIdentities
+		if hasHTTPRouteGroupRules(t) {
+			allowMulticlusterGateway = true
+		}
 
 		// TCP routes for this traffic target
 		if tcpRouteMatches, err := mc.getTCPRouteMatchesFromTrafficTarget(*t); err != nil {
@@ -65,9 +69,52 @@ func (mc *MeshCatalog) ListInboundTrafficTargetsWithRoutes(upstream identity.Ser
 		}
 	}
 
+	// The multicluster gateway enforces the HTTP route rules of the traffic targets for remote downstreams in HTTP mode,
+	// so it is only allowed to connect to the HTTP ports of the upstream
+	if allowMulticlusterGateway && mc.isMulticlusterHTTPGatewayEnabled() {
+		if gatewayTrafficTarget := mc.getMulticlusterGatewayTrafficTarget(upstream); gatewayTrafficTarget != nil {
+			trafficTargets = append(trafficTargets, *gatewayTrafficTarget)
+		}
+	}
+
 	return trafficTargets, nil
 }
 
+// getMulticlusterGatewayTrafficTarget returns the traffic target allowing the multicluster gateway to connect to the
+// HTTP ports of the given upstream, or nil if the upstream has no HTTP ports
+func (mc *MeshCatalog) getMulticlusterGatewayTrafficTarget(upstream identity.ServiceIdentity) *trafficpolicy.TrafficTargetWithRoutes {
+	var httpPorts []int
+	portSet := mapset.NewSet()
+	for _, svc := range mc.getServicesForServiceIdentity(upstream) {
+		if !isHTTPProtocol(mc.GetInboundServiceProtocol(upstream, svc)) {
+			continue
+		}
+		if added := portSet.Add(int(svc.TargetPort)); added {
+			httpPorts = append(httpPorts, int(svc.TargetPort))
+		}
+	}
+	if len(httpPorts) == 0 {
+		return nil
+	}
+
+	return &trafficpolicy.TrafficTargetWithRoutes{
+		Name:            multiclusterGatewayTrafficTargetName,
+		Destination:     identity.GetKubernetesServiceIdentity(upstream.ToK8sServiceAccount(), identity.ClusterLocalTrustDomain),
+		Sources:         []identity.ServiceIdentity{mc.getMulticlusterGatewayIdentity()},
+		TCPRouteMatches: []trafficpolicy.TCPRouteMatch{{Ports: httpPorts}},
+	}
+}
+
+// hasHTTPRouteGroupRules returns a boolean indicating if the given traffic target has HTTP route rules
+func hasHTTPRouteGroupRules(trafficTarget *smiAccess.TrafficTarget) bool {
+	for _, rule := range trafficTarget.Spec.Rules {
+		if rule.Kind == smi.HTTPRouteGroupKind {
+			return true
+		}
+	}
+	return false
+}
+
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func (mc *MeshCatalog) getAllowedDirectionalServiceAccounts(svcIdentity identity.ServiceIdentity, direction trafficDirection) []identity.ServiceIdentity {
 	svcAccount := svcIdentity.ToK8sServiceAccount()
@@ -10,9 +10,11 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/configurator"
 
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
@@ -851,6 +853,7 @@ func TestListInboundTrafficTargetsWithRoutes(t *testing.T) {
 			}
 
 			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
 
 			// Mock TrafficTargets returned by MeshSpec, should return all TrafficTargets relevant for this test
 			mockMeshSpec.EXPECT().ListTrafficTargets().Return(tc.trafficTargets).AnyTimes()
@@ -870,3 +873,92 @@ func TestListInboundTrafficTargetsWithRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestListInboundTrafficTargetsWithRoutesForMulticlusterGateway(t *testing.T) {
+	upstreamIdentity := identity.K8sServiceAccount{Namespace: "ns-1", Name: "sa-1"}.ToServiceIdentity()
+	downstreamIdentity := identity.ServiceIdentity("sa-2.ns-2.cluster.local")
+	gatewayIdentity := identity.ServiceIdentity("osm-multicluster-gateway.osm-system.cluster.local")
+	upstreamServices := []service.MeshService{
+		{Name: "web", Namespace: "ns-1", Port: 80, TargetPort: 8080, Protocol: "http"},
+		{Name: "db", Namespace: "ns-1", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
+	}
+	newTrafficTarget := func(ruleKind string) *smiAccess.TrafficTarget {
+		return &smiAccess.TrafficTarget{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-1", Namespace: "ns-1"},
+			Spec: smiAccess.TrafficTargetSpec{
+				Destination: smiAccess.IdentityBindingSubject{Kind: "ServiceAccount", Name: "sa-1", Namespace: "ns-1"},
+				Sources:     []smiAccess.IdentityBindingSubject{{Kind: "ServiceAccount", Name: "sa-2", Namespace: "ns-2"}},
+				Rules:       []smiAccess.TrafficTargetRule{{Kind: ruleKind, Name: "route-1"}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                   string
+		trafficTarget          *smiAccess.TrafficTarget
+		expectedTrafficTargets []trafficpolicy.TrafficTargetWithRoutes
+	}{
+		{
+			name:          "gateway is allowed to connect to the HTTP ports of a traffic target with HTTP route rules",
+			trafficTarget: newTrafficTarget(smi.HTTPRouteGroupKind),
+			expectedTrafficTargets: []trafficpolicy.TrafficTargetWithRoutes{
+				{
+					Name:        "ns-1/test-1",
+					Destination: upstreamIdentity,
+					Sources:     []identity.ServiceIdentity{downstreamIdentity},
+				},
+				{
+					Name:            multiclusterGatewayTrafficTargetName,
+					Destination:     upstreamIdentity,
+					Sources:         []identity.ServiceIdentity{gatewayIdentity},
+					TCPRouteMatches: []trafficpolicy.TCPRouteMatch{{Ports: []int{8080}}},
+				},
+			},
+		},
+		{
+			name:          "gateway is not allowed to connect to a traffic target with only TCP route rules",
+			trafficTarget: newTrafficTarget(smi.TCPRouteKind),
+			expectedTrafficTargets: []trafficpolicy.TrafficTargetWithRoutes{
+				{
+					Name:            "ns-1/test-1",
+					Destination:     upstreamIdentity,
+					Sources:         []identity.ServiceIdentity{downstreamIdentity},
+					TCPRouteMatches: []trafficpolicy.TCPRouteMatch{{Ports: []int{5432}}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			meshCatalog := MeshCatalog{
+				meshSpec:         mockMeshSpec,
+				configurator:     mockCfg,
+				serviceProviders: []service.Provider{mockServiceProvider},
+			}
+
+			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
+				EnableMulticlusterMode:        true,
+				EnableMulticlusterHTTPGateway: true,
+			}).AnyTimes()
+			mockCfg.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficTargets().Return([]*smiAccess.TrafficTarget{tc.trafficTarget}).AnyTimes()
+			mockMeshSpec.EXPECT().GetTCPRoute("ns-1/route-1").Return(&smiSpecs.TCPRoute{
+				Spec: smiSpecs.TCPRouteSpec{Matches: smiSpecs.TCPMatch{Ports: []int{5432}}},
+			}).AnyTimes()
+			mockServiceProvider.EXPECT().GetServicesForServiceIdentity(upstreamIdentity).Return(upstreamServices).AnyTimes()
+			mockServiceProvider.EXPECT().GetID().Return("test").AnyTimes()
+
+			actual, err := meshCatalog.ListInboundTrafficTargetsWithRoutes(upstreamIdentity)
+			assert.Nil(err)
+			assert.ElementsMatch(tc.expectedTrafficTargets, actual)
+		})
+	}
+}
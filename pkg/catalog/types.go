@@ -5,12 +5,19 @@
 package catalog
 
 import (
+	"sync"
+
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/egressdns"
 	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/gatewayapi"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/ingress"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/policy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
@@ -37,6 +44,42 @@ type MeshCatalog struct {
 	// policyController implements the functionality related to the resources part of the policy.openrservicemesh.io
 	// API group, such as egress.
 	policyController policy.Controller
+
+	// multiclusterController implements the functionality related to the MultiClusterService resource
+	// part of the config.openservicemesh.io API group. It is nil when multicluster mode is disabled.
+	multiclusterController config.Controller
+
+	// gatewayAPIController implements the functionality related to the resources part of the gateway.networking.k8s.io
+	// API group used to program the ingress gateway. It is nil when the ingress gateway is disabled.
+	gatewayAPIController gatewayapi.Controller
+
+	// ingressMonitor implements the functionality related to the Kubernetes Ingress resources of the IngressClasses
+	// monitored by OSM
+	ingressMonitor ingress.Monitor
+
+	// egressDNSResolver resolves the hosts of the TCP ports of Egress policies to the IP addresses TCP egress
+	// traffic is matched on
+	egressDNSResolver egressdns.Resolver
+
+	// egressGatewayIdentity is the service identity of the egress gateway, used by sidecars to validate the
+	// identity of the egress gateway.
+	egressGatewayIdentity identity.ServiceIdentity
+
+	// msgBroker counts the events updating the proxies, which invalidate the cached ingress gateway traffic policy
+	msgBroker *messaging.Broker
+
+	// ingressGatewayPolicyCache caches the ingress gateway traffic policy, which is needed for each service of each
+	// proxy to authorize the ingress gateway to access the backends
+	ingressGatewayPolicyCache ingressGatewayPolicyCache
+}
+
+// ingressGatewayPolicyCache is the type used to cache the ingress gateway traffic policy until the next event
+// updating the proxies
+type ingressGatewayPolicyCache struct {
+	sync.Mutex
+	valid      bool
+	eventCount uint64
+	policy     *trafficpolicy.IngressGatewayTrafficPolicy
 }
 
 // MeshCataloger is the mechanism by which the Service Mesh controller discovers all Envoy proxies connected to the catalog.
@@ -77,6 +120,19 @@ type MeshCataloger interface {
 
 	// GetInboundMeshTrafficPolicy returns the inbound mesh traffic policy for the given upstream identity and services
 	GetInboundMeshTrafficPolicy(identity.ServiceIdentity, []service.MeshService) *trafficpolicy.InboundMeshTrafficPolicy
+
+	// GetInboundServiceProtocol returns the protocol with which the inbound traffic to the given upstream service is
+	// proxied by the proxies of the given upstream identity
+	GetInboundServiceProtocol(identity.ServiceIdentity, service.MeshService) string
+
+	// GetMulticlusterGatewayTrafficPolicy returns the traffic policy for the multicluster gateway in HTTP mode
+	GetMulticlusterGatewayTrafficPolicy() *trafficpolicy.MulticlusterGatewayTrafficPolicy
+
+	// GetEgressGatewayTrafficPolicy returns the traffic policy for the egress gateway
+	GetEgressGatewayTrafficPolicy() *trafficpolicy.EgressGatewayTrafficPolicy
+
+	// GetIngressGatewayTrafficPolicy returns the traffic policy for the ingress gateway
+	GetIngressGatewayTrafficPolicy() *trafficpolicy.IngressGatewayTrafficPolicy
 }
 
 type trafficDirection string
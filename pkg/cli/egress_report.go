@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+const (
+	prometheusServiceName = "osm-prometheus"
+	prometheusQueryPath   = "/api/v1/query"
+
+	// egressClusterNameSelector selects the egress cluster stats, whose 'envoy_cluster_name' tag
+	// is the egress stat prefix once the egress stats tags are extracted
+	egressClusterNameSelector = `envoy_cluster_name="` + envoy.EgressStatPrefix + `"`
+)
+
+// EgressTrafficSummary is the egress traffic from a source identity to an external destination over a time window
+type EgressTrafficSummary struct {
+	SourceNamespace      string
+	SourceServiceAccount string
+	Host                 string
+	Port                 string
+	Requests             float64
+	Connections          float64
+	BytesSent            float64
+	BytesReceived        float64
+}
+
+// egressTrafficKey uniquely identifies the source identity and external destination of egress traffic
+type egressTrafficKey struct {
+	sourceNamespace      string
+	sourceServiceAccount string
+	host                 string
+	port                 string
+}
+
+// prometheusQueryResponse is the response of the Prometheus instant query API
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// GetEgressTrafficReport returns the egress traffic of the mesh over the given time window, as recorded
+// by the Prometheus instance deployed by OSM in the given namespace
+func GetEgressTrafficReport(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, localPort uint16, window string) ([]EgressTrafficSummary, error) {
+	svc, err := clientSet.CoreV1().Services(osmNamespace).Get(context.TODO(), prometheusServiceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Errorf("Could not find service %s in namespace %s: %s", prometheusServiceName, osmNamespace, err)
+	}
+	if len(svc.Spec.Ports) == 0 {
+		return nil, errors.Errorf("Service %s in namespace %s does not expose any port", prometheusServiceName, osmNamespace)
+	}
+
+	pods, err := clientSet.CoreV1().Pods(osmNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.Set(svc.Spec.Selector).AsSelector().String(),
+	})
+	if err != nil {
+		return nil, errors.Errorf("Error listing pods of service %s in namespace %s: %s", prometheusServiceName, osmNamespace, err)
+	}
+	var prometheusPod *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			prometheusPod = &pods.Items[i]
+			break
+		}
+	}
+	if prometheusPod == nil {
+		return nil, errors.Errorf("No running Prometheus pod available in namespace %s", osmNamespace)
+	}
+
+	dialer, err := k8s.DialerToPod(config, clientSet, prometheusPod.Name, prometheusPod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	portForwarder, err := k8s.NewPortForwarder(dialer, fmt.Sprintf("%d:%d", localPort, svc.Spec.Ports[0].TargetPort.IntValue()))
+	if err != nil {
+		return nil, errors.Errorf("Error setting up port forwarding: %s", err)
+	}
+
+	var report []EgressTrafficSummary
+	err = portForwarder.Start(func(pf *k8s.PortForwarder) error {
+		defer pf.Stop()
+		report, err = queryEgressTraffic(fmt.Sprintf("http://localhost:%d", localPort), window)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Errorf("Error retrieving egress traffic from Prometheus: %s", err)
+	}
+
+	return report, nil
+}
+
+// queryEgressTraffic aggregates the egress traffic over the given time window from the Prometheus API at the given URL
+func queryEgressTraffic(prometheusURL string, window string) ([]EgressTrafficSummary, error) {
+	summaries := make(map[egressTrafficKey]*EgressTrafficSummary)
+
+	metrics := []struct {
+		name  string
+		value func(*EgressTrafficSummary) *float64
+	}{
+		{"envoy_cluster_upstream_rq_total", func(s *EgressTrafficSummary) *float64 { return &s.Requests }},
+		{"envoy_cluster_upstream_cx_total", func(s *EgressTrafficSummary) *float64 { return &s.Connections }},
+		{"envoy_cluster_upstream_cx_tx_bytes_total", func(s *EgressTrafficSummary) *float64 { return &s.BytesSent }},
+		{"envoy_cluster_upstream_cx_rx_bytes_total", func(s *EgressTrafficSummary) *float64 { return &s.BytesReceived }},
+	}
+
+	for _, metric := range metrics {
+		query := fmt.Sprintf("sum by (%s, %s, %s, %s) (increase(%s{%s}[%s]))",
+			envoy.EgressStatsTagSourceNamespace, envoy.EgressStatsTagSourceServiceAccount, envoy.EgressStatsTagHost, envoy.EgressStatsTagPort,
+			metric.name, egressClusterNameSelector, window)
+
+		resp, err := queryPrometheus(prometheusURL, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range resp.Data.Result {
+			key := egressTrafficKey{
+				sourceNamespace:      result.Metric[envoy.EgressStatsTagSourceNamespace],
+				sourceServiceAccount: result.Metric[envoy.EgressStatsTagSourceServiceAccount],
+				host:                 envoy.DecodeEgressStatsTag(result.Metric[envoy.EgressStatsTagHost]),
+				port:                 result.Metric[envoy.EgressStatsTagPort],
+			}
+			value, err := parseSampleValue(result.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			summary, ok := summaries[key]
+			if !ok {
+				summary = &EgressTrafficSummary{
+					SourceNamespace:      key.sourceNamespace,
+					SourceServiceAccount: key.sourceServiceAccount,
+					Host:                 key.host,
+					Port:                 key.port,
+				}
+				summaries[key] = summary
+			}
+			*metric.value(summary) += value
+		}
+	}
+
+	var report []EgressTrafficSummary
+	for _, summary := range summaries {
+		report = append(report, *summary)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.SourceNamespace != b.SourceNamespace {
+			return a.SourceNamespace < b.SourceNamespace
+		}
+		if a.SourceServiceAccount != b.SourceServiceAccount {
+			return a.SourceServiceAccount < b.SourceServiceAccount
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		return a.Port < b.Port
+	})
+
+	return report, nil
+}
+
+// queryPrometheus runs the given instant query against the Prometheus API at the given URL
+func queryPrometheus(prometheusURL string, query string) (*prometheusQueryResponse, error) {
+	queryURL := fmt.Sprintf("%s%s?%s", prometheusURL, prometheusQueryPath, url.Values{"query": []string{query}}.Encode())
+
+	// #nosec G107: Potential HTTP request made with variable url
+	resp, err := http.Get(queryURL)
+	if err != nil {
+		return nil, errors.Errorf("Error fetching url %s: %s", queryURL, err)
+	}
+	//nolint: errcheck
+	//#nosec G307
+	defer resp.Body.Close()
+
+	var result prometheusQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Errorf("Error decoding Prometheus response: %s", err)
+	}
+	if result.Status != "success" {
+		return nil, errors.Errorf("Prometheus query %q failed: %s", query, result.Error)
+	}
+
+	return &result, nil
+}
+
+// parseSampleValue parses the value of a Prometheus instant vector sample, which is a [timestamp, "value"] pair
+func parseSampleValue(sample []interface{}) (float64, error) {
+	if len(sample) != 2 {
+		return 0, errors.Errorf("Invalid Prometheus sample %v", sample)
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, errors.Errorf("Invalid Prometheus sample value %v", sample[1])
+	}
+	return strconv.ParseFloat(value, 64)
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestQueryEgressTraffic(t *testing.T) {
+	assert := tassert.New(t)
+
+	sample := func(ns, sa, host, port, value string) map[string]interface{} {
+		return map[string]interface{}{
+			"metric": map[string]string{
+				"osm_egress_source_namespace":       ns,
+				"osm_egress_source_service_account": sa,
+				"osm_egress_host":                   host,
+				"osm_egress_port":                   port,
+			},
+			"value": []interface{}{1633072800.0, value},
+		}
+	}
+	results := map[string][]map[string]interface{}{
+		"envoy_cluster_upstream_rq_total": {
+			sample("ns-1", "sa-1", "httpbin_org", "80", "10"),
+		},
+		"envoy_cluster_upstream_cx_total": {
+			sample("ns-1", "sa-1", "httpbin_org", "80", "2"),
+			sample("ns-2", "sa-2", "*", "3306", "5"),
+		},
+		"envoy_cluster_upstream_cx_tx_bytes_total": {
+			sample("ns-2", "sa-2", "*", "3306", "1024"),
+		},
+		"envoy_cluster_upstream_cx_rx_bytes_total": {},
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(prometheusQueryPath, r.URL.Path)
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+
+		var result []map[string]interface{}
+		for metric, samples := range results {
+			if strings.Contains(query, metric+"{") {
+				result = samples
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"resultType": "vector",
+				"result":     result,
+			},
+		})
+	}))
+	defer server.Close()
+
+	report, err := queryEgressTraffic(server.URL, "1h")
+	assert.Nil(err)
+	assert.Equal([]EgressTrafficSummary{
+		{
+			SourceNamespace:      "ns-1",
+			SourceServiceAccount: "sa-1",
+			Host:                 "httpbin.org",
+			Port:                 "80",
+			Requests:             10,
+			Connections:          2,
+		},
+		{
+			SourceNamespace:      "ns-2",
+			SourceServiceAccount: "sa-2",
+			Host:                 "*",
+			Port:                 "3306",
+			Connections:          5,
+			BytesSent:            1024,
+		},
+	}, report)
+
+	assert.Len(queries, 4)
+	assert.Equal(`sum by (osm_egress_source_namespace, osm_egress_source_service_account, osm_egress_host, osm_egress_port) (increase(envoy_cluster_upstream_rq_total{envoy_cluster_name="osm-egress"}[1h]))`, queries[0])
+}
+
+func TestQueryEgressTrafficError(t *testing.T) {
+	assert := tassert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "error",
+			"error":  "invalid parameter 'query'",
+		})
+	}))
+	defer server.Close()
+
+	report, err := queryEgressTraffic(server.URL, "not-a-duration")
+	assert.NotNil(err)
+	assert.Nil(report)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/injector"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/webhook"
+)
+
+// PreviewSidecarInjection returns the result of previewing the sidecar injection of each of the given JSON manifests,
+// in the given namespace when a manifest does not specify one. The injection is previewed by the sidecar injector
+// deployed by OSM in the given OSM namespace, whose preview server is port forwarded to.
+func PreviewSidecarInjection(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, localPort uint16, manifests [][]byte, namespace string) ([]injector.InjectionResult, error) {
+	var injectorPod *corev1.Pod
+	if pods := k8s.GetOSMInjectorPods(clientSet, osmNamespace); pods != nil {
+		for i := range pods.Items {
+			if pods.Items[i].Status.Phase == corev1.PodRunning {
+				injectorPod = &pods.Items[i]
+				break
+			}
+		}
+	}
+	if injectorPod == nil {
+		return nil, errors.Errorf("No running %s pod available in namespace %s", constants.OSMInjectorName, osmNamespace)
+	}
+
+	dialer, err := k8s.DialerToPod(config, clientSet, injectorPod.Name, injectorPod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	portForwarder, err := k8s.NewPortForwarder(dialer, fmt.Sprintf("%d:%d", localPort, constants.InjectorPreviewPort))
+	if err != nil {
+		return nil, errors.Errorf("Error setting up port forwarding: %s", err)
+	}
+
+	var results []injector.InjectionResult
+	err = portForwarder.Start(func(pf *k8s.PortForwarder) error {
+		defer pf.Stop()
+		injectURL := fmt.Sprintf("http://localhost:%d%s?%s", localPort, injector.WebhookInjectPath,
+			url.Values{injector.WebhookInjectNamespaceKey: []string{namespace}}.Encode())
+		for _, manifest := range manifests {
+			result, err := previewSidecarInjection(http.DefaultClient, injectURL, manifest)
+			if err != nil {
+				return err
+			}
+			results = append(results, *result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Errorf("Error previewing sidecar injection: %s", err)
+	}
+
+	return results, nil
+}
+
+// previewSidecarInjection previews the sidecar injection of the given JSON manifest with the sidecar injector at the given URL
+func previewSidecarInjection(client *http.Client, injectURL string, manifest []byte) (*injector.InjectionResult, error) {
+	resp, err := client.Post(injectURL, webhook.ContentTypeJSON, bytes.NewReader(manifest))
+	if err != nil {
+		return nil, errors.Errorf("Error fetching url %s: %s", injectURL, err)
+	}
+	//nolint: errcheck
+	//#nosec G307
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Errorf("Error reading HTTP response: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s", bytes.TrimSpace(body))
+	}
+
+	var result injector.InjectionResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, errors.Errorf("Error decoding sidecar injection preview: %s", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,359 @@
+package cni
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// Run installs the OSM CNI plugin on the node, and keeps it installed until the given channel is closed,
+// after which the plugin is uninstalled
+func (i *Installer) Run(stop <-chan struct{}) error {
+	if err := i.installBinary(); err != nil {
+		return err
+	}
+	if err := i.install(); err != nil {
+		return err
+	}
+	log.Info().Msgf("Installed the OSM CNI plugin in %s and %s", i.BinDir, i.ConfDir)
+
+	// The pods injected with the sidecar are only scheduled on the node once the plugin is installed
+	if err := i.labelNode(true); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(installInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			// No pod injected with the sidecar is scheduled on the node once the plugin is uninstalled
+			if err := i.labelNode(false); err != nil {
+				return err
+			}
+			if err := i.uninstall(); err != nil {
+				return err
+			}
+			log.Info().Msgf("Uninstalled the OSM CNI plugin from %s and %s", i.BinDir, i.ConfDir)
+			return nil
+		case <-ticker.C:
+			if err := i.install(); err != nil {
+				log.Error().Err(err).Msg("Error reconciling the installation of the OSM CNI plugin")
+			}
+		}
+	}
+}
+
+// installBinary copies the running binary, which is also the plugin binary, to the CNI plugin binaries directory
+func (i *Installer) installBinary() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Errorf("Error locating the %s binary: %s", PluginName, err)
+	}
+	binary, err := os.Open(filepath.Clean(executable))
+	if err != nil {
+		return errors.Errorf("Error reading the %s binary: %s", PluginName, err)
+	}
+	//nolint: errcheck
+	//#nosec G307
+	defer binary.Close()
+
+	if err := writeFileAtomic(filepath.Join(i.BinDir, PluginName), binary, 0755); err != nil {
+		return errors.Errorf("Error copying the %s binary to %s: %s", PluginName, i.BinDir, err)
+	}
+	return nil
+}
+
+// install writes the kubeconfig of the plugin, and chains the plugin to the CNI network configuration used by
+// the container runtime
+func (i *Installer) install() error {
+	kubeconfigPath := filepath.Join(i.ConfDir, kubeconfigFileName)
+	kubeconfig, err := newInClusterKubeconfig()
+	if err != nil {
+		return errors.Errorf("Error generating the %s kubeconfig: %s", PluginName, err)
+	}
+	// The kubeconfig is rewritten when the token of the plugin is rotated
+	if current, err := ioutil.ReadFile(filepath.Clean(kubeconfigPath)); err != nil || string(current) != string(kubeconfig) {
+		if err := writeFileAtomic(kubeconfigPath, bytes.NewReader(kubeconfig), 0600); err != nil {
+			return errors.Errorf("Error writing the %s kubeconfig to %s: %s", PluginName, i.ConfDir, err)
+		}
+	}
+
+	confFile, err := findPrimaryConfFile(i.ConfDir)
+	if err != nil {
+		return err
+	}
+	conf, err := ioutil.ReadFile(filepath.Clean(confFile))
+	if err != nil {
+		return errors.Errorf("Error reading CNI network configuration %s: %s", confFile, err)
+	}
+	chainedConf, err := chainPlugin(conf, PluginConf{
+		Type:              PluginName,
+		Kubeconfig:        kubeconfigPath,
+		OSMNamespace:      i.OSMNamespace,
+		ExcludeNamespaces: i.ExcludeNamespaces,
+	})
+	if err != nil {
+		return errors.Errorf("Error chaining the %s plugin to CNI network configuration %s: %s", PluginName, confFile, err)
+	}
+	if jsonEqual(chainedConf, conf) {
+		return nil
+	}
+
+	// A network configuration with a single plugin is replaced by a network configuration list, in which plugins can be chained
+	chainedConfFile := confFile
+	if filepath.Ext(confFile) != ".conflist" {
+		chainedConfFile = strings.TrimSuffix(confFile, filepath.Ext(confFile)) + ".conflist"
+	}
+	if err := writeFileAtomic(chainedConfFile, bytes.NewReader(chainedConf), 0644); err != nil {
+		return errors.Errorf("Error writing CNI network configuration %s: %s", chainedConfFile, err)
+	}
+	if chainedConfFile != confFile {
+		if err := os.Remove(confFile); err != nil {
+			return errors.Errorf("Error removing CNI network configuration %s: %s", confFile, err)
+		}
+	}
+	log.Info().Msgf("Chained the %s plugin to CNI network configuration %s", PluginName, chainedConfFile)
+
+	return nil
+}
+
+// uninstall removes the plugin from the CNI network configuration, and removes the plugin binary and its kubeconfig from the node
+func (i *Installer) uninstall() error {
+	confFile, err := findPrimaryConfFile(i.ConfDir)
+	if err != nil {
+		return err
+	}
+	// The plugin can only be chained in a network configuration list
+	if filepath.Ext(confFile) == ".conflist" {
+		conf, err := ioutil.ReadFile(filepath.Clean(confFile))
+		if err != nil {
+			return errors.Errorf("Error reading CNI network configuration %s: %s", confFile, err)
+		}
+		unchainedConf, err := unchainPlugin(conf)
+		if err != nil {
+			return errors.Errorf("Error removing the %s plugin from CNI network configuration %s: %s", PluginName, confFile, err)
+		}
+		if !jsonEqual(unchainedConf, conf) {
+			if err := writeFileAtomic(confFile, bytes.NewReader(unchainedConf), 0644); err != nil {
+				return errors.Errorf("Error writing CNI network configuration %s: %s", confFile, err)
+			}
+		}
+	}
+
+	for _, file := range []string{filepath.Join(i.ConfDir, kubeconfigFileName), filepath.Join(i.BinDir, PluginName)} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return errors.Errorf("Error removing %s: %s", file, err)
+		}
+	}
+
+	return nil
+}
+
+// labelNode sets the CNINodeLabel label on the node the plugin is installed on if installed is true, and removes it otherwise
+func (i *Installer) labelNode(installed bool) error {
+	var value interface{}
+	if installed {
+		value = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				constants.CNINodeLabel: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kubeAPITimeout)
+	defer cancel()
+
+	if _, err := i.KubeClient.CoreV1().Nodes().Patch(ctx, i.NodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return errors.Errorf("Error updating the %s label of node %s: %s", constants.CNINodeLabel, i.NodeName, err)
+	}
+	return nil
+}
+
+// findPrimaryConfFile returns the CNI network configuration file used by the container runtime,
+// which is the first one in lexicographic order in the given directory
+func findPrimaryConfFile(confDir string) (string, error) {
+	files, err := ioutil.ReadDir(confDir)
+	if err != nil {
+		return "", errors.Errorf("Error reading CNI network configuration directory %s: %s", confDir, err)
+	}
+
+	var confFiles []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		switch filepath.Ext(file.Name()) {
+		case ".conf", ".conflist", ".json":
+			confFiles = append(confFiles, file.Name())
+		}
+	}
+	if len(confFiles) == 0 {
+		return "", errors.Errorf("No CNI network configuration found in %s", confDir)
+	}
+
+	sort.Strings(confFiles)
+	return filepath.Join(confDir, confFiles[0]), nil
+}
+
+// chainPlugin appends the given plugin configuration to the plugins of the given CNI network configuration,
+// replacing the existing configuration of the plugin. A network configuration with a single plugin is converted
+// to a network configuration list.
+func chainPlugin(conf []byte, pluginConf PluginConf) ([]byte, error) {
+	confList, err := parseConfList(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	rawPluginConf, err := json.Marshal(pluginConf)
+	if err != nil {
+		return nil, err
+	}
+	var plugin map[string]interface{}
+	if err := json.Unmarshal(rawPluginConf, &plugin); err != nil {
+		return nil, err
+	}
+
+	plugins := removePlugin(confList["plugins"].([]interface{}))
+	confList["plugins"] = append(plugins, plugin)
+
+	return json.MarshalIndent(confList, "", "  ")
+}
+
+// unchainPlugin removes the configuration of the OSM CNI plugin from the given CNI network configuration list
+func unchainPlugin(conf []byte) ([]byte, error) {
+	confList, err := parseConfList(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	confList["plugins"] = removePlugin(confList["plugins"].([]interface{}))
+
+	return json.MarshalIndent(confList, "", "  ")
+}
+
+// parseConfList parses the given CNI network configuration as a network configuration list
+func parseConfList(conf []byte) (map[string]interface{}, error) {
+	var confList map[string]interface{}
+	if err := json.Unmarshal(conf, &confList); err != nil {
+		return nil, err
+	}
+
+	if _, ok := confList["plugins"]; !ok {
+		// A network configuration with a single plugin
+		return map[string]interface{}{
+			"cniVersion": confList["cniVersion"],
+			"name":       confList["name"],
+			"plugins":    []interface{}{confList},
+		}, nil
+	}
+
+	if _, ok := confList["plugins"].([]interface{}); !ok {
+		return nil, errors.New("Invalid plugins in CNI network configuration list")
+	}
+	return confList, nil
+}
+
+// removePlugin returns the given plugins without the OSM CNI plugin
+func removePlugin(plugins []interface{}) []interface{} {
+	var filtered []interface{}
+	for _, plugin := range plugins {
+		if p, ok := plugin.(map[string]interface{}); ok && p["type"] == PluginName {
+			continue
+		}
+		filtered = append(filtered, plugin)
+	}
+	return filtered
+}
+
+// jsonEqual returns true if the given JSON documents are equal, regardless of their formatting
+func jsonEqual(a, b []byte) bool {
+	var aValue, bValue interface{}
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// newInClusterKubeconfig returns a kubeconfig with the in-cluster credentials of the pod installing the plugin
+func newInClusterKubeconfig() ([]byte, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	caData, err := ioutil.ReadFile(config.TLSClientConfig.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	token, err := ioutil.ReadFile(config.BearerTokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[PluginName] = &clientcmdapi.Cluster{
+		Server:                   config.Host,
+		CertificateAuthorityData: caData,
+	}
+	kubeconfig.AuthInfos[PluginName] = &clientcmdapi.AuthInfo{
+		Token: string(token),
+	}
+	kubeconfig.Contexts[PluginName] = &clientcmdapi.Context{
+		Cluster:  PluginName,
+		AuthInfo: PluginName,
+	}
+	kubeconfig.CurrentContext = PluginName
+
+	return clientcmd.Write(*kubeconfig)
+}
+
+// writeFileAtomic writes the given data to the given file, such that readers never observe a partially written file
+func writeFileAtomic(file string, data io.Reader, perm os.FileMode) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	//nolint: errcheck
+	//#nosec G307
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := io.Copy(tmpFile, data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Chmod(perm); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), file)
+}
@@ -0,0 +1,133 @@
+package cni
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestChainPlugin(t *testing.T) {
+	pluginConf := PluginConf{
+		Type:              PluginName,
+		Kubeconfig:        "/etc/cni/net.d/osm-cni.kubeconfig",
+		OSMNamespace:      "osm-system",
+		ExcludeNamespaces: []string{"kube-system"},
+	}
+
+	testCases := []struct {
+		name     string
+		conf     string
+		expected string
+	}{
+		{
+			name:     "network configuration list",
+			conf:     `{"cniVersion":"0.4.0","name":"k8s-pod-network","plugins":[{"type":"calico"},{"type":"portmap"}]}`,
+			expected: `{"cniVersion":"0.4.0","name":"k8s-pod-network","plugins":[{"type":"calico"},{"type":"portmap"},{"type":"osm-cni","kubeconfig":"/etc/cni/net.d/osm-cni.kubeconfig","osmNamespace":"osm-system","excludeNamespaces":["kube-system"]}]}`,
+		},
+		{
+			name:     "network configuration list with the plugin already chained",
+			conf:     `{"cniVersion":"0.4.0","name":"k8s-pod-network","plugins":[{"type":"calico"},{"type":"osm-cni","kubeconfig":"/old"}]}`,
+			expected: `{"cniVersion":"0.4.0","name":"k8s-pod-network","plugins":[{"type":"calico"},{"type":"osm-cni","kubeconfig":"/etc/cni/net.d/osm-cni.kubeconfig","osmNamespace":"osm-system","excludeNamespaces":["kube-system"]}]}`,
+		},
+		{
+			name:     "network configuration with a single plugin",
+			conf:     `{"cniVersion":"0.3.1","name":"bridge","type":"bridge","bridge":"cni0"}`,
+			expected: `{"cniVersion":"0.3.1","name":"bridge","plugins":[{"cniVersion":"0.3.1","name":"bridge","type":"bridge","bridge":"cni0"},{"type":"osm-cni","kubeconfig":"/etc/cni/net.d/osm-cni.kubeconfig","osmNamespace":"osm-system","excludeNamespaces":["kube-system"]}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := chainPlugin([]byte(tc.conf), pluginConf)
+			assert.Nil(err)
+			assert.JSONEq(tc.expected, string(actual))
+
+			unchained, err := unchainPlugin(actual)
+			assert.Nil(err)
+			assert.NotContains(string(unchained), PluginName)
+		})
+	}
+
+	_, err := chainPlugin([]byte(`{"plugins":"invalid"}`), pluginConf)
+	tassert.NotNil(t, err)
+}
+
+func TestFindPrimaryConfFile(t *testing.T) {
+	assert := tassert.New(t)
+
+	confDir, err := ioutil.TempDir("", "osm-cni")
+	assert.Nil(err)
+	defer os.RemoveAll(confDir) //nolint: errcheck
+
+	_, err = findPrimaryConfFile(confDir)
+	assert.NotNil(err)
+
+	for _, file := range []string{"20-flannel.conflist", "10-calico.conflist", "05-kubeconfig", ".10-calico.conflist123"} {
+		assert.Nil(ioutil.WriteFile(filepath.Join(confDir, file), []byte("{}"), 0600))
+	}
+	confFile, err := findPrimaryConfFile(confDir)
+	assert.Nil(err)
+	assert.Equal(filepath.Join(confDir, "10-calico.conflist"), confFile)
+}
+
+func TestUninstall(t *testing.T) {
+	assert := tassert.New(t)
+
+	dir, err := ioutil.TempDir("", "osm-cni")
+	assert.Nil(err)
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	i := &Installer{BinDir: filepath.Join(dir, "bin"), ConfDir: filepath.Join(dir, "net.d")}
+	assert.Nil(os.Mkdir(i.BinDir, 0700))
+	assert.Nil(os.Mkdir(i.ConfDir, 0700))
+
+	confFile := filepath.Join(i.ConfDir, "10-calico.conflist")
+	assert.Nil(writeFileAtomic(confFile, strings.NewReader(`{"name":"k8s-pod-network","plugins":[{"type":"calico"},{"type":"osm-cni"}]}`), 0644))
+	assert.Nil(writeFileAtomic(filepath.Join(i.ConfDir, kubeconfigFileName), strings.NewReader("kubeconfig"), 0600))
+	assert.Nil(writeFileAtomic(filepath.Join(i.BinDir, PluginName), strings.NewReader("binary"), 0755))
+
+	assert.Nil(i.uninstall())
+
+	conf, err := ioutil.ReadFile(confFile)
+	assert.Nil(err)
+	assert.JSONEq(`{"name":"k8s-pod-network","plugins":[{"type":"calico"}]}`, string(conf))
+	assert.NoFileExists(filepath.Join(i.ConfDir, kubeconfigFileName))
+	assert.NoFileExists(filepath.Join(i.BinDir, PluginName))
+}
+
+func TestLabelNode(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node",
+			Labels: map[string]string{"kubernetes.io/os": "linux"},
+		},
+	})
+	i := &Installer{NodeName: "node", KubeClient: kubeClient}
+
+	assert.Nil(i.labelNode(true))
+	node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "node", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"kubernetes.io/os": "linux", constants.CNINodeLabel: "true"}, node.Labels)
+
+	assert.Nil(i.labelNode(false))
+	node, err = kubeClient.CoreV1().Nodes().Get(context.TODO(), "node", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"kubernetes.io/os": "linux"}, node.Labels)
+
+	i.NodeName = "unknown"
+	assert.NotNil(i.labelNode(true))
+}
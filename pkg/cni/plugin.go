@@ -0,0 +1,186 @@
+package cni
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/injector"
+)
+
+// errorCode is the code of the errors returned by the plugin to the container runtime.
+// The codes below 100 are reserved by the CNI specification.
+const errorCode = 100
+
+// Run runs the CNI command given by the CNI_COMMAND environment variable, with the network configuration read from
+// the given reader, and writes the result to the given writer as specified by the CNI specification.
+// On error, the error is written to the given writer and returned, and the plugin must exit with a non-zero code.
+func Run(stdin io.Reader, stdout io.Writer) error {
+	p := &plugin{
+		getenv:        os.Getenv,
+		newKubeClient: newKubeClient,
+		runInNetNS:    runInNetNS,
+	}
+
+	err := p.run(stdin, stdout)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error running CNI command %s for container %s", os.Getenv("CNI_COMMAND"), os.Getenv("CNI_CONTAINERID"))
+		_ = json.NewEncoder(stdout).Encode(map[string]interface{}{
+			"cniVersion": supportedVersions[len(supportedVersions)-1],
+			"code":       errorCode,
+			"msg":        err.Error(),
+		})
+	}
+	return err
+}
+
+// run runs the CNI command given by the CNI_COMMAND environment variable
+func (p *plugin) run(stdin io.Reader, stdout io.Writer) error {
+	command := p.getenv("CNI_COMMAND")
+	if command == cmdVersion {
+		return json.NewEncoder(stdout).Encode(map[string]interface{}{
+			"cniVersion":        supportedVersions[len(supportedVersions)-1],
+			"supportedVersions": supportedVersions,
+		})
+	}
+
+	stdinData, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		return errors.Errorf("Error reading the network configuration: %s", err)
+	}
+	var conf PluginConf
+	if err := json.Unmarshal(stdinData, &conf); err != nil {
+		return errors.Errorf("Error parsing the network configuration: %s", err)
+	}
+
+	switch command {
+	case cmdAdd:
+		if err := p.add(&conf); err != nil {
+			return err
+		}
+		return writeResult(stdout, &conf)
+
+	case cmdDel, cmdCheck:
+		// The rules are deleted along with the network namespace of the pod, and are not checked
+		return nil
+
+	default:
+		return errors.Errorf("Unsupported CNI command %q", command)
+	}
+}
+
+// add programs the iptables rules recorded in the annotation of the pod whose network sandbox is created, if the
+// signature of the annotation shows it was set by the sidecar injector. Any error fails the creation of the network
+// sandbox, so that the pod never runs without its traffic being intercepted.
+func (p *plugin) add(conf *PluginConf) error {
+	args := parseArgs(p.getenv("CNI_ARGS"))
+	namespace, name := args["K8S_POD_NAMESPACE"], args["K8S_POD_NAME"]
+	if namespace == "" || name == "" {
+		log.Debug().Msgf("Container %s is not a Kubernetes pod, skipping", p.getenv("CNI_CONTAINERID"))
+		return nil
+	}
+	for _, ns := range conf.ExcludeNamespaces {
+		if ns == namespace {
+			log.Debug().Msgf("Namespace %s is excluded, skipping pod %s/%s", namespace, namespace, name)
+			return nil
+		}
+	}
+
+	kubeClient, err := p.newKubeClient(conf.Kubeconfig)
+	if err != nil {
+		return errors.Errorf("Error creating Kubernetes client from kubeconfig %s: %s", conf.Kubeconfig, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kubeAPITimeout)
+	defer cancel()
+
+	pod, err := kubeClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Errorf("Error retrieving pod %s/%s: %s", namespace, name, err)
+	}
+
+	annotation, ok := pod.Annotations[constants.IptablesConfigAnnotation]
+	if !ok {
+		log.Debug().Msgf("Pod %s/%s does not have the %s annotation, skipping", namespace, name, constants.IptablesConfigAnnotation)
+		return nil
+	}
+	// Only the iptables config recorded by the sidecar injector is trusted
+	signature := pod.Annotations[constants.IptablesConfigSignatureAnnotation]
+	keySecret, err := kubeClient.CoreV1().Secrets(conf.OSMNamespace).Get(ctx, constants.IptablesConfigKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Errorf("Error retrieving secret %s/%s: %s", conf.OSMNamespace, constants.IptablesConfigKeySecretName, err)
+	}
+	if !injector.VerifyIptablesConfig(keySecret.Data[injector.IptablesConfigKeySecretKey], namespace, annotation, signature) {
+		return errors.Errorf("The %s annotation on pod %s/%s was not set by the sidecar injector", constants.IptablesConfigAnnotation, namespace, name)
+	}
+
+	var iptablesConfig injector.IptablesConfig
+	if err := json.Unmarshal([]byte(annotation), &iptablesConfig); err != nil {
+		return errors.Errorf("Invalid %s annotation on pod %s/%s: %s", constants.IptablesConfigAnnotation, namespace, name, err)
+	}
+
+	commands := injector.GenerateIptablesCommands(iptablesConfig)
+	if err := p.runInNetNS(ctx, p.getenv("CNI_NETNS"), commands); err != nil {
+		return errors.Errorf("Error programming the iptables rules of pod %s/%s: %s", namespace, name, err)
+	}
+
+	log.Info().Msgf("Programmed the iptables rules of pod %s/%s", namespace, name)
+	return nil
+}
+
+// writeResult writes the result of the previous plugin in the chain, which the OSM CNI plugin does not change
+func writeResult(stdout io.Writer, conf *PluginConf) error {
+	if len(conf.PrevResult) > 0 {
+		_, err := stdout.Write(conf.PrevResult)
+		return err
+	}
+	return json.NewEncoder(stdout).Encode(map[string]interface{}{
+		"cniVersion": conf.CNIVersion,
+	})
+}
+
+// parseArgs parses the 'KEY1=VALUE1;KEY2=VALUE2' arguments given by the CNI_ARGS environment variable
+func parseArgs(cniArgs string) map[string]string {
+	args := make(map[string]string)
+	for _, arg := range strings.Split(cniArgs, ";") {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) == 2 {
+			args[kv[0]] = kv[1]
+		}
+	}
+	return args
+}
+
+// newKubeClient returns a Kubernetes client for the given kubeconfig file
+func newKubeClient(kubeconfig string) (kubernetes.Interface, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = kubeAPITimeout
+	return kubernetes.NewForConfig(config)
+}
+
+// runInNetNS runs the given shell commands in the given network namespace, using the tools of the node
+func runInNetNS(ctx context.Context, netns string, commands string) error {
+	if netns == "" {
+		return errors.New("CNI_NETNS is not set")
+	}
+
+	// #nosec G204: Subprocess launched with variable
+	cmd := exec.CommandContext(ctx, "nsenter", "--net="+netns, "/bin/sh", "-c", commands)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.Errorf("%s: %s", err, output)
+	}
+	return nil
+}
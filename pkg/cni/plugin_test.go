@@ -0,0 +1,226 @@
+package cni
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/injector"
+)
+
+func TestRun(t *testing.T) {
+	key := []byte("key")
+	signedAnnotations := func(namespace string, iptablesConfig string) map[string]string {
+		return map[string]string{
+			constants.IptablesConfigAnnotation:          iptablesConfig,
+			constants.IptablesConfigSignatureAnnotation: injector.SignIptablesConfig(key, namespace, iptablesConfig),
+		}
+	}
+
+	const (
+		netConf    = `{"cniVersion":"0.4.0","name":"k8s-pod-network","type":"osm-cni","kubeconfig":"/etc/cni/net.d/osm-cni.kubeconfig","osmNamespace":"osm-system","excludeNamespaces":["kube-system"],"prevResult":{"cniVersion":"0.4.0","ips":[{"version":"4","address":"10.0.0.5/24"}]}}`
+		prevResult = `{"cniVersion":"0.4.0","ips":[{"version":"4","address":"10.0.0.5/24"}]}`
+	)
+
+	testCases := []struct {
+		name             string
+		env              map[string]string
+		stdin            string
+		pod              *corev1.Pod
+		runInNetNSErr    error
+		expectedErr      bool
+		expectedStdout   string
+		expectedCommands []string
+	}{
+		{
+			name: "pod with the iptables config annotation",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_NETNS":   "/var/run/netns/cni-1",
+				"CNI_ARGS":    "IgnoreUnknown=1;K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod;K8S_POD_INFRA_CONTAINER_ID=1",
+			},
+			stdin: netConf,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod",
+					Namespace:   "ns",
+					Annotations: signedAnnotations("ns", `{"outboundIPRangeExclusionList":["10.1.0.0/16"],"inboundPortExclusionList":[8080]}`),
+				},
+			},
+			expectedStdout: prevResult,
+			expectedCommands: []string{
+				"iptables-restore --noflush",
+				"-I OSM_PROXY_OUTBOUND -d 10.1.0.0/16 -j RETURN",
+				"-I OSM_PROXY_INBOUND -p tcp --match multiport --dports 8080 -j RETURN",
+			},
+		},
+		{
+			name: "pod without the iptables config annotation",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_NETNS":   "/var/run/netns/cni-1",
+				"CNI_ARGS":    "K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod",
+			},
+			stdin:          netConf,
+			pod:            &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"}},
+			expectedStdout: prevResult,
+		},
+		{
+			name: "pod in an excluded namespace",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_ARGS":    "K8S_POD_NAMESPACE=kube-system;K8S_POD_NAME=pod",
+			},
+			stdin:          netConf,
+			expectedStdout: prevResult,
+		},
+		{
+			name: "container that is not a pod",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+			},
+			stdin:          `{"cniVersion":"0.4.0","name":"k8s-pod-network","type":"osm-cni"}`,
+			expectedStdout: `{"cniVersion":"0.4.0"}` + "\n",
+		},
+		{
+			name: "pod not found",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_ARGS":    "K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod",
+			},
+			stdin:       netConf,
+			expectedErr: true,
+		},
+		{
+			name: "invalid iptables config annotation",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_ARGS":    "K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod",
+			},
+			stdin: netConf,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod",
+					Namespace:   "ns",
+					Annotations: signedAnnotations("ns", `invalid`),
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "iptables config annotation without signature",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_ARGS":    "K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod",
+			},
+			stdin: netConf,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod",
+					Namespace:   "ns",
+					Annotations: map[string]string{constants.IptablesConfigAnnotation: `{}`},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "iptables config annotation signed for another namespace",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_ARGS":    "K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod",
+			},
+			stdin: netConf,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod",
+					Namespace:   "ns",
+					Annotations: signedAnnotations("other", `{}`),
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "error programming the iptables rules",
+			env: map[string]string{
+				"CNI_COMMAND": cmdAdd,
+				"CNI_ARGS":    "K8S_POD_NAMESPACE=ns;K8S_POD_NAME=pod",
+			},
+			stdin: netConf,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod",
+					Namespace:   "ns",
+					Annotations: signedAnnotations("ns", `{}`),
+				},
+			},
+			runInNetNSErr: errors.New("iptables-restore: not found"),
+			expectedErr:   true,
+		},
+		{
+			name:  "DEL command",
+			env:   map[string]string{"CNI_COMMAND": cmdDel},
+			stdin: netConf,
+		},
+		{
+			name:           "VERSION command",
+			env:            map[string]string{"CNI_COMMAND": cmdVersion},
+			expectedStdout: `{"cniVersion":"1.0.0","supportedVersions":["0.3.0","0.3.1","0.4.0","1.0.0"]}` + "\n",
+		},
+		{
+			name:        "invalid network configuration",
+			env:         map[string]string{"CNI_COMMAND": cmdAdd},
+			stdin:       `invalid`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.IptablesConfigKeySecretName, Namespace: "osm-system"},
+				Data:       map[string][]byte{injector.IptablesConfigKeySecretKey: key},
+			})
+			if tc.pod != nil {
+				_, err := kubeClient.CoreV1().Pods(tc.pod.Namespace).Create(context.TODO(), tc.pod, metav1.CreateOptions{})
+				assert.Nil(err)
+			}
+
+			var commands string
+			p := &plugin{
+				getenv: func(key string) string {
+					return tc.env[key]
+				},
+				newKubeClient: func(kubeconfig string) (kubernetes.Interface, error) {
+					assert.Equal("/etc/cni/net.d/osm-cni.kubeconfig", kubeconfig)
+					return kubeClient, nil
+				},
+				runInNetNS: func(_ context.Context, netns string, cmds string) error {
+					assert.Equal(tc.env["CNI_NETNS"], netns)
+					commands = cmds
+					return tc.runInNetNSErr
+				},
+			}
+
+			var stdout bytes.Buffer
+			err := p.run(strings.NewReader(tc.stdin), &stdout)
+			assert.Equal(tc.expectedErr, err != nil)
+			if !tc.expectedErr {
+				assert.Equal(tc.expectedStdout, stdout.String())
+			}
+			for _, expectedCommand := range tc.expectedCommands {
+				assert.Contains(commands, expectedCommand)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package cni implements the OSM CNI plugin and its installation on the nodes. The plugin is chained to the CNI
+// plugin of the cluster, and programs the iptables rules that intercept the traffic of the pods injected with the
+// sidecar when their network sandbox is created, so that the pods do not require a privileged init container.
+package cni
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("osm-cni")
+)
+
+const (
+	// PluginName is the name of the OSM CNI plugin binary, and its type in the CNI network configuration
+	PluginName = "osm-cni"
+
+	// kubeconfigFileName is the name of the kubeconfig file used by the plugin, written in the CNI network configuration directory
+	kubeconfigFileName = "osm-cni.kubeconfig"
+
+	// kubeAPITimeout is the timeout of the requests of the plugin to the Kubernetes API server
+	kubeAPITimeout = 10 * time.Second
+
+	// installInterval is the interval at which the installation of the plugin is reconciled, to chain the plugin again
+	// if the CNI network configuration is rewritten by the CNI plugin of the cluster, and to refresh the token of the plugin
+	installInterval = 10 * time.Second
+)
+
+// CNI commands, given by the CNI_COMMAND environment variable
+const (
+	cmdAdd     = "ADD"
+	cmdDel     = "DEL"
+	cmdCheck   = "CHECK"
+	cmdVersion = "VERSION"
+)
+
+// supportedVersions are the versions of the CNI specification supported by the plugin
+var supportedVersions = []string{"0.3.0", "0.3.1", "0.4.0", "1.0.0"}
+
+// PluginConf is the configuration of the OSM CNI plugin in the CNI network configuration
+type PluginConf struct {
+	// CNIVersion is the version of the CNI specification of the network configuration
+	CNIVersion string `json:"cniVersion,omitempty"`
+
+	// Name is the name of the network
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the plugin, which must be PluginName
+	Type string `json:"type"`
+
+	// Kubeconfig is the path of the kubeconfig file used to retrieve the pods from the Kubernetes API server
+	Kubeconfig string `json:"kubeconfig"`
+
+	// OSMNamespace is the namespace of the control plane, holding the secret of the key the iptables config
+	// annotations of the pods are signed with
+	OSMNamespace string `json:"osmNamespace"`
+
+	// ExcludeNamespaces are the namespaces whose pods are ignored by the plugin
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// PrevResult is the result of the previous plugin in the chain, which is returned unchanged
+	PrevResult json.RawMessage `json:"prevResult,omitempty"`
+}
+
+// plugin implements the CNI commands of the OSM CNI plugin
+type plugin struct {
+	// getenv returns the value of the given environment variable
+	getenv func(key string) string
+
+	// newKubeClient returns a Kubernetes client for the given kubeconfig file
+	newKubeClient func(kubeconfig string) (kubernetes.Interface, error)
+
+	// runInNetNS runs the given shell commands in the given network namespace
+	runInNetNS func(ctx context.Context, netns string, commands string) error
+}
+
+// Installer installs the OSM CNI plugin on a node
+type Installer struct {
+	// BinDir is the directory of the CNI plugin binaries on the node
+	BinDir string
+
+	// ConfDir is the directory of the CNI network configuration files on the node
+	ConfDir string
+
+	// OSMNamespace is the namespace of the control plane
+	OSMNamespace string
+
+	// NodeName is the name of the node the plugin is installed on, which is labeled while the plugin is installed
+	NodeName string
+
+	// KubeClient is the client used to label the node
+	KubeClient kubernetes.Interface
+
+	// ExcludeNamespaces are the namespaces whose pods are ignored by the plugin
+	ExcludeNamespaces []string
+}
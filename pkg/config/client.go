@@ -18,6 +18,8 @@ const (
 	apiGroup = "config.openservicemesh.io"
 
 	multiclusterInformerName = `MulticlusterService`
+
+	sidecarConfigInformerName = `SidecarConfig`
 )
 
 // NewConfigController returns a config.Controller struct related to functionality provided by the resources in the config.openservicemesh.io API group
@@ -26,8 +28,9 @@ func NewConfigController(kubeConfig *rest.Config, kubeController k8s.Controller,
 	informerFactory := configV1alpha1Informers.NewSharedInformerFactory(configClient, k8s.DefaultKubeEventResyncInterval)
 
 	client := client{
-		informer:       informerFactory.Config().V1alpha1().MultiClusterServices(),
-		kubeController: kubeController,
+		informer:              informerFactory.Config().V1alpha1().MultiClusterServices(),
+		sidecarConfigInformer: informerFactory.Config().V1alpha1().SidecarConfigs(),
+		kubeController:        kubeController,
 	}
 
 	shouldObserve := func(obj interface{}) bool {
@@ -51,17 +54,18 @@ func NewConfigController(kubeConfig *rest.Config, kubeController k8s.Controller,
 func (c client) run(stop <-chan struct{}) error {
 	log.Info().Msgf("Starting informers for %s", apiGroup)
 
-	if c.informer == nil {
+	if c.informer == nil || c.sidecarConfigInformer == nil {
 		return errInitInformers
 	}
 
 	go c.informer.Informer().Run(stop)
+	go c.sidecarConfigInformer.Informer().Run(stop)
 
-	log.Info().Msgf("Waiting for %s %s informers' cache to sync", apiGroup, multiclusterInformerName)
-	if !cache.WaitForCacheSync(stop, c.informer.Informer().HasSynced) {
+	log.Info().Msgf("Waiting for %s %s and %s informers' cache to sync", apiGroup, multiclusterInformerName, sidecarConfigInformerName)
+	if !cache.WaitForCacheSync(stop, c.informer.Informer().HasSynced, c.sidecarConfigInformer.Informer().HasSynced) {
 		return errSyncingCaches
 	}
 
-	log.Info().Msgf("Cache sync finished for %s %s informers", apiGroup, multiclusterInformerName)
+	log.Info().Msgf("Cache sync finished for %s %s and %s informers", apiGroup, multiclusterInformerName, sidecarConfigInformerName)
 	return nil
 }
@@ -75,3 +75,17 @@ func (mr *MockControllerMockRecorder) ListMultiClusterServices() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMultiClusterServices", reflect.TypeOf((*MockController)(nil).ListMultiClusterServices))
 }
+
+// ListSidecarConfigs mocks base method.
+func (m *MockController) ListSidecarConfigs(arg0 string) []v1alpha1.SidecarConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSidecarConfigs", arg0)
+	ret0, _ := ret[0].([]v1alpha1.SidecarConfig)
+	return ret0
+}
+
+// ListSidecarConfigs indicates an expected call of ListSidecarConfigs.
+func (mr *MockControllerMockRecorder) ListSidecarConfigs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSidecarConfigs", reflect.TypeOf((*MockController)(nil).ListSidecarConfigs), arg0)
+}
@@ -37,9 +37,12 @@ func (c client) GetMultiClusterService(name, namespace string) *v1alpha1.MultiCl
 		return nil
 	}
 	mcs, ok, err := c.informer.Informer().GetStore().GetByKey(namespace + "/" + name)
-	if err != nil || !ok {
+	if err != nil {
 		log.Error().Str(constants.LogFieldContext, constants.LogContextMulticluster).Err(err).Msgf("Error getting MultiClusterService %s in namespace %s from informer ", name, namespace)
 		return nil
 	}
+	if !ok {
+		return nil
+	}
 	return mcs.(*v1alpha1.MultiClusterService)
 }
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+)
+
+// ListSidecarConfigs returns the SidecarConfig resources in the given namespace
+func (c client) ListSidecarConfigs(namespace string) []v1alpha1.SidecarConfig {
+	var sidecarConfigs []v1alpha1.SidecarConfig
+
+	if !c.kubeController.IsMonitoredNamespace(namespace) {
+		return sidecarConfigs
+	}
+	objs, err := c.sidecarConfigInformer.Lister().SidecarConfigs(namespace).List(labels.Everything())
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing SidecarConfigs in namespace %s", namespace)
+		return sidecarConfigs
+	}
+	for _, sidecarConfig := range objs {
+		sidecarConfigs = append(sidecarConfigs, *sidecarConfig)
+	}
+
+	return sidecarConfigs
+}
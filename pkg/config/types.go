@@ -14,8 +14,9 @@ var (
 
 // client is the type used to represent the Kubernetes client for the multiclusterservice.openservicemesh.io API group
 type client struct {
-	informer       configV1alpha1Informers.MultiClusterServiceInformer
-	kubeController kubernetes.Controller
+	informer              configV1alpha1Informers.MultiClusterServiceInformer
+	sidecarConfigInformer configV1alpha1Informers.SidecarConfigInformer
+	kubeController        kubernetes.Controller
 }
 
 // Controller is the interface for the functionality provided by the resources part of the multiclusterservice.openservicemesh.io API group
@@ -23,4 +24,7 @@ type Controller interface {
 	ListMultiClusterServices() []v1alpha1.MultiClusterService
 	GetMultiClusterService(name, namespace string) *v1alpha1.MultiClusterService
 	GetMultiClusterServiceByServiceAccount(serviceAccount, namespace string) []v1alpha1.MultiClusterService
+
+	// ListSidecarConfigs returns the SidecarConfig resources in the given namespace
+	ListSidecarConfigs(namespace string) []v1alpha1.SidecarConfig
 }
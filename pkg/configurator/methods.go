@@ -3,6 +3,7 @@ package configurator
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -27,6 +28,18 @@ const (
 
 	// maxCertKeyBitSize is the maximum certificate key bit size
 	maxCertKeyBitSize = 4096
+
+	// defaultEgressHostResolutionTTL is the default duration for which the IP addresses of egress hosts are cached
+	defaultEgressHostResolutionTTL = 30 * time.Second
+
+	// defaultSidecarRestartInterval is the default minimum duration between two restarts of workloads running outdated sidecars
+	defaultSidecarRestartInterval = "5m"
+
+	// defaultProtocolDetectionTimeout is the default duration for which the outbound listener waits to detect the protocol of a connection
+	defaultProtocolDetectionTimeout = time.Second
+
+	// minEgressHostResolutionTTL is the minimum duration for which the IP addresses of egress hosts are cached
+	minEgressHostResolutionTTL = 5 * time.Second
 )
 
 // The functions in this file implement the configurator.Configurator interface
@@ -188,6 +201,16 @@ func (c *client) GetInboundPortExclusionList() []int {
 	return c.getMeshConfig().Spec.Traffic.InboundPortExclusionList
 }
 
+// GetOutboundUIDExclusionList returns the list of user IDs whose outbound traffic is excluded from sidecar interception
+func (c *client) GetOutboundUIDExclusionList() []int {
+	return c.getMeshConfig().Spec.Traffic.OutboundUIDExclusionList
+}
+
+// GetOutboundGIDExclusionList returns the list of group IDs whose outbound traffic is excluded from sidecar interception
+func (c *client) GetOutboundGIDExclusionList() []int {
+	return c.getMeshConfig().Spec.Traffic.OutboundGIDExclusionList
+}
+
 // IsPrivilegedInitContainer returns whether init containers should be privileged
 func (c *client) IsPrivilegedInitContainer() bool {
 	return c.getMeshConfig().Spec.Sidecar.EnablePrivilegedInitContainer
@@ -210,6 +233,45 @@ func (c *client) GetProxyResources() corev1.ResourceRequirements {
 	return c.getMeshConfig().Spec.Sidecar.Resources
 }
 
+// GetProxyConcurrency returns the number of worker threads of the proxies, 0 if unset
+func (c *client) GetProxyConcurrency() int {
+	return c.getMeshConfig().Spec.Sidecar.Concurrency
+}
+
+// GetProxyDrainDuration returns the duration for which the proxies drain their connections when shutting down.
+// If unset or non-parsable value, returns 0 duration
+func (c *client) GetProxyDrainDuration() time.Duration {
+	drainDuration := c.getMeshConfig().Spec.Sidecar.DrainDuration
+	if drainDuration == "" {
+		return time.Duration(0)
+	}
+	duration, err := time.ParseDuration(drainDuration)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error parsing proxy drain duration %s", drainDuration)
+		return time.Duration(0)
+	}
+	return duration
+}
+
+// IsHoldApplicationUntilProxyStarts returns whether the application containers are started only once the proxy is ready
+func (c *client) IsHoldApplicationUntilProxyStarts() bool {
+	return c.getMeshConfig().Spec.Sidecar.HoldApplicationUntilProxyStarts
+}
+
+// GetProxyStatsTags returns the tags, and their fixed values, added to all the stats of the proxies
+func (c *client) GetProxyStatsTags() map[string]string {
+	return c.getMeshConfig().Spec.Sidecar.StatsTags
+}
+
+// GetSidecarRolloutConfig returns the configuration of the automatic restart of the workloads running outdated sidecars
+func (c *client) GetSidecarRolloutConfig() configv1alpha1.SidecarRolloutSpec {
+	rollout := c.getMeshConfig().Spec.Sidecar.Rollout
+	if rollout.RestartInterval == "" {
+		rollout.RestartInterval = defaultSidecarRestartInterval
+	}
+	return rollout
+}
+
 // GetInboundExternalAuthConfig returns the External Authentication configuration for incoming traffic, if any
 func (c *client) GetInboundExternalAuthConfig() auth.ExtAuthConfig {
 	extAuthConfig := auth.ExtAuthConfig{}
@@ -231,6 +293,47 @@ func (c *client) GetInboundExternalAuthConfig() auth.ExtAuthConfig {
 	return extAuthConfig
 }
 
+// GetEgressDenyFeedback returns the feedback configuration for denied egress traffic
+func (c *client) GetEgressDenyFeedback() configv1alpha1.EgressDenyFeedbackSpec {
+	egressDenyFeedback := c.getMeshConfig().Spec.Traffic.EgressDenyFeedback
+	if egressDenyFeedback.HTTPStatusCode == 0 {
+		egressDenyFeedback.HTTPStatusCode = http.StatusForbidden
+	}
+	return egressDenyFeedback
+}
+
+// GetEgressHostResolutionTTL returns the duration for which the IP addresses resolved for the hosts of
+// TCP Egress policies are cached
+func (c *client) GetEgressHostResolutionTTL() time.Duration {
+	ttl := c.getMeshConfig().Spec.Traffic.EgressHostResolutionTTL
+	if ttl == "" {
+		return defaultEgressHostResolutionTTL
+	}
+
+	duration, err := time.ParseDuration(ttl)
+	if err != nil || duration < minEgressHostResolutionTTL {
+		log.Warn().Err(err).Msgf("Invalid egress host resolution TTL %s, defaulting to %v", ttl, defaultEgressHostResolutionTTL)
+		return defaultEgressHostResolutionTTL
+	}
+	return duration
+}
+
+// GetProtocolDetectionTimeout returns the duration for which the outbound listener waits for the first bytes of a
+// connection to detect its protocol
+func (c *client) GetProtocolDetectionTimeout() time.Duration {
+	timeout := c.getMeshConfig().Spec.Traffic.ProtocolDetectionTimeout
+	if timeout == "" {
+		return defaultProtocolDetectionTimeout
+	}
+
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration <= 0 {
+		log.Warn().Err(err).Msgf("Invalid protocol detection timeout %s, defaulting to %v", timeout, defaultProtocolDetectionTimeout)
+		return defaultProtocolDetectionTimeout
+	}
+	return duration
+}
+
 // GetFeatureFlags returns OSM's feature flags
 func (c *client) GetFeatureFlags() configv1alpha1.FeatureFlags {
 	return c.getMeshConfig().Spec.FeatureFlags
@@ -333,6 +333,36 @@ func TestCreateUpdateConfig(t *testing.T) {
 				assert.Equal([]int{7070, 6080}, cfg.GetInboundPortExclusionList())
 			},
 		},
+		{
+			name:                  "GetOutboundUIDExclusionList",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetOutboundUIDExclusionList())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					OutboundUIDExclusionList: []int{1000, 1001},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]int{1000, 1001}, cfg.GetOutboundUIDExclusionList())
+			},
+		},
+		{
+			name:                  "GetOutboundGIDExclusionList",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetOutboundGIDExclusionList())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					OutboundGIDExclusionList: []int{2000},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]int{2000}, cfg.GetOutboundGIDExclusionList())
+			},
+		},
 		{
 			name: "IsPrivilegedInitContainer",
 			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
@@ -431,6 +461,191 @@ func TestCreateUpdateConfig(t *testing.T) {
 				assert.Equal(resource.MustParse("512M"), res.Limits[v1.ResourceMemory])
 			},
 		},
+		{
+			name:                  "GetProxyConcurrency",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(0, cfg.GetProxyConcurrency())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					Concurrency: 2,
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(2, cfg.GetProxyConcurrency())
+			},
+		},
+		{
+			name:                  "GetProxyDrainDuration",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Duration(0), cfg.GetProxyDrainDuration())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					DrainDuration: "45s",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(45*time.Second, cfg.GetProxyDrainDuration())
+			},
+		},
+		{
+			name:                  "GetProxyDrainDurationInvalid",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Duration(0), cfg.GetProxyDrainDuration())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					DrainDuration: "45",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Duration(0), cfg.GetProxyDrainDuration())
+			},
+		},
+		{
+			name:                  "IsHoldApplicationUntilProxyStarts",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsHoldApplicationUntilProxyStarts())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					HoldApplicationUntilProxyStarts: true,
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsHoldApplicationUntilProxyStarts())
+			},
+		},
+		{
+			name:                  "GetProxyStatsTags",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Empty(cfg.GetProxyStatsTags())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					StatsTags: map[string]string{"cluster": "east"},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(map[string]string{"cluster": "east"}, cfg.GetProxyStatsTags())
+			},
+		},
+		{
+			name:                  "GetSidecarRolloutConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.SidecarRolloutSpec{RestartInterval: "5m"}, cfg.GetSidecarRolloutConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					Rollout: v1alpha1.SidecarRolloutSpec{
+						Enable:            true,
+						RestartInterval:   "1h",
+						MaintenanceWindow: &v1alpha1.MaintenanceWindowSpec{Start: "22:00", End: "04:00"},
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.SidecarRolloutSpec{
+					Enable:            true,
+					RestartInterval:   "1h",
+					MaintenanceWindow: &v1alpha1.MaintenanceWindowSpec{Start: "22:00", End: "04:00"},
+				}, cfg.GetSidecarRolloutConfig())
+			},
+		},
+		{
+			name:                  "GetEgressDenyFeedback",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.EgressDenyFeedbackSpec{HTTPStatusCode: 403}, cfg.GetEgressDenyFeedback())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					EgressDenyFeedback: v1alpha1.EgressDenyFeedbackSpec{
+						Enable:         true,
+						HTTPStatusCode: 502,
+						HTTPBody:       "denied",
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.EgressDenyFeedbackSpec{Enable: true, HTTPStatusCode: 502, HTTPBody: "denied"}, cfg.GetEgressDenyFeedback())
+			},
+		},
+		{
+			name:                  "GetEgressHostResolutionTTL",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(30*time.Second, cfg.GetEgressHostResolutionTTL())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					EgressHostResolutionTTL: "1m",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Minute, cfg.GetEgressHostResolutionTTL())
+			},
+		},
+		{
+			name: "InvalidEgressHostResolutionTTL",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					EgressHostResolutionTTL: "invalid",
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(30*time.Second, cfg.GetEgressHostResolutionTTL())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					EgressHostResolutionTTL: "1s",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(30*time.Second, cfg.GetEgressHostResolutionTTL())
+			},
+		},
+		{
+			name:                  "GetProtocolDetectionTimeout",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Second, cfg.GetProtocolDetectionTimeout())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ProtocolDetectionTimeout: "200ms",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(200*time.Millisecond, cfg.GetProtocolDetectionTimeout())
+			},
+		},
+		{
+			name: "InvalidProtocolDetectionTimeout",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ProtocolDetectionTimeout: "invalid",
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Second, cfg.GetProtocolDetectionTimeout())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ProtocolDetectionTimeout: "0s",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Second, cfg.GetProtocolDetectionTimeout())
+			},
+		},
 		{
 			name:                  "IsWASMStatsEnabled",
 			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
@@ -65,6 +65,34 @@ func (mr *MockConfiguratorMockRecorder) GetConfigResyncInterval() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigResyncInterval", reflect.TypeOf((*MockConfigurator)(nil).GetConfigResyncInterval))
 }
 
+// GetEgressDenyFeedback mocks base method.
+func (m *MockConfigurator) GetEgressDenyFeedback() v1alpha1.EgressDenyFeedbackSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressDenyFeedback")
+	ret0, _ := ret[0].(v1alpha1.EgressDenyFeedbackSpec)
+	return ret0
+}
+
+// GetEgressDenyFeedback indicates an expected call of GetEgressDenyFeedback.
+func (mr *MockConfiguratorMockRecorder) GetEgressDenyFeedback() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressDenyFeedback", reflect.TypeOf((*MockConfigurator)(nil).GetEgressDenyFeedback))
+}
+
+// GetEgressHostResolutionTTL mocks base method.
+func (m *MockConfigurator) GetEgressHostResolutionTTL() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressHostResolutionTTL")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetEgressHostResolutionTTL indicates an expected call of GetEgressHostResolutionTTL.
+func (mr *MockConfiguratorMockRecorder) GetEgressHostResolutionTTL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressHostResolutionTTL", reflect.TypeOf((*MockConfigurator)(nil).GetEgressHostResolutionTTL))
+}
+
 // GetEnvoyImage mocks base method.
 func (m *MockConfigurator) GetEnvoyImage() string {
 	m.ctrl.T.Helper()
@@ -234,6 +262,20 @@ func (mr *MockConfiguratorMockRecorder) GetOSMNamespace() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOSMNamespace", reflect.TypeOf((*MockConfigurator)(nil).GetOSMNamespace))
 }
 
+// GetOutboundGIDExclusionList mocks base method.
+func (m *MockConfigurator) GetOutboundGIDExclusionList() []int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboundGIDExclusionList")
+	ret0, _ := ret[0].([]int)
+	return ret0
+}
+
+// GetOutboundGIDExclusionList indicates an expected call of GetOutboundGIDExclusionList.
+func (mr *MockConfiguratorMockRecorder) GetOutboundGIDExclusionList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundGIDExclusionList", reflect.TypeOf((*MockConfigurator)(nil).GetOutboundGIDExclusionList))
+}
+
 // GetOutboundIPRangeExclusionList mocks base method.
 func (m *MockConfigurator) GetOutboundIPRangeExclusionList() []string {
 	m.ctrl.T.Helper()
@@ -262,6 +304,62 @@ func (mr *MockConfiguratorMockRecorder) GetOutboundPortExclusionList() *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundPortExclusionList", reflect.TypeOf((*MockConfigurator)(nil).GetOutboundPortExclusionList))
 }
 
+// GetOutboundUIDExclusionList mocks base method.
+func (m *MockConfigurator) GetOutboundUIDExclusionList() []int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboundUIDExclusionList")
+	ret0, _ := ret[0].([]int)
+	return ret0
+}
+
+// GetOutboundUIDExclusionList indicates an expected call of GetOutboundUIDExclusionList.
+func (mr *MockConfiguratorMockRecorder) GetOutboundUIDExclusionList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundUIDExclusionList", reflect.TypeOf((*MockConfigurator)(nil).GetOutboundUIDExclusionList))
+}
+
+// GetProtocolDetectionTimeout mocks base method.
+func (m *MockConfigurator) GetProtocolDetectionTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProtocolDetectionTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetProtocolDetectionTimeout indicates an expected call of GetProtocolDetectionTimeout.
+func (mr *MockConfiguratorMockRecorder) GetProtocolDetectionTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProtocolDetectionTimeout", reflect.TypeOf((*MockConfigurator)(nil).GetProtocolDetectionTimeout))
+}
+
+// GetProxyConcurrency mocks base method.
+func (m *MockConfigurator) GetProxyConcurrency() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyConcurrency")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetProxyConcurrency indicates an expected call of GetProxyConcurrency.
+func (mr *MockConfiguratorMockRecorder) GetProxyConcurrency() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyConcurrency", reflect.TypeOf((*MockConfigurator)(nil).GetProxyConcurrency))
+}
+
+// GetProxyDrainDuration mocks base method.
+func (m *MockConfigurator) GetProxyDrainDuration() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyDrainDuration")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetProxyDrainDuration indicates an expected call of GetProxyDrainDuration.
+func (mr *MockConfiguratorMockRecorder) GetProxyDrainDuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyDrainDuration", reflect.TypeOf((*MockConfigurator)(nil).GetProxyDrainDuration))
+}
+
 // GetProxyResources mocks base method.
 func (m *MockConfigurator) GetProxyResources() v1.ResourceRequirements {
 	m.ctrl.T.Helper()
@@ -276,6 +374,20 @@ func (mr *MockConfiguratorMockRecorder) GetProxyResources() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyResources", reflect.TypeOf((*MockConfigurator)(nil).GetProxyResources))
 }
 
+// GetProxyStatsTags mocks base method.
+func (m *MockConfigurator) GetProxyStatsTags() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyStatsTags")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// GetProxyStatsTags indicates an expected call of GetProxyStatsTags.
+func (mr *MockConfiguratorMockRecorder) GetProxyStatsTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyStatsTags", reflect.TypeOf((*MockConfigurator)(nil).GetProxyStatsTags))
+}
+
 // GetServiceCertValidityPeriod mocks base method.
 func (m *MockConfigurator) GetServiceCertValidityPeriod() time.Duration {
 	m.ctrl.T.Helper()
@@ -290,6 +402,20 @@ func (mr *MockConfiguratorMockRecorder) GetServiceCertValidityPeriod() *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceCertValidityPeriod", reflect.TypeOf((*MockConfigurator)(nil).GetServiceCertValidityPeriod))
 }
 
+// GetSidecarRolloutConfig mocks base method.
+func (m *MockConfigurator) GetSidecarRolloutConfig() v1alpha1.SidecarRolloutSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSidecarRolloutConfig")
+	ret0, _ := ret[0].(v1alpha1.SidecarRolloutSpec)
+	return ret0
+}
+
+// GetSidecarRolloutConfig indicates an expected call of GetSidecarRolloutConfig.
+func (mr *MockConfiguratorMockRecorder) GetSidecarRolloutConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSidecarRolloutConfig", reflect.TypeOf((*MockConfigurator)(nil).GetSidecarRolloutConfig))
+}
+
 // GetTracingEndpoint mocks base method.
 func (m *MockConfigurator) GetTracingEndpoint() string {
 	m.ctrl.T.Helper()
@@ -360,6 +486,20 @@ func (mr *MockConfiguratorMockRecorder) IsEgressEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEgressEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsEgressEnabled))
 }
 
+// IsHoldApplicationUntilProxyStarts mocks base method.
+func (m *MockConfigurator) IsHoldApplicationUntilProxyStarts() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsHoldApplicationUntilProxyStarts")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsHoldApplicationUntilProxyStarts indicates an expected call of IsHoldApplicationUntilProxyStarts.
+func (mr *MockConfiguratorMockRecorder) IsHoldApplicationUntilProxyStarts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsHoldApplicationUntilProxyStarts", reflect.TypeOf((*MockConfigurator)(nil).IsHoldApplicationUntilProxyStarts))
+}
+
 // IsPermissiveTrafficPolicyMode mocks base method.
 func (m *MockConfigurator) IsPermissiveTrafficPolicyMode() bool {
 	m.ctrl.T.Helper()
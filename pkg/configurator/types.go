@@ -90,6 +90,12 @@ type Configurator interface {
 	// GetInboundPortExclusionList returns the list of ports to exclude from inbound sidecar interception
 	GetInboundPortExclusionList() []int
 
+	// GetOutboundUIDExclusionList returns the list of user IDs whose outbound traffic is excluded from sidecar interception
+	GetOutboundUIDExclusionList() []int
+
+	// GetOutboundGIDExclusionList returns the list of group IDs whose outbound traffic is excluded from sidecar interception
+	GetOutboundGIDExclusionList() []int
+
 	// IsPrivilegedInitContainer determines whether init containers should be privileged
 	IsPrivilegedInitContainer() bool
 
@@ -100,9 +106,36 @@ type Configurator interface {
 	// GetProxyResources returns the `Resources` configured for proxies, if any
 	GetProxyResources() corev1.ResourceRequirements
 
+	// GetProxyConcurrency returns the number of worker threads of the proxies, 0 if unset
+	GetProxyConcurrency() int
+
+	// GetProxyDrainDuration returns the duration for which the proxies drain their connections when shutting down.
+	// If unset or non-parsable value, returns 0 duration
+	GetProxyDrainDuration() time.Duration
+
+	// IsHoldApplicationUntilProxyStarts returns whether the application containers are started only once the proxy is ready
+	IsHoldApplicationUntilProxyStarts() bool
+
+	// GetProxyStatsTags returns the tags, and their fixed values, added to all the stats of the proxies
+	GetProxyStatsTags() map[string]string
+
+	// GetSidecarRolloutConfig returns the configuration of the automatic restart of the workloads running outdated sidecars
+	GetSidecarRolloutConfig() configv1alpha1.SidecarRolloutSpec
+
 	// GetInboundExternalAuthConfig returns the External Authentication configuration for incoming traffic, if any
 	GetInboundExternalAuthConfig() auth.ExtAuthConfig
 
+	// GetEgressDenyFeedback returns the feedback configuration for denied egress traffic
+	GetEgressDenyFeedback() configv1alpha1.EgressDenyFeedbackSpec
+
+	// GetEgressHostResolutionTTL returns the duration for which the IP addresses resolved for the hosts of
+	// TCP Egress policies are cached
+	GetEgressHostResolutionTTL() time.Duration
+
+	// GetProtocolDetectionTimeout returns the duration for which the outbound listener waits for the first bytes of a
+	// connection to detect its protocol
+	GetProtocolDetectionTimeout() time.Duration
+
 	// GetFeatureFlags returns OSM's feature flags
 	GetFeatureFlags() configv1alpha1.FeatureFlags
 }
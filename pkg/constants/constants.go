@@ -7,9 +7,15 @@ const (
 	// WildcardIPAddr is a string constant.
 	WildcardIPAddr = "0.0.0.0"
 
+	// WildcardIPv6Addr is the IPv6 wildcard address.
+	WildcardIPv6Addr = "::"
+
 	// EnvoyAdminPort is Envoy's admin port
 	EnvoyAdminPort = 15000
 
+	// EnvoyAdminQuitPath is the path of Envoy's admin endpoint shutting down Envoy, only reachable from the pod of the Envoy sidecar
+	EnvoyAdminQuitPath = "/quitquitquit"
+
 	// EnvoyAdminPortName is Envoy's admin port name
 	EnvoyAdminPortName = "proxy-admin"
 
@@ -37,6 +43,9 @@ const (
 	// LocalhostIPAddress is the local host address.
 	LocalhostIPAddress = "127.0.0.1"
 
+	// LocalhostIPv6Address is the IPv6 local host address.
+	LocalhostIPv6Address = "::1"
+
 	// EnvoyMetricsCluster is the cluster name of the Prometheus metrics cluster
 	EnvoyMetricsCluster = "envoy-metrics-cluster"
 
@@ -73,6 +82,10 @@ const (
 	// ValidatorWebhookPort is the port on which the resource validator webhook listens
 	ValidatorWebhookPort = 9093
 
+	// InjectorPreviewPort is the port on which the sidecar injector previews the sidecar injection of manifests.
+	// It listens on the loopback interface only, to be reached by port forwarding to the sidecar injector pod.
+	InjectorPreviewPort = 9094
+
 	// OSMControllerName is the name of the OSM Controller (formerly ADS service).
 	OSMControllerName = "osm-controller"
 
@@ -103,6 +116,10 @@ const (
 	// MutatingWebhookCertificateSecretName is the default value for mutating webhook secret name
 	MutatingWebhookCertificateSecretName = "mutating-webhook-cert-secret"
 
+	// IptablesConfigKeySecretName is the name of the secret holding the key the sidecar injector signs the iptables
+	// config of the pods with, verified by the OSM CNI plugin
+	IptablesConfigKeySecretName = "osm-iptables-config-key" // #nosec G101: Potential hardcoded credentials
+
 	// ValidatingWebhookCertificateSecretName is the default value for validating webhook secret name
 	ValidatingWebhookCertificateSecretName = "validating-webhook-cert-secret" // #nosec G101: Potential hardcoded credentials
 
@@ -130,6 +147,10 @@ const (
 	// EnvoyUniqueIDLabelName is the label applied to pods with the unique ID of the Envoy sidecar.
 	EnvoyUniqueIDLabelName = "osm-proxy-uuid"
 
+	// EnvoyBootstrapConfigSecretPrefix is the prefix of the name of the secret holding the bootstrap config of an Envoy
+	// sidecar, followed by the unique ID of the sidecar.
+	EnvoyBootstrapConfigSecretPrefix = "envoy-bootstrap-config-"
+
 	// TimeDateLayout is the layout for time.Parse used in this repo
 	TimeDateLayout = "2006-01-02T15:04:05.000Z"
 
@@ -171,6 +192,102 @@ const (
 
 	// MetricsAnnotation is the annotation used for enabling/disabling metrics
 	MetricsAnnotation = "openservicemesh.io/metrics"
+
+	// IptablesConfigAnnotation is the annotation set on the injected pods by the sidecar injector when the OSM CNI
+	// plugin is enabled, recording the configuration of the traffic interception rules the plugin must program
+	IptablesConfigAnnotation = "openservicemesh.io/iptables-config"
+
+	// IptablesConfigSignatureAnnotation is the annotation set on the injected pods by the sidecar injector along with
+	// IptablesConfigAnnotation, recording the signature the OSM CNI plugin verifies to only trust the iptables config
+	// of the pods mutated by the sidecar injector
+	IptablesConfigSignatureAnnotation = "openservicemesh.io/iptables-config-signature"
+
+	// SidecarHashAnnotation is the annotation set on the injected pods by the sidecar injector, recording the hash of
+	// the configuration of the injected sidecar and init container to detect the pods running an outdated sidecar
+	SidecarHashAnnotation = "openservicemesh.io/sidecar-hash"
+
+	// SidecarOutdatedAnnotation is the annotation set on the pods running an outdated sidecar, whose configuration
+	// differs from the configuration that would be injected with the current mesh configuration
+	SidecarOutdatedAnnotation = "openservicemesh.io/sidecar-outdated"
+
+	// SidecarRolloutLastRestartAnnotation is the annotation set on the sidecar injector Deployment to record the time of
+	// the last restart of a workload running outdated sidecars
+	SidecarRolloutLastRestartAnnotation = "openservicemesh.io/sidecar-rollout-last-restart"
+)
+
+// Annotations used to override the sidecar settings configured in the MeshConfig for a pod. The annotations
+// can be set on a pod, or on its namespace to apply to all its pods. The annotations on a pod take precedence.
+const (
+	// SidecarCPURequestAnnotation is the annotation used to override the CPU request of the sidecar, ex. '100m'
+	SidecarCPURequestAnnotation = "openservicemesh.io/sidecar-cpu-request"
+
+	// SidecarCPULimitAnnotation is the annotation used to override the CPU limit of the sidecar, ex. '1'
+	SidecarCPULimitAnnotation = "openservicemesh.io/sidecar-cpu-limit"
+
+	// SidecarMemoryRequestAnnotation is the annotation used to override the memory request of the sidecar, ex. '64Mi'
+	SidecarMemoryRequestAnnotation = "openservicemesh.io/sidecar-memory-request"
+
+	// SidecarMemoryLimitAnnotation is the annotation used to override the memory limit of the sidecar, ex. '512Mi'
+	SidecarMemoryLimitAnnotation = "openservicemesh.io/sidecar-memory-limit"
+
+	// SidecarLogLevelAnnotation is the annotation used to override the log level of the sidecar.
+	// Supported values are 'trace', 'debug', 'info', 'warning', 'warn', 'error', 'critical' and 'off'.
+	SidecarLogLevelAnnotation = "openservicemesh.io/sidecar-log-level"
+
+	// SidecarConcurrencyAnnotation is the annotation used to configure the number of worker threads of the sidecar,
+	// which defaults to the number of hardware threads of the node
+	SidecarConcurrencyAnnotation = "openservicemesh.io/sidecar-concurrency"
+
+	// SidecarImageAnnotation is the annotation used to override the image of the sidecar
+	SidecarImageAnnotation = "openservicemesh.io/sidecar-image"
+
+	// SidecarHoldApplicationUntilProxyStartsAnnotation is the annotation used to start the application containers only once
+	// the sidecar is ready, and to drain the sidecar before the application terminates, ex. 'true'
+	SidecarHoldApplicationUntilProxyStartsAnnotation = "openservicemesh.io/sidecar-hold-application-until-proxy-starts"
+
+	// SidecarQuitOnApplicationExitAnnotation is the annotation used to opt out of the sidecar of the pods of Jobs exiting
+	// once the application containers have terminated, ex. 'false'. The sidecar watches the processes of the application
+	// containers, which requires sharing the process namespace of the pod, and the sh, readlink and wget commands in
+	// the sidecar image.
+	SidecarQuitOnApplicationExitAnnotation = "openservicemesh.io/sidecar-quit-on-application-exit"
+)
+
+// Annotations used to configure load balancing for a service
+const (
+	// LoadBalancerAnnotation is the annotation used to configure the load balancing algorithm used by
+	// clients of a service. Supported values are 'round-robin' (default), 'ring-hash' and 'maglev'.
+	LoadBalancerAnnotation = "openservicemesh.io/load-balancer"
+
+	// HashKeyAnnotation is the annotation used to configure the request attribute used to compute the hash
+	// for consistent-hash load balancing. Supported values are 'header:<name>', 'cookie:<name>' and 'source-ip'.
+	// The hash key of an apex service whose traffic is split by a TrafficSplit is ignored, the backend services
+	// must be annotated instead.
+	HashKeyAnnotation = "openservicemesh.io/hash-key"
+
+	// HashCookieTTLAnnotation is the annotation used to configure the TTL of the cookie generated by the
+	// proxy when a cookie based hash key is absent in a request, ex. '1h'.
+	HashCookieTTLAnnotation = "openservicemesh.io/hash-cookie-ttl"
+
+	// RedisShardingAnnotation is the annotation used to opt a service whose ports have the 'redis' protocol into
+	// key based sharding, ex. 'true'. The clients proxy the Redis commands with the Envoy Redis proxy, which routes
+	// each command to the endpoint selected by hashing its key with a Maglev load balancer. Each endpoint must hold
+	// an independent shard of the keyspace, so replicas of a single instance and Redis Cluster nodes must not opt in.
+	// The commands unsupported by the Redis proxy, such as transactions and pub/sub, are rejected, and the keys are
+	// redistributed when the endpoints change. Without the annotation, the Redis traffic is proxied as TCP.
+	RedisShardingAnnotation = "openservicemesh.io/redis-sharding"
+)
+
+// Annotations used to derive ingress traffic policies from Kubernetes Ingress resources
+const (
+	// IngressBackendProtocolAnnotation is the annotation on an IngressClass used to monitor the Ingress resources
+	// of the class, and to configure the protocol used by its ingress controller to connect to the backends.
+	// Supported values are 'http' and 'https'.
+	IngressBackendProtocolAnnotation = "openservicemesh.io/ingress-backend-protocol"
+
+	// IngressSourceServiceAnnotation is the annotation on an IngressClass used to restrict the sources allowed to
+	// access the backends of the Ingress resources of the class to the endpoints of the given '<namespace>/<name>'
+	// ingress controller service. It is required for the backend protocol 'http', whose downstream identity cannot be verified.
+	IngressSourceServiceAnnotation = "openservicemesh.io/ingress-source-service"
 )
 
 // Labels used by the control plane
@@ -183,6 +300,25 @@ const (
 
 	// AppLabel is the label used to identify the app
 	AppLabel = "app"
+
+	// CNINodeLabel is the label set on the nodes the OSM CNI plugin is installed on. When the OSM CNI plugin is
+	// enabled, the pods injected with the sidecar are only scheduled on these nodes.
+	CNINodeLabel = "openservicemesh.io/cni"
+)
+
+// Labels and annotations used for multicluster service discovery
+const (
+	// MulticlusterExportLabel is the label used to export a service to the peer clusters of the mesh.
+	// A service can also be exported using an annotation with the same key.
+	MulticlusterExportLabel = "openservicemesh.io/multicluster-export"
+
+	// MulticlusterPeerLabel is the label used to identify a Secret in the OSM namespace holding the
+	// kubeconfig of a peer cluster whose exported services are imported into the local cluster
+	MulticlusterPeerLabel = "openservicemesh.io/multicluster-peer"
+
+	// MulticlusterManagedByLabel is the label applied to the MultiClusterService resources generated
+	// by multicluster service discovery
+	MulticlusterManagedByLabel = "openservicemesh.io/multicluster-managed-by"
 )
 
 // Annotations used for Metrics
@@ -223,6 +359,22 @@ const (
 	// Ex. MySQL, SMTP, PostgreSQL etc. where the server initiates the first
 	// byte in a TCP connection.
 	ProtocolTCPServerFirst = "tcp-server-first"
+
+	// ProtocolAuto implies the protocol is detected by inspecting the traffic, as HTTP or TCP.
+	// It is the protocol of the service ports that do not declare their protocol when protocol sniffing is enabled.
+	ProtocolAuto = "auto"
+
+	// MySQL protocol, a TCP based server first protocol
+	ProtocolMySQL = "mysql"
+
+	// PostgreSQL protocol
+	ProtocolPostgres = "postgres"
+
+	// Redis protocol, proxied as TCP unless the service opts into key based sharding with RedisShardingAnnotation
+	ProtocolRedis = "redis"
+
+	// MongoDB protocol
+	ProtocolMongo = "mongo"
 )
 
 // Operating systems.
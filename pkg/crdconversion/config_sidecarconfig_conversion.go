@@ -0,0 +1,27 @@
+package crdconversion
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// serveSidecarConfigConversion serves endpoint for the converter defined as convertSidecarConfig function.
+func serveSidecarConfigConversion(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, convertSidecarConfig)
+}
+
+// convertSidecarConfig contains the business logic to convert sidecarconfigs.config.openservicemesh.io CRD
+// Example implementation reference : https://github.com/kubernetes/kubernetes/blob/release-1.22/test/images/agnhost/crd-conversion-webhook/converter/example_converter.go
+func convertSidecarConfig(Object *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, metav1.Status) {
+	convertedObject := Object.DeepCopy()
+	fromVersion := Object.GetAPIVersion()
+
+	if toVersion == fromVersion {
+		return nil, statusErrorWithMessage("SidecarConfig: conversion from a version to itself should not call the webhook: %s", toVersion)
+	}
+
+	log.Debug().Msg("SidecarConfig: successfully converted object")
+	return convertedObject, statusSucceed()
+}
@@ -30,6 +30,7 @@ const (
 	httpRouteGroupConverterPath        = "/convert/httproutegroup"
 	meshConfigConverterPath            = "/convert/meshconfig"
 	multiclusterServiceConverterPath   = "/convert/multiclusterservice"
+	sidecarConfigConverterPath         = "/convert/sidecarconfig"
 	egressPolicyConverterPath          = "/convert/egresspolicy"
 	trafficSplitConverterPath          = "/convert/trafficsplit"
 	tcpRoutesConverterPath             = "/convert/tcproutes"
@@ -41,6 +42,7 @@ var crdConversionWebhookConfiguration = map[string]string{
 	"httproutegroups.specs.smi-spec.io":              httpRouteGroupConverterPath,
 	"meshconfigs.config.openservicemesh.io":          meshConfigConverterPath,
 	"multiclusterservices.config.openservicemesh.io": multiclusterServiceConverterPath,
+	"sidecarconfigs.config.openservicemesh.io":       sidecarConfigConverterPath,
 	"egresses.policy.openservicemesh.io":             egressPolicyConverterPath,
 	"trafficsplits.split.smi-spec.io":                trafficSplitConverterPath,
 	"tcproutes.specs.smi-spec.io":                    tcpRoutesConverterPath,
@@ -92,6 +94,7 @@ func (crdWh *crdConversionWebhook) run(stop <-chan struct{}) {
 	webhookMux.HandleFunc(trafficAccessConverterPath, serveTrafficAccessConversion)
 	webhookMux.HandleFunc(httpRouteGroupConverterPath, serveHTTPRouteGroupConversion)
 	webhookMux.HandleFunc(multiclusterServiceConverterPath, serveMultiClusterServiceConversion)
+	webhookMux.HandleFunc(sidecarConfigConverterPath, serveSidecarConfigConversion)
 	webhookMux.HandleFunc(egressPolicyConverterPath, serveEgressPolicyConversion)
 	webhookMux.HandleFunc(trafficSplitConverterPath, serveTrafficSplitConversion)
 	webhookMux.HandleFunc(tcpRoutesConverterPath, serveTCPRouteConversion)
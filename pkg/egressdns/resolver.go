@@ -0,0 +1,170 @@
+package egressdns
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+// NewResolver returns a Resolver that resolves the hosts of the TCP ports of Egress policies once it is run
+func NewResolver(policyController policy.Controller, cfg configurator.Configurator, msgBroker *messaging.Broker) Resolver {
+	return &resolver{
+		policyController: policyController,
+		cfg:              cfg,
+		msgBroker:        msgBroker,
+		lookupHost:       net.DefaultResolver.LookupHost,
+		cache:            make(map[string]*cacheEntry),
+		refreshChan:      make(chan struct{}, 1),
+	}
+}
+
+// Resolve returns the IP addresses the given host was last resolved to. A host that has not been resolved yet is
+// resolved in the background, and the proxies are updated once it is, so that building the proxy config referencing
+// it never waits on DNS.
+func (r *resolver) Resolve(host string) []string {
+	r.cacheMutex.RLock()
+	entry, ok := r.cache[host]
+	r.cacheMutex.RUnlock()
+	if ok {
+		return entry.ipAddresses
+	}
+
+	// A refresh resolves all the hosts that have not been resolved yet, so a pending refresh needs not be queued again
+	select {
+	case r.refreshChan <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run periodically resolves the hosts whose IP addresses have expired, and the hosts that have not been resolved yet
+// as they are requested, until the given channel is closed. The hosts are only resolved while the egress policy
+// feature is enabled.
+func (r *resolver) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(refreshCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-r.refreshChan:
+		}
+
+		if !r.cfg.GetFeatureFlags().EnableEgressPolicy {
+			r.cacheMutex.Lock()
+			r.cache = make(map[string]*cacheEntry)
+			r.cacheMutex.Unlock()
+			continue
+		}
+		if r.refresh() {
+			log.Debug().Msg("IP addresses of egress hosts changed, updating proxies")
+			r.msgBroker.GetQueue().AddRateLimited(events.PubSubMessage{
+				Kind: announcements.ProxyUpdate,
+			})
+		}
+	}
+}
+
+// refresh resolves the hosts of the TCP ports of the Egress policies whose IP addresses have expired, and forgets
+// the hosts that are no longer referenced. It returns true if the IP addresses of any host changed.
+func (r *resolver) refresh() bool {
+	hosts := mapset.NewSet()
+	for _, egress := range r.policyController.ListEgressPolicies() {
+		for _, host := range GetTCPHosts(egress) {
+			hosts.Add(host)
+		}
+	}
+
+	ttl := r.cfg.GetEgressHostResolutionTTL()
+	var expiredHosts []string
+	r.cacheMutex.Lock()
+	for host := range r.cache {
+		if !hosts.Contains(host) {
+			delete(r.cache, host)
+		}
+	}
+	for host := range hosts.Iter() {
+		entry, ok := r.cache[host.(string)]
+		if !ok || time.Since(entry.resolvedAt) >= ttl {
+			expiredHosts = append(expiredHosts, host.(string))
+		}
+	}
+	r.cacheMutex.Unlock()
+
+	changed := false
+	for _, host := range expiredHosts {
+		ipAddresses, err := r.lookup(host)
+
+		r.cacheMutex.Lock()
+		entry, ok := r.cache[host]
+		if !ok {
+			entry = &cacheEntry{}
+			r.cache[host] = entry
+		}
+		if err != nil {
+			// Keep the IP addresses previously resolved, which are more likely to be correct than none
+			log.Warn().Err(err).Msgf("Error resolving egress host %s, keeping the IP addresses it was last resolved to", host)
+		} else if !reflect.DeepEqual(entry.ipAddresses, ipAddresses) {
+			log.Info().Msgf("Egress host %s resolved to new IP addresses %v, previously %v", host, ipAddresses, entry.ipAddresses)
+			entry.ipAddresses = ipAddresses
+			changed = true
+		}
+		entry.resolvedAt = time.Now()
+		r.cacheMutex.Unlock()
+	}
+
+	return changed
+}
+
+// lookup returns the sorted IP addresses the given host resolves to
+func (r *resolver) lookup(host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	ipAddresses, err := r.lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ipAddresses)
+	return ipAddresses, nil
+}
+
+// GetTCPHosts returns the hosts of the given Egress policy that must be resolved to match the traffic on its TCP ports.
+// Wildcard hosts cannot be resolved, and IP addresses do not need to be.
+func GetTCPHosts(egress *policyv1alpha1.Egress) []string {
+	hasTCPPort := false
+	for _, port := range egress.Spec.Ports {
+		switch strings.ToLower(port.Protocol) {
+		case constants.ProtocolTCP, constants.ProtocolTCPServerFirst:
+			hasTCPPort = true
+		}
+	}
+	if !hasTCPPort {
+		return nil
+	}
+
+	var hosts []string
+	for _, host := range egress.Spec.Hosts {
+		if utils.IsWildcardHost(host) || net.ParseIP(host) != nil {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
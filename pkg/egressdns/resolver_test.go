@@ -0,0 +1,203 @@
+package egressdns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/policy"
+)
+
+type fakeDNS struct {
+	records map[string][]string
+	lookups map[string]int
+}
+
+func (f *fakeDNS) lookupHost(_ context.Context, host string) ([]string, error) {
+	f.lookups[host]++
+	ipAddresses, ok := f.records[host]
+	if !ok {
+		return nil, errors.Errorf("no such host %s", host)
+	}
+	return append([]string(nil), ipAddresses...), nil
+}
+
+func newTestResolver(mockCtrl *gomock.Controller, dns *fakeDNS) (*resolver, *policy.MockController) {
+	mockPolicyController := policy.NewMockController(mockCtrl)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockCfg.EXPECT().GetEgressHostResolutionTTL().Return(30 * time.Second).AnyTimes()
+
+	return &resolver{
+		policyController: mockPolicyController,
+		cfg:              mockCfg,
+		lookupHost:       dns.lookupHost,
+		cache:            make(map[string]*cacheEntry),
+		refreshChan:      make(chan struct{}, 1),
+	}, mockPolicyController
+}
+
+func TestResolve(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dns := &fakeDNS{
+		records: map[string][]string{"db.example.com": {"10.0.0.2", "10.0.0.1"}},
+		lookups: make(map[string]int),
+	}
+	r, mockPolicyController := newTestResolver(mockCtrl, dns)
+	mockPolicyController.EXPECT().ListEgressPolicies().Return([]*policyv1alpha1.Egress{
+		{
+			Spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"db.example.com", "unknown.example.com"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 5432, Protocol: "tcp"}},
+			},
+		},
+	}).AnyTimes()
+
+	// Hosts that have not been resolved yet are not looked up synchronously, but a refresh is requested
+	assert.Empty(r.Resolve("db.example.com"))
+	assert.Empty(r.Resolve("unknown.example.com"))
+	assert.Empty(dns.lookups)
+	assert.Len(r.refreshChan, 1)
+
+	// The IP addresses are sorted and cached once resolved
+	<-r.refreshChan
+	assert.True(r.refresh())
+	assert.Equal([]string{"10.0.0.1", "10.0.0.2"}, r.Resolve("db.example.com"))
+	assert.Equal([]string{"10.0.0.1", "10.0.0.2"}, r.Resolve("db.example.com"))
+	assert.Equal(1, dns.lookups["db.example.com"])
+	assert.Empty(r.refreshChan)
+
+	// Lookup errors are cached until the host is resolved again
+	assert.Empty(r.Resolve("unknown.example.com"))
+	assert.Equal(1, dns.lookups["unknown.example.com"])
+	assert.Empty(r.refreshChan)
+}
+
+func TestRunWithEgressPolicyDisabled(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dns := &fakeDNS{lookups: make(map[string]int)}
+	r, _ := newTestResolver(mockCtrl, dns)
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	r.cfg = mockCfg
+	resolved := make(chan struct{})
+	mockCfg.EXPECT().GetFeatureFlags().DoAndReturn(func() v1alpha1.FeatureFlags {
+		close(resolved)
+		return v1alpha1.FeatureFlags{EnableEgressPolicy: false}
+	}).Times(1)
+	r.cache["db.example.com"] = &cacheEntry{ipAddresses: []string{"10.0.0.1"}, resolvedAt: time.Now()}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		r.Run(stop)
+		close(done)
+	}()
+
+	// Hosts are neither resolved nor cached while the egress policy feature is disabled
+	assert.Empty(r.Resolve("foo.example.com"))
+	<-resolved
+	close(stop)
+	<-done
+	assert.Empty(dns.lookups)
+	assert.Empty(r.Resolve("db.example.com"))
+}
+
+func TestRefresh(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dns := &fakeDNS{
+		records: map[string][]string{
+			"db.example.com":    {"10.0.0.1"},
+			"cache.example.com": {"10.0.1.1"},
+		},
+		lookups: make(map[string]int),
+	}
+	r, mockPolicyController := newTestResolver(mockCtrl, dns)
+
+	egress := &policyv1alpha1.Egress{
+		Spec: policyv1alpha1.EgressSpec{
+			Hosts: []string{"db.example.com"},
+			Ports: []policyv1alpha1.PortSpec{{Number: 5432, Protocol: "tcp"}},
+		},
+	}
+	mockPolicyController.EXPECT().ListEgressPolicies().Return([]*policyv1alpha1.Egress{egress}).AnyTimes()
+
+	// Hosts referenced by policies are resolved even if they have not been requested yet
+	assert.True(r.refresh())
+	assert.Equal([]string{"10.0.0.1"}, r.Resolve("db.example.com"))
+	assert.Equal(1, dns.lookups["db.example.com"])
+
+	// Hosts are not resolved again until their IP addresses expire
+	assert.False(r.refresh())
+	assert.Equal(1, dns.lookups["db.example.com"])
+
+	// Expired hosts are resolved again, and a change in IP addresses is reported
+	dns.records["db.example.com"] = []string{"10.0.0.3"}
+	r.cache["db.example.com"].resolvedAt = time.Now().Add(-time.Minute)
+	assert.True(r.refresh())
+	assert.Equal([]string{"10.0.0.3"}, r.Resolve("db.example.com"))
+
+	// Lookup errors keep the previously resolved IP addresses
+	delete(dns.records, "db.example.com")
+	r.cache["db.example.com"].resolvedAt = time.Now().Add(-time.Minute)
+	assert.False(r.refresh())
+	assert.Equal([]string{"10.0.0.3"}, r.Resolve("db.example.com"))
+
+	// Hosts no longer referenced by policies are forgotten
+	r.cache["cache.example.com"] = &cacheEntry{ipAddresses: []string{"10.0.1.1"}, resolvedAt: time.Now()}
+	r.refresh()
+	assert.NotContains(r.cache, "cache.example.com")
+}
+
+func TestGetTCPHosts(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     policyv1alpha1.EgressSpec
+		expected []string
+	}{
+		{
+			name: "hosts for TCP port",
+			spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"db.example.com", "*.example.com", "10.0.0.1"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 5432, Protocol: "tcp"}},
+			},
+			expected: []string{"db.example.com"},
+		},
+		{
+			name: "hosts for TCP server-first port",
+			spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"db.example.com"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 3306, Protocol: "tcp-server-first"}},
+			},
+			expected: []string{"db.example.com"},
+		},
+		{
+			name: "hosts for HTTP and HTTPS ports only",
+			spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"foo.com"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 80, Protocol: "http"}, {Number: 443, Protocol: "https"}},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, GetTCPHosts(&policyv1alpha1.Egress{Spec: tc.spec}))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package egressdns implements the resolution of the hosts of the TCP ports of Egress policies to IP addresses,
+// so that TCP egress traffic, which carries no host information, can be matched on the current IP addresses
+// of the hosts. The hosts are re-resolved periodically, and the proxies are updated when their IP addresses change.
+package egressdns
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/policy"
+)
+
+var (
+	log = logger.New("egress-dns")
+)
+
+const (
+	// refreshCheckInterval is the interval at which the hosts whose resolved IP addresses have expired are resolved again
+	refreshCheckInterval = 5 * time.Second
+
+	// lookupTimeout is the timeout of the DNS lookup of a host
+	lookupTimeout = 5 * time.Second
+)
+
+// Resolver is the interface to resolve the hosts of the TCP ports of Egress policies
+type Resolver interface {
+	// Resolve returns the IP addresses the given host resolves to, or none if it has not been resolved yet. The hosts
+	// are resolved in the background, and their IP addresses are cached for the configured TTL, after which they are
+	// resolved again.
+	Resolve(host string) []string
+
+	// Run resolves the hosts in the background until the given channel is closed
+	Run(stop <-chan struct{})
+}
+
+// resolver implements the Resolver interface
+type resolver struct {
+	policyController policy.Controller
+	cfg              configurator.Configurator
+	msgBroker        *messaging.Broker
+
+	// lookupHost looks up the IP addresses of the given host
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+
+	cacheMutex sync.RWMutex
+	cache      map[string]*cacheEntry
+
+	// refreshChan is signaled to resolve the hosts that have not been resolved yet without waiting for the next
+	// refresh check
+	refreshChan chan struct{}
+}
+
+// cacheEntry is the IP addresses a host was last resolved to
+type cacheEntry struct {
+	ipAddresses []string
+	resolvedAt  time.Time
+}
@@ -0,0 +1,53 @@
+// Package egressgateway implements the helpers for the egress gateway. When the egress gateway is enabled, sidecars
+// route the Egress traffic allowed by Egress policies to the egress gateway over mTLS, and the gateway enforces the
+// Egress policies before originating the traffic to the external destination from a stable source.
+package egressgateway
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// ServiceName is the name of the egress gateway's Kubernetes service
+	ServiceName = "osm-egress-gateway"
+
+	// ListenerPort is the port on which the egress gateway accepts mTLS connections from sidecars
+	ListenerPort = 15805
+
+	// serverNameSuffix is the suffix of the SNI sidecars use to request an external destination from the egress gateway
+	serverNameSuffix = "egress.osm"
+)
+
+// GetEgressGatewaySubjectCommonName creates a unique certificate.CommonName
+// specifically for an Egress Gateway. Each gateway will have its own unique
+// cert. The kind of Envoy (egress gateway) is encoded in the cert CN by convention.
+func GetEgressGatewaySubjectCommonName(serviceAccount, namespace string) certificate.CommonName {
+	gatewayUID := uuid.New()
+	envoyType := envoy.KindEgressGateway
+	return envoy.NewXDSCertCommonName(gatewayUID, envoyType, serviceAccount, namespace)
+}
+
+// GetService returns the MeshService corresponding to the egress gateway deployed in the given namespace
+func GetService(osmNamespace string) service.MeshService {
+	return service.MeshService{
+		Name:       ServiceName,
+		Namespace:  osmNamespace,
+		Port:       ListenerPort,
+		TargetPort: ListenerPort,
+		Protocol:   constants.ProtocolTCP,
+	}
+}
+
+// GetServerName returns the SNI used by sidecars to request the given external host and port from the egress gateway.
+// The egress gateway matches the SNI to the external destination, since the original destination of the traffic is
+// not known to the gateway.
+func GetServerName(host string, port int) string {
+	return fmt.Sprintf("%s.%d.%s", host, port, serverNameSuffix)
+}
@@ -0,0 +1,38 @@
+package egressgateway
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestEgressGatewayHelpers(t *testing.T) {
+	assert := tassert.New(t)
+	serviceAccount := "-svc-account-"
+	namespace := "-namespace-"
+
+	actualCN := GetEgressGatewaySubjectCommonName(serviceAccount, namespace)
+	expectedSuffix := ".egress-gateway.-svc-account-.-namespace-.cluster.local"
+	assert.True(strings.HasSuffix(actualCN.String(), expectedSuffix), fmt.Sprintf("Expected the Proxy Cert's Common Name to end with %s", expectedSuffix))
+
+	// Is the kind of proxy properly encoded in this certificate?
+	actualProxyKind, err := envoy.GetKindFromProxyCertificate(actualCN)
+	assert.Nil(err)
+	assert.Equal(envoy.KindEgressGateway, actualProxyKind)
+
+	// Is the service identity properly encoded in this certificate?
+	actualIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(actualCN)
+	assert.Nil(err)
+	assert.Equal("-svc-account-.-namespace-.cluster.local", actualIdentity.String())
+
+	svc := GetService("osm-system")
+	assert.Equal("osm-system/osm-egress-gateway", svc.String())
+	assert.Equal("osm-egress-gateway.osm-system.svc.cluster.local", svc.FQDN())
+	assert.EqualValues(ListenerPort, svc.TargetPort)
+
+	assert.Equal("api.github.com.443.egress.osm", GetServerName("api.github.com", 443))
+}
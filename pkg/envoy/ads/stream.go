@@ -343,6 +343,14 @@ func (s *Server) recordPodMetadata(p *envoy.Proxy) error {
 			Msgf("Proxy is a Multicluster gateway, skipping recording pod metadata")
 		return nil
 	}
+	if p.Kind() == envoy.KindEgressGateway {
+		log.Debug().Str("proxy", p.String()).Msgf("Proxy is an egress gateway, skipping recording pod metadata")
+		return nil
+	}
+	if p.Kind() == envoy.KindIngressGateway {
+		log.Debug().Str("proxy", p.String()).Msgf("Proxy is an ingress gateway, skipping recording pod metadata")
+		return nil
+	}
 
 	pod, err := envoy.GetPodFromCertificate(p.GetCertificateCommonName(), s.kubecontroller)
 	if err != nil {
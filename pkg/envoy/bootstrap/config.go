@@ -1,11 +1,14 @@
 package bootstrap
 
 import (
+	"sort"
+
 	xds_accesslog_config "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	xds_bootstrap "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
 	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_metrics "github.com/envoyproxy/go-control-plane/envoy/config/metrics/v3"
 	xds_accesslog_stream "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/stream/v3"
 	xds_transport_sockets "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_upstream_http "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
@@ -19,6 +22,11 @@ import (
 
 // BuildFromConfig builds and returns an Envoy Bootstrap object from the given config
 func BuildFromConfig(config Config) (*xds_bootstrap.Bootstrap, error) {
+	adminAddress := config.AdminAddress
+	if adminAddress == "" {
+		adminAddress = constants.LocalhostIPAddress
+	}
+
 	httpProtocolOptions := &xds_upstream_http.HttpProtocolOptions{
 		UpstreamProtocolOptions: &xds_upstream_http.HttpProtocolOptions_ExplicitHttpConfig_{
 			ExplicitHttpConfig: &xds_upstream_http.HttpProtocolOptions_ExplicitHttpConfig{
@@ -98,7 +106,7 @@ func BuildFromConfig(config Config) (*xds_bootstrap.Bootstrap, error) {
 			Address: &xds_core.Address{
 				Address: &xds_core.Address_SocketAddress{
 					SocketAddress: &xds_core.SocketAddress{
-						Address: constants.LocalhostIPAddress,
+						Address: adminAddress,
 						PortSpecifier: &xds_core.SocketAddress_PortValue{
 							PortValue: config.AdminPort,
 						},
@@ -106,6 +114,9 @@ func BuildFromConfig(config Config) (*xds_bootstrap.Bootstrap, error) {
 				},
 			},
 		},
+		StatsConfig: &xds_metrics.StatsConfig{
+			StatsTags: append(envoy.GetEgressStatsTags(), getFixedStatsTags(config.StatsTags)...),
+		},
 		DynamicResources: &xds_bootstrap.Bootstrap_DynamicResources{
 			AdsConfig: &xds_core.ApiConfigSource{
 				ApiType:             xds_core.ApiConfigSource_GRPC,
@@ -183,3 +194,21 @@ func BuildFromConfig(config Config) (*xds_bootstrap.Bootstrap, error) {
 
 	return bootstrap, nil
 }
+
+// getFixedStatsTags returns the stats tags with the given fixed values, sorted by tag name
+func getFixedStatsTags(statsTags map[string]string) []*xds_metrics.TagSpecifier {
+	var tagNames []string
+	for tagName := range statsTags {
+		tagNames = append(tagNames, tagName)
+	}
+	sort.Strings(tagNames)
+
+	var tags []*xds_metrics.TagSpecifier
+	for _, tagName := range tagNames {
+		tags = append(tags, &xds_metrics.TagSpecifier{
+			TagName:  tagName,
+			TagValue: &xds_metrics.TagSpecifier_FixedValue{FixedValue: statsTags[tagName]},
+		})
+	}
+	return tags
+}
@@ -93,6 +93,41 @@ static_resources:
         '@type': type.googleapis.com/envoy.extensions.upstreams.http.v3.HttpProtocolOptions
         explicit_http_config:
           http2_protocol_options: {}
+stats_config:
+  stats_tags:
+  - regex: ^(?:cluster\.osm-egress|(?:http|tcp)\.osm-egress-denied)\.(([^.]+)\.)[^.]+\.
+    tag_name: osm_egress_source_namespace
+  - regex: ^(?:cluster\.osm-egress|(?:http|tcp)\.osm-egress-denied)\.[^.]+\.(([^.]+)\.)
+    tag_name: osm_egress_source_service_account
+  - regex: ^cluster\.osm-egress\.[^.]+\.[^.]+\.(([^.]+)\.)\d+\.
+    tag_name: osm_egress_host
+  - regex: ^(?:cluster\.osm-egress\.[^.]+\.[^.]+\.[^.]+\.|(?:http|tcp)\.osm-egress-denied\.[^.]+\.[^.]+\.)((\d+|\*)\.)
+    tag_name: osm_egress_port
 `
 	assert.Equal(expectedYAML, string(actualYAML))
 }
+
+func TestBuildFromConfigWithStatsTags(t *testing.T) {
+	assert := tassert.New(t)
+	cert := tresor.NewFakeCertificate()
+
+	bootstrapConfig, err := BuildFromConfig(Config{
+		NodeID:           cert.GetCommonName().String(),
+		AdminPort:        15000,
+		XDSClusterName:   constants.OSMControllerName,
+		TrustedCA:        cert.GetIssuingCA(),
+		CertificateChain: cert.GetCertificateChain(),
+		PrivateKey:       cert.GetPrivateKey(),
+		XDSHost:          "osm-controller.osm-system.svc.cluster.local",
+		XDSPort:          15128,
+		StatsTags:        map[string]string{"team": "bookstore", "cluster": "east"},
+	})
+	assert.Nil(err)
+
+	statsTags := bootstrapConfig.StatsConfig.StatsTags
+	assert.Len(statsTags, 6)
+	assert.Equal("cluster", statsTags[4].TagName)
+	assert.Equal("east", statsTags[4].GetFixedValue())
+	assert.Equal("team", statsTags[5].TagName)
+	assert.Equal("bookstore", statsTags[5].GetFixedValue())
+}
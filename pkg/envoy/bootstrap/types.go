@@ -33,4 +33,11 @@ type Config struct {
 
 	// PrivateKey is the private key for the certificate used by the proxy to connect to the XDS cluster
 	PrivateKey []byte
+
+	// StatsTags are the tags, and their fixed values, added to all the stats of the proxy
+	StatsTags map[string]string
+
+	// AdminAddress is the loopback IP address the Envoy admin interface listens on, matching the primary IP family
+	// of the proxy's pod. It defaults to the IPv4 loopback address.
+	AdminAddress string
 }
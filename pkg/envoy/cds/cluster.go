@@ -1,12 +1,14 @@
 package cds
 
 import (
+	"net"
 	"strings"
 	"time"
 
 	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_dfp_cluster "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/dynamic_forward_proxy/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
@@ -16,11 +18,14 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/secrets"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
 )
 
 // replacer used to configure an Envoy cluster's altStatName
@@ -56,7 +61,19 @@ func getUpstreamServiceCluster(downstreamIdentity identity.ServiceIdentity, conf
 	// Configure service discovery based on traffic policies
 	remoteCluster.ClusterDiscoveryType = &xds_cluster.Cluster_Type{Type: xds_cluster.Cluster_EDS}
 	remoteCluster.EdsClusterConfig = &xds_cluster.Cluster_EdsClusterConfig{EdsConfig: envoy.GetADSConfigSource()}
-	remoteCluster.LbPolicy = xds_cluster.Cluster_ROUND_ROBIN
+	remoteCluster.LbPolicy = getClusterLbPolicy(config.LoadBalancer)
+
+	if config.EnableLocalityWeightedLb {
+		remoteCluster.CommonLbConfig = &xds_cluster.Cluster_CommonLbConfig{
+			LocalityConfigSpecifier: &xds_cluster.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+				LocalityWeightedLbConfig: &xds_cluster.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+			},
+		}
+	}
+
+	if config.OutlierDetection != nil {
+		remoteCluster.OutlierDetection = getOutlierDetection(config.OutlierDetection)
+	}
 
 	if config.EnableEnvoyActiveHealthChecks {
 		enableHealthChecksOnCluster(remoteCluster, config.Service)
@@ -64,6 +81,21 @@ func getUpstreamServiceCluster(downstreamIdentity identity.ServiceIdentity, conf
 	return remoteCluster
 }
 
+// getOutlierDetection returns the Envoy outlier detection config corresponding to the given outlier detection config.
+// Both gateway errors and 5xx errors are considered, so that unreachable remote gateways are ejected along with
+// local endpoints returning errors.
+func getOutlierDetection(outlierDetection *trafficpolicy.OutlierDetection) *xds_cluster.OutlierDetection {
+	return &xds_cluster.OutlierDetection{
+		Consecutive_5Xx:                    wrapperspb.UInt32(outlierDetection.ConsecutiveErrors),
+		ConsecutiveGatewayFailure:          wrapperspb.UInt32(outlierDetection.ConsecutiveErrors),
+		EnforcingConsecutive_5Xx:           wrapperspb.UInt32(100),
+		EnforcingConsecutiveGatewayFailure: wrapperspb.UInt32(100),
+		Interval:                           durationpb.New(outlierDetection.Interval),
+		BaseEjectionTime:                   durationpb.New(outlierDetection.BaseEjectionTime),
+		MaxEjectionPercent:                 wrapperspb.UInt32(outlierDetection.MaxEjectionPercent),
+	}
+}
+
 // getMulticlusterGatewayUpstreamServiceCluster returns an Envoy Cluster corresponding to the given upstream service for the multicluster gateway
 func getMulticlusterGatewayUpstreamServiceCluster(catalog catalog.MeshCataloger, upstreamSvc service.MeshService, withActiveHealthChecks bool) (*xds_cluster.Cluster, error) {
 	HTTP2ProtocolOptions, err := envoy.GetHTTP2ProtocolOptions()
@@ -100,6 +132,72 @@ func getMulticlusterGatewayUpstreamServiceCluster(catalog catalog.MeshCataloger,
 	return remoteCluster, nil
 }
 
+// getMulticlusterGatewayHTTPUpstreamCluster returns the cluster used by the multicluster gateway to re-originate mTLS
+// to a local HTTP service using the gateway's identity
+func getMulticlusterGatewayHTTPUpstreamCluster(gatewayIdentity identity.ServiceIdentity, upstream *trafficpolicy.MulticlusterGatewayHTTPUpstream, withActiveHealthChecks bool) (*xds_cluster.Cluster, error) {
+	HTTP2ProtocolOptions, err := envoy.GetHTTP2ProtocolOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(envoy.GetUpstreamTLSContext(gatewayIdentity, upstream.Service))
+	if err != nil {
+		return nil, err
+	}
+
+	upstreamCluster := &xds_cluster.Cluster{
+		Name: upstream.ClusterName,
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_STRICT_DNS,
+		},
+		LbPolicy:                      xds_cluster.Cluster_ROUND_ROBIN,
+		TypedExtensionProtocolOptions: HTTP2ProtocolOptions,
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledUpstreamTLSContext,
+			},
+		},
+		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+			ClusterName: upstream.ClusterName,
+			Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*xds_endpoint.LbEndpoint{{
+						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &xds_endpoint.Endpoint{
+								// The mTLS connection is originated to the service's cluster IP, and is
+								// received by the upstream proxy on the service port
+								Address: envoy.GetAddress(upstream.Service.ServerName(), uint32(upstream.Service.Port)),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	if withActiveHealthChecks {
+		enableHealthChecksOnCluster(upstreamCluster, upstream.Service)
+	}
+	return upstreamCluster, nil
+}
+
+// getClusterLbPolicy returns the Envoy cluster load balancing policy corresponding to the given load balancer config
+func getClusterLbPolicy(lbConfig *trafficpolicy.LoadBalancerConfig) xds_cluster.Cluster_LbPolicy {
+	if lbConfig == nil {
+		return xds_cluster.Cluster_ROUND_ROBIN
+	}
+
+	switch lbConfig.Algorithm {
+	case trafficpolicy.RingHashLoadBalancer:
+		return xds_cluster.Cluster_RING_HASH
+	case trafficpolicy.MaglevLoadBalancer:
+		return xds_cluster.Cluster_MAGLEV
+	default:
+		return xds_cluster.Cluster_ROUND_ROBIN
+	}
+}
+
 func enableHealthChecksOnCluster(cluster *xds_cluster.Cluster, upstreamSvc service.MeshService) {
 	cluster.HealthChecks = []*xds_core.HealthCheck{
 		{
@@ -133,6 +231,11 @@ func getLocalServiceCluster(config trafficpolicy.MeshClusterConfig) *xds_cluster
 		return nil
 	}
 
+	dnsLookupFamily := xds_cluster.Cluster_V4_ONLY
+	if utils.IsIPv6(net.ParseIP(config.Address)) {
+		dnsLookupFamily = xds_cluster.Cluster_V6_ONLY
+	}
+
 	return &xds_cluster.Cluster{
 		// The name must match the domain being cURLed in the demo
 		Name:          config.Name,
@@ -142,7 +245,7 @@ func getLocalServiceCluster(config trafficpolicy.MeshClusterConfig) *xds_cluster
 		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
 			Type: xds_cluster.Cluster_STRICT_DNS,
 		},
-		DnsLookupFamily: xds_cluster.Cluster_V4_ONLY,
+		DnsLookupFamily: dnsLookupFamily,
 		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
 			// NOTE: results.MeshService is the top level service that is cURLed.
 			ClusterName: config.Name,
@@ -170,7 +273,7 @@ func getLocalServiceCluster(config trafficpolicy.MeshClusterConfig) *xds_cluster
 }
 
 // getPrometheusCluster returns an Envoy Cluster responsible for scraping metrics by Prometheus
-func getPrometheusCluster() *xds_cluster.Cluster {
+func getPrometheusCluster(localhostIPAddress string) *xds_cluster.Cluster {
 	return &xds_cluster.Cluster{
 		Name:        constants.EnvoyMetricsCluster,
 		AltStatName: constants.EnvoyMetricsCluster,
@@ -186,7 +289,7 @@ func getPrometheusCluster() *xds_cluster.Cluster {
 					LbEndpoints: []*xds_endpoint.LbEndpoint{{
 						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
 							Endpoint: &xds_endpoint.Endpoint{
-								Address: envoy.GetAddress(constants.LocalhostIPAddress, constants.EnvoyAdminPort),
+								Address: envoy.GetAddress(localhostIPAddress, constants.EnvoyAdminPort),
 							},
 						},
 						LoadBalancingWeight: &wrappers.UInt32Value{
@@ -200,23 +303,44 @@ func getPrometheusCluster() *xds_cluster.Cluster {
 }
 
 // getEgressClusters returns a slice of XDS cluster objects for the given egress cluster configs.
+// The stats of the clusters are tagged with the downstream identity and the external destination.
 // If the cluster config is invalid, an error is logged and the corresponding cluster config is ignored.
-func getEgressClusters(clusterConfigs []*trafficpolicy.EgressClusterConfig) []*xds_cluster.Cluster {
+func getEgressClusters(downstreamIdentity identity.ServiceIdentity, clusterConfigs []*trafficpolicy.EgressClusterConfig, osmNamespace string) []*xds_cluster.Cluster {
 	if clusterConfigs == nil {
 		return nil
 	}
 
 	var egressClusters []*xds_cluster.Cluster
 	for _, config := range clusterConfigs {
-		switch config.Host {
-		case "":
+		if config.ViaEgressGateway {
+			// Cluster config is routed through the egress gateway, which proxies the traffic to the Host
+			if cluster, err := getEgressGatewayCluster(downstreamIdentity, config, osmNamespace); err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingDNSEgressCluster)).
+					Msg("Error building the egress gateway cluster for the given egress cluster config")
+			} else {
+				egressClusters = append(egressClusters, withEgressStatName(cluster, downstreamIdentity, config))
+			}
+			continue
+		}
+
+		switch {
+		case config.Host == "":
 			// Cluster config does not have a Host specified, route it to its original destination.
 			// Used for TCP based clusters
 			if originalDestinationEgressCluster, err := getOriginalDestinationEgressCluster(config.Name); err != nil {
 				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingOrgDstEgressCluster)).
 					Msg("Error building the original destination cluster for the given egress cluster config")
 			} else {
-				egressClusters = append(egressClusters, originalDestinationEgressCluster)
+				egressClusters = append(egressClusters, withEgressStatName(originalDestinationEgressCluster, downstreamIdentity, config))
+			}
+		case utils.IsWildcardHost(config.Host):
+			// Cluster config has a wildcard Host specified, route it to the host in the request resolved using DNS.
+			// Used for HTTP based clusters matching wildcard hosts
+			if cluster, err := getDynamicForwardProxyEgressCluster(config); err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingDNSEgressCluster)).
+					Msg("Error building dynamic forward proxy cluster for the given egress cluster config")
+			} else {
+				egressClusters = append(egressClusters, withEgressStatName(cluster, downstreamIdentity, config))
 			}
 		default:
 			// Cluster config has a Host specified, route it based on the Host resolved using DNS.
@@ -225,7 +349,7 @@ func getEgressClusters(clusterConfigs []*trafficpolicy.EgressClusterConfig) []*x
 				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingDNSEgressCluster)).
 					Msg("Error building cluster for the given egress cluster config")
 			} else {
-				egressClusters = append(egressClusters, cluster)
+				egressClusters = append(egressClusters, withEgressStatName(cluster, downstreamIdentity, config))
 			}
 		}
 	}
@@ -233,6 +357,13 @@ func getEgressClusters(clusterConfigs []*trafficpolicy.EgressClusterConfig) []*x
 	return egressClusters
 }
 
+// withEgressStatName sets the stat name of the given egress cluster so that its stats are tagged with the
+// source identity and the external destination
+func withEgressStatName(cluster *xds_cluster.Cluster, downstreamIdentity identity.ServiceIdentity, config *trafficpolicy.EgressClusterConfig) *xds_cluster.Cluster {
+	cluster.AltStatName = envoy.GetEgressClusterStatName(downstreamIdentity, config.Host, config.Port)
+	return cluster
+}
+
 // getDNSResolvableEgressCluster returns an XDS cluster object that is resolved using DNS for the given egress cluster config.
 // If the egress cluster config is invalid, an error is returned.
 func getDNSResolvableEgressCluster(config *trafficpolicy.EgressClusterConfig) (*xds_cluster.Cluster, error) {
@@ -249,7 +380,7 @@ func getDNSResolvableEgressCluster(config *trafficpolicy.EgressClusterConfig) (*
 		return nil, errors.New("Invalid egress cluster config: Port unspecified")
 	}
 
-	return &xds_cluster.Cluster{
+	cluster := &xds_cluster.Cluster{
 		Name:        config.Name,
 		AltStatName: formatAltStatNameForPrometheus(config.Name),
 		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
@@ -273,9 +404,195 @@ func getDNSResolvableEgressCluster(config *trafficpolicy.EgressClusterConfig) (*
 				},
 			},
 		},
+	}
+
+	if config.TLS != nil {
+		// Originate TLS to the external host
+		transportSocket, err := getEgressTLSTransportSocket(config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		cluster.TransportSocket = transportSocket
+	}
+
+	return cluster, nil
+}
+
+// getDynamicForwardProxyEgressCluster returns an XDS cluster object for the given egress cluster config with a wildcard
+// host. The cluster resolves the host in the request's host header using DNS, so it must be used along with the dynamic
+// forward proxy HTTP filter, which only routes requests matching the wildcard host to the cluster.
+func getDynamicForwardProxyEgressCluster(config *trafficpolicy.EgressClusterConfig) (*xds_cluster.Cluster, error) {
+	if config.Name == "" {
+		return nil, errors.New("Invalid egress cluster config: Name unspecified")
+	}
+
+	marshalledClusterConfig, err := ptypes.MarshalAny(&xds_dfp_cluster.ClusterConfig{
+		DnsCacheConfig: envoy.GetEgressDNSCacheConfig(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_cluster.Cluster{
+		Name:        config.Name,
+		AltStatName: formatAltStatNameForPrometheus(config.Name),
+		ClusterDiscoveryType: &xds_cluster.Cluster_ClusterType{
+			ClusterType: &xds_cluster.Cluster_CustomClusterType{
+				Name:        envoy.DynamicForwardProxyClusterType,
+				TypedConfig: marshalledClusterConfig,
+			},
+		},
+		LbPolicy: xds_cluster.Cluster_CLUSTER_PROVIDED,
+	}, nil
+}
+
+// getEgressTLSTransportSocket returns the transport socket used to originate TLS to an external host
+func getEgressTLSTransportSocket(tlsConfig *trafficpolicy.EgressTLSConfig) (*xds_core.TransportSocket, error) {
+	if tlsConfig.SNI == "" {
+		return nil, errors.New("Invalid egress TLS config: SNI unspecified")
+	}
+
+	caSDSCert := secrets.SDSCert{
+		Name:     tlsConfig.CACertSecret.String(),
+		CertType: secrets.EgressCACertType,
+	}
+	var clientSDSCert *secrets.SDSCert
+	if tlsConfig.ClientCertSecret != nil {
+		clientSDSCert = &secrets.SDSCert{
+			Name:     tlsConfig.ClientCertSecret.String(),
+			CertType: secrets.EgressClientCertType,
+		}
+	}
+
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(envoy.GetEgressUpstreamTLSContext(tlsConfig.SNI, caSDSCert, clientSDSCert))
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_core.TransportSocket{
+		Name: wellknown.TransportSocketTls,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{
+			TypedConfig: marshalledUpstreamTLSContext,
+		},
+	}, nil
+}
+
+// getEgressGatewayCluster returns an Envoy cluster that routes the traffic for the given egress cluster config through
+// the egress gateway over mTLS. The SNI requests the external destination in the egress cluster config from the gateway.
+func getEgressGatewayCluster(downstreamIdentity identity.ServiceIdentity, config *trafficpolicy.EgressClusterConfig, osmNamespace string) (*xds_cluster.Cluster, error) {
+	if config.Host == "" {
+		return nil, errors.New("Invalid egress cluster config: Host unspecified")
+	}
+
+	gatewaySvc := egressgateway.GetService(osmNamespace)
+	upstreamTLSContext := envoy.GetUpstreamTLSContext(downstreamIdentity, gatewaySvc)
+	upstreamTLSContext.Sni = egressgateway.GetServerName(config.Host, config.Port)
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(upstreamTLSContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_cluster.Cluster{
+		Name:        config.Name,
+		AltStatName: formatAltStatNameForPrometheus(config.Name),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_STRICT_DNS,
+		},
+		LbPolicy: xds_cluster.Cluster_ROUND_ROBIN,
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledUpstreamTLSContext,
+			},
+		},
+		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+			ClusterName: config.Name,
+			Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*xds_endpoint.LbEndpoint{{
+						HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+							Endpoint: &xds_endpoint.Endpoint{
+								Address: envoy.GetAddress(gatewaySvc.FQDN(), egressgateway.ListenerPort),
+							},
+						},
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: constants.ClusterWeightAcceptAll,
+						},
+					}},
+				},
+			},
+		},
 	}, nil
 }
 
+// getEgressGatewayUpstreamClusters returns the egress gateway's clusters for the external destinations it proxies traffic to
+func getEgressGatewayUpstreamClusters(upstreams []*trafficpolicy.EgressGatewayUpstream) []*xds_cluster.Cluster {
+	var clusters []*xds_cluster.Cluster
+	for _, upstream := range upstreams {
+		cluster, err := getDNSResolvableEgressCluster(&trafficpolicy.EgressClusterConfig{
+			Name: upstream.ClusterName,
+			Host: upstream.Host,
+			Port: upstream.Port,
+			TLS:  upstream.TLS,
+		})
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingDNSEgressCluster)).
+				Msgf("Error building egress gateway cluster for upstream %s", upstream.ClusterName)
+			continue
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
+
+// getIngressGatewayUpstreamClusters returns the ingress gateway's clusters for the mesh services it routes traffic to.
+// The ingress gateway originates mTLS to the services using the given gateway identity.
+func getIngressGatewayUpstreamClusters(gatewayIdentity identity.ServiceIdentity, upstreams []*trafficpolicy.IngressGatewayUpstream) []*xds_cluster.Cluster {
+	var clusters []*xds_cluster.Cluster
+	for _, upstream := range upstreams {
+		marshalledUpstreamTLSContext, err := ptypes.MarshalAny(envoy.GetIngressGatewayUpstreamTLSContext(gatewayIdentity, upstream.Service))
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+				Msgf("Error marshalling UpstreamTlsContext for ingress gateway upstream %s", upstream.Service)
+			continue
+		}
+
+		clusterName := upstream.Service.EnvoyClusterName()
+		clusters = append(clusters, &xds_cluster.Cluster{
+			Name: clusterName,
+			ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+				Type: xds_cluster.Cluster_STRICT_DNS,
+			},
+			LbPolicy: xds_cluster.Cluster_ROUND_ROBIN,
+			TransportSocket: &xds_core.TransportSocket{
+				Name: wellknown.TransportSocketTls,
+				ConfigType: &xds_core.TransportSocket_TypedConfig{
+					TypedConfig: marshalledUpstreamTLSContext,
+				},
+			},
+			LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+				ClusterName: clusterName,
+				Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+					{
+						LbEndpoints: []*xds_endpoint.LbEndpoint{{
+							HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+								Endpoint: &xds_endpoint.Endpoint{
+									// The mTLS connection is originated to the service's cluster IP, and is
+									// received by the upstream proxy on the service port
+									Address: envoy.GetAddress(upstream.Service.ServerName(), uint32(upstream.Service.Port)),
+								},
+							},
+						}},
+					},
+				},
+			},
+		})
+	}
+
+	return clusters
+}
+
 // getOriginalDestinationEgressCluster returns an Envoy cluster that routes traffic to its original destination.
 // The original destination is the original IP address and port prior to being redirected to the sidecar proxy.
 func getOriginalDestinationEgressCluster(name string) (*xds_cluster.Cluster, error) {
@@ -311,11 +628,13 @@ func upstreamClustersFromClusterConfigs(downstreamIdentity identity.ServiceIdent
 	return clusters
 }
 
-func localClustersFromClusterConfigs(configs []*trafficpolicy.MeshClusterConfig) []*xds_cluster.Cluster {
+func localClustersFromClusterConfigs(configs []*trafficpolicy.MeshClusterConfig, localhostIPAddress string) []*xds_cluster.Cluster {
 	var clusters []*xds_cluster.Cluster
 
 	for _, c := range configs {
-		clusters = append(clusters, getLocalServiceCluster(*c))
+		config := *c
+		config.Address = localhostIPAddress
+		clusters = append(clusters, getLocalServiceCluster(config))
 	}
 	return clusters
 }
@@ -2,17 +2,26 @@ package cds
 
 import (
 	"testing"
+	"time"
 
 	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_dfp_cluster "github.com/envoyproxy/go-control-plane/envoy/extensions/clusters/dynamic_forward_proxy/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
@@ -63,6 +72,83 @@ func TestGetUpstreamServiceCluster(t *testing.T) {
 	}
 }
 
+func TestGetUpstreamServiceClusterWithFailover(t *testing.T) {
+	assert := tassert.New(t)
+
+	clusterConfig := trafficpolicy.MeshClusterConfig{
+		Name: "default/bookstore-v1_14001",
+		Service: service.MeshService{
+			Namespace: "default",
+			Name:      "bookstore-v1",
+			Port:      14001,
+		},
+		EnableLocalityWeightedLb: true,
+		OutlierDetection: &trafficpolicy.OutlierDetection{
+			ConsecutiveErrors:  5,
+			Interval:           10 * time.Second,
+			BaseEjectionTime:   30 * time.Second,
+			MaxEjectionPercent: 50,
+		},
+	}
+
+	remoteCluster := getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, clusterConfig)
+	assert.NotNil(remoteCluster)
+	assert.NotNil(remoteCluster.CommonLbConfig.GetLocalityWeightedLbConfig())
+
+	expectedOutlierDetection := &xds_cluster.OutlierDetection{
+		Consecutive_5Xx:                    &wrappers.UInt32Value{Value: 5},
+		ConsecutiveGatewayFailure:          &wrappers.UInt32Value{Value: 5},
+		EnforcingConsecutive_5Xx:           &wrappers.UInt32Value{Value: 100},
+		EnforcingConsecutiveGatewayFailure: &wrappers.UInt32Value{Value: 100},
+		Interval:                           durationpb.New(10 * time.Second),
+		BaseEjectionTime:                   durationpb.New(30 * time.Second),
+		MaxEjectionPercent:                 &wrappers.UInt32Value{Value: 50},
+	}
+	assert.True(proto.Equal(expectedOutlierDetection, remoteCluster.OutlierDetection))
+
+	clusterConfig.EnableLocalityWeightedLb = false
+	clusterConfig.OutlierDetection = nil
+	remoteCluster = getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, clusterConfig)
+	assert.Nil(remoteCluster.CommonLbConfig)
+	assert.Nil(remoteCluster.OutlierDetection)
+}
+
+func TestGetClusterLbPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		lbConfig *trafficpolicy.LoadBalancerConfig
+		expected xds_cluster.Cluster_LbPolicy
+	}{
+		{
+			name:     "round robin when unset",
+			lbConfig: nil,
+			expected: xds_cluster.Cluster_ROUND_ROBIN,
+		},
+		{
+			name:     "round robin",
+			lbConfig: &trafficpolicy.LoadBalancerConfig{Algorithm: trafficpolicy.RoundRobinLoadBalancer},
+			expected: xds_cluster.Cluster_ROUND_ROBIN,
+		},
+		{
+			name:     "ring hash",
+			lbConfig: &trafficpolicy.LoadBalancerConfig{Algorithm: trafficpolicy.RingHashLoadBalancer},
+			expected: xds_cluster.Cluster_RING_HASH,
+		},
+		{
+			name:     "maglev",
+			lbConfig: &trafficpolicy.LoadBalancerConfig{Algorithm: trafficpolicy.MaglevLoadBalancer},
+			expected: xds_cluster.Cluster_MAGLEV,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getClusterLbPolicy(tc.lbConfig))
+		})
+	}
+}
+
 func TestGetMulticlusterGatewayUpstreamServiceCluster(t *testing.T) {
 	upstreamSvc := service.MeshService{
 		Namespace:  "ns1",
@@ -136,6 +222,74 @@ func TestGetMulticlusterGatewayUpstreamServiceCluster(t *testing.T) {
 	}
 }
 
+func TestGetMulticlusterGatewayHTTPUpstreamCluster(t *testing.T) {
+	assert := tassert.New(t)
+
+	gatewayIdentity := identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity()
+	upstream := &trafficpolicy.MulticlusterGatewayHTTPUpstream{
+		Service: service.MeshService{
+			Namespace:  "ns1",
+			Name:       "s1",
+			Port:       80,
+			TargetPort: 8080,
+		},
+		Identity:    identity.K8sServiceAccount{Name: "s1", Namespace: "ns1"}.ToServiceIdentity(),
+		ClusterName: "ns1/s1|80",
+	}
+
+	upstreamCluster, err := getMulticlusterGatewayHTTPUpstreamCluster(gatewayIdentity, upstream, false)
+	assert.NoError(err)
+	assert.Equal("ns1/s1|80", upstreamCluster.Name)
+	assert.Equal(xds_cluster.Cluster_STRICT_DNS, upstreamCluster.GetType())
+	assert.Equal(xds_cluster.Cluster_ROUND_ROBIN, upstreamCluster.LbPolicy)
+	assert.Nil(upstreamCluster.HealthChecks)
+
+	// The connection is originated to the service port of the cluster IP
+	assert.Equal(upstream.ClusterName, upstreamCluster.LoadAssignment.ClusterName)
+	assert.Len(upstreamCluster.LoadAssignment.Endpoints, 1)
+	assert.Equal(envoy.GetAddress("s1.ns1.svc.cluster.local", 80),
+		upstreamCluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address)
+
+	// mTLS is originated using the gateway's identity
+	expectedTLSContext, err := ptypes.MarshalAny(envoy.GetUpstreamTLSContext(gatewayIdentity, upstream.Service))
+	assert.NoError(err)
+	assert.True(proto.Equal(expectedTLSContext, upstreamCluster.TransportSocket.GetTypedConfig()))
+
+	upstreamCluster, err = getMulticlusterGatewayHTTPUpstreamCluster(gatewayIdentity, upstream, true)
+	assert.NoError(err)
+	assert.NotNil(upstreamCluster.HealthChecks)
+}
+
+func TestGetIngressGatewayUpstreamClusters(t *testing.T) {
+	assert := tassert.New(t)
+
+	gatewayIdentity := identity.ServiceIdentity("osm-ingress-gateway.osm-system.cluster.local")
+	upstreamSvc := service.MeshService{
+		Namespace:  "ns1",
+		Name:       "s1",
+		Port:       80,
+		TargetPort: 8080,
+	}
+
+	clusters := getIngressGatewayUpstreamClusters(gatewayIdentity, []*trafficpolicy.IngressGatewayUpstream{
+		{Service: upstreamSvc, Protocol: constants.ProtocolHTTP},
+	})
+	assert.Len(clusters, 1)
+	assert.Equal("ns1/s1|8080", clusters[0].Name)
+	assert.Equal(xds_cluster.Cluster_STRICT_DNS, clusters[0].GetType())
+
+	// The connection is originated to the service port of the cluster IP
+	assert.Equal(envoy.GetAddress("s1.ns1.svc.cluster.local", 80),
+		clusters[0].LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address)
+
+	// mTLS is originated using the ingress gateway's certificate, without the in-mesh ALPN
+	tlsContext := &xds_auth.UpstreamTlsContext{}
+	assert.NoError(ptypes.UnmarshalAny(clusters[0].TransportSocket.GetTypedConfig(), tlsContext))
+	assert.Equal("ingress-gateway-cert:osm-ingress-gateway.osm-system.cluster.local", tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+	assert.Equal(upstreamSvc.ServerName(), tlsContext.Sni)
+	assert.Empty(tlsContext.CommonTlsContext.AlpnProtocols)
+}
+
 func TestGetLocalServiceCluster(t *testing.T) {
 	testCases := []struct {
 		name                             string
@@ -236,7 +390,7 @@ func TestGetPrometheusCluster(t *testing.T) {
 		},
 	}
 
-	actual := *getPrometheusCluster()
+	actual := *getPrometheusCluster(constants.LocalhostIPAddress)
 	assert.Equal(expectedCluster.LoadAssignment.ClusterName, actual.LoadAssignment.ClusterName)
 	assert.Equal(len(expectedCluster.LoadAssignment.Endpoints[0].LbEndpoints), len(actual.LoadAssignment.Endpoints))
 	assert.Equal(expectedCluster.LoadAssignment.Endpoints[0].LbEndpoints, actual.LoadAssignment.Endpoints[0].LbEndpoints)
@@ -332,18 +486,68 @@ func TestGetEgressClusters(t *testing.T) {
 			},
 			expectedClusterCount: 2,
 		},
+		{
+			name: "cluster configs routed via the egress gateway",
+			clusterConfigs: []*trafficpolicy.EgressClusterConfig{
+				{
+					Name:             "foo.com:80",
+					Host:             "foo.com",
+					Port:             80,
+					ViaEgressGateway: true,
+				},
+				{
+					Name:             "bar.com:443",
+					Port:             443,
+					ViaEgressGateway: true,
+				},
+			},
+			expectedClusterCount: 1,
+		},
+		{
+			name: "cluster configs for wildcard and non-wildcard HTTP hosts",
+			clusterConfigs: []*trafficpolicy.EgressClusterConfig{
+				{
+					Name: "*.foo.com:80",
+					Host: "*.foo.com",
+					Port: 80,
+				},
+				{
+					Name: "bar.com:80",
+					Host: "bar.com",
+					Port: 80,
+				},
+			},
+			expectedClusterCount: 2,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
 
-			actual := getEgressClusters(tc.clusterConfigs)
+			actual := getEgressClusters(tests.BookbuyerServiceIdentity, tc.clusterConfigs, "osm-system")
 			assert.Len(actual, tc.expectedClusterCount)
 		})
 	}
 }
 
+func TestGetEgressClustersStatNames(t *testing.T) {
+	assert := tassert.New(t)
+
+	clusterConfigs := []*trafficpolicy.EgressClusterConfig{
+		{Name: "foo.com:80", Host: "foo.com", Port: 80},
+		{Name: "*.bar.com:80", Host: "*.bar.com", Port: 80},
+		{Name: "3306", Port: 3306},
+		{Name: "baz.com:443", Host: "baz.com", Port: 443, ViaEgressGateway: true},
+	}
+
+	actual := getEgressClusters(tests.BookbuyerServiceIdentity, clusterConfigs, "osm-system")
+	assert.Len(actual, len(clusterConfigs))
+	for i, cluster := range actual {
+		assert.Equal(envoy.GetEgressClusterStatName(tests.BookbuyerServiceIdentity, clusterConfigs[i].Host, clusterConfigs[i].Port), cluster.AltStatName)
+	}
+}
+
 func TestGetDNSResolvableEgressCluster(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -431,6 +635,96 @@ func TestGetDNSResolvableEgressCluster(t *testing.T) {
 	}
 }
 
+func TestGetDNSResolvableEgressClusterWithTLS(t *testing.T) {
+	assert := tassert.New(t)
+
+	config := &trafficpolicy.EgressClusterConfig{
+		Name: "foo.com:80",
+		Host: "foo.com",
+		Port: 80,
+		TLS: &trafficpolicy.EgressTLSConfig{
+			SNI:              "api.foo.com",
+			CACertSecret:     types.NamespacedName{Namespace: "test", Name: "ca"},
+			ClientCertSecret: &types.NamespacedName{Namespace: "test", Name: "client"},
+		},
+	}
+
+	cluster, err := getDNSResolvableEgressCluster(config)
+	assert.NoError(err)
+	assert.NotNil(cluster.TransportSocket)
+
+	upstreamTLSContext := &xds_auth.UpstreamTlsContext{}
+	err = ptypes.UnmarshalAny(cluster.TransportSocket.GetTypedConfig(), upstreamTLSContext)
+	assert.NoError(err)
+	assert.Equal("api.foo.com", upstreamTLSContext.Sni)
+
+	validationContext := upstreamTLSContext.CommonTlsContext.GetCombinedValidationContext()
+	assert.Equal("egress-ca-cert:test/ca", validationContext.ValidationContextSdsSecretConfig.Name)
+	assert.Equal("api.foo.com", validationContext.DefaultValidationContext.MatchSubjectAltNames[0].GetExact())
+	// A wildcard certificate for the domain of the SNI is valid for the SNI
+	assert.Equal("*.foo.com", validationContext.DefaultValidationContext.MatchSubjectAltNames[1].GetExact())
+	assert.Len(upstreamTLSContext.CommonTlsContext.TlsCertificateSdsSecretConfigs, 1)
+	assert.Equal("egress-client-cert:test/client", upstreamTLSContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name)
+
+	// The SNI must be specified to validate the external host's certificate
+	config.TLS.SNI = ""
+	_, err = getDNSResolvableEgressCluster(config)
+	assert.Error(err)
+}
+
+func TestGetDynamicForwardProxyEgressCluster(t *testing.T) {
+	assert := tassert.New(t)
+
+	cluster, err := getDynamicForwardProxyEgressCluster(&trafficpolicy.EgressClusterConfig{
+		Name: "*.foo.com:80",
+		Host: "*.foo.com",
+		Port: 80,
+	})
+	assert.NoError(err)
+	assert.Equal("*.foo.com:80", cluster.Name)
+	assert.Equal(xds_cluster.Cluster_CLUSTER_PROVIDED, cluster.LbPolicy)
+	assert.Nil(cluster.LoadAssignment)
+
+	customClusterType := cluster.GetClusterType()
+	assert.Equal(envoy.DynamicForwardProxyClusterType, customClusterType.Name)
+	clusterConfig := &xds_dfp_cluster.ClusterConfig{}
+	err = ptypes.UnmarshalAny(customClusterType.TypedConfig, clusterConfig)
+	assert.NoError(err)
+	assert.True(proto.Equal(envoy.GetEgressDNSCacheConfig(), clusterConfig.DnsCacheConfig))
+
+	_, err = getDynamicForwardProxyEgressCluster(&trafficpolicy.EgressClusterConfig{Host: "*.foo.com", Port: 80})
+	assert.Error(err)
+}
+
+func TestGetEgressGatewayCluster(t *testing.T) {
+	assert := tassert.New(t)
+
+	config := &trafficpolicy.EgressClusterConfig{
+		Name:             "foo.com:80",
+		Host:             "foo.com",
+		Port:             80,
+		ViaEgressGateway: true,
+	}
+
+	cluster, err := getEgressGatewayCluster(tests.BookbuyerServiceIdentity, config, "osm-system")
+	assert.NoError(err)
+	assert.Equal("foo.com:80", cluster.Name)
+	assert.Equal(xds_cluster.Cluster_STRICT_DNS, cluster.GetType())
+
+	address := cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress()
+	assert.Equal("osm-egress-gateway.osm-system.svc.cluster.local", address.Address)
+	assert.Equal(uint32(egressgateway.ListenerPort), address.GetPortValue())
+
+	upstreamTLSContext := &xds_auth.UpstreamTlsContext{}
+	err = ptypes.UnmarshalAny(cluster.TransportSocket.GetTypedConfig(), upstreamTLSContext)
+	assert.NoError(err)
+	assert.Equal("foo.com.80.egress.osm", upstreamTLSContext.Sni)
+
+	// The host must be specified to derive the SNI
+	_, err = getEgressGatewayCluster(tests.BookbuyerServiceIdentity, &trafficpolicy.EgressClusterConfig{Name: "foo", Port: 80}, "osm-system")
+	assert.Error(err)
+}
+
 func TestFormatAltStatNameForPrometheus(t *testing.T) {
 	testCases := []struct {
 		name                string
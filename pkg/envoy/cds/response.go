@@ -12,6 +12,8 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/ingressgateway"
 	"github.com/openservicemesh/osm/pkg/k8s"
 )
 
@@ -27,7 +29,25 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 	}
 
 	if proxy.Kind() == envoy.KindGateway && cfg.GetFeatureFlags().EnableMulticlusterMode {
+		// In HTTP mode, the gateway re-originates mTLS to the HTTP services it terminates traffic for
+		httpServices := mapset.NewSet()
+		if gatewayTrafficPolicy := meshCatalog.GetMulticlusterGatewayTrafficPolicy(); gatewayTrafficPolicy != nil {
+			for _, upstream := range gatewayTrafficPolicy.HTTPUpstreams {
+				cluster, err := getMulticlusterGatewayHTTPUpstreamCluster(proxyIdentity, upstream, cfg.GetFeatureFlags().EnableEnvoyActiveHealthChecks)
+				if err != nil {
+					log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrObtainingUpstreamServiceCluster)).Str("proxy", proxy.String()).
+						Msgf("Failed to construct HTTP service cluster for service %s for proxy", upstream.Service)
+					return nil, err
+				}
+				clusters = append(clusters, cluster)
+				httpServices.Add(upstream.Service)
+			}
+		}
+
 		for _, dstService := range meshCatalog.ListOutboundServicesForMulticlusterGateway() {
+			if httpServices.Contains(dstService) {
+				continue
+			}
 			cluster, err := getMulticlusterGatewayUpstreamServiceCluster(meshCatalog, dstService, cfg.GetFeatureFlags().EnableEnvoyActiveHealthChecks)
 			if err != nil {
 				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrObtainingUpstreamServiceCluster)).Str("proxy", proxy.String()).
@@ -39,6 +59,27 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		return removeDups(clusters), nil
 	}
 
+	if proxy.Kind() == envoy.KindEgressGateway {
+		// The egress gateway proxies the traffic allowed by Egress policies to the external destinations
+		if gatewayTrafficPolicy := meshCatalog.GetEgressGatewayTrafficPolicy(); gatewayTrafficPolicy != nil {
+			clusters = append(clusters, getEgressGatewayUpstreamClusters(gatewayTrafficPolicy.Upstreams)...)
+		}
+		return removeDups(clusters), nil
+	}
+
+	if proxy.Kind() == envoy.KindIngressGateway {
+		// The ingress gateway routes the traffic accepted by the Gateway listeners to the backends of the attached routes
+		gatewayCN, _, err := ingressgateway.GetClientCertificateSpec(cfg)
+		if err != nil {
+			log.Error().Err(err).Str("proxy", proxy.String()).Msg("Error getting the ingress gateway identity, skipping ingress gateway clusters")
+			return nil, err
+		}
+		if gatewayTrafficPolicy := meshCatalog.GetIngressGatewayTrafficPolicy(); gatewayTrafficPolicy != nil {
+			clusters = append(clusters, getIngressGatewayUpstreamClusters(identity.ServiceIdentity(gatewayCN), gatewayTrafficPolicy.Upstreams)...)
+		}
+		return removeDups(clusters), nil
+	}
+
 	// Build upstream clusters based on allowed outbound traffic policies
 	outboundMeshTrafficPolicy := meshCatalog.GetOutboundMeshTrafficPolicy(proxyIdentity)
 	if outboundMeshTrafficPolicy != nil {
@@ -54,7 +95,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 	}
 	inboundMeshTrafficPolicy := meshCatalog.GetInboundMeshTrafficPolicy(proxyIdentity, proxyServices)
 	if inboundMeshTrafficPolicy != nil {
-		clusters = append(clusters, localClustersFromClusterConfigs(inboundMeshTrafficPolicy.ClustersConfigs)...)
+		clusters = append(clusters, localClustersFromClusterConfigs(inboundMeshTrafficPolicy.ClustersConfigs, proxy.GetLocalhostIPAddress())...)
 	}
 
 	// Add egress clusters based on applied policies
@@ -62,7 +103,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		log.Error().Err(err).Msgf("Error retrieving egress policies for proxy with identity %s, skipping egress clusters", proxyIdentity)
 	} else {
 		if egressTrafficPolicy != nil {
-			clusters = append(clusters, getEgressClusters(egressTrafficPolicy.ClustersConfigs)...)
+			clusters = append(clusters, getEgressClusters(proxyIdentity, egressTrafficPolicy.ClustersConfigs, cfg.GetOSMNamespace())...)
 		}
 	}
 
@@ -82,7 +123,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 	if pod, err := envoy.GetPodFromCertificate(proxy.GetCertificateCommonName(), meshCatalog.GetKubeController()); err != nil {
 		log.Warn().Str("proxy", proxy.String()).Msg("Could not find pod for connecting proxy, no metadata was recorded")
 	} else if k8s.IsMetricsEnabled(pod) {
-		clusters = append(clusters, getPrometheusCluster())
+		clusters = append(clusters, getPrometheusCluster(proxy.GetLocalhostIPAddress()))
 	}
 
 	// Add an outbound tracing cluster (from localhost to tracing sink)
@@ -30,6 +30,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
@@ -479,6 +480,7 @@ func TestNewResponseGetEgressTrafficPolicyNotEmpty(t *testing.T) {
 	cfg.EXPECT().IsTracingEnabled().Return(false).Times(1)
 	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: false}).AnyTimes()
 	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
 
 	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
 	tassert.NoError(t, err)
@@ -504,12 +506,73 @@ func TestNewResponseForMulticlusterGateway(t *testing.T) {
 	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 	meshCatalog.EXPECT().ListOutboundServicesForMulticlusterGateway().Return([]service.MeshService{
 		tests.BookstoreV1Service,
+		tests.BookstoreV2Service,
+	}).AnyTimes()
+	// The bookstore-v2 service is terminated by the gateway in HTTP mode
+	meshCatalog.EXPECT().GetMulticlusterGatewayTrafficPolicy().Return(&trafficpolicy.MulticlusterGatewayTrafficPolicy{
+		HTTPUpstreams: []*trafficpolicy.MulticlusterGatewayHTTPUpstream{
+			{
+				Service:     tests.BookstoreV2Service,
+				Identity:    tests.BookstoreServiceIdentity,
+				ClusterName: tests.BookstoreV2Service.EnvoyClusterName(),
+			},
+		},
+	}).AnyTimes()
+
+	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
+	assert.NoError(err)
+	assert.Len(resp, 2)
+
+	httpCluster := resp[0].(*xds_cluster.Cluster)
+	assert.Equal(tests.BookstoreV2Service.EnvoyClusterName(), httpCluster.Name)
+	assert.NotNil(httpCluster.TransportSocket)
+
+	passthroughCluster := resp[1].(*xds_cluster.Cluster)
+	assert.Equal(tests.BookstoreV1Service.ServerName(), passthroughCluster.Name)
+	assert.Nil(passthroughCluster.TransportSocket)
+}
+
+func TestNewResponseForEgressGateway(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxyRegistry := registry.NewProxyRegistry(registry.ExplicitProxyServiceMapper(func(*envoy.Proxy) ([]service.MeshService, error) {
+		return nil, nil
+	}), nil)
+	cn := egressgateway.GetEgressGatewaySubjectCommonName("osm", "osm-system")
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	assert.Nil(err)
+
+	ctrl := gomock.NewController(t)
+	meshCatalog := catalog.NewMockMeshCataloger(ctrl)
+	cfg := configurator.NewMockConfigurator(ctrl)
+
+	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressGateway: true}).AnyTimes()
+	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	meshCatalog.EXPECT().GetEgressGatewayTrafficPolicy().Return(&trafficpolicy.EgressGatewayTrafficPolicy{
+		Upstreams: []*trafficpolicy.EgressGatewayUpstream{
+			{
+				Host:        "foo.com",
+				Port:        443,
+				ClusterName: "foo.com:443",
+				ServerName:  egressgateway.GetServerName("foo.com", 443),
+			},
+			{
+				Host:        "foo.com",
+				Port:        443,
+				ClusterName: "foo.com:443", // the test ensures this duplicate is removed
+				ServerName:  egressgateway.GetServerName("foo.com", 443),
+			},
+		},
 	}).AnyTimes()
 
 	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
 	assert.NoError(err)
 	assert.Len(resp, 1)
-	assert.Equal(tests.BookstoreV1Service.ServerName(), resp[0].(*xds_cluster.Cluster).Name)
+
+	cluster := resp[0].(*xds_cluster.Cluster)
+	assert.Equal("foo.com:443", cluster.Name)
+	assert.Equal(xds_cluster.Cluster_STRICT_DNS, cluster.GetType())
+	assert.Equal("foo.com", cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address.GetSocketAddress().Address)
 }
 
 func TestRemoveDups(t *testing.T) {
@@ -6,19 +6,21 @@ import (
 
 	"github.com/golang/protobuf/ptypes/wrappers"
 
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
 const (
-	localZone             = "local"
-	localClusterPriority  = uint32(0)
-	remoteClusterPriority = uint32(1)
+	localZone            = "local"
+	localClusterPriority = uint32(0)
 )
 
-// newClusterLoadAssignment returns the cluster load assignments for the given service and its endpoints
-func newClusterLoadAssignment(svc service.MeshService, serviceEndpoints []endpoint.Endpoint) *xds_endpoint.ClusterLoadAssignment {
+// newClusterLoadAssignment returns the cluster load assignments for the given service and its endpoints.
+// IPv6 endpoints are only programmed when IPv6 is enabled, because their traffic is not intercepted
+// by the destination's sidecar otherwise.
+func newClusterLoadAssignment(svc service.MeshService, serviceEndpoints []endpoint.Endpoint, enableIPv6 bool) *xds_endpoint.ClusterLoadAssignment {
 	localLbEndpoints := &xds_endpoint.LocalityLbEndpoints{
 		Locality: &xds_core.Locality{
 			Zone: localZone,
@@ -42,6 +44,11 @@ func newClusterLoadAssignment(svc service.MeshService, serviceEndpoints []endpoi
 	}
 
 	for _, meshEndpoint := range serviceEndpoints {
+		if meshEndpoint.IP.To4() == nil && !enableIPv6 {
+			log.Trace().Msgf("Skipping IPv6 endpoint %s for cluster %s, IPv6 is disabled", meshEndpoint, svc)
+			continue
+		}
+
 		lbEpt := &xds_endpoint.LbEndpoint{
 			HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
 				Endpoint: &xds_endpoint.Endpoint{
@@ -57,19 +64,25 @@ func newClusterLoadAssignment(svc service.MeshService, serviceEndpoints []endpoi
 			continue
 		}
 
-		// Endpoint belongs to a remote cluster, configure its locality
+		// Endpoint belongs to a remote cluster, configure its locality.
+		// The priority of a remote cluster is determined by the endpoints provider
+		// based on the failover policy for the service.
 		remoteLbEndpoints := &xds_endpoint.LocalityLbEndpoints{
 			Locality: &xds_core.Locality{
 				Zone: meshEndpoint.Zone,
 			},
 			LbEndpoints: []*xds_endpoint.LbEndpoint{lbEpt},
-			Priority:    remoteClusterPriority,
+			Priority:    uint32(meshEndpoint.Priority),
 			LoadBalancingWeight: &wrappers.UInt32Value{
 				Value: uint32(meshEndpoint.Weight),
 			},
 		}
-		if meshEndpoint.Priority != 0 {
-			remoteLbEndpoints.Priority = uint32(meshEndpoint.Priority)
+		// A remote cluster sharing the priority of the local cluster implies traffic is distributed
+		// between them based on their weights, so the local cluster must also be weighted.
+		if remoteLbEndpoints.Priority == localClusterPriority {
+			localLbEndpoints.LoadBalancingWeight = &wrappers.UInt32Value{
+				Value: constants.ClusterWeightAcceptAll,
+			}
 		}
 		cla.Endpoints = append(cla.Endpoints, remoteLbEndpoints)
 		log.Trace().Msgf("Adding Endpoint: cluster=%s, endpoint=%s, weight=%d", svc, meshEndpoint, meshEndpoint.Weight)
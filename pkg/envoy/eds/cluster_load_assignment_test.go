@@ -19,11 +19,77 @@ import (
 func TestNewClusterLoadAssignment(t *testing.T) {
 	remoteZoneName := "remote"
 	testCases := []struct {
-		name      string
-		svc       service.MeshService
-		endpoints []endpoint.Endpoint
-		expected  *xds_endpoint.ClusterLoadAssignment
+		name       string
+		svc        service.MeshService
+		endpoints  []endpoint.Endpoint
+		enableIPv6 bool
+		expected   *xds_endpoint.ClusterLoadAssignment
 	}{
+		{
+			name: "IPv6 endpoints are skipped when IPv6 is disabled",
+			svc:  service.MeshService{Namespace: "ns1", Name: "bookstore-1", TargetPort: 80},
+			endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("1.1.1.1"), Port: 80},
+				{IP: net.ParseIP("2001:db8::1"), Port: 80},
+			},
+			enableIPv6: false,
+			expected: &xds_endpoint.ClusterLoadAssignment{
+				ClusterName: "ns1/bookstore-1|80",
+				Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+					{
+						Locality: &xds_core.Locality{
+							Zone: localZone,
+						},
+						LbEndpoints: []*xds_endpoint.LbEndpoint{
+							{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("1.1.1.1", 80),
+									},
+								},
+							},
+						},
+						Priority: localClusterPriority,
+					},
+				},
+			},
+		},
+		{
+			name: "IPv4 and IPv6 endpoints are programmed when IPv6 is enabled",
+			svc:  service.MeshService{Namespace: "ns1", Name: "bookstore-1", TargetPort: 80},
+			endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("1.1.1.1"), Port: 80},
+				{IP: net.ParseIP("2001:db8::1"), Port: 80},
+			},
+			enableIPv6: true,
+			expected: &xds_endpoint.ClusterLoadAssignment{
+				ClusterName: "ns1/bookstore-1|80",
+				Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+					{
+						Locality: &xds_core.Locality{
+							Zone: localZone,
+						},
+						LbEndpoints: []*xds_endpoint.LbEndpoint{
+							{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("1.1.1.1", 80),
+									},
+								},
+							},
+							{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("2001:db8::1", 80),
+									},
+								},
+							},
+						},
+						Priority: localClusterPriority,
+					},
+				},
+			},
+		},
 		{
 			name: "multiple endpoints per cluster within the same locality",
 			svc:  service.MeshService{Namespace: "ns1", Name: "bookstore-1", TargetPort: 80},
@@ -123,7 +189,7 @@ func TestNewClusterLoadAssignment(t *testing.T) {
 			name: "multicluster: with only remote endpoints",
 			svc:  service.MeshService{Namespace: "ns1", Name: "bookstore-1", TargetPort: 80},
 			endpoints: []endpoint.Endpoint{
-				{IP: net.ParseIP("2.3.4.5"), Port: 80, Weight: endpoint.Weight(10), Zone: remoteZoneName},
+				{IP: net.ParseIP("2.3.4.5"), Port: 80, Weight: endpoint.Weight(10), Priority: endpoint.Priority(1), Zone: remoteZoneName},
 			},
 			expected: &xds_endpoint.ClusterLoadAssignment{
 				ClusterName: "ns1/bookstore-1|80",
@@ -147,7 +213,7 @@ func TestNewClusterLoadAssignment(t *testing.T) {
 								},
 							},
 						},
-						Priority: remoteClusterPriority,
+						Priority: uint32(1),
 						LoadBalancingWeight: &wrappers.UInt32Value{
 							Value: 10,
 						},
@@ -155,12 +221,51 @@ func TestNewClusterLoadAssignment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "multicluster: remote endpoints sharing the local priority",
+			svc:  service.MeshService{Namespace: "ns1", Name: "bookstore-1", TargetPort: 80},
+			endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("2.3.4.5"), Port: 80, Weight: endpoint.Weight(50), Zone: remoteZoneName},
+			},
+			expected: &xds_endpoint.ClusterLoadAssignment{
+				ClusterName: "ns1/bookstore-1|80",
+				Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+					{
+						Locality: &xds_core.Locality{
+							Zone: localZone,
+						},
+						LbEndpoints: []*xds_endpoint.LbEndpoint{},
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: 100,
+						},
+					},
+					{
+						Locality: &xds_core.Locality{
+							Zone: remoteZoneName,
+						},
+						LbEndpoints: []*xds_endpoint.LbEndpoint{
+							{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("2.3.4.5", 80),
+									},
+								},
+							},
+						},
+						Priority: uint32(0),
+						LoadBalancingWeight: &wrappers.UInt32Value{
+							Value: 50,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
-			actual := newClusterLoadAssignment(tc.svc, tc.endpoints)
+			actual := newClusterLoadAssignment(tc.svc, tc.endpoints, tc.enableIPv6)
 			assert.True(cmp.Equal(tc.expected, actual, protocmp.Transform()), cmp.Diff(tc.expected, actual, protocmp.Transform()))
 		})
 	}
@@ -19,18 +19,19 @@ import (
 )
 
 // NewResponse creates a new Endpoint Discovery Response.
-func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, _ configurator.Configurator, _ certificate.Manager, _ *registry.ProxyRegistry) ([]types.Resource, error) {
+func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager, _ *registry.ProxyRegistry) ([]types.Resource, error) {
 	// If request comes through and requests specific endpoints, just attempt to answer those
+	enableIPv6 := cfg.GetFeatureFlags().EnableIPv6
 	if request != nil && len(request.ResourceNames) > 0 {
-		return fulfillEDSRequest(meshCatalog, proxy, request)
+		return fulfillEDSRequest(meshCatalog, proxy, request, enableIPv6)
 	}
 
 	// Otherwise, generate all endpoint configuration for this proxy
-	return generateEDSConfig(meshCatalog, proxy)
+	return generateEDSConfig(meshCatalog, proxy, enableIPv6)
 }
 
 // fulfillEDSRequest replies only to requested EDS endpoints on Discovery Request
-func fulfillEDSRequest(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest) ([]types.Resource, error) {
+func fulfillEDSRequest(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, enableIPv6 bool) ([]types.Resource, error) {
 	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
 		log.Error().Err(err).Str("proxy", proxy.String()).Msg("Error looking up proxy identity")
@@ -50,7 +51,7 @@ func fulfillEDSRequest(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, re
 		}
 		endpoints := meshCatalog.ListAllowedUpstreamEndpointsForService(proxyIdentity, meshSvc)
 		log.Trace().Msgf("Endpoints for upstream cluster %s for downstream proxy identity %s: %v", cluster, proxyIdentity, endpoints)
-		loadAssignment := newClusterLoadAssignment(meshSvc, endpoints)
+		loadAssignment := newClusterLoadAssignment(meshSvc, endpoints, enableIPv6)
 		rdsResources = append(rdsResources, loadAssignment)
 	}
 
@@ -58,7 +59,7 @@ func fulfillEDSRequest(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, re
 }
 
 // generateEDSConfig generates all endpoints expected for a given proxy
-func generateEDSConfig(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy) ([]types.Resource, error) {
+func generateEDSConfig(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, enableIPv6 bool) ([]types.Resource, error) {
 	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
 		log.Error().Err(err).Str("proxy", proxy.String()).Msg("Error looking up proxy identity")
@@ -69,7 +70,7 @@ func generateEDSConfig(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy) ([
 	upstreamSvcEndpoints := getUpstreamEndpointsForProxyIdentity(meshCatalog, proxyIdentity)
 
 	for svc, endpoints := range upstreamSvcEndpoints {
-		loadAssignment := newClusterLoadAssignment(svc, endpoints)
+		loadAssignment := newClusterLoadAssignment(svc, endpoints, enableIPv6)
 		edsResources = append(edsResources, loadAssignment)
 	}
 
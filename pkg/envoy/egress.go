@@ -0,0 +1,165 @@
+package envoy
+
+import (
+	"fmt"
+	"strings"
+
+	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	xds_metrics "github.com/envoyproxy/go-control-plane/envoy/config/metrics/v3"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+const (
+	// EgressStatPrefix is the prefix of the stat names of the egress clusters, used to extract the egress stats tags
+	EgressStatPrefix = "osm-egress"
+
+	// EgressDeniedStatPrefix is the prefix of the stat names of the egress traffic denied by the outbound listener,
+	// used to extract the egress stats tags
+	EgressDeniedStatPrefix = "osm-egress-denied"
+
+	// EgressStatsTagSourceNamespace is the stats tag for the namespace of the source identity of egress traffic
+	EgressStatsTagSourceNamespace = "osm_egress_source_namespace"
+
+	// EgressStatsTagSourceServiceAccount is the stats tag for the service account of the source identity of egress traffic
+	EgressStatsTagSourceServiceAccount = "osm_egress_source_service_account"
+
+	// EgressStatsTagHost is the stats tag for the external host of egress traffic
+	EgressStatsTagHost = "osm_egress_host"
+
+	// EgressStatsTagPort is the stats tag for the external port of egress traffic
+	EgressStatsTagPort = "osm_egress_port"
+
+	// EgressStatsAnyHost is the value of the host stats tag for egress traffic that is not matched on a host,
+	// such as TCP traffic matched on IP ranges
+	EgressStatsAnyHost = "*"
+
+	// EgressStatsAnyPort is the value of the port stats tag for denied egress traffic that is not matched on a port
+	EgressStatsAnyPort = "*"
+
+	// egressAccessLogType is the value of the 'log_type' field of the egress access log entries
+	egressAccessLogType = "egress"
+
+	// egressDeniedAccessLogType is the value of the 'log_type' field of the denied egress access log entries
+	egressDeniedAccessLogType = "egress-denied"
+)
+
+var (
+	// egressStatNameReplacer replaces the characters that cannot be part of a stat name segment. The periods are
+	// replaced with underscores, so the underscores, the colons and the escape character itself are escaped for the
+	// substitution to be reversible.
+	egressStatNameReplacer = strings.NewReplacer("%", "%25", "_", "%5F", ":", "%3A", ".", "_")
+
+	// egressStatTagReplacer reverts the substitutions of egressStatNameReplacer. Each pattern starts with a different
+	// character, so the patterns cannot overlap.
+	egressStatTagReplacer = strings.NewReplacer("%25", "%", "%5F", "_", "%3A", ":", "_", ".")
+)
+
+// DecodeEgressStatsTag returns the value of a stats tag extracted from a segment of an egress stat name, reverting
+// the substitution of the characters that cannot be part of a stat name segment
+func DecodeEgressStatsTag(tag string) string {
+	return egressStatTagReplacer.Replace(tag)
+}
+
+// GetEgressClusterStatName returns the stat name of an egress cluster for the given source identity and external
+// host and port. The stat name is of the form 'osm-egress.<namespace>.<service-account>.<host>.<port>', in which the
+// periods in each segment are replaced with underscores, so that the segments can be extracted as tags.
+// See DecodeEgressStatsTag to revert the substitution.
+func GetEgressClusterStatName(sourceIdentity identity.ServiceIdentity, host string, port int) string {
+	if host == "" {
+		host = EgressStatsAnyHost
+	}
+	sa := sourceIdentity.ToK8sServiceAccount()
+	return strings.Join([]string{
+		EgressStatPrefix,
+		egressStatNameReplacer.Replace(sa.Namespace),
+		egressStatNameReplacer.Replace(sa.Name),
+		egressStatNameReplacer.Replace(host),
+		fmt.Sprintf("%d", port),
+	}, ".")
+}
+
+// GetEgressDeniedStatPrefix returns the stat prefix of the egress traffic from the given source identity to the given
+// port that is denied by the outbound listener. The stat prefix is of the form
+// 'osm-egress-denied.<namespace>.<service-account>.<port>', in which the port is '*' if the port is 0, for traffic
+// that is not matched on a port.
+func GetEgressDeniedStatPrefix(sourceIdentity identity.ServiceIdentity, port int) string {
+	portSegment := EgressStatsAnyPort
+	if port != 0 {
+		portSegment = fmt.Sprintf("%d", port)
+	}
+	sa := sourceIdentity.ToK8sServiceAccount()
+	return strings.Join([]string{
+		EgressDeniedStatPrefix,
+		egressStatNameReplacer.Replace(sa.Namespace),
+		egressStatNameReplacer.Replace(sa.Name),
+		portSegment,
+	}, ".")
+}
+
+// GetEgressStatsTags returns the stats tags extracted from the stat names of the egress clusters and of the denied
+// egress traffic
+func GetEgressStatsTags() []*xds_metrics.TagSpecifier {
+	// The first capture group is removed from the stat name, the second one is the tag value
+	prefix := fmt.Sprintf(`^cluster\.%s\.`, EgressStatPrefix)
+	// The source identity is also extracted from the HTTP connection manager and TCP proxy stats of denied egress traffic
+	sourcePrefix := fmt.Sprintf(`^(?:cluster\.%s|(?:http|tcp)\.%s)\.`, EgressStatPrefix, EgressDeniedStatPrefix)
+	segment := `[^.]+\.`
+	// The port is also extracted from the stats of denied egress traffic, in which it is '*' when not matched on
+	portPrefix := fmt.Sprintf(`^(?:cluster\.%s\.%s|(?:http|tcp)\.%s\.%s)`, EgressStatPrefix, segment+segment+segment,
+		EgressDeniedStatPrefix, segment+segment)
+	return []*xds_metrics.TagSpecifier{
+		{
+			TagName:  EgressStatsTagSourceNamespace,
+			TagValue: &xds_metrics.TagSpecifier_Regex{Regex: sourcePrefix + `(([^.]+)\.)` + segment},
+		},
+		{
+			TagName:  EgressStatsTagSourceServiceAccount,
+			TagValue: &xds_metrics.TagSpecifier_Regex{Regex: sourcePrefix + segment + `(([^.]+)\.)`},
+		},
+		{
+			TagName:  EgressStatsTagHost,
+			TagValue: &xds_metrics.TagSpecifier_Regex{Regex: prefix + segment + segment + `(([^.]+)\.)` + `\d+\.`},
+		},
+		{
+			TagName:  EgressStatsTagPort,
+			TagValue: &xds_metrics.TagSpecifier_Regex{Regex: portPrefix + `((\d+|\*)\.)`},
+		},
+	}
+}
+
+// GetEgressAccessLog returns the access log for egress traffic from the given source identity. The entries are
+// written to the proxy's standard output with the 'log_type' field set to 'egress' to form an audit trail of
+// the external destinations accessed by each identity.
+func GetEgressAccessLog(sourceIdentity identity.ServiceIdentity) []*xds_accesslog_filter.AccessLog {
+	return getEgressAccessLog(sourceIdentity, egressAccessLogType)
+}
+
+// GetEgressDeniedAccessLog returns the access log for the egress traffic from the given source identity that is
+// denied because no Egress policy allows it. The entries have the 'log_type' field set to 'egress-denied'.
+func GetEgressDeniedAccessLog(sourceIdentity identity.ServiceIdentity) []*xds_accesslog_filter.AccessLog {
+	return getEgressAccessLog(sourceIdentity, egressDeniedAccessLogType)
+}
+
+func getEgressAccessLog(sourceIdentity identity.ServiceIdentity, logType string) []*xds_accesslog_filter.AccessLog {
+	accessLogger := getStdoutAccessLog()
+	fields := accessLogger.GetLogFormat().GetJsonFormat().Fields
+	fields["log_type"] = pbStringValue(logType)
+	fields["source_identity"] = pbStringValue(sourceIdentity.String())
+	fields["original_destination"] = pbStringValue(`%DOWNSTREAM_LOCAL_ADDRESS%`)
+
+	accessLog, err := ptypes.MarshalAny(accessLogger)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling egress AccessLog object")
+		return nil
+	}
+	return []*xds_accesslog_filter.AccessLog{{
+		Name: AccessLoggerName,
+		ConfigType: &xds_accesslog_filter.AccessLog_TypedConfig{
+			TypedConfig: accessLog,
+		}},
+	}
+}
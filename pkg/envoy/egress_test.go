@@ -0,0 +1,128 @@
+package envoy
+
+import (
+	"regexp"
+	"testing"
+
+	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/stream/v3"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+func TestGetEgressClusterStatName(t *testing.T) {
+	sourceIdentity := identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns"}.ToServiceIdentity()
+
+	testCases := []struct {
+		host     string
+		port     int
+		expected string
+	}{
+		{host: "foo.com", port: 80, expected: "osm-egress.ns.sa-1.foo_com.80"},
+		{host: "*.foo.com", port: 443, expected: "osm-egress.ns.sa-1.*_foo_com.443"},
+		{host: "", port: 3306, expected: "osm-egress.ns.sa-1.*.3306"},
+		{host: "_sip._tcp.foo.com", port: 5060, expected: "osm-egress.ns.sa-1.%5Fsip_%5Ftcp_foo_com.5060"},
+		{host: "fd00::1", port: 80, expected: "osm-egress.ns.sa-1.fd00%3A%3A1.80"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, GetEgressClusterStatName(sourceIdentity, tc.host, tc.port))
+		})
+	}
+}
+
+func TestDecodeEgressStatsTag(t *testing.T) {
+	for _, host := range []string{"foo.com", "*.foo.com", "_sip._tcp.foo.com", "a_.b", "a._b", "fd00::1", "100%.foo.com"} {
+		t.Run(host, func(t *testing.T) {
+			tassert.Equal(t, host, DecodeEgressStatsTag(egressStatNameReplacer.Replace(host)))
+		})
+	}
+}
+
+func TestGetEgressStatsTags(t *testing.T) {
+	assert := tassert.New(t)
+
+	sourceIdentity := identity.K8sServiceAccount{Name: "sa", Namespace: "ns"}.ToServiceIdentity()
+	statNames := []string{
+		"cluster." + GetEgressClusterStatName(sourceIdentity, "foo.com", 80) + ".upstream_rq_total",
+		"cluster." + GetEgressClusterStatName(sourceIdentity, "foo.com", 80) + ".circuit_breakers.default.cx_open",
+	}
+	expectedTags := map[string]string{
+		EgressStatsTagSourceNamespace:      "ns",
+		EgressStatsTagSourceServiceAccount: "sa",
+		EgressStatsTagHost:                 "foo_com",
+		EgressStatsTagPort:                 "80",
+	}
+
+	tags := GetEgressStatsTags()
+	assert.Len(tags, len(expectedTags))
+	for _, statName := range statNames {
+		for _, tag := range tags {
+			matches := regexp.MustCompile(tag.GetRegex()).FindStringSubmatch(statName)
+			assert.Len(matches, 3, tag.TagName)
+			assert.Equal(expectedTags[tag.TagName], matches[2], tag.TagName)
+		}
+	}
+
+	// The tags must not be extracted from other clusters
+	for _, tag := range tags {
+		assert.False(regexp.MustCompile(tag.GetRegex()).MatchString("cluster.foo_com_80.upstream_rq_total"))
+	}
+
+	// Only the source identity and port tags are extracted from the stats of denied egress traffic
+	deniedStatNames := map[string]string{
+		"tcp." + GetEgressDeniedStatPrefix(sourceIdentity, 80) + ".downstream_cx_total": "80",
+		"http." + GetEgressDeniedStatPrefix(sourceIdentity, 0) + ".downstream_rq_4xx":   EgressStatsAnyPort,
+	}
+	for statName, port := range deniedStatNames {
+		for _, tag := range tags {
+			matches := regexp.MustCompile(tag.GetRegex()).FindStringSubmatch(statName)
+			switch tag.TagName {
+			case EgressStatsTagSourceNamespace, EgressStatsTagSourceServiceAccount:
+				assert.Len(matches, 3, tag.TagName)
+				assert.Equal(expectedTags[tag.TagName], matches[2], tag.TagName)
+			case EgressStatsTagPort:
+				assert.Len(matches, 3, tag.TagName)
+				assert.Equal(port, matches[2], tag.TagName)
+			default:
+				assert.Empty(matches, tag.TagName)
+			}
+		}
+	}
+}
+
+func TestGetEgressDeniedStatPrefix(t *testing.T) {
+	sourceIdentity := identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns"}.ToServiceIdentity()
+	tassert.Equal(t, "osm-egress-denied.ns.sa-1.80", GetEgressDeniedStatPrefix(sourceIdentity, 80))
+	tassert.Equal(t, "osm-egress-denied.ns.sa-1.*", GetEgressDeniedStatPrefix(sourceIdentity, 0))
+}
+
+func TestGetEgressAccessLog(t *testing.T) {
+	assert := tassert.New(t)
+
+	sourceIdentity := identity.K8sServiceAccount{Name: "sa", Namespace: "ns"}.ToServiceIdentity()
+	accessLogs := GetEgressAccessLog(sourceIdentity)
+	assert.Len(accessLogs, 1)
+	assert.Equal(AccessLoggerName, accessLogs[0].Name)
+
+	accessLogger := &xds_accesslog.StdoutAccessLog{}
+	assert.Nil(ptypes.UnmarshalAny(accessLogs[0].GetTypedConfig(), accessLogger))
+	fields := accessLogger.GetLogFormat().GetJsonFormat().Fields
+	assert.Equal("egress", fields["log_type"].GetStringValue())
+	assert.Equal(sourceIdentity.String(), fields["source_identity"].GetStringValue())
+	assert.Equal("%DOWNSTREAM_LOCAL_ADDRESS%", fields["original_destination"].GetStringValue())
+	// The common access log fields are included
+	assert.Equal("%UPSTREAM_CLUSTER%", fields["upstream_cluster"].GetStringValue())
+
+	// The common access log is not modified
+	assert.NotContains(getStdoutAccessLog().GetLogFormat().GetJsonFormat().Fields, "log_type")
+
+	deniedAccessLogs := GetEgressDeniedAccessLog(sourceIdentity)
+	assert.Len(deniedAccessLogs, 1)
+	assert.Nil(ptypes.UnmarshalAny(deniedAccessLogs[0].GetTypedConfig(), accessLogger))
+	fields = accessLogger.GetLogFormat().GetJsonFormat().Fields
+	assert.Equal("egress-denied", fields["log_type"].GetStringValue())
+	assert.Equal(sourceIdentity.String(), fields["source_identity"].GetStringValue())
+}
@@ -2,9 +2,13 @@ package lds
 
 import (
 	"fmt"
+	"sort"
 
+	mapset "github.com/deckarep/golang-set"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
@@ -15,11 +19,21 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+	"github.com/openservicemesh/osm/pkg/utils"
 )
 
 const (
 	egressHTTPFilterChainPrefix = "egress-http"
 	egressTCPFilterChainPrefix  = "egress-tcp"
+
+	egressDeniedHTTPFilterChainName = "outbound-egress-denied-http"
+	egressDeniedTCPFilterChainName  = "outbound-egress-denied-tcp"
+	egressDeniedRouteConfigName     = "egress-denied"
+	egressDeniedVirtualHostName     = "egress-denied"
+
+	// egressDeniedCluster is the cluster denied egress TCP connections are proxied to. The cluster is never
+	// programmed, so that the connections are closed and counted as having no route.
+	egressDeniedCluster = "osm-egress-denied"
 )
 
 var (
@@ -30,15 +44,17 @@ var (
 	httpProtocols = []string{"http/1.0", "http/1.1", "h2c"}
 )
 
-// getEgressFilterChainsForMatches returns a slice of egress filter chains for the given traffic matches
-func (lb *listenerBuilder) getEgressFilterChainsForMatches(matches []*trafficpolicy.TrafficMatch) []*xds_listener.FilterChain {
+// getEgressFilterChainsForMatches returns a slice of egress filter chains for the given traffic matches.
+// The HTTP filter chains for the given dynamic forward proxy ports are configured to route requests to
+// dynamic forward proxy clusters.
+func (lb *listenerBuilder) getEgressFilterChainsForMatches(matches []*trafficpolicy.TrafficMatch, dynamicForwardProxyPorts map[int]bool) []*xds_listener.FilterChain {
 	var filterChains []*xds_listener.FilterChain
 
 	for _, match := range matches {
 		switch match.DestinationProtocol {
 		case constants.ProtocolHTTP:
 			// HTTP protocol --> HTTPConnectionManager filter
-			if filterChain, err := lb.getEgressHTTPFilterChain(match.DestinationPort); err != nil {
+			if filterChain, err := lb.getEgressHTTPFilterChain(match.DestinationPort, dynamicForwardProxyPorts[match.DestinationPort]); err != nil {
 				log.Error().Err(err).Msgf("Error building egress HTTP filter chain for port [%d]", match.DestinationPort)
 			} else {
 				filterChains = append(filterChains, filterChain)
@@ -57,8 +73,8 @@ func (lb *listenerBuilder) getEgressFilterChainsForMatches(matches []*trafficpol
 	return filterChains
 }
 
-func (lb *listenerBuilder) getEgressHTTPFilterChain(destinationPort int) (*xds_listener.FilterChain, error) {
-	filter, err := lb.getOutboundHTTPFilter(route.GetEgressRouteConfigNameForPort(destinationPort))
+func (lb *listenerBuilder) getEgressHTTPFilterChain(destinationPort int, enableDynamicForwardProxy bool) (*xds_listener.FilterChain, error) {
+	filter, err := lb.getOutboundHTTPFilter(route.GetEgressRouteConfigNameForPort(destinationPort), enableDynamicForwardProxy, lb.serviceIdentity)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error building HTTP filter chain for destination port [%d]", destinationPort)
 		return nil, err
@@ -77,10 +93,23 @@ func (lb *listenerBuilder) getEgressHTTPFilterChain(destinationPort int) (*xds_l
 	}, nil
 }
 
+// getDynamicForwardProxyPorts returns the set of ports with egress clusters for wildcard hosts, which are
+// routed to using dynamic forward proxy clusters
+func getDynamicForwardProxyPorts(clusterConfigs []*trafficpolicy.EgressClusterConfig) map[int]bool {
+	ports := make(map[int]bool)
+	for _, config := range clusterConfigs {
+		if !config.ViaEgressGateway && utils.IsWildcardHost(config.Host) {
+			ports[config.Port] = true
+		}
+	}
+	return ports
+}
+
 func (lb *listenerBuilder) getEgressTCPFilterChain(match trafficpolicy.TrafficMatch) (*xds_listener.FilterChain, error) {
 	tcpProxy := &xds_tcp_proxy.TcpProxy{
 		StatPrefix:       fmt.Sprintf("%s.%d", egressTCPProxyStatPrefix, match.DestinationPort),
 		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: match.Cluster},
+		AccessLog:        envoy.GetEgressAccessLog(lb.serviceIdentity),
 	}
 
 	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
@@ -106,8 +135,14 @@ func (lb *listenerBuilder) getEgressTCPFilterChain(match trafficpolicy.TrafficMa
 		destinationPrefixes = append(destinationPrefixes, cidr)
 	}
 
+	filterChainName := fmt.Sprintf("%s.%d", egressTCPFilterChainPrefix, match.DestinationPort)
+	if match.Name != "" {
+		// Multiple TCP filter chains can exist for the same port when the matches are named
+		filterChainName = fmt.Sprintf("%s.%s", filterChainName, match.Name)
+	}
+
 	return &xds_listener.FilterChain{
-		Name:    fmt.Sprintf("%s.%d", egressTCPFilterChainPrefix, match.DestinationPort),
+		Name:    filterChainName,
 		Filters: []*xds_listener.Filter{tcpFilter},
 		FilterChainMatch: &xds_listener.FilterChainMatch{
 			DestinationPort: &wrapperspb.UInt32Value{
@@ -118,3 +153,171 @@ func (lb *listenerBuilder) getEgressTCPFilterChain(match trafficpolicy.TrafficMa
 		},
 	}, nil
 }
+
+// getEgressDeniedFilterChains returns the filter chains that deny the egress traffic not matched by any of the given
+// filter chains, with feedback, and the default filter chain of the listener denying the remaining TCP connections.
+// Denied HTTP requests are responded to with a local reply, while denied TCP connections are closed. Both are logged
+// with the denied egress access log and counted in stats tagged with the source identity and the destination port.
+//
+// Filter chain matching picks the most specific destination port before considering the application protocol, so
+// in addition to the HTTP filter chain matching any port, an HTTP and a TCP filter chain are returned for each port
+// of the given filter chains that do not already match any destination on that port. The denied traffic on the other
+// ports is counted with the '*' port, since the default filter chain does not match on a port.
+func (lb *listenerBuilder) getEgressDeniedFilterChains(filterChains []*xds_listener.FilterChain) ([]*xds_listener.FilterChain, *xds_listener.FilterChain, error) {
+	egressDenyFeedback := lb.cfg.GetEgressDenyFeedback()
+
+	ports := mapset.NewSet()
+	portsMatchingAnyDestination := mapset.NewSet()
+	for _, filterChain := range filterChains {
+		match := filterChain.GetFilterChainMatch()
+		if match.GetDestinationPort() == nil {
+			continue
+		}
+		port := match.GetDestinationPort().GetValue()
+		ports.Add(port)
+		if len(match.GetPrefixRanges()) == 0 && len(match.GetServerNames()) == 0 {
+			portsMatchingAnyDestination.Add(port)
+		}
+	}
+	var deniedPorts []int
+	for port := range ports.Difference(portsMatchingAnyDestination).Iter() {
+		deniedPorts = append(deniedPorts, int(port.(uint32)))
+	}
+	sort.Ints(deniedPorts)
+
+	httpFilter, err := lb.getEgressDeniedHTTPFilter(0, egressDenyFeedback.HTTPStatusCode, egressDenyFeedback.HTTPBody)
+	if err != nil {
+		return nil, nil, err
+	}
+	deniedFilterChains := []*xds_listener.FilterChain{
+		{
+			Name:    egressDeniedHTTPFilterChainName,
+			Filters: []*xds_listener.Filter{httpFilter},
+			FilterChainMatch: &xds_listener.FilterChainMatch{
+				ApplicationProtocols: httpProtocols,
+			},
+		},
+	}
+	for _, port := range deniedPorts {
+		httpFilter, err := lb.getEgressDeniedHTTPFilter(port, egressDenyFeedback.HTTPStatusCode, egressDenyFeedback.HTTPBody)
+		if err != nil {
+			return nil, nil, err
+		}
+		tcpFilter, err := lb.getEgressDeniedTCPFilter(port)
+		if err != nil {
+			return nil, nil, err
+		}
+		deniedFilterChains = append(deniedFilterChains,
+			&xds_listener.FilterChain{
+				Name:    fmt.Sprintf("%s.%d", egressDeniedHTTPFilterChainName, port),
+				Filters: []*xds_listener.Filter{httpFilter},
+				FilterChainMatch: &xds_listener.FilterChainMatch{
+					DestinationPort:      &wrapperspb.UInt32Value{Value: uint32(port)},
+					ApplicationProtocols: httpProtocols,
+				},
+			},
+			&xds_listener.FilterChain{
+				Name:    fmt.Sprintf("%s.%d", egressDeniedTCPFilterChainName, port),
+				Filters: []*xds_listener.Filter{tcpFilter},
+				FilterChainMatch: &xds_listener.FilterChainMatch{
+					DestinationPort: &wrapperspb.UInt32Value{Value: uint32(port)},
+				},
+			},
+		)
+	}
+
+	tcpFilter, err := lb.getEgressDeniedTCPFilter(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defaultFilterChain := &xds_listener.FilterChain{
+		Name:    egressDeniedTCPFilterChainName,
+		Filters: []*xds_listener.Filter{tcpFilter},
+	}
+
+	return deniedFilterChains, defaultFilterChain, nil
+}
+
+// getEgressDeniedTCPFilter returns the TCP proxy filter that closes the denied egress TCP connections to the given
+// port, or to any port if the port is 0
+func (lb *listenerBuilder) getEgressDeniedTCPFilter(port int) (*xds_listener.Filter, error) {
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       envoy.GetEgressDeniedStatPrefix(lb.serviceIdentity, port),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: egressDeniedCluster},
+		AccessLog:        envoy.GetEgressDeniedAccessLog(lb.serviceIdentity),
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling TcpProxy object for denied egress filter chain")
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.TCPProxy,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+	}, nil
+}
+
+// getEgressDeniedHTTPFilter returns the HTTP connection manager filter that responds to denied egress HTTP requests
+// to the given port, or to any port if the port is 0, with a local reply having the given status code and body. If the
+// body is empty, it defaults to a message naming the Egress policy required to allow the request.
+func (lb *listenerBuilder) getEgressDeniedHTTPFilter(port int, statusCode int, body string) (*xds_listener.Filter, error) {
+	if body == "" {
+		sa := lb.serviceIdentity.ToK8sServiceAccount()
+		body = fmt.Sprintf("Egress to %%REQ(:AUTHORITY)%% is denied: no Egress policy with the source ServiceAccount %s/%s matches this destination\n",
+			sa.Namespace, sa.Name)
+	}
+
+	connManager := &xds_hcm.HttpConnectionManager{
+		StatPrefix: envoy.GetEgressDeniedStatPrefix(lb.serviceIdentity, port),
+		CodecType:  xds_hcm.HttpConnectionManager_AUTO,
+		HttpFilters: []*xds_hcm.HttpFilter{
+			{Name: wellknown.Router},
+		},
+		RouteSpecifier: &xds_hcm.HttpConnectionManager_RouteConfig{
+			RouteConfig: &xds_route.RouteConfiguration{
+				Name: egressDeniedRouteConfigName,
+				VirtualHosts: []*xds_route.VirtualHost{
+					{
+						Name:    egressDeniedVirtualHostName,
+						Domains: []string{"*"},
+						Routes: []*xds_route.Route{
+							{
+								Match: &xds_route.RouteMatch{
+									PathSpecifier: &xds_route.RouteMatch_Prefix{Prefix: "/"},
+								},
+								Action: &xds_route.Route_DirectResponse{
+									DirectResponse: &xds_route.DirectResponseAction{Status: uint32(statusCode)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		// Direct responses are local replies, whose body is formatted so that it can refer to the request
+		LocalReplyConfig: &xds_hcm.LocalReplyConfig{
+			BodyFormat: &xds_core.SubstitutionFormatString{
+				Format: &xds_core.SubstitutionFormatString_TextFormatSource{
+					TextFormatSource: &xds_core.DataSource{
+						Specifier: &xds_core.DataSource_InlineString{InlineString: body},
+					},
+				},
+			},
+		},
+		AccessLog: envoy.GetEgressDeniedAccessLog(lb.serviceIdentity),
+	}
+
+	marshalledConnManager, err := ptypes.MarshalAny(connManager)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling HttpConnectionManager object for denied egress filter chain")
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.HTTPConnectionManager,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledConnManager},
+	}, nil
+}
@@ -0,0 +1,159 @@
+package lds
+
+import (
+	"fmt"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/egressgateway"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rbac"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	egressGatewayListenerName        = "egress-gateway-listener"
+	egressGatewayFilterChainName     = "egress-gateway-filter-chain"
+	egressGatewayRBACPolicyName      = "egress-gateway-sources"
+	egressGatewayTCPProxyStatsPrefix = "egress-gateway"
+)
+
+// buildEgressGatewayListener builds the listener for the egress gateway. The gateway terminates the mTLS connections
+// from sidecars, and proxies the traffic to the external destination matching the SNI requested by the sidecar
+// if the downstream identity is allowed to access the destination by an Egress policy.
+func (lb *listenerBuilder) buildEgressGatewayListener() (*xds_listener.Listener, error) {
+	var filterChains []*xds_listener.FilterChain
+	if gatewayTrafficPolicy := lb.meshCatalog.GetEgressGatewayTrafficPolicy(); gatewayTrafficPolicy != nil {
+		for _, upstream := range gatewayTrafficPolicy.Upstreams {
+			filterChain, err := lb.getEgressGatewayFilterChain(upstream)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error building egress gateway filter chain for upstream %s", upstream.ClusterName)
+				continue
+			}
+			filterChains = append(filterChains, filterChain)
+		}
+	}
+
+	if len(filterChains) == 0 {
+		// Configuring a listener without a filter chain is an error
+		return nil, nil
+	}
+
+	return &xds_listener.Listener{
+		Name:         egressGatewayListenerName,
+		Address:      envoy.GetWildcardAddress(egressgateway.ListenerPort, lb.cfg.GetFeatureFlags().EnableIPv6),
+		FilterChains: filterChains,
+		ListenerFilters: []*xds_listener.ListenerFilter{
+			{
+				Name: wellknown.TlsInspector,
+			},
+		},
+	}, nil
+}
+
+// getEgressGatewayFilterChain returns the filter chain for the given egress gateway upstream
+func (lb *listenerBuilder) getEgressGatewayFilterChain(upstream *trafficpolicy.EgressGatewayUpstream) (*xds_listener.FilterChain, error) {
+	if len(upstream.AllowedSourceIdentities) == 0 {
+		// An RBAC policy without principals allows any downstream
+		return nil, errors.New("No source identities are allowed to access the upstream")
+	}
+
+	rbacFilter, err := buildEgressGatewayRBACFilter(upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", egressGatewayTCPProxyStatsPrefix, upstream.ClusterName),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: upstream.ClusterName},
+		AccessLog:        envoy.GetAccessLog(),
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling TcpProxy for egress gateway upstream %s", upstream.ClusterName)
+		return nil, err
+	}
+
+	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(lb.serviceIdentity, true /* mTLS */))
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling DownstreamTLSContext for egress gateway upstream %s", upstream.ClusterName)
+		return nil, err
+	}
+
+	return &xds_listener.FilterChain{
+		Name: fmt.Sprintf("%s-%s", egressGatewayFilterChainName, upstream.ClusterName),
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			ServerNames:       []string{upstream.ServerName},
+			TransportProtocol: envoy.TransportProtocolTLS,
+		},
+		Filters: []*xds_listener.Filter{
+			rbacFilter,
+			{
+				Name:       wellknown.TCPProxy,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+			},
+		},
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledDownstreamTLSContext,
+			},
+		},
+	}, nil
+}
+
+// buildEgressGatewayRBACFilter builds a network RBAC filter that allows the source identities of the given upstream
+func buildEgressGatewayRBACFilter(upstream *trafficpolicy.EgressGatewayUpstream) (*xds_listener.Filter, error) {
+	return buildNetworkRBACFilterForIdentities(egressGatewayRBACPolicyName, upstream.AllowedSourceIdentities)
+}
+
+// buildNetworkRBACFilterForIdentities builds a network RBAC filter with the given policy name that allows the given
+// downstream identities
+func buildNetworkRBACFilterForIdentities(policyName string, allowedIdentities []identity.ServiceIdentity) (*xds_listener.Filter, error) {
+	var principalRuleList []rbac.RulesList
+	for _, sourceIdentity := range allowedIdentities {
+		principalRuleList = append(principalRuleList, rbac.RulesList{
+			OrRules: []rbac.Rule{
+				{Attribute: rbac.DownstreamAuthPrincipal, Value: sourceIdentity.String()},
+			},
+		})
+	}
+
+	policy, err := (&rbac.Policy{Principals: principalRuleList}).Generate()
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrBuildingRBACPolicy)).
+			Msgf("Error building RBAC policy %s", policyName)
+		return nil, err
+	}
+
+	networkRBACPolicy := &xds_network_rbac.RBAC{
+		StatPrefix: "network-", // will be displayed as network-rbac.<path>
+		Rules: &xds_rbac.RBAC{
+			Action:   xds_rbac.RBAC_ALLOW, // Allows the request if and only if there is a policy that matches the request
+			Policies: map[string]*xds_rbac.Policy{policyName: policy},
+		},
+	}
+
+	marshalledNetworkRBACPolicy, err := ptypes.MarshalAny(networkRBACPolicy)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling RBAC policy: %v", networkRBACPolicy)
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.RoleBasedAccessControl,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledNetworkRBACPolicy},
+	}, nil
+}
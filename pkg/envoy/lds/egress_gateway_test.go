@@ -0,0 +1,148 @@
+package lds
+
+import (
+	"testing"
+
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestBuildEgressGatewayListener(t *testing.T) {
+	id := identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity()
+
+	testCases := []struct {
+		name                 string
+		gatewayPolicy        *trafficpolicy.EgressGatewayTrafficPolicy
+		expectListener       bool
+		expectedFilterChains int
+	}{
+		{
+			name:           "no egress gateway traffic policy",
+			gatewayPolicy:  nil,
+			expectListener: false,
+		},
+		{
+			name: "upstreams with allowed sources",
+			gatewayPolicy: &trafficpolicy.EgressGatewayTrafficPolicy{
+				Upstreams: []*trafficpolicy.EgressGatewayUpstream{
+					{
+						Host:                    "foo.com",
+						Port:                    80,
+						ClusterName:             "foo.com:80",
+						ServerName:              egressgateway.GetServerName("foo.com", 80),
+						AllowedSourceIdentities: []identity.ServiceIdentity{tests.BookbuyerServiceIdentity},
+					},
+					{
+						Host:                    "bar.com",
+						Port:                    443,
+						ClusterName:             "bar.com:443",
+						ServerName:              egressgateway.GetServerName("bar.com", 443),
+						AllowedSourceIdentities: []identity.ServiceIdentity{tests.BookbuyerServiceIdentity, tests.BookstoreServiceIdentity},
+					},
+				},
+			},
+			expectListener:       true,
+			expectedFilterChains: 2,
+		},
+		{
+			name: "upstream without allowed sources is skipped",
+			gatewayPolicy: &trafficpolicy.EgressGatewayTrafficPolicy{
+				Upstreams: []*trafficpolicy.EgressGatewayUpstream{
+					{
+						Host:        "foo.com",
+						Port:        80,
+						ClusterName: "foo.com:80",
+						ServerName:  egressgateway.GetServerName("foo.com", 80),
+					},
+				},
+			},
+			expectListener: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressGateway: true}).AnyTimes()
+			mockCatalog.EXPECT().GetEgressGatewayTrafficPolicy().Return(tc.gatewayPolicy).Times(1)
+
+			lb := &listenerBuilder{
+				meshCatalog:     mockCatalog,
+				cfg:             mockConfigurator,
+				serviceIdentity: id,
+			}
+
+			listener, err := lb.buildEgressGatewayListener()
+			assert.Nil(err)
+			if !tc.expectListener {
+				assert.Nil(listener)
+				return
+			}
+
+			assert.Equal(egressGatewayListenerName, listener.Name)
+			assert.Equal(envoy.GetAddress(constants.WildcardIPAddr, egressgateway.ListenerPort), listener.Address)
+			assert.Len(listener.ListenerFilters, 1)
+			assert.Len(listener.FilterChains, tc.expectedFilterChains)
+		})
+	}
+}
+
+func TestGetEgressGatewayFilterChain(t *testing.T) {
+	assert := tassert.New(t)
+
+	lb := &listenerBuilder{
+		serviceIdentity: identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity(),
+	}
+	upstream := &trafficpolicy.EgressGatewayUpstream{
+		Host:                    "foo.com",
+		Port:                    443,
+		ClusterName:             "foo.com:443",
+		ServerName:              egressgateway.GetServerName("foo.com", 443),
+		AllowedSourceIdentities: []identity.ServiceIdentity{tests.BookbuyerServiceIdentity},
+	}
+
+	filterChain, err := lb.getEgressGatewayFilterChain(upstream)
+	assert.Nil(err)
+	assert.Equal("egress-gateway-filter-chain-foo.com:443", filterChain.Name)
+	assert.Equal([]string{"foo.com.443.egress.osm"}, filterChain.FilterChainMatch.ServerNames)
+	assert.Equal(envoy.TransportProtocolTLS, filterChain.FilterChainMatch.TransportProtocol)
+	assert.NotNil(filterChain.TransportSocket)
+	assert.Len(filterChain.Filters, 2)
+
+	// The RBAC filter only allows the source identities of the upstream
+	assert.Equal(wellknown.RoleBasedAccessControl, filterChain.Filters[0].Name)
+	rbacPolicy := &xds_network_rbac.RBAC{}
+	err = ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), rbacPolicy)
+	assert.Nil(err)
+	assert.Equal(xds_rbac.RBAC_ALLOW, rbacPolicy.Rules.Action)
+	principals := rbacPolicy.Rules.Policies[egressGatewayRBACPolicyName].Principals
+	assert.Len(principals, 1)
+	assert.Equal(tests.BookbuyerServiceIdentity.String(), principals[0].GetOrIds().Ids[0].GetAuthenticated().PrincipalName.GetExact())
+
+	// The TCP proxy filter proxies the traffic to the upstream's cluster
+	assert.Equal(wellknown.TCPProxy, filterChain.Filters[1].Name)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	err = ptypes.UnmarshalAny(filterChain.Filters[1].GetTypedConfig(), tcpProxy)
+	assert.Nil(err)
+	assert.Equal("foo.com:443", tcpProxy.GetCluster())
+}
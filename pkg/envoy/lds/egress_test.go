@@ -3,15 +3,21 @@ package lds
 import (
 	"testing"
 
+	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/stream/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
 	tassert "github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -59,7 +65,7 @@ func TestGetEgressHTTPFilterChain(t *testing.T) {
 			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableEgressPolicy: true,
 				EnableWASMStats:    false}).AnyTimes()
-			actual, err := lb.getEgressHTTPFilterChain(tc.destinationPort)
+			actual, err := lb.getEgressHTTPFilterChain(tc.destinationPort, false)
 
 			assert.Equal(tc.expectError, err != nil)
 			assert.Equal(tc.expectedFilterChainMatch, actual.FilterChainMatch)
@@ -69,6 +75,28 @@ func TestGetEgressHTTPFilterChain(t *testing.T) {
 	}
 }
 
+func TestGetDynamicForwardProxyPorts(t *testing.T) {
+	assert := tassert.New(t)
+
+	clusterConfigs := []*trafficpolicy.EgressClusterConfig{
+		{Name: "*.foo.com:80", Host: "*.foo.com", Port: 80},
+		{Name: "bar.com:90", Host: "bar.com", Port: 90},
+		{Name: "443", Port: 443},
+		{Name: "*.baz.com:100", Host: "*.baz.com", Port: 100, ViaEgressGateway: true},
+	}
+
+	assert.Equal(map[int]bool{80: true}, getDynamicForwardProxyPorts(clusterConfigs))
+	assert.Empty(getDynamicForwardProxyPorts(nil))
+}
+
+// getAccessLogField returns the value of the given field in the JSON format of the given stdout access log
+func getAccessLogField(assert *tassert.Assertions, accessLogs []*xds_accesslog_filter.AccessLog, field string) string {
+	assert.Len(accessLogs, 1)
+	accessLogger := &xds_accesslog.StdoutAccessLog{}
+	assert.Nil(ptypes.UnmarshalAny(accessLogs[0].GetTypedConfig(), accessLogger))
+	return accessLogger.GetLogFormat().GetJsonFormat().Fields[field].GetStringValue()
+}
+
 func TestGetEgressTCPFilterChain(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -157,7 +185,7 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 				EnableWASMStats:    false,
 			}).AnyTimes()
 
-			lb := &listenerBuilder{}
+			lb := &listenerBuilder{serviceIdentity: tests.BookbuyerServiceIdentity}
 
 			actual, err := lb.getEgressTCPFilterChain(tc.trafficMatch)
 
@@ -165,6 +193,12 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 			assert.Equal(tc.expectedFilterChainMatch, actual.FilterChainMatch)
 			assert.Len(actual.Filters, 1) // Single TCPProxy filter
 			assert.Equal(wellknown.TCPProxy, actual.Filters[0].Name)
+
+			// Egress connections are logged using the egress access log
+			tcpProxy := &xds_tcp_proxy.TcpProxy{}
+			assert.Nil(ptypes.UnmarshalAny(actual.Filters[0].GetTypedConfig(), tcpProxy))
+			assert.Equal("egress", getAccessLogField(assert, tcpProxy.AccessLog, "log_type"))
+			assert.Equal(tests.BookbuyerServiceIdentity.String(), getAccessLogField(assert, tcpProxy.AccessLog, "source_identity"))
 		})
 	}
 }
@@ -233,9 +267,124 @@ func TestGetEgressFilterChainsForMatches(t *testing.T) {
 				EnableWASMStats:    false,
 			}).AnyTimes()
 
-			actual := lb.getEgressFilterChainsForMatches(tc.trafficMatches)
+			actual := lb.getEgressFilterChainsForMatches(tc.trafficMatches, nil)
 
 			assert.Len(actual, tc.expectedFilterChainCount)
 		})
 	}
 }
+
+func TestGetEgressDeniedFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetEgressDenyFeedback().Return(v1alpha1.EgressDenyFeedbackSpec{
+		Enable:         true,
+		HTTPStatusCode: 403,
+	}).Times(1)
+
+	lb := &listenerBuilder{
+		cfg:             mockConfigurator,
+		serviceIdentity: tests.BookbuyerServiceIdentity,
+	}
+
+	filterChains := []*xds_listener.FilterChain{
+		{
+			// Mesh filter chain on port 80, requires a denied HTTP filter chain on port 80
+			FilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort: &wrapperspb.UInt32Value{Value: 80},
+				PrefixRanges:    []*xds_core.CidrRange{{AddressPrefix: "10.0.0.1", PrefixLen: &wrapperspb.UInt32Value{Value: 32}}},
+			},
+		},
+		{
+			// Egress filter chain on port 443 matching server names, requires a denied HTTP filter chain on port 443
+			FilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort: &wrapperspb.UInt32Value{Value: 443},
+				ServerNames:     []string{"foo.com"},
+			},
+		},
+		{
+			// Egress filter chains on port 8080 matching any destination, no denied HTTP filter chain on port 8080
+			FilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort:      &wrapperspb.UInt32Value{Value: 8080},
+				ApplicationProtocols: httpProtocols,
+			},
+		},
+		{
+			FilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort: &wrapperspb.UInt32Value{Value: 8080},
+				PrefixRanges:    []*xds_core.CidrRange{{AddressPrefix: "10.0.0.2", PrefixLen: &wrapperspb.UInt32Value{Value: 32}}},
+			},
+		},
+	}
+
+	deniedFilterChains, defaultFilterChain, err := lb.getEgressDeniedFilterChains(filterChains)
+	assert.Nil(err)
+
+	assert.Len(deniedFilterChains, 5)
+	expectedFilterChains := []struct {
+		name        string
+		port        uint32
+		filter      string
+		statPrefix  string
+		httpMatched bool
+	}{
+		{"outbound-egress-denied-http", 0, wellknown.HTTPConnectionManager, "osm-egress-denied.default.bookbuyer.*", true},
+		{"outbound-egress-denied-http.80", 80, wellknown.HTTPConnectionManager, "osm-egress-denied.default.bookbuyer.80", true},
+		{"outbound-egress-denied-tcp.80", 80, wellknown.TCPProxy, "osm-egress-denied.default.bookbuyer.80", false},
+		{"outbound-egress-denied-http.443", 443, wellknown.HTTPConnectionManager, "osm-egress-denied.default.bookbuyer.443", true},
+		{"outbound-egress-denied-tcp.443", 443, wellknown.TCPProxy, "osm-egress-denied.default.bookbuyer.443", false},
+	}
+	for i, expected := range expectedFilterChains {
+		filterChain := deniedFilterChains[i]
+		assert.Equal(expected.name, filterChain.Name)
+		assert.Equal(expected.port, filterChain.FilterChainMatch.DestinationPort.GetValue())
+		assert.Empty(filterChain.FilterChainMatch.PrefixRanges)
+		assert.Len(filterChain.Filters, 1)
+		assert.Equal(expected.filter, filterChain.Filters[0].Name)
+		if expected.httpMatched {
+			assert.Equal(httpProtocols, filterChain.FilterChainMatch.ApplicationProtocols)
+			connManager := &xds_hcm.HttpConnectionManager{}
+			assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), connManager))
+			assert.Equal(expected.statPrefix, connManager.StatPrefix)
+		} else {
+			assert.Empty(filterChain.FilterChainMatch.ApplicationProtocols)
+			tcpProxy := &xds_tcp_proxy.TcpProxy{}
+			assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), tcpProxy))
+			assert.Equal(expected.statPrefix, tcpProxy.StatPrefix)
+		}
+	}
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(deniedFilterChains[0].Filters[0].GetTypedConfig(), connManager))
+	directResponse := connManager.GetRouteConfig().VirtualHosts[0].Routes[0].GetDirectResponse()
+	assert.Equal(uint32(403), directResponse.Status)
+	assert.Equal("Egress to %REQ(:AUTHORITY)% is denied: no Egress policy with the source ServiceAccount default/bookbuyer matches this destination\n",
+		connManager.LocalReplyConfig.BodyFormat.GetTextFormatSource().GetInlineString())
+	assert.Equal("egress-denied", getAccessLogField(assert, connManager.AccessLog, "log_type"))
+
+	assert.Equal("outbound-egress-denied-tcp", defaultFilterChain.Name)
+	assert.Nil(defaultFilterChain.FilterChainMatch)
+	assert.Len(defaultFilterChain.Filters, 1)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	assert.Nil(ptypes.UnmarshalAny(defaultFilterChain.Filters[0].GetTypedConfig(), tcpProxy))
+	assert.Equal("osm-egress-denied.default.bookbuyer.*", tcpProxy.StatPrefix)
+	assert.Equal(egressDeniedCluster, tcpProxy.GetCluster())
+	assert.Equal("egress-denied", getAccessLogField(assert, tcpProxy.AccessLog, "log_type"))
+	assert.Equal(tests.BookbuyerServiceIdentity.String(), getAccessLogField(assert, tcpProxy.AccessLog, "source_identity"))
+}
+
+func TestGetEgressDeniedHTTPFilterCustomBody(t *testing.T) {
+	assert := tassert.New(t)
+
+	lb := &listenerBuilder{serviceIdentity: tests.BookbuyerServiceIdentity}
+	filter, err := lb.getEgressDeniedHTTPFilter(0, 502, "blocked %REQ(:AUTHORITY)%")
+	assert.Nil(err)
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), connManager))
+	assert.Equal(uint32(502), connManager.GetRouteConfig().VirtualHosts[0].Routes[0].GetDirectResponse().Status)
+	assert.Equal("blocked %REQ(:AUTHORITY)%", connManager.LocalReplyConfig.BodyFormat.GetTextFormatSource().GetInlineString())
+}
@@ -3,34 +3,63 @@ package lds
 import (
 	"fmt"
 
+	mapset "github.com/deckarep/golang-set"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
+	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 const (
-	multiclusterGatewayFilterChainName = "multicluster-gateway-filter-chain"
+	multiclusterGatewayFilterChainName     = "multicluster-gateway-filter-chain"
+	multiclusterGatewayHTTPFilterChainName = "multicluster-gateway-http-filter-chain"
 
 	// multiclusterGatewayListenerPort is port number for the multicluster gateway.
 	multiclusterGatewayListenerPort = 15443
 )
 
 func (lb *listenerBuilder) buildMulticlusterGatewayListener() (*xds_listener.Listener, error) {
-	upstreamServices := lb.meshCatalog.ListOutboundServicesForMulticlusterGateway()
-	filterChains, err := getMulticlusterGatewayFilterChains(upstreamServices)
+	// HTTP services are terminated by the gateway when its HTTP mode is enabled, while the traffic
+	// for the remaining services is passed through to the upstream based on the SNI.
+	var filterChains []*xds_listener.FilterChain
+	httpServices := mapset.NewSet()
+	if gatewayTrafficPolicy := lb.meshCatalog.GetMulticlusterGatewayTrafficPolicy(); gatewayTrafficPolicy != nil {
+		httpFilterChains, err := lb.getMulticlusterGatewayHTTPFilterChains(gatewayTrafficPolicy.HTTPUpstreams)
+		if err != nil {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msg("[Multicluster] Error creating Multicluster gateway HTTP filter chain")
+			return nil, err
+		}
+		filterChains = append(filterChains, httpFilterChains...)
+		for _, upstream := range gatewayTrafficPolicy.HTTPUpstreams {
+			httpServices.Add(upstream.Service)
+		}
+	}
+
+	var upstreamServices []service.MeshService
+	for _, upstreamSvc := range lb.meshCatalog.ListOutboundServicesForMulticlusterGateway() {
+		if !httpServices.Contains(upstreamSvc) {
+			upstreamServices = append(upstreamServices, upstreamSvc)
+		}
+	}
+	tcpFilterChains, err := getMulticlusterGatewayFilterChains(upstreamServices)
 	if err != nil {
 		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msg("[Multicluster] Error creating Multicluster gateway filter chain")
 		return nil, err
 	}
+	filterChains = append(filterChains, tcpFilterChains...)
 
 	return &xds_listener.Listener{
 		Name:         multiclusterListenerName,
-		Address:      envoy.GetAddress(constants.WildcardIPAddr, multiclusterGatewayListenerPort),
+		Address:      envoy.GetWildcardAddress(multiclusterGatewayListenerPort, lb.cfg.GetFeatureFlags().EnableIPv6),
 		FilterChains: filterChains,
 		ListenerFilters: []*xds_listener.ListenerFilter{
 			{
@@ -80,3 +109,67 @@ func getMulticlusterGatewayFilterChains(upstreamServices []service.MeshService)
 	}
 	return filterChains, nil
 }
+
+// getMulticlusterGatewayHTTPFilterChains returns the filter chains terminating mTLS for the given HTTP upstreams. The gateway
+// presents the certificate of the upstream service to the downstream, and applies the HTTP routes and RBAC policies of
+// the upstream service before re-originating mTLS to it.
+func (lb *listenerBuilder) getMulticlusterGatewayHTTPFilterChains(upstreams []*trafficpolicy.MulticlusterGatewayHTTPUpstream) ([]*xds_listener.FilterChain, error) {
+	if len(upstreams) == 0 {
+		return nil, nil
+	}
+
+	connManager, err := httpConnManagerOptions{
+		direction:         inbound,
+		rdsRoutConfigName: route.MulticlusterGatewayRouteConfigName,
+
+		// Tracing options
+		enableTracing:      lb.cfg.IsTracingEnabled(),
+		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
+	}.build()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error building HTTP connection manager for multicluster gateway")
+	}
+
+	marshalledConnManager, err := ptypes.MarshalAny(connManager)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling HTTP connection manager for multicluster gateway")
+	}
+
+	var filterChains []*xds_listener.FilterChain
+	for _, upstream := range upstreams {
+		marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(upstream.Identity, true /* mTLS */))
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+				Msgf("[Multicluster] Error marshalling DownstreamTLSContext for gateway filter chain service %s", upstream.Service)
+			continue
+		}
+
+		filterChain := &xds_listener.FilterChain{
+			Name: fmt.Sprintf("%s-%s", multiclusterGatewayHTTPFilterChainName, upstream.Service),
+			FilterChainMatch: &xds_listener.FilterChainMatch{
+				ServerNames: []string{
+					upstream.Service.ServerName(),
+				},
+				TransportProtocol:    envoy.TransportProtocolTLS,
+				ApplicationProtocols: envoy.ALPNInMesh, // in-mesh proxies will advertise this, set in UpstreamTlsContext
+			},
+			Filters: []*xds_listener.Filter{
+				{
+					Name: wellknown.HTTPConnectionManager,
+					ConfigType: &xds_listener.Filter_TypedConfig{
+						TypedConfig: marshalledConnManager,
+					},
+				},
+			},
+			TransportSocket: &xds_core.TransportSocket{
+				Name: wellknown.TransportSocketTls,
+				ConfigType: &xds_core.TransportSocket_TypedConfig{
+					TypedConfig: marshalledDownstreamTLSContext,
+				},
+			},
+		}
+		filterChains = append(filterChains, filterChain)
+	}
+
+	return filterChains, nil
+}
@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	tassert "github.com/stretchr/testify/assert"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
@@ -12,9 +16,11 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 func TestBuildMulticlusterGatewayListeners(t *testing.T) {
@@ -33,6 +39,7 @@ func TestBuildMulticlusterGatewayListeners(t *testing.T) {
 	}
 
 	mockCatalog.EXPECT().ListOutboundServicesForMulticlusterGateway().Return(meshServices).AnyTimes()
+	mockCatalog.EXPECT().GetMulticlusterGatewayTrafficPolicy().Return(nil).AnyTimes()
 	lb := &listenerBuilder{
 		meshCatalog:     mockCatalog,
 		cfg:             mockConfigurator,
@@ -61,3 +68,59 @@ func TestGetGatewayFilterChains(t *testing.T) {
 	assert.Equal(filterChains[0].FilterChainMatch.TransportProtocol, "tls")
 	assert.Equal(len(filterChains[0].Filters), 2)
 }
+
+func TestBuildMulticlusterGatewayListenerWithHTTPUpstreams(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTracingEndpoint().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).AnyTimes()
+
+	mockCatalog.EXPECT().ListOutboundServicesForMulticlusterGateway().Return([]service.MeshService{
+		tests.BookstoreV1Service,
+		tests.BookstoreV2Service,
+	}).AnyTimes()
+	// The bookstore-v2 service is terminated by the gateway in HTTP mode
+	mockCatalog.EXPECT().GetMulticlusterGatewayTrafficPolicy().Return(&trafficpolicy.MulticlusterGatewayTrafficPolicy{
+		HTTPUpstreams: []*trafficpolicy.MulticlusterGatewayHTTPUpstream{
+			{
+				Service:     tests.BookstoreV2Service,
+				Identity:    tests.BookstoreServiceIdentity,
+				ClusterName: tests.BookstoreV2Service.EnvoyClusterName(),
+			},
+		},
+	}).AnyTimes()
+
+	lb := &listenerBuilder{
+		meshCatalog:     mockCatalog,
+		cfg:             mockConfigurator,
+		serviceIdentity: identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity(),
+	}
+
+	listener, err := lb.buildMulticlusterGatewayListener()
+	assert.Nil(err)
+	assert.Len(listener.FilterChains, 2)
+
+	httpFilterChain := listener.FilterChains[0]
+	assert.Equal(fmt.Sprintf("%s-%s", multiclusterGatewayHTTPFilterChainName, tests.BookstoreV2Service), httpFilterChain.Name)
+	assert.ElementsMatch([]string{tests.BookstoreV2Service.ServerName()}, httpFilterChain.FilterChainMatch.ServerNames)
+	assert.Len(httpFilterChain.Filters, 1)
+	assert.Equal(wellknown.HTTPConnectionManager, httpFilterChain.Filters[0].Name)
+
+	// The gateway presents the certificate of the upstream service to the downstream
+	expectedTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(tests.BookstoreServiceIdentity, true))
+	assert.Nil(err)
+	assert.True(proto.Equal(expectedTLSContext, httpFilterChain.TransportSocket.GetTypedConfig()))
+
+	hcm := &xds_hcm.HttpConnectionManager{}
+	assert.Nil(ptypes.UnmarshalAny(httpFilterChain.Filters[0].GetTypedConfig(), hcm))
+	assert.Equal(route.MulticlusterGatewayRouteConfigName, hcm.GetRds().RouteConfigName)
+
+	tcpFilterChain := listener.FilterChains[1]
+	assert.Equal(fmt.Sprintf("%s-%s", multiclusterGatewayFilterChainName, tests.BookstoreV1ServiceName), tcpFilterChain.Name)
+	assert.Nil(tcpFilterChain.TransportSocket)
+}
@@ -4,14 +4,17 @@ import (
 	"fmt"
 
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_http_dfp "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/dynamic_forward_proxy/v3"
 	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 
 	"github.com/openservicemesh/osm/pkg/auth"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
 )
 
 // connectionDirection defines, for filter terms, the direction of a connection from
@@ -40,6 +43,14 @@ type httpConnManagerOptions struct {
 	extAuthConfig            *auth.ExtAuthConfig
 	enableActiveHealthChecks bool
 
+	// enableDynamicForwardProxy enables the dynamic forward proxy filter, required to route requests
+	// to dynamic forward proxy clusters
+	enableDynamicForwardProxy bool
+
+	// egressSourceIdentity is the source identity of egress traffic. If set, requests are logged
+	// using the egress access log.
+	egressSourceIdentity identity.ServiceIdentity
+
 	// Tracing options
 	enableTracing      bool
 	tracingAPIEndpoint string
@@ -67,6 +78,10 @@ func (options httpConnManagerOptions) build() (*xds_hcm.HttpConnectionManager, e
 		AccessLog: envoy.GetAccessLog(),
 	}
 
+	if options.egressSourceIdentity != "" {
+		connManager.AccessLog = envoy.GetEgressAccessLog(options.egressSourceIdentity)
+	}
+
 	// For inbound connections, add the Authz filter
 	if options.direction == inbound && options.extAuthConfig != nil {
 		connManager.HttpFilters = append(connManager.HttpFilters, getExtAuthzHTTPFilter(options.extAuthConfig))
@@ -103,12 +118,36 @@ func (options httpConnManagerOptions) build() (*xds_hcm.HttpConnectionManager, e
 		connManager.HttpFilters = append(connManager.HttpFilters, hc)
 	}
 
+	if options.enableDynamicForwardProxy {
+		dfp, err := getDynamicForwardProxyHTTPFilter()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting dynamic forward proxy filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, dfp)
+	}
+
 	// *IMPORTANT NOTE*: The Router filter must always be the last filter
 	connManager.HttpFilters = append(connManager.HttpFilters, &xds_hcm.HttpFilter{Name: wellknown.Router})
 
 	return connManager, nil
 }
 
+// getDynamicForwardProxyHTTPFilter returns the dynamic forward proxy HTTP filter, which resolves the host in the
+// request's host header for requests routed to dynamic forward proxy clusters
+func getDynamicForwardProxyHTTPFilter() (*xds_hcm.HttpFilter, error) {
+	marshalledFilterConfig, err := ptypes.MarshalAny(&xds_http_dfp.FilterConfig{
+		DnsCacheConfig: envoy.GetEgressDNSCacheConfig(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name:       envoy.DynamicForwardProxyHTTPFilterName,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{TypedConfig: marshalledFilterConfig},
+	}, nil
+}
+
 func getPrometheusConnectionManager() *xds_hcm.HttpConnectionManager {
 	return &xds_hcm.HttpConnectionManager{
 		StatPrefix: prometheusHTTPConnManagerStatPrefix,
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/openservicemesh/osm/pkg/auth"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
 )
 
 func TestHTTPConnbuild(t *testing.T) {
@@ -59,6 +61,42 @@ func TestHTTPConnbuild(t *testing.T) {
 				a.Nil(connManager.Tracing)
 			},
 		},
+		{
+			name: "dynamic forward proxy filter when enabled",
+			option: httpConnManagerOptions{
+				enableDynamicForwardProxy: true,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.True(contains(connManager.HttpFilters, envoy.DynamicForwardProxyHTTPFilterName))
+				// The router filter must be the last filter
+				a.Equal(wellknown.Router, connManager.HttpFilters[len(connManager.HttpFilters)-1].Name)
+			},
+		},
+		{
+			name: "egress access log when the egress source identity is set",
+			option: httpConnManagerOptions{
+				egressSourceIdentity: identity.K8sServiceAccount{Name: "sa", Namespace: "ns"}.ToServiceIdentity(),
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Equal("egress", getAccessLogField(a, connManager.AccessLog, "log_type"))
+			},
+		},
+		{
+			name:   "default access log when the egress source identity is not set",
+			option: httpConnManagerOptions{},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Equal("", getAccessLogField(a, connManager.AccessLog, "log_type"))
+			},
+		},
+		{
+			name: "dynamic forward proxy filter when disabled",
+			option: httpConnManagerOptions{
+				enableDynamicForwardProxy: false,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.True(notContains(connManager.HttpFilters, envoy.DynamicForwardProxyHTTPFilterName))
+			},
+		},
 		{
 			name: "WASM config when WASM stats headers are unset",
 			option: httpConnManagerOptions{
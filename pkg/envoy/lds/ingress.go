@@ -1,10 +1,12 @@
 package lds
 
 import (
+	"fmt"
 	"strings"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/pkg/errors"
@@ -18,6 +20,11 @@ import (
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
+const (
+	// ingressTCPProxyStatsPrefix is the stats prefix for the TCP proxy filter of TCP ingress filter chains
+	ingressTCPProxyStatsPrefix = "ingress"
+)
+
 func (lb *listenerBuilder) getIngressFilterChains(svc service.MeshService) []*xds_listener.FilterChain {
 	ingressPolicy, err := lb.meshCatalog.GetIngressTrafficPolicy(svc)
 	if err != nil {
@@ -48,28 +55,6 @@ func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *t
 		return nil, errors.Errorf("Nil IngressTrafficMatch for ingress on proxy with identity %s", lb.serviceIdentity)
 	}
 
-	// Build the HTTP Connection Manager filter from its options
-	ingressConnManager, err := httpConnManagerOptions{
-		direction:         inbound,
-		rdsRoutConfigName: route.IngressRouteConfigName,
-
-		// Additional filters
-		wasmStatsHeaders: nil, // no WASM Stats for ingress traffic
-		extAuthConfig:    lb.getExtAuthConfig(),
-
-		// Tracing options
-		enableTracing:      lb.cfg.IsTracingEnabled(),
-		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
-	}.build()
-	if err != nil {
-		return nil, errors.Errorf("Error building inbound HTTP connection manager for proxy with identity %s, traffic match: %v ", lb.serviceIdentity, trafficMatch)
-	}
-
-	marshalledIngressConnManager, err := ptypes.MarshalAny(ingressConnManager)
-	if err != nil {
-		return nil, errors.Errorf("Error marshalling ingress HttpConnectionManager object for proxy with identity %s", lb.serviceIdentity)
-	}
-
 	var sourcePrefixes []*xds_core.CidrRange
 	for _, ipRange := range trafficMatch.SourceIPRanges {
 		cidr, err := envoy.GetCIDRRangeFromStr(ipRange)
@@ -88,14 +73,6 @@ func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *t
 			},
 			SourcePrefixRanges: sourcePrefixes,
 		},
-		Filters: []*xds_listener.Filter{
-			{
-				Name: wellknown.HTTPConnectionManager,
-				ConfigType: &xds_listener.Filter_TypedConfig{
-					TypedConfig: marshalledIngressConnManager,
-				},
-			},
-		},
 	}
 
 	switch strings.ToLower(trafficMatch.Protocol) {
@@ -105,6 +82,12 @@ func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *t
 			log.Warn().Msgf("Allowing HTTP ingress on proxy with identity %s is insecure, use IngressBackend.Spec.Sources to restrict clients", lb.serviceIdentity)
 		}
 
+		httpConnManagerFilter, err := lb.getIngressHTTPConnManagerFilter(trafficMatch)
+		if err != nil {
+			return nil, err
+		}
+		filterChain.Filters = []*xds_listener.Filter{httpConnManagerFilter}
+
 	case constants.ProtocolHTTPS:
 		// For HTTPS backend, configure the following:
 		// 1. TransportProtocol to match TLS
@@ -125,11 +108,93 @@ func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *t
 			},
 		}
 
+		httpConnManagerFilter, err := lb.getIngressHTTPConnManagerFilter(trafficMatch)
+		if err != nil {
+			return nil, err
+		}
+		filterChain.Filters = []*xds_listener.Filter{httpConnManagerFilter}
+
+	case constants.ProtocolTCP:
+		// For TCP backends, the ingress gateway's mTLS connection is terminated and proxied to the local cluster if
+		// the downstream identity is allowed. This is used by the ingress gateway to route TLS traffic to backends.
+		if len(trafficMatch.AllowedServiceIdentities) == 0 {
+			// An RBAC policy without principals allows any downstream
+			return nil, errors.Errorf("No downstream identities are allowed for TCP ingress on proxy with identity %s, traffic match: %v", lb.serviceIdentity, trafficMatch)
+		}
+
+		filterChain.FilterChainMatch.TransportProtocol = envoy.TransportProtocolTLS
+		filterChain.FilterChainMatch.ServerNames = trafficMatch.ServerNames
+
+		marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(lb.serviceIdentity, true /* mTLS */))
+		if err != nil {
+			return nil, errors.Errorf("Error marshalling DownstreamTLSContext in ingress filter chain for proxy with identity %s", lb.serviceIdentity)
+		}
+
+		filterChain.TransportSocket = &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledDownstreamTLSContext,
+			},
+		}
+
+		rbacFilter, err := buildNetworkRBACFilterForIdentities(trafficMatch.Name, trafficMatch.AllowedServiceIdentities)
+		if err != nil {
+			return nil, err
+		}
+
+		marshalledTCPProxy, err := ptypes.MarshalAny(&xds_tcp_proxy.TcpProxy{
+			StatPrefix:       fmt.Sprintf("%s.%s", ingressTCPProxyStatsPrefix, trafficMatch.ClusterName),
+			ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: trafficMatch.ClusterName},
+		})
+		if err != nil {
+			return nil, errors.Errorf("Error marshalling TcpProxy in ingress filter chain for proxy with identity %s", lb.serviceIdentity)
+		}
+
+		filterChain.Filters = []*xds_listener.Filter{
+			rbacFilter,
+			{
+				Name:       wellknown.TCPProxy,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+			},
+		}
+
 	default:
-		err := errors.Errorf("Unsupported ingress protocol %s on proxy with identity %s. Ingress protocol must be one of 'http, https'", trafficMatch.Protocol, lb.serviceIdentity)
+		err := errors.Errorf("Unsupported ingress protocol %s on proxy with identity %s. Ingress protocol must be one of 'http, https, tcp'", trafficMatch.Protocol, lb.serviceIdentity)
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrUnsupportedProtocolForService)).Msg("Error building filter chain for ingress")
 		return nil, err
 	}
 
 	return filterChain, nil
 }
+
+// getIngressHTTPConnManagerFilter returns the HTTP connection manager filter for the given HTTP ingress traffic match
+func (lb *listenerBuilder) getIngressHTTPConnManagerFilter(trafficMatch *trafficpolicy.IngressTrafficMatch) (*xds_listener.Filter, error) {
+	// Build the HTTP Connection Manager filter from its options
+	ingressConnManager, err := httpConnManagerOptions{
+		direction:         inbound,
+		rdsRoutConfigName: route.IngressRouteConfigName,
+
+		// Additional filters
+		wasmStatsHeaders: nil, // no WASM Stats for ingress traffic
+		extAuthConfig:    lb.getExtAuthConfig(),
+
+		// Tracing options
+		enableTracing:      lb.cfg.IsTracingEnabled(),
+		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
+	}.build()
+	if err != nil {
+		return nil, errors.Errorf("Error building inbound HTTP connection manager for proxy with identity %s, traffic match: %v ", lb.serviceIdentity, trafficMatch)
+	}
+
+	marshalledIngressConnManager, err := ptypes.MarshalAny(ingressConnManager)
+	if err != nil {
+		return nil, errors.Errorf("Error marshalling ingress HttpConnectionManager object for proxy with identity %s", lb.serviceIdentity)
+	}
+
+	return &xds_listener.Filter{
+		Name: wellknown.HTTPConnectionManager,
+		ConfigType: &xds_listener.Filter_TypedConfig{
+			TypedConfig: marshalledIngressConnManager,
+		},
+	}, nil
+}
@@ -0,0 +1,254 @@
+package lds
+
+import (
+	"fmt"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	ingressGatewayListenerNamePrefix  = "ingress-gateway-listener"
+	ingressGatewayTCPProxyStatsPrefix = "ingress-gateway"
+
+	// transportProtocolRawBuffer is the transport protocol of plaintext connections detected by the TLS inspector
+	transportProtocolRawBuffer = "raw_buffer"
+)
+
+// buildIngressGatewayListeners builds the listeners for the ingress gateway, one for each port of the listeners of the
+// Gateways managed by OSM. HTTP traffic is routed using the route configuration for the port, while TLS traffic is
+// routed based on its SNI.
+func (lb *listenerBuilder) buildIngressGatewayListeners() []*xds_listener.Listener {
+	gatewayTrafficPolicy := lb.meshCatalog.GetIngressGatewayTrafficPolicy()
+	if gatewayTrafficPolicy == nil {
+		return nil
+	}
+
+	var listeners []*xds_listener.Listener
+	for _, gatewayListener := range gatewayTrafficPolicy.Listeners {
+		var filterChains []*xds_listener.FilterChain
+		// Filter chains with the same match are merged by Gateway listeners sharing the route configuration of the port.
+		// Envoy rejects a listener whose filter chains match the same transport protocol and server name, so a filter
+		// chain overlapping a previous one on any of its matches is ignored.
+		filterChainMatches := make(map[string]string)
+		for _, gatewayFilterChain := range gatewayListener.FilterChains {
+			filterChain, err := lb.getIngressGatewayFilterChain(gatewayListener.Port, gatewayFilterChain)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error building ingress gateway filter chain %s on port %d", gatewayFilterChain.Name, gatewayListener.Port)
+				continue
+			}
+
+			matchKeys := getFilterChainMatchKeys(filterChain.FilterChainMatch)
+			if existing, conflict := getConflictingFilterChain(filterChainMatches, matchKeys); conflict {
+				if gatewayFilterChain.Protocol != constants.ProtocolHTTP {
+					log.Error().Msgf("Filter chain %s on port %d conflicts with filter chain %s, ignoring filter chain",
+						gatewayFilterChain.Name, gatewayListener.Port, existing)
+				}
+				continue
+			}
+
+			for _, matchKey := range matchKeys {
+				filterChainMatches[matchKey] = gatewayFilterChain.Name
+			}
+			filterChains = append(filterChains, filterChain)
+		}
+
+		if len(filterChains) == 0 {
+			// Configuring a listener without a filter chain is an error
+			continue
+		}
+
+		listeners = append(listeners, &xds_listener.Listener{
+			Name:         fmt.Sprintf("%s-%d", ingressGatewayListenerNamePrefix, gatewayListener.Port),
+			Address:      envoy.GetWildcardAddress(uint32(gatewayListener.Port), lb.cfg.GetFeatureFlags().EnableIPv6),
+			FilterChains: filterChains,
+			ListenerFilters: []*xds_listener.ListenerFilter{
+				{
+					Name: wellknown.TlsInspector,
+				},
+			},
+		})
+	}
+
+	return listeners
+}
+
+// getIngressGatewayFilterChain returns the filter chain on the given port for the given ingress gateway filter chain
+func (lb *listenerBuilder) getIngressGatewayFilterChain(port int, gatewayFilterChain *trafficpolicy.IngressGatewayFilterChain) (*xds_listener.FilterChain, error) {
+	filterChain := &xds_listener.FilterChain{
+		Name: gatewayFilterChain.Name,
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			ServerNames: gatewayFilterChain.ServerNames,
+		},
+	}
+
+	switch gatewayFilterChain.Protocol {
+	case constants.ProtocolHTTP:
+		filterChain.FilterChainMatch.TransportProtocol = transportProtocolRawBuffer
+		httpConnManagerFilter, err := lb.getIngressGatewayHTTPConnManagerFilter(port)
+		if err != nil {
+			return nil, err
+		}
+		filterChain.Filters = []*xds_listener.Filter{httpConnManagerFilter}
+
+	case constants.ProtocolHTTPS:
+		filterChain.FilterChainMatch.TransportProtocol = envoy.TransportProtocolTLS
+		transportSocket, err := getIngressGatewayDownstreamTransportSocket(gatewayFilterChain)
+		if err != nil {
+			return nil, err
+		}
+		filterChain.TransportSocket = transportSocket
+
+		httpConnManagerFilter, err := lb.getIngressGatewayHTTPConnManagerFilter(port)
+		if err != nil {
+			return nil, err
+		}
+		filterChain.Filters = []*xds_listener.Filter{httpConnManagerFilter}
+
+	case constants.ProtocolTCP:
+		filterChain.FilterChainMatch.TransportProtocol = envoy.TransportProtocolTLS
+		// TLS is passed through to the backends unless the listener terminates TLS
+		if len(gatewayFilterChain.CertificateSecrets) > 0 {
+			transportSocket, err := getIngressGatewayDownstreamTransportSocket(gatewayFilterChain)
+			if err != nil {
+				return nil, err
+			}
+			filterChain.TransportSocket = transportSocket
+		}
+
+		tcpProxyFilter, err := getIngressGatewayTCPProxyFilter(gatewayFilterChain)
+		if err != nil {
+			return nil, err
+		}
+		filterChain.Filters = []*xds_listener.Filter{tcpProxyFilter}
+
+	default:
+		return nil, errors.Errorf("Unsupported protocol %s for ingress gateway filter chain %s", gatewayFilterChain.Protocol, gatewayFilterChain.Name)
+	}
+
+	return filterChain, nil
+}
+
+// getIngressGatewayHTTPConnManagerFilter returns the HTTP connection manager filter routing requests using the
+// route configuration for the given port
+func (lb *listenerBuilder) getIngressGatewayHTTPConnManagerFilter(port int) (*xds_listener.Filter, error) {
+	connManager, err := httpConnManagerOptions{
+		direction:         outbound,
+		rdsRoutConfigName: route.GetIngressGatewayRouteConfigNameForPort(port),
+
+		// Tracing options
+		enableTracing:      lb.cfg.IsTracingEnabled(),
+		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
+	}.build()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error building HTTP connection manager for ingress gateway port %d", port)
+	}
+
+	marshalledConnManager, err := ptypes.MarshalAny(connManager)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling HttpConnectionManager for ingress gateway port %d", port)
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.HTTPConnectionManager,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledConnManager},
+	}, nil
+}
+
+// getIngressGatewayTCPProxyFilter returns the TCP proxy filter proxying connections to the weighted clusters of the
+// given filter chain
+func getIngressGatewayTCPProxyFilter(gatewayFilterChain *trafficpolicy.IngressGatewayFilterChain) (*xds_listener.Filter, error) {
+	if len(gatewayFilterChain.WeightedClusters) == 0 {
+		return nil, errors.Errorf("No backends for ingress gateway filter chain %s", gatewayFilterChain.Name)
+	}
+
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix: fmt.Sprintf("%s.%s", ingressGatewayTCPProxyStatsPrefix, gatewayFilterChain.Name),
+		AccessLog:  envoy.GetAccessLog(),
+	}
+	if len(gatewayFilterChain.WeightedClusters) == 1 {
+		tcpProxy.ClusterSpecifier = &xds_tcp_proxy.TcpProxy_Cluster{Cluster: gatewayFilterChain.WeightedClusters[0].ClusterName.String()}
+	} else {
+		var clusterWeights []*xds_tcp_proxy.TcpProxy_WeightedCluster_ClusterWeight
+		for _, weightedCluster := range gatewayFilterChain.WeightedClusters {
+			clusterWeights = append(clusterWeights, &xds_tcp_proxy.TcpProxy_WeightedCluster_ClusterWeight{
+				Name:   weightedCluster.ClusterName.String(),
+				Weight: uint32(weightedCluster.Weight),
+			})
+		}
+		tcpProxy.ClusterSpecifier = &xds_tcp_proxy.TcpProxy_WeightedClusters{
+			WeightedClusters: &xds_tcp_proxy.TcpProxy_WeightedCluster{Clusters: clusterWeights},
+		}
+	}
+
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling TcpProxy for ingress gateway filter chain %s", gatewayFilterChain.Name)
+		return nil, err
+	}
+
+	return &xds_listener.Filter{
+		Name:       wellknown.TCPProxy,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+	}, nil
+}
+
+// getIngressGatewayDownstreamTransportSocket returns the transport socket terminating TLS using the certificates
+// referenced by the given filter chain
+func getIngressGatewayDownstreamTransportSocket(gatewayFilterChain *trafficpolicy.IngressGatewayFilterChain) (*xds_core.TransportSocket, error) {
+	if len(gatewayFilterChain.CertificateSecrets) == 0 {
+		return nil, errors.Errorf("No certificates to terminate TLS for ingress gateway filter chain %s", gatewayFilterChain.Name)
+	}
+
+	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetIngressGatewayDownstreamTLSContext(gatewayFilterChain.CertificateSecrets))
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling DownstreamTLSContext for ingress gateway filter chain %s", gatewayFilterChain.Name)
+		return nil, err
+	}
+
+	return &xds_core.TransportSocket{
+		Name: wellknown.TransportSocketTls,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{
+			TypedConfig: marshalledDownstreamTLSContext,
+		},
+	}, nil
+}
+
+// getFilterChainMatchKeys returns the keys identifying the matches of the given filter chain match, one for each of
+// its server names
+func getFilterChainMatchKeys(filterChainMatch *xds_listener.FilterChainMatch) []string {
+	if len(filterChainMatch.ServerNames) == 0 {
+		return []string{filterChainMatch.TransportProtocol + "|"}
+	}
+
+	var matchKeys []string
+	for _, serverName := range filterChainMatch.ServerNames {
+		matchKeys = append(matchKeys, fmt.Sprintf("%s|%s", filterChainMatch.TransportProtocol, serverName))
+	}
+	return matchKeys
+}
+
+// getConflictingFilterChain returns the name of the filter chain already matching any of the given match keys, and
+// a boolean indicating if such a filter chain exists
+func getConflictingFilterChain(filterChainMatches map[string]string, matchKeys []string) (string, bool) {
+	for _, matchKey := range matchKeys {
+		if existing, ok := filterChainMatches[matchKey]; ok {
+			return existing, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,195 @@
+package lds
+
+import (
+	"testing"
+
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestBuildIngressGatewayListeners(t *testing.T) {
+	certSecrets := []types.NamespacedName{{Namespace: "test", Name: "cert"}}
+
+	testCases := []struct {
+		name                 string
+		gatewayPolicy        *trafficpolicy.IngressGatewayTrafficPolicy
+		expectedListeners    []string
+		expectedFilterChains []int
+	}{
+		{
+			name:              "no ingress gateway traffic policy",
+			gatewayPolicy:     nil,
+			expectedListeners: nil,
+		},
+		{
+			name: "HTTP, HTTPS and TLS listeners",
+			gatewayPolicy: &trafficpolicy.IngressGatewayTrafficPolicy{
+				Listeners: []*trafficpolicy.IngressGatewayListener{
+					{
+						Port: 80,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{
+							{Name: "test/gateway-1/http", Protocol: constants.ProtocolHTTP},
+							// HTTP listeners on the same port share the route configuration for the port
+							{Name: "test/gateway-2/http", Protocol: constants.ProtocolHTTP},
+						},
+					},
+					{
+						Port: 443,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{
+							{Name: "test/gateway-1/https", Protocol: constants.ProtocolHTTPS, ServerNames: []string{"foo.com"}, CertificateSecrets: certSecrets},
+							{
+								Name:             "test/gateway-1/tls/test/route",
+								Protocol:         constants.ProtocolTCP,
+								ServerNames:      []string{"db.foo.com"},
+								WeightedClusters: []service.WeightedCluster{{ClusterName: "test/db|5432", Weight: 1}},
+							},
+						},
+					},
+				},
+			},
+			expectedListeners:    []string{"ingress-gateway-listener-80", "ingress-gateway-listener-443"},
+			expectedFilterChains: []int{1, 2},
+		},
+		{
+			name: "filter chains overlapping on a TLS server name are skipped",
+			gatewayPolicy: &trafficpolicy.IngressGatewayTrafficPolicy{
+				Listeners: []*trafficpolicy.IngressGatewayListener{
+					{
+						Port: 443,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{
+							{Name: "test/gateway-1/https", Protocol: constants.ProtocolHTTPS, ServerNames: []string{"foo.com", "bar.com"}, CertificateSecrets: certSecrets},
+							// TLS passthrough matching foo.com, which is already matched by the HTTPS filter chain
+							{
+								Name:             "test/gateway-2/tls/test/route",
+								Protocol:         constants.ProtocolTCP,
+								ServerNames:      []string{"foo.com", "db.foo.com"},
+								WeightedClusters: []service.WeightedCluster{{ClusterName: "test/db|5432", Weight: 1}},
+							},
+							{
+								Name:             "test/gateway-2/tls/test/other-route",
+								Protocol:         constants.ProtocolTCP,
+								ServerNames:      []string{"baz.com"},
+								WeightedClusters: []service.WeightedCluster{{ClusterName: "test/db|5432", Weight: 1}},
+							},
+						},
+					},
+				},
+			},
+			expectedListeners:    []string{"ingress-gateway-listener-443"},
+			expectedFilterChains: []int{2},
+		},
+		{
+			name: "invalid filter chains are skipped",
+			gatewayPolicy: &trafficpolicy.IngressGatewayTrafficPolicy{
+				Listeners: []*trafficpolicy.IngressGatewayListener{
+					{
+						Port: 443,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{
+							// HTTPS without certificates
+							{Name: "test/gateway/https", Protocol: constants.ProtocolHTTPS},
+							// TLS without backends
+							{Name: "test/gateway/tls/test/route", Protocol: constants.ProtocolTCP},
+						},
+					},
+				},
+			},
+			expectedListeners: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableIngressGateway: true}).AnyTimes()
+			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetTracingEndpoint().Return("").AnyTimes()
+			mockCatalog.EXPECT().GetIngressGatewayTrafficPolicy().Return(tc.gatewayPolicy).Times(1)
+
+			lb := &listenerBuilder{
+				meshCatalog: mockCatalog,
+				cfg:         mockConfigurator,
+			}
+
+			listeners := lb.buildIngressGatewayListeners()
+			assert.Len(listeners, len(tc.expectedListeners))
+			for i, listener := range listeners {
+				assert.Equal(tc.expectedListeners[i], listener.Name)
+				assert.Len(listener.ListenerFilters, 1)
+				assert.Len(listener.FilterChains, tc.expectedFilterChains[i])
+			}
+		})
+	}
+}
+
+func TestGetIngressGatewayFilterChain(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTracingEndpoint().Return("").AnyTimes()
+
+	lb := &listenerBuilder{
+		cfg: mockConfigurator,
+	}
+
+	// HTTP traffic is matched on plaintext connections
+	filterChain, err := lb.getIngressGatewayFilterChain(80, &trafficpolicy.IngressGatewayFilterChain{Name: "http", Protocol: constants.ProtocolHTTP})
+	assert.Nil(err)
+	assert.Equal(transportProtocolRawBuffer, filterChain.FilterChainMatch.TransportProtocol)
+	assert.Nil(filterChain.TransportSocket)
+	assert.Len(filterChain.Filters, 1)
+	assert.Equal(wellknown.HTTPConnectionManager, filterChain.Filters[0].Name)
+
+	// HTTPS traffic is terminated using the listener's certificates
+	filterChain, err = lb.getIngressGatewayFilterChain(443, &trafficpolicy.IngressGatewayFilterChain{
+		Name:               "https",
+		Protocol:           constants.ProtocolHTTPS,
+		ServerNames:        []string{"foo.com"},
+		CertificateSecrets: []types.NamespacedName{{Namespace: "test", Name: "cert"}},
+	})
+	assert.Nil(err)
+	assert.Equal(envoy.TransportProtocolTLS, filterChain.FilterChainMatch.TransportProtocol)
+	assert.Equal([]string{"foo.com"}, filterChain.FilterChainMatch.ServerNames)
+	assert.NotNil(filterChain.TransportSocket)
+	assert.Equal(wellknown.HTTPConnectionManager, filterChain.Filters[0].Name)
+
+	// TLS traffic is passed through to the weighted backends
+	filterChain, err = lb.getIngressGatewayFilterChain(443, &trafficpolicy.IngressGatewayFilterChain{
+		Name:        "tls",
+		Protocol:    constants.ProtocolTCP,
+		ServerNames: []string{"db.foo.com"},
+		WeightedClusters: []service.WeightedCluster{
+			{ClusterName: "test/db-v1|5432", Weight: 90},
+			{ClusterName: "test/db-v2|5432", Weight: 10},
+		},
+	})
+	assert.Nil(err)
+	assert.Equal(envoy.TransportProtocolTLS, filterChain.FilterChainMatch.TransportProtocol)
+	assert.Nil(filterChain.TransportSocket)
+	assert.Len(filterChain.Filters, 1)
+	assert.Equal(wellknown.TCPProxy, filterChain.Filters[0].Name)
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	err = ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), tcpProxy)
+	assert.Nil(err)
+	assert.Len(tcpProxy.GetWeightedClusters().Clusters, 2)
+	assert.Equal(uint32(90), tcpProxy.GetWeightedClusters().Clusters[0].Weight)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/auth"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
@@ -144,6 +145,36 @@ func TestGetIngressFilterChainFromTrafficMatch(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "TCP traffic match from the ingress gateway",
+			trafficMatch: &trafficpolicy.IngressTrafficMatch{
+				Name:                     "ingress-gateway_tcp",
+				Port:                     80,
+				Protocol:                 "tcp",
+				ServerNames:              []string{"foo.bar.svc.cluster.local"},
+				AllowedServiceIdentities: []identity.ServiceIdentity{"osm-ingress-gateway.osm-system.cluster.local"},
+				ClusterName:              "bar/foo|80|local",
+			},
+			expectedEnvoyFilters: []string{wellknown.RoleBasedAccessControl, wellknown.TCPProxy},
+			expectedFilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+				TransportProtocol: "tls",
+				ServerNames:       []string{"foo.bar.svc.cluster.local"},
+			},
+			expectError: false,
+		},
+		{
+			name: "TCP traffic match without allowed identities",
+			trafficMatch: &trafficpolicy.IngressTrafficMatch{
+				Name:        "ingress-gateway_tcp",
+				Port:        80,
+				Protocol:    "tcp",
+				ClusterName: "bar/foo|80|local",
+			},
+			expectedEnvoyFilters:     nil,
+			expectedFilterChainMatch: nil,
+			expectError:              true,
+		},
 		{
 			name: "unsupported protocol",
 			trafficMatch: &trafficpolicy.IngressTrafficMatch{
@@ -170,19 +201,22 @@ func TestGetIngressFilterChainFromTrafficMatch(t *testing.T) {
 				cfg:             mockConfigurator,
 			}
 
-			mockConfigurator.EXPECT().IsTracingEnabled().Return(false)
-			mockConfigurator.EXPECT().GetTracingEndpoint().Return("test")
+			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetTracingEndpoint().Return("test").AnyTimes()
 			mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 				Enable: false,
-			})
+			}).AnyTimes()
 
 			actual, err := lb.getIngressFilterChainFromTrafficMatch(tc.trafficMatch)
 			assert.Equal(tc.expectError, err != nil)
 
 			if err == nil {
 				assert.Equal(tc.expectedFilterChainMatch, actual.FilterChainMatch)
-				assert.Len(actual.Filters, 1) // Single HTTPConnectionManager filter
-				assert.Equal(wellknown.HTTPConnectionManager, actual.Filters[0].Name)
+				var actualFilters []string
+				for _, filter := range actual.Filters {
+					actualFilters = append(actualFilters, filter.Name)
+				}
+				assert.Equal(tc.expectedEnvoyFilters, actualFilters)
 			}
 		})
 	}
@@ -7,6 +7,7 @@ import (
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
@@ -17,6 +18,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
@@ -33,6 +35,17 @@ const (
 func (lb *listenerBuilder) getInboundMeshFilterChains(proxyService service.MeshService) []*xds_listener.FilterChain {
 	var filterChains []*xds_listener.FilterChain
 
+	// The admin interface of Envoy, bound to the loopback address of the pod, must never be reachable from the mesh
+	if proxyService.TargetPort == constants.EnvoyAdminPort {
+		log.Error().Msgf("Cannot build inbound filter chain for proxy-service:port %s:%d, the port is reserved for the admin interface of Envoy", proxyService, proxyService.TargetPort)
+		return nil
+	}
+
+	// The protocol of the ports whose protocol is detected by the downstream is resolved for the inbound traffic
+	if proxyService.Protocol == constants.ProtocolAuto {
+		proxyService.Protocol = lb.meshCatalog.GetInboundServiceProtocol(lb.serviceIdentity, proxyService)
+	}
+
 	// Create protocol specific inbound filter chains for MeshService's TargetPort
 	switch strings.ToLower(proxyService.Protocol) {
 	case constants.ProtocolHTTP, constants.ProtocolGRPC:
@@ -43,7 +56,8 @@ func (lb *listenerBuilder) getInboundMeshFilterChains(proxyService service.MeshS
 		}
 		filterChains = append(filterChains, filterChainForPort)
 
-	case constants.ProtocolTCP, constants.ProtocolTCPServerFirst:
+	case constants.ProtocolTCP, constants.ProtocolTCPServerFirst,
+		constants.ProtocolMySQL, constants.ProtocolPostgres, constants.ProtocolRedis, constants.ProtocolMongo:
 		filterChainForPort, err := lb.getInboundMeshTCPFilterChain(proxyService, uint32(proxyService.TargetPort))
 		if err != nil {
 			log.Error().Err(err).Msgf("Error building inbound TCP filter chain for proxy:port %s:%d", proxyService, proxyService.TargetPort)
@@ -240,8 +254,10 @@ func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService
 	return filters, nil
 }
 
-// getOutboundHTTPFilter returns an HTTP connection manager network filter used to filter outbound HTTP traffic for the given route configuration
-func (lb *listenerBuilder) getOutboundHTTPFilter(routeConfigName string) (*xds_listener.Filter, error) {
+// getOutboundHTTPFilter returns an HTTP connection manager network filter used to filter outbound HTTP traffic for the given route configuration.
+// The dynamic forward proxy filter is enabled if the routes may direct traffic to dynamic forward proxy clusters.
+// If the egress source identity is set, requests are logged using the egress access log.
+func (lb *listenerBuilder) getOutboundHTTPFilter(routeConfigName string, enableDynamicForwardProxy bool, egressSourceIdentity identity.ServiceIdentity) (*xds_listener.Filter, error) {
 	var marshalledFilter *any.Any
 	var err error
 
@@ -254,6 +270,9 @@ func (lb *listenerBuilder) getOutboundHTTPFilter(routeConfigName string) (*xds_l
 		wasmStatsHeaders: lb.statsHeaders,
 		extAuthConfig:    nil, // Ext auth is not configured for outbound connections
 
+		enableDynamicForwardProxy: enableDynamicForwardProxy,
+		egressSourceIdentity:      egressSourceIdentity,
+
 		// Tracing options
 		enableTracing:      lb.cfg.IsTracingEnabled(),
 		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
@@ -302,7 +321,7 @@ func (lb *listenerBuilder) getOutboundFilterChainMatchForService(trafficMatch tr
 
 func (lb *listenerBuilder) getOutboundHTTPFilterChainForService(trafficMatch trafficpolicy.TrafficMatch) (*xds_listener.FilterChain, error) {
 	// Get HTTP filter for service
-	filter, err := lb.getOutboundHTTPFilter(route.GetOutboundMeshRouteConfigNameForPort(trafficMatch.DestinationPort))
+	filter, err := lb.getOutboundHTTPFilter(route.GetOutboundMeshRouteConfigNameForPort(trafficMatch.DestinationPort), false, "")
 	if err != nil {
 		log.Error().Err(err).Msgf("Error getting HTTP filter for traffic match %s", trafficMatch.Name)
 		return nil, err
@@ -324,10 +343,10 @@ func (lb *listenerBuilder) getOutboundHTTPFilterChainForService(trafficMatch tra
 }
 
 func (lb *listenerBuilder) getOutboundTCPFilterChainForService(trafficMatch trafficpolicy.TrafficMatch) (*xds_listener.FilterChain, error) {
-	// Get TCP filter for service
-	filter, err := lb.getOutboundTCPFilter(trafficMatch)
+	// Get TCP filters for service
+	filters, err := lb.getOutboundTCPFilters(trafficMatch)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error getting outbound TCP filter for traffic match %s", trafficMatch.Name)
+		log.Error().Err(err).Msgf("Error getting outbound TCP filters for traffic match %s", trafficMatch.Name)
 		return nil, err
 	}
 
@@ -341,7 +360,7 @@ func (lb *listenerBuilder) getOutboundTCPFilterChainForService(trafficMatch traf
 	filterChainName := fmt.Sprintf("%s:%s", outboundMeshTCPFilterChainPrefix, trafficMatch.Name)
 	return &xds_listener.FilterChain{
 		Name:             filterChainName,
-		Filters:          []*xds_listener.Filter{filter},
+		Filters:          filters,
 		FilterChainMatch: filterChainMatch,
 	}, nil
 }
@@ -372,6 +391,17 @@ func (lb *listenerBuilder) getOutboundTCPFilter(trafficMatch trafficpolicy.Traff
 		}
 	}
 
+	// Only a source IP based hash key is applicable to consistent-hash load balancing of TCP connections
+	if trafficMatch.HashPolicy != nil && trafficMatch.HashPolicy.Kind == trafficpolicy.HashKeySourceIP {
+		tcpProxy.HashPolicy = []*xds_type.HashPolicy{
+			{
+				PolicySpecifier: &xds_type.HashPolicy_SourceIp_{
+					SourceIp: &xds_type.HashPolicy_SourceIp{},
+				},
+			},
+		}
+	}
+
 	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
@@ -385,17 +415,11 @@ func (lb *listenerBuilder) getOutboundTCPFilter(trafficMatch trafficpolicy.Traff
 	}, nil
 }
 
-// getOutboundFilterChainPerUpstream returns a list of filter chains corresponding to upstream services
-func (lb *listenerBuilder) getOutboundFilterChainPerUpstream() []*xds_listener.FilterChain {
+// getOutboundFilterChainPerUpstream returns a list of filter chains corresponding to the given upstream traffic matches
+func (lb *listenerBuilder) getOutboundFilterChainPerUpstream(trafficMatches []*trafficpolicy.TrafficMatch) []*xds_listener.FilterChain {
 	var filterChains []*xds_listener.FilterChain
 
-	outboundMeshTrafficPolicy := lb.meshCatalog.GetOutboundMeshTrafficPolicy(lb.serviceIdentity)
-	if outboundMeshTrafficPolicy == nil {
-		// no outbound mesh traffic policies
-		return nil
-	}
-
-	for _, trafficMatch := range outboundMeshTrafficPolicy.TrafficMatches {
+	for _, trafficMatch := range trafficMatches {
 		log.Trace().Msgf("Building outbound mesh filter chain %s for proxy with identity %s", trafficMatch.Name, lb.serviceIdentity)
 		// Create an outbound filter chain match per TrafficMatch object
 		switch strings.ToLower(trafficMatch.DestinationProtocol) {
@@ -407,7 +431,8 @@ func (lb *listenerBuilder) getOutboundFilterChainPerUpstream() []*xds_listener.F
 				filterChains = append(filterChains, httpFilterChain)
 			}
 
-		case constants.ProtocolTCP, constants.ProtocolTCPServerFirst:
+		case constants.ProtocolTCP, constants.ProtocolTCPServerFirst,
+			constants.ProtocolMySQL, constants.ProtocolPostgres, constants.ProtocolRedis, constants.ProtocolMongo:
 			// Construct TCP filter chain
 			if tcpFilterChain, err := lb.getOutboundTCPFilterChainForService(*trafficMatch); err != nil {
 				log.Error().Err(err).Msgf("Error constructing outbound TCP filter chain for traffic match %s on proxy with identity %s", trafficMatch.Name, lb.serviceIdentity)
@@ -415,6 +440,21 @@ func (lb *listenerBuilder) getOutboundFilterChainPerUpstream() []*xds_listener.F
 				filterChains = append(filterChains, tcpFilterChain)
 			}
 
+		case constants.ProtocolAuto:
+			// Construct an HTTP filter chain matching the traffic detected as HTTP by the HttpInspector ListenerFilter,
+			// and a TCP filter chain matching the remaining traffic
+			if httpFilterChain, err := lb.getOutboundHTTPFilterChainForService(*trafficMatch); err != nil {
+				log.Error().Err(err).Msgf("Error constructing outbound HTTP filter chain for traffic match %s on proxy with identity %s", trafficMatch.Name, lb.serviceIdentity)
+			} else {
+				httpFilterChain.FilterChainMatch.ApplicationProtocols = httpApplicationProtocols
+				filterChains = append(filterChains, httpFilterChain)
+			}
+			if tcpFilterChain, err := lb.getOutboundTCPFilterChainForService(*trafficMatch); err != nil {
+				log.Error().Err(err).Msgf("Error constructing outbound TCP filter chain for traffic match %s on proxy with identity %s", trafficMatch.Name, lb.serviceIdentity)
+			} else {
+				filterChains = append(filterChains, tcpFilterChain)
+			}
+
 		default:
 			log.Error().Msgf("Cannot build outbound filter chain, unsupported protocol %s for traffic match %s", trafficMatch.DestinationProtocol, trafficMatch.Name)
 		}
@@ -2,21 +2,25 @@ package lds
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/ptypes"
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/auth"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -570,6 +574,49 @@ func TestGetOutboundTCPFilter(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "TCP filter for upstream with source IP hash policy",
+			trafficMatch: trafficpolicy.TrafficMatch{
+				Name: "test",
+				WeightedClusters: []service.WeightedCluster{
+					{
+						ClusterName: "bar/foo_14001",
+						Weight:      100,
+					},
+				},
+				HashPolicy: &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeySourceIP},
+			},
+			expectedTCPProxyConfig: &xds_tcp_proxy.TcpProxy{
+				StatPrefix:       "outbound-mesh-tcp-proxy_test",
+				ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: "bar/foo_14001"},
+				HashPolicy: []*xds_type.HashPolicy{
+					{
+						PolicySpecifier: &xds_type.HashPolicy_SourceIp_{
+							SourceIp: &xds_type.HashPolicy_SourceIp{},
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "TCP filter for upstream ignores header hash policy",
+			trafficMatch: trafficpolicy.TrafficMatch{
+				Name: "test",
+				WeightedClusters: []service.WeightedCluster{
+					{
+						ClusterName: "bar/foo_14001",
+						Weight:      100,
+					},
+				},
+				HashPolicy: &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeyHeader, Name: "x-user-id"},
+			},
+			expectedTCPProxyConfig: &xds_tcp_proxy.TcpProxy{
+				StatPrefix:       "outbound-mesh-tcp-proxy_test",
+				ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: "bar/foo_14001"},
+			},
+			expectError: false,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -591,6 +638,11 @@ func TestGetOutboundTCPFilter(t *testing.T) {
 			assert.Equal(tc.expectedTCPProxyConfig.ClusterSpecifier, actualConfig.ClusterSpecifier)
 
 			assert.Equal(tc.expectedTCPProxyConfig.StatPrefix, actualConfig.StatPrefix)
+
+			assert.Equal(len(tc.expectedTCPProxyConfig.HashPolicy), len(actualConfig.HashPolicy))
+			for i := range tc.expectedTCPProxyConfig.HashPolicy {
+				assert.True(proto.Equal(tc.expectedTCPProxyConfig.HashPolicy[i], actualConfig.HashPolicy[i]))
+			}
 		})
 	}
 }
@@ -614,7 +666,62 @@ func TestGetOutboundHTTPFilter(t *testing.T) {
 		EnableWASMStats: false,
 	}).AnyTimes()
 
-	filter, err := lb.getOutboundHTTPFilter(route.OutboundRouteConfigName)
+	filter, err := lb.getOutboundHTTPFilter(route.OutboundRouteConfigName, false, "")
 	assert.NoError(err)
 	assert.Equal(filter.Name, wellknown.HTTPConnectionManager)
 }
+
+func TestGetInboundMeshFilterChainsForAdminPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	lb := &listenerBuilder{
+		serviceIdentity: tests.BookbuyerServiceIdentity,
+	}
+
+	proxyService := tests.BookbuyerService
+	proxyService.TargetPort = constants.EnvoyAdminPort
+
+	assert.Nil(lb.getInboundMeshFilterChains(proxyService))
+}
+
+func TestGetInboundMeshFilterChainsForDetectedProtocol(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetTracingEndpoint().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{}).AnyTimes()
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
+
+	lb := &listenerBuilder{
+		meshCatalog:     mockCatalog,
+		cfg:             mockConfigurator,
+		serviceIdentity: tests.BookbuyerServiceIdentity,
+	}
+
+	proxyService := tests.BookbuyerService
+	proxyService.Protocol = constants.ProtocolAuto
+
+	testCases := []struct {
+		inboundProtocol     string
+		expectedChainPrefix string
+	}{
+		{inboundProtocol: constants.ProtocolTCP, expectedChainPrefix: inboundMeshTCPFilterChainPrefix},
+		{inboundProtocol: constants.ProtocolHTTP, expectedChainPrefix: inboundMeshHTTPFilterChainPrefix},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.inboundProtocol, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			// The protocol of the port whose protocol is detected by the downstream is resolved for the inbound traffic
+			mockCatalog.EXPECT().GetInboundServiceProtocol(tests.BookbuyerServiceIdentity, proxyService).Return(tc.inboundProtocol).Times(1)
+
+			filterChains := lb.getInboundMeshFilterChains(proxyService)
+			assert.Len(filterChains, 1)
+			assert.True(strings.HasPrefix(filterChains[0].Name, tc.expectedChainPrefix), filterChains[0].Name)
+		})
+	}
+}
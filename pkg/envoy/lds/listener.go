@@ -28,11 +28,15 @@ const (
 )
 
 func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error) {
-	serviceFilterChains := lb.getOutboundFilterChainPerUpstream()
+	var meshTrafficMatches []*trafficpolicy.TrafficMatch
+	if outboundMeshTrafficPolicy := lb.meshCatalog.GetOutboundMeshTrafficPolicy(lb.serviceIdentity); outboundMeshTrafficPolicy != nil {
+		meshTrafficMatches = outboundMeshTrafficPolicy.TrafficMatches
+	}
+	serviceFilterChains := lb.getOutboundFilterChainPerUpstream(meshTrafficMatches)
 
 	listener := &xds_listener.Listener{
 		Name:             outboundListenerName,
-		Address:          envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyOutboundListenerPort),
+		Address:          envoy.GetWildcardAddress(constants.EnvoyOutboundListenerPort, lb.cfg.GetFeatureFlags().EnableIPv6),
 		TrafficDirection: xds_core.TrafficDirection_OUTBOUND,
 		FilterChains:     serviceFilterChains,
 		ListenerFilters: []*xds_listener.ListenerFilter{
@@ -48,7 +52,8 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 	// This filter chain matches any traffic not matching any of the filter chains built from
 	// mesh (SMI or permissive mode) or egress traffic policies. Traffic matching this default
 	// passthrough filter chain will be allowed to passthrough to its original destination.
-	if lb.cfg.IsEgressEnabled() {
+	egressEnabled := lb.cfg.IsEgressEnabled()
+	if egressEnabled {
 		egressFilterChain, err := getDefaultPassthroughFilterChain()
 		if err != nil {
 			log.Error().Err(err).Msgf("Error getting filter chain for Egress")
@@ -57,24 +62,33 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 		listener.DefaultFilterChain = egressFilterChain
 	}
 
-	if featureflags := lb.cfg.GetFeatureFlags(); featureflags.EnableEgressPolicy {
+	// When global egress is disabled, the egress traffic not matching any filter chain can be denied with feedback
+	// instead of being dropped silently. This requires the HttpInspector ListenerFilter to detect HTTP traffic.
+	enableEgressDenyFeedback := !egressEnabled && lb.cfg.GetEgressDenyFeedback().Enable
+
+	// The protocol of the upstream services that do not declare it is detected by the HttpInspector ListenerFilter
+	enableProtocolSniffing := hasProtocol(meshTrafficMatches, constants.ProtocolAuto)
+
+	if featureflags := lb.cfg.GetFeatureFlags(); featureflags.EnableEgressPolicy || enableEgressDenyFeedback || enableProtocolSniffing {
 		var trafficMatches []*trafficpolicy.TrafficMatch
 		var filterDisableMatchPredicate *xds_listener.ListenerFilterChainMatchPredicate
 		// Create filter chains for egress based on policies
-		if egressTrafficPolicy, err := lb.meshCatalog.GetEgressTrafficPolicy(lb.serviceIdentity); err != nil {
-			log.Error().Err(err).Msgf("Error retrieving egress policies for proxy with identity %s, skipping egress filters", lb.serviceIdentity)
-		} else if egressTrafficPolicy != nil {
-			egressFilterChains := lb.getEgressFilterChainsForMatches(egressTrafficPolicy.TrafficMatches)
-			listener.FilterChains = append(listener.FilterChains, egressFilterChains...)
-			trafficMatches = append(trafficMatches, egressTrafficPolicy.TrafficMatches...)
+		if featureflags.EnableEgressPolicy {
+			if egressTrafficPolicy, err := lb.meshCatalog.GetEgressTrafficPolicy(lb.serviceIdentity); err != nil {
+				log.Error().Err(err).Msgf("Error retrieving egress policies for proxy with identity %s, skipping egress filters", lb.serviceIdentity)
+			} else if egressTrafficPolicy != nil {
+				egressFilterChains := lb.getEgressFilterChainsForMatches(egressTrafficPolicy.TrafficMatches, getDynamicForwardProxyPorts(egressTrafficPolicy.ClustersConfigs))
+				listener.FilterChains = append(listener.FilterChains, egressFilterChains...)
+				trafficMatches = append(trafficMatches, egressTrafficPolicy.TrafficMatches...)
+			}
 		}
-		trafficMatches = append(trafficMatches, lb.meshCatalog.GetOutboundMeshTrafficPolicy(lb.serviceIdentity).TrafficMatches...)
+		trafficMatches = append(trafficMatches, meshTrafficMatches...)
 		filterDisableMatchPredicate = getFilterMatchPredicateForTrafficMatches(trafficMatches)
 		additionalListenerFilters := []*xds_listener.ListenerFilter{
 			// Configure match predicate for ports serving server-first protocols (ex. mySQL, postgreSQL etc.).
 			// Ports corresponding to server-first protocols, where the server initiates the first byte of a connection, will
 			// cause the HttpInspector ListenerFilter to timeout because it waits for data from the client to inspect the protocol.
-			// Such ports will set the protocol to 'tcp-server-first' in an Egress policy.
+			// Such ports will set the protocol to 'tcp-server-first' in an Egress policy, or to a server-first protocol such as 'mysql'.
 			// The 'FilterDisabled' field configures the match predicate.
 			{
 				// To inspect TLS metadata, such as the transport protocol and SNI
@@ -90,10 +104,22 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 		listener.ListenerFilters = append(listener.ListenerFilters, additionalListenerFilters...)
 
 		// ListenerFilter can timeout for server-first protocols. In such cases, continue the processing of the connection
-		// and fallback to the default filter chain.
+		// and fallback to the default filter chain. The timeout is kept short for the connections to the ports of
+		// server-first protocols that do not declare their protocol not to stall.
+		listener.ListenerFiltersTimeout = ptypes.DurationProto(lb.cfg.GetProtocolDetectionTimeout())
 		listener.ContinueOnListenerFiltersTimeout = true
 	}
 
+	if enableEgressDenyFeedback {
+		deniedFilterChains, deniedDefaultFilterChain, err := lb.getEgressDeniedFilterChains(listener.FilterChains)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error getting filter chains for denied egress")
+			return nil, err
+		}
+		listener.FilterChains = append(listener.FilterChains, deniedFilterChains...)
+		listener.DefaultFilterChain = deniedDefaultFilterChain
+	}
+
 	if len(listener.FilterChains) == 0 && listener.DefaultFilterChain == nil {
 		// Programming a listener with no filter chains is an error.
 		// It is possible for the outbound listener to have no filter chains if
@@ -105,10 +131,10 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 	return listener, nil
 }
 
-func newInboundListener() *xds_listener.Listener {
+func newInboundListener(enableIPv6 bool) *xds_listener.Listener {
 	return &xds_listener.Listener{
 		Name:             inboundListenerName,
-		Address:          envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyInboundListenerPort),
+		Address:          envoy.GetWildcardAddress(constants.EnvoyInboundListenerPort, enableIPv6),
 		TrafficDirection: xds_core.TrafficDirection_INBOUND,
 		FilterChains:     []*xds_listener.FilterChain{},
 		ListenerFilters: []*xds_listener.ListenerFilter{
@@ -125,7 +151,7 @@ func newInboundListener() *xds_listener.Listener {
 	}
 }
 
-func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager) (*xds_listener.Listener, error) {
+func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager, enableIPv6 bool) (*xds_listener.Listener, error) {
 	marshalledConnManager, err := ptypes.MarshalAny(connManager)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
@@ -136,7 +162,7 @@ func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager) (*xds_l
 	return &xds_listener.Listener{
 		Name:             prometheusListenerName,
 		TrafficDirection: xds_core.TrafficDirection_INBOUND,
-		Address:          envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyPrometheusInboundListenerPort),
+		Address:          envoy.GetWildcardAddress(constants.EnvoyPrometheusInboundListenerPort, enableIPv6),
 		FilterChains: []*xds_listener.FilterChain{
 			{
 				Filters: []*xds_listener.Filter{
@@ -185,7 +211,7 @@ func getFilterMatchPredicateForTrafficMatches(matches []*trafficpolicy.TrafficMa
 
 	for _, match := range matches {
 		// Only configure match predicate for server first protocol
-		if match.DestinationProtocol != constants.ProtocolTCPServerFirst {
+		if !isServerFirstProtocol(match.DestinationProtocol) {
 			continue
 		}
 
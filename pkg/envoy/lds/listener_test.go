@@ -1,13 +1,16 @@
 package lds
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	tassert "github.com/stretchr/testify/assert"
@@ -40,18 +43,25 @@ var _ = Describe("Construct inbound listeners", func() {
 
 	Context("Test creation of inbound listener", func() {
 		It("Tests the inbound listener config", func() {
-			listener := newInboundListener()
+			listener := newInboundListener(false)
 			Expect(listener.Address).To(Equal(envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyInboundListenerPort)))
 			Expect(len(listener.ListenerFilters)).To(Equal(2)) // TlsInspector, OriginalDestination listener filter
 			Expect(listener.ListenerFilters[0].Name).To(Equal(wellknown.TlsInspector))
 			Expect(listener.TrafficDirection).To(Equal(xds_core.TrafficDirection_INBOUND))
 		})
+
+		It("Tests the inbound listener config with IPv6 enabled", func() {
+			listener := newInboundListener(true)
+			Expect(listener.Address.GetSocketAddress().Address).To(Equal(constants.WildcardIPv6Addr))
+			Expect(listener.Address.GetSocketAddress().Ipv4Compat).To(BeTrue())
+			Expect(listener.Address.GetSocketAddress().GetPortValue()).To(Equal(uint32(constants.EnvoyInboundListenerPort)))
+		})
 	})
 
 	Context("Test creation of Prometheus listener", func() {
 		It("Tests the Prometheus listener config", func() {
 			connManager := getPrometheusConnectionManager()
-			listener, _ := buildPrometheusListener(connManager)
+			listener, _ := buildPrometheusListener(connManager, false)
 			Expect(listener.Address).To(Equal(envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyPrometheusInboundListenerPort)))
 			Expect(len(listener.ListenerFilters)).To(Equal(0)) //  no listener filters
 			Expect(listener.TrafficDirection).To(Equal(xds_core.TrafficDirection_INBOUND))
@@ -159,6 +169,27 @@ func TestGetFilterMatchPredicateForTrafficMatches(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "MySQL port present",
+			matches: []*trafficpolicy.TrafficMatch{
+				{
+					DestinationProtocol: "postgres",
+					DestinationPort:     5432,
+				},
+				{
+					DestinationProtocol: "mysql",
+					DestinationPort:     3306,
+				},
+			},
+			expectedMatch: &xds_listener.ListenerFilterChainMatchPredicate{
+				Rule: &xds_listener.ListenerFilterChainMatchPredicate_DestinationPortRange{
+					DestinationPortRange: &xds_type.Int32Range{
+						Start: 3306, // Start is inclusive
+						End:   3307, // End is exclusive
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -187,12 +218,14 @@ func TestNewOutboundListener(t *testing.T) {
 				DestinationProtocol: constants.ProtocolTCPServerFirst,
 			},
 		},
-	}).Times(2)
+	}).Times(1)
 	cfg := configurator.NewMockConfigurator(mockCtrl)
 	cfg.EXPECT().IsEgressEnabled().Return(false).Times(1)
+	cfg.EXPECT().GetEgressDenyFeedback().Return(configv1alpha1.EgressDenyFeedbackSpec{}).Times(1)
 	cfg.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{
 		EnableEgressPolicy: true,
-	}).Times(1)
+	}).Times(2)
+	cfg.EXPECT().GetProtocolDetectionTimeout().Return(time.Second).Times(1)
 
 	lb := newListenerBuilder(meshCatalog, identity, cfg, nil)
 
@@ -213,3 +246,79 @@ func TestNewOutboundListener(t *testing.T) {
 	assert.Equal(wellknown.HttpInspector, listener.ListenerFilters[2].Name)
 	assert.Equal(listener.ListenerFilters[1].FilterDisabled, listener.ListenerFilters[2].FilterDisabled)
 }
+
+func TestNewOutboundListenerProtocolSniffing(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	identity := identity.K8sServiceAccount{}.ToServiceIdentity()
+	meshCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	meshCatalog.EXPECT().GetOutboundMeshTrafficPolicy(identity).Return(&trafficpolicy.OutboundMeshTrafficPolicy{
+		TrafficMatches: []*trafficpolicy.TrafficMatch{
+			{
+				Name:                "ns/s1_80_auto",
+				WeightedClusters:    []service.WeightedCluster{{ClusterName: "ns/s1|80", Weight: 100}},
+				DestinationIPRanges: []string{"10.0.0.1/32"},
+				DestinationPort:     80,
+				DestinationProtocol: constants.ProtocolAuto,
+			},
+		},
+	}).Times(1)
+	cfg := configurator.NewMockConfigurator(mockCtrl)
+	cfg.EXPECT().IsEgressEnabled().Return(false).Times(1)
+	cfg.EXPECT().GetEgressDenyFeedback().Return(configv1alpha1.EgressDenyFeedbackSpec{}).Times(1)
+	cfg.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
+	cfg.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	cfg.EXPECT().GetTracingEndpoint().Return("").AnyTimes()
+	cfg.EXPECT().GetProtocolDetectionTimeout().Return(100 * time.Millisecond).Times(1)
+
+	lb := newListenerBuilder(meshCatalog, identity, cfg, nil)
+
+	listener, err := lb.newOutboundListener()
+	assert.NoError(err)
+	assert.NotNil(listener)
+
+	// The connections to the port without a declared protocol that are not detected as HTTP within the
+	// configured timeout are matched by the TCP filter chain
+	assert.Equal(ptypes.DurationProto(100*time.Millisecond), listener.ListenerFiltersTimeout)
+	assert.True(listener.ContinueOnListenerFiltersTimeout)
+
+	// Egress policies are disabled, but the HTTP traffic to the port without a declared protocol must be detected
+	assert.Len(listener.ListenerFilters, 3) // OriginalDst, TlsInspector, HttpInspector
+	assert.Equal(wellknown.HttpInspector, listener.ListenerFilters[2].Name)
+	assert.Nil(listener.ListenerFilters[2].FilterDisabled)
+
+	// The traffic detected as HTTP is matched by the HTTP filter chain, and the remaining traffic by the TCP filter chain
+	assert.Len(listener.FilterChains, 2)
+	assert.Equal(fmt.Sprintf("%s:ns/s1_80_auto", outboundMeshHTTPFilterChainPrefix), listener.FilterChains[0].Name)
+	assert.Equal(httpApplicationProtocols, listener.FilterChains[0].FilterChainMatch.ApplicationProtocols)
+	assert.Equal(fmt.Sprintf("%s:ns/s1_80_auto", outboundMeshTCPFilterChainPrefix), listener.FilterChains[1].Name)
+	assert.Empty(listener.FilterChains[1].FilterChainMatch.ApplicationProtocols)
+}
+
+func TestNewOutboundListenerEgressDenyFeedback(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	identity := identity.K8sServiceAccount{}.ToServiceIdentity()
+	meshCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	meshCatalog.EXPECT().GetOutboundMeshTrafficPolicy(identity).Return(&trafficpolicy.OutboundMeshTrafficPolicy{}).Times(1)
+	cfg := configurator.NewMockConfigurator(mockCtrl)
+	cfg.EXPECT().IsEgressEnabled().Return(false).Times(1)
+	cfg.EXPECT().GetEgressDenyFeedback().Return(configv1alpha1.EgressDenyFeedbackSpec{Enable: true, HTTPStatusCode: 403}).Times(2)
+	cfg.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).Times(2)
+	cfg.EXPECT().GetProtocolDetectionTimeout().Return(time.Second).Times(1)
+
+	lb := newListenerBuilder(meshCatalog, identity, cfg, nil)
+
+	listener, err := lb.newOutboundListener()
+	assert.NoError(err)
+	assert.NotNil(listener)
+
+	// Egress policies are disabled, but HTTP traffic must still be detected
+	assert.Len(listener.ListenerFilters, 3) // OriginalDst, TlsInspector, HttpInspector
+	assert.Equal(wellknown.HttpInspector, listener.ListenerFilters[2].Name)
+	assert.True(listener.ContinueOnListenerFiltersTimeout)
+
+	assert.Len(listener.FilterChains, 1)
+	assert.Equal(egressDeniedHTTPFilterChainName, listener.FilterChains[0].Name)
+	assert.Equal(egressDeniedTCPFilterChainName, listener.DefaultFilterChain.Name)
+}
@@ -0,0 +1,127 @@
+package lds
+
+import (
+	"fmt"
+	"time"
+
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_mongo_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/mongo_proxy/v3"
+	xds_mysql_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/mysql_proxy/v3"
+	xds_postgres_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/postgres_proxy/v3alpha"
+	xds_redis_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/redis_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// postgresProxyFilterName is the name of the PostgreSQL proxy network filter, not defined in wellknown
+	postgresProxyFilterName = "envoy.filters.network.postgres_proxy"
+
+	// outboundMeshProtocolStatPrefix is the stat prefix of the protocol specific network filters, followed by the protocol
+	outboundMeshProtocolStatPrefix = "outbound-mesh"
+
+	// redisOpTimeout is the timeout of each Redis command proxied by the Redis proxy network filter
+	redisOpTimeout = 5 * time.Second
+)
+
+// httpApplicationProtocols are the application protocols set by the HttpInspector ListenerFilter on plaintext HTTP traffic
+var httpApplicationProtocols = []string{"http/1.0", "http/1.1", "h2c"}
+
+// isServerFirstProtocol returns a boolean indicating if the server initiates the first byte of the connections of the
+// given protocol, in which case the traffic cannot be inspected by ListenerFilters waiting for data from the client
+func isServerFirstProtocol(protocol string) bool {
+	return protocol == constants.ProtocolTCPServerFirst || protocol == constants.ProtocolMySQL
+}
+
+// hasProtocol returns a boolean indicating if any of the given traffic matches has the given destination protocol
+func hasProtocol(trafficMatches []*trafficpolicy.TrafficMatch, protocol string) bool {
+	for _, trafficMatch := range trafficMatches {
+		if trafficMatch.DestinationProtocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// getOutboundTCPFilters returns the network filters used to filter outbound TCP traffic for the given traffic match.
+// The traffic of the database protocols is decoded by the matching Envoy network filter before the TCP proxy filter
+// to emit protocol level stats. The Redis traffic of the upstream services opting into key based sharding is proxied
+// by the Redis proxy filter, which routes each command to an endpoint of the upstream service based on its key, unless
+// the upstream service is split across multiple clusters. The other Redis traffic is proxied as TCP.
+func (lb *listenerBuilder) getOutboundTCPFilters(trafficMatch trafficpolicy.TrafficMatch) ([]*xds_listener.Filter, error) {
+	var filters []*xds_listener.Filter
+	statPrefix := fmt.Sprintf("%s-%s_%s", outboundMeshProtocolStatPrefix, trafficMatch.DestinationProtocol, trafficMatch.Name)
+
+	var protocolFilter *xds_listener.Filter
+	var err error
+	switch trafficMatch.DestinationProtocol {
+	case constants.ProtocolRedis:
+		if !trafficMatch.RedisSharding {
+			break
+		}
+		if len(trafficMatch.WeightedClusters) == 1 {
+			filter, err := getRedisProxyFilter(statPrefix, trafficMatch.WeightedClusters[0].ClusterName.String())
+			if err != nil {
+				return nil, err
+			}
+			return []*xds_listener.Filter{filter}, nil
+		}
+		log.Debug().Msgf("Redis traffic match %s has %d weighted clusters, proxying it as TCP", trafficMatch.Name, len(trafficMatch.WeightedClusters))
+
+	case constants.ProtocolMySQL:
+		protocolFilter, err = getNetworkFilter(wellknown.MySQLProxy, &xds_mysql_proxy.MySQLProxy{StatPrefix: statPrefix})
+
+	case constants.ProtocolPostgres:
+		protocolFilter, err = getNetworkFilter(postgresProxyFilterName, &xds_postgres_proxy.PostgresProxy{StatPrefix: statPrefix})
+
+	case constants.ProtocolMongo:
+		protocolFilter, err = getNetworkFilter(wellknown.MongoProxy, &xds_mongo_proxy.MongoProxy{StatPrefix: statPrefix})
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error building %s network filter for traffic match %s", trafficMatch.DestinationProtocol, trafficMatch.Name)
+	}
+	if protocolFilter != nil {
+		filters = append(filters, protocolFilter)
+	}
+
+	tcpProxyFilter, err := lb.getOutboundTCPFilter(trafficMatch)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, tcpProxyFilter)
+
+	return filters, nil
+}
+
+// getRedisProxyFilter returns a Redis proxy network filter routing the commands to the given cluster
+func getRedisProxyFilter(statPrefix string, cluster string) (*xds_listener.Filter, error) {
+	redisProxy := &xds_redis_proxy.RedisProxy{
+		StatPrefix: statPrefix,
+		Settings: &xds_redis_proxy.RedisProxy_ConnPoolSettings{
+			OpTimeout: ptypes.DurationProto(redisOpTimeout),
+		},
+		PrefixRoutes: &xds_redis_proxy.RedisProxy_PrefixRoutes{
+			CatchAllRoute: &xds_redis_proxy.RedisProxy_PrefixRoutes_Route{
+				Cluster: cluster,
+			},
+		},
+	}
+	return getNetworkFilter(wellknown.RedisProxy, redisProxy)
+}
+
+// getNetworkFilter returns the network filter with the given name and config
+func getNetworkFilter(name string, config proto.Message) (*xds_listener.Filter, error) {
+	marshalledConfig, err := ptypes.MarshalAny(config)
+	if err != nil {
+		return nil, err
+	}
+	return &xds_listener.Filter{
+		Name:       name,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledConfig},
+	}, nil
+}
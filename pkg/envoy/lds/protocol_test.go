@@ -0,0 +1,140 @@
+package lds
+
+import (
+	"testing"
+
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_mongo_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/mongo_proxy/v3"
+	xds_mysql_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/mysql_proxy/v3"
+	xds_postgres_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/postgres_proxy/v3alpha"
+	xds_redis_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/redis_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetOutboundTCPFilters(t *testing.T) {
+	singleCluster := []service.WeightedCluster{{ClusterName: "ns/db|6379", Weight: 100}}
+	splitClusters := []service.WeightedCluster{
+		{ClusterName: "ns/db-v1|6379", Weight: 50},
+		{ClusterName: "ns/db-v2|6379", Weight: 50},
+	}
+
+	testCases := []struct {
+		name                string
+		protocol            string
+		redisSharding       bool
+		weightedClusters    []service.WeightedCluster
+		expectedFilterNames []string
+	}{
+		{
+			name:                "TCP",
+			protocol:            constants.ProtocolTCP,
+			weightedClusters:    singleCluster,
+			expectedFilterNames: []string{wellknown.TCPProxy},
+		},
+		{
+			name:                "MySQL",
+			protocol:            constants.ProtocolMySQL,
+			weightedClusters:    singleCluster,
+			expectedFilterNames: []string{wellknown.MySQLProxy, wellknown.TCPProxy},
+		},
+		{
+			name:                "PostgreSQL",
+			protocol:            constants.ProtocolPostgres,
+			weightedClusters:    singleCluster,
+			expectedFilterNames: []string{postgresProxyFilterName, wellknown.TCPProxy},
+		},
+		{
+			name:                "MongoDB",
+			protocol:            constants.ProtocolMongo,
+			weightedClusters:    singleCluster,
+			expectedFilterNames: []string{wellknown.MongoProxy, wellknown.TCPProxy},
+		},
+		{
+			name:                "Redis",
+			protocol:            constants.ProtocolRedis,
+			weightedClusters:    singleCluster,
+			expectedFilterNames: []string{wellknown.TCPProxy},
+		},
+		{
+			name:                "Redis with sharding",
+			protocol:            constants.ProtocolRedis,
+			redisSharding:       true,
+			weightedClusters:    singleCluster,
+			expectedFilterNames: []string{wellknown.RedisProxy},
+		},
+		{
+			name:                "Redis with sharding split across multiple clusters",
+			protocol:            constants.ProtocolRedis,
+			redisSharding:       true,
+			weightedClusters:    splitClusters,
+			expectedFilterNames: []string{wellknown.TCPProxy},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+
+			lb := newListenerBuilder(catalog.NewMockMeshCataloger(mockCtrl), tests.BookbuyerServiceIdentity, configurator.NewMockConfigurator(mockCtrl), nil)
+			filters, err := lb.getOutboundTCPFilters(trafficpolicy.TrafficMatch{
+				Name:                "db",
+				DestinationProtocol: tc.protocol,
+				RedisSharding:       tc.redisSharding,
+				WeightedClusters:    tc.weightedClusters,
+			})
+			assert.NoError(err)
+
+			var filterNames []string
+			for _, filter := range filters {
+				filterNames = append(filterNames, filter.Name)
+			}
+			assert.Equal(tc.expectedFilterNames, filterNames)
+		})
+	}
+}
+
+func TestGetOutboundTCPFiltersConfig(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+
+	lb := newListenerBuilder(catalog.NewMockMeshCataloger(mockCtrl), tests.BookbuyerServiceIdentity, configurator.NewMockConfigurator(mockCtrl), nil)
+	getFilter := func(protocol string) *xds_listener.Filter {
+		filters, err := lb.getOutboundTCPFilters(trafficpolicy.TrafficMatch{
+			Name:                "db",
+			DestinationProtocol: protocol,
+			RedisSharding:       true,
+			WeightedClusters:    []service.WeightedCluster{{ClusterName: "ns/db|5000", Weight: 100}},
+		})
+		assert.NoError(err)
+		return filters[0]
+	}
+
+	mysqlProxy := &xds_mysql_proxy.MySQLProxy{}
+	assert.NoError(ptypes.UnmarshalAny(getFilter(constants.ProtocolMySQL).GetTypedConfig(), mysqlProxy))
+	assert.Equal("outbound-mesh-mysql_db", mysqlProxy.StatPrefix)
+
+	postgresProxy := &xds_postgres_proxy.PostgresProxy{}
+	assert.NoError(ptypes.UnmarshalAny(getFilter(constants.ProtocolPostgres).GetTypedConfig(), postgresProxy))
+	assert.Equal("outbound-mesh-postgres_db", postgresProxy.StatPrefix)
+
+	mongoProxy := &xds_mongo_proxy.MongoProxy{}
+	assert.NoError(ptypes.UnmarshalAny(getFilter(constants.ProtocolMongo).GetTypedConfig(), mongoProxy))
+	assert.Equal("outbound-mesh-mongo_db", mongoProxy.StatPrefix)
+
+	redisProxy := &xds_redis_proxy.RedisProxy{}
+	assert.NoError(ptypes.UnmarshalAny(getFilter(constants.ProtocolRedis).GetTypedConfig(), redisProxy))
+	assert.Equal("outbound-mesh-redis_db", redisProxy.StatPrefix)
+	assert.Equal("ns/db|5000", redisProxy.PrefixRoutes.CatchAllRoute.Cluster)
+	assert.NoError(redisProxy.Validate())
+}
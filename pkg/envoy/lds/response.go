@@ -47,6 +47,25 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		return ldsResources, nil
 	}
 
+	if proxy.Kind() == envoy.KindEgressGateway {
+		egressGatewayListener, err := lb.buildEgressGatewayListener()
+		if err != nil {
+			log.Error().Err(err).Str("proxy", proxy.String()).Msgf("Error building egress gateway listener")
+			return ldsResources, err
+		}
+		if egressGatewayListener != nil {
+			ldsResources = append(ldsResources, egressGatewayListener)
+		}
+		return ldsResources, nil
+	}
+
+	if proxy.Kind() == envoy.KindIngressGateway {
+		for _, ingressGatewayListener := range lb.buildIngressGatewayListeners() {
+			ldsResources = append(ldsResources, ingressGatewayListener)
+		}
+		return ldsResources, nil
+	}
+
 	// --- OUTBOUND -------------------
 	outboundListener, err := lb.newOutboundListener()
 	if err != nil {
@@ -62,7 +81,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 	}
 
 	// --- INBOUND -------------------
-	inboundListener := newInboundListener()
+	inboundListener := newInboundListener(cfg.GetFeatureFlags().EnableIPv6)
 
 	svcList, err := proxyRegistry.ListProxyServices(proxy)
 	if err != nil {
@@ -92,7 +111,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 	} else if k8s.IsMetricsEnabled(pod) {
 		// Build Prometheus listener config
 		prometheusConnManager := getPrometheusConnectionManager()
-		if prometheusListener, err := buildPrometheusListener(prometheusConnManager); err != nil {
+		if prometheusListener, err := buildPrometheusListener(prometheusConnManager, cfg.GetFeatureFlags().EnableIPv6); err != nil {
 			log.Error().Err(err).Str("proxy", proxy.String()).Msgf("Error building Prometheus listener")
 		} else {
 			ldsResources = append(ldsResources, prometheusListener)
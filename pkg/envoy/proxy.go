@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	mapset "github.com/deckarep/golang-set"
@@ -31,8 +32,11 @@ type Proxy struct {
 	// The time this Proxy connected to the OSM control plane
 	connectedAt time.Time
 
+	// versionsMutex guards the sent and applied versions, which are read outside of the proxy's xDS stream
+	versionsMutex      sync.RWMutex
 	lastSentVersion    map[TypeURI]uint64
 	lastAppliedVersion map[TypeURI]uint64
+	lastSentAt         time.Time
 	lastNonce          map[TypeURI]string
 
 	// Contains the last resource names sent for a given proxy and TypeURL
@@ -120,30 +124,60 @@ func (p *Proxy) StatsHeaders() map[string]string {
 
 // SetLastAppliedVersion records the version of the given Envoy proxy that was last acknowledged.
 func (p *Proxy) SetLastAppliedVersion(typeURI TypeURI, version uint64) {
+	p.versionsMutex.Lock()
+	defer p.versionsMutex.Unlock()
 	p.lastAppliedVersion[typeURI] = version
 }
 
 // GetLastAppliedVersion returns the last version successfully applied to the given Envoy proxy.
 func (p *Proxy) GetLastAppliedVersion(typeURI TypeURI) uint64 {
+	p.versionsMutex.RLock()
+	defer p.versionsMutex.RUnlock()
 	return p.lastAppliedVersion[typeURI]
 }
 
 // GetLastSentVersion returns the last sent version.
 func (p *Proxy) GetLastSentVersion(typeURI TypeURI) uint64 {
+	p.versionsMutex.RLock()
+	defer p.versionsMutex.RUnlock()
 	return p.lastSentVersion[typeURI]
 }
 
-// IncrementLastSentVersion increments last sent version.
+// IncrementLastSentVersion increments last sent version, and records the time the version was sent.
 func (p *Proxy) IncrementLastSentVersion(typeURI TypeURI) uint64 {
+	p.versionsMutex.Lock()
+	defer p.versionsMutex.Unlock()
 	p.lastSentVersion[typeURI]++
-	return p.GetLastSentVersion(typeURI)
+	p.lastSentAt = time.Now()
+	return p.lastSentVersion[typeURI]
 }
 
 // SetLastSentVersion records the version of the given config last sent to the proxy.
 func (p *Proxy) SetLastSentVersion(typeURI TypeURI, ver uint64) {
+	p.versionsMutex.Lock()
+	defer p.versionsMutex.Unlock()
 	p.lastSentVersion[typeURI] = ver
 }
 
+// GetLastSentTime returns the time a version of any config was last sent to the proxy.
+func (p *Proxy) GetLastSentTime() time.Time {
+	p.versionsMutex.RLock()
+	defer p.versionsMutex.RUnlock()
+	return p.lastSentAt
+}
+
+// IsConfigAcknowledged returns true if the proxy has acknowledged the last version sent for every config type.
+func (p *Proxy) IsConfigAcknowledged() bool {
+	p.versionsMutex.RLock()
+	defer p.versionsMutex.RUnlock()
+	for typeURI, sentVersion := range p.lastSentVersion {
+		if p.lastAppliedVersion[typeURI] < sentVersion {
+			return false
+		}
+	}
+	return true
+}
+
 // GetLastSentNonce returns last sent nonce.
 func (p *Proxy) GetLastSentNonce(typeURI TypeURI) string {
 	nonce, ok := p.lastNonce[typeURI]
@@ -193,6 +227,16 @@ func (p *Proxy) GetIP() net.Addr {
 	return p.Addr
 }
 
+// GetLocalhostIPAddress returns the loopback IP address of the primary IP family of the pod of the proxy, which is
+// the IP family of the address the proxy connected to xDS from
+func (p *Proxy) GetLocalhostIPAddress() string {
+	var ip net.IP
+	if tcpAddr, ok := p.Addr.(*net.TCPAddr); ok {
+		ip = tcpAddr.IP
+	}
+	return utils.GetLocalhostIPAddress(ip)
+}
+
 // GetLastResourcesSent returns a set of resources last sent for a proxy givne a TypeURL
 // If none were sent, empty set is returned
 func (p *Proxy) GetLastResourcesSent(typeURI TypeURI) mapset.Set {
@@ -88,6 +88,21 @@ var _ = Describe("Test proxy methods", func() {
 			proxy.IncrementLastSentVersion(TypeCDS)
 			actual = proxy.GetLastSentVersion(TypeCDS)
 			Expect(actual).To(Equal(newVersion + 1))
+			Expect(proxy.GetLastSentTime().IsZero()).To(BeFalse())
+		})
+	})
+
+	Context("test IsConfigAcknowledged()", func() {
+		It("returns whether the last sent versions have been applied", func() {
+			p, err := NewProxy(certCommonName, certSerialNumber, tests.NewMockAddress("1.2.3.4"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p.IsConfigAcknowledged()).To(BeTrue())
+
+			version := p.IncrementLastSentVersion(TypeLDS)
+			Expect(p.IsConfigAcknowledged()).To(BeFalse())
+
+			p.SetLastAppliedVersion(TypeLDS, version)
+			Expect(p.IsConfigAcknowledged()).To(BeTrue())
 		})
 	})
 
@@ -153,12 +168,12 @@ func TestStatsHeaders(t *testing.T) {
 	const unknown = "unknown"
 	tests := []struct {
 		name     string
-		proxy    Proxy
+		proxy    *Proxy
 		expected map[string]string
 	}{
 		{
 			name: "nil metadata",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: nil,
 			},
 			expected: map[string]string{
@@ -170,7 +185,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "empty metadata",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{},
 			},
 			expected: map[string]string{
@@ -182,7 +197,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "full metadata",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{
 					Name:         "pod",
 					Namespace:    "ns",
@@ -199,7 +214,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "replicaset with expected name format",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{
 					WorkloadKind: "ReplicaSet",
 					WorkloadName: "some-name-randomchars",
@@ -214,7 +229,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "replicaset without expected name format",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{
 					WorkloadKind: "ReplicaSet",
 					WorkloadName: "name",
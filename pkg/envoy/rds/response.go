@@ -25,6 +25,23 @@ func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, discoveryR
 		return nil, err
 	}
 
+	// ---
+	// The multicluster gateway is not associated with any service. In HTTP mode, the gateway
+	// routes the traffic it terminates using the routes of the destination services.
+	if proxy.Kind() == envoy.KindGateway {
+		return newMulticlusterGatewayResponse(cataloger, discoveryReq), nil
+	}
+
+	// The egress gateway proxies TCP streams and is not programmed with any routes
+	if proxy.Kind() == envoy.KindEgressGateway {
+		return nil, nil
+	}
+
+	// The ingress gateway routes the HTTP traffic accepted by the Gateway listeners using the attached HTTPRoutes
+	if proxy.Kind() == envoy.KindIngressGateway {
+		return newIngressGatewayResponse(cataloger, discoveryReq), nil
+	}
+
 	proxyServices, err := proxyRegistry.ListProxyServices(proxy)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingServiceList)).
@@ -107,6 +124,42 @@ func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, discoveryR
 	return rdsResources, nil
 }
 
+// newMulticlusterGatewayResponse returns the route configurations for the multicluster gateway
+func newMulticlusterGatewayResponse(cataloger catalog.MeshCataloger, discoveryReq *xds_discovery.DiscoveryRequest) []types.Resource {
+	var rdsResources []types.Resource
+
+	gatewayTrafficPolicy := cataloger.GetMulticlusterGatewayTrafficPolicy()
+	if gatewayTrafficPolicy != nil {
+		if routeConfig := route.BuildMulticlusterGatewayRouteConfiguration(gatewayTrafficPolicy.HTTPRoutePolicies); routeConfig != nil {
+			rdsResources = append(rdsResources, routeConfig)
+		}
+	}
+
+	if discoveryReq != nil {
+		rdsResources = ensureRDSRequestCompletion(discoveryReq, rdsResources)
+	}
+
+	return rdsResources
+}
+
+// newIngressGatewayResponse returns the route configurations for the ingress gateway
+func newIngressGatewayResponse(cataloger catalog.MeshCataloger, discoveryReq *xds_discovery.DiscoveryRequest) []types.Resource {
+	var rdsResources []types.Resource
+
+	gatewayTrafficPolicy := cataloger.GetIngressGatewayTrafficPolicy()
+	if gatewayTrafficPolicy != nil {
+		for _, routeConfig := range route.BuildIngressGatewayRouteConfiguration(gatewayTrafficPolicy.HTTPRouteConfigsPerPort) {
+			rdsResources = append(rdsResources, routeConfig)
+		}
+	}
+
+	if discoveryReq != nil {
+		rdsResources = ensureRDSRequestCompletion(discoveryReq, rdsResources)
+	}
+
+	return rdsResources
+}
+
 // ensureRDSRequestCompletion computes delta between requested resources and response resources.
 // If any resources requested were not responded to, this function will fill those in with empty RouteConfig stubs
 func ensureRDSRequestCompletion(discoveryReq *xds_discovery.DiscoveryRequest, rdsResources []types.Resource) []types.Resource {
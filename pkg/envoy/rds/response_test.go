@@ -26,6 +26,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -498,3 +499,45 @@ func TestResponseRequestCompletion(t *testing.T) {
 		}
 	}
 }
+
+func TestNewResponseForMulticlusterGateway(t *testing.T) {
+	assert := tassert.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	cn := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindGateway, "osm", "osm-system")
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	assert.Nil(err)
+
+	mockCatalog.EXPECT().GetMulticlusterGatewayTrafficPolicy().Return(&trafficpolicy.MulticlusterGatewayTrafficPolicy{
+		HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+			{
+				Name:      tests.BookstoreV1Hostnames[0],
+				Hostnames: tests.BookstoreV1Hostnames,
+				Rules: []*trafficpolicy.Rule{
+					{
+						Route: trafficpolicy.RouteWeightedClusters{
+							HTTPRouteMatch:   tests.BookstoreBuyHTTPRoute,
+							WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+						},
+						AllowedServiceIdentities: mapset.NewSet(tests.BookbuyerServiceIdentity),
+					},
+				},
+			},
+		},
+	}).Times(1)
+
+	resources, err := NewResponse(mockCatalog, proxy, &xds_discovery.DiscoveryRequest{
+		ResourceNames: []string{route.MulticlusterGatewayRouteConfigName},
+	}, mockConfigurator, nil, nil)
+	assert.Nil(err)
+	assert.Len(resources, 1)
+
+	routeConfig, ok := resources[0].(*xds_route.RouteConfiguration)
+	assert.True(ok)
+	assert.Equal(route.MulticlusterGatewayRouteConfigName, routeConfig.Name)
+	assert.Len(routeConfig.VirtualHosts, 1)
+}
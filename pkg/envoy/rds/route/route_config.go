@@ -10,6 +10,7 @@ import (
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/wrappers"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -29,9 +30,15 @@ const (
 	// IngressRouteConfigName is the name of the ingress RDS route configuration
 	IngressRouteConfigName = "rds-ingress"
 
+	// MulticlusterGatewayRouteConfigName is the name of the multicluster gateway RDS route configuration
+	MulticlusterGatewayRouteConfigName = "rds-multicluster-gateway"
+
 	// egressRouteConfigNamePrefix is the prefix for the name of the egress RDS route configuration
 	egressRouteConfigNamePrefix = "rds-egress"
 
+	// ingressGatewayRouteConfigNamePrefix is the prefix for the name of the ingress gateway RDS route configuration
+	ingressGatewayRouteConfigNamePrefix = "rds-ingress-gateway"
+
 	// inboundVirtualHost is prefix for the virtual host's name in the inbound route configuration
 	inboundVirtualHost = "inbound_virtual-host"
 
@@ -44,6 +51,12 @@ const (
 	// ingressVirtualHost is the prefix for the virtual host's name in the ingress route configuration
 	ingressVirtualHost = "ingress_virtual-host"
 
+	// ingressGatewayVirtualHost is the prefix for the virtual host's name in the ingress gateway route configuration
+	ingressGatewayVirtualHost = "ingress-gateway_virtual-host"
+
+	// multiclusterGatewayVirtualHost is the prefix for the virtual host's name in the multicluster gateway route configuration
+	multiclusterGatewayVirtualHost = "multicluster-gateway_virtual-host"
+
 	// methodHeaderKey is the key of the header for HTTP methods
 	methodHeaderKey = ":method"
 
@@ -100,6 +113,23 @@ func BuildIngressConfiguration(ingress []*trafficpolicy.InboundTrafficPolicy) *x
 	return ingressRouteConfig
 }
 
+// BuildMulticlusterGatewayRouteConfiguration constructs the Envoy construct (*xds_route.RouteConfiguration) for the HTTP routes
+// terminated by the multicluster gateway
+func BuildMulticlusterGatewayRouteConfiguration(policies []*trafficpolicy.InboundTrafficPolicy) *xds_route.RouteConfiguration {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	routeConfig := NewRouteConfigurationStub(MulticlusterGatewayRouteConfigName)
+	for _, policy := range policies {
+		virtualHost := buildVirtualHostStub(multiclusterGatewayVirtualHost, policy.Name, policy.Hostnames)
+		virtualHost.Routes = buildInboundRoutes(policy.Rules)
+		routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, virtualHost)
+	}
+
+	return routeConfig
+}
+
 // BuildOutboundMeshRouteConfiguration constructs the Envoy construct (*xds_route.RouteConfiguration) for the given outbound mesh route configs
 func BuildOutboundMeshRouteConfiguration(portSpecificRouteConfigs map[int][]*trafficpolicy.OutboundTrafficPolicy) []*xds_route.RouteConfiguration {
 	var routeConfigs []*xds_route.RouteConfiguration
@@ -140,6 +170,24 @@ func BuildEgressRouteConfiguration(portSpecificRouteConfigs map[int][]*trafficpo
 	return routeConfigs
 }
 
+// BuildIngressGatewayRouteConfiguration constructs the Envoy construct (*xds_route.RouteConfiguration) for the given ingress gateway route configs
+func BuildIngressGatewayRouteConfiguration(portSpecificRouteConfigs map[int][]*trafficpolicy.OutboundTrafficPolicy) []*xds_route.RouteConfiguration {
+	var routeConfigs []*xds_route.RouteConfiguration
+
+	// An Envoy RouteConfiguration will exist for each port of the ingress gateway with HTTP listeners
+	for port, configs := range portSpecificRouteConfigs {
+		routeConfig := NewRouteConfigurationStub(GetIngressGatewayRouteConfigNameForPort(port))
+		for _, config := range configs {
+			virtualHost := buildVirtualHostStub(ingressGatewayVirtualHost, config.Name, config.Hostnames)
+			virtualHost.Routes = buildIngressGatewayRoutes(config.Routes)
+			routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, virtualHost)
+		}
+		routeConfigs = append(routeConfigs, routeConfig)
+	}
+
+	return routeConfigs
+}
+
 //NewRouteConfigurationStub creates the route configuration placeholder
 func NewRouteConfigurationStub(routeConfigName string) *xds_route.RouteConfiguration {
 	routeConfiguration := xds_route.RouteConfiguration{
@@ -193,7 +241,11 @@ func buildOutboundRoutes(outRoutes []*trafficpolicy.RouteWeightedClusters) []*xd
 	var routes []*xds_route.Route
 	for _, outRoute := range outRoutes {
 		emptyHeaders := map[string]string{}
-		routes = append(routes, buildRoute(trafficpolicy.PathMatchRegex, constants.RegexMatchAll, constants.WildcardHTTPMethod, emptyHeaders, outRoute.WeightedClusters, outRoute.RetryPolicy))
+		route := buildRoute(trafficpolicy.PathMatchRegex, constants.RegexMatchAll, constants.WildcardHTTPMethod, emptyHeaders, outRoute.WeightedClusters, outRoute.RetryPolicy)
+		if hashPolicy := buildHashPolicy(outRoute.HashPolicy); hashPolicy != nil {
+			route.GetRoute().HashPolicy = []*xds_route.RouteAction_HashPolicy{hashPolicy}
+		}
+		routes = append(routes, route)
 	}
 
 	return routes
@@ -216,6 +268,18 @@ func buildEgressRoutes(routingRules []*trafficpolicy.EgressHTTPRoutingRule) []*x
 	return routes
 }
 
+func buildIngressGatewayRoutes(gatewayRoutes []*trafficpolicy.RouteWeightedClusters) []*xds_route.Route {
+	var routes []*xds_route.Route
+	for _, gatewayRoute := range gatewayRoutes {
+		// Each HTTP method corresponds to a separate route
+		for _, method := range sanitizeHTTPMethods(gatewayRoute.HTTPRouteMatch.Methods) {
+			route := buildRoute(gatewayRoute.HTTPRouteMatch.PathMatchType, gatewayRoute.HTTPRouteMatch.Path, method, gatewayRoute.HTTPRouteMatch.Headers, gatewayRoute.WeightedClusters, gatewayRoute.RetryPolicy)
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
 func buildRoute(pathMatchTypeType trafficpolicy.PathMatchType, path string, method string, headersMap map[string]string, weightedClusters mapset.Set, retryPolicy trafficpolicy.RetryPolicy) *xds_route.Route {
 	route := xds_route.Route{
 		Match: &xds_route.RouteMatch{
@@ -261,6 +325,52 @@ func buildRoute(pathMatchTypeType trafficpolicy.PathMatchType, path string, meth
 	return &route
 }
 
+// buildHashPolicy returns the route hash policy used by consistent-hash load balancers for the given hash policy
+func buildHashPolicy(hashPolicy *trafficpolicy.HashPolicy) *xds_route.RouteAction_HashPolicy {
+	if hashPolicy == nil {
+		return nil
+	}
+
+	switch hashPolicy.Kind {
+	case trafficpolicy.HashKeyHeader:
+		return &xds_route.RouteAction_HashPolicy{
+			PolicySpecifier: &xds_route.RouteAction_HashPolicy_Header_{
+				Header: &xds_route.RouteAction_HashPolicy_Header{
+					HeaderName: hashPolicy.Name,
+				},
+			},
+		}
+
+	case trafficpolicy.HashKeyCookie:
+		cookie := &xds_route.RouteAction_HashPolicy_Cookie{
+			Name: hashPolicy.Name,
+			Path: "/",
+		}
+		// A cookie is generated by the proxy only when a TTL is set
+		if hashPolicy.CookieTTL > 0 {
+			cookie.Ttl = durationpb.New(hashPolicy.CookieTTL)
+		}
+		return &xds_route.RouteAction_HashPolicy{
+			PolicySpecifier: &xds_route.RouteAction_HashPolicy_Cookie_{
+				Cookie: cookie,
+			},
+		}
+
+	case trafficpolicy.HashKeySourceIP:
+		return &xds_route.RouteAction_HashPolicy{
+			PolicySpecifier: &xds_route.RouteAction_HashPolicy_ConnectionProperties_{
+				ConnectionProperties: &xds_route.RouteAction_HashPolicy_ConnectionProperties{
+					SourceIp: true,
+				},
+			},
+		}
+
+	default:
+		log.Error().Msgf("Unsupported hash key kind %s, skipping hash policy", hashPolicy.Kind)
+		return nil
+	}
+}
+
 func buildWeightedCluster(weightedClusters mapset.Set) *xds_route.WeightedCluster {
 	var wc xds_route.WeightedCluster
 	var total int
@@ -376,6 +486,11 @@ func GetEgressRouteConfigNameForPort(port int) string {
 	return fmt.Sprintf("%s.%d", egressRouteConfigNamePrefix, port)
 }
 
+// GetIngressGatewayRouteConfigNameForPort returns the ingress gateway route configuration object's name given the port it is targeted to
+func GetIngressGatewayRouteConfigNameForPort(port int) string {
+	return fmt.Sprintf("%s.%d", ingressGatewayRouteConfigNamePrefix, port)
+}
+
 // GetOutboundMeshRouteConfigNameForPort returns the outbound mesh route configuration object's name given the port it is targeted to
 func GetOutboundMeshRouteConfigNameForPort(port int) string {
 	return fmt.Sprintf("%s.%d", OutboundRouteConfigName, port)
@@ -3,6 +3,7 @@ package route
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
@@ -11,6 +12,7 @@ import (
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
@@ -290,6 +292,72 @@ func TestBuildIngressRouteConfiguration(t *testing.T) {
 	}
 }
 
+func TestBuildMulticlusterGatewayRouteConfiguration(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Nil(BuildMulticlusterGatewayRouteConfiguration(nil))
+
+	actual := BuildMulticlusterGatewayRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{
+		{
+			Name:      "bookstore-v1.default.svc.cluster.local",
+			Hostnames: []string{"bookstore-v1.default.svc.cluster.local"},
+			Rules: []*trafficpolicy.Rule{
+				{
+					Route: trafficpolicy.RouteWeightedClusters{
+						HTTPRouteMatch:   tests.BookstoreBuyHTTPRoute,
+						WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+					},
+					AllowedServiceIdentities: mapset.NewSet(tests.BookbuyerServiceIdentity),
+				},
+			},
+		},
+	})
+	assert.NotNil(actual)
+	assert.Equal(MulticlusterGatewayRouteConfigName, actual.Name)
+	assert.Len(actual.VirtualHosts, 1)
+	assert.Equal("multicluster-gateway_virtual-host|bookstore-v1.default.svc.cluster.local", actual.VirtualHosts[0].Name)
+	assert.Len(actual.VirtualHosts[0].Routes, 1)
+
+	// The RBAC policy of the destination is applied at the gateway
+	assert.NotNil(actual.VirtualHosts[0].Routes[0].TypedPerFilterConfig)
+}
+
+func TestBuildIngressGatewayRouteConfiguration(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := BuildIngressGatewayRouteConfiguration(map[int][]*trafficpolicy.OutboundTrafficPolicy{
+		80: {
+			{
+				Name:      "foo.com",
+				Hostnames: []string{"foo.com", "foo.com:80"},
+				Routes: []*trafficpolicy.RouteWeightedClusters{
+					{
+						HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+							Path:          "/api",
+							PathMatchType: trafficpolicy.PathMatchPrefix,
+							Methods:       []string{"GET", "POST"},
+							Headers:       map[string]string{"x-version": "v1"},
+						},
+						WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+					},
+				},
+			},
+		},
+	})
+	assert.Len(actual, 1)
+	assert.Equal("rds-ingress-gateway.80", actual[0].Name)
+	assert.Len(actual[0].VirtualHosts, 1)
+	assert.Equal("ingress-gateway_virtual-host|foo.com", actual[0].VirtualHosts[0].Name)
+	assert.Equal([]string{"foo.com", "foo.com:80"}, actual[0].VirtualHosts[0].Domains)
+
+	// Each method corresponds to a separate route
+	assert.Len(actual[0].VirtualHosts[0].Routes, 2)
+	assert.Equal("/api", actual[0].VirtualHosts[0].Routes[0].Match.GetPrefix())
+
+	// Routes are not subject to RBAC at the gateway
+	assert.Nil(actual[0].VirtualHosts[0].Routes[0].TypedPerFilterConfig)
+}
+
 func TestBuildVirtualHostStub(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -422,6 +490,69 @@ func TestBuildOutboundRoutes(t *testing.T) {
 	assert.Equal("testCluster", actual[0].GetRoute().GetWeightedClusters().Clusters[0].Name)
 	assert.Equal(uint32(100), actual[0].GetRoute().GetWeightedClusters().Clusters[0].Weight.GetValue())
 	assert.Equal(&xds_route.RetryPolicy{}, actual[0].GetRoute().RetryPolicy)
+	assert.Nil(actual[0].GetRoute().HashPolicy)
+
+	input[0].HashPolicy = &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeyHeader, Name: "x-user-id"}
+	actual = buildOutboundRoutes(input)
+	assert.Len(actual[0].GetRoute().HashPolicy, 1)
+	assert.Equal("x-user-id", actual[0].GetRoute().HashPolicy[0].GetHeader().HeaderName)
+}
+
+func TestBuildHashPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		hashPolicy *trafficpolicy.HashPolicy
+		expected   *xds_route.RouteAction_HashPolicy
+	}{
+		{
+			name:       "no hash policy",
+			hashPolicy: nil,
+			expected:   nil,
+		},
+		{
+			name:       "header hash policy",
+			hashPolicy: &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeyHeader, Name: "x-user-id"},
+			expected: &xds_route.RouteAction_HashPolicy{
+				PolicySpecifier: &xds_route.RouteAction_HashPolicy_Header_{
+					Header: &xds_route.RouteAction_HashPolicy_Header{HeaderName: "x-user-id"},
+				},
+			},
+		},
+		{
+			name:       "cookie hash policy without TTL",
+			hashPolicy: &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeyCookie, Name: "session"},
+			expected: &xds_route.RouteAction_HashPolicy{
+				PolicySpecifier: &xds_route.RouteAction_HashPolicy_Cookie_{
+					Cookie: &xds_route.RouteAction_HashPolicy_Cookie{Name: "session", Path: "/"},
+				},
+			},
+		},
+		{
+			name:       "cookie hash policy with TTL",
+			hashPolicy: &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeyCookie, Name: "session", CookieTTL: time.Hour},
+			expected: &xds_route.RouteAction_HashPolicy{
+				PolicySpecifier: &xds_route.RouteAction_HashPolicy_Cookie_{
+					Cookie: &xds_route.RouteAction_HashPolicy_Cookie{Name: "session", Path: "/", Ttl: durationpb.New(time.Hour)},
+				},
+			},
+		},
+		{
+			name:       "source IP hash policy",
+			hashPolicy: &trafficpolicy.HashPolicy{Kind: trafficpolicy.HashKeySourceIP},
+			expected: &xds_route.RouteAction_HashPolicy{
+				PolicySpecifier: &xds_route.RouteAction_HashPolicy_ConnectionProperties_{
+					ConnectionProperties: &xds_route.RouteAction_HashPolicy_ConnectionProperties{SourceIp: true},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, buildHashPolicy(tc.hashPolicy))
+		})
+	}
 }
 
 func TestBuildRoute(t *testing.T) {
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -32,6 +33,7 @@ func (e ExplicitProxyServiceMapper) ListProxyServices(p *envoy.Proxy) ([]service
 // KubeProxyServiceMapper maps an Envoy instance to services in a Kubernetes cluster.
 type KubeProxyServiceMapper struct {
 	KubeController k8s.Controller
+	Configurator   configurator.Configurator
 }
 
 // ListProxyServices maps an Envoy instance to a number of Kubernetes services.
@@ -49,7 +51,7 @@ func (k *KubeProxyServiceMapper) ListProxyServices(p *envoy.Proxy) ([]service.Me
 		return nil, nil
 	}
 
-	meshServices := kubernetesServicesToMeshServices(k.KubeController, services)
+	meshServices := kubernetesServicesToMeshServices(k.KubeController, services, k.Configurator.GetFeatureFlags().EnableProtocolSniffing)
 
 	servicesForPod := strings.Join(listServiceNames(meshServices), ",")
 	log.Trace().Msgf("Services associated with Pod with UID=%s Name=%s/%s: %+v",
@@ -58,9 +60,9 @@ func (k *KubeProxyServiceMapper) ListProxyServices(p *envoy.Proxy) ([]service.Me
 	return meshServices, nil
 }
 
-func kubernetesServicesToMeshServices(kubeController k8s.Controller, kubernetesServices []v1.Service) (meshServices []service.MeshService) {
+func kubernetesServicesToMeshServices(kubeController k8s.Controller, kubernetesServices []v1.Service, enableProtocolSniffing bool) (meshServices []service.MeshService) {
 	for _, svc := range kubernetesServices {
-		meshServices = append(meshServices, k8s.ServiceToMeshServices(kubeController, svc)...)
+		meshServices = append(meshServices, k8s.ServiceToMeshServices(kubeController, svc, enableProtocolSniffing)...)
 	}
 	return meshServices
 }
@@ -16,7 +16,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -32,7 +34,9 @@ var _ = Describe("Test Proxy-Service mapping", func() {
 	mockCtrl := gomock.NewController(ginkgo.GinkgoT())
 	kubeClient := testclient.NewSimpleClientset()
 	mockKubeController := k8s.NewMockController(mockCtrl)
-	proxyRegistry := NewProxyRegistry(&KubeProxyServiceMapper{mockKubeController}, nil)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
+	proxyRegistry := NewProxyRegistry(&KubeProxyServiceMapper{KubeController: mockKubeController, Configurator: mockConfigurator}, nil)
 
 	Context("Test ListProxyServices()", func() {
 		It("works as expected", func() {
@@ -530,7 +534,7 @@ func TestKubernetesServicesToMeshServices(t *testing.T) {
 
 			mockKubeController.EXPECT().GetEndpoints(gomock.Any()).Return(&v1.Endpoints{}, nil).Times(len(tc.k8sServices))
 
-			actual := kubernetesServicesToMeshServices(mockKubeController, tc.k8sServices)
+			actual := kubernetesServicesToMeshServices(mockKubeController, tc.k8sServices, false)
 			assert.ElementsMatch(tc.expectedMeshServices, actual)
 		})
 	}
@@ -0,0 +1,142 @@
+package sds
+
+import (
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/secrets"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// egressCACertKey is the key in a secret holding the CA bundle used to validate an external host during TLS origination
+	egressCACertKey = "ca.crt"
+)
+
+// getEgressSecret returns the secret used to originate TLS to an external host. A proxy can only be served the secrets
+// referenced by the Egress policies applicable to it.
+func (s *sdsImpl) getEgressSecret(sdscert secrets.SDSCert, proxy *envoy.Proxy) (*xds_auth.Secret, error) {
+	secretName, err := sdscert.GetK8sSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isEgressSecretReferenced(*secretName, sdscert.CertType, proxy) {
+		return nil, errEgressSecretNotAllowed
+	}
+
+	k8sSecret := s.meshCatalog.GetKubeController().GetSecret(secretName.Name, secretName.Namespace)
+	if k8sSecret == nil {
+		return nil, errors.Errorf("Secret %s not found", secretName)
+	}
+
+	switch sdscert.CertType {
+	case secrets.EgressCACertType:
+		caBundle, ok := k8sSecret.Data[egressCACertKey]
+		if !ok {
+			return nil, errors.Errorf("Secret %s is missing the %s key", secretName, egressCACertKey)
+		}
+		return &xds_auth.Secret{
+			Name: sdscert.String(),
+			Type: &xds_auth.Secret_ValidationContext{
+				ValidationContext: &xds_auth.CertificateValidationContext{
+					TrustedCa: &xds_core.DataSource{
+						Specifier: &xds_core.DataSource_InlineBytes{
+							InlineBytes: caBundle,
+						},
+					},
+				},
+			},
+		}, nil
+
+	default:
+		return getK8sTLSSecret(sdscert, k8sSecret)
+	}
+}
+
+// getK8sTLSSecret returns the SDS secret holding the certificate chain and private key stored in the given
+// Kubernetes TLS secret
+func getK8sTLSSecret(sdscert secrets.SDSCert, k8sSecret *corev1.Secret) (*xds_auth.Secret, error) {
+	certChain, okCert := k8sSecret.Data[corev1.TLSCertKey]
+	privateKey, okKey := k8sSecret.Data[corev1.TLSPrivateKeyKey]
+	if !okCert || !okKey {
+		return nil, errors.Errorf("Secret %s/%s is missing the %s or %s key", k8sSecret.Namespace, k8sSecret.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+	return &xds_auth.Secret{
+		Name: sdscert.String(),
+		Type: &xds_auth.Secret_TlsCertificate{
+			TlsCertificate: &xds_auth.TlsCertificate{
+				CertificateChain: &xds_core.DataSource{
+					Specifier: &xds_core.DataSource_InlineBytes{
+						InlineBytes: certChain,
+					},
+				},
+				PrivateKey: &xds_core.DataSource{
+					Specifier: &xds_core.DataSource_InlineBytes{
+						InlineBytes: privateKey,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// isEgressSecretReferenced returns a boolean indicating if the given secret is referenced for the given cert type
+// by the TLS origination configs applicable to the proxy
+func (s *sdsImpl) isEgressSecretReferenced(secretName types.NamespacedName, certType secrets.SDSCertType, proxy *envoy.Proxy) bool {
+	for _, tlsConfig := range s.listEgressTLSConfigs(proxy) {
+		switch certType {
+		case secrets.EgressCACertType:
+			if tlsConfig.CACertSecret == secretName {
+				return true
+			}
+		case secrets.EgressClientCertType:
+			if tlsConfig.ClientCertSecret != nil && *tlsConfig.ClientCertSecret == secretName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listEgressTLSConfigs returns the TLS origination configs applicable to the proxy. The egress gateway originates
+// TLS on behalf of the sidecars when the Egress traffic is routed through it. The configs are computed once per
+// response, since a proxy requests all its egress secrets at once.
+func (s *sdsImpl) listEgressTLSConfigs(proxy *envoy.Proxy) []*trafficpolicy.EgressTLSConfig {
+	if !s.egressTLSConfigsComputed {
+		s.egressTLSConfigs = s.getEgressTLSConfigs(proxy)
+		s.egressTLSConfigsComputed = true
+	}
+	return s.egressTLSConfigs
+}
+
+// getEgressTLSConfigs computes the TLS origination configs applicable to the proxy
+func (s *sdsImpl) getEgressTLSConfigs(proxy *envoy.Proxy) []*trafficpolicy.EgressTLSConfig {
+	var tlsConfigs []*trafficpolicy.EgressTLSConfig
+
+	if proxy.Kind() == envoy.KindEgressGateway {
+		if gatewayPolicy := s.meshCatalog.GetEgressGatewayTrafficPolicy(); gatewayPolicy != nil {
+			for _, upstream := range gatewayPolicy.Upstreams {
+				if upstream.TLS != nil {
+					tlsConfigs = append(tlsConfigs, upstream.TLS)
+				}
+			}
+		}
+		return tlsConfigs
+	}
+
+	egressPolicy, err := s.meshCatalog.GetEgressTrafficPolicy(s.serviceIdentity)
+	if err != nil || egressPolicy == nil {
+		return nil
+	}
+	for _, clusterConfig := range egressPolicy.ClustersConfigs {
+		if clusterConfig.TLS != nil {
+			tlsConfigs = append(tlsConfigs, clusterConfig.TLS)
+		}
+	}
+	return tlsConfigs
+}
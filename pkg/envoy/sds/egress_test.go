@@ -0,0 +1,187 @@
+package sds
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/egressgateway"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/secrets"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetEgressSecret(t *testing.T) {
+	proxySvcAccount := identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}
+	sidecarCN := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, proxySvcAccount.Name, proxySvcAccount.Namespace)
+	gatewayCN := egressgateway.GetEgressGatewaySubjectCommonName("osm", "osm-system")
+
+	tlsConfig := &trafficpolicy.EgressTLSConfig{
+		SNI:              "foo.com",
+		CACertSecret:     types.NamespacedName{Namespace: "ns-1", Name: "ca"},
+		ClientCertSecret: &types.NamespacedName{Namespace: "ns-1", Name: "client"},
+	}
+	k8sSecrets := map[string]*corev1.Secret{
+		"ca": {
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "ns-1"},
+			Data:       map[string][]byte{"ca.crt": []byte("ca-bundle")},
+		},
+		"client": {
+			ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "ns-1"},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+		"invalid": {
+			ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "ns-1"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		proxyCN       certificate.CommonName
+		sdsCert       secrets.SDSCert
+		referencedCA  string
+		expectedError bool
+	}{
+		{
+			name:          "CA bundle referenced by the sidecar's Egress policies",
+			proxyCN:       sidecarCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/ca", CertType: secrets.EgressCACertType},
+			referencedCA:  "ca",
+			expectedError: false,
+		},
+		{
+			name:          "client certificate referenced by the sidecar's Egress policies",
+			proxyCN:       sidecarCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/client", CertType: secrets.EgressClientCertType},
+			referencedCA:  "ca",
+			expectedError: false,
+		},
+		{
+			name:          "CA bundle referenced by the egress gateway's upstreams",
+			proxyCN:       gatewayCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/ca", CertType: secrets.EgressCACertType},
+			referencedCA:  "ca",
+			expectedError: false,
+		},
+		{
+			name:          "secret not referenced by the Egress policies",
+			proxyCN:       sidecarCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-2/ca", CertType: secrets.EgressCACertType},
+			referencedCA:  "ca",
+			expectedError: true,
+		},
+		{
+			name:          "client certificate requested as a CA bundle",
+			proxyCN:       sidecarCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/client", CertType: secrets.EgressCACertType},
+			referencedCA:  "ca",
+			expectedError: true,
+		},
+		{
+			name:          "secret is missing the CA bundle",
+			proxyCN:       sidecarCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/invalid", CertType: secrets.EgressCACertType},
+			referencedCA:  "invalid",
+			expectedError: true,
+		},
+		{
+			name:          "secret does not exist",
+			proxyCN:       sidecarCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/missing", CertType: secrets.EgressCACertType},
+			referencedCA:  "missing",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+			mockKubeController.EXPECT().GetSecret(gomock.Any(), "ns-1").DoAndReturn(func(name, _ string) *corev1.Secret {
+				return k8sSecrets[name]
+			}).AnyTimes()
+
+			referencedTLSConfig := *tlsConfig
+			referencedTLSConfig.CACertSecret.Name = tc.referencedCA
+			mockCatalog.EXPECT().GetEgressTrafficPolicy(proxySvcAccount.ToServiceIdentity()).Return(&trafficpolicy.EgressTrafficPolicy{
+				ClustersConfigs: []*trafficpolicy.EgressClusterConfig{
+					{Name: "foo.com:80", Host: "foo.com", Port: 80, TLS: &referencedTLSConfig},
+				},
+			}, nil).AnyTimes()
+			mockCatalog.EXPECT().GetEgressGatewayTrafficPolicy().Return(&trafficpolicy.EgressGatewayTrafficPolicy{
+				Upstreams: []*trafficpolicy.EgressGatewayUpstream{
+					{Host: "foo.com", Port: 80, ClusterName: "foo.com:80", TLS: &referencedTLSConfig},
+				},
+			}).AnyTimes()
+
+			proxy, err := envoy.NewProxy(tc.proxyCN, "", nil)
+			assert.Nil(err)
+
+			s := &sdsImpl{
+				meshCatalog:     mockCatalog,
+				serviceIdentity: proxySvcAccount.ToServiceIdentity(),
+			}
+
+			secret, err := s.getEgressSecret(tc.sdsCert, proxy)
+			assert.Equal(tc.expectedError, err != nil)
+			if tc.expectedError {
+				return
+			}
+
+			assert.Equal(tc.sdsCert.String(), secret.Name)
+			if tc.sdsCert.CertType == secrets.EgressCACertType {
+				assert.Equal([]byte("ca-bundle"), secret.GetValidationContext().TrustedCa.GetInlineBytes())
+			} else {
+				assert.Equal([]byte("cert"), secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+				assert.Equal([]byte("key"), secret.GetTlsCertificate().PrivateKey.GetInlineBytes())
+			}
+		})
+	}
+}
+
+func TestListEgressTLSConfigsComputedOnce(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxySvcAccount := identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}
+	tlsConfig := &trafficpolicy.EgressTLSConfig{
+		SNI:              "foo.com",
+		CACertSecret:     types.NamespacedName{Namespace: "ns-1", Name: "ca"},
+		ClientCertSecret: &types.NamespacedName{Namespace: "ns-1", Name: "client"},
+	}
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockCatalog.EXPECT().GetEgressTrafficPolicy(proxySvcAccount.ToServiceIdentity()).Return(&trafficpolicy.EgressTrafficPolicy{
+		ClustersConfigs: []*trafficpolicy.EgressClusterConfig{
+			{Name: "foo.com:80", Host: "foo.com", Port: 80, TLS: tlsConfig},
+		},
+	}, nil).Times(1)
+
+	proxy, err := envoy.NewProxy(envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, proxySvcAccount.Name, proxySvcAccount.Namespace), "", nil)
+	assert.Nil(err)
+
+	s := &sdsImpl{
+		meshCatalog:     mockCatalog,
+		serviceIdentity: proxySvcAccount.ToServiceIdentity(),
+	}
+
+	// The Egress traffic policy is computed once for all the egress secrets requested by the proxy
+	assert.True(s.isEgressSecretReferenced(tlsConfig.CACertSecret, secrets.EgressCACertType, proxy))
+	assert.True(s.isEgressSecretReferenced(*tlsConfig.ClientCertSecret, secrets.EgressClientCertType, proxy))
+	assert.False(s.isEgressSecretReferenced(types.NamespacedName{Namespace: "ns-1", Name: "other"}, secrets.EgressCACertType, proxy))
+}
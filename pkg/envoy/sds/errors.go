@@ -5,5 +5,8 @@ import (
 )
 
 var (
-	errCertMismatch = errors.New("certificate mismatch")
+	errCertMismatch                 = errors.New("certificate mismatch")
+	errGatewayServiceCertNotAllowed = errors.New("service certificate not allowed for multicluster gateway")
+	errEgressSecretNotAllowed       = errors.New("secret not referenced by the Egress policies applicable to the proxy")
+	errGatewaySecretNotAllowed      = errors.New("secret not allowed for proxy, only the ingress gateway can request secrets referenced by Gateways")
 )
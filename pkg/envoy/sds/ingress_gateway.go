@@ -0,0 +1,77 @@
+package sds
+
+import (
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/secrets"
+	"github.com/openservicemesh/osm/pkg/ingressgateway"
+)
+
+// getIngressGatewayCert returns the certificate presented by the ingress gateway to backends, as specified by the
+// MeshConfig's ingress gateway certificate spec. The same certificate authorizes ingress traffic from the gateway.
+func (s *sdsImpl) getIngressGatewayCert(sdscert secrets.SDSCert, proxy *envoy.Proxy) (*xds_auth.Secret, error) {
+	if proxy.Kind() != envoy.KindIngressGateway {
+		return nil, errGatewaySecretNotAllowed
+	}
+
+	cn, validity, err := ingressgateway.GetClientCertificateSpec(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if string(cn) != sdscert.Name {
+		return nil, errCertMismatch
+	}
+
+	cert, err := s.certManager.IssueCertificate(cn, validity)
+	if err != nil {
+		return nil, err
+	}
+
+	return getServiceCertSecret(cert, sdscert.String())
+}
+
+// getGatewayListenerSecret returns the certificate used by the ingress gateway to terminate TLS on a Gateway listener.
+// Only the secrets referenced by the listeners of the Gateways managed by OSM can be requested.
+func (s *sdsImpl) getGatewayListenerSecret(sdscert secrets.SDSCert, proxy *envoy.Proxy) (*xds_auth.Secret, error) {
+	if proxy.Kind() != envoy.KindIngressGateway {
+		return nil, errGatewaySecretNotAllowed
+	}
+
+	secretName, err := sdscert.GetK8sSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isGatewayListenerSecretReferenced(*secretName) {
+		return nil, errGatewaySecretNotAllowed
+	}
+
+	k8sSecret := s.meshCatalog.GetKubeController().GetSecret(secretName.Name, secretName.Namespace)
+	if k8sSecret == nil {
+		return nil, errors.Errorf("Secret %s not found", secretName)
+	}
+
+	return getK8sTLSSecret(sdscert, k8sSecret)
+}
+
+// isGatewayListenerSecretReferenced returns a boolean indicating if the given secret is referenced by a listener
+// programmed on the ingress gateway
+func (s *sdsImpl) isGatewayListenerSecretReferenced(secretName types.NamespacedName) bool {
+	gatewayPolicy := s.meshCatalog.GetIngressGatewayTrafficPolicy()
+	if gatewayPolicy == nil {
+		return false
+	}
+	for _, listener := range gatewayPolicy.Listeners {
+		for _, filterChain := range listener.FilterChains {
+			for _, certSecret := range filterChain.CertificateSecrets {
+				if certSecret == secretName {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package sds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/secrets"
+	"github.com/openservicemesh/osm/pkg/ingressgateway"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetGatewayListenerSecret(t *testing.T) {
+	sidecarCN := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, "sa-1", "ns-1")
+	gatewayCN := ingressgateway.GetIngressGatewaySubjectCommonName("osm", "osm-system")
+
+	k8sSecrets := map[string]*corev1.Secret{
+		"cert": {
+			ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: "ns-1"},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+		"unreferenced": {
+			ObjectMeta: metav1.ObjectMeta{Name: "unreferenced", Namespace: "ns-1"},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		proxyCN       certificate.CommonName
+		sdsCert       secrets.SDSCert
+		expectedError bool
+	}{
+		{
+			name:          "certificate referenced by a Gateway listener",
+			proxyCN:       gatewayCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/cert", CertType: secrets.GatewayListenerCertType},
+			expectedError: false,
+		},
+		{
+			name:          "certificate not referenced by a Gateway listener",
+			proxyCN:       gatewayCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/unreferenced", CertType: secrets.GatewayListenerCertType},
+			expectedError: true,
+		},
+		{
+			name:          "certificate requested by a sidecar",
+			proxyCN:       sidecarCN,
+			sdsCert:       secrets.SDSCert{Name: "ns-1/cert", CertType: secrets.GatewayListenerCertType},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+			mockKubeController.EXPECT().GetSecret(gomock.Any(), "ns-1").DoAndReturn(func(name, _ string) *corev1.Secret {
+				return k8sSecrets[name]
+			}).AnyTimes()
+			mockCatalog.EXPECT().GetIngressGatewayTrafficPolicy().Return(&trafficpolicy.IngressGatewayTrafficPolicy{
+				Listeners: []*trafficpolicy.IngressGatewayListener{
+					{
+						Port: 443,
+						FilterChains: []*trafficpolicy.IngressGatewayFilterChain{
+							{Name: "ns-1/gateway/https", Protocol: "https", CertificateSecrets: []types.NamespacedName{{Namespace: "ns-1", Name: "cert"}}},
+						},
+					},
+				},
+			}).AnyTimes()
+
+			proxy, err := envoy.NewProxy(tc.proxyCN, "", nil)
+			assert.Nil(err)
+
+			s := &sdsImpl{meshCatalog: mockCatalog}
+
+			secret, err := s.getGatewayListenerSecret(tc.sdsCert, proxy)
+			assert.Equal(tc.expectedError, err != nil)
+			if tc.expectedError {
+				return
+			}
+			assert.Equal(tc.sdsCert.String(), secret.Name)
+			assert.Equal([]byte("cert"), secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+			assert.Equal([]byte("key"), secret.GetTlsCertificate().PrivateKey.GetInlineBytes())
+		})
+	}
+}
+
+func TestGetIngressGatewayCert(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	gatewayCN := ingressgateway.GetIngressGatewaySubjectCommonName("osm", "osm-system")
+	clientCN := certificate.CommonName("osm-ingress-gateway.osm-system.cluster.local")
+
+	mockCfg := configurator.NewMockConfigurator(mockCtrl)
+	mockCertManager := certificate.NewMockManager(mockCtrl)
+	mockCert := certificate.NewMockCertificater(mockCtrl)
+	mockCfg.EXPECT().GetMeshConfig().Return(&v1alpha1.MeshConfig{
+		Spec: v1alpha1.MeshConfigSpec{
+			Certificate: v1alpha1.CertificateSpec{
+				IngressGateway: &v1alpha1.IngressGatewayCertSpec{
+					SubjectAltNames:  []string{clientCN.String()},
+					ValidityDuration: "1h",
+				},
+			},
+		},
+	}).AnyTimes()
+	mockCertManager.EXPECT().IssueCertificate(clientCN, time.Hour).Return(mockCert, nil).Times(1)
+	mockCert.EXPECT().GetCertificateChain().Return([]byte("cert")).AnyTimes()
+	mockCert.EXPECT().GetPrivateKey().Return([]byte("key")).AnyTimes()
+
+	gatewayProxy, err := envoy.NewProxy(gatewayCN, "", nil)
+	assert.Nil(err)
+	s := &sdsImpl{cfg: mockCfg, certManager: mockCertManager}
+
+	sdsCert := secrets.SDSCert{Name: clientCN.String(), CertType: secrets.IngressGatewayCertType}
+	secret, err := s.getIngressGatewayCert(sdsCert, gatewayProxy)
+	assert.Nil(err)
+	assert.Equal(sdsCert.String(), secret.Name)
+	assert.Equal([]byte("cert"), secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+
+	// Only the certificate specified by the MeshConfig can be requested
+	_, err = s.getIngressGatewayCert(secrets.SDSCert{Name: "other.osm-system.cluster.local", CertType: secrets.IngressGatewayCertType}, gatewayProxy)
+	assert.Equal(errCertMismatch, err)
+}
@@ -79,7 +79,15 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 		switch sdsCert.CertType {
 		// A service certificate is requested
 		case secrets.ServiceCertType:
-			envoySecret, err := getServiceCertSecret(cert, requestedCertificate)
+			serviceCert := cert
+			if proxy.Kind() == envoy.KindGateway {
+				if serviceCert, err = s.getMulticlusterGatewayServiceCert(cert, *sdsCert); err != nil {
+					log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceCertSecret)).
+						Str("proxy", proxy.String()).Msgf("Error getting service cert %s for multicluster gateway", requestedCertificate)
+					continue
+				}
+			}
+			envoySecret, err := getServiceCertSecret(serviceCert, requestedCertificate)
 			if err != nil {
 				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceCertSecret)).
 					Str("proxy", proxy.String()).Msgf("Error getting service cert %s for proxy", requestedCertificate)
@@ -96,6 +104,34 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 			}
 			certs = append(certs, envoySecret)
 
+		// A secret used to originate TLS to an external host is requested
+		case secrets.EgressCACertType, secrets.EgressClientCertType:
+			envoySecret, err := s.getEgressSecret(*sdsCert, proxy)
+			if err != nil {
+				log.Error().Err(err).Str("proxy", proxy.String()).Msgf("Error getting egress secret %s for proxy", requestedCertificate)
+				continue
+			}
+			certs = append(certs, envoySecret)
+
+		// The certificate presented by the ingress gateway to backends is requested
+		case secrets.IngressGatewayCertType:
+			envoySecret, err := s.getIngressGatewayCert(*sdsCert, proxy)
+			if err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceCertSecret)).
+					Str("proxy", proxy.String()).Msgf("Error getting ingress gateway cert %s for proxy", requestedCertificate)
+				continue
+			}
+			certs = append(certs, envoySecret)
+
+		// A certificate used by the ingress gateway to terminate TLS on a Gateway listener is requested
+		case secrets.GatewayListenerCertType:
+			envoySecret, err := s.getGatewayListenerSecret(*sdsCert, proxy)
+			if err != nil {
+				log.Error().Err(err).Str("proxy", proxy.String()).Msgf("Error getting Gateway listener secret %s for proxy", requestedCertificate)
+				continue
+			}
+			certs = append(certs, envoySecret)
+
 		default:
 			log.Error().Str("proxy", proxy.String()).Msgf("Unexpected certificate type %s requested by proxy", requestedCertificate)
 		}
@@ -128,6 +164,34 @@ func getServiceCertSecret(cert certificate.Certificater, name string) (*xds_auth
 	return secret, nil
 }
 
+// getMulticlusterGatewayServiceCert returns the service certificate requested by the multicluster gateway. In HTTP mode,
+// the gateway terminates mTLS on behalf of HTTP services, and presents the certificate of the service to downstream
+// clients so that they can continue to validate the identity of the upstream service.
+func (s *sdsImpl) getMulticlusterGatewayServiceCert(gatewayCert certificate.Certificater, sdscert secrets.SDSCert) (certificate.Certificater, error) {
+	svcAccount, err := sdscert.GetK8sServiceAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	svcIdentity := svcAccount.ToServiceIdentity()
+	if svcIdentity == s.serviceIdentity {
+		return gatewayCert, nil
+	}
+
+	// Only the identities of the HTTP services whose traffic is terminated by the gateway are allowed
+	gatewayPolicy := s.meshCatalog.GetMulticlusterGatewayTrafficPolicy()
+	if gatewayPolicy == nil {
+		return nil, errGatewayServiceCertNotAllowed
+	}
+	for _, upstream := range gatewayPolicy.HTTPUpstreams {
+		if upstream.Identity == svcIdentity {
+			return s.certManager.IssueCertificate(certificate.CommonName(svcIdentity), s.cfg.GetServiceCertValidityPeriod())
+		}
+	}
+
+	return nil, errGatewayServiceCertNotAllowed
+}
+
 func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert secrets.SDSCert) (*xds_auth.Secret, error) {
 	secret := &xds_auth.Secret{
 		// The Name field must match the tls_context.common_tls_context.tls_certificate_sds_secret_configs.name
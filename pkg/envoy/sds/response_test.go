@@ -3,6 +3,7 @@ package sds
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
@@ -22,6 +23,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 // TestNewResponse sets up a fake kube client, then a pod and makes an SDS request,
@@ -223,6 +225,97 @@ func TestGetServiceCert(t *testing.T) {
 	}
 }
 
+func TestGetMulticlusterGatewayServiceCert(t *testing.T) {
+	gatewayIdentity := identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity()
+	upstreamIdentity := identity.K8sServiceAccount{Name: "bookstore", Namespace: "ns-1"}.ToServiceIdentity()
+	gatewayPolicy := &trafficpolicy.MulticlusterGatewayTrafficPolicy{
+		HTTPUpstreams: []*trafficpolicy.MulticlusterGatewayHTTPUpstream{
+			{
+				Service:     service.MeshService{Name: "bookstore", Namespace: "ns-1"},
+				Identity:    upstreamIdentity,
+				ClusterName: "ns-1/bookstore",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		requestedCert string
+		gatewayPolicy *trafficpolicy.MulticlusterGatewayTrafficPolicy
+		expectIssue   bool
+		expectError   bool
+	}{
+		{
+			name:          "gateway's own certificate",
+			requestedCert: "service-cert:osm-system/osm",
+			expectIssue:   false,
+			expectError:   false,
+		},
+		{
+			name:          "certificate for an HTTP upstream",
+			requestedCert: "service-cert:ns-1/bookstore",
+			gatewayPolicy: gatewayPolicy,
+			expectIssue:   true,
+			expectError:   false,
+		},
+		{
+			name:          "certificate for an identity not served by the gateway",
+			requestedCert: "service-cert:ns-1/bookbuyer",
+			gatewayPolicy: gatewayPolicy,
+			expectIssue:   false,
+			expectError:   true,
+		},
+		{
+			name:          "HTTP mode disabled",
+			requestedCert: "service-cert:ns-1/bookstore",
+			gatewayPolicy: nil,
+			expectIssue:   false,
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCertManager := certificate.NewMockManager(mockCtrl)
+			gatewayCert := certificate.NewMockCertificater(mockCtrl)
+			upstreamCert := certificate.NewMockCertificater(mockCtrl)
+
+			mockCatalog.EXPECT().GetMulticlusterGatewayTrafficPolicy().Return(tc.gatewayPolicy).AnyTimes()
+			if tc.expectIssue {
+				mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(time.Hour).Times(1)
+				mockCertManager.EXPECT().IssueCertificate(certificate.CommonName(upstreamIdentity), time.Hour).Return(upstreamCert, nil).Times(1)
+			}
+
+			s := &sdsImpl{
+				serviceIdentity: gatewayIdentity,
+				meshCatalog:     mockCatalog,
+				cfg:             mockConfigurator,
+				certManager:     mockCertManager,
+			}
+
+			sdsCert, err := secrets.UnmarshalSDSCert(tc.requestedCert)
+			assert.Nil(err)
+
+			cert, err := s.getMulticlusterGatewayServiceCert(gatewayCert, *sdsCert)
+			assert.Equal(tc.expectError, err != nil)
+			switch {
+			case tc.expectError:
+				assert.Nil(cert)
+			case tc.expectIssue:
+				assert.Equal(upstreamCert, cert)
+			default:
+				assert.Equal(gatewayCert, cert)
+			}
+		})
+	}
+}
+
 func TestGetSDSSecrets(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
@@ -7,6 +7,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 var (
@@ -19,4 +20,9 @@ type sdsImpl struct {
 	meshCatalog     catalog.MeshCataloger
 	cfg             configurator.Configurator
 	certManager     certificate.Manager
+
+	// egressTLSConfigs are the TLS origination configs applicable to the proxy, computed once per response
+	// when an egress secret is first requested
+	egressTLSConfigs         []*trafficpolicy.EgressTLSConfig
+	egressTLSConfigsComputed bool
 }
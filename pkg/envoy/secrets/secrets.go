@@ -3,6 +3,8 @@ package secrets
 import (
 	"strings"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -86,6 +88,24 @@ func (sdsc *SDSCert) GetK8sServiceAccount() (*identity.K8sServiceAccount, error)
 	}, nil
 }
 
+// GetK8sSecret unmarshals the namespaced name of a Kubernetes secret from a SDSCert name
+func (sdsc *SDSCert) GetK8sSecret() (*types.NamespacedName, error) {
+	slices := strings.Split(sdsc.Name, namespaceNameSeparator)
+	if len(slices) != 2 {
+		return nil, errInvalidNamespacedSecretFormat
+	}
+
+	// Make sure the slices are not empty. Split might actually leave empty slices.
+	if slices[0] == "" || slices[1] == "" {
+		return nil, errInvalidNamespacedSecretFormat
+	}
+
+	return &types.NamespacedName{
+		Namespace: slices[0],
+		Name:      slices[1],
+	}, nil
+}
+
 // GetSecretNameForIdentity returns the SDS secret name corresponding to the given ServiceIdentity
 func GetSecretNameForIdentity(si identity.ServiceIdentity) string {
 	// TODO(draychev): The cert names can be redone to move away from using "namespace/name" format [https://github.com/openservicemesh/osm/issues/2218]
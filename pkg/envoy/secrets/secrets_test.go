@@ -6,6 +6,7 @@ import (
 
 	tassert "github.com/stretchr/testify/assert"
 	trequire "github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -162,6 +163,41 @@ func TestUnmarshalMeshService(t *testing.T) {
 	}
 }
 
+func TestUnmarshalK8sSecret(t *testing.T) {
+	testCases := []struct {
+		name        string
+		sdsCert     SDSCert
+		expected    *types.NamespacedName
+		expectedErr bool
+	}{
+		{
+			name:     "successfully unmarshal secret",
+			sdsCert:  SDSCert{Name: "ns/secret", CertType: EgressCACertType},
+			expected: &types.NamespacedName{Namespace: "ns", Name: "secret"},
+		},
+		{
+			name:        "incomplete namespaced secret name",
+			sdsCert:     SDSCert{Name: "ns/", CertType: EgressCACertType},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid namespaced secret name",
+			sdsCert:     SDSCert{Name: "secret", CertType: EgressClientCertType},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := tc.sdsCert.GetK8sSecret()
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
+
 func TestUnmarshalK8sServiceAccount(t *testing.T) {
 	require := trequire.New(t)
 
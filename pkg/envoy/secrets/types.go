@@ -43,6 +43,18 @@ const (
 
 	// RootCertTypeForMTLSInbound is the prefix for the mTLS root certificate resource name for downstream connectivity. Example: "root-cert-for-mtls-inbound:ns/name"
 	RootCertTypeForMTLSInbound SDSCertType = "root-cert-for-mtls-inbound"
+
+	// EgressCACertType is the prefix for the CA bundle resource name used to validate an external host during TLS origination. Example: "egress-ca-cert:ns/secret-name"
+	EgressCACertType SDSCertType = "egress-ca-cert"
+
+	// EgressClientCertType is the prefix for the client certificate resource name presented to an external host during TLS origination. Example: "egress-client-cert:ns/secret-name"
+	EgressClientCertType SDSCertType = "egress-client-cert"
+
+	// IngressGatewayCertType is the prefix for the client certificate resource name presented by the ingress gateway to backends. Example: "ingress-gateway-cert:osm-ingress-gateway.osm-system.cluster.local"
+	IngressGatewayCertType SDSCertType = "ingress-gateway-cert"
+
+	// GatewayListenerCertType is the prefix for the certificate resource name used by the ingress gateway to terminate TLS on a Gateway listener. Example: "gateway-listener-cert:ns/secret-name"
+	GatewayListenerCertType SDSCertType = "gateway-listener-cert"
 )
 
 // Defines valid cert types
@@ -50,4 +62,8 @@ var validCertTypes = map[SDSCertType]struct{}{
 	ServiceCertType:             {},
 	RootCertTypeForMTLSOutbound: {},
 	RootCertTypeForMTLSInbound:  {},
+	EgressCACertType:            {},
+	EgressClientCertType:        {},
+	IngressGatewayCertType:      {},
+	GatewayListenerCertType:     {},
 }
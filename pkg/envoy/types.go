@@ -105,4 +105,10 @@ const (
 
 	// KindGateway implies the proxy is a gateway
 	KindGateway ProxyKind = "gateway"
+
+	// KindEgressGateway implies the proxy is an egress gateway
+	KindEgressGateway ProxyKind = "egress-gateway"
+
+	// KindIngressGateway implies the proxy is an ingress gateway
+	KindIngressGateway ProxyKind = "ingress-gateway"
 )
@@ -7,8 +7,10 @@ import (
 	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/stream/v3"
+	xds_dns_cache "github.com/envoyproxy/go-control-plane/envoy/extensions/common/dynamic_forward_proxy/v3"
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	extensions_upstream_http_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
 	structpb "github.com/golang/protobuf/ptypes/struct"
@@ -17,6 +19,7 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -56,6 +59,15 @@ const (
 
 	// MulticlusterGatewayCluster is the tls passthough cluster name for multicluster gateway
 	MulticlusterGatewayCluster = "passthrough-multicluster-gateway"
+
+	// EgressDNSCacheName is the name of the DNS cache used to resolve wildcard egress hosts
+	EgressDNSCacheName = "egress-dns-cache"
+
+	// DynamicForwardProxyClusterType is the name of the custom cluster type used by dynamic forward proxy clusters
+	DynamicForwardProxyClusterType = "envoy.clusters.dynamic_forward_proxy"
+
+	// DynamicForwardProxyHTTPFilterName is the name of the dynamic forward proxy HTTP filter
+	DynamicForwardProxyHTTPFilterName = "envoy.filters.http.dynamic_forward_proxy"
 )
 
 // ALPNInMesh indicates that the proxy is connecting to an in-mesh destination.
@@ -77,6 +89,18 @@ func GetAddress(address string, port uint32) *xds_core.Address {
 	}
 }
 
+// GetWildcardAddress returns the address to listen on all the interfaces on the given port. When IPv6 is enabled,
+// the IPv6 wildcard address is used, and IPv4 connections are also accepted for dual-stack support.
+func GetWildcardAddress(port uint32, enableIPv6 bool) *xds_core.Address {
+	if !enableIPv6 {
+		return GetAddress(constants.WildcardIPAddr, port)
+	}
+
+	address := GetAddress(constants.WildcardIPv6Addr, port)
+	address.GetSocketAddress().Ipv4Compat = true
+	return address
+}
+
 // GetTLSParams creates Envoy TlsParameters struct.
 func GetTLSParams() *xds_auth.TlsParameters {
 	return &xds_auth.TlsParameters{
@@ -232,6 +256,120 @@ func GetUpstreamTLSContext(downstreamIdentity identity.ServiceIdentity, upstream
 	return tlsConfig
 }
 
+// GetEgressUpstreamTLSContext creates an upstream Envoy TLS Context to originate TLS to an external host with the given SNI.
+// The certificate presented by the external host is validated using the given CA bundle and against the SNI, which can be
+// covered by a wildcard DNS name of the certificate. If a client certificate is given, it is presented to the external host
+// for mutual TLS.
+func GetEgressUpstreamTLSContext(sni string, caSDSCert secrets.SDSCert, clientSDSCert *secrets.SDSCert) *xds_auth.UpstreamTlsContext {
+	commonTLSContext := &xds_auth.CommonTlsContext{
+		TlsParams: GetTLSParams(),
+		ValidationContextType: &xds_auth.CommonTlsContext_CombinedValidationContext{
+			CombinedValidationContext: &xds_auth.CommonTlsContext_CombinedCertificateValidationContext{
+				DefaultValidationContext: &xds_auth.CertificateValidationContext{
+					MatchSubjectAltNames: getEgressSubjectAltNameMatchers(sni),
+				},
+				ValidationContextSdsSecretConfig: &xds_auth.SdsSecretConfig{
+					// Example ==> Name: "egress-ca-cert:NameSpaceHere/SecretNameHere"
+					Name:      caSDSCert.String(),
+					SdsConfig: GetADSConfigSource(),
+				},
+			},
+		},
+	}
+
+	if clientSDSCert != nil {
+		commonTLSContext.TlsCertificateSdsSecretConfigs = []*xds_auth.SdsSecretConfig{{
+			// Example ==> Name: "egress-client-cert:NameSpaceHere/SecretNameHere"
+			Name:      clientSDSCert.String(),
+			SdsConfig: GetADSConfigSource(),
+		}}
+	}
+
+	return &xds_auth.UpstreamTlsContext{
+		CommonTlsContext: commonTLSContext,
+		Sni:              sni,
+	}
+}
+
+// getEgressSubjectAltNameMatchers returns the matchers of the subject alternative names of a certificate valid for the
+// given SNI. Besides the SNI itself, a certificate is valid for the SNI if it has a wildcard DNS name replacing the
+// leftmost label of the SNI, ex. '*.example.com' for 'api.example.com', as long as the wildcard is not followed by a
+// top level domain only.
+func getEgressSubjectAltNameMatchers(sni string) []*xds_matcher.StringMatcher {
+	matchers := []*xds_matcher.StringMatcher{{
+		MatchPattern: &xds_matcher.StringMatcher_Exact{
+			Exact: sni,
+		},
+	}}
+
+	if net.ParseIP(sni) != nil {
+		// IP addresses are matched exactly
+		return matchers
+	}
+	labels := strings.SplitN(sni, ".", 2)
+	if len(labels) < 2 || !strings.Contains(labels[1], ".") {
+		return matchers
+	}
+
+	return append(matchers, &xds_matcher.StringMatcher{
+		MatchPattern: &xds_matcher.StringMatcher_Exact{
+			Exact: "*." + labels[1],
+		},
+	})
+}
+
+// GetIngressGatewayUpstreamTLSContext creates an upstream Envoy TLS Context for the ingress gateway to originate mTLS
+// to the given upstream service. The ingress gateway presents the certificate of the given gateway identity, and does
+// not advertise the in-mesh ALPN so that the upstream's ingress filter chain is matched instead of its in-mesh filter chain.
+func GetIngressGatewayUpstreamTLSContext(gatewayIdentity identity.ServiceIdentity, upstreamSvc service.MeshService) *xds_auth.UpstreamTlsContext {
+	gatewaySDSCert := secrets.SDSCert{
+		Name:     gatewayIdentity.String(),
+		CertType: secrets.IngressGatewayCertType,
+	}
+	upstreamPeerValidationSDSCert := &secrets.SDSCert{
+		Name:     upstreamSvc.String(),
+		CertType: secrets.RootCertTypeForMTLSOutbound,
+	}
+
+	return &xds_auth.UpstreamTlsContext{
+		CommonTlsContext: getCommonTLSContext(gatewaySDSCert, upstreamPeerValidationSDSCert),
+		Sni:              upstreamSvc.ServerName(),
+	}
+}
+
+// GetIngressGatewayDownstreamTLSContext creates a downstream Envoy TLS Context for the ingress gateway to terminate TLS
+// using the certificates in the given secrets. Clients are not required to present a certificate.
+func GetIngressGatewayDownstreamTLSContext(certSecrets []types.NamespacedName) *xds_auth.DownstreamTlsContext {
+	commonTLSContext := &xds_auth.CommonTlsContext{
+		TlsParams: GetTLSParams(),
+	}
+	for _, certSecret := range certSecrets {
+		sdsCert := secrets.SDSCert{
+			Name:     certSecret.String(),
+			CertType: secrets.GatewayListenerCertType,
+		}
+		commonTLSContext.TlsCertificateSdsSecretConfigs = append(commonTLSContext.TlsCertificateSdsSecretConfigs, &xds_auth.SdsSecretConfig{
+			// Example ==> Name: "gateway-listener-cert:NameSpaceHere/SecretNameHere"
+			Name:      sdsCert.String(),
+			SdsConfig: GetADSConfigSource(),
+		})
+	}
+
+	return &xds_auth.DownstreamTlsContext{
+		CommonTlsContext:         commonTLSContext,
+		RequireClientCertificate: &wrappers.BoolValue{Value: false},
+	}
+}
+
+// GetEgressDNSCacheConfig returns the DNS cache config shared by the dynamic forward proxy HTTP filter and the
+// dynamic forward proxy clusters used to route egress traffic to wildcard hosts. The filter and the clusters must
+// reference identical DNS cache configs for the hosts resolved by the filter to be used by the clusters.
+func GetEgressDNSCacheConfig() *xds_dns_cache.DnsCacheConfig {
+	return &xds_dns_cache.DnsCacheConfig{
+		Name: EgressDNSCacheName,
+	}
+}
+
 // GetHTTP2ProtocolOptions creates an Envoy http configuration that matches the downstream protocol
 func GetHTTP2ProtocolOptions() (map[string]*any.Any, error) {
 	marshalledHTTPProtocolOptions, err := ptypes.MarshalAny(
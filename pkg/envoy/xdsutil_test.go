@@ -68,6 +68,45 @@ func TestGetStdoutAccessLog(t *testing.T) {
 	assert.Equal(resAccessLogger, expAccessLogger)
 }
 
+func TestGetWildcardAddress(t *testing.T) {
+	assert := tassert.New(t)
+
+	ipv4Addr := GetWildcardAddress(15001, false)
+	assert.Equal("0.0.0.0", ipv4Addr.GetSocketAddress().Address)
+	assert.Equal(uint32(15001), ipv4Addr.GetSocketAddress().GetPortValue())
+	assert.False(ipv4Addr.GetSocketAddress().Ipv4Compat)
+
+	ipv6Addr := GetWildcardAddress(15001, true)
+	assert.Equal("::", ipv6Addr.GetSocketAddress().Address)
+	assert.Equal(uint32(15001), ipv6Addr.GetSocketAddress().GetPortValue())
+	assert.True(ipv6Addr.GetSocketAddress().Ipv4Compat)
+}
+
+func TestGetEgressSubjectAltNameMatchers(t *testing.T) {
+	testCases := []struct {
+		sni      string
+		expected []string
+	}{
+		{sni: "api.example.com", expected: []string{"api.example.com", "*.example.com"}},
+		{sni: "foo.api.example.com", expected: []string{"foo.api.example.com", "*.api.example.com"}},
+		{sni: "example.com", expected: []string{"example.com"}},
+		{sni: "localhost", expected: []string{"localhost"}},
+		{sni: "10.0.0.1", expected: []string{"10.0.0.1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sni, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var actual []string
+			for _, matcher := range getEgressSubjectAltNameMatchers(tc.sni) {
+				actual = append(actual, matcher.GetExact())
+			}
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
+
 var _ = Describe("Test Envoy tools", func() {
 	Context("Test GetAddress()", func() {
 		It("should return address", func() {
@@ -80,6 +80,12 @@ const (
 
 	// ErrInvalidSourceKind	indicated an applied SMI TrafficTarget policy has an invalid source kind
 	ErrInvalidSourceKind
+
+	// ErrInvalidLoadBalancerConfig indicates the load balancer configuration specified for a service is invalid
+	ErrInvalidLoadBalancerConfig
+
+	// ErrInvalidEgressGatewayPolicy indicates an egress policy cannot be enforced by the egress gateway
+	ErrInvalidEgressGatewayPolicy
 )
 
 // Range 3000-3500 is reserved for errors related to k8s constructs (service accounts, namespaces, etc.)
@@ -481,6 +487,20 @@ An applied SMI TrafficTarget policy has an invalid destination kind.
 
 	ErrInvalidSourceKind: `
 An applied SMI TrafficTarget policy has an invalid source kind.
+`,
+
+	ErrInvalidLoadBalancerConfig: `
+The load balancer configuration specified using annotations on a service is invalid.
+The system falls back to round robin load balancing for the service. Please verify
+the values of the 'openservicemesh.io/load-balancer', 'openservicemesh.io/hash-key'
+and 'openservicemesh.io/hash-cookie-ttl' annotations on the service.
+`,
+
+	ErrInvalidEgressGatewayPolicy: `
+The egress policy cannot be enforced by the egress gateway, because it specifies a
+TCP port or no hosts. The gateway routes traffic based on the host requested by the
+sidecar, so such policies are ignored while the egress gateway is enabled instead of
+letting their traffic bypass the gateway.
 `,
 
 	ErrGettingInboundTrafficTargets: `
@@ -0,0 +1,194 @@
+package gatewayapi
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayClientset "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
+	gatewayInformers "sigs.k8s.io/gateway-api/pkg/client/informers/gateway/externalversions"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/ingressgateway"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+// NewGatewayAPIController returns a gatewayapi.Controller interface related to functionality provided by the resources in the gateway.networking.k8s.io API group
+func NewGatewayAPIController(kubeController k8s.Controller, gatewayClient gatewayClientset.Interface, stop chan struct{}, msgBroker *messaging.Broker) (Controller, error) {
+	return newClient(kubeController, gatewayClient, stop, msgBroker)
+}
+
+func newClient(kubeController k8s.Controller, gatewayClient gatewayClientset.Interface, stop chan struct{}, msgBroker *messaging.Broker) (client, error) {
+	informerFactory := gatewayInformers.NewSharedInformerFactory(gatewayClient, k8s.DefaultKubeEventResyncInterval)
+
+	informerCollection := informerCollection{
+		gatewayClass: informerFactory.Gateway().V1alpha2().GatewayClasses().Informer(),
+		gateway:      informerFactory.Gateway().V1alpha2().Gateways().Informer(),
+		httpRoute:    informerFactory.Gateway().V1alpha2().HTTPRoutes().Informer(),
+		tlsRoute:     informerFactory.Gateway().V1alpha2().TLSRoutes().Informer(),
+	}
+
+	cacheCollection := cacheCollection{
+		gatewayClass: informerCollection.gatewayClass.GetStore(),
+		gateway:      informerCollection.gateway.GetStore(),
+		httpRoute:    informerCollection.httpRoute.GetStore(),
+		tlsRoute:     informerCollection.tlsRoute.GetStore(),
+	}
+
+	client := client{
+		informers:      &informerCollection,
+		caches:         &cacheCollection,
+		kubeController: kubeController,
+	}
+
+	shouldObserve := func(obj interface{}) bool {
+		object, ok := obj.(metav1.Object)
+		if !ok {
+			return false
+		}
+		return kubeController.IsMonitoredNamespace(object.GetNamespace())
+	}
+
+	// GatewayClass is a cluster scoped resource
+	shouldObserveGatewayClass := func(obj interface{}) bool {
+		gatewayClass, ok := obj.(*gatewayv1alpha2.GatewayClass)
+		if !ok {
+			return false
+		}
+		return isManagedGatewayClass(gatewayClass)
+	}
+
+	gatewayClassEventTypes := k8s.EventTypes{
+		Add:    announcements.GatewayClassAdded,
+		Update: announcements.GatewayClassUpdated,
+		Delete: announcements.GatewayClassDeleted,
+	}
+	informerCollection.gatewayClass.AddEventHandler(k8s.GetEventHandlerFuncs(shouldObserveGatewayClass, gatewayClassEventTypes, msgBroker))
+	gatewayEventTypes := k8s.EventTypes{
+		Add:    announcements.GatewayAdded,
+		Update: announcements.GatewayUpdated,
+		Delete: announcements.GatewayDeleted,
+	}
+	informerCollection.gateway.AddEventHandler(k8s.GetEventHandlerFuncs(shouldObserve, gatewayEventTypes, msgBroker))
+	httpRouteEventTypes := k8s.EventTypes{
+		Add:    announcements.GatewayHTTPRouteAdded,
+		Update: announcements.GatewayHTTPRouteUpdated,
+		Delete: announcements.GatewayHTTPRouteDeleted,
+	}
+	informerCollection.httpRoute.AddEventHandler(k8s.GetEventHandlerFuncs(shouldObserve, httpRouteEventTypes, msgBroker))
+	tlsRouteEventTypes := k8s.EventTypes{
+		Add:    announcements.GatewayTLSRouteAdded,
+		Update: announcements.GatewayTLSRouteUpdated,
+		Delete: announcements.GatewayTLSRouteDeleted,
+	}
+	informerCollection.tlsRoute.AddEventHandler(k8s.GetEventHandlerFuncs(shouldObserve, tlsRouteEventTypes, msgBroker))
+
+	err := client.run(stop)
+	if err != nil {
+		return client, errors.Errorf("Could not start %s informer clients: %s", gatewayv1alpha2.SchemeGroupVersion, err)
+	}
+
+	return client, err
+}
+
+func (c client) run(stop <-chan struct{}) error {
+	log.Info().Msgf("Starting informer clients for API group %s", gatewayv1alpha2.SchemeGroupVersion)
+
+	if c.informers == nil {
+		return errInitInformers
+	}
+
+	sharedInformers := map[string]cache.SharedInformer{
+		"GatewayClass": c.informers.gatewayClass,
+		"Gateway":      c.informers.gateway,
+		"HTTPRoute":    c.informers.httpRoute,
+		"TLSRoute":     c.informers.tlsRoute,
+	}
+
+	var informerNames []string
+	var hasSynced []cache.InformerSynced
+	for name, informer := range sharedInformers {
+		if informer == nil {
+			log.Error().Msgf("Informer for '%s' not initialized, ignoring it", name) // TODO: log with errcode
+			continue
+		}
+		informerNames = append(informerNames, name)
+		log.Info().Msgf("Starting informer: %s", name)
+		go informer.Run(stop)
+		hasSynced = append(hasSynced, informer.HasSynced)
+	}
+
+	log.Info().Msgf("Waiting for informers %v caches to sync", informerNames)
+	if !cache.WaitForCacheSync(stop, hasSynced...) {
+		return errSyncingCaches
+	}
+
+	log.Info().Msgf("Cache sync finished for %v informers in API group %s", informerNames, gatewayv1alpha2.SchemeGroupVersion)
+	return nil
+}
+
+// ListGateways lists the Gateways in the monitored namespaces whose GatewayClass is managed by OSM
+func (c client) ListGateways() []*gatewayv1alpha2.Gateway {
+	var gateways []*gatewayv1alpha2.Gateway
+
+	for _, gatewayIface := range c.caches.gateway.List() {
+		gateway := gatewayIface.(*gatewayv1alpha2.Gateway)
+
+		if !c.kubeController.IsMonitoredNamespace(gateway.Namespace) {
+			continue
+		}
+
+		gatewayClassIface, exists, err := c.caches.gatewayClass.GetByKey(string(gateway.Spec.GatewayClassName))
+		if !exists || err != nil {
+			log.Trace().Msgf("GatewayClass %s of Gateway %s/%s not found, ignoring Gateway", gateway.Spec.GatewayClassName, gateway.Namespace, gateway.Name)
+			continue
+		}
+		if !isManagedGatewayClass(gatewayClassIface.(*gatewayv1alpha2.GatewayClass)) {
+			continue
+		}
+
+		gateways = append(gateways, gateway)
+	}
+
+	return gateways
+}
+
+// ListHTTPRoutes lists the HTTPRoutes in the monitored namespaces
+func (c client) ListHTTPRoutes() []*gatewayv1alpha2.HTTPRoute {
+	var routes []*gatewayv1alpha2.HTTPRoute
+
+	for _, routeIface := range c.caches.httpRoute.List() {
+		route := routeIface.(*gatewayv1alpha2.HTTPRoute)
+
+		if !c.kubeController.IsMonitoredNamespace(route.Namespace) {
+			continue
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+// ListTLSRoutes lists the TLSRoutes in the monitored namespaces
+func (c client) ListTLSRoutes() []*gatewayv1alpha2.TLSRoute {
+	var routes []*gatewayv1alpha2.TLSRoute
+
+	for _, routeIface := range c.caches.tlsRoute.List() {
+		route := routeIface.(*gatewayv1alpha2.TLSRoute)
+
+		if !c.kubeController.IsMonitoredNamespace(route.Namespace) {
+			continue
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+// isManagedGatewayClass returns a boolean indicating if the given GatewayClass is managed by OSM
+func isManagedGatewayClass(gatewayClass *gatewayv1alpha2.GatewayClass) bool {
+	return string(gatewayClass.Spec.ControllerName) == ingressgateway.ControllerName
+}
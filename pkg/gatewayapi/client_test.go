@@ -0,0 +1,86 @@
+package gatewayapi
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	fakeGatewayClient "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned/fake"
+
+	"github.com/openservicemesh/osm/pkg/ingressgateway"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestListGateways(t *testing.T) {
+	a := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(false).AnyTimes()
+
+	c, err := newClient(mockKubeController, fakeGatewayClient.NewSimpleClientset(), nil, nil)
+	a.Nil(err)
+
+	managedClass := &gatewayv1alpha2.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "osm"},
+		Spec:       gatewayv1alpha2.GatewayClassSpec{ControllerName: ingressgateway.ControllerName},
+	}
+	unmanagedClass := &gatewayv1alpha2.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec:       gatewayv1alpha2.GatewayClassSpec{ControllerName: "example.com/gateway-controller"},
+	}
+	_ = c.caches.gatewayClass.Add(managedClass)
+	_ = c.caches.gatewayClass.Add(unmanagedClass)
+
+	managedGateway := &gatewayv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "test"},
+		Spec:       gatewayv1alpha2.GatewaySpec{GatewayClassName: "osm"},
+	}
+	unmanagedGateway := &gatewayv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "test"},
+		Spec:       gatewayv1alpha2.GatewaySpec{GatewayClassName: "other"},
+	}
+	unknownClassGateway := &gatewayv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "unknown", Namespace: "test"},
+		Spec:       gatewayv1alpha2.GatewaySpec{GatewayClassName: "unknown"},
+	}
+	unmonitoredGateway := &gatewayv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "unmonitored"},
+		Spec:       gatewayv1alpha2.GatewaySpec{GatewayClassName: "osm"},
+	}
+	for _, gateway := range []*gatewayv1alpha2.Gateway{managedGateway, unmanagedGateway, unknownClassGateway, unmonitoredGateway} {
+		_ = c.caches.gateway.Add(gateway)
+	}
+
+	a.ElementsMatch([]*gatewayv1alpha2.Gateway{managedGateway}, c.ListGateways())
+}
+
+func TestListRoutes(t *testing.T) {
+	a := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(false).AnyTimes()
+
+	c, err := newClient(mockKubeController, fakeGatewayClient.NewSimpleClientset(), nil, nil)
+	a.Nil(err)
+
+	monitoredHTTPRoute := &gatewayv1alpha2.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"}}
+	unmonitoredHTTPRoute := &gatewayv1alpha2.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "unmonitored"}}
+	_ = c.caches.httpRoute.Add(monitoredHTTPRoute)
+	_ = c.caches.httpRoute.Add(unmonitoredHTTPRoute)
+
+	monitoredTLSRoute := &gatewayv1alpha2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "test"}}
+	unmonitoredTLSRoute := &gatewayv1alpha2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "unmonitored"}}
+	_ = c.caches.tlsRoute.Add(monitoredTLSRoute)
+	_ = c.caches.tlsRoute.Add(unmonitoredTLSRoute)
+
+	a.ElementsMatch([]*gatewayv1alpha2.HTTPRoute{monitoredHTTPRoute}, c.ListHTTPRoutes())
+	a.ElementsMatch([]*gatewayv1alpha2.TLSRoute{monitoredTLSRoute}, c.ListTLSRoutes())
+}
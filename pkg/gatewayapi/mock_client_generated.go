@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/openservicemesh/osm/pkg/gatewayapi (interfaces: Controller)
+
+// Package gatewayapi is a generated GoMock package.
+package gatewayapi
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// MockController is a mock of Controller interface.
+type MockController struct {
+	ctrl     *gomock.Controller
+	recorder *MockControllerMockRecorder
+}
+
+// MockControllerMockRecorder is the mock recorder for MockController.
+type MockControllerMockRecorder struct {
+	mock *MockController
+}
+
+// NewMockController creates a new mock instance.
+func NewMockController(ctrl *gomock.Controller) *MockController {
+	mock := &MockController{ctrl: ctrl}
+	mock.recorder = &MockControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockController) EXPECT() *MockControllerMockRecorder {
+	return m.recorder
+}
+
+// ListGateways mocks base method.
+func (m *MockController) ListGateways() []*v1alpha2.Gateway {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGateways")
+	ret0, _ := ret[0].([]*v1alpha2.Gateway)
+	return ret0
+}
+
+// ListGateways indicates an expected call of ListGateways.
+func (mr *MockControllerMockRecorder) ListGateways() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGateways", reflect.TypeOf((*MockController)(nil).ListGateways))
+}
+
+// ListHTTPRoutes mocks base method.
+func (m *MockController) ListHTTPRoutes() []*v1alpha2.HTTPRoute {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHTTPRoutes")
+	ret0, _ := ret[0].([]*v1alpha2.HTTPRoute)
+	return ret0
+}
+
+// ListHTTPRoutes indicates an expected call of ListHTTPRoutes.
+func (mr *MockControllerMockRecorder) ListHTTPRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHTTPRoutes", reflect.TypeOf((*MockController)(nil).ListHTTPRoutes))
+}
+
+// ListTLSRoutes mocks base method.
+func (m *MockController) ListTLSRoutes() []*v1alpha2.TLSRoute {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTLSRoutes")
+	ret0, _ := ret[0].([]*v1alpha2.TLSRoute)
+	return ret0
+}
+
+// ListTLSRoutes indicates an expected call of ListTLSRoutes.
+func (mr *MockControllerMockRecorder) ListTLSRoutes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTLSRoutes", reflect.TypeOf((*MockController)(nil).ListTLSRoutes))
+}
@@ -0,0 +1,50 @@
+// Package gatewayapi implements the Kubernetes client for the resources in the gateway.networking.k8s.io API group
+// used to program the OSM ingress gateway.
+package gatewayapi
+
+import (
+	"k8s.io/client-go/tools/cache"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("gateway-api-controller")
+)
+
+// informerCollection is the type used to represent the collection of informers for the gateway.networking.k8s.io API group
+type informerCollection struct {
+	gatewayClass cache.SharedIndexInformer
+	gateway      cache.SharedIndexInformer
+	httpRoute    cache.SharedIndexInformer
+	tlsRoute     cache.SharedIndexInformer
+}
+
+// cacheCollection is the type used to represent the collection of caches for the gateway.networking.k8s.io API group
+type cacheCollection struct {
+	gatewayClass cache.Store
+	gateway      cache.Store
+	httpRoute    cache.Store
+	tlsRoute     cache.Store
+}
+
+// client is the type used to represent the Kubernetes client for the gateway.networking.k8s.io API group
+type client struct {
+	informers      *informerCollection
+	caches         *cacheCollection
+	kubeController k8s.Controller
+}
+
+// Controller is the interface for the functionality provided by the resources part of the gateway.networking.k8s.io API group
+type Controller interface {
+	// ListGateways lists the Gateways in the monitored namespaces whose GatewayClass is managed by OSM
+	ListGateways() []*gatewayv1alpha2.Gateway
+
+	// ListHTTPRoutes lists the HTTPRoutes in the monitored namespaces
+	ListHTTPRoutes() []*gatewayv1alpha2.HTTPRoute
+
+	// ListTLSRoutes lists the TLSRoutes in the monitored namespaces
+	ListTLSRoutes() []*gatewayv1alpha2.TLSRoute
+}
@@ -25,6 +25,7 @@ type ConfigV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	MeshConfigsGetter
 	MultiClusterServicesGetter
+	SidecarConfigsGetter
 }
 
 // ConfigV1alpha1Client is used to interact with features provided by the config.openservicemesh.io group.
@@ -40,6 +41,10 @@ func (c *ConfigV1alpha1Client) MultiClusterServices(namespace string) MultiClust
 	return newMultiClusterServices(c, namespace)
 }
 
+func (c *ConfigV1alpha1Client) SidecarConfigs(namespace string) SidecarConfigInterface {
+	return newSidecarConfigs(c, namespace)
+}
+
 // NewForConfig creates a new ConfigV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*ConfigV1alpha1Client, error) {
 	config := *c
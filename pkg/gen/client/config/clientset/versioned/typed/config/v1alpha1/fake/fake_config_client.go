@@ -33,6 +33,10 @@ func (c *FakeConfigV1alpha1) MultiClusterServices(namespace string) v1alpha1.Mul
 	return &FakeMultiClusterServices{c, namespace}
 }
 
+func (c *FakeConfigV1alpha1) SidecarConfigs(namespace string) v1alpha1.SidecarConfigInterface {
+	return &FakeSidecarConfigs{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeConfigV1alpha1) RESTClient() rest.Interface {
@@ -0,0 +1,127 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeSidecarConfigs implements SidecarConfigInterface
+type FakeSidecarConfigs struct {
+	Fake *FakeConfigV1alpha1
+	ns   string
+}
+
+var sidecarconfigsResource = schema.GroupVersionResource{Group: "config.openservicemesh.io", Version: "v1alpha1", Resource: "sidecarconfigs"}
+
+var sidecarconfigsKind = schema.GroupVersionKind{Group: "config.openservicemesh.io", Version: "v1alpha1", Kind: "SidecarConfig"}
+
+// Get takes name of the sidecarConfig, and returns the corresponding sidecarConfig object, and an error if there is any.
+func (c *FakeSidecarConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.SidecarConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(sidecarconfigsResource, c.ns, name), &v1alpha1.SidecarConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SidecarConfig), err
+}
+
+// List takes label and field selectors, and returns the list of SidecarConfigs that match those selectors.
+func (c *FakeSidecarConfigs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.SidecarConfigList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(sidecarconfigsResource, sidecarconfigsKind, c.ns, opts), &v1alpha1.SidecarConfigList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.SidecarConfigList{ListMeta: obj.(*v1alpha1.SidecarConfigList).ListMeta}
+	for _, item := range obj.(*v1alpha1.SidecarConfigList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested sidecarConfigs.
+func (c *FakeSidecarConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(sidecarconfigsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a sidecarConfig and creates it.  Returns the server's representation of the sidecarConfig, and an error, if there is any.
+func (c *FakeSidecarConfigs) Create(ctx context.Context, sidecarConfig *v1alpha1.SidecarConfig, opts v1.CreateOptions) (result *v1alpha1.SidecarConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(sidecarconfigsResource, c.ns, sidecarConfig), &v1alpha1.SidecarConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SidecarConfig), err
+}
+
+// Update takes the representation of a sidecarConfig and updates it. Returns the server's representation of the sidecarConfig, and an error, if there is any.
+func (c *FakeSidecarConfigs) Update(ctx context.Context, sidecarConfig *v1alpha1.SidecarConfig, opts v1.UpdateOptions) (result *v1alpha1.SidecarConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(sidecarconfigsResource, c.ns, sidecarConfig), &v1alpha1.SidecarConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SidecarConfig), err
+}
+
+// Delete takes name of the sidecarConfig and deletes it. Returns an error if one occurs.
+func (c *FakeSidecarConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(sidecarconfigsResource, c.ns, name), &v1alpha1.SidecarConfig{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeSidecarConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(sidecarconfigsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.SidecarConfigList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched sidecarConfig.
+func (c *FakeSidecarConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SidecarConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(sidecarconfigsResource, c.ns, name, pt, data, subresources...), &v1alpha1.SidecarConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.SidecarConfig), err
+}
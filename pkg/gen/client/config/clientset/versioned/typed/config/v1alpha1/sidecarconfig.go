@@ -0,0 +1,175 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	scheme "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// SidecarConfigsGetter has a method to return a SidecarConfigInterface.
+// A group's client should implement this interface.
+type SidecarConfigsGetter interface {
+	SidecarConfigs(namespace string) SidecarConfigInterface
+}
+
+// SidecarConfigInterface has methods to work with SidecarConfig resources.
+type SidecarConfigInterface interface {
+	Create(ctx context.Context, sidecarConfig *v1alpha1.SidecarConfig, opts v1.CreateOptions) (*v1alpha1.SidecarConfig, error)
+	Update(ctx context.Context, sidecarConfig *v1alpha1.SidecarConfig, opts v1.UpdateOptions) (*v1alpha1.SidecarConfig, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.SidecarConfig, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.SidecarConfigList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SidecarConfig, err error)
+	SidecarConfigExpansion
+}
+
+// sidecarConfigs implements SidecarConfigInterface
+type sidecarConfigs struct {
+	client rest.Interface
+	ns     string
+}
+
+// newSidecarConfigs returns a SidecarConfigs
+func newSidecarConfigs(c *ConfigV1alpha1Client, namespace string) *sidecarConfigs {
+	return &sidecarConfigs{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the sidecarConfig, and returns the corresponding sidecarConfig object, and an error if there is any.
+func (c *sidecarConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.SidecarConfig, err error) {
+	result = &v1alpha1.SidecarConfig{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of SidecarConfigs that match those selectors.
+func (c *sidecarConfigs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.SidecarConfigList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.SidecarConfigList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested sidecarConfigs.
+func (c *sidecarConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a sidecarConfig and creates it.  Returns the server's representation of the sidecarConfig, and an error, if there is any.
+func (c *sidecarConfigs) Create(ctx context.Context, sidecarConfig *v1alpha1.SidecarConfig, opts v1.CreateOptions) (result *v1alpha1.SidecarConfig, err error) {
+	result = &v1alpha1.SidecarConfig{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a sidecarConfig and updates it. Returns the server's representation of the sidecarConfig, and an error, if there is any.
+func (c *sidecarConfigs) Update(ctx context.Context, sidecarConfig *v1alpha1.SidecarConfig, opts v1.UpdateOptions) (result *v1alpha1.SidecarConfig, err error) {
+	result = &v1alpha1.SidecarConfig{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		Name(sidecarConfig.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(sidecarConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the sidecarConfig and deletes it. Returns an error if one occurs.
+func (c *sidecarConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *sidecarConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched sidecarConfig.
+func (c *sidecarConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.SidecarConfig, err error) {
+	result = &v1alpha1.SidecarConfig{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("sidecarconfigs").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
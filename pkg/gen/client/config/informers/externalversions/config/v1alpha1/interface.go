@@ -25,6 +25,8 @@ type Interface interface {
 	MeshConfigs() MeshConfigInformer
 	// MultiClusterServices returns a MultiClusterServiceInformer.
 	MultiClusterServices() MultiClusterServiceInformer
+	// SidecarConfigs returns a SidecarConfigInformer.
+	SidecarConfigs() SidecarConfigInformer
 }
 
 type version struct {
@@ -47,3 +49,8 @@ func (v *version) MeshConfigs() MeshConfigInformer {
 func (v *version) MultiClusterServices() MultiClusterServiceInformer {
 	return &multiClusterServiceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// SidecarConfigs returns a SidecarConfigInformer.
+func (v *version) SidecarConfigs() SidecarConfigInformer {
+	return &sidecarConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
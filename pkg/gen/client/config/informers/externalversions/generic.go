@@ -54,6 +54,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Config().V1alpha1().MeshConfigs().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("multiclusterservices"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Config().V1alpha1().MultiClusterServices().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("sidecarconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Config().V1alpha1().SidecarConfigs().Informer()}, nil
 
 	}
 
@@ -30,3 +30,11 @@ type MultiClusterServiceListerExpansion interface{}
 // MultiClusterServiceNamespaceListerExpansion allows custom methods to be added to
 // MultiClusterServiceNamespaceLister.
 type MultiClusterServiceNamespaceListerExpansion interface{}
+
+// SidecarConfigListerExpansion allows custom methods to be added to
+// SidecarConfigLister.
+type SidecarConfigListerExpansion interface{}
+
+// SidecarConfigNamespaceListerExpansion allows custom methods to be added to
+// SidecarConfigNamespaceLister.
+type SidecarConfigNamespaceListerExpansion interface{}
@@ -0,0 +1,96 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SidecarConfigLister helps list SidecarConfigs.
+// All objects returned here must be treated as read-only.
+type SidecarConfigLister interface {
+	// List lists all SidecarConfigs in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.SidecarConfig, err error)
+	// SidecarConfigs returns an object that can list and get SidecarConfigs.
+	SidecarConfigs(namespace string) SidecarConfigNamespaceLister
+	SidecarConfigListerExpansion
+}
+
+// sidecarConfigLister implements the SidecarConfigLister interface.
+type sidecarConfigLister struct {
+	indexer cache.Indexer
+}
+
+// NewSidecarConfigLister returns a new SidecarConfigLister.
+func NewSidecarConfigLister(indexer cache.Indexer) SidecarConfigLister {
+	return &sidecarConfigLister{indexer: indexer}
+}
+
+// List lists all SidecarConfigs in the indexer.
+func (s *sidecarConfigLister) List(selector labels.Selector) (ret []*v1alpha1.SidecarConfig, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.SidecarConfig))
+	})
+	return ret, err
+}
+
+// SidecarConfigs returns an object that can list and get SidecarConfigs.
+func (s *sidecarConfigLister) SidecarConfigs(namespace string) SidecarConfigNamespaceLister {
+	return sidecarConfigNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// SidecarConfigNamespaceLister helps list and get SidecarConfigs.
+// All objects returned here must be treated as read-only.
+type SidecarConfigNamespaceLister interface {
+	// List lists all SidecarConfigs in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.SidecarConfig, err error)
+	// Get retrieves the SidecarConfig from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.SidecarConfig, error)
+	SidecarConfigNamespaceListerExpansion
+}
+
+// sidecarConfigNamespaceLister implements the SidecarConfigNamespaceLister
+// interface.
+type sidecarConfigNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all SidecarConfigs in the indexer for a given namespace.
+func (s sidecarConfigNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.SidecarConfig, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.SidecarConfig))
+	})
+	return ret, err
+}
+
+// Get retrieves the SidecarConfig from the indexer for a given namespace and name.
+func (s sidecarConfigNamespaceLister) Get(name string) (*v1alpha1.SidecarConfig, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("sidecarconfig"), name)
+	}
+	return obj.(*v1alpha1.SidecarConfig), nil
+}
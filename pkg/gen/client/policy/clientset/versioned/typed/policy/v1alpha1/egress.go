@@ -37,6 +37,7 @@ type EgressesGetter interface {
 type EgressInterface interface {
 	Create(ctx context.Context, egress *v1alpha1.Egress, opts v1.CreateOptions) (*v1alpha1.Egress, error)
 	Update(ctx context.Context, egress *v1alpha1.Egress, opts v1.UpdateOptions) (*v1alpha1.Egress, error)
+	UpdateStatus(ctx context.Context, egress *v1alpha1.Egress, opts v1.UpdateOptions) (*v1alpha1.Egress, error)
 	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Egress, error)
@@ -132,6 +133,22 @@ func (c *egresses) Update(ctx context.Context, egress *v1alpha1.Egress, opts v1.
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *egresses) UpdateStatus(ctx context.Context, egress *v1alpha1.Egress, opts v1.UpdateOptions) (result *v1alpha1.Egress, err error) {
+	result = &v1alpha1.Egress{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("egresses").
+		Name(egress.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(egress).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the egress and deletes it. Returns an error if one occurs.
 func (c *egresses) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
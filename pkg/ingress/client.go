@@ -2,18 +2,61 @@ package ingress
 
 import (
 	"github.com/pkg/errors"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 
+	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
-// Initialize initializes the client and starts the ingress gateway certificate manager routine
+const (
+	// ingressClassAnnotation is the deprecated annotation used to specify the IngressClass of an Ingress.
+	// For backwards compatibility, it takes precedence over the IngressClassName field when set.
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+)
+
+// Initialize initializes the client, starts the ingress gateway certificate manager routine and returns
+// the Monitor for Kubernetes Ingress resources
 func Initialize(kubeClient kubernetes.Interface, kubeController k8s.Controller, stop chan struct{},
-	cfg configurator.Configurator, certProvider certificate.Manager, msgBroker *messaging.Broker) error {
+	cfg configurator.Configurator, certProvider certificate.Manager, msgBroker *messaging.Broker) (Monitor, error) {
+	c := newClient(kubeClient, kubeController, cfg, certProvider, msgBroker)
+
+	if err := c.run(stop); err != nil {
+		return nil, errors.Errorf("Could not start %s informer clients: %s", networkingV1.SchemeGroupVersion, err)
+	}
+
+	if err := c.provisionIngressGatewayCert(stop); err != nil {
+		return nil, errors.Wrap(err, "Error provisioning ingress gateway certificate")
+	}
+
+	return c, nil
+}
+
+func newClient(kubeClient kubernetes.Interface, kubeController k8s.Controller, cfg configurator.Configurator,
+	certProvider certificate.Manager, msgBroker *messaging.Broker) *client {
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, k8s.DefaultKubeEventResyncInterval)
+
+	informerCollection := informerCollection{
+		ingress:      informerFactory.Networking().V1().Ingresses().Informer(),
+		ingressClass: informerFactory.Networking().V1().IngressClasses().Informer(),
+	}
+
+	cacheCollection := cacheCollection{
+		ingress:      informerCollection.ingress.GetStore(),
+		ingressClass: informerCollection.ingressClass.GetStore(),
+	}
+
 	c := &client{
+		informers:      &informerCollection,
+		caches:         &cacheCollection,
 		kubeClient:     kubeClient,
 		kubeController: kubeController,
 		cfg:            cfg,
@@ -21,9 +64,159 @@ func Initialize(kubeClient kubernetes.Interface, kubeController k8s.Controller,
 		msgBroker:      msgBroker,
 	}
 
-	if err := c.provisionIngressGatewayCert(stop); err != nil {
-		return errors.Wrap(err, "Error provisioning ingress gateway certificate")
+	shouldObserve := func(obj interface{}) bool {
+		object, ok := obj.(metav1.Object)
+		if !ok {
+			return false
+		}
+		return kubeController.IsMonitoredNamespace(object.GetNamespace())
+	}
+
+	// IngressClass is a cluster scoped resource
+	shouldObserveIngressClass := func(obj interface{}) bool {
+		ingressClass, ok := obj.(*networkingV1.IngressClass)
+		if !ok {
+			return false
+		}
+		return isMonitoredIngressClass(ingressClass)
+	}
+
+	ingressEventTypes := k8s.EventTypes{
+		Add:    announcements.IngressAdded,
+		Update: announcements.IngressUpdated,
+		Delete: announcements.IngressDeleted,
+	}
+	informerCollection.ingress.AddEventHandler(k8s.GetEventHandlerFuncs(shouldObserve, ingressEventTypes, msgBroker))
+	ingressClassEventTypes := k8s.EventTypes{
+		Add:    announcements.IngressClassAdded,
+		Update: announcements.IngressClassUpdated,
+		Delete: announcements.IngressClassDeleted,
+	}
+	informerCollection.ingressClass.AddEventHandler(k8s.GetEventHandlerFuncs(shouldObserveIngressClass, ingressClassEventTypes, msgBroker))
+
+	return c
+}
+
+func (c *client) run(stop <-chan struct{}) error {
+	log.Info().Msgf("Starting informer clients for API group %s", networkingV1.SchemeGroupVersion)
+
+	if c.informers == nil {
+		return errInitInformers
+	}
+
+	sharedInformers := map[string]cache.SharedInformer{
+		"Ingress":      c.informers.ingress,
+		"IngressClass": c.informers.ingressClass,
+	}
+
+	var informerNames []string
+	var hasSynced []cache.InformerSynced
+	for name, informer := range sharedInformers {
+		if informer == nil {
+			log.Error().Msgf("Informer for '%s' not initialized, ignoring it", name) // TODO: log with errcode
+			continue
+		}
+		informerNames = append(informerNames, name)
+		log.Info().Msgf("Starting informer: %s", name)
+		go informer.Run(stop)
+		hasSynced = append(hasSynced, informer.HasSynced)
 	}
 
+	log.Info().Msgf("Waiting for informers %v caches to sync", informerNames)
+	if !cache.WaitForCacheSync(stop, hasSynced...) {
+		return errSyncingCaches
+	}
+
+	log.Info().Msgf("Cache sync finished for %v informers in API group %s", informerNames, networkingV1.SchemeGroupVersion)
 	return nil
 }
+
+// GetIngressNetworkingV1 returns the networking.k8s.io/v1 Ingress resources whose backends correspond to the
+// given service, and whose IngressClass is annotated to be monitored by OSM
+func (c *client) GetIngressNetworkingV1(meshService service.MeshService) []*networkingV1.Ingress {
+	if !c.kubeController.IsMonitoredNamespace(meshService.Namespace) {
+		return nil
+	}
+
+	var ingresses []*networkingV1.Ingress
+	for _, ingressIface := range c.caches.ingress.List() {
+		ingress := ingressIface.(*networkingV1.Ingress)
+
+		if ingress.Namespace != meshService.Namespace {
+			continue
+		}
+		if c.GetIngressClass(ingress) == nil {
+			continue
+		}
+		if !isIngressBackend(ingress, meshService.Name) {
+			continue
+		}
+
+		ingresses = append(ingresses, ingress)
+	}
+
+	return ingresses
+}
+
+// GetIngressClass returns the IngressClass of the given Ingress if it is annotated to be monitored by OSM,
+// nil otherwise
+func (c *client) GetIngressClass(ingress *networkingV1.Ingress) *networkingV1.IngressClass {
+	className := ingress.Annotations[ingressClassAnnotation]
+	if className == "" && ingress.Spec.IngressClassName != nil {
+		className = *ingress.Spec.IngressClassName
+	}
+
+	var ingressClass *networkingV1.IngressClass
+	if className != "" {
+		ingressClassIface, exists, err := c.caches.ingressClass.GetByKey(className)
+		if err != nil || !exists {
+			return nil
+		}
+		ingressClass = ingressClassIface.(*networkingV1.IngressClass)
+	} else {
+		// Ingress resources without a class belong to the default IngressClass
+		ingressClass = c.getDefaultIngressClass()
+	}
+
+	if ingressClass == nil || !isMonitoredIngressClass(ingressClass) {
+		return nil
+	}
+	return ingressClass
+}
+
+// getDefaultIngressClass returns the IngressClass marked as default, nil if there is none
+func (c *client) getDefaultIngressClass() *networkingV1.IngressClass {
+	for _, ingressClassIface := range c.caches.ingressClass.List() {
+		ingressClass := ingressClassIface.(*networkingV1.IngressClass)
+		if ingressClass.Annotations[networkingV1.AnnotationIsDefaultIngressClass] == "true" {
+			return ingressClass
+		}
+	}
+	return nil
+}
+
+// isMonitoredIngressClass returns true if the Ingress resources of the given IngressClass are monitored by OSM
+func isMonitoredIngressClass(ingressClass *networkingV1.IngressClass) bool {
+	_, ok := ingressClass.Annotations[constants.IngressBackendProtocolAnnotation]
+	return ok
+}
+
+// isIngressBackend returns true if the service with the given name is a backend of the given Ingress
+func isIngressBackend(ingress *networkingV1.Ingress, serviceName string) bool {
+	if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil && backend.Service.Name == serviceName {
+		return true
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil && path.Backend.Service.Name == serviceName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package ingress
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestGetIngressNetworkingV1(t *testing.T) {
+	a := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(false).AnyTimes()
+
+	c := newClient(fake.NewSimpleClientset(), mockKubeController, nil, nil, nil)
+
+	monitoredClass := &networkingV1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nginx",
+			Annotations: map[string]string{constants.IngressBackendProtocolAnnotation: "http"},
+		},
+	}
+	unmonitoredClass := &networkingV1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+	}
+	_ = c.caches.ingressClass.Add(monitoredClass)
+	_ = c.caches.ingressClass.Add(unmonitoredClass)
+
+	newIngress := func(name, className string, backendName string) *networkingV1.Ingress {
+		return &networkingV1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec: networkingV1.IngressSpec{
+				IngressClassName: &className,
+				Rules: []networkingV1.IngressRule{
+					{
+						Host: "foo.com",
+						IngressRuleValue: networkingV1.IngressRuleValue{
+							HTTP: &networkingV1.HTTPIngressRuleValue{
+								Paths: []networkingV1.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: networkingV1.IngressBackend{
+											Service: &networkingV1.IngressServiceBackend{
+												Name: backendName,
+												Port: networkingV1.ServiceBackendPort{Number: 80},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	monitoredIngress := newIngress("monitored", "nginx", "foo")
+	legacyAnnotatedIngress := newIngress("legacy", "", "foo")
+	legacyAnnotatedIngress.Spec.IngressClassName = nil
+	legacyAnnotatedIngress.Annotations = map[string]string{ingressClassAnnotation: "nginx"}
+	defaultBackendIngress := &networkingV1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-backend", Namespace: "test", Annotations: map[string]string{ingressClassAnnotation: "nginx"}},
+		Spec: networkingV1.IngressSpec{
+			DefaultBackend: &networkingV1.IngressBackend{
+				Service: &networkingV1.IngressServiceBackend{Name: "foo", Port: networkingV1.ServiceBackendPort{Number: 80}},
+			},
+		},
+	}
+	otherBackendIngress := newIngress("other-backend", "nginx", "bar")
+	unmonitoredClassIngress := newIngress("unmonitored-class", "other", "foo")
+	unknownClassIngress := newIngress("unknown-class", "unknown", "foo")
+	noClassIngress := newIngress("no-class", "", "foo")
+	noClassIngress.Spec.IngressClassName = nil
+	for _, ingress := range []*networkingV1.Ingress{monitoredIngress, legacyAnnotatedIngress, defaultBackendIngress, otherBackendIngress,
+		unmonitoredClassIngress, unknownClassIngress, noClassIngress} {
+		_ = c.caches.ingress.Add(ingress)
+	}
+
+	svc := service.MeshService{Name: "foo", Namespace: "test", Port: 80, TargetPort: 8080}
+	a.ElementsMatch([]*networkingV1.Ingress{monitoredIngress, legacyAnnotatedIngress, defaultBackendIngress}, c.GetIngressNetworkingV1(svc))
+
+	// Services in unmonitored namespaces are ignored
+	a.Empty(c.GetIngressNetworkingV1(service.MeshService{Name: "foo", Namespace: "unmonitored", Port: 80}))
+
+	// Ingress resources without a class belong to the default IngressClass
+	a.Nil(c.GetIngressClass(noClassIngress))
+	defaultClass := monitoredClass.DeepCopy()
+	defaultClass.Annotations[networkingV1.AnnotationIsDefaultIngressClass] = "true"
+	_ = c.caches.ingressClass.Update(defaultClass)
+	a.Equal(defaultClass, c.GetIngressClass(noClassIngress))
+	a.Len(c.GetIngressNetworkingV1(svc), 4)
+}
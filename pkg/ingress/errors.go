@@ -0,0 +1,8 @@
+package ingress
+
+import "github.com/pkg/errors"
+
+var (
+	errSyncingCaches = errors.New("Failed initial cache sync for Ingress informers")
+	errInitInformers = errors.New("Ingress informers not initialized")
+)
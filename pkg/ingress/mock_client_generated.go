@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/openservicemesh/osm/pkg/ingress (interfaces: Monitor)
+
+// Package ingress is a generated GoMock package.
+package ingress
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	service "github.com/openservicemesh/osm/pkg/service"
+	v1 "k8s.io/api/networking/v1"
+)
+
+// MockMonitor is a mock of Monitor interface.
+type MockMonitor struct {
+	ctrl     *gomock.Controller
+	recorder *MockMonitorMockRecorder
+}
+
+// MockMonitorMockRecorder is the mock recorder for MockMonitor.
+type MockMonitorMockRecorder struct {
+	mock *MockMonitor
+}
+
+// NewMockMonitor creates a new mock instance.
+func NewMockMonitor(ctrl *gomock.Controller) *MockMonitor {
+	mock := &MockMonitor{ctrl: ctrl}
+	mock.recorder = &MockMonitorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMonitor) EXPECT() *MockMonitorMockRecorder {
+	return m.recorder
+}
+
+// GetIngressClass mocks base method.
+func (m *MockMonitor) GetIngressClass(arg0 *v1.Ingress) *v1.IngressClass {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressClass", arg0)
+	ret0, _ := ret[0].(*v1.IngressClass)
+	return ret0
+}
+
+// GetIngressClass indicates an expected call of GetIngressClass.
+func (mr *MockMonitorMockRecorder) GetIngressClass(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressClass", reflect.TypeOf((*MockMonitor)(nil).GetIngressClass), arg0)
+}
+
+// GetIngressNetworkingV1 mocks base method.
+func (m *MockMonitor) GetIngressNetworkingV1(arg0 service.MeshService) []*v1.Ingress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressNetworkingV1", arg0)
+	ret0, _ := ret[0].([]*v1.Ingress)
+	return ret0
+}
+
+// GetIngressNetworkingV1 indicates an expected call of GetIngressNetworkingV1.
+func (mr *MockMonitorMockRecorder) GetIngressNetworkingV1(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressNetworkingV1", reflect.TypeOf((*MockMonitor)(nil).GetIngressNetworkingV1), arg0)
+}
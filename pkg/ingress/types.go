@@ -2,24 +2,52 @@
 package ingress
 
 import (
+	networkingV1 "k8s.io/api/networking/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
 var (
 	log = logger.New("ingress")
 )
 
+// informerCollection is the type used to represent the collection of informers for the networking.k8s.io API group
+type informerCollection struct {
+	ingress      cache.SharedIndexInformer
+	ingressClass cache.SharedIndexInformer
+}
+
+// cacheCollection is the type used to represent the collection of caches for the networking.k8s.io API group
+type cacheCollection struct {
+	ingress      cache.Store
+	ingressClass cache.Store
+}
+
 // client is a struct for all components necessary to connect to and maintain state of a Kubernetes cluster.
 type client struct {
+	informers      *informerCollection
+	caches         *cacheCollection
 	kubeClient     kubernetes.Interface
 	kubeController k8s.Controller
 	cfg            configurator.Configurator
 	certProvider   certificate.Manager
 	msgBroker      *messaging.Broker
 }
+
+// Monitor is the client interface for K8s Ingress resource
+type Monitor interface {
+	// GetIngressNetworkingV1 returns the networking.k8s.io/v1 Ingress resources whose backends correspond to the
+	// given service, and whose IngressClass is annotated to be monitored by OSM
+	GetIngressNetworkingV1(service.MeshService) []*networkingV1.Ingress
+
+	// GetIngressClass returns the IngressClass of the given Ingress if it is annotated to be monitored by OSM,
+	// nil otherwise
+	GetIngressClass(*networkingV1.Ingress) *networkingV1.IngressClass
+}
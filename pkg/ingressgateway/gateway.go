@@ -0,0 +1,50 @@
+// Package ingressgateway implements the helpers for the ingress gateway. When the ingress gateway is enabled, OSM
+// programs its own Envoy based ingress gateway using the Kubernetes Gateway API resources (Gateway, HTTPRoute, TLSRoute)
+// whose GatewayClass is managed by OSM, and the gateway proxies the ingress traffic to mesh backends over mTLS.
+package ingressgateway
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+const (
+	// ServiceName is the name of the ingress gateway's Kubernetes service
+	ServiceName = "osm-ingress-gateway"
+
+	// ControllerName is the controller name a GatewayClass must specify for its Gateways to be programmed on the ingress gateway
+	ControllerName = "openservicemesh.io/gateway-controller"
+)
+
+// GetIngressGatewaySubjectCommonName creates a unique certificate.CommonName
+// specifically for an Ingress Gateway. Each gateway will have its own unique
+// cert. The kind of Envoy (ingress gateway) is encoded in the cert CN by convention.
+func GetIngressGatewaySubjectCommonName(serviceAccount, namespace string) certificate.CommonName {
+	gatewayUID := uuid.New()
+	envoyType := envoy.KindIngressGateway
+	return envoy.NewXDSCertCommonName(gatewayUID, envoyType, serviceAccount, namespace)
+}
+
+// GetClientCertificateSpec returns the common name and validity duration of the certificate presented by the ingress
+// gateway to mesh backends. This is the certificate specified by the MeshConfig's ingress gateway certificate spec,
+// so that backends authorize the ingress gateway using its common name as the authenticated principal.
+func GetClientCertificateSpec(cfg configurator.Configurator) (certificate.CommonName, time.Duration, error) {
+	certSpec := cfg.GetMeshConfig().Spec.Certificate.IngressGateway
+	if certSpec == nil || len(certSpec.SubjectAltNames) == 0 {
+		return "", 0, errors.New("Ingress gateway certificate spec is not configured in the MeshConfig")
+	}
+
+	validityDuration, err := time.ParseDuration(certSpec.ValidityDuration)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "Invalid ingress gateway cert duration '%s' specified", certSpec.ValidityDuration)
+	}
+
+	// OSM only supports configuring a single SAN per cert, so pick the first one
+	return certificate.CommonName(certSpec.SubjectAltNames[0]), validityDuration, nil
+}
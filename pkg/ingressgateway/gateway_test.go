@@ -0,0 +1,31 @@
+package ingressgateway
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestIngressGatewayHelpers(t *testing.T) {
+	assert := tassert.New(t)
+	serviceAccount := "-svc-account-"
+	namespace := "-namespace-"
+
+	actualCN := GetIngressGatewaySubjectCommonName(serviceAccount, namespace)
+	expectedSuffix := ".ingress-gateway.-svc-account-.-namespace-.cluster.local"
+	assert.True(strings.HasSuffix(actualCN.String(), expectedSuffix), fmt.Sprintf("Expected the Proxy Cert's Common Name to end with %s", expectedSuffix))
+
+	// Is the kind of proxy properly encoded in this certificate?
+	actualProxyKind, err := envoy.GetKindFromProxyCertificate(actualCN)
+	assert.Nil(err)
+	assert.Equal(envoy.KindIngressGateway, actualProxyKind)
+
+	// Is the service identity properly encoded in this certificate?
+	actualIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(actualCN)
+	assert.Nil(err)
+	assert.Equal("-svc-account-.-namespace-.cluster.local", actualIdentity.String())
+}
@@ -0,0 +1,144 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// BootstrapSecretCleanupLeaseName is the name of the Lease used to elect the injector replica garbage collecting
+	// the bootstrap config secrets
+	BootstrapSecretCleanupLeaseName = "osm-bootstrap-secret-cleanup"
+
+	// bootstrapSecretCleanupInterval is the interval at which the orphaned bootstrap config secrets are garbage collected
+	bootstrapSecretCleanupInterval = 5 * time.Minute
+
+	// bootstrapSecretGracePeriod is the minimum age of a bootstrap config secret without a pod before it is deleted,
+	// for the secrets created for pods that are still being admitted not to be deleted
+	bootstrapSecretGracePeriod = 10 * time.Minute
+
+	bootstrapSecretLive     = "live"
+	bootstrapSecretOrphaned = "orphaned"
+)
+
+// bootstrapSecretCleanup garbage collects the Envoy bootstrap config secrets created by the webhook. The secrets of the
+// existing pods are owned by their pod, for Kubernetes to delete them with the pod, and the secrets whose pod does not
+// exist, such as the secrets created for pods whose admission failed after the webhook, are deleted.
+type bootstrapSecretCleanup struct {
+	wh *mutatingWebhook
+
+	// now returns the current time
+	now func() time.Time
+}
+
+// runBootstrapSecretCleanup garbage collects the orphaned bootstrap config secrets until the given channel is closed
+func (wh *mutatingWebhook) runBootstrapSecretCleanup(stop <-chan struct{}) {
+	c := &bootstrapSecretCleanup{
+		wh:  wh,
+		now: time.Now,
+	}
+
+	ticker := time.NewTicker(bootstrapSecretCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.reconcile()
+		}
+	}
+}
+
+// reconcile sets the OwnerReference of the bootstrap config secrets of the existing pods, deletes the orphaned ones,
+// and records the number of live and orphaned secrets
+func (c *bootstrapSecretCleanup) reconcile() {
+	namespaces, err := c.wh.kubeController.ListMonitoredNamespaces()
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing the monitored namespaces to garbage collect bootstrap config secrets")
+		return
+	}
+
+	// The pods are listed from the informer cache, the secrets created for pods that are not cached yet being
+	// within their grace period
+	podsByUUID := make(map[string]*corev1.Pod)
+	for _, pod := range c.wh.kubeController.ListPods() {
+		if proxyUUID, ok := pod.Labels[constants.EnvoyUniqueIDLabelName]; ok {
+			podsByUUID[proxyUUID] = pod
+		}
+	}
+
+	var live, orphaned int
+	for _, namespace := range namespaces {
+		l, o, err := c.reconcileNamespace(namespace, podsByUUID)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error garbage collecting the bootstrap config secrets of namespace %s", namespace)
+		}
+		live += l
+		orphaned += o
+	}
+
+	metricsstore.DefaultMetricsStore.InjectorBootstrapSecretCount.WithLabelValues(bootstrapSecretLive).Set(float64(live))
+	metricsstore.DefaultMetricsStore.InjectorBootstrapSecretCount.WithLabelValues(bootstrapSecretOrphaned).Set(float64(orphaned))
+}
+
+// reconcileNamespace garbage collects the bootstrap config secrets of the given namespace, given the pods keyed by
+// their proxy UUID, and returns the number of live and orphaned secrets in the namespace. The orphaned secrets within
+// their grace period are counted but kept.
+func (c *bootstrapSecretCleanup) reconcileNamespace(namespace string, podsByUUID map[string]*corev1.Pod) (live int, orphaned int, err error) {
+	secrets, err := c.wh.kubeClient.CoreV1().Secrets(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			constants.OSMAppNameLabelKey:     constants.OSMAppNameLabelValue,
+			constants.OSMAppInstanceLabelKey: c.wh.meshName,
+		}).String(),
+	})
+	if err != nil {
+		return 0, 0, errors.Errorf("Error listing secrets: %s", err)
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !strings.HasPrefix(secret.Name, constants.EnvoyBootstrapConfigSecretPrefix) {
+			continue
+		}
+
+		if pod, ok := podsByUUID[strings.TrimPrefix(secret.Name, constants.EnvoyBootstrapConfigSecretPrefix)]; ok && pod.Namespace == namespace {
+			live++
+			// The controller sets the OwnerReference when the pod is added, unless it misses the event
+			if updated, err := k8s.SetProxyBootstrapSecretOwner(c.wh.kubeClient, secret, pod); err != nil {
+				log.Error().Err(err).Msgf("Error updating OwnerReference for Secret %s/%s to reference Pod %s/%s", namespace, secret.Name, namespace, pod.Name)
+			} else if updated {
+				log.Debug().Msgf("Updated OwnerReference for Secret %s/%s to reference Pod %s/%s", namespace, secret.Name, namespace, pod.Name)
+			}
+			continue
+		}
+
+		orphaned++
+		if c.now().Sub(secret.CreationTimestamp.Time) < bootstrapSecretGracePeriod {
+			continue
+		}
+		if err := c.wh.kubeClient.CoreV1().Secrets(namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Error().Err(err).Msgf("Error deleting orphaned bootstrap config Secret %s/%s", namespace, secret.Name)
+			}
+			continue
+		}
+		orphaned--
+		metricsstore.DefaultMetricsStore.InjectorBootstrapSecretDeleteCount.Inc()
+		log.Info().Msgf("Deleted orphaned bootstrap config Secret %s/%s", namespace, secret.Name)
+	}
+
+	return live, orphaned, nil
+}
@@ -0,0 +1,117 @@
+package injector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+func TestBootstrapSecretCleanup(t *testing.T) {
+	assert := tassert.New(t)
+
+	const (
+		namespace = "ns"
+		meshName  = "osm"
+	)
+	now := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	newSecret := func(name string, age time.Duration, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+	}
+	meshLabels := map[string]string{
+		constants.OSMAppNameLabelKey:     constants.OSMAppNameLabelValue,
+		constants.OSMAppInstanceLabelKey: meshName,
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bookstore",
+			Namespace: namespace,
+			UID:       types.UID("pod-uid"),
+			Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: "live"},
+		},
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		pod,
+		newSecret(constants.EnvoyBootstrapConfigSecretPrefix+"live", time.Hour, meshLabels),
+		newSecret(constants.EnvoyBootstrapConfigSecretPrefix+"orphaned", time.Hour, meshLabels),
+		newSecret(constants.EnvoyBootstrapConfigSecretPrefix+"admitting", time.Minute, meshLabels),
+		newSecret(constants.EnvoyBootstrapConfigSecretPrefix+"other-mesh", time.Hour, map[string]string{
+			constants.OSMAppNameLabelKey:     constants.OSMAppNameLabelValue,
+			constants.OSMAppInstanceLabelKey: "other",
+		}),
+		newSecret("app-secret", time.Hour, meshLabels),
+	)
+
+	mockCtrl := gomock.NewController(t)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().ListMonitoredNamespaces().Return([]string{namespace}, nil)
+	mockKubeController.EXPECT().ListPods().Return([]*corev1.Pod{
+		pod,
+		// A pod in another namespace does not keep the secret of the same proxy UUID alive
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other",
+				Namespace: "other",
+				Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: "orphaned"},
+			},
+		},
+		// A pod without sidecar
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-sidecar", Namespace: namespace}},
+	})
+
+	c := &bootstrapSecretCleanup{
+		wh: &mutatingWebhook{
+			kubeClient:     kubeClient,
+			kubeController: mockKubeController,
+			meshName:       meshName,
+		},
+		now: func() time.Time { return now },
+	}
+
+	deleteCount := testutil.ToFloat64(metricsstore.DefaultMetricsStore.InjectorBootstrapSecretDeleteCount)
+	c.reconcile()
+
+	secrets, err := kubeClient.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(err)
+	var names []string
+	for _, secret := range secrets.Items {
+		names = append(names, secret.Name)
+	}
+	// The orphaned secret past its grace period is deleted
+	assert.ElementsMatch([]string{
+		constants.EnvoyBootstrapConfigSecretPrefix + "live",
+		constants.EnvoyBootstrapConfigSecretPrefix + "admitting",
+		constants.EnvoyBootstrapConfigSecretPrefix + "other-mesh",
+		"app-secret",
+	}, names)
+
+	// The secret of the existing pod is owned by the pod
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), constants.EnvoyBootstrapConfigSecretPrefix+"live", metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal([]metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: pod.Name, UID: pod.UID}}, secret.OwnerReferences)
+
+	assert.Equal(deleteCount+1, testutil.ToFloat64(metricsstore.DefaultMetricsStore.InjectorBootstrapSecretDeleteCount))
+	assert.Equal(1.0, testutil.ToFloat64(metricsstore.DefaultMetricsStore.InjectorBootstrapSecretCount.WithLabelValues(bootstrapSecretLive)))
+	assert.Equal(1.0, testutil.ToFloat64(metricsstore.DefaultMetricsStore.InjectorBootstrapSecretCount.WithLabelValues(bootstrapSecretOrphaned)))
+}
@@ -3,6 +3,7 @@ package injector
 import (
 	"context"
 	"fmt"
+	"net"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +30,8 @@ func getEnvoyConfigYAML(config envoyBootstrapConfigMeta, cfg configurator.Config
 		PrivateKey:       config.Key,
 		XDSHost:          config.XDSHost,
 		XDSPort:          config.XDSPort,
+		StatsTags:        config.StatsTags,
+		AdminAddress:     config.LocalhostIPAddress,
 	})
 	if err != nil {
 		log.Error().Err(err).Msgf("Error building Envoy boostrap config")
@@ -61,41 +64,41 @@ func getProbeResources(config envoyBootstrapConfigMeta) ([]*xds_listener.Listene
 
 	// Is there a liveness probe in the Pod Spec?
 	if config.OriginalHealthProbes.liveness != nil {
-		listener, err := getLivenessListener(config.OriginalHealthProbes.liveness)
+		listener, err := getLivenessListener(config.OriginalHealthProbes.liveness, config.EnableIPv6)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error getting liveness listener")
 			return nil, nil, err
 		}
 		listeners = append(listeners, listener)
-		clusters = append(clusters, getLivenessCluster(config.OriginalHealthProbes.liveness))
+		clusters = append(clusters, getLivenessCluster(config.OriginalHealthProbes.liveness, config.LocalhostIPAddress))
 	}
 
 	// Is there a readiness probe in the Pod Spec?
 	if config.OriginalHealthProbes.readiness != nil {
-		listener, err := getReadinessListener(config.OriginalHealthProbes.readiness)
+		listener, err := getReadinessListener(config.OriginalHealthProbes.readiness, config.EnableIPv6)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error getting readiness listener")
 			return nil, nil, err
 		}
 		listeners = append(listeners, listener)
-		clusters = append(clusters, getReadinessCluster(config.OriginalHealthProbes.readiness))
+		clusters = append(clusters, getReadinessCluster(config.OriginalHealthProbes.readiness, config.LocalhostIPAddress))
 	}
 
 	// Is there a startup probe in the Pod Spec?
 	if config.OriginalHealthProbes.startup != nil {
-		listener, err := getStartupListener(config.OriginalHealthProbes.startup)
+		listener, err := getStartupListener(config.OriginalHealthProbes.startup, config.EnableIPv6)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error getting startup listener")
 			return nil, nil, err
 		}
 		listeners = append(listeners, listener)
-		clusters = append(clusters, getStartupCluster(config.OriginalHealthProbes.startup))
+		clusters = append(clusters, getStartupCluster(config.OriginalHealthProbes.startup, config.LocalhostIPAddress))
 	}
 
 	return listeners, clusters, nil
 }
 
-func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamespace string, cert certificate.Certificater, originalHealthProbes healthProbes) (*corev1.Secret, error) {
+func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamespace string, cert certificate.Certificater, originalHealthProbes healthProbes, statsTags map[string]string) (*corev1.Secret, error) {
 	configMeta := envoyBootstrapConfigMeta{
 		EnvoyAdminPort: constants.EnvoyAdminPort,
 		XDSClusterName: constants.OSMControllerName,
@@ -111,6 +114,11 @@ func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamesp
 		// OriginalHealthProbes stores the path and port for liveness, readiness, and startup health probes as initially
 		// defined on the Pod Spec.
 		OriginalHealthProbes: originalHealthProbes,
+
+		StatsTags: statsTags,
+
+		LocalhostIPAddress: wh.getLocalhostIPAddress(),
+		EnableIPv6:         wh.configurator.GetFeatureFlags().EnableIPv6 || utils.IsIPv6(net.ParseIP(wh.config.PodIP)),
 	}
 	yamlContent, err := getEnvoyConfigYAML(configMeta, wh.configurator)
 	if err != nil {
@@ -140,3 +148,9 @@ func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamesp
 	log.Debug().Msgf("Creating bootstrap config for Envoy: name=%s, namespace=%s", name, namespace)
 	return wh.kubeClient.CoreV1().Secrets(namespace).Create(context.Background(), secret, metav1.CreateOptions{})
 }
+
+// getLocalhostIPAddress returns the loopback IP address of the primary IP family of the pods, which is the IP family
+// of the pod of the sidecar injector
+func (wh *mutatingWebhook) getLocalhostIPAddress() string {
+	return utils.GetLocalhostIPAddress(net.ParseIP(wh.config.PodIP))
+}
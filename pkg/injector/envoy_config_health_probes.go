@@ -7,7 +7,6 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/protobuf/types/known/structpb"
 
-	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/errcode"
 
@@ -32,28 +31,28 @@ const (
 	startupListener   = "startup_listener"
 )
 
-func getLivenessCluster(originalProbe *healthProbe) *xds_cluster.Cluster {
+func getLivenessCluster(originalProbe *healthProbe, localhostIPAddress string) *xds_cluster.Cluster {
 	if originalProbe == nil {
 		return nil
 	}
-	return getProbeCluster(livenessCluster, originalProbe.port)
+	return getProbeCluster(livenessCluster, originalProbe.port, localhostIPAddress)
 }
 
-func getReadinessCluster(originalProbe *healthProbe) *xds_cluster.Cluster {
+func getReadinessCluster(originalProbe *healthProbe, localhostIPAddress string) *xds_cluster.Cluster {
 	if originalProbe == nil {
 		return nil
 	}
-	return getProbeCluster(readinessCluster, originalProbe.port)
+	return getProbeCluster(readinessCluster, originalProbe.port, localhostIPAddress)
 }
 
-func getStartupCluster(originalProbe *healthProbe) *xds_cluster.Cluster {
+func getStartupCluster(originalProbe *healthProbe, localhostIPAddress string) *xds_cluster.Cluster {
 	if originalProbe == nil {
 		return nil
 	}
-	return getProbeCluster(startupCluster, originalProbe.port)
+	return getProbeCluster(startupCluster, originalProbe.port, localhostIPAddress)
 }
 
-func getProbeCluster(clusterName string, port int32) *xds_cluster.Cluster {
+func getProbeCluster(clusterName string, port int32, localhostIPAddress string) *xds_cluster.Cluster {
 	return &xds_cluster.Cluster{
 		Name: clusterName,
 		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
@@ -71,7 +70,7 @@ func getProbeCluster(clusterName string, port int32) *xds_cluster.Cluster {
 									Address: &xds_core.Address{
 										Address: &xds_core.Address_SocketAddress{
 											SocketAddress: &xds_core.SocketAddress{
-												Address: constants.LocalhostIPAddress,
+												Address: localhostIPAddress,
 												PortSpecifier: &xds_core.SocketAddress_PortValue{
 													PortValue: uint32(port),
 												},
@@ -88,28 +87,28 @@ func getProbeCluster(clusterName string, port int32) *xds_cluster.Cluster {
 	}
 }
 
-func getLivenessListener(originalProbe *healthProbe) (*xds_listener.Listener, error) {
+func getLivenessListener(originalProbe *healthProbe, enableIPv6 bool) (*xds_listener.Listener, error) {
 	if originalProbe == nil {
 		return nil, nil
 	}
-	return getProbeListener(livenessListener, livenessCluster, livenessProbePath, livenessProbePort, originalProbe)
+	return getProbeListener(livenessListener, livenessCluster, livenessProbePath, livenessProbePort, originalProbe, enableIPv6)
 }
 
-func getReadinessListener(originalProbe *healthProbe) (*xds_listener.Listener, error) {
+func getReadinessListener(originalProbe *healthProbe, enableIPv6 bool) (*xds_listener.Listener, error) {
 	if originalProbe == nil {
 		return nil, nil
 	}
-	return getProbeListener(readinessListener, readinessCluster, readinessProbePath, readinessProbePort, originalProbe)
+	return getProbeListener(readinessListener, readinessCluster, readinessProbePath, readinessProbePort, originalProbe, enableIPv6)
 }
 
-func getStartupListener(originalProbe *healthProbe) (*xds_listener.Listener, error) {
+func getStartupListener(originalProbe *healthProbe, enableIPv6 bool) (*xds_listener.Listener, error) {
 	if originalProbe == nil {
 		return nil, nil
 	}
-	return getProbeListener(startupListener, startupCluster, startupProbePath, startupProbePort, originalProbe)
+	return getProbeListener(startupListener, startupCluster, startupProbePath, startupProbePort, originalProbe, enableIPv6)
 }
 
-func getProbeListener(listenerName, clusterName, newPath string, port int32, originalProbe *healthProbe) (*xds_listener.Listener, error) {
+func getProbeListener(listenerName, clusterName, newPath string, port int32, originalProbe *healthProbe, enableIPv6 bool) (*xds_listener.Listener, error) {
 	var filterChain *xds_listener.FilterChain
 	if originalProbe.isHTTP {
 		httpAccessLog, err := getHTTPAccessLog()
@@ -185,17 +184,8 @@ func getProbeListener(listenerName, clusterName, newPath string, port int32, ori
 	}
 
 	return &xds_listener.Listener{
-		Name: listenerName,
-		Address: &xds_core.Address{
-			Address: &xds_core.Address_SocketAddress{
-				SocketAddress: &xds_core.SocketAddress{
-					Address: "0.0.0.0",
-					PortSpecifier: &xds_core.SocketAddress_PortValue{
-						PortValue: uint32(port),
-					},
-				},
-			},
-		},
+		Name:    listenerName,
+		Address: envoy.GetWildcardAddress(uint32(port), enableIPv6),
 		FilterChains: []*xds_listener.FilterChain{
 			filterChain,
 		},
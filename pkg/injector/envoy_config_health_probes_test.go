@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/reflect/protoreflect"
 
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/injector/test"
 )
 
@@ -31,20 +32,20 @@ var _ = ginkgo.Describe("Test functions creating Envoy config and rewriting the
 		"getVirtualHostsDefault": func() protoreflect.ProtoMessage {
 			return getVirtualHost("/some/path", "-cluster-name-", "/original/probe/path", 0*time.Second)
 		},
-		"getProbeCluster":     func() protoreflect.ProtoMessage { return getProbeCluster("cluster-name", 12341234) },
-		"getLivenessCluster":  func() protoreflect.ProtoMessage { return getLivenessCluster(liveness) },
-		"getReadinessCluster": func() protoreflect.ProtoMessage { return getReadinessCluster(readiness) },
-		"getStartupCluster":   func() protoreflect.ProtoMessage { return getStartupCluster(startup) },
+		"getProbeCluster":     func() protoreflect.ProtoMessage { return getProbeCluster("cluster-name", 12341234, constants.LocalhostIPAddress) },
+		"getLivenessCluster":  func() protoreflect.ProtoMessage { return getLivenessCluster(liveness, constants.LocalhostIPAddress) },
+		"getReadinessCluster": func() protoreflect.ProtoMessage { return getReadinessCluster(readiness, constants.LocalhostIPAddress) },
+		"getStartupCluster":   func() protoreflect.ProtoMessage { return getStartupCluster(startup, constants.LocalhostIPAddress) },
 	}
 
 	listenerFunctionsToTest := map[string]func() (protoreflect.ProtoMessage, error){
 		"getHTTPAccessLog":           func() (protoreflect.ProtoMessage, error) { return getHTTPAccessLog() },
 		"getTCPAccessLog":            func() (protoreflect.ProtoMessage, error) { return getTCPAccessLog() },
-		"getProbeListener":           func() (protoreflect.ProtoMessage, error) { return getProbeListener("a", "b", "c", 9, liveness) },
-		"getLivenessListener":        func() (protoreflect.ProtoMessage, error) { return getLivenessListener(liveness) },
-		"getLivenessListenerNonHTTP": func() (protoreflect.ProtoMessage, error) { return getLivenessListener(livenessNonHTTP) },
-		"getReadinessListener":       func() (protoreflect.ProtoMessage, error) { return getReadinessListener(readiness) },
-		"getStartupListener":         func() (protoreflect.ProtoMessage, error) { return getStartupListener(startup) },
+		"getProbeListener":           func() (protoreflect.ProtoMessage, error) { return getProbeListener("a", "b", "c", 9, liveness, false) },
+		"getLivenessListener":        func() (protoreflect.ProtoMessage, error) { return getLivenessListener(liveness, false) },
+		"getLivenessListenerNonHTTP": func() (protoreflect.ProtoMessage, error) { return getLivenessListener(livenessNonHTTP, false) },
+		"getReadinessListener":       func() (protoreflect.ProtoMessage, error) { return getReadinessListener(readiness, false) },
+		"getStartupListener":         func() (protoreflect.ProtoMessage, error) { return getStartupListener(startup, false) },
 	}
 
 	for fnName, fn := range clusterFunctionsToTest {
@@ -80,7 +81,7 @@ func TestGetProbeCluster(t *testing.T) {
 		}
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
-				assert.Equal(t, test.expected, getLivenessCluster(test.probe))
+				assert.Equal(t, test.expected, getLivenessCluster(test.probe, constants.LocalhostIPAddress))
 			})
 		}
 	})
@@ -93,7 +94,7 @@ func TestGetProbeCluster(t *testing.T) {
 		}
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
-				assert.Equal(t, test.expected, getReadinessCluster(test.probe))
+				assert.Equal(t, test.expected, getReadinessCluster(test.probe, constants.LocalhostIPAddress))
 			})
 		}
 	})
@@ -106,7 +107,7 @@ func TestGetProbeCluster(t *testing.T) {
 		}
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
-				assert.Equal(t, test.expected, getStartupCluster(test.probe))
+				assert.Equal(t, test.expected, getStartupCluster(test.probe, constants.LocalhostIPAddress))
 			})
 		}
 	})
@@ -128,7 +129,7 @@ func TestGetProbeListener(t *testing.T) {
 		}
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
-				actual, err := getLivenessListener(test.probe)
+				actual, err := getLivenessListener(test.probe, false)
 				assert.Equal(t, test.expected, actual)
 				assert.Equal(t, test.err, err)
 			})
@@ -143,7 +144,7 @@ func TestGetProbeListener(t *testing.T) {
 		}
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
-				actual, err := getReadinessListener(test.probe)
+				actual, err := getReadinessListener(test.probe, false)
 				assert.Equal(t, test.expected, actual)
 				assert.Equal(t, test.err, err)
 			})
@@ -158,7 +159,7 @@ func TestGetProbeListener(t *testing.T) {
 		}
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
-				actual, err := getStartupListener(test.probe)
+				actual, err := getStartupListener(test.probe, false)
 				assert.Equal(t, test.expected, actual)
 				assert.Equal(t, test.err, err)
 			})
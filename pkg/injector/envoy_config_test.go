@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"path"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -17,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -118,6 +120,8 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 		XDSPort:        15128,
 
 		OriginalHealthProbes: probes,
+
+		LocalhostIPAddress: constants.LocalhostIPAddress,
 	}
 
 	Context("Test getEnvoyConfigYAML()", func() {
@@ -138,14 +142,16 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 			wh := &mutatingWebhook{
 				kubeClient:          fake.NewSimpleClientset(),
 				kubeController:      k8s.NewMockController(gomock.NewController(GinkgoT())),
+				configurator:        mockConfigurator,
 				nonInjectNamespaces: mapset.NewSet(),
 				meshName:            "some-mesh",
 			}
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).Times(1)
 			name := uuid.New().String()
 			namespace := "a"
 			osmNamespace := "b"
 
-			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes)
+			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes, nil)
 			Expect(err).ToNot(HaveOccurred())
 
 			expected := corev1.Secret{
@@ -195,11 +201,13 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 			wh := &mutatingWebhook{
 				kubeClient:          fake.NewSimpleClientset(existing),
 				kubeController:      k8s.NewMockController(gomock.NewController(GinkgoT())),
+				configurator:        mockConfigurator,
 				nonInjectNamespaces: mapset.NewSet(),
 				meshName:            "some-mesh",
 			}
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).Times(1)
 
-			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes)
+			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes, nil)
 			Expect(err).ToNot(HaveOccurred())
 
 			expected := corev1.Secret{
@@ -239,19 +247,21 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 
 	Context("test unix getEnvoySidecarContainerSpec()", func() {
 		It("creates Envoy sidecar spec", func() {
-			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("debug").Times(1)
 			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(1)
 			mockConfigurator.EXPECT().GetEnvoyWindowsImage().Return(envoyImage).Times(0)
-			mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{
-				// Test set Limits
-				Limits: map[corev1.ResourceName]resource.Quantity{
-					"cpu":    resource.MustParse("2"),
-					"memory": resource.MustParse("512M"),
+			sidecarCfg := &sidecarConfig{
+				logLevel: "debug",
+				resources: corev1.ResourceRequirements{
+					// Test set Limits
+					Limits: map[corev1.ResourceName]resource.Quantity{
+						"cpu":    resource.MustParse("2"),
+						"memory": resource.MustParse("512M"),
+					},
+					// Test unset Requests
+					Requests: nil,
 				},
-				// Test unset Requests
-				Requests: nil,
-			}).Times(1)
-			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, originalHealthProbes, constants.OSLinux)
+			}
+			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, sidecarCfg, originalHealthProbes, constants.OSLinux, constants.LocalhostIPAddress)
 
 			expected := corev1.Container{
 				Name:            constants.EnvoyContainerName,
@@ -360,23 +370,103 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 
 			Expect(actual).To(Equal(expected))
 		})
+
+		It("overrides the Envoy image and concurrency", func() {
+			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(1)
+			sidecarCfg := &sidecarConfig{
+				logLevel:    "info",
+				concurrency: 2,
+				image:       "envoyproxy/envoy-alpine:v1.19.1",
+			}
+			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, sidecarCfg, originalHealthProbes, constants.OSLinux, constants.LocalhostIPAddress)
+
+			Expect(actual.Image).To(Equal("envoyproxy/envoy-alpine:v1.19.1"))
+			Expect(actual.Args).To(Equal([]string{
+				"--log-level", "info",
+				"--config-path", "/etc/envoy/bootstrap.yaml",
+				"--service-cluster", "svcacc.namespace",
+				"--bootstrap-version 3",
+				"--concurrency", "2",
+			}))
+		})
+
+		It("starts Envoy with a shell quitting it once the application exits", func() {
+			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(1)
+			sidecarCfg := &sidecarConfig{
+				logLevel:              "info",
+				quitOnApplicationExit: true,
+			}
+			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, sidecarCfg, originalHealthProbes, constants.OSLinux, constants.LocalhostIPAddress)
+
+			Expect(actual.Command).To(Equal([]string{"sh", "-c", getEnvoyQuitOnApplicationExitCommand(getEnvoyAdminURL(constants.LocalhostIPAddress), false), "envoy"}))
+			Expect(actual.Command[2]).To(ContainSubstring("http://127.0.0.1:15000/quitquitquit"))
+			Expect(actual.Command[2]).ToNot(ContainSubstring("command -v"))
+			Expect(actual.Args).To(ContainElements("--log-level", "info"))
+		})
+
+		It("verifies the tools watching the application containers of a Job pod when the image is overridden", func() {
+			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(1)
+			sidecarCfg := &sidecarConfig{
+				logLevel:              "info",
+				image:                 "envoy-custom",
+				quitOnApplicationExit: true,
+			}
+			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, sidecarCfg, originalHealthProbes, constants.OSLinux, constants.LocalhostIPAddress)
+
+			Expect(actual.Image).To(Equal("envoy-custom"))
+			Expect(actual.Command).To(Equal([]string{"sh", "-c", getEnvoyQuitOnApplicationExitCommand(getEnvoyAdminURL(constants.LocalhostIPAddress), true), "envoy"}))
+			Expect(actual.Command[2]).To(HavePrefix("for tool in readlink wget; do"))
+			Expect(actual.Command[2]).To(ContainSubstring(`exec envoy "$@"`))
+		})
+
+		It("configures the drain duration and holds the application until the Envoy proxy is ready", func() {
+			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(1)
+			sidecarCfg := &sidecarConfig{
+				logLevel:                        "info",
+				drainDuration:                   30 * time.Second,
+				holdApplicationUntilProxyStarts: true,
+			}
+			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, sidecarCfg, originalHealthProbes, constants.OSLinux, constants.LocalhostIPAddress)
+
+			Expect(actual.Args).To(ContainElements("--drain-time-s", "30"))
+			Expect(actual.Lifecycle).ToNot(BeNil())
+			Expect(actual.Lifecycle.PostStart.Exec.Command).To(Equal([]string{"sh", "-c", getEnvoyReadyWaitCommand(getEnvoyAdminURL(constants.LocalhostIPAddress))}))
+			Expect(actual.Lifecycle.PreStop.Exec.Command).To(Equal([]string{"sh", "-c", getEnvoyDrainCommand(getEnvoyAdminURL(constants.LocalhostIPAddress), 30*time.Second)}))
+			Expect(actual.Lifecycle.PreStop.Exec.Command[2]).To(ContainSubstring("drain_listeners?graceful"))
+		})
+
+		It("drains the connections of the Envoy proxy on shutdown when the drain duration is set", func() {
+			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(1)
+			sidecarCfg := &sidecarConfig{
+				logLevel:      "info",
+				drainDuration: 10 * time.Second,
+			}
+			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, sidecarCfg, originalHealthProbes, constants.OSLinux, constants.LocalhostIPAddress)
+
+			Expect(actual.Args).To(ContainElements("--drain-time-s", "10"))
+			Expect(actual.Lifecycle).ToNot(BeNil())
+			Expect(actual.Lifecycle.PostStart).To(BeNil())
+			Expect(actual.Lifecycle.PreStop.Exec.Command).To(Equal([]string{"sh", "-c", getEnvoyDrainCommand(getEnvoyAdminURL(constants.LocalhostIPAddress), 10*time.Second)}))
+		})
 	})
 
 	Context("test Windows getEnvoySidecarContainerSpec()", func() {
 		It("creates Envoy sidecar spec", func() {
-			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("debug").Times(1)
 			mockConfigurator.EXPECT().GetEnvoyWindowsImage().Return(envoyImage).Times(1)
 			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(0)
-			mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{
-				// Test set Limits
-				Limits: map[corev1.ResourceName]resource.Quantity{
-					"cpu":    resource.MustParse("2"),
-					"memory": resource.MustParse("512M"),
+			sidecarCfg := &sidecarConfig{
+				logLevel: "debug",
+				resources: corev1.ResourceRequirements{
+					// Test set Limits
+					Limits: map[corev1.ResourceName]resource.Quantity{
+						"cpu":    resource.MustParse("2"),
+						"memory": resource.MustParse("512M"),
+					},
+					// Test unset Requests
+					Requests: nil,
 				},
-				// Test unset Requests
-				Requests: nil,
-			}).Times(1)
-			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, originalHealthProbes, constants.OSWindows)
+			}
+			actual := getEnvoySidecarContainerSpec(pod, mockConfigurator, sidecarCfg, originalHealthProbes, constants.OSWindows, constants.LocalhostIPAddress)
 
 			expected := corev1.Container{
 				Name:            constants.EnvoyContainerName,
@@ -2,7 +2,10 @@ package injector
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -13,6 +16,15 @@ import (
 const (
 	envoyBootstrapConfigFile = "bootstrap.yaml"
 	envoyProxyConfigPath     = "/etc/envoy"
+
+	// envoyReadyTimeoutSeconds is the maximum duration for which the start of the application containers is held
+	// until the Envoy sidecar is ready, after which the sidecar is restarted
+	envoyReadyTimeoutSeconds = 120
+
+	// envoyDrainTimeoutSeconds is the maximum duration for which the termination of the Envoy sidecar is delayed until
+	// the application has closed its connections, when no drain duration is configured. It matches the default
+	// termination grace period of the pods.
+	envoyDrainTimeoutSeconds = 30
 )
 
 func getPlatformSpecificSpecComponents(cfg configurator.Configurator, podOS string) (podSecurityContext *corev1.SecurityContext, envoyContainer string) {
@@ -38,10 +50,58 @@ func getPlatformSpecificSpecComponents(cfg configurator.Configurator, podOS stri
 	return
 }
 
-func getEnvoySidecarContainerSpec(pod *corev1.Pod, cfg configurator.Configurator, originalHealthProbes healthProbes, podOS string) corev1.Container {
+func getEnvoySidecarContainerSpec(pod *corev1.Pod, cfg configurator.Configurator, sidecarCfg *sidecarConfig, originalHealthProbes healthProbes, podOS string, localhostIPAddress string) corev1.Container {
 	// cluster ID will be used as an identifier to the tracing sink
 	clusterID := fmt.Sprintf("%s.%s", pod.Spec.ServiceAccountName, pod.Namespace)
 	securityContext, containerImage := getPlatformSpecificSpecComponents(cfg, podOS)
+	if sidecarCfg.image != "" {
+		containerImage = sidecarCfg.image
+	}
+
+	args := []string{
+		"--log-level", sidecarCfg.logLevel,
+		"--config-path", strings.Join([]string{envoyProxyConfigPath, envoyBootstrapConfigFile}, "/"),
+		"--service-cluster", clusterID,
+		"--bootstrap-version 3",
+	}
+	if sidecarCfg.concurrency > 0 {
+		args = append(args, "--concurrency", strconv.Itoa(sidecarCfg.concurrency))
+	}
+	if sidecarCfg.drainDuration > 0 {
+		args = append(args, "--drain-time-s", strconv.Itoa(int(sidecarCfg.drainDuration.Seconds())))
+	}
+
+	adminURL := getEnvoyAdminURL(localhostIPAddress)
+	command := []string{"envoy"}
+	if sidecarCfg.quitOnApplicationExit {
+		// Envoy is started by a shell watching the application containers, the arguments being passed to Envoy
+		// The tools the command requires are verified when the image is overridden, as they may not be available
+		command = []string{"sh", "-c", getEnvoyQuitOnApplicationExitCommand(adminURL, sidecarCfg.image != ""), "envoy"}
+	}
+
+	lifecycle := &corev1.Lifecycle{}
+	if sidecarCfg.holdApplicationUntilProxyStarts {
+		// The containers of a pod are started in order, and a container is started only once the postStart hook
+		// of the previous container completes. The sidecar is added as the first container of the pod.
+		lifecycle.PostStart = &corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sh", "-c", getEnvoyReadyWaitCommand(adminURL)},
+			},
+		}
+	}
+	if sidecarCfg.holdApplicationUntilProxyStarts || (sidecarCfg.drainDuration > 0 && !strings.EqualFold(podOS, constants.OSWindows)) {
+		// On termination, the sidecar receives SIGTERM only once its preStop hook completes, which drains the
+		// listeners of the sidecar and waits until the application has closed its connections, or the drain
+		// duration elapses. The drain is bounded by the termination grace period of the pod.
+		lifecycle.PreStop = &corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sh", "-c", getEnvoyDrainCommand(adminURL, sidecarCfg.drainDuration)},
+			},
+		}
+	}
+	if lifecycle.PostStart == nil && lifecycle.PreStop == nil {
+		lifecycle = nil
+	}
 
 	return corev1.Container{
 		Name:            constants.EnvoyContainerName,
@@ -54,14 +114,10 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, cfg configurator.Configurator
 			ReadOnly:  true,
 			MountPath: envoyProxyConfigPath,
 		}},
-		Command:   []string{"envoy"},
-		Resources: cfg.GetProxyResources(),
-		Args: []string{
-			"--log-level", cfg.GetEnvoyLogLevel(),
-			"--config-path", strings.Join([]string{envoyProxyConfigPath, envoyBootstrapConfigFile}, "/"),
-			"--service-cluster", clusterID,
-			"--bootstrap-version 3",
-		},
+		Command:   command,
+		Resources: sidecarCfg.resources,
+		Args:      args,
+		Lifecycle: lifecycle,
 		Env: []corev1.EnvVar{
 			{
 				Name: "POD_UID",
@@ -107,6 +163,74 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, cfg configurator.Configurator
 	}
 }
 
+// getEnvoyAdminURL returns the URL of the admin interface of the Envoy sidecar listening on the given loopback address
+func getEnvoyAdminURL(localhostIPAddress string) string {
+	return "http://" + net.JoinHostPort(localhostIPAddress, strconv.Itoa(constants.EnvoyAdminPort))
+}
+
+// getEnvoyReadyWaitCommand returns the shell command waiting until the Envoy sidecar is ready to serve traffic,
+// which is once it has received its initial configuration from the xDS server
+func getEnvoyReadyWaitCommand(adminURL string) string {
+	return fmt.Sprintf("for i in $(seq %d); do wget -q -O /dev/null '%s/ready' && exit 0; sleep 1; done; exit 1",
+		envoyReadyTimeoutSeconds, adminURL)
+}
+
+// getEnvoyDrainCommand returns the shell command gracefully draining the listeners of the Envoy sidecar, and waiting
+// until all the connections of the sidecar are closed, or the given drain duration elapses
+func getEnvoyDrainCommand(adminURL string, drainDuration time.Duration) string {
+	timeoutSeconds := envoyDrainTimeoutSeconds
+	if drainDuration > 0 {
+		timeoutSeconds = int(drainDuration.Seconds())
+	}
+
+	return fmt.Sprintf("wget -q -O /dev/null --post-data='' '%s/drain_listeners?graceful'; "+
+		"for i in $(seq %d); do "+
+		"[ \"$(wget -q -O - '%s/stats?filter=^server.total_connections$' | cut -d' ' -f2)\" = \"0\" ] && exit 0; "+
+		"sleep 1; done; exit 0",
+		adminURL, timeoutSeconds, adminURL)
+}
+
+// getEnvoyQuitOnApplicationExitCommand returns the shell command starting Envoy, and stopping it with the quit
+// endpoint of its admin interface once all the application containers of the pod have terminated.
+// The processes of the application containers are those of the shared process namespace of the pod that run in
+// another mount namespace than the sidecar, except for the pause container running as PID 1.
+// If verifyTools is true, Envoy is started without watching the application containers when the commands watching
+// them are not available.
+func getEnvoyQuitOnApplicationExitCommand(adminURL string, verifyTools bool) string {
+	var toolsCheck string
+	if verifyTools {
+		toolsCheck = `for tool in readlink wget; do
+  if ! command -v $tool >/dev/null 2>&1; then
+    echo "$tool not found in the sidecar image, the sidecar will not exit once the application containers terminate" >&2
+    exec envoy "$@"
+  fi
+done
+`
+	}
+
+	return toolsCheck + fmt.Sprintf(`envoy "$@" &
+pid=$!
+trap 'kill -TERM $pid' TERM
+mnt=$(readlink /proc/self/ns/mnt)
+seen=0
+while kill -0 $pid 2>/dev/null; do
+  running=0
+  for p in /proc/[0-9]*; do
+    [ "$p" = /proc/1 ] && continue
+    [ "$(readlink $p/ns/mnt 2>/dev/null)" = "$mnt" ] && continue
+    running=1
+    break
+  done
+  if [ $running = 1 ]; then
+    seen=1
+  elif [ $seen = 1 ]; then
+    wget -q -O /dev/null --post-data='' '%s%s'
+  fi
+  sleep 1
+done
+wait $pid`, adminURL, constants.EnvoyAdminQuitPath)
+}
+
 func getEnvoyContainerPorts(originalHealthProbes healthProbes) []corev1.ContainerPort {
 	containerPorts := []corev1.ContainerPort{
 		{
@@ -7,9 +7,8 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 )
 
-func getInitContainerSpec(containerName string, cfg configurator.Configurator, outboundIPRangeExclusionList []string, outboundPortExclusionList []int,
-	inboundPortExclusionList []int, enablePrivilegedInitContainer bool) corev1.Container {
-	iptablesInitCommand := generateIptablesCommands(outboundIPRangeExclusionList, outboundPortExclusionList, inboundPortExclusionList)
+func getInitContainerSpec(containerName string, cfg configurator.Configurator, iptablesConfig IptablesConfig, enablePrivilegedInitContainer bool) corev1.Container {
+	iptablesInitCommand := GenerateIptablesCommands(iptablesConfig)
 
 	return corev1.Container{
 		Name:  containerName,
@@ -0,0 +1,207 @@
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/webhook"
+)
+
+const (
+	// WebhookInjectPath is the HTTP path at which the sidecar injection of a manifest can be previewed
+	WebhookInjectPath = "/inject"
+
+	// WebhookInjectNamespaceKey is the url variable name for the namespace of a manifest that does not specify one
+	WebhookInjectNamespaceKey = "namespace"
+)
+
+// podTemplatePaths are the paths of the pod templates of the kinds of workloads the sidecar injection can be previewed for.
+// The sidecar injection of a Pod manifest is previewed on the manifest itself.
+var podTemplatePaths = map[string][]string{
+	"Pod":                   nil,
+	"Deployment":            {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+}
+
+// injectHandler previews the sidecar injection of the manifest in the body of the request, without issuing a certificate
+// for the sidecar or creating its bootstrap config secret.
+func (wh *mutatingWebhook) injectHandler(w http.ResponseWriter, req *http.Request) {
+	log.Trace().Msgf("Received sidecar injection preview request: Method=%v, URL=%v", req.Method, req.URL)
+
+	if req.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("Invalid method %s; Expected %s", req.Method, http.MethodPost), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if contentType := req.Header.Get(webhook.HTTPHeaderContentType); contentType != webhook.ContentTypeJSON {
+		http.Error(w, fmt.Sprintf("Invalid content type %s; Expected %s", contentType, webhook.ContentTypeJSON), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	manifest, err := webhook.GetAdmissionRequestBody(w, req)
+	if err != nil {
+		// Error was already logged and written to the ResponseWriter
+		return
+	}
+
+	result, err := wh.previewInjection(manifest, req.URL.Query().Get(WebhookInjectNamespaceKey))
+	if err != nil {
+		log.Error().Err(err).Msg("Error previewing sidecar injection")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error marshalling sidecar injection preview: %s", err), http.StatusInternalServerError)
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingKubernetesResource)).
+			Msg("Error marshalling sidecar injection preview")
+		return
+	}
+
+	w.Header().Set(webhook.HTTPHeaderContentType, webhook.ContentTypeJSON)
+	if _, err := w.Write(resp); err != nil {
+		log.Error().Err(err).Msg("Error writing sidecar injection preview")
+	}
+}
+
+// previewInjection returns the result of injecting the sidecar in the pod template of the given JSON manifest, which is
+// in the given namespace unless the manifest specifies one. The pod template is patched the same way as the pods
+// admitted by the webhook, as a dry-run.
+func (wh *mutatingWebhook) previewInjection(manifest []byte, namespace string) (*InjectionResult, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(manifest); err != nil {
+		return nil, errors.Errorf("Error decoding manifest: %s", err)
+	}
+	templatePath, ok := podTemplatePaths[obj.GetKind()]
+	if !ok {
+		return nil, errors.Errorf("Unsupported kind %s, the sidecar injection can only be previewed for pods and workloads with a pod template", obj.GetKind())
+	}
+	if obj.GetNamespace() != "" {
+		namespace = obj.GetNamespace()
+	}
+	if namespace == "" {
+		return nil, errors.Errorf("The namespace of %s %s must be specified", obj.GetKind(), obj.GetName())
+	}
+
+	pod, err := getPodFromManifest(obj, templatePath)
+	if err != nil {
+		return nil, err
+	}
+	original, err := setPodInManifest(obj, templatePath, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	inject, reason, err := wh.getInjectionDecision(pod, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !inject {
+		return &InjectionResult{Reason: reason}, nil
+	}
+
+	// The pods of Jobs, including the Jobs of CronJobs, are controlled by a Job
+	ownerReferences := pod.OwnerReferences
+	if obj.GetKind() == "Job" || obj.GetKind() == "CronJob" {
+		isController := true
+		pod.OwnerReferences = append(pod.OwnerReferences, metav1.OwnerReference{Kind: "Job", Name: obj.GetName(), Controller: &isController})
+	}
+
+	raw, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	dryRun := true
+	req := &admissionv1.AdmissionRequest{
+		Namespace: namespace,
+		Object:    runtime.RawExtension{Raw: raw},
+		DryRun:    &dryRun,
+	}
+	if _, err := wh.createPatch(pod, req, uuid.New()); err != nil {
+		return nil, err
+	}
+	pod.OwnerReferences = ownerReferences
+
+	mutated, err := setPodInManifest(obj, templatePath, pod)
+	if err != nil {
+		return nil, err
+	}
+	originalRaw, err := original.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	mutatedRaw, err := mutated.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &InjectionResult{
+		Injected: true,
+		Object:   mutatedRaw,
+		Patch:    admission.PatchResponseFromRaw(originalRaw, mutatedRaw).Patches,
+	}, nil
+}
+
+// getPodFromManifest returns the pod of the given manifest at the given pod template path
+func getPodFromManifest(obj *unstructured.Unstructured, templatePath []string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	if templatePath == nil {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+			return nil, errors.Errorf("Error decoding Pod %s: %s", obj.GetName(), err)
+		}
+		return pod, nil
+	}
+
+	content, found, err := unstructured.NestedMap(obj.Object, templatePath...)
+	if err != nil || !found {
+		return nil, errors.Errorf("%s %s does not have a pod template", obj.GetKind(), obj.GetName())
+	}
+	var template corev1.PodTemplateSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &template); err != nil {
+		return nil, errors.Errorf("Error decoding the pod template of %s %s: %s", obj.GetKind(), obj.GetName(), err)
+	}
+	pod.ObjectMeta = template.ObjectMeta
+	pod.Spec = template.Spec
+	return pod, nil
+}
+
+// setPodInManifest returns a copy of the given manifest whose pod at the given pod template path is the given pod
+func setPodInManifest(obj *unstructured.Unstructured, templatePath []string, pod *corev1.Pod) (*unstructured.Unstructured, error) {
+	if templatePath == nil {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+		if err != nil {
+			return nil, err
+		}
+		return &unstructured.Unstructured{Object: content}, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.PodTemplateSpec{
+		ObjectMeta: pod.ObjectMeta,
+		Spec:       pod.Spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	manifest := obj.DeepCopy()
+	if err := unstructured.SetNestedMap(manifest.Object, content, templatePath...); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
@@ -0,0 +1,175 @@
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/config"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestPreviewInjection(t *testing.T) {
+	const namespace = "ns"
+
+	deployment := `{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {"name": "bookstore"},
+		"spec": {
+			"selector": {"matchLabels": {"app": "bookstore"}},
+			"template": {
+				"metadata": {"labels": {"app": "bookstore"}},
+				"spec": {"containers": [{"name": "bookstore", "image": "bookstore"}]}
+			}
+		}
+	}`
+
+	testCases := []struct {
+		name                 string
+		manifest             string
+		namespace            string
+		namespaceAnnotations map[string]string
+		isMonitored          bool
+		expectedInjected     bool
+		expectedReason       string
+		expectedPatches      []string
+		expectedErr          string
+	}{
+		{
+			name:                 "sidecar injected in the pod template of a Deployment",
+			manifest:             deployment,
+			namespace:            namespace,
+			namespaceAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+			isMonitored:          true,
+			expectedInjected:     true,
+			expectedPatches: []string{
+				`"path":"/spec/template/spec/initContainers"`,
+				`"path":"/spec/template/spec/containers/1"`,
+				`"path":"/spec/template/spec/volumes"`,
+				`"path":"/spec/template/metadata/annotations"`,
+			},
+		},
+		{
+			name:             "sidecar injected in a Pod annotated for injection",
+			manifest:         `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "bookstore", "namespace": "ns", "annotations": {"openservicemesh.io/sidecar-injection": "enabled"}}, "spec": {"containers": [{"name": "bookstore"}]}}`,
+			isMonitored:      true,
+			expectedInjected: true,
+			expectedPatches: []string{
+				`"path":"/spec/initContainers"`,
+				`"path":"/spec/containers/1"`,
+			},
+		},
+		{
+			name:           "sidecar not injected in a namespace that is not monitored",
+			manifest:       deployment,
+			namespace:      namespace,
+			isMonitored:    false,
+			expectedReason: "Sidecar injection is not permitted in namespace ns, or the namespace is not monitored by the mesh",
+		},
+		{
+			name:           "sidecar not injected without injection annotation",
+			manifest:       deployment,
+			namespace:      namespace,
+			isMonitored:    true,
+			expectedReason: "Neither the pod nor namespace ns is annotated with openservicemesh.io/sidecar-injection",
+		},
+		{
+			name:                 "sidecar not injected in a pod annotated to disable injection",
+			manifest:             `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "bookstore", "annotations": {"openservicemesh.io/sidecar-injection": "disabled"}}}`,
+			namespace:            namespace,
+			namespaceAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+			isMonitored:          true,
+			expectedReason:       "The pod is annotated with openservicemesh.io/sidecar-injection=disabled",
+		},
+		{
+			name:        "unsupported kind",
+			manifest:    `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "bookstore"}}`,
+			namespace:   namespace,
+			expectedErr: "Unsupported kind Service",
+		},
+		{
+			name:        "namespace not specified",
+			manifest:    deployment,
+			expectedErr: "The namespace of Deployment bookstore must be specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			mockCtrl := gomock.NewController(t)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigClient := config.NewMockController(mockCtrl)
+			kubeClient := fake.NewSimpleClientset()
+
+			mockKubeController.EXPECT().IsMonitoredNamespace(namespace).Return(tc.isMonitored).AnyTimes()
+			mockKubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: namespace, Annotations: tc.namespaceAnnotations},
+			}).AnyTimes()
+			mockConfigClient.EXPECT().ListSidecarConfigs(namespace).Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{}).AnyTimes()
+			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("error").AnyTimes()
+			mockConfigurator.EXPECT().GetProxyConcurrency().Return(0).AnyTimes()
+			mockConfigurator.EXPECT().GetProxyDrainDuration().AnyTimes()
+			mockConfigurator.EXPECT().IsHoldApplicationUntilProxyStarts().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetProxyStatsTags().Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().GetEnvoyImage().Return("envoy-linux-image").AnyTimes()
+			mockConfigurator.EXPECT().GetEnvoyWindowsImage().Return("envoy-windows-image").AnyTimes()
+			mockConfigurator.EXPECT().GetInitContainerImage().Return("init-container-image").AnyTimes()
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
+			mockConfigurator.EXPECT().IsPrivilegedInitContainer().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetOutboundIPRangeExclusionList().Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().GetOutboundPortExclusionList().Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().GetInboundPortExclusionList().Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().GetOutboundUIDExclusionList().Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().GetOutboundGIDExclusionList().Return(nil).AnyTimes()
+
+			// No certificate manager: previewing the injection must not issue a certificate
+			wh := &mutatingWebhook{
+				kubeClient:          kubeClient,
+				kubeController:      mockKubeController,
+				configClient:        mockConfigClient,
+				configurator:        mockConfigurator,
+				osmNamespace:        "osm-system",
+				nonInjectNamespaces: mapset.NewSet(),
+			}
+
+			result, err := wh.previewInjection([]byte(tc.manifest), tc.namespace)
+			if tc.expectedErr != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedInjected, result.Injected)
+			assert.Equal(tc.expectedReason, result.Reason)
+
+			patches, err := json.Marshal(result.Patch)
+			assert.NoError(err)
+			for _, expectedPatch := range tc.expectedPatches {
+				assert.Contains(string(patches), expectedPatch)
+			}
+			if tc.expectedInjected {
+				assert.Contains(string(result.Object), `"name":"envoy"`)
+			}
+
+			// The bootstrap config secret is not created
+			secrets, err := kubeClient.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+			assert.NoError(err)
+			assert.Empty(secrets.Items)
+		})
+	}
+}
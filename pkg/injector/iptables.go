@@ -2,31 +2,49 @@ package injector
 
 import (
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 )
 
-// iptablesOutboundStaticRules is the list of iptables rules related to outbound traffic interception and redirection
-var iptablesOutboundStaticRules = []string{
-	// Redirects outbound TCP traffic hitting OSM_PROXY_OUT_REDIRECT chain to Envoy's outbound listener port
-	fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyOutboundListenerPort),
+const (
+	// iptablesRestoreCmd is the command used to program IPv4 rules
+	iptablesRestoreCmd = "iptables-restore"
 
-	// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
-	fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp --dport %d -j ACCEPT", constants.EnvoyAdminPort),
+	// ip6tablesRestoreCmd is the command used to program IPv6 rules
+	ip6tablesRestoreCmd = "ip6tables-restore"
 
-	// For outbound TCP traffic jump from OUTPUT chain to OSM_PROXY_OUTBOUND chain
-	"-A OUTPUT -p tcp -j OSM_PROXY_OUTBOUND",
+	// localhostIPv4CIDR is the IPv4 loopback address
+	localhostIPv4CIDR = "127.0.0.1/32"
 
-	// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
-	fmt.Sprintf("-A OSM_PROXY_OUTBOUND -m owner --uid-owner %d -j RETURN", constants.EnvoyUID),
+	// localhostIPv6CIDR is the IPv6 loopback address
+	localhostIPv6CIDR = "::1/128"
+)
+
+// iptablesOutboundStaticRules returns the list of iptables rules related to outbound traffic interception and redirection,
+// skipping traffic to the given localhost CIDR
+func iptablesOutboundStaticRules(localhostCIDR string) []string {
+	return []string{
+		// Redirects outbound TCP traffic hitting OSM_PROXY_OUT_REDIRECT chain to Envoy's outbound listener port
+		fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyOutboundListenerPort),
+
+		// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
+		fmt.Sprintf("-A OSM_PROXY_OUT_REDIRECT -p tcp --dport %d -j ACCEPT", constants.EnvoyAdminPort),
 
-	// Skip localhost traffic, doesn't need to be routed via the proxy
-	"-A OSM_PROXY_OUTBOUND -d 127.0.0.1/32 -j RETURN",
+		// For outbound TCP traffic jump from OUTPUT chain to OSM_PROXY_OUTBOUND chain
+		"-A OUTPUT -p tcp -j OSM_PROXY_OUTBOUND",
 
-	// Redirect remaining outbound traffic to Envoy
-	"-A OSM_PROXY_OUTBOUND -j OSM_PROXY_OUT_REDIRECT",
+		// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
+		fmt.Sprintf("-A OSM_PROXY_OUTBOUND -m owner --uid-owner %d -j RETURN", constants.EnvoyUID),
+
+		// Skip localhost traffic, doesn't need to be routed via the proxy
+		fmt.Sprintf("-A OSM_PROXY_OUTBOUND -d %s -j RETURN", localhostCIDR),
+
+		// Redirect remaining outbound traffic to Envoy
+		"-A OSM_PROXY_OUTBOUND -j OSM_PROXY_OUT_REDIRECT",
+	}
 }
 
 // iptablesInboundStaticRules is the list of iptables rules related to inbound traffic interception and redirection
@@ -51,8 +69,52 @@ var iptablesInboundStaticRules = []string{
 	"-A OSM_PROXY_INBOUND -p tcp -j OSM_PROXY_IN_REDIRECT",
 }
 
-// generateIptablesCommands generates a list of iptables commands to set up sidecar interception and redirection
-func generateIptablesCommands(outboundIPRangeExclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int) string {
+// IptablesConfig is the configuration of the iptables rules that intercept the traffic of a pod and redirect it
+// to its sidecar. It is recorded in the IptablesConfigAnnotation annotation of the pods whose rules are programmed
+// by the OSM CNI plugin.
+type IptablesConfig struct {
+	OutboundIPRangeExclusionList []string `json:"outboundIPRangeExclusionList,omitempty"`
+	OutboundPortExclusionList    []int    `json:"outboundPortExclusionList,omitempty"`
+	InboundPortExclusionList     []int    `json:"inboundPortExclusionList,omitempty"`
+	OutboundUIDExclusionList     []int    `json:"outboundUIDExclusionList,omitempty"`
+	OutboundGIDExclusionList     []int    `json:"outboundGIDExclusionList,omitempty"`
+	EnableIPv6                   bool     `json:"enableIPv6,omitempty"`
+}
+
+// GenerateIptablesCommands generates a list of iptables commands to set up sidecar interception and redirection.
+// When IPv6 is enabled, the IPv6 traffic is also intercepted using ip6tables.
+// The commands are run by the init container, or by the OSM CNI plugin in the network namespace of the pod.
+func GenerateIptablesCommands(config IptablesConfig) string {
+	var ipv4RangeExclusionList, ipv6RangeExclusionList []string
+	for _, cidr := range config.OutboundIPRangeExclusionList {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid IP range %s in outbound IP range exclusion list, skipping", cidr)
+			continue
+		}
+		if ip.To4() != nil {
+			ipv4RangeExclusionList = append(ipv4RangeExclusionList, cidr)
+		} else {
+			ipv6RangeExclusionList = append(ipv6RangeExclusionList, cidr)
+		}
+	}
+
+	cmd := generateIptablesRestoreCommand(iptablesRestoreCmd, localhostIPv4CIDR, ipv4RangeExclusionList, config)
+	if !config.EnableIPv6 {
+		if len(ipv6RangeExclusionList) > 0 {
+			log.Warn().Msgf("IPv6 is not enabled, ignoring IPv6 ranges %v in outbound IP range exclusion list", ipv6RangeExclusionList)
+		}
+		return cmd
+	}
+
+	// Fail the init container or the CNI plugin if either of the commands fail
+	return "set -e\n" + cmd +
+		generateIptablesRestoreCommand(ip6tablesRestoreCmd, localhostIPv6CIDR, ipv6RangeExclusionList, config)
+}
+
+// generateIptablesRestoreCommand generates the given iptables-restore command to program the interception and redirection rules,
+// excluding the given IP ranges of the address family of the command
+func generateIptablesRestoreCommand(restoreCmd string, localhostCIDR string, outboundIPRangeExclusionList []string, config IptablesConfig) string {
 	var rules strings.Builder
 
 	fmt.Fprintln(&rules, `# OSM sidecar interception rules
@@ -67,9 +129,9 @@ func generateIptablesCommands(outboundIPRangeExclusionList []string, outboundPor
 	cmds = append(cmds, iptablesInboundStaticRules...)
 
 	// 2. Create dynamic inbound ports exclusion rules
-	if len(inboundPortExclusionList) > 0 {
+	if len(config.InboundPortExclusionList) > 0 {
 		var portExclusionListStr []string
-		for _, port := range inboundPortExclusionList {
+		for _, port := range config.InboundPortExclusionList {
 			portExclusionListStr = append(portExclusionListStr, strconv.Itoa(port))
 		}
 		inboundPortsToExclude := strings.Join(portExclusionListStr, ",")
@@ -78,7 +140,7 @@ func generateIptablesCommands(outboundIPRangeExclusionList []string, outboundPor
 	}
 
 	// 3. Create outbound rules
-	cmds = append(cmds, iptablesOutboundStaticRules...)
+	cmds = append(cmds, iptablesOutboundStaticRules(localhostCIDR)...)
 
 	// 4. Create dynamic outbound ip ranges exclusion rules
 	for _, cidr := range outboundIPRangeExclusionList {
@@ -89,9 +151,9 @@ func generateIptablesCommands(outboundIPRangeExclusionList []string, outboundPor
 	}
 
 	// 5. Create dynamic outbound ports exclusion rules
-	if len(outboundPortExclusionList) > 0 {
+	if len(config.OutboundPortExclusionList) > 0 {
 		var portExclusionListStr []string
-		for _, port := range outboundPortExclusionList {
+		for _, port := range config.OutboundPortExclusionList {
 			portExclusionListStr = append(portExclusionListStr, strconv.Itoa(port))
 		}
 		outboundPortsToExclude := strings.Join(portExclusionListStr, ",")
@@ -99,16 +161,27 @@ func generateIptablesCommands(outboundIPRangeExclusionList []string, outboundPor
 		cmds = append(cmds, rule)
 	}
 
+	// 6. Create dynamic outbound user and group IDs exclusion rules, for the traffic originated by the processes
+	// running as these users or groups to bypass the sidecar
+	for _, uid := range config.OutboundUIDExclusionList {
+		rule := fmt.Sprintf("-I OSM_PROXY_OUTBOUND -m owner --uid-owner %d -j RETURN", uid)
+		cmds = append(cmds, rule)
+	}
+	for _, gid := range config.OutboundGIDExclusionList {
+		rule := fmt.Sprintf("-I OSM_PROXY_OUTBOUND -m owner --gid-owner %d -j RETURN", gid)
+		cmds = append(cmds, rule)
+	}
+
 	for _, rule := range cmds {
 		fmt.Fprintln(&rules, rule)
 	}
 
 	fmt.Fprint(&rules, "COMMIT")
 
-	cmd := fmt.Sprintf(`iptables-restore --noflush <<EOF
+	cmd := fmt.Sprintf(`%s --noflush <<EOF
 %s
 EOF
-`, rules.String())
+`, restoreCmd, rules.String())
 
 	return cmd
 }
@@ -0,0 +1,96 @@
+package injector
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+const (
+	// IptablesConfigKeySecretKey is the key of the signing key in the IptablesConfigKeySecretName secret
+	IptablesConfigKeySecretKey = "key"
+
+	// iptablesConfigKeySize is the size in bytes of the key the iptables config annotations are signed with
+	iptablesConfigKeySize = 32
+)
+
+// getOrCreateIptablesConfigKey returns the key the iptables config annotations are signed with, creating the secret
+// holding it in the given namespace if it does not exist. Multiple instances of the injector end up with the same key.
+func getOrCreateIptablesConfigKey(kubeClient kubernetes.Interface, osmNamespace string) ([]byte, error) {
+	key := make([]byte, iptablesConfigKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	// When multiple instances attempt to create the secret, only one of them succeeds and the others get
+	// an AlreadyExists error back
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.IptablesConfigKeySecretName,
+			Namespace: osmNamespace,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey:    constants.OSMAppNameLabelValue,
+				constants.OSMAppVersionLabelKey: version.Version,
+			},
+		},
+		Data: map[string][]byte{
+			IptablesConfigKeySecretKey: key,
+		},
+	}
+	if _, err := kubeClient.CoreV1().Secrets(osmNamespace).Create(context.Background(), secret, metav1.CreateOptions{}); err == nil {
+		return key, nil
+	} else if !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(osmNamespace).Get(context.Background(), constants.IptablesConfigKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	key, ok := secret.Data[IptablesConfigKeySecretKey]
+	if !ok || len(key) == 0 {
+		return nil, errors.Errorf("Secret %s/%s does not have the %s key", osmNamespace, constants.IptablesConfigKeySecretName, IptablesConfigKeySecretKey)
+	}
+	return key, nil
+}
+
+// SignIptablesConfig returns the signature of the given iptables config annotation of a pod in the given namespace,
+// computed with the given key. The namespace is signed so that the config cannot be reused in another namespace.
+func SignIptablesConfig(key []byte, namespace string, iptablesConfig string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(namespace + "\n" + iptablesConfig))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyIptablesConfig returns true if the given signature of the given iptables config annotation of a pod in
+// the given namespace was computed with the given key
+func VerifyIptablesConfig(key []byte, namespace string, iptablesConfig string, signature string) bool {
+	return hmac.Equal([]byte(SignIptablesConfig(key, namespace, iptablesConfig)), []byte(signature))
+}
+
+// setIptablesConfigAnnotations records the given iptables config, and its signature computed with the given key,
+// in the annotations of the pod read by the OSM CNI plugin
+func setIptablesConfigAnnotations(pod *corev1.Pod, namespace string, config IptablesConfig, key []byte) error {
+	iptablesConfig, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[constants.IptablesConfigAnnotation] = string(iptablesConfig)
+	pod.Annotations[constants.IptablesConfigSignatureAnnotation] = SignIptablesConfig(key, namespace, string(iptablesConfig))
+	return nil
+}
@@ -0,0 +1,45 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetOrCreateIptablesConfigKey(t *testing.T) {
+	assert := tassert.New(t)
+	kubeClient := fake.NewSimpleClientset()
+
+	// The key is created by the first instance, and shared by the others
+	key, err := getOrCreateIptablesConfigKey(kubeClient, "osm-system")
+	assert.Nil(err)
+	assert.Len(key, iptablesConfigKeySize)
+
+	otherKey, err := getOrCreateIptablesConfigKey(kubeClient, "osm-system")
+	assert.Nil(err)
+	assert.Equal(key, otherKey)
+}
+
+func TestSetIptablesConfigAnnotations(t *testing.T) {
+	assert := tassert.New(t)
+	key := []byte("key")
+
+	pod := &corev1.Pod{}
+	err := setIptablesConfigAnnotations(pod, "ns", IptablesConfig{InboundPortExclusionList: []int{8080}}, key)
+	assert.Nil(err)
+
+	iptablesConfig := pod.Annotations[constants.IptablesConfigAnnotation]
+	signature := pod.Annotations[constants.IptablesConfigSignatureAnnotation]
+	assert.Equal(`{"inboundPortExclusionList":[8080]}`, iptablesConfig)
+	assert.True(VerifyIptablesConfig(key, "ns", iptablesConfig, signature))
+
+	// The signature is only valid for the config and namespace it was computed for, with the same key
+	assert.False(VerifyIptablesConfig(key, "ns", `{}`, signature))
+	assert.False(VerifyIptablesConfig(key, "other", iptablesConfig, signature))
+	assert.False(VerifyIptablesConfig([]byte("other"), "ns", iptablesConfig, signature))
+	assert.False(VerifyIptablesConfig(key, "ns", iptablesConfig, ""))
+}
@@ -1,6 +1,7 @@
 package injector
 
 import (
+	"strings"
 	"testing"
 
 	tassert "github.com/stretchr/testify/assert"
@@ -9,11 +10,13 @@ import (
 func TestGenerateIptablesCommands(t *testing.T) {
 	assert := tassert.New(t)
 
-	outboundIPRangeExclusion := []string{"1.1.1.1/32", "2.2.2.2/32"}
-	outboundPortExclusion := []int{10, 20}
-	inboundPortExclusion := []int{30, 40}
-
-	actual := generateIptablesCommands(outboundIPRangeExclusion, outboundPortExclusion, inboundPortExclusion)
+	actual := GenerateIptablesCommands(IptablesConfig{
+		OutboundIPRangeExclusionList: []string{"1.1.1.1/32", "2.2.2.2/32"},
+		OutboundPortExclusionList:    []int{10, 20},
+		InboundPortExclusionList:     []int{30, 40},
+		OutboundUIDExclusionList:     []int{1000},
+		OutboundGIDExclusionList:     []int{2000},
+	})
 
 	expected := `iptables-restore --noflush <<EOF
 # OSM sidecar interception rules
@@ -39,9 +42,45 @@ func TestGenerateIptablesCommands(t *testing.T) {
 -I OSM_PROXY_OUTBOUND -d 1.1.1.1/32 -j RETURN
 -I OSM_PROXY_OUTBOUND -d 2.2.2.2/32 -j RETURN
 -I OSM_PROXY_OUTBOUND -p tcp --match multiport --dports 10,20 -j RETURN
+-I OSM_PROXY_OUTBOUND -m owner --uid-owner 1000 -j RETURN
+-I OSM_PROXY_OUTBOUND -m owner --gid-owner 2000 -j RETURN
 COMMIT
 EOF
 `
 
 	assert.Equal(expected, actual)
 }
+
+func TestGenerateIptablesCommandsIPv6(t *testing.T) {
+	assert := tassert.New(t)
+
+	outboundIPRangeExclusion := []string{"1.1.1.1/32", "2001:db8::/32"}
+
+	// IPv6 disabled: IPv6 ranges are ignored and ip6tables rules are not generated
+	actual := GenerateIptablesCommands(IptablesConfig{OutboundIPRangeExclusionList: outboundIPRangeExclusion})
+	assert.Contains(actual, "-I OSM_PROXY_OUTBOUND -d 1.1.1.1/32 -j RETURN")
+	assert.NotContains(actual, "2001:db8::/32")
+	assert.NotContains(actual, ip6tablesRestoreCmd)
+
+	// IPv6 enabled: IPv4 and IPv6 ranges are programmed in their respective tables
+	actual = GenerateIptablesCommands(IptablesConfig{
+		OutboundIPRangeExclusionList: outboundIPRangeExclusion,
+		OutboundUIDExclusionList:     []int{1000},
+		EnableIPv6:                   true,
+	})
+	v6Index := strings.Index(actual, ip6tablesRestoreCmd)
+	assert.True(strings.HasPrefix(actual, "set -e\n"))
+	assert.Greater(v6Index, 0)
+
+	v4Commands, v6Commands := actual[:v6Index], actual[v6Index:]
+	assert.Contains(v4Commands, "-A OSM_PROXY_OUTBOUND -d 127.0.0.1/32 -j RETURN")
+	assert.Contains(v4Commands, "-I OSM_PROXY_OUTBOUND -d 1.1.1.1/32 -j RETURN")
+	assert.NotContains(v4Commands, "2001:db8::/32")
+	assert.Contains(v6Commands, "-A OSM_PROXY_OUTBOUND -d ::1/128 -j RETURN")
+	assert.Contains(v6Commands, "-I OSM_PROXY_OUTBOUND -d 2001:db8::/32 -j RETURN")
+	assert.NotContains(v6Commands, "1.1.1.1/32")
+
+	// The user ID exclusions apply to both address families
+	assert.Contains(v4Commands, "-I OSM_PROXY_OUTBOUND -m owner --uid-owner 1000 -j RETURN")
+	assert.Contains(v6Commands, "-I OSM_PROXY_OUTBOUND -m owner --uid-owner 1000 -j RETURN")
+}
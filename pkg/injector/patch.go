@@ -2,7 +2,6 @@ package injector
 
 import (
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -24,33 +23,23 @@ import (
 func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.AdmissionRequest, proxyUUID uuid.UUID) ([]byte, error) {
 	namespace := req.Namespace
 
-	// Issue a certificate for the proxy sidecar - used for Envoy to connect to XDS (not Envoy-to-Envoy connections)
-	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, pod.Spec.ServiceAccountName, namespace)
-	log.Debug().Msgf("Patching POD spec: service-account=%s, namespace=%s with certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
-	startTime := time.Now()
-	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	// Validate the annotations overriding the sidecar settings before making any change
+	sidecarCfg, err := wh.getSidecarConfig(pod, namespace)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error issuing bootstrap certificate for Envoy with CN=%s", cn)
 		return nil, err
 	}
-	elapsed := time.Since(startTime)
 
-	metricsstore.DefaultMetricsStore.CertIssuedCount.Inc()
-	metricsstore.DefaultMetricsStore.CertIssuedTime.
-		WithLabelValues().Observe(elapsed.Seconds())
 	originalHealthProbes := rewriteHealthProbes(pod)
 
 	// Create the bootstrap configuration for the Envoy proxy for the given pod
-	envoyBootstrapConfigName := fmt.Sprintf("envoy-bootstrap-config-%s", proxyUUID)
+	envoyBootstrapConfigName := constants.EnvoyBootstrapConfigSecretPrefix + proxyUUID.String()
 
-	// The webhook has a side effect (making out-of-band changes) of creating k8s secret
-	// corresponding to the Envoy bootstrap config. Such a side effect needs to be skipped
-	// when the request is a DryRun.
+	// The webhook has side effects (making out-of-band changes) of issuing a certificate and creating the k8s secret
+	// corresponding to the Envoy bootstrap config. Such side effects need to be skipped when the request is a DryRun.
 	// Ref: https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/#side-effects
 	if req.DryRun != nil && *req.DryRun {
 		log.Debug().Msgf("Skipping envoy bootstrap config creation for dry-run request: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
-	} else if _, err = wh.createEnvoyBootstrapConfig(envoyBootstrapConfigName, namespace, wh.osmNamespace, bootstrapCertificate, originalHealthProbes); err != nil {
-		log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for pod: service-account=%s, namespace=%s, certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
+	} else if err := wh.createEnvoyBootstrapConfigWithCertificate(envoyBootstrapConfigName, pod, namespace, proxyUUID, originalHealthProbes, sidecarCfg); err != nil {
 		return nil, err
 	}
 
@@ -64,25 +53,63 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 	if err := wh.verifyPrerequisites(podOS); err != nil {
 		return nil, err
 	}
-	if !strings.EqualFold(podOS, constants.OSWindows) {
-		// Build outbound port exclusion list
-		podOutboundPortExclusionList, _ := wh.getPortExclusionListForPod(pod, namespace, outboundPortExclusionListAnnotation)
-		globalOutboundPortExclusionList := wh.configurator.GetOutboundPortExclusionList()
-		outboundPortExclusionList := mergePortExclusionLists(podOutboundPortExclusionList, globalOutboundPortExclusionList)
+	template := wh.getSidecarTemplate(pod, namespace, sidecarCfg)
+	// The traffic interception settings are not applicable to Windows pods
+	if iptablesConfig := template.IptablesConfig; iptablesConfig != nil {
+		if template.EnableCNI {
+			// The OSM CNI plugin programs the iptables rules when the network sandbox of the pod is created,
+			// based on the configuration recorded in the signed annotations of the pod
+			if err := setIptablesConfigAnnotations(pod, namespace, *iptablesConfig, wh.iptablesConfigKey); err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingKubernetesResource)).
+					Msgf("Error marshaling the iptables config of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+				return nil, err
+			}
 
-		// Build inbound port exclusion list
-		podInboundPortExclusionList, _ := wh.getPortExclusionListForPod(pod, namespace, inboundPortExclusionListAnnotation)
-		globalInboundPortExclusionList := wh.configurator.GetInboundPortExclusionList()
-		inboundPortExclusionList := mergePortExclusionLists(podInboundPortExclusionList, globalInboundPortExclusionList)
+			// The pod is only scheduled on the nodes the plugin is installed on, so that its traffic is never left
+			// unintercepted
+			requireCNINode(pod)
+		} else {
+			// Only the OSM CNI plugin acts on the iptables config annotations
+			delete(pod.Annotations, constants.IptablesConfigAnnotation)
+			delete(pod.Annotations, constants.IptablesConfigSignatureAnnotation)
+
+			// Add the Init Container
+			initContainer := getInitContainerSpec(constants.InitContainerName, wh.configurator, *iptablesConfig, template.PrivilegedInitContainer)
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+		}
+	}
 
-		// Add the Init Container
-		initContainer := getInitContainerSpec(constants.InitContainerName, wh.configurator, wh.configurator.GetOutboundIPRangeExclusionList(), outboundPortExclusionList, inboundPortExclusionList, wh.configurator.IsPrivilegedInitContainer())
-		pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+	// The sidecar of the pods of Jobs exits once the application containers have terminated, which it detects
+	// by watching the processes of the pod
+	if sidecarCfg.quitOnApplicationExit {
+		shareProcessNamespace := true
+		pod.Spec.ShareProcessNamespace = &shareProcessNamespace
 	}
 
 	// Add the Envoy sidecar
-	sidecar := getEnvoySidecarContainerSpec(pod, wh.configurator, originalHealthProbes, podOS)
-	pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
+	sidecar := getEnvoySidecarContainerSpec(pod, wh.configurator, sidecarCfg, originalHealthProbes, podOS, wh.getLocalhostIPAddress())
+	if sidecarCfg.holdApplicationUntilProxyStarts || sidecarCfg.quitOnApplicationExit {
+		// The application containers are started after the sidecar, and after it is ready when they are held. The
+		// sidecar quitting on application exit must watch the processes of the pod before the application containers
+		// start, as an application container exiting before it is seen would never be waited for.
+		pod.Spec.Containers = append([]corev1.Container{sidecar}, pod.Spec.Containers...)
+	} else {
+		pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
+	}
+
+	// Record the hash of the injected configuration to detect when the sidecar becomes outdated
+	sidecarHash, err := template.hash()
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingKubernetesResource)).
+			Msgf("Error hashing the sidecar configuration of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+		return nil, err
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[constants.SidecarHashAnnotation] = sidecarHash
+	// A pod created from the template of a pod running an outdated sidecar runs an up to date sidecar
+	delete(pod.Annotations, constants.SidecarOutdatedAnnotation)
 
 	enableMetrics, err := wh.isMetricsEnabled(namespace)
 	if err != nil {
@@ -109,6 +136,32 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 	return json.Marshal(makePatches(req, pod))
 }
 
+// createEnvoyBootstrapConfigWithCertificate issues the xDS certificate of the sidecar of the given pod, and creates
+// the secret holding the Envoy bootstrap config with this certificate
+func (wh *mutatingWebhook) createEnvoyBootstrapConfigWithCertificate(name string, pod *corev1.Pod, namespace string, proxyUUID uuid.UUID,
+	originalHealthProbes healthProbes, sidecarCfg *sidecarConfig) error {
+	// Issue a certificate for the proxy sidecar - used for Envoy to connect to XDS (not Envoy-to-Envoy connections)
+	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, pod.Spec.ServiceAccountName, namespace)
+	log.Debug().Msgf("Patching POD spec: service-account=%s, namespace=%s with certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
+	startTime := time.Now()
+	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing bootstrap certificate for Envoy with CN=%s", cn)
+		return err
+	}
+	elapsed := time.Since(startTime)
+
+	metricsstore.DefaultMetricsStore.CertIssuedCount.Inc()
+	metricsstore.DefaultMetricsStore.CertIssuedTime.
+		WithLabelValues().Observe(elapsed.Seconds())
+
+	if _, err = wh.createEnvoyBootstrapConfig(name, namespace, wh.osmNamespace, bootstrapCertificate, originalHealthProbes, sidecarCfg.statsTags); err != nil {
+		log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for pod: service-account=%s, namespace=%s, certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
+		return err
+	}
+	return nil
+}
+
 // verifyPrerequisites verifies if the prerequisites to patch the request are met by returning an error if unmet
 func (wh *mutatingWebhook) verifyPrerequisites(podOS string) error {
 	isWindows := strings.EqualFold(podOS, constants.OSWindows)
@@ -122,14 +175,42 @@ func (wh *mutatingWebhook) verifyPrerequisites(podOS string) error {
 		// Windows pods require Envoy Windows image
 		return errors.New("MeshConfig sidecar.envoyWindowsImage not set")
 	}
-	if image := wh.configurator.GetInitContainerImage(); !isWindows && image == "" {
-		// Linux pods require init container image
+	if image := wh.configurator.GetInitContainerImage(); !isWindows && image == "" && !wh.configurator.GetFeatureFlags().EnableCNI {
+		// Linux pods require init container image, unless the iptables rules are programmed by the OSM CNI plugin
 		return errors.New("MeshConfig sidecar.initContainerImage not set")
 	}
 
 	return nil
 }
 
+// requireCNINode requires the given pod to be scheduled on a node labeled by the OSM CNI plugin installer.
+// The requirement is added to each of the node selector terms of the pod, as the terms are ORed.
+func requireCNINode(pod *corev1.Pod) {
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      constants.CNINodeLabel,
+		Operator: corev1.NodeSelectorOpExists,
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := pod.Spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range nodeSelector.NodeSelectorTerms {
+		term := &nodeSelector.NodeSelectorTerms[i]
+		term.MatchExpressions = append(term.MatchExpressions, requirement)
+	}
+}
+
 func makePatches(req *admissionv1.AdmissionRequest, pod *corev1.Pod) []jsonpatch.JsonPatchOperation {
 	original := req.Object.Raw
 	current, err := json.Marshal(pod)
@@ -141,23 +222,41 @@ func makePatches(req *admissionv1.AdmissionRequest, pod *corev1.Pod) []jsonpatch
 	return admissionResponse.Patches
 }
 
-func mergePortExclusionLists(podSpecificPortExclusionList, globalPortExclusionList []int) []int {
-	portExclusionListMap := mapset.NewSet()
-	var portExclusionListMerged []int
+// mergeExclusionLists merges the given pod specific and global exclusion lists of ports, user IDs or group IDs,
+// without duplicates
+func mergeExclusionLists(podSpecificExclusionList, globalExclusionList []int) []int {
+	exclusionListMap := mapset.NewSet()
+	var exclusionListMerged []int
 
-	// iterate over the global outbound ports to be excluded
-	for _, port := range globalPortExclusionList {
-		if addedToSet := portExclusionListMap.Add(port); addedToSet {
-			portExclusionListMerged = append(portExclusionListMerged, port)
+	// iterate over the global values to be excluded
+	for _, value := range globalExclusionList {
+		if addedToSet := exclusionListMap.Add(value); addedToSet {
+			exclusionListMerged = append(exclusionListMerged, value)
 		}
 	}
 
-	// iterate over the pod specific ports to be excluded
-	for _, port := range podSpecificPortExclusionList {
-		if addedToSet := portExclusionListMap.Add(port); addedToSet {
-			portExclusionListMerged = append(portExclusionListMerged, port)
+	// iterate over the pod specific values to be excluded
+	for _, value := range podSpecificExclusionList {
+		if addedToSet := exclusionListMap.Add(value); addedToSet {
+			exclusionListMerged = append(exclusionListMerged, value)
+		}
+	}
+
+	return exclusionListMerged
+}
+
+// mergeIPRangeExclusionLists merges the pod specific and global IP range exclusion lists
+func mergeIPRangeExclusionLists(podSpecificIPRangeExclusionList, globalIPRangeExclusionList []string) []string {
+	ipRangeExclusionListMap := mapset.NewSet()
+	var ipRangeExclusionListMerged []string
+
+	for _, ipRangeExclusionList := range [][]string{globalIPRangeExclusionList, podSpecificIPRangeExclusionList} {
+		for _, ipRange := range ipRangeExclusionList {
+			if addedToSet := ipRangeExclusionListMap.Add(ipRange); addedToSet {
+				ipRangeExclusionListMerged = append(ipRangeExclusionListMerged, ipRange)
+			}
 		}
 	}
 
-	return portExclusionListMerged
+	return ipRangeExclusionListMerged
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/mock/gomock"
@@ -15,8 +16,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -32,11 +36,16 @@ func TestCreatePatch(t *testing.T) {
 	)
 
 	testCases := []struct {
-		name            string
-		os              string
-		namespace       *corev1.Namespace
-		dryRun          bool
-		expectedPatches []string
+		name              string
+		os                string
+		namespace         *corev1.Namespace
+		dryRun            bool
+		featureFlags      configv1alpha1.FeatureFlags
+		sidecarConfigs    []configv1alpha1.SidecarConfig
+		ownerReferences   []metav1.OwnerReference
+		expectedPatches   []string
+		unexpectedPatches []string
+		sidecarFirst      bool
 	}{
 		{
 			name: "creates a patch for a unix worker",
@@ -50,6 +59,9 @@ func TestCreatePatch(t *testing.T) {
 				// Add Envoy UID Label
 				`"path":"/metadata/labels"`,
 				fmt.Sprintf(`"value":{"osm-proxy-uuid":"%v"`, proxyUUID),
+				// Add sidecar hash Annotation
+				`"path":"/metadata/annotations"`,
+				`"value":{"openservicemesh.io/sidecar-hash":"`,
 				// Add Volumes
 				`"path":"/spec/volumes"`,
 				fmt.Sprintf(`"value":[{"name":"envoy-bootstrap-config-volume","secret":{"secretName":"envoy-bootstrap-config-%v"}}]}`, proxyUUID),
@@ -96,7 +108,7 @@ func TestCreatePatch(t *testing.T) {
 				fmt.Sprintf(`"value":{"osm-proxy-uuid":"%v"`, proxyUUID),
 				// Add metrics Annotations
 				`"path":"/metadata/annotations"`,
-				`"value":{"prometheus.io/path":"/stats/prometheus","prometheus.io/port":"15010","prometheus.io/scrape":"true"}`,
+				`"prometheus.io/path":"/stats/prometheus","prometheus.io/port":"15010","prometheus.io/scrape":"true"}`,
 				// Add Volumes
 				`"path":"/spec/volumes"`,
 				fmt.Sprintf(`"value":[{"name":"envoy-bootstrap-config-volume","secret":{"secretName":"envoy-bootstrap-config-%v"}}]}`, proxyUUID),
@@ -108,6 +120,81 @@ func TestCreatePatch(t *testing.T) {
 				`"command":["envoy"]`,
 			},
 		},
+		{
+			name: "creates a patch for a unix worker with the CNI plugin enabled",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			featureFlags: configv1alpha1.FeatureFlags{EnableCNI: true, EnableIPv6: true},
+			expectedPatches: []string{
+				// Add iptables config Annotations
+				`"path":"/metadata/annotations"`,
+				`"value":{"openservicemesh.io/iptables-config":"{\"enableIPv6\":true}","openservicemesh.io/iptables-config-signature":`,
+				// Require a node the CNI plugin is installed on
+				`"path":"/spec/affinity"`,
+				`"nodeAffinity":{"requiredDuringSchedulingIgnoredDuringExecution":{"nodeSelectorTerms":[{"matchExpressions":[{"key":"openservicemesh.io/cni","operator":"Exists"}]}]}}`,
+				// Add Envoy Container
+				`"path":"/spec/containers"`,
+				`"command":["envoy"]`,
+			},
+			unexpectedPatches: []string{
+				// The Init Container is not added
+				`"path":"/spec/initContainers"`,
+			},
+		},
+		{
+			name: "creates a patch for a unix worker with a SidecarConfig",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			featureFlags: configv1alpha1.FeatureFlags{EnableCNI: true},
+			sidecarConfigs: []configv1alpha1.SidecarConfig{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "sidecar-config", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						HoldApplicationUntilProxyStarts: pointer.BoolPtr(true),
+						OutboundTrafficCapture: &configv1alpha1.OutboundTrafficCaptureSpec{
+							IPRangeExclusionList: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			expectedPatches: []string{
+				// Add iptables config Annotation
+				`"path":"/metadata/annotations"`,
+				`"value":{"openservicemesh.io/iptables-config":"{\"outboundIPRangeExclusionList\":[\"10.0.0.0/8\"]}"`,
+				// Add Envoy Container, holding the application containers until it is ready
+				`"path":"/spec/containers"`,
+				`"lifecycle":{"postStart":{"exec":{"command":["sh","-c"`,
+			},
+				sidecarFirst: true,
+		},
+		{
+			name: "creates a patch for a unix worker of a Job",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			ownerReferences: []metav1.OwnerReference{
+				{Kind: "Job", Name: "job", Controller: pointer.BoolPtr(true)},
+			},
+			expectedPatches: []string{
+				// Share the process namespace of the pod with the sidecar
+				`"path":"/spec/shareProcessNamespace","value":true`,
+				// Add Envoy Container, quitting once the application containers have terminated
+				`quitquitquit`,
+			},
+			// The sidecar watches the processes of the pod before the application containers start
+			sidecarFirst: true,
+		},
 		{
 			name: "unix dry run",
 			os:   constants.OSLinux,
@@ -128,13 +215,16 @@ func TestCreatePatch(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 			mockNsController := k8s.NewMockController(mockCtrl)
-			mockNsController.EXPECT().GetNamespace(namespace).Return(tc.namespace)
+			mockNsController.EXPECT().GetNamespace(namespace).Return(tc.namespace).Times(2)
+			mockConfigClient := config.NewMockController(mockCtrl)
+			mockConfigClient.EXPECT().ListSidecarConfigs(namespace).Return(tc.sidecarConfigs)
 			_, err := client.CoreV1().Namespaces().Create(context.TODO(), tc.namespace, metav1.CreateOptions{})
 			assert.NoError(err)
 
 			wh := &mutatingWebhook{
 				kubeClient:          client,
 				kubeController:      mockNsController,
+				configClient:        mockConfigClient,
 				certManager:         tresor.NewFakeCertManager(mockConfigurator),
 				configurator:        mockConfigurator,
 				nonInjectNamespaces: mapset.NewSet(),
@@ -144,17 +234,28 @@ func TestCreatePatch(t *testing.T) {
 			mockConfigurator.EXPECT().GetEnvoyImage().Return("envoy-windows-image").AnyTimes()
 			mockConfigurator.EXPECT().GetInitContainerImage().Return("init-container-image").AnyTimes()
 
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(tc.featureFlags).AnyTimes()
+
 			if tc.os == constants.OSLinux {
-				mockConfigurator.EXPECT().IsPrivilegedInitContainer().Return(false).Times(1)
+				if !tc.featureFlags.EnableCNI {
+					mockConfigurator.EXPECT().IsPrivilegedInitContainer().Return(false).Times(1)
+				}
 				mockConfigurator.EXPECT().GetOutboundIPRangeExclusionList().Return(nil).Times(1)
 				mockConfigurator.EXPECT().GetOutboundPortExclusionList().Return(nil).Times(1)
 				mockConfigurator.EXPECT().GetInboundPortExclusionList().Return(nil).Times(1)
+				mockConfigurator.EXPECT().GetOutboundUIDExclusionList().Return(nil).Times(1)
+				mockConfigurator.EXPECT().GetOutboundGIDExclusionList().Return(nil).Times(1)
 			}
 			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("").Times(1)
 			mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{}).Times(1)
+			mockConfigurator.EXPECT().GetProxyConcurrency().Return(0).Times(1)
+			mockConfigurator.EXPECT().GetProxyDrainDuration().Return(time.Duration(0)).Times(1)
+			mockConfigurator.EXPECT().IsHoldApplicationUntilProxyStarts().Return(false).Times(1)
+			mockConfigurator.EXPECT().GetProxyStatsTags().Return(nil).Times(1)
 			mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
 
 			pod := tests.NewOsSpecificPodFixture(namespace, podName, tests.BookstoreServiceAccountName, nil, tc.os)
+			pod.OwnerReferences = tc.ownerReferences
 
 			raw, err := json.Marshal(pod)
 			assert.NoError(err)
@@ -173,6 +274,15 @@ func TestCreatePatch(t *testing.T) {
 			for _, expectedPatch := range tc.expectedPatches {
 				assert.Contains(patches, expectedPatch)
 			}
+			for _, unexpectedPatch := range tc.unexpectedPatches {
+				assert.NotContains(patches, unexpectedPatch)
+			}
+
+			sidecarIndex := len(pod.Spec.Containers) - 1
+			if tc.sidecarFirst {
+				sidecarIndex = 0
+			}
+			assert.Equal(constants.EnvoyContainerName, pod.Spec.Containers[sidecarIndex].Name)
 		})
 	}
 
@@ -182,15 +292,26 @@ func TestCreatePatch(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
 		mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 		mockNsController := k8s.NewMockController(mockCtrl)
+		mockConfigClient := config.NewMockController(mockCtrl)
 
 		wh := &mutatingWebhook{
 			kubeClient:          client,
 			kubeController:      mockNsController,
+			configClient:        mockConfigClient,
 			certManager:         tresor.NewFakeCertManager(mockConfigurator),
 			configurator:        mockConfigurator,
 			nonInjectNamespaces: mapset.NewSet(),
 		}
 
+		mockNsController.EXPECT().GetNamespace("not-" + namespace).Return(nil)
+		mockConfigClient.EXPECT().ListSidecarConfigs("not-" + namespace).Return(nil)
+		mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{})
+		mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{})
+		mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("")
+		mockConfigurator.EXPECT().GetProxyConcurrency().Return(0)
+		mockConfigurator.EXPECT().GetProxyDrainDuration().Return(time.Duration(0))
+		mockConfigurator.EXPECT().IsHoldApplicationUntilProxyStarts().Return(false)
+		mockConfigurator.EXPECT().GetProxyStatsTags().Return(nil)
 		mockConfigurator.EXPECT().GetEnvoyImage().Return("")
 
 		pod := tests.NewOsSpecificPodFixture(namespace, podName, tests.BookstoreServiceAccountName, nil, constants.OSLinux)
@@ -207,7 +328,7 @@ func TestCreatePatch(t *testing.T) {
 	})
 }
 
-func TestMergePortExclusionLists(t *testing.T) {
+func TestMergeExclusionLists(t *testing.T) {
 	testCases := []struct {
 		name                              string
 		podOutboundPortExclusionList      []int
@@ -250,12 +371,49 @@ func TestMergePortExclusionLists(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
 
-			actual := mergePortExclusionLists(tc.podOutboundPortExclusionList, tc.globalOutboundPortExclusionList)
+			actual := mergeExclusionLists(tc.podOutboundPortExclusionList, tc.globalOutboundPortExclusionList)
 			assert.ElementsMatch(tc.expectedOutboundPortExclusionList, actual)
 		})
 	}
 }
 
+func TestMergeIPRangeExclusionLists(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		podIPRangeExclusionList []string
+		globalIPRangeExclusion  []string
+		expected                []string
+	}{
+		{
+			name:                    "overlap in global and pod IP range exclusion list",
+			podIPRangeExclusionList: []string{"10.0.0.0/8", "1.1.1.1/32"},
+			globalIPRangeExclusion:  []string{"10.0.0.0/8", "2.2.2.2/32"},
+			expected:                []string{"10.0.0.0/8", "2.2.2.2/32", "1.1.1.1/32"},
+		},
+		{
+			name:                    "pod IP range exclusion list is nil",
+			podIPRangeExclusionList: nil,
+			globalIPRangeExclusion:  []string{"10.0.0.0/8"},
+			expected:                []string{"10.0.0.0/8"},
+		},
+		{
+			name:                    "no global or pod IP range exclusion list",
+			podIPRangeExclusionList: nil,
+			globalIPRangeExclusion:  nil,
+			expected:                nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual := mergeIPRangeExclusionLists(tc.podIPRangeExclusionList, tc.globalIPRangeExclusion)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
+
 func TestVerifyPrerequisites(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -263,6 +421,7 @@ func TestVerifyPrerequisites(t *testing.T) {
 		linuxImage   string
 		windowsImage string
 		initImage    string
+		enableCNI    bool
 		expectErr    bool
 	}{
 		{
@@ -276,6 +435,12 @@ func TestVerifyPrerequisites(t *testing.T) {
 			linuxImage: "envoy",
 			expectErr:  true,
 		},
+		{
+			name:       "prereqs met for linux pod when init container image is missing with the CNI plugin enabled",
+			linuxImage: "envoy",
+			enableCNI:  true,
+			expectErr:  false,
+		},
 		{
 			name:      "prereqs not met for linux pod when envoy container image is missing",
 			initImage: "init",
@@ -316,9 +481,54 @@ func TestVerifyPrerequisites(t *testing.T) {
 			mockCfg.EXPECT().GetEnvoyImage().Return(tc.linuxImage).AnyTimes()
 			mockCfg.EXPECT().GetEnvoyWindowsImage().Return(tc.windowsImage).AnyTimes()
 			mockCfg.EXPECT().GetInitContainerImage().Return(tc.initImage).AnyTimes()
+			mockCfg.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{EnableCNI: tc.enableCNI}).AnyTimes()
 
 			err := wh.verifyPrerequisites(tc.podOS)
 			assert.Equal(tc.expectErr, err != nil)
 		})
 	}
 }
+
+func TestRequireCNINode(t *testing.T) {
+	cniRequirement := corev1.NodeSelectorRequirement{Key: constants.CNINodeLabel, Operator: corev1.NodeSelectorOpExists}
+	zoneRequirement := corev1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+	archRequirement := corev1.NodeSelectorRequirement{Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64"}}
+
+	testCases := []struct {
+		name     string
+		affinity *corev1.Affinity
+		expected []corev1.NodeSelectorTerm
+	}{
+		{
+			name:     "pod without affinity",
+			expected: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{cniRequirement}}},
+		},
+		{
+			name: "pod with node selector terms",
+			affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement}},
+							{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement}},
+						},
+					},
+				},
+			},
+			expected: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement, cniRequirement}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement, cniRequirement}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: tc.affinity}}
+			requireCNINode(pod)
+			assert.Equal(tc.expected, pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// SidecarRolloutLeaseName is the name of the Lease used to elect the injector replica running the sidecar rollout
+	SidecarRolloutLeaseName = "osm-sidecar-rollout"
+
+	// sidecarRolloutCheckInterval is the interval at which the pods running an outdated sidecar are detected
+	sidecarRolloutCheckInterval = time.Minute
+
+	// defaultSidecarRestartInterval is the minimum duration between two workload restarts when the configured one is invalid
+	defaultSidecarRestartInterval = 5 * time.Minute
+
+	// restartedAtAnnotation is the annotation of the pod template of a workload set to restart its pods,
+	// which is the annotation set by 'kubectl rollout restart'
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+	// maintenanceWindowTimeFormat is the format of the start and the end of a maintenance window
+	maintenanceWindowTimeFormat = "15:04"
+)
+
+// workload is a Deployment, StatefulSet or DaemonSet whose pods run a sidecar
+type workload struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (w workload) String() string {
+	return fmt.Sprintf("%s %s/%s", w.kind, w.namespace, w.name)
+}
+
+// sidecarRollout detects the pods running an outdated sidecar, whose injected configuration differs from the
+// configuration that would be injected with the current mesh configuration, and restarts their workloads for the
+// sidecar to be injected again. The workloads are restarted one at a time, at the configured rate and during the
+// configured maintenance window. A single replica of the sidecar injector runs the rollout at a time, and the time of
+// the last restart is recorded on the sidecar injector Deployment for the rate to apply across leadership changes.
+type sidecarRollout struct {
+	wh          *mutatingWebhook
+	lastRestart time.Time
+
+	// now returns the current time
+	now func() time.Time
+}
+
+// runSidecarRollout marks the pods running an outdated sidecar and restarts their workloads, when enabled in the
+// MeshConfig, until the given channel is closed
+func (wh *mutatingWebhook) runSidecarRollout(stop <-chan struct{}) {
+	r := &sidecarRollout{
+		wh:  wh,
+		now: time.Now,
+	}
+	r.loadLastRestart()
+
+	ticker := time.NewTicker(sidecarRolloutCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.reconcile()
+		}
+	}
+}
+
+// reconcile marks the pods running an outdated sidecar, and restarts the first of their workloads whose restart is
+// allowed when the rollout is enabled
+func (r *sidecarRollout) reconcile() {
+	outdatedWorkloads := r.markOutdatedPods()
+
+	rollout := r.wh.configurator.GetSidecarRolloutConfig()
+	if !rollout.Enable || len(outdatedWorkloads) == 0 || !r.isRestartAllowed(rollout) {
+		return
+	}
+
+	for _, w := range outdatedWorkloads {
+		restarted, err := r.restartWorkload(w)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error restarting %s running outdated sidecars", w)
+			continue
+		}
+		if restarted {
+			log.Info().Msgf("Restarted %s running outdated sidecars", w)
+			r.lastRestart = r.now()
+			if err := r.saveLastRestart(); err != nil {
+				log.Error().Err(err).Msg("Error recording the time of the last workload restart running outdated sidecars")
+			}
+			return
+		}
+	}
+}
+
+// markOutdatedPods sets the SidecarOutdatedAnnotation annotation on the pods running an outdated sidecar, removes it
+// from the other pods, and returns the workloads of the pods running an outdated sidecar
+func (r *sidecarRollout) markOutdatedPods() []workload {
+	workloads := make(map[workload]struct{})
+	for _, pod := range r.wh.kubeController.ListPods() {
+		// The pods injected before the hash of the sidecar configuration was recorded are ignored
+		if _, ok := pod.Labels[constants.EnvoyUniqueIDLabelName]; !ok {
+			continue
+		}
+		if _, ok := pod.Annotations[constants.SidecarHashAnnotation]; !ok || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		outdated, err := r.isOutdated(pod)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error checking if the sidecar of pod %s/%s is outdated", pod.Namespace, pod.Name)
+			continue
+		}
+		if err := r.setOutdatedAnnotation(pod, outdated); err != nil {
+			log.Error().Err(err).Msgf("Error annotating pod %s/%s running an outdated sidecar", pod.Namespace, pod.Name)
+		}
+		if !outdated {
+			continue
+		}
+
+		w, err := r.getWorkload(pod)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error getting the workload of pod %s/%s running an outdated sidecar", pod.Namespace, pod.Name)
+			continue
+		}
+		if w != nil {
+			workloads[*w] = struct{}{}
+		}
+	}
+
+	var outdatedWorkloads []workload
+	for w := range workloads {
+		outdatedWorkloads = append(outdatedWorkloads, w)
+	}
+	sort.Slice(outdatedWorkloads, func(i, j int) bool {
+		return outdatedWorkloads[i].String() < outdatedWorkloads[j].String()
+	})
+	return outdatedWorkloads
+}
+
+// isOutdated returns true if the configuration of the sidecar injected in the given pod differs from the configuration
+// that would be injected with the current mesh configuration
+func (r *sidecarRollout) isOutdated(pod *corev1.Pod) (bool, error) {
+	sidecarCfg, err := r.wh.getSidecarConfig(pod, pod.Namespace)
+	if err != nil {
+		return false, err
+	}
+	sidecarHash, err := r.wh.getSidecarTemplate(pod, pod.Namespace, sidecarCfg).hash()
+	if err != nil {
+		return false, err
+	}
+	return sidecarHash != pod.Annotations[constants.SidecarHashAnnotation], nil
+}
+
+// setOutdatedAnnotation sets or removes the SidecarOutdatedAnnotation annotation on the given pod
+func (r *sidecarRollout) setOutdatedAnnotation(pod *corev1.Pod, outdated bool) error {
+	_, annotated := pod.Annotations[constants.SidecarOutdatedAnnotation]
+	if outdated == annotated {
+		return nil
+	}
+
+	value := "null"
+	if outdated {
+		value = `"true"`
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s":%s}}}`, constants.SidecarOutdatedAnnotation, value)
+	_, err := r.wh.kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.Background(), pod.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// getWorkload returns the Deployment, StatefulSet or DaemonSet controlling the given pod, or nil if the pod is
+// controlled by another kind of controller, or by no controller
+func (r *sidecarRollout) getWorkload(pod *corev1.Pod) (*workload, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil, nil
+	}
+
+	switch owner.Kind {
+	case "StatefulSet", "DaemonSet":
+		return &workload{kind: owner.Kind, namespace: pod.Namespace, name: owner.Name}, nil
+
+	case "ReplicaSet":
+		replicaSet, err := r.wh.kubeClient.AppsV1().ReplicaSets(pod.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if deployment := metav1.GetControllerOf(replicaSet); deployment != nil && deployment.Kind == "Deployment" {
+			return &workload{kind: deployment.Kind, namespace: pod.Namespace, name: deployment.Name}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// restartWorkload restarts the pods of the given workload, unless a rollout of the workload is in progress,
+// returning true if the workload is restarted
+func (r *sidecarRollout) restartWorkload(w workload) (bool, error) {
+	ctx := context.Background()
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"%s":"%s"}}}}}`,
+		restartedAtAnnotation, r.now().Format(time.RFC3339)))
+
+	switch w.kind {
+	case "Deployment":
+		deployment, err := r.wh.kubeClient.AppsV1().Deployments(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if isDeploymentRollingOut(deployment) {
+			return false, nil
+		}
+		_, err = r.wh.kubeClient.AppsV1().Deployments(w.namespace).Patch(ctx, w.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		return err == nil, err
+
+	case "StatefulSet":
+		statefulSet, err := r.wh.kubeClient.AppsV1().StatefulSets(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if isStatefulSetRollingOut(statefulSet) {
+			return false, nil
+		}
+		_, err = r.wh.kubeClient.AppsV1().StatefulSets(w.namespace).Patch(ctx, w.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		return err == nil, err
+
+	case "DaemonSet":
+		daemonSet, err := r.wh.kubeClient.AppsV1().DaemonSets(w.namespace).Get(ctx, w.name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if isDaemonSetRollingOut(daemonSet) {
+			return false, nil
+		}
+		_, err = r.wh.kubeClient.AppsV1().DaemonSets(w.namespace).Patch(ctx, w.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		return err == nil, err
+	}
+
+	return false, errors.Errorf("Unsupported workload kind %s", w.kind)
+}
+
+// loadLastRestart reads the time of the last workload restart recorded on the sidecar injector Deployment
+func (r *sidecarRollout) loadLastRestart() {
+	deployment, err := r.wh.kubeClient.AppsV1().Deployments(r.wh.osmNamespace).Get(context.Background(), constants.OSMInjectorName, metav1.GetOptions{})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting Deployment %s/%s to read the time of the last workload restart", r.wh.osmNamespace, constants.OSMInjectorName)
+		return
+	}
+	lastRestart, ok := deployment.Annotations[constants.SidecarRolloutLastRestartAnnotation]
+	if !ok {
+		return
+	}
+	if r.lastRestart, err = time.Parse(time.RFC3339, lastRestart); err != nil {
+		log.Warn().Err(err).Msgf("Invalid time of the last workload restart %s on Deployment %s/%s", lastRestart, r.wh.osmNamespace, constants.OSMInjectorName)
+	}
+}
+
+// saveLastRestart records the time of the last workload restart on the sidecar injector Deployment
+func (r *sidecarRollout) saveLastRestart() error {
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}}}`, constants.SidecarRolloutLastRestartAnnotation, r.lastRestart.Format(time.RFC3339))
+	_, err := r.wh.kubeClient.AppsV1().Deployments(r.wh.osmNamespace).Patch(context.Background(), constants.OSMInjectorName, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// isRestartAllowed returns true if the configured duration elapsed since the last workload restart, and the current
+// time is within the configured maintenance window
+func (r *sidecarRollout) isRestartAllowed(rollout configv1alpha1.SidecarRolloutSpec) bool {
+	restartInterval, err := time.ParseDuration(rollout.RestartInterval)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Invalid sidecar restart interval %s, defaulting to %v", rollout.RestartInterval, defaultSidecarRestartInterval)
+		restartInterval = defaultSidecarRestartInterval
+	}
+	now := r.now()
+	if now.Sub(r.lastRestart) < restartInterval {
+		return false
+	}
+
+	if rollout.MaintenanceWindow == nil {
+		return true
+	}
+	inWindow, err := isInMaintenanceWindow(now, *rollout.MaintenanceWindow)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid sidecar rollout maintenance window, workloads running outdated sidecars are not restarted")
+		return false
+	}
+	return inWindow
+}
+
+// isInMaintenanceWindow returns true if the given time is within the given daily maintenance window
+func isInMaintenanceWindow(now time.Time, window configv1alpha1.MaintenanceWindowSpec) (bool, error) {
+	start, err := time.Parse(maintenanceWindowTimeFormat, window.Start)
+	if err != nil {
+		return false, err
+	}
+	end, err := time.Parse(maintenanceWindowTimeFormat, window.End)
+	if err != nil {
+		return false, err
+	}
+
+	minuteOfDay := func(t time.Time) int {
+		return t.Hour()*60 + t.Minute()
+	}
+	current, startMinute, endMinute := minuteOfDay(now.UTC()), minuteOfDay(start), minuteOfDay(end)
+	if startMinute <= endMinute {
+		return startMinute <= current && current < endMinute, nil
+	}
+	// The window spans midnight
+	return current >= startMinute || current < endMinute, nil
+}
+
+// isDeploymentRollingOut returns true if a rollout of the given Deployment is in progress
+func isDeploymentRollingOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration < deployment.Generation ||
+		deployment.Status.UpdatedReplicas < replicas ||
+		deployment.Status.Replicas > deployment.Status.UpdatedReplicas
+}
+
+// isStatefulSetRollingOut returns true if a rollout of the given StatefulSet is in progress
+func isStatefulSetRollingOut(statefulSet *appsv1.StatefulSet) bool {
+	return statefulSet.Status.ObservedGeneration < statefulSet.Generation ||
+		statefulSet.Status.CurrentRevision != statefulSet.Status.UpdateRevision
+}
+
+// isDaemonSetRollingOut returns true if a rollout of the given DaemonSet is in progress
+func isDaemonSetRollingOut(daemonSet *appsv1.DaemonSet) bool {
+	return daemonSet.Status.ObservedGeneration < daemonSet.Generation ||
+		daemonSet.Status.UpdatedNumberScheduled < daemonSet.Status.DesiredNumberScheduled
+}
@@ -0,0 +1,231 @@
+package injector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/config"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestIsInMaintenanceWindow(t *testing.T) {
+	testCases := []struct {
+		name     string
+		now      string
+		window   configv1alpha1.MaintenanceWindowSpec
+		expected bool
+	}{
+		{
+			name:     "within the window",
+			now:      "2021-09-01T03:30:00Z",
+			window:   configv1alpha1.MaintenanceWindowSpec{Start: "02:00", End: "04:00"},
+			expected: true,
+		},
+		{
+			name:     "at the end of the window",
+			now:      "2021-09-01T04:00:00Z",
+			window:   configv1alpha1.MaintenanceWindowSpec{Start: "02:00", End: "04:00"},
+			expected: false,
+		},
+		{
+			name:     "within the window in another time zone",
+			now:      "2021-09-01T05:30:00+02:00",
+			window:   configv1alpha1.MaintenanceWindowSpec{Start: "02:00", End: "04:00"},
+			expected: true,
+		},
+		{
+			name:     "within a window spanning midnight",
+			now:      "2021-09-01T23:30:00Z",
+			window:   configv1alpha1.MaintenanceWindowSpec{Start: "22:00", End: "02:00"},
+			expected: true,
+		},
+		{
+			name:     "outside a window spanning midnight",
+			now:      "2021-09-01T12:00:00Z",
+			window:   configv1alpha1.MaintenanceWindowSpec{Start: "22:00", End: "02:00"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			now, err := time.Parse(time.RFC3339, tc.now)
+			assert.Nil(err)
+
+			actual, err := isInMaintenanceWindow(now, tc.window)
+			assert.Nil(err)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+
+	_, err := isInMaintenanceWindow(time.Now(), configv1alpha1.MaintenanceWindowSpec{Start: "2am", End: "04:00"})
+	tassert.NotNil(t, err)
+}
+
+func TestIsRestartAllowed(t *testing.T) {
+	assert := tassert.New(t)
+
+	now := time.Date(2021, 9, 1, 3, 0, 0, 0, time.UTC)
+	r := &sidecarRollout{now: func() time.Time { return now }}
+
+	assert.True(r.isRestartAllowed(configv1alpha1.SidecarRolloutSpec{Enable: true, RestartInterval: "5m"}))
+
+	r.lastRestart = now.Add(-time.Minute)
+	assert.False(r.isRestartAllowed(configv1alpha1.SidecarRolloutSpec{Enable: true, RestartInterval: "5m"}))
+	assert.True(r.isRestartAllowed(configv1alpha1.SidecarRolloutSpec{Enable: true, RestartInterval: "30s"}))
+
+	r.lastRestart = time.Time{}
+	assert.False(r.isRestartAllowed(configv1alpha1.SidecarRolloutSpec{
+		Enable:            true,
+		RestartInterval:   "5m",
+		MaintenanceWindow: &configv1alpha1.MaintenanceWindowSpec{Start: "22:00", End: "02:00"},
+	}))
+}
+
+func TestSidecarRolloutReconcile(t *testing.T) {
+	assert := tassert.New(t)
+
+	const namespace = "ns"
+	now := time.Date(2021, 9, 1, 3, 0, 0, 0, time.UTC)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: namespace, Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32Ptr(1)},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bookstore-5ccf77f46d",
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "bookstore", Controller: pointer.BoolPtr(true)},
+			},
+		},
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: namespace},
+		Status:     appsv1.StatefulSetStatus{CurrentRevision: "mysql-1", UpdateRevision: "mysql-2"},
+	}
+	newPod := func(name string, sidecarHash string, owner metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				Labels:          map[string]string{constants.EnvoyUniqueIDLabelName: name},
+				Annotations:     map[string]string{constants.SidecarHashAnnotation: sidecarHash},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+		}
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigClient := config.NewMockController(mockCtrl)
+
+	rolloutConfig := mockConfigurator.EXPECT().GetSidecarRolloutConfig().Return(configv1alpha1.SidecarRolloutSpec{Enable: false}).Times(1)
+	mockConfigurator.EXPECT().GetSidecarRolloutConfig().Return(configv1alpha1.SidecarRolloutSpec{Enable: true, RestartInterval: "5m"}).After(rolloutConfig).AnyTimes()
+	mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{}).AnyTimes()
+	mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("error").AnyTimes()
+	mockConfigurator.EXPECT().GetProxyConcurrency().Return(0).AnyTimes()
+	mockConfigurator.EXPECT().GetProxyDrainDuration().Return(time.Duration(0)).AnyTimes()
+	mockConfigurator.EXPECT().IsHoldApplicationUntilProxyStarts().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetProxyStatsTags().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetEnvoyImage().Return("envoyproxy/envoy-alpine:v1.19.1").AnyTimes()
+	mockConfigurator.EXPECT().GetInitContainerImage().Return("openservicemesh/init:v0.10.0").AnyTimes()
+	mockConfigurator.EXPECT().IsPrivilegedInitContainer().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
+	mockConfigurator.EXPECT().GetOutboundIPRangeExclusionList().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetOutboundPortExclusionList().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetInboundPortExclusionList().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetOutboundUIDExclusionList().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetOutboundGIDExclusionList().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}).AnyTimes()
+	mockConfigClient.EXPECT().ListSidecarConfigs(namespace).Return(nil).AnyTimes()
+
+	wh := &mutatingWebhook{
+		osmNamespace:   "osm-system",
+		kubeController: mockKubeController,
+		configClient:   mockConfigClient,
+		configurator:   mockConfigurator,
+	}
+
+	// Compute the hash of the sidecar injected with the current configuration
+	upToDatePod := newPod("up-to-date", "", metav1.OwnerReference{Kind: "ReplicaSet", Name: replicaSet.Name, Controller: pointer.BoolPtr(true)})
+	sidecarCfg, err := wh.getSidecarConfig(upToDatePod, namespace)
+	assert.Nil(err)
+	upToDateHash, err := wh.getSidecarTemplate(upToDatePod, namespace, sidecarCfg).hash()
+	assert.Nil(err)
+	upToDatePod.Annotations[constants.SidecarHashAnnotation] = upToDateHash
+	upToDatePod.Annotations[constants.SidecarOutdatedAnnotation] = "true"
+
+	injectorDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.OSMInjectorName, Namespace: wh.osmNamespace},
+	}
+	pods := []*corev1.Pod{
+		upToDatePod,
+		newPod("bookstore", "outdated", metav1.OwnerReference{Kind: "ReplicaSet", Name: replicaSet.Name, Controller: pointer.BoolPtr(true)}),
+		newPod("mysql-0", "outdated", metav1.OwnerReference{Kind: "StatefulSet", Name: statefulSet.Name, Controller: pointer.BoolPtr(true)}),
+		newPod("job", "outdated", metav1.OwnerReference{Kind: "Job", Name: "job", Controller: pointer.BoolPtr(true)}),
+	}
+	objects := []runtime.Object{deployment, replicaSet, statefulSet, injectorDeployment}
+	for _, pod := range pods {
+		objects = append(objects, pod)
+	}
+	wh.kubeClient = fake.NewSimpleClientset(objects...)
+	mockKubeController.EXPECT().ListPods().Return(pods).AnyTimes()
+
+	r := &sidecarRollout{wh: wh, now: func() time.Time { return now }}
+	r.loadLastRestart()
+	assert.True(r.lastRestart.IsZero())
+
+	assertOutdatedPods := func() {
+		for podName, outdated := range map[string]bool{"up-to-date": false, "bookstore": true, "mysql-0": true, "job": true} {
+			pod, err := wh.kubeClient.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+			assert.Nil(err)
+			_, annotated := pod.Annotations[constants.SidecarOutdatedAnnotation]
+			assert.Equal(outdated, annotated, podName)
+		}
+	}
+
+	// The pods running an outdated sidecar are annotated when the rollout is disabled, but no workload is restarted
+	r.reconcile()
+	assertOutdatedPods()
+	unchangedDeployment, err := wh.kubeClient.AppsV1().Deployments(namespace).Get(context.Background(), deployment.Name, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Empty(unchangedDeployment.Spec.Template.Annotations)
+	assert.True(r.lastRestart.IsZero())
+
+	r.reconcile()
+	assertOutdatedPods()
+
+	// Only the Deployment is restarted, as the StatefulSet is being rolled out
+	updatedDeployment, err := wh.kubeClient.AppsV1().Deployments(namespace).Get(context.Background(), deployment.Name, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(now.Format(time.RFC3339), updatedDeployment.Spec.Template.Annotations[restartedAtAnnotation])
+	updatedStatefulSet, err := wh.kubeClient.AppsV1().StatefulSets(namespace).Get(context.Background(), statefulSet.Name, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Empty(updatedStatefulSet.Spec.Template.Annotations)
+	assert.Equal(now, r.lastRestart)
+
+	// The time of the last restart is recorded for the next replica running the rollout
+	next := &sidecarRollout{wh: wh, now: func() time.Time { return now }}
+	next.loadLastRestart()
+	assert.True(now.Equal(next.lastRestart))
+	assert.False(next.isRestartAllowed(configv1alpha1.SidecarRolloutSpec{Enable: true, RestartInterval: "5m"}))
+}
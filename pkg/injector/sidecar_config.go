@@ -0,0 +1,389 @@
+package injector
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// envoyLogLevels are the log levels supported by Envoy
+var envoyLogLevels = []string{"trace", "debug", "info", "warning", "warn", "error", "critical", "off"}
+
+// sidecarConfig is the configuration of the sidecar of a pod, resolved from the MeshConfig, the SidecarConfig resources
+// and the annotations overriding it on the pod and on its namespace
+type sidecarConfig struct {
+	resources corev1.ResourceRequirements
+	logLevel  string
+
+	// concurrency is the number of worker threads of the sidecar, 0 to use the number of hardware threads of the node
+	concurrency int
+
+	// image is the image of the sidecar, empty to use the image configured in the MeshConfig for the OS of the pod
+	image string
+
+	// drainDuration is the duration for which the sidecar drains its connections when shutting down, 0 to use the Envoy default
+	drainDuration time.Duration
+
+	// holdApplicationUntilProxyStarts indicates whether the application containers are started only once the sidecar is ready
+	holdApplicationUntilProxyStarts bool
+
+	// statsTags are the tags, and their fixed values, added to all the stats of the sidecar
+	statsTags map[string]string
+
+	// quitOnApplicationExit indicates whether the sidecar exits once all the application containers of the pod have
+	// terminated, for the pods of Jobs to complete
+	quitOnApplicationExit bool
+
+	// disableQuitOnApplicationExit indicates whether the sidecar of the pods of Jobs is opted out of quitting
+	// once the application containers have terminated, and the process namespace of the pod is not shared
+	disableQuitOnApplicationExit bool
+
+	// outboundIPRangeExclusionList and outboundPortExclusionList are the outbound traffic exclusions of the SidecarConfig
+	// resources, in addition to the exclusions of the MeshConfig. The IP ranges include the ones annotated on the pod.
+	outboundIPRangeExclusionList []string
+	outboundPortExclusionList    []int
+
+	// outboundUIDExclusionList and outboundGIDExclusionList are the user and group IDs annotated on the pod whose
+	// outbound traffic is not intercepted, in addition to the exclusions of the MeshConfig
+	outboundUIDExclusionList []int
+	outboundGIDExclusionList []int
+}
+
+// sidecarResourceAnnotations are the annotations overriding the resources of the sidecar
+var sidecarResourceAnnotations = []struct {
+	annotation   string
+	resourceName corev1.ResourceName
+	isLimit      bool
+}{
+	{constants.SidecarCPURequestAnnotation, corev1.ResourceCPU, false},
+	{constants.SidecarCPULimitAnnotation, corev1.ResourceCPU, true},
+	{constants.SidecarMemoryRequestAnnotation, corev1.ResourceMemory, false},
+	{constants.SidecarMemoryLimitAnnotation, corev1.ResourceMemory, true},
+}
+
+// getSidecarConfig returns the configuration of the sidecar of the given pod. The settings are resolved by increasing
+// precedence from the MeshConfig, the SidecarConfig without selector in the namespace of the pod, the annotations on
+// the namespace, the SidecarConfig selecting the pod, and the annotations on the pod.
+// An error is returned if any of the annotations has an invalid value.
+func (wh *mutatingWebhook) getSidecarConfig(pod *corev1.Pod, namespace string) (*sidecarConfig, error) {
+	// The resources and stats tags are copied to not modify the MeshConfig
+	resources := wh.configurator.GetProxyResources()
+	sidecarCfg := &sidecarConfig{
+		resources:                       *resources.DeepCopy(),
+		logLevel:                        wh.configurator.GetEnvoyLogLevel(),
+		concurrency:                     wh.configurator.GetProxyConcurrency(),
+		drainDuration:                   wh.configurator.GetProxyDrainDuration(),
+		holdApplicationUntilProxyStarts: wh.configurator.IsHoldApplicationUntilProxyStarts(),
+		statsTags:                       make(map[string]string),
+	}
+	for tagName, tagValue := range wh.configurator.GetProxyStatsTags() {
+		sidecarCfg.statsTags[tagName] = tagValue
+	}
+
+	podAnnotations := getSidecarAnnotations(pod.Annotations, "pod")
+	var namespaceAnnotations map[string]sidecarAnnotation
+	if ns := wh.kubeController.GetNamespace(namespace); ns != nil {
+		namespaceAnnotations = getSidecarAnnotations(ns.Annotations, fmt.Sprintf("namespace %s", ns.Name))
+		// The annotations on the namespace overridden by the annotations on the pod are ignored
+		for key := range podAnnotations {
+			delete(namespaceAnnotations, key)
+		}
+	}
+	namespaceSidecarConfig, workloadSidecarConfig := wh.getSidecarConfigsForPod(pod, namespace)
+
+	sidecarCfg.applySidecarConfig(namespaceSidecarConfig)
+	if err := sidecarCfg.applyAnnotations(namespaceAnnotations); err != nil {
+		return nil, err
+	}
+	sidecarCfg.applySidecarConfig(workloadSidecarConfig)
+	if err := sidecarCfg.applyAnnotations(podAnnotations); err != nil {
+		return nil, err
+	}
+	if err := sidecarCfg.applyOutboundExclusionAnnotations(pod.Annotations); err != nil {
+		return nil, err
+	}
+
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := sidecarCfg.resources.Requests[resourceName]
+		limit, hasLimit := sidecarCfg.resources.Limits[resourceName]
+		if hasRequest && hasLimit && request.Cmp(limit) > 0 {
+			return nil, errors.Errorf("Sidecar %s request %s must be less than or equal to its limit %s", resourceName, request.String(), limit.String())
+		}
+	}
+
+	// The application containers of Windows pods cannot be held, as the Envoy Windows image has no shell to wait for the sidecar
+	isWindows := strings.EqualFold(pod.Spec.NodeSelector["kubernetes.io/os"], constants.OSWindows)
+	if sidecarCfg.holdApplicationUntilProxyStarts && isWindows {
+		log.Warn().Msgf("Ignoring holdApplicationUntilProxyStarts for Windows pod %s/%s", namespace, pod.Name)
+		sidecarCfg.holdApplicationUntilProxyStarts = false
+	}
+
+	// The sidecar of the pods of Jobs watches the processes of the application containers, which requires the
+	// process namespace of the pod to be shared between its containers
+	if isJobPod(pod) && !isWindows {
+		if sidecarCfg.disableQuitOnApplicationExit {
+			log.Debug().Msgf("Quitting on application exit is disabled for Job pod %s/%s", namespace, pod.Name)
+		} else if pod.Spec.ShareProcessNamespace != nil && !*pod.Spec.ShareProcessNamespace {
+			log.Warn().Msgf("Process namespace sharing is disabled for Job pod %s/%s, its sidecar will not exit once the Job completes", namespace, pod.Name)
+		} else {
+			sidecarCfg.quitOnApplicationExit = true
+		}
+	}
+
+	return sidecarCfg, nil
+}
+
+// getSidecarConfigsForPod returns the SidecarConfig without selector in the given namespace, and the SidecarConfig
+// selecting the given pod. When several SidecarConfigs apply at the same level, the oldest one is used.
+func (wh *mutatingWebhook) getSidecarConfigsForPod(pod *corev1.Pod, namespace string) (namespaceSidecarConfig, workloadSidecarConfig *configv1alpha1.SidecarConfig) {
+	for _, sc := range wh.configClient.ListSidecarConfigs(namespace) {
+		sc := sc
+		if sc.Spec.Selector == nil {
+			namespaceSidecarConfig = oldestSidecarConfig(namespaceSidecarConfig, &sc)
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(sc.Spec.Selector)
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid selector in SidecarConfig %s/%s, ignoring it", sc.Namespace, sc.Name)
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			workloadSidecarConfig = oldestSidecarConfig(workloadSidecarConfig, &sc)
+		}
+	}
+	return
+}
+
+// isJobPod returns true if the given pod is controlled by a Job, including the Jobs created by CronJobs
+func isJobPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			return ref.Kind == "Job"
+		}
+	}
+	return false
+}
+
+// oldestSidecarConfig returns the oldest of the given SidecarConfigs, ordered by name when created at the same time
+func oldestSidecarConfig(current, candidate *configv1alpha1.SidecarConfig) *configv1alpha1.SidecarConfig {
+	if current == nil {
+		return candidate
+	}
+	log.Warn().Msgf("SidecarConfigs %s and %s in namespace %s apply to the same pods, only the oldest one is used",
+		current.Name, candidate.Name, current.Namespace)
+
+	if candidate.CreationTimestamp.Before(&current.CreationTimestamp) ||
+		(candidate.CreationTimestamp.Equal(&current.CreationTimestamp) && candidate.Name < current.Name) {
+		return candidate
+	}
+	return current
+}
+
+// applySidecarConfig overrides the sidecar configuration with the settings of the given SidecarConfig
+func (c *sidecarConfig) applySidecarConfig(sc *configv1alpha1.SidecarConfig) {
+	if sc == nil {
+		return
+	}
+	spec := sc.Spec
+
+	if spec.LogLevel != "" {
+		c.logLevel = strings.ToLower(spec.LogLevel)
+	}
+	if spec.Resources != nil {
+		for resourceName, quantity := range spec.Resources.Requests {
+			c.setResource(resourceName, quantity, false)
+		}
+		for resourceName, quantity := range spec.Resources.Limits {
+			c.setResource(resourceName, quantity, true)
+		}
+	}
+	if spec.Concurrency != nil {
+		c.concurrency = *spec.Concurrency
+	}
+	if spec.DrainDuration != nil {
+		c.drainDuration = spec.DrainDuration.Duration
+	}
+	if spec.HoldApplicationUntilProxyStarts != nil {
+		c.holdApplicationUntilProxyStarts = *spec.HoldApplicationUntilProxyStarts
+	}
+	for tagName, tagValue := range spec.StatsTags {
+		c.statsTags[tagName] = tagValue
+	}
+	if spec.OutboundTrafficCapture != nil {
+		c.outboundIPRangeExclusionList = spec.OutboundTrafficCapture.IPRangeExclusionList
+		c.outboundPortExclusionList = spec.OutboundTrafficCapture.PortExclusionList
+	}
+}
+
+// applyAnnotations overrides the sidecar configuration with the given annotations,
+// returning an error if any of the annotations has an invalid value
+func (c *sidecarConfig) applyAnnotations(annotations map[string]sidecarAnnotation) error {
+	for _, r := range sidecarResourceAnnotations {
+		value, ok := annotations[r.annotation]
+		if !ok {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value.value)
+		if err != nil {
+			return value.invalid(r.annotation, err.Error())
+		}
+		if quantity.Sign() < 0 {
+			return value.invalid(r.annotation, "must be greater than or equal to 0")
+		}
+		c.setResource(r.resourceName, quantity, r.isLimit)
+	}
+
+	if value, ok := annotations[constants.SidecarLogLevelAnnotation]; ok {
+		logLevel := strings.ToLower(value.value)
+		if !isValidEnvoyLogLevel(logLevel) {
+			return value.invalid(constants.SidecarLogLevelAnnotation, fmt.Sprintf("must be one of %v", envoyLogLevels))
+		}
+		c.logLevel = logLevel
+	}
+
+	if value, ok := annotations[constants.SidecarConcurrencyAnnotation]; ok {
+		concurrency, err := strconv.Atoi(value.value)
+		if err != nil || concurrency <= 0 {
+			return value.invalid(constants.SidecarConcurrencyAnnotation, "must be a positive integer")
+		}
+		c.concurrency = concurrency
+	}
+
+	if value, ok := annotations[constants.SidecarImageAnnotation]; ok {
+		if value.value == "" || strings.ContainsAny(value.value, " \t\n") {
+			return value.invalid(constants.SidecarImageAnnotation, "must be a non-empty image reference without whitespaces")
+		}
+		c.image = value.value
+	}
+
+	if value, ok := annotations[constants.SidecarHoldApplicationUntilProxyStartsAnnotation]; ok {
+		hold, err := strconv.ParseBool(value.value)
+		if err != nil {
+			return value.invalid(constants.SidecarHoldApplicationUntilProxyStartsAnnotation, "must be a boolean")
+		}
+		c.holdApplicationUntilProxyStarts = hold
+	}
+
+	if value, ok := annotations[constants.SidecarQuitOnApplicationExitAnnotation]; ok {
+		quit, err := strconv.ParseBool(value.value)
+		if err != nil {
+			return value.invalid(constants.SidecarQuitOnApplicationExitAnnotation, "must be a boolean")
+		}
+		c.disableQuitOnApplicationExit = !quit
+	}
+
+	return nil
+}
+
+// applyOutboundExclusionAnnotations adds the outbound traffic exclusions annotated on a pod to the sidecar configuration,
+// returning an error if any of the annotations has an invalid value
+func (c *sidecarConfig) applyOutboundExclusionAnnotations(annotations map[string]string) error {
+	if value, ok := annotations[outboundIPRangeExclusionListAnnotation]; ok {
+		annotation := sidecarAnnotation{value: value, source: "pod"}
+		var ipRanges []string
+		for _, ipRange := range splitAnnotationList(value) {
+			if _, _, err := net.ParseCIDR(ipRange); err != nil {
+				return annotation.invalid(outboundIPRangeExclusionListAnnotation, "must be a comma separated list of IP ranges of the form x.x.x.x/y")
+			}
+			ipRanges = append(ipRanges, ipRange)
+		}
+		c.outboundIPRangeExclusionList = mergeIPRangeExclusionLists(ipRanges, c.outboundIPRangeExclusionList)
+	}
+
+	for _, id := range []struct {
+		annotation string
+		list       *[]int
+	}{
+		{outboundUIDExclusionListAnnotation, &c.outboundUIDExclusionList},
+		{outboundGIDExclusionListAnnotation, &c.outboundGIDExclusionList},
+	} {
+		value, ok := annotations[id.annotation]
+		if !ok {
+			continue
+		}
+		annotation := sidecarAnnotation{value: value, source: "pod"}
+		for _, idStr := range splitAnnotationList(value) {
+			// User and group IDs are unsigned 32-bit integers
+			parsed, err := strconv.ParseUint(idStr, 10, 32)
+			if err != nil {
+				return annotation.invalid(id.annotation, "must be a comma separated list of non-negative integers")
+			}
+			*id.list = append(*id.list, int(parsed))
+		}
+	}
+
+	return nil
+}
+
+// splitAnnotationList returns the items of the given comma separated annotation value
+func splitAnnotationList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setResource sets the request or the limit of the given resource of the sidecar
+func (c *sidecarConfig) setResource(resourceName corev1.ResourceName, quantity resource.Quantity, isLimit bool) {
+	resourceList := &c.resources.Requests
+	if isLimit {
+		resourceList = &c.resources.Limits
+	}
+	if *resourceList == nil {
+		*resourceList = make(corev1.ResourceList)
+	}
+	(*resourceList)[resourceName] = quantity
+}
+
+// sidecarAnnotation is the value of an annotation overriding a sidecar setting, and the object it is set on
+type sidecarAnnotation struct {
+	value  string
+	source string
+}
+
+// invalid returns the error for an invalid annotation value
+func (a sidecarAnnotation) invalid(annotation string, reason string) error {
+	return errors.Errorf("Invalid value %q for annotation %q on %s: %s", a.value, annotation, a.source, reason)
+}
+
+// getSidecarAnnotations returns the annotations overriding the sidecar settings among the given annotations of an object
+func getSidecarAnnotations(annotations map[string]string, source string) map[string]sidecarAnnotation {
+	keys := []string{constants.SidecarLogLevelAnnotation, constants.SidecarConcurrencyAnnotation, constants.SidecarImageAnnotation,
+		constants.SidecarHoldApplicationUntilProxyStartsAnnotation, constants.SidecarQuitOnApplicationExitAnnotation}
+	for _, r := range sidecarResourceAnnotations {
+		keys = append(keys, r.annotation)
+	}
+
+	sidecarAnnotations := make(map[string]sidecarAnnotation)
+	for _, key := range keys {
+		if value, ok := annotations[key]; ok {
+			sidecarAnnotations[key] = sidecarAnnotation{value: strings.TrimSpace(value), source: source}
+		}
+	}
+	return sidecarAnnotations
+}
+
+// isValidEnvoyLogLevel returns true if the given log level is supported by Envoy
+func isValidEnvoyLogLevel(logLevel string) bool {
+	for _, l := range envoyLogLevels {
+		if l == logLevel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,410 @@
+package injector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/config"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestGetSidecarConfig(t *testing.T) {
+	const namespace = "ns"
+
+	meshConfigResources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("512M"),
+		},
+	}
+
+	testCases := []struct {
+		name                 string
+		podAnnotations       map[string]string
+		namespaceAnnotations map[string]string
+		sidecarConfigs       []configv1alpha1.SidecarConfig
+		nodeSelector         map[string]string
+		ownerReferences      []metav1.OwnerReference
+		expectedConfig       *sidecarConfig
+		expectedErr          string
+	}{
+		{
+			name: "no annotations",
+			expectedConfig: &sidecarConfig{
+				resources: meshConfigResources,
+				logLevel:  "error",
+				statsTags: map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "annotations on the pod",
+			podAnnotations: map[string]string{
+				constants.SidecarCPURequestAnnotation:    "100m",
+				constants.SidecarMemoryRequestAnnotation: "64Mi",
+				constants.SidecarMemoryLimitAnnotation:   "128Mi",
+				constants.SidecarLogLevelAnnotation:      "DEBUG",
+				constants.SidecarConcurrencyAnnotation:   "2",
+				constants.SidecarImageAnnotation:         "envoyproxy/envoy-alpine:v1.19.1",
+			},
+			expectedConfig: &sidecarConfig{
+				resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("64Mi"),
+					},
+				},
+				logLevel:    "debug",
+				concurrency: 2,
+				image:       "envoyproxy/envoy-alpine:v1.19.1",
+				statsTags:   map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "annotations on the pod take precedence over the annotations on the namespace",
+			podAnnotations: map[string]string{
+				constants.SidecarLogLevelAnnotation: "info",
+			},
+			namespaceAnnotations: map[string]string{
+				constants.SidecarLogLevelAnnotation:    "trace",
+				constants.SidecarConcurrencyAnnotation: "4",
+			},
+			expectedConfig: &sidecarConfig{
+				resources:   meshConfigResources,
+				logLevel:    "info",
+				concurrency: 4,
+				statsTags:   map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "SidecarConfig selecting the pod takes precedence over the SidecarConfig of the namespace",
+			sidecarConfigs: []configv1alpha1.SidecarConfig{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "namespace", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						LogLevel:                        "info",
+						Concurrency:                     pointer.IntPtr(4),
+						HoldApplicationUntilProxyStarts: pointer.BoolPtr(true),
+						StatsTags:                       map[string]string{"team": "store", "mesh": "store-mesh"},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bookstore"}},
+						LogLevel:      "DEBUG",
+						DrainDuration: &metav1.Duration{Duration: 30 * time.Second},
+						StatsTags:     map[string]string{"app": "bookstore"},
+						OutboundTrafficCapture: &configv1alpha1.OutboundTrafficCaptureSpec{
+							IPRangeExclusionList: []string{"10.0.0.0/8"},
+							PortExclusionList:    []int{6379},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "other-workload", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bookbuyer"}},
+						LogLevel: "trace",
+					},
+				},
+			},
+			expectedConfig: &sidecarConfig{
+				resources:                       meshConfigResources,
+				logLevel:                        "debug",
+				concurrency:                     4,
+				drainDuration:                   30 * time.Second,
+				holdApplicationUntilProxyStarts: true,
+				statsTags:                       map[string]string{"mesh": "store-mesh", "team": "store", "app": "bookstore"},
+				outboundIPRangeExclusionList:    []string{"10.0.0.0/8"},
+				outboundPortExclusionList:       []int{6379},
+			},
+		},
+		{
+			name: "annotations take precedence over the SidecarConfig of the same level",
+			sidecarConfigs: []configv1alpha1.SidecarConfig{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "namespace", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						Concurrency:                     pointer.IntPtr(4),
+						HoldApplicationUntilProxyStarts: pointer.BoolPtr(true),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bookstore"}},
+						LogLevel: "debug",
+					},
+				},
+			},
+			podAnnotations: map[string]string{
+				constants.SidecarLogLevelAnnotation:                        "info",
+				constants.SidecarHoldApplicationUntilProxyStartsAnnotation: "false",
+			},
+			namespaceAnnotations: map[string]string{
+				constants.SidecarConcurrencyAnnotation: "2",
+				constants.SidecarLogLevelAnnotation:    "trace",
+			},
+			expectedConfig: &sidecarConfig{
+				resources:   meshConfigResources,
+				logLevel:    "info",
+				concurrency: 2,
+				statsTags:   map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "oldest SidecarConfig of the namespace is used",
+			sidecarConfigs: []configv1alpha1.SidecarConfig{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "newer", Namespace: namespace, CreationTimestamp: metav1.NewTime(time.Unix(200, 0))},
+					Spec:       configv1alpha1.SidecarConfigSpec{LogLevel: "trace"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "older", Namespace: namespace, CreationTimestamp: metav1.NewTime(time.Unix(100, 0))},
+					Spec:       configv1alpha1.SidecarConfigSpec{LogLevel: "info"},
+				},
+			},
+			expectedConfig: &sidecarConfig{
+				resources: meshConfigResources,
+				logLevel:  "info",
+				statsTags: map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "application containers of Windows pods are not held",
+			sidecarConfigs: []configv1alpha1.SidecarConfig{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "namespace", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						HoldApplicationUntilProxyStarts: pointer.BoolPtr(true),
+					},
+				},
+			},
+			nodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+			expectedConfig: &sidecarConfig{
+				resources: meshConfigResources,
+				logLevel:  "error",
+				statsTags: map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "sidecar of a Job pod quits once the application exits",
+			ownerReferences: []metav1.OwnerReference{
+				{Kind: "Job", Name: "job", Controller: pointer.BoolPtr(true)},
+			},
+			expectedConfig: &sidecarConfig{
+				resources:             meshConfigResources,
+				logLevel:              "error",
+				statsTags:             map[string]string{"mesh": "osm"},
+				quitOnApplicationExit: true,
+			},
+		},
+		{
+			name: "sidecar of a Job pod opted out of quitting once the application exits",
+			podAnnotations: map[string]string{
+				constants.SidecarQuitOnApplicationExitAnnotation: "false",
+			},
+			ownerReferences: []metav1.OwnerReference{
+				{Kind: "Job", Name: "job", Controller: pointer.BoolPtr(true)},
+			},
+			expectedConfig: &sidecarConfig{
+				resources:                    meshConfigResources,
+				logLevel:                     "error",
+				statsTags:                    map[string]string{"mesh": "osm"},
+				disableQuitOnApplicationExit: true,
+			},
+		},
+		{
+			name: "invalid quit on application exit annotation",
+			podAnnotations: map[string]string{
+				constants.SidecarQuitOnApplicationExitAnnotation: "no",
+			},
+			expectedErr: "openservicemesh.io/sidecar-quit-on-application-exit",
+		},
+		{
+			name: "sidecar of a Windows Job pod does not quit once the application exits",
+			ownerReferences: []metav1.OwnerReference{
+				{Kind: "Job", Name: "job", Controller: pointer.BoolPtr(true)},
+			},
+			nodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+			expectedConfig: &sidecarConfig{
+				resources: meshConfigResources,
+				logLevel:  "error",
+				statsTags: map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "sidecar of a pod owned by a ReplicaSet does not quit",
+			ownerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "rs", Controller: pointer.BoolPtr(true)},
+			},
+			expectedConfig: &sidecarConfig{
+				resources: meshConfigResources,
+				logLevel:  "error",
+				statsTags: map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "invalid resource quantity",
+			podAnnotations: map[string]string{
+				constants.SidecarCPULimitAnnotation: "one",
+			},
+			expectedErr: `Invalid value "one" for annotation "openservicemesh.io/sidecar-cpu-limit" on pod`,
+		},
+		{
+			name: "negative resource quantity",
+			namespaceAnnotations: map[string]string{
+				constants.SidecarMemoryRequestAnnotation: "-1Mi",
+			},
+			expectedErr: `Invalid value "-1Mi" for annotation "openservicemesh.io/sidecar-memory-request" on namespace ns: must be greater than or equal to 0`,
+		},
+		{
+			name: "request greater than the limit",
+			podAnnotations: map[string]string{
+				constants.SidecarCPURequestAnnotation: "2",
+			},
+			expectedErr: "Sidecar cpu request 2 must be less than or equal to its limit 1",
+		},
+		{
+			name: "invalid log level",
+			podAnnotations: map[string]string{
+				constants.SidecarLogLevelAnnotation: "verbose",
+			},
+			expectedErr: `Invalid value "verbose" for annotation "openservicemesh.io/sidecar-log-level" on pod: must be one of`,
+		},
+		{
+			name: "invalid concurrency",
+			podAnnotations: map[string]string{
+				constants.SidecarConcurrencyAnnotation: "0",
+			},
+			expectedErr: `Invalid value "0" for annotation "openservicemesh.io/sidecar-concurrency" on pod: must be a positive integer`,
+		},
+		{
+			name: "annotation on the namespace holds the application until the sidecar starts",
+			namespaceAnnotations: map[string]string{
+				constants.SidecarHoldApplicationUntilProxyStartsAnnotation: "true",
+			},
+			expectedConfig: &sidecarConfig{
+				resources:                       meshConfigResources,
+				logLevel:                        "error",
+				holdApplicationUntilProxyStarts: true,
+				statsTags:                       map[string]string{"mesh": "osm"},
+			},
+		},
+		{
+			name: "invalid hold application until proxy starts",
+			podAnnotations: map[string]string{
+				constants.SidecarHoldApplicationUntilProxyStartsAnnotation: "yes please",
+			},
+			expectedErr: `Invalid value "yes please" for annotation "openservicemesh.io/sidecar-hold-application-until-proxy-starts" on pod: must be a boolean`,
+		},
+		{
+			name: "invalid image",
+			podAnnotations: map[string]string{
+				constants.SidecarImageAnnotation: "envoy image",
+			},
+			expectedErr: `Invalid value "envoy image" for annotation "openservicemesh.io/sidecar-image" on pod`,
+		},
+		{
+			name: "outbound exclusion annotations on the pod are merged with the SidecarConfig",
+			sidecarConfigs: []configv1alpha1.SidecarConfig{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "namespace", Namespace: namespace},
+					Spec: configv1alpha1.SidecarConfigSpec{
+						OutboundTrafficCapture: &configv1alpha1.OutboundTrafficCaptureSpec{
+							IPRangeExclusionList: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			podAnnotations: map[string]string{
+				outboundIPRangeExclusionListAnnotation: "192.168.0.0/16, 10.0.0.0/8",
+				outboundUIDExclusionListAnnotation:     "1000,1001",
+				outboundGIDExclusionListAnnotation:     "2000",
+			},
+			expectedConfig: &sidecarConfig{
+				resources:                    meshConfigResources,
+				logLevel:                     "error",
+				statsTags:                    map[string]string{"mesh": "osm"},
+				outboundIPRangeExclusionList: []string{"10.0.0.0/8", "192.168.0.0/16"},
+				outboundUIDExclusionList:     []int{1000, 1001},
+				outboundGIDExclusionList:     []int{2000},
+			},
+		},
+		{
+			name: "invalid outbound IP range exclusion list",
+			podAnnotations: map[string]string{
+				outboundIPRangeExclusionListAnnotation: "10.0.0.1",
+			},
+			expectedErr: `Invalid value "10.0.0.1" for annotation "openservicemesh.io/outbound-ip-range-exclusion-list" on pod`,
+		},
+		{
+			name: "invalid outbound UID exclusion list",
+			podAnnotations: map[string]string{
+				outboundUIDExclusionListAnnotation: "1000,-1",
+			},
+			expectedErr: `Invalid value "1000,-1" for annotation "openservicemesh.io/outbound-uid-exclusion-list" on pod: must be a comma separated list of non-negative integers`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			mockCtrl := gomock.NewController(t)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigClient := config.NewMockController(mockCtrl)
+
+			mockKubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: namespace, Annotations: tc.namespaceAnnotations},
+			})
+			mockConfigurator.EXPECT().GetProxyResources().Return(*meshConfigResources.DeepCopy())
+			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("error")
+			mockConfigurator.EXPECT().GetProxyConcurrency().Return(0)
+			mockConfigurator.EXPECT().GetProxyDrainDuration().Return(time.Duration(0))
+			mockConfigurator.EXPECT().IsHoldApplicationUntilProxyStarts().Return(false)
+			mockConfigurator.EXPECT().GetProxyStatsTags().Return(map[string]string{"mesh": "osm"})
+			mockConfigClient.EXPECT().ListSidecarConfigs(namespace).Return(tc.sidecarConfigs)
+
+			wh := &mutatingWebhook{
+				kubeController: mockKubeController,
+				configClient:   mockConfigClient,
+				configurator:   mockConfigurator,
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "pod",
+					Namespace:       namespace,
+					Labels:          map[string]string{"app": "bookstore"},
+					Annotations:     tc.podAnnotations,
+					OwnerReferences: tc.ownerReferences,
+				},
+				Spec: corev1.PodSpec{NodeSelector: tc.nodeSelector},
+			}
+
+			actual, err := wh.getSidecarConfig(pod, namespace)
+			if tc.expectedErr != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedConfig, actual)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package injector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// sidecarTemplate is the configuration of the sidecar and init container injected in a pod, resolved from the mesh
+// configuration. Its hash is recorded in the SidecarHashAnnotation annotation of the pod, and compared to the hash of
+// the configuration resolved from the current mesh configuration to detect the pods running an outdated sidecar.
+type sidecarTemplate struct {
+	SidecarImage                    string                      `json:"sidecarImage"`
+	Resources                       corev1.ResourceRequirements `json:"resources"`
+	LogLevel                        string                      `json:"logLevel"`
+	Concurrency                     int                         `json:"concurrency,omitempty"`
+	DrainDuration                   time.Duration               `json:"drainDuration,omitempty"`
+	HoldApplicationUntilProxyStarts bool                        `json:"holdApplicationUntilProxyStarts,omitempty"`
+	QuitOnApplicationExit           bool                        `json:"quitOnApplicationExit,omitempty"`
+	StatsTags                       map[string]string           `json:"statsTags,omitempty"`
+
+	// The traffic interception settings are not applicable to Windows pods
+	IptablesConfig          *IptablesConfig `json:"iptablesConfig,omitempty"`
+	EnableCNI               bool            `json:"enableCNI,omitempty"`
+	InitContainerImage      string          `json:"initContainerImage,omitempty"`
+	PrivilegedInitContainer bool            `json:"privilegedInitContainer,omitempty"`
+}
+
+// getSidecarTemplate returns the configuration of the sidecar and init container to inject in the given pod,
+// given the resolved configuration of its sidecar
+func (wh *mutatingWebhook) getSidecarTemplate(pod *corev1.Pod, namespace string, sidecarCfg *sidecarConfig) *sidecarTemplate {
+	podOS := pod.Spec.NodeSelector["kubernetes.io/os"]
+	_, sidecarImage := getPlatformSpecificSpecComponents(wh.configurator, podOS)
+	if sidecarCfg.image != "" {
+		sidecarImage = sidecarCfg.image
+	}
+
+	template := &sidecarTemplate{
+		SidecarImage:                    sidecarImage,
+		Resources:                       sidecarCfg.resources,
+		LogLevel:                        sidecarCfg.logLevel,
+		Concurrency:                     sidecarCfg.concurrency,
+		DrainDuration:                   sidecarCfg.drainDuration,
+		HoldApplicationUntilProxyStarts: sidecarCfg.holdApplicationUntilProxyStarts,
+		QuitOnApplicationExit:           sidecarCfg.quitOnApplicationExit,
+		StatsTags:                       sidecarCfg.statsTags,
+	}
+	if strings.EqualFold(podOS, constants.OSWindows) {
+		return template
+	}
+
+	// Build outbound port exclusion list
+	podOutboundPortExclusionList, _ := wh.getPortExclusionListForPod(pod, namespace, outboundPortExclusionListAnnotation)
+	podOutboundPortExclusionList = mergeExclusionLists(podOutboundPortExclusionList, sidecarCfg.outboundPortExclusionList)
+	globalOutboundPortExclusionList := wh.configurator.GetOutboundPortExclusionList()
+	outboundPortExclusionList := mergeExclusionLists(podOutboundPortExclusionList, globalOutboundPortExclusionList)
+
+	// Build outbound IP range exclusion list
+	outboundIPRangeExclusionList := mergeIPRangeExclusionLists(sidecarCfg.outboundIPRangeExclusionList, wh.configurator.GetOutboundIPRangeExclusionList())
+
+	// Build inbound port exclusion list
+	podInboundPortExclusionList, _ := wh.getPortExclusionListForPod(pod, namespace, inboundPortExclusionListAnnotation)
+	globalInboundPortExclusionList := wh.configurator.GetInboundPortExclusionList()
+	inboundPortExclusionList := mergeExclusionLists(podInboundPortExclusionList, globalInboundPortExclusionList)
+
+	// Build outbound user and group ID exclusion lists, which are merged like port lists
+	outboundUIDExclusionList := mergeExclusionLists(sidecarCfg.outboundUIDExclusionList, wh.configurator.GetOutboundUIDExclusionList())
+	outboundGIDExclusionList := mergeExclusionLists(sidecarCfg.outboundGIDExclusionList, wh.configurator.GetOutboundGIDExclusionList())
+
+	featureFlags := wh.configurator.GetFeatureFlags()
+	template.IptablesConfig = &IptablesConfig{
+		OutboundIPRangeExclusionList: outboundIPRangeExclusionList,
+		OutboundPortExclusionList:    outboundPortExclusionList,
+		InboundPortExclusionList:     inboundPortExclusionList,
+		OutboundUIDExclusionList:     outboundUIDExclusionList,
+		OutboundGIDExclusionList:     outboundGIDExclusionList,
+		EnableIPv6:                   featureFlags.EnableIPv6,
+	}
+	template.EnableCNI = featureFlags.EnableCNI
+	if !featureFlags.EnableCNI {
+		template.InitContainerImage = wh.configurator.GetInitContainerImage()
+		template.PrivilegedInitContainer = wh.configurator.IsPrivilegedInitContainer()
+	}
+
+	return template
+}
+
+// hash returns the hash of the sidecar template
+func (t *sidecarTemplate) hash() (string, error) {
+	// The keys of the maps are sorted when marshaled, such that the same template always has the same hash
+	template, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(template)
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,38 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestSidecarTemplateHash(t *testing.T) {
+	assert := tassert.New(t)
+
+	template := &sidecarTemplate{
+		SidecarImage: "envoyproxy/envoy-alpine:v1.19.1",
+		LogLevel:     "error",
+		StatsTags:    map[string]string{"team": "store", "cluster": "east"},
+		IptablesConfig: &IptablesConfig{
+			OutboundPortExclusionList: []int{6379},
+		},
+		InitContainerImage: "openservicemesh/init:v0.10.0",
+	}
+	hash, err := template.hash()
+	assert.Nil(err)
+	assert.Len(hash, 16)
+
+	// The same configuration always has the same hash
+	sameTemplate := *template
+	sameTemplate.StatsTags = map[string]string{"cluster": "east", "team": "store"}
+	sameHash, err := sameTemplate.hash()
+	assert.Nil(err)
+	assert.Equal(hash, sameHash)
+
+	// A change of the configuration changes the hash
+	updatedTemplate := *template
+	updatedTemplate.InitContainerImage = "openservicemesh/init:v0.11.0"
+	updatedHash, err := updatedTemplate.hash()
+	assert.Nil(err)
+	assert.NotEqual(hash, updatedHash)
+}
@@ -3,10 +3,14 @@
 package injector
 
 import (
+	"encoding/json"
+
 	mapset "github.com/deckarep/golang-set"
+	"gomodules.xyz/jsonpatch/v2"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/logger"
@@ -24,11 +28,15 @@ type mutatingWebhook struct {
 	kubeClient     kubernetes.Interface
 	certManager    certificate.Manager
 	kubeController k8s.Controller
+	configClient   config.Controller
 	osmNamespace   string
 	meshName       string
 	cert           certificate.Certificater
 	configurator   configurator.Configurator
 
+	// iptablesConfigKey is the key the iptables config annotations verified by the OSM CNI plugin are signed with
+	iptablesConfigKey []byte
+
 	nonInjectNamespaces mapset.Set
 }
 
@@ -36,6 +44,25 @@ type mutatingWebhook struct {
 type Config struct {
 	// ListenPort defines the port on which the sidecar injector listens
 	ListenPort int
+
+	// PodIP defines the IP address of the pod of the sidecar injector, whose IP family is the primary IP family
+	// of the pods of the cluster
+	PodIP string
+}
+
+// InjectionResult is the result of previewing the sidecar injection of a manifest
+type InjectionResult struct {
+	// Injected is true when the sidecar is injected in the pod template of the manifest
+	Injected bool `json:"injected"`
+
+	// Reason is the reason the sidecar is not injected
+	Reason string `json:"reason,omitempty"`
+
+	// Object is the manifest with the sidecar injected in its pod template
+	Object json.RawMessage `json:"object,omitempty"`
+
+	// Patch is the JSON patch injecting the sidecar in the manifest
+	Patch []jsonpatch.JsonPatchOperation `json:"patch,omitempty"`
 }
 
 // Context needed to compose the Envoy bootstrap YAML.
@@ -54,4 +81,14 @@ type envoyBootstrapConfigMeta struct {
 	// The bootstrap Envoy config will be affected by the liveness, readiness, startup probes set on
 	// the pod this Envoy is fronting.
 	OriginalHealthProbes healthProbes
+
+	// The tags, and their fixed values, added to all the stats of the Envoy
+	StatsTags map[string]string
+
+	// The loopback IP address of the primary IP family of the pod, on which the Envoy admin interface listens
+	// and the application containers are reached
+	LocalhostIPAddress string
+
+	// Whether the health probe listeners accept IPv6 connections in addition to IPv4 connections
+	EnableIPv6 bool
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
@@ -47,10 +48,19 @@ const (
 
 	// inboundPortExclusionListAnnotation is the annotation used for inbound port exclusions
 	inboundPortExclusionListAnnotation = "openservicemesh.io/inbound-port-exclusion-list"
+
+	// outboundIPRangeExclusionListAnnotation is the annotation used for outbound IP range exclusions
+	outboundIPRangeExclusionListAnnotation = "openservicemesh.io/outbound-ip-range-exclusion-list"
+
+	// outboundUIDExclusionListAnnotation is the annotation used to exclude the outbound traffic of user IDs
+	outboundUIDExclusionListAnnotation = "openservicemesh.io/outbound-uid-exclusion-list"
+
+	// outboundGIDExclusionListAnnotation is the annotation used to exclude the outbound traffic of group IDs
+	outboundGIDExclusionListAnnotation = "openservicemesh.io/outbound-gid-exclusion-list"
 )
 
 // NewMutatingWebhook starts a new web server handling requests from the injector MutatingWebhookConfiguration
-func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certManager certificate.Manager, kubeController k8s.Controller, meshName, osmNamespace, webhookConfigName, osmVersion string, webhookTimeout int32, enableReconciler bool, stop <-chan struct{}, cfg configurator.Configurator) error {
+func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certManager certificate.Manager, kubeController k8s.Controller, configClient config.Controller, meshName, osmNamespace, webhookConfigName, osmVersion string, webhookTimeout int32, enableReconciler bool, stop <-chan struct{}, cfg configurator.Configurator) error {
 	// This is a certificate issued for the webhook handler
 	// This cert does not have to be related to the Envoy certs, but it does have to match
 	// the cert provisioned with the MutatingWebhookConfiguration
@@ -68,16 +78,24 @@ func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certMana
 		return errors.Errorf("Error fetching webhook certificate from k8s secret: %s", err)
 	}
 
+	iptablesConfigKey, err := getOrCreateIptablesConfigKey(kubeClient, osmNamespace)
+	if err != nil {
+		return errors.Errorf("Error fetching the iptables config signing key from k8s secret: %s", err)
+	}
+
 	wh := mutatingWebhook{
 		config:         config,
 		kubeClient:     kubeClient,
 		certManager:    certManager,
 		kubeController: kubeController,
+		configClient:   configClient,
 		osmNamespace:   osmNamespace,
 		meshName:       meshName,
 		cert:           webhookHandlerCert,
 		configurator:   cfg,
 
+		iptablesConfigKey: iptablesConfigKey,
+
 		// Envoy sidecars should never be injected in these namespaces
 		nonInjectNamespaces: mapset.NewSetFromSlice([]interface{}{
 			metav1.NamespaceSystem,
@@ -89,12 +107,48 @@ func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certMana
 	// Start the MutatingWebhook web server
 	go wh.run(stop)
 
+	// Start the sidecar injection preview server
+	go wh.runPreview(stop)
+
+	// Mark the pods running outdated sidecars and restart their workloads, when enabled, from a single replica
+	k8s.RunWithLeaderElection(kubeClient, osmNamespace, SidecarRolloutLeaseName, stop, wh.runSidecarRollout)
+
+	// Garbage collect the bootstrap config secrets of the pods that do not exist from a single replica
+	k8s.RunWithLeaderElection(kubeClient, osmNamespace, BootstrapSecretCleanupLeaseName, stop, wh.runBootstrapSecretCleanup)
+
 	if err = createOrUpdateMutatingWebhook(wh.kubeClient, webhookHandlerCert, webhookTimeout, webhookConfigName, meshName, osmNamespace, osmVersion, enableReconciler); err != nil {
 		return errors.Errorf("Error creating MutatingWebhookConfiguration %s: %+v", webhookConfigName, err)
 	}
 	return nil
 }
 
+// runPreview serves the previews of the sidecar injection of manifests, without side effects, until the given channel
+// is closed. The server only listens on the loopback interface, so that it can only be reached by port forwarding to
+// the sidecar injector pod, which requires permission to create the 'pods/portforward' subresource.
+func (wh *mutatingWebhook) runPreview(stop <-chan struct{}) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(WebhookInjectPath, wh.injectHandler)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf("127.0.0.1:%d", constants.InjectorPreviewPort),
+		Handler: mux,
+	}
+
+	log.Info().Msgf("Starting sidecar injection preview server on port: %v", constants.InjectorPreviewPort)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("Sidecar injection preview HTTP server failed to start")
+		}
+	}()
+
+	// Wait on exit signals
+	<-stop
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		log.Error().Err(err).Msg("Error shutting down sidecar injection preview HTTP server")
+	}
+}
+
 func (wh *mutatingWebhook) run(stop <-chan struct{}) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -284,18 +338,25 @@ func (wh *mutatingWebhook) isNamespaceInjectable(namespace string) bool {
 //
 // The function returns an error when it is unable to determine whether to perform sidecar injection.
 func (wh *mutatingWebhook) mustInject(pod *corev1.Pod, namespace string) (bool, error) {
+	inject, _, err := wh.getInjectionDecision(pod, namespace)
+	return inject, err
+}
+
+// getInjectionDecision determines whether the sidecar must be injected as described by mustInject, along with
+// the reason the sidecar is not injected when it must not be.
+func (wh *mutatingWebhook) getInjectionDecision(pod *corev1.Pod, namespace string) (inject bool, reason string, err error) {
 	// Sidecar injection is not permitted for pods on the host network.
 	// Since iptables rules are created to intercept and redirect traffic via the proxy sidecar,
 	// pods on the host network cannot be injected with the sidecar as the required iptables rules
 	// will result in routing failures on the host's network.
 	if pod.Spec.HostNetwork {
 		log.Debug().Msgf("Pod with UID %s has HostNetwork enabled, cannot inject a sidecar", pod.ObjectMeta.UID)
-		return false, nil
+		return false, "The pod is on the host network", nil
 	}
 
 	if !wh.isNamespaceInjectable(namespace) {
 		log.Warn().Msgf("Mutation request is for pod with UID %s; Injection in Namespace %s is not permitted", pod.ObjectMeta.UID, namespace)
-		return false, nil
+		return false, fmt.Sprintf("Sidecar injection is not permitted in namespace %s, or the namespace is not monitored by the mesh", namespace), nil
 	}
 
 	// Check if the pod is annotated for injection
@@ -303,35 +364,38 @@ func (wh *mutatingWebhook) mustInject(pod *corev1.Pod, namespace string) (bool,
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrDeterminingPodInjectionEnablement)).
 			Msg("Error determining if the pod is enabled for sidecar injection")
-		return false, err
+		return false, "", err
 	}
 
 	// Check if the namespace is annotated for injection
 	ns := wh.kubeController.GetNamespace(namespace)
 	if ns == nil {
 		log.Error().Err(errNamespaceNotFound).Msgf("Error retrieving namespace %s", namespace)
-		return false, errNamespaceNotFound
+		return false, "", errNamespaceNotFound
 	}
 	nsInjectAnnotationExists, nsInject, err := isAnnotatedForInjection(ns.Annotations, "Namespace", ns.Name)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrDeterminingNamespaceInjectionEnablement)).
 			Msgf("Error determining if namespace %s is enabled for sidecar injection", namespace)
-		return false, err
+		return false, "", err
 	}
 
 	if podInjectAnnotationExists && podInject {
 		// Pod is explicitly annotated to enable sidecar injection
-		return true, nil
+		return true, "", nil
 	} else if nsInjectAnnotationExists && nsInject {
 		// Namespace is annotated to enable sidecar injection
 		if !podInjectAnnotationExists || podInject {
 			// If pod annotation doesn't exist or if an annotation exists to enable injection, enable it
-			return true, nil
+			return true, "", nil
 		}
 	}
 
 	// Conditions to inject the sidecar are not met
-	return false, nil
+	if podInjectAnnotationExists {
+		return false, fmt.Sprintf("The pod is annotated with %s=%s", constants.SidecarInjectionAnnotation, pod.Annotations[constants.SidecarInjectionAnnotation]), nil
+	}
+	return false, fmt.Sprintf("Neither the pod nor namespace %s is annotated with %s", namespace, constants.SidecarInjectionAnnotation), nil
 }
 
 // getPortExclusionListForPod gets a list of ports to exclude from sidecar traffic interception for the given
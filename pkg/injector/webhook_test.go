@@ -28,6 +28,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -569,7 +570,7 @@ var _ = Describe("Testing Injector Functions", func() {
 
 		_, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), webhookName, metav1.GetOptions{})
 		Expect(err).NotTo(HaveOccurred())
-		actualErr := NewMutatingWebhook(Config{}, kubeClient, certManager, kubeController, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, stop, cfg)
+		actualErr := NewMutatingWebhook(Config{}, kubeClient, certManager, kubeController, nil, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, stop, cfg)
 		Expect(actualErr).NotTo(HaveOccurred())
 		close(stop)
 	})
@@ -585,7 +586,7 @@ var _ = Describe("Testing Injector Functions", func() {
 
 		cfg.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
 
-		actualErr := NewMutatingWebhook(Config{}, kubeClient, certManager, kubeController, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, stop, cfg)
+		actualErr := NewMutatingWebhook(Config{}, kubeClient, certManager, kubeController, nil, meshName, osmNamespace, webhookName, osmVersion, webhookTimeout, enableReconciler, stop, cfg)
 		Expect(actualErr).NotTo(HaveOccurred())
 		_, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), webhookName, metav1.GetOptions{})
 		Expect(err).NotTo(HaveOccurred())
@@ -1000,24 +1001,35 @@ func TestWebhookMutate(t *testing.T) {
 
 		mockCtrl := gomock.NewController(t)
 		kubeController := k8s.NewMockController(mockCtrl)
-		kubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{}).Times(1)
+		kubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{}).Times(2)
 		kubeController.EXPECT().GetNamespace(namespace).Return(nil).Times(1)
 		kubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true)
 
 		cfg := configurator.NewMockConfigurator(mockCtrl)
 		cfg.EXPECT().GetOutboundPortExclusionList()
 		cfg.EXPECT().GetInboundPortExclusionList()
+		cfg.EXPECT().GetOutboundUIDExclusionList()
+		cfg.EXPECT().GetOutboundGIDExclusionList()
 		cfg.EXPECT().GetOutboundIPRangeExclusionList()
 		cfg.EXPECT().IsPrivilegedInitContainer()
+		cfg.EXPECT().GetFeatureFlags().Times(2)
 		cfg.EXPECT().GetInitContainerImage().Return("init-container-image").AnyTimes()
 		cfg.EXPECT().GetEnvoyImage().Return("envoy-linux-image").AnyTimes()
 		cfg.EXPECT().GetEnvoyWindowsImage().Return("envoy-windows-image").AnyTimes()
 		cfg.EXPECT().GetProxyResources()
 		cfg.EXPECT().GetEnvoyLogLevel()
+		cfg.EXPECT().GetProxyConcurrency()
+		cfg.EXPECT().GetProxyDrainDuration()
+		cfg.EXPECT().IsHoldApplicationUntilProxyStarts()
+		cfg.EXPECT().GetProxyStatsTags()
+
+		configClient := config.NewMockController(mockCtrl)
+		configClient.EXPECT().ListSidecarConfigs(namespace)
 
 		wh := &mutatingWebhook{
 			nonInjectNamespaces: mapset.NewSet(),
 			kubeController:      kubeController,
+			configClient:        configClient,
 			certManager:         tresor.NewFakeCertManager(cfg),
 			kubeClient:          fake.NewSimpleClientset(),
 			configurator:        cfg,
@@ -1049,23 +1061,34 @@ func TestWebhookMutate(t *testing.T) {
 
 		mockCtrl := gomock.NewController(t)
 		kubeController := k8s.NewMockController(mockCtrl)
-		kubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{}).Times(2)
+		kubeController.EXPECT().GetNamespace(namespace).Return(&corev1.Namespace{}).Times(3)
 		kubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true)
 
 		cfg := configurator.NewMockConfigurator(mockCtrl)
 		cfg.EXPECT().GetOutboundPortExclusionList()
 		cfg.EXPECT().GetInboundPortExclusionList()
+		cfg.EXPECT().GetOutboundUIDExclusionList()
+		cfg.EXPECT().GetOutboundGIDExclusionList()
 		cfg.EXPECT().GetOutboundIPRangeExclusionList()
 		cfg.EXPECT().IsPrivilegedInitContainer()
+		cfg.EXPECT().GetFeatureFlags().Times(2)
 		cfg.EXPECT().GetInitContainerImage().Return("init-container-image").AnyTimes()
 		cfg.EXPECT().GetEnvoyImage().Return("envoy-linux-image").AnyTimes()
 		cfg.EXPECT().GetEnvoyWindowsImage().Return("envoy-windows-image").AnyTimes()
 		cfg.EXPECT().GetProxyResources()
 		cfg.EXPECT().GetEnvoyLogLevel()
+		cfg.EXPECT().GetProxyConcurrency()
+		cfg.EXPECT().GetProxyDrainDuration()
+		cfg.EXPECT().IsHoldApplicationUntilProxyStarts()
+		cfg.EXPECT().GetProxyStatsTags()
+
+		configClient := config.NewMockController(mockCtrl)
+		configClient.EXPECT().ListSidecarConfigs(namespace)
 
 		wh := &mutatingWebhook{
 			nonInjectNamespaces: mapset.NewSet(),
 			kubeController:      kubeController,
+			configClient:        configClient,
 			certManager:         tresor.NewFakeCertManager(cfg),
 			kubeClient:          fake.NewSimpleClientset(),
 			configurator:        cfg,
@@ -2,7 +2,6 @@ package k8s
 
 import (
 	"context"
-	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -45,11 +44,14 @@ func WatchAndUpdateProxyBootstrapSecret(kubeClient kubernetes.Interface, msgBrok
 				continue
 			}
 
-			podUID := addedPodObj.GetUID()
-			podUUID := addedPodObj.GetLabels()[constants.EnvoyUniqueIDLabelName]
+			podUUID, ok := addedPodObj.GetLabels()[constants.EnvoyUniqueIDLabelName]
+			if !ok {
+				// The pod does not have a sidecar, hence no bootstrap secret
+				continue
+			}
 			podName := addedPodObj.GetName()
 			namespace := addedPodObj.GetNamespace()
-			secretName := fmt.Sprintf("envoy-bootstrap-config-%s", podUUID)
+			secretName := constants.EnvoyBootstrapConfigSecretPrefix + podUUID
 
 			secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
 			if err != nil {
@@ -57,26 +59,42 @@ func WatchAndUpdateProxyBootstrapSecret(kubeClient kubernetes.Interface, msgBrok
 				continue
 			}
 
-			secret.ObjectMeta.OwnerReferences = append(secret.ObjectMeta.OwnerReferences, metav1.OwnerReference{
-				APIVersion: "v1",
-				Kind:       "Pod",
-				Name:       podName,
-				UID:        podUID,
-			})
-
-			if _, err = kubeClient.CoreV1().Secrets(namespace).Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
+			if updated, err := SetProxyBootstrapSecretOwner(kubeClient, secret, addedPodObj); err != nil {
 				// There might be conflicts when multiple controllers try to update the same resource
 				// One of the controllers will successfully update the resource, hence conflicts shoud be ignored and not treated as an error
 				if !apierrors.IsConflict(err) {
 					log.Error().Err(err).Msgf("Failed to update OwnerReference for Secret %s/%s to reference Pod %s/%s", namespace, secretName, namespace, podName)
 				}
-			} else {
+			} else if updated {
 				log.Debug().Msgf("Updated OwnerReference for Secret %s/%s to reference Pod %s/%s", namespace, secretName, namespace, podName)
 			}
 		}
 	}
 }
 
+// SetProxyBootstrapSecretOwner sets the OwnerReference of the given proxy bootstrap secret to point to the given pod,
+// for the secret to be garbage collected with the pod. It returns false if the secret already references the pod.
+func SetProxyBootstrapSecretOwner(kubeClient kubernetes.Interface, secret *corev1.Secret, pod *corev1.Pod) (bool, error) {
+	for _, ownerReference := range secret.GetOwnerReferences() {
+		if ownerReference.UID == pod.GetUID() {
+			return false, nil
+		}
+	}
+
+	secret = secret.DeepCopy()
+	secret.ObjectMeta.OwnerReferences = append(secret.ObjectMeta.OwnerReferences, metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.GetName(),
+		UID:        pod.GetUID(),
+	})
+
+	if _, err := kubeClient.CoreV1().Secrets(secret.Namespace).Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // WatchAndUpdateLogLevel watches for log level changes and updates the global log level
 func WatchAndUpdateLogLevel(msgBroker *messaging.Broker, stop <-chan struct{}) {
 	kubePubSub := msgBroker.GetKubeEventPubSub()
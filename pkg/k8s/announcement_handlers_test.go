@@ -104,6 +104,44 @@ func TestWatchAndUpdateProxyBootstrapSecret(t *testing.T) {
 	}, 1*time.Second, 50*time.Millisecond)
 }
 
+func TestSetProxyBootstrapSecretOwner(t *testing.T) {
+	a := assert.New(t)
+
+	namespace := "app"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.EnvoyBootstrapConfigSecretPrefix + uuid.New().String(),
+			Namespace: namespace,
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: namespace,
+			UID:       types.UID(uuid.New().String()),
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+
+	updated, err := SetProxyBootstrapSecretOwner(kubeClient, secret, pod)
+	a.Nil(err)
+	a.True(updated)
+
+	secret, err = kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), secret.Name, metav1.GetOptions{})
+	a.Nil(err)
+	expectedOwnerReferences := []metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: pod.Name, UID: pod.UID}}
+	a.Equal(expectedOwnerReferences, secret.OwnerReferences)
+
+	// The OwnerReference is not duplicated when the secret already references the pod
+	updated, err = SetProxyBootstrapSecretOwner(kubeClient, secret, pod)
+	a.Nil(err)
+	a.False(updated)
+
+	secret, err = kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), secret.Name, metav1.GetOptions{})
+	a.Nil(err)
+	a.Equal(expectedOwnerReferences, secret.OwnerReferences)
+}
+
 func TestWatchAndUpdateLogLevel(t *testing.T) {
 	testCases := []struct {
 		name             string
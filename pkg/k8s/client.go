@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/pkg/errors"
@@ -10,10 +11,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/utils/pointer"
 
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	policyv1alpha1Client "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
@@ -49,11 +50,12 @@ func newClient(kubeClient kubernetes.Interface, policyClient policyv1alpha1Clien
 		ServiceAccounts: c.initServiceAccountsMonitor,
 		Pods:            c.initPodMonitor,
 		Endpoints:       c.initEndpointMonitor,
+		Secrets:         func() { c.initSecretWatcher(stop) },
 	}
 
 	// If specific informers are not selected to be initialized, initialize all informers
 	if len(selectInformers) == 0 {
-		selectInformers = []InformerKey{Namespaces, Services, ServiceAccounts, Pods, Endpoints}
+		selectInformers = []InformerKey{Namespaces, Services, ServiceAccounts, Pods, Endpoints, Secrets}
 	}
 
 	for _, informer := range selectInformers {
@@ -150,6 +152,11 @@ func (c *client) initEndpointMonitor() {
 	c.informers[Endpoints].AddEventHandler(GetEventHandlerFuncs(c.shouldObserve, eptEventTypes, c.msgBroker))
 }
 
+// Initializes the watch of the secrets referenced by the resources of the mesh
+func (c *client) initSecretWatcher(stop <-chan struct{}) {
+	c.secrets = newSecretWatcher(c.kubeClient, c.msgBroker, c.shouldObserve, stop)
+}
+
 func (c *client) run(stop <-chan struct{}) error {
 	log.Info().Msg("Namespace controller client started")
 	var hasSynced []cache.InformerSynced
@@ -280,6 +287,23 @@ func (c client) GetEndpoints(svc service.MeshService) (*corev1.Endpoints, error)
 	return nil, nil
 }
 
+// WatchSecrets sets the secrets referenced by the resources of the mesh, which are the only secrets cached and observed
+func (c client) WatchSecrets(secrets []types.NamespacedName) {
+	if c.secrets == nil {
+		return
+	}
+	c.secrets.watch(secrets)
+}
+
+// GetSecret returns the secret with the given name and namespace if it is watched and exists in a monitored namespace,
+// otherwise nil
+func (c client) GetSecret(name string, namespace string) *corev1.Secret {
+	if c.secrets == nil || !c.IsMonitoredNamespace(namespace) {
+		return nil
+	}
+	return c.secrets.get(types.NamespacedName{Namespace: namespace, Name: name})
+}
+
 // ListServiceIdentitiesForService lists ServiceAccounts associated with the given service
 func (c client) ListServiceIdentitiesForService(svc service.MeshService) ([]identity.K8sServiceAccount, error) {
 	var svcAccounts []identity.K8sServiceAccount
@@ -333,14 +357,19 @@ func (c client) UpdateStatus(resource interface{}) (metav1.Object, error) {
 		obj := resource.(*policyv1alpha1.IngressBackend)
 		return c.policyClient.PolicyV1alpha1().IngressBackends(obj.Namespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
 
+	case *policyv1alpha1.Egress:
+		obj := resource.(*policyv1alpha1.Egress)
+		return c.policyClient.PolicyV1alpha1().Egresses(obj.Namespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
+
 	default:
 		return nil, errors.Errorf("Unsupported type: %T", t)
 	}
 }
 
 // ServiceToMeshServices translates a k8s service with one or more ports to one or more
-// MeshService objects per port.
-func ServiceToMeshServices(c Controller, svc corev1.Service) []service.MeshService {
+// MeshService objects per port. The protocol of the ports that do not declare it is detected
+// by inspecting the traffic when protocol sniffing is enabled, and is HTTP otherwise.
+func ServiceToMeshServices(c Controller, svc corev1.Service, enableProtocolSniffing bool) []service.MeshService {
 	var meshServices []service.MeshService
 
 	for _, portSpec := range svc.Spec.Ports {
@@ -348,7 +377,7 @@ func ServiceToMeshServices(c Controller, svc corev1.Service) []service.MeshServi
 			Namespace: svc.Namespace,
 			Name:      svc.Name,
 			Port:      uint16(portSpec.Port),
-			Protocol:  pointer.StringDeref(portSpec.AppProtocol, constants.ProtocolHTTP),
+			Protocol:  getPortProtocol(portSpec, enableProtocolSniffing),
 		}
 
 		// The endpoints for the kubernetes service carry information that allows
@@ -364,6 +393,25 @@ func ServiceToMeshServices(c Controller, svc corev1.Service) []service.MeshServi
 	return meshServices
 }
 
+// getPortProtocol returns the protocol of the given service port, declared by its appProtocol or else by the
+// prefix of its name, such as 'http-web'
+func getPortProtocol(portSpec corev1.ServicePort, enableProtocolSniffing bool) string {
+	if portSpec.AppProtocol != nil {
+		return *portSpec.AppProtocol
+	}
+
+	for _, protocol := range []string{constants.ProtocolHTTP, constants.ProtocolGRPC, constants.ProtocolTCP} {
+		if portSpec.Name == protocol || strings.HasPrefix(portSpec.Name, protocol+"-") {
+			return protocol
+		}
+	}
+
+	if enableProtocolSniffing {
+		return constants.ProtocolAuto
+	}
+	return constants.ProtocolHTTP
+}
+
 func getTargetPortFromEndpoints(endpointName string, endpoints corev1.Endpoints) (endpointPort uint16) {
 	// Per https://pkg.go.dev/k8s.io/api/core/v1#ServicePort and
 	// https://pkg.go.dev/k8s.io/api/core/v1#EndpointPort, if a service has multiple
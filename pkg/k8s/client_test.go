@@ -9,7 +9,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
 
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
@@ -17,6 +19,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/messaging"
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
@@ -423,6 +426,107 @@ func TestGetEndpoints(t *testing.T) {
 	}
 }
 
+func TestGetSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "ns1",
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		secretName       string
+		secretNamespace  string
+		watchedSecrets   []types.NamespacedName
+		monitorNamespace bool
+		expected         *corev1.Secret
+	}{
+		{
+			name:             "gets the watched secret from the cache given its name and namespace",
+			secretName:       "foo",
+			secretNamespace:  "ns1",
+			watchedSecrets:   []types.NamespacedName{{Namespace: "ns1", Name: "foo"}},
+			monitorNamespace: true,
+			expected:         secret,
+		},
+		{
+			name:             "returns nil if the secret is not watched",
+			secretName:       "foo",
+			secretNamespace:  "ns1",
+			watchedSecrets:   nil,
+			monitorNamespace: true,
+			expected:         nil,
+		},
+		{
+			name:             "returns nil if the watched secret does not exist",
+			secretName:       "invalid",
+			secretNamespace:  "ns1",
+			watchedSecrets:   []types.NamespacedName{{Namespace: "ns1", Name: "invalid"}},
+			monitorNamespace: true,
+			expected:         nil,
+		},
+		{
+			name:             "returns nil if the secret's namespace is not monitored",
+			secretName:       "foo",
+			secretNamespace:  "ns1",
+			watchedSecrets:   []types.NamespacedName{{Namespace: "ns1", Name: "foo"}},
+			monitorNamespace: false,
+			expected:         nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			stop := make(chan struct{})
+			defer close(stop)
+
+			c, err := newClient(testclient.NewSimpleClientset(secret), nil, testMeshName, stop, messaging.NewBroker(stop), Namespaces, Secrets)
+			a.Nil(err)
+			if tc.monitorNamespace {
+				_ = c.informers[Namespaces].GetStore().Add(&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   tc.secretNamespace,
+						Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName},
+					},
+				})
+			}
+
+			c.WatchSecrets(tc.watchedSecrets)
+			for _, watched := range tc.watchedSecrets {
+				a.True(cache.WaitForCacheSync(stop, c.secrets.informers[watched].informer.HasSynced))
+			}
+
+			actual := c.GetSecret(tc.secretName, tc.secretNamespace)
+			a.Equal(tc.expected, actual)
+		})
+	}
+}
+
+func TestWatchSecretsStopsUnreferencedSecrets(t *testing.T) {
+	a := assert.New(t)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	c, err := newClient(testclient.NewSimpleClientset(), nil, testMeshName, stop, messaging.NewBroker(stop), Secrets)
+	a.Nil(err)
+
+	foo := types.NamespacedName{Namespace: "ns1", Name: "foo"}
+	bar := types.NamespacedName{Namespace: "ns1", Name: "bar"}
+
+	c.WatchSecrets([]types.NamespacedName{foo, bar})
+	a.Len(c.secrets.informers, 2)
+	fooInformer := c.secrets.informers[foo]
+
+	c.WatchSecrets([]types.NamespacedName{foo})
+	a.Len(c.secrets.informers, 1)
+	a.Same(fooInformer, c.secrets.informers[foo])
+
+	c.WatchSecrets(nil)
+	a.Empty(c.secrets.informers)
+}
+
 func TestListServiceIdentitiesForService(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -644,10 +748,28 @@ func TestUpdateStatus(t *testing.T) {
 					Reason:        "valid",
 				},
 			},
+		}, {
+			name: "valid Egress resource",
+			existingResource: &policyv1alpha1.Egress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "egress-1",
+					Namespace: "test",
+				},
+			},
+			updatedResource: &policyv1alpha1.Egress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "egress-1",
+					Namespace: "test",
+				},
+				Status: policyv1alpha1.EgressStatus{
+					CurrentStatus: "committed",
+					Reason:        "valid",
+				},
+			},
 		}, {
 			name:             "unsupported resource",
 			existingResource: &policyv1alpha1.Egress{},
-			updatedResource:  &policyv1alpha1.Egress{},
+			updatedResource:  &corev1.Service{},
 			expectErr:        true,
 		},
 	}
@@ -802,8 +924,56 @@ func TestK8sServicesToMeshServices(t *testing.T) {
 			assert.Nil(err)
 			assert.NotNil(kubeController)
 
-			actual := ServiceToMeshServices(kubeController, tc.svc)
+			actual := ServiceToMeshServices(kubeController, tc.svc, false)
 			assert.ElementsMatch(tc.expected, actual)
 		})
 	}
 }
+
+func TestGetPortProtocol(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		port                   corev1.ServicePort
+		enableProtocolSniffing bool
+		expected               string
+	}{
+		{
+			name:     "appProtocol",
+			port:     corev1.ServicePort{Name: "http-web", AppProtocol: pointer.StringPtr("redis")},
+			expected: "redis",
+		},
+		{
+			name:     "name prefix",
+			port:     corev1.ServicePort{Name: "grpc-api"},
+			expected: "grpc",
+		},
+		{
+			name:     "name matching a protocol",
+			port:     corev1.ServicePort{Name: "tcp"},
+			expected: "tcp",
+		},
+		{
+			name:     "name containing a protocol without a dash",
+			port:     corev1.ServicePort{Name: "tcpdump"},
+			expected: "http",
+		},
+		{
+			name:                   "undeclared protocol with protocol sniffing enabled",
+			port:                   corev1.ServicePort{Name: "web"},
+			enableProtocolSniffing: true,
+			expected:               "auto",
+		},
+		{
+			name:     "undeclared protocol with protocol sniffing disabled",
+			port:     corev1.ServicePort{},
+			expected: "http",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getPortProtocol(tc.port, tc.enableProtocolSniffing))
+		})
+	}
+}
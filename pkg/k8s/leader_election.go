@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// leaderElectionLeaseDuration is the duration for which a leader holds the lease without renewing it
+	leaderElectionLeaseDuration = 15 * time.Second
+
+	// leaderElectionRenewDeadline is the duration within which the leader must renew the lease before giving it up
+	leaderElectionRenewDeadline = 10 * time.Second
+
+	// leaderElectionRetryPeriod is the interval at which the replicas try to acquire or renew the lease
+	leaderElectionRetryPeriod = 2 * time.Second
+)
+
+// RunWithLeaderElection runs the given function in a goroutine on a single replica of a component at a time, until the
+// given stop channel is closed. The replicas elect a leader using the Lease with the given name in the given namespace,
+// and the function runs on the leader until the given stop channel is closed or the leader loses the lease, in which
+// case the stop channel passed to the function is closed and the replica tries to acquire the lease again.
+func RunWithLeaderElection(kubeClient kubernetes.Interface, namespace string, name string, stop <-chan struct{}, run func(stop <-chan struct{})) {
+	identity, err := os.Hostname()
+	if err != nil || identity == "" {
+		identity = uuid.New().String()
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Client: kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	go func() {
+		for ctx.Err() == nil {
+			leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+				Lock:            lock,
+				LeaseDuration:   leaderElectionLeaseDuration,
+				RenewDeadline:   leaderElectionRenewDeadline,
+				RetryPeriod:     leaderElectionRetryPeriod,
+				ReleaseOnCancel: true,
+				Name:            name,
+				Callbacks: leaderelection.LeaderCallbacks{
+					OnStartedLeading: func(leaderCtx context.Context) {
+						log.Info().Msgf("Acquired lease %s/%s as %s", namespace, name, identity)
+						run(leaderCtx.Done())
+					},
+					OnStoppedLeading: func() {
+						log.Info().Msgf("Released lease %s/%s as %s", namespace, name, identity)
+					},
+				},
+			})
+		}
+	}()
+}
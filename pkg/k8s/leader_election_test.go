@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunWithLeaderElection(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := fake.NewSimpleClientset()
+	stop := make(chan struct{})
+	running := make(chan struct{})
+	stopped := make(chan struct{})
+
+	RunWithLeaderElection(kubeClient, "osm-system", "test-lease", stop, func(leaderStop <-chan struct{}) {
+		close(running)
+		<-leaderStop
+		close(stopped)
+	})
+
+	select {
+	case <-running:
+	case <-time.After(5 * time.Second):
+		assert.Fail("function not run once the lease was acquired")
+	}
+
+	lease, err := kubeClient.CoordinationV1().Leases("osm-system").Get(context.Background(), "test-lease", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.NotNil(lease.Spec.HolderIdentity)
+
+	close(stop)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		assert.Fail("function not stopped once the stop channel was closed")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	service "github.com/openservicemesh/osm/pkg/service"
 	v1 "k8s.io/api/core/v1"
 	v10 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // MockController is a mock of Controller interface.
@@ -66,6 +67,20 @@ func (mr *MockControllerMockRecorder) GetNamespace(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespace", reflect.TypeOf((*MockController)(nil).GetNamespace), arg0)
 }
 
+// GetSecret mocks base method.
+func (m *MockController) GetSecret(arg0, arg1 string) *v1.Secret {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecret", arg0, arg1)
+	ret0, _ := ret[0].(*v1.Secret)
+	return ret0
+}
+
+// GetSecret indicates an expected call of GetSecret.
+func (mr *MockControllerMockRecorder) GetSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*MockController)(nil).GetSecret), arg0, arg1)
+}
+
 // GetService mocks base method.
 func (m *MockController) GetService(arg0 service.MeshService) *v1.Service {
 	m.ctrl.T.Helper()
@@ -180,3 +195,15 @@ func (mr *MockControllerMockRecorder) UpdateStatus(arg0 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockController)(nil).UpdateStatus), arg0)
 }
+
+// WatchSecrets mocks base method.
+func (m *MockController) WatchSecrets(arg0 []types.NamespacedName) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WatchSecrets", arg0)
+}
+
+// WatchSecrets indicates an expected call of WatchSecrets.
+func (mr *MockControllerMockRecorder) WatchSecrets(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchSecrets", reflect.TypeOf((*MockController)(nil).WatchSecrets), arg0)
+}
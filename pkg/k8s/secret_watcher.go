@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+// secretWatcher caches the secrets referenced by the resources of the mesh. Each referenced secret is watched by
+// a dedicated informer selecting the secret by name, so the other secrets of the cluster, which are typically many
+// and unrelated to the mesh, are neither cached nor observed.
+type secretWatcher struct {
+	kubeClient    kubernetes.Interface
+	msgBroker     *messaging.Broker
+	shouldObserve observeFilter
+	stop          <-chan struct{}
+
+	mu        sync.RWMutex
+	informers map[types.NamespacedName]*secretInformer
+}
+
+// secretInformer is the informer of a single secret, stopped once the secret is no longer referenced
+type secretInformer struct {
+	informer cache.SharedIndexInformer
+	stop     chan struct{}
+}
+
+// newSecretWatcher returns a secretWatcher whose informers run until the given stop channel is closed
+func newSecretWatcher(kubeClient kubernetes.Interface, msgBroker *messaging.Broker, shouldObserve observeFilter, stop <-chan struct{}) *secretWatcher {
+	return &secretWatcher{
+		kubeClient:    kubeClient,
+		msgBroker:     msgBroker,
+		shouldObserve: shouldObserve,
+		stop:          stop,
+		informers:     make(map[types.NamespacedName]*secretInformer),
+	}
+}
+
+// watch sets the secrets that are watched, starting the informers of the newly referenced secrets and stopping the
+// informers of the secrets that are no longer referenced
+func (w *secretWatcher) watch(secrets []types.NamespacedName) {
+	referenced := make(map[types.NamespacedName]bool)
+	for _, secret := range secrets {
+		referenced[secret] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for secret, secretInformer := range w.informers {
+		if !referenced[secret] {
+			log.Debug().Msgf("Secret %s is no longer referenced, stopping its watch", secret)
+			close(secretInformer.stop)
+			delete(w.informers, secret)
+		}
+	}
+
+	for secret := range referenced {
+		if _, ok := w.informers[secret]; ok {
+			continue
+		}
+		log.Debug().Msgf("Secret %s is referenced, starting its watch", secret)
+		w.informers[secret] = w.runSecretInformer(secret)
+	}
+}
+
+// runSecretInformer runs the informer of the given secret until it is stopped or the watcher's stop channel is closed
+func (w *secretWatcher) runSecretInformer(secret types.NamespacedName) *secretInformer {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(w.kubeClient, DefaultKubeEventResyncInterval,
+		informers.WithNamespace(secret.Namespace),
+		informers.WithTweakListOptions(func(opt *metav1.ListOptions) {
+			opt.FieldSelector = fields.OneTermEqualSelector("metadata.name", secret.Name).String()
+		}),
+	)
+	informer := informerFactory.Core().V1().Secrets().Informer()
+
+	secretEventTypes := EventTypes{
+		Add:    announcements.SecretAdded,
+		Update: announcements.SecretUpdated,
+		Delete: announcements.SecretDeleted,
+	}
+	informer.AddEventHandler(GetEventHandlerFuncs(w.shouldObserve, secretEventTypes, w.msgBroker))
+
+	secretInformer := &secretInformer{
+		informer: informer,
+		stop:     make(chan struct{}),
+	}
+
+	informerStop := make(chan struct{})
+	go func() {
+		select {
+		case <-w.stop:
+		case <-secretInformer.stop:
+		}
+		close(informerStop)
+	}()
+	go informer.Run(informerStop)
+
+	return secretInformer
+}
+
+// get returns the given secret if it is watched and exists, otherwise nil
+func (w *secretWatcher) get(secret types.NamespacedName) *corev1.Secret {
+	w.mu.RLock()
+	secretInformer, ok := w.informers[secret]
+	w.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	// client-go cache uses <namespace>/<name> as key
+	secretIf, exists, err := secretInformer.informer.GetStore().GetByKey(secret.String())
+	if exists && err == nil {
+		return secretIf.(*corev1.Secret)
+	}
+	return nil
+}
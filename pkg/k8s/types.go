@@ -7,6 +7,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -61,6 +62,8 @@ const (
 	Endpoints InformerKey = "Endpoints"
 	// ServiceAccounts lookup identifier
 	ServiceAccounts InformerKey = "ServiceAccounts"
+	// Secrets lookup identifier
+	Secrets InformerKey = "Secrets"
 )
 
 // informerCollection is the type holding the collection of informers we keep
@@ -72,6 +75,7 @@ type client struct {
 	kubeClient   kubernetes.Interface
 	policyClient policyv1alpha1Client.Interface
 	informers    informerCollection
+	secrets      *secretWatcher
 	msgBroker    *messaging.Broker
 }
 
@@ -105,6 +109,13 @@ type Controller interface {
 	// GetEndpoints returns the endpoints for a given service, if found
 	GetEndpoints(service.MeshService) (*corev1.Endpoints, error)
 
+	// WatchSecrets sets the secrets referenced by the resources of the mesh, which are the only secrets cached and observed
+	WatchSecrets([]types.NamespacedName)
+
+	// GetSecret returns the secret with the given name and namespace if it is watched and exists in a monitored
+	// namespace, otherwise nil
+	GetSecret(name string, namespace string) *corev1.Secret
+
 	// UpdateStatus updates the status subresource for the given resource and GroupVersionKind
 	// The object within the 'interface{}' must be a pointer to the underlying resource
 	UpdateStatus(interface{}) (metav1.Object, error)
@@ -256,6 +256,10 @@ func getProxyUpdateEvent(msg events.PubSubMessage) *proxyUpdateEvent {
 		announcements.EndpointAdded, announcements.EndpointDeleted, announcements.EndpointUpdated,
 		// k8s Ingress event
 		announcements.IngressAdded, announcements.IngressDeleted, announcements.IngressUpdated,
+		// k8s IngressClass event
+		announcements.IngressClassAdded, announcements.IngressClassDeleted, announcements.IngressClassUpdated,
+		// k8s Secret event
+		announcements.SecretAdded, announcements.SecretDeleted, announcements.SecretUpdated,
 		//
 		// OSM resource events
 		//
@@ -266,6 +270,17 @@ func getProxyUpdateEvent(msg events.PubSubMessage) *proxyUpdateEvent {
 		// MulticlusterService event
 		announcements.MultiClusterServiceAdded, announcements.MultiClusterServiceDeleted, announcements.MultiClusterServiceUpdated,
 		//
+		// Gateway API resource events
+		//
+		// GatewayClass event
+		announcements.GatewayClassAdded, announcements.GatewayClassDeleted, announcements.GatewayClassUpdated,
+		// Gateway event
+		announcements.GatewayAdded, announcements.GatewayDeleted, announcements.GatewayUpdated,
+		// HTTPRoute event
+		announcements.GatewayHTTPRouteAdded, announcements.GatewayHTTPRouteDeleted, announcements.GatewayHTTPRouteUpdated,
+		// TLSRoute event
+		announcements.GatewayTLSRouteAdded, announcements.GatewayTLSRouteDeleted, announcements.GatewayTLSRouteUpdated,
+		//
 		// SMI resource events
 		//
 		// SMI HTTPRouteGroup event
@@ -299,6 +314,7 @@ func getProxyUpdateEvent(msg events.PubSubMessage) *proxyUpdateEvent {
 		// changes.
 		if prevSpec.Traffic.EnableEgress != newSpec.Traffic.EnableEgress ||
 			prevSpec.Traffic.EnablePermissiveTrafficPolicyMode != newSpec.Traffic.EnablePermissiveTrafficPolicyMode ||
+			prevSpec.Traffic.EgressDenyFeedback != newSpec.Traffic.EgressDenyFeedback ||
 			prevSpec.Observability.Tracing != newSpec.Observability.Tracing ||
 			prevSpec.Traffic.InboundExternalAuthorization.Enable != newSpec.Traffic.InboundExternalAuthorization.Enable ||
 			// Only trigger an update on InboundExternalAuthorization field changes if the new spec has the 'Enable' flag set to true.
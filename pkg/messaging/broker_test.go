@@ -211,6 +211,28 @@ func TestGetProxyUpdateEvent(t *testing.T) {
 			expectEvent:   true,
 			expectedTopic: announcements.ProxyUpdate.String(),
 		},
+		{
+			name: "MeshConfig updated to change the egress deny feedback",
+			msg: events.PubSubMessage{
+				Kind: announcements.MeshConfigUpdated,
+				OldObj: &configv1alpha1.MeshConfig{
+					Spec: configv1alpha1.MeshConfigSpec{
+						Traffic: configv1alpha1.TrafficSpec{
+							EgressDenyFeedback: configv1alpha1.EgressDenyFeedbackSpec{Enable: true},
+						},
+					},
+				},
+				NewObj: &configv1alpha1.MeshConfig{
+					Spec: configv1alpha1.MeshConfigSpec{
+						Traffic: configv1alpha1.TrafficSpec{
+							EgressDenyFeedback: configv1alpha1.EgressDenyFeedbackSpec{Enable: true, HTTPStatusCode: 502},
+						},
+					},
+				},
+			},
+			expectEvent:   true,
+			expectedTopic: announcements.ProxyUpdate.String(),
+		},
 		{
 			name: "MeshConfigUpdate event with unexpected object type",
 			msg: events.PubSubMessage{
@@ -304,6 +326,24 @@ func TestGetProxyUpdateEvent(t *testing.T) {
 			expectEvent:   true,
 			expectedTopic: "proxy:foo",
 		},
+		{
+			// Label updates changing the Egress policies the pod is subject to are handled by the policy controller
+			name: "Pod label update event not resulting in a proxy update",
+			msg: events.PubSubMessage{
+				OldObj: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{constants.EnvoyUniqueIDLabelName: "foo"},
+					},
+				},
+				NewObj: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{constants.EnvoyUniqueIDLabelName: "foo", "app": "bar"},
+					},
+				},
+				Kind: announcements.PodUpdated,
+			},
+			expectEvent: false,
+		},
 		{
 			name: "Pod delete event",
 			msg: events.PubSubMessage{
@@ -58,6 +58,13 @@ type MetricsStore struct {
 	// InjectorRqTime the histogram to track times for the injector webhook calls
 	InjectorRqTime *prometheus.HistogramVec
 
+	// InjectorBootstrapSecretCount is the metric for the number of Envoy bootstrap config secrets whose pod exists (live),
+	// and whose pod does not exist (orphaned), as of their last garbage collection
+	InjectorBootstrapSecretCount *prometheus.GaugeVec
+
+	// InjectorBootstrapSecretDeleteCount is the metric counter for the number of orphaned Envoy bootstrap config secrets deleted
+	InjectorBootstrapSecretDeleteCount prometheus.Counter
+
 	/*
 	 * Certificate metrics
 	 */
@@ -181,6 +188,24 @@ func init() {
 			"success",
 		})
 
+	defaultMetricsStore.InjectorBootstrapSecretCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "injector",
+			Name:      "bootstrap_secret_count",
+			Help:      "Represents the number of Envoy bootstrap config secrets, by whether their pod exists",
+		},
+		[]string{
+			"state", // live or orphaned
+		})
+
+	defaultMetricsStore.InjectorBootstrapSecretDeleteCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "injector",
+		Name:      "bootstrap_secret_delete_count",
+		Help:      "Represents the number of orphaned Envoy bootstrap config secrets deleted",
+	})
+
 	/*
 	 * Certificate metrics
 	 */
@@ -0,0 +1,437 @@
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("multicluster-discovery")
+)
+
+const (
+	// DefaultDiscoveryInterval is the interval at which the services exported by the peer clusters are synced
+	DefaultDiscoveryInterval = 30 * time.Second
+
+	// DiscoveryLeaseName is the name of the Lease used to elect the controller replica running the discovery
+	DiscoveryLeaseName = "osm-multicluster-discovery"
+
+	// discoveryManagerName is the value of the MulticlusterManagedByLabel on the MultiClusterService
+	// resources generated by the discovery
+	discoveryManagerName = "osm-multicluster-discovery"
+
+	// peerKubeconfigKey is the key in a peer cluster Secret holding the kubeconfig of the peer cluster
+	peerKubeconfigKey = "kubeconfig"
+
+	// peerClusterNameKey is the optional key in a peer cluster Secret holding the name of the peer cluster.
+	// The name of the Secret is used as the cluster name if unspecified.
+	peerClusterNameKey = "clusterName"
+
+	// peerGatewayAddressKey is the optional key in a peer cluster Secret holding the IP:port address of the
+	// peer cluster's multicluster gateway. The address is looked up from the gateway's service in the peer
+	// cluster if unspecified.
+	peerGatewayAddressKey = "gatewayAddress"
+
+	// gatewayServiceName is the name of the multicluster gateway's service in the OSM namespace
+	gatewayServiceName = "osm-multicluster-gateway"
+
+	// gatewayPort is the port the multicluster gateway accepts connections from peer clusters on
+	gatewayPort = 15443
+)
+
+// Discovery imports the services exported by the peer clusters of the mesh into the local cluster.
+// Peer clusters are registered using Secrets in the OSM namespace labeled with MulticlusterPeerLabel,
+// which hold the kubeconfig used to discover the services exported by the peer cluster. A
+// MultiClusterService is generated for each imported service, and is removed once the service
+// is no longer exported by any of the peer clusters.
+type Discovery struct {
+	kubeClient   kubernetes.Interface
+	configClient configClientset.Interface
+	osmNamespace string
+
+	// conflicts is the set of imported services for which a MultiClusterService authored by the user exists,
+	// used to log a conflict only once until it is resolved
+	conflicts map[types.NamespacedName]bool
+
+	// newPeerClient returns a client for the peer cluster with the given kubeconfig
+	newPeerClient func(kubeconfig []byte) (kubernetes.Interface, error)
+}
+
+// peerCluster is the type used to represent a peer cluster registered with the mesh
+type peerCluster struct {
+	name           string
+	client         kubernetes.Interface
+	gatewayAddress string
+}
+
+// NewDiscovery returns a Discovery instance for the local cluster
+func NewDiscovery(kubeClient kubernetes.Interface, configClient configClientset.Interface, osmNamespace string) *Discovery {
+	return &Discovery{
+		kubeClient:    kubeClient,
+		configClient:  configClient,
+		osmNamespace:  osmNamespace,
+		conflicts:     make(map[types.NamespacedName]bool),
+		newPeerClient: newPeerClientFromKubeconfig,
+	}
+}
+
+// Start runs the discovery at the given interval in a goroutine until the given channel is closed.
+// Only one replica of the controller must run the discovery at a time, see k8s.RunWithLeaderElection.
+func (d *Discovery) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := d.Sync(); err != nil {
+				log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msg("Error syncing services exported by peer clusters")
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sync imports the services exported by the peer clusters, creating, updating and deleting the
+// MultiClusterService resources generated by the discovery as needed
+func (d *Discovery) Sync() error {
+	peers, unavailable, err := d.listPeerClusters()
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[types.NamespacedName]*v1alpha1.MultiClusterServiceSpec)
+	for _, peer := range peers {
+		imported, err := d.importServices(peer, desired)
+		if err != nil {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error importing services from peer cluster %s", peer.name)
+			unavailable[peer.name] = true
+			continue
+		}
+		mergeImportedServices(desired, imported)
+	}
+
+	return d.reconcile(desired, unavailable)
+}
+
+// listPeerClusters returns the peer clusters registered with the mesh, along with the names
+// of the registered peer clusters that are currently unavailable
+func (d *Discovery) listPeerClusters() ([]peerCluster, map[string]bool, error) {
+	secrets, err := d.kubeClient.CoreV1().Secrets(d.osmNamespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: constants.MulticlusterPeerLabel,
+	})
+	if err != nil {
+		return nil, nil, errors.Errorf("Error listing peer cluster secrets in namespace %s: %s", d.osmNamespace, err)
+	}
+
+	var peers []peerCluster
+	unavailable := make(map[string]bool)
+	for i := range secrets.Items {
+		peer, err := d.getPeerCluster(&secrets.Items[i])
+		if err != nil {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error registering peer cluster from secret %s/%s", d.osmNamespace, secrets.Items[i].Name)
+			unavailable[peer.name] = true
+			continue
+		}
+		peers = append(peers, peer)
+	}
+
+	return peers, unavailable, nil
+}
+
+// getPeerCluster returns the peer cluster registered using the given Secret
+func (d *Discovery) getPeerCluster(secret *corev1.Secret) (peerCluster, error) {
+	peer := peerCluster{
+		name: secret.Name,
+	}
+	if name, ok := secret.Data[peerClusterNameKey]; ok && len(name) > 0 {
+		peer.name = string(name)
+	}
+
+	kubeconfig, ok := secret.Data[peerKubeconfigKey]
+	if !ok {
+		return peer, errors.Errorf("Missing key %s", peerKubeconfigKey)
+	}
+
+	client, err := d.newPeerClient(kubeconfig)
+	if err != nil {
+		return peer, errors.Errorf("Error creating client for peer cluster %s: %s", peer.name, err)
+	}
+	peer.client = client
+
+	if address, ok := secret.Data[peerGatewayAddressKey]; ok && len(address) > 0 {
+		peer.gatewayAddress = string(address)
+	} else if peer.gatewayAddress, err = d.getPeerGatewayAddress(client); err != nil {
+		return peer, err
+	}
+
+	if _, _, err := net.SplitHostPort(peer.gatewayAddress); err != nil {
+		return peer, errors.Errorf("Invalid gateway address %s for peer cluster %s: %s", peer.gatewayAddress, peer.name, err)
+	}
+
+	return peer, nil
+}
+
+// getPeerGatewayAddress returns the externally reachable address of the given peer cluster's multicluster gateway
+func (d *Discovery) getPeerGatewayAddress(client kubernetes.Interface) (string, error) {
+	gatewaySvc, err := client.CoreV1().Services(d.osmNamespace).Get(context.Background(), gatewayServiceName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Errorf("Error fetching multicluster gateway service %s/%s: %s", d.osmNamespace, gatewayServiceName, err)
+	}
+
+	for _, ingress := range gatewaySvc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return net.JoinHostPort(ingress.IP, fmt.Sprint(gatewayPort)), nil
+		}
+	}
+
+	return "", errors.Errorf("Multicluster gateway service %s/%s does not have an external IP", d.osmNamespace, gatewayServiceName)
+}
+
+// importServices returns the specs of the services exported by the given peer cluster, holding only the peer cluster
+// and the ports of the services. The service account of a service is looked up only if the service was not imported
+// from another peer cluster, in the given desired specs. The returned specs are merged into the desired specs only if
+// all the services exported by the peer cluster were imported, so that the clusters of a peer that is unavailable
+// midway are retained as is by the reconciliation.
+func (d *Discovery) importServices(peer peerCluster, desired map[types.NamespacedName]*v1alpha1.MultiClusterServiceSpec) (map[types.NamespacedName]*v1alpha1.MultiClusterServiceSpec, error) {
+	services, err := peer.client.CoreV1().Services(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Errorf("Error listing services: %s", err)
+	}
+
+	imported := make(map[types.NamespacedName]*v1alpha1.MultiClusterServiceSpec)
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if !isServiceExported(svc) {
+			continue
+		}
+
+		name := types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}
+		var serviceAccount string
+		if spec, ok := desired[name]; ok {
+			serviceAccount = spec.ServiceAccount
+		} else {
+			if serviceAccount, err = getServiceAccountForService(peer.client, svc); err != nil {
+				return nil, err
+			}
+			if serviceAccount == "" {
+				log.Debug().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Skipping exported service %s in peer cluster %s without pods", name, peer.name)
+				continue
+			}
+		}
+
+		imported[name] = &v1alpha1.MultiClusterServiceSpec{
+			ServiceAccount: serviceAccount,
+			Clusters: []v1alpha1.ClusterSpec{{
+				Name:    peer.name,
+				Address: peer.gatewayAddress,
+			}},
+			Ports: mergePorts(nil, svc.Spec.Ports),
+		}
+	}
+
+	return imported, nil
+}
+
+// mergeImportedServices merges the specs of the services imported from a peer cluster into the desired specs
+func mergeImportedServices(desired, imported map[types.NamespacedName]*v1alpha1.MultiClusterServiceSpec) {
+	for name, spec := range imported {
+		existing, ok := desired[name]
+		if !ok {
+			desired[name] = spec
+			continue
+		}
+		existing.Clusters = append(existing.Clusters, spec.Clusters...)
+		existing.Ports = mergePortSpecs(existing.Ports, spec.Ports)
+	}
+}
+
+// reconcile creates, updates and deletes the MultiClusterService resources generated by the discovery
+// so that they match the given desired specs. The clusters of unavailable peers are retained so that a
+// transient failure to reach a peer does not remove the services imported from it.
+func (d *Discovery) reconcile(desired map[types.NamespacedName]*v1alpha1.MultiClusterServiceSpec, unavailable map[string]bool) error {
+	mcsClient := d.configClient.ConfigV1alpha1()
+	existing, err := mcsClient.MultiClusterServices(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{constants.MulticlusterManagedByLabel: discoveryManagerName}).String(),
+	})
+	if err != nil {
+		return errors.Errorf("Error listing MultiClusterServices generated by discovery: %s", err)
+	}
+
+	for i := range existing.Items {
+		mcs := &existing.Items[i]
+		name := types.NamespacedName{Namespace: mcs.Namespace, Name: mcs.Name}
+		for _, cluster := range mcs.Spec.Clusters {
+			if !unavailable[cluster.Name] {
+				continue
+			}
+			if _, ok := desired[name]; !ok {
+				desired[name] = &v1alpha1.MultiClusterServiceSpec{
+					ServiceAccount: mcs.Spec.ServiceAccount,
+					Ports:          mcs.Spec.Ports,
+				}
+			}
+			desired[name].Clusters = append(desired[name].Clusters, cluster)
+		}
+
+		spec, ok := desired[name]
+		delete(desired, name)
+
+		if !ok {
+			log.Info().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Deleting MultiClusterService %s no longer exported by peer clusters", name)
+			if err := mcsClient.MultiClusterServices(mcs.Namespace).Delete(context.Background(), mcs.Name, metav1.DeleteOptions{}); err != nil && !k8sErrors.IsNotFound(err) {
+				log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error deleting MultiClusterService %s", name)
+			}
+			continue
+		}
+
+		sortClusters(spec)
+		if isSpecUpToDate(mcs.Spec, *spec) {
+			continue
+		}
+
+		// Preserve the failover policy configured by the user on the generated resource
+		spec.Failover = mcs.Spec.Failover
+		mcs.Spec = *spec
+		log.Info().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Updating MultiClusterService %s", name)
+		if _, err := mcsClient.MultiClusterServices(mcs.Namespace).Update(context.Background(), mcs, metav1.UpdateOptions{}); err != nil {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error updating MultiClusterService %s", name)
+		}
+	}
+
+	conflicts := make(map[types.NamespacedName]bool)
+	for name, spec := range desired {
+		sortClusters(spec)
+		mcs := &v1alpha1.MultiClusterService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+				Labels: map[string]string{
+					constants.MulticlusterManagedByLabel: discoveryManagerName,
+				},
+			},
+			Spec: *spec,
+		}
+
+		_, err := mcsClient.MultiClusterServices(name.Namespace).Create(context.Background(), mcs, metav1.CreateOptions{})
+		switch {
+		case k8sErrors.IsAlreadyExists(err):
+			// A MultiClusterService authored by the user is never overwritten
+			if !d.conflicts[name] {
+				log.Warn().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Skipping import of service %s from peer clusters, conflicts with existing MultiClusterService not generated by discovery", name)
+			}
+			conflicts[name] = true
+		case err != nil:
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error creating MultiClusterService %s", name)
+		default:
+			log.Info().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Created MultiClusterService %s imported from peer clusters", name)
+		}
+	}
+	d.conflicts = conflicts
+
+	return nil
+}
+
+// isServiceExported returns a boolean indicating if the given service is exported to the peer clusters
+// of the mesh, using either a label or an annotation
+func isServiceExported(svc *corev1.Service) bool {
+	return svc.Labels[constants.MulticlusterExportLabel] == "true" || svc.Annotations[constants.MulticlusterExportLabel] == "true"
+}
+
+// getServiceAccountForService returns the service account of a pod backing the given service,
+// or an empty string if the service does not have any pods
+func getServiceAccountForService(client kubernetes.Interface, svc *corev1.Service) (string, error) {
+	if len(svc.Spec.Selector) == 0 {
+		return "", nil
+	}
+
+	pods, err := client.CoreV1().Pods(svc.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", errors.Errorf("Error listing pods for service %s/%s: %s", svc.Namespace, svc.Name, err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Spec.ServiceAccountName != "" {
+			return pod.Spec.ServiceAccountName, nil
+		}
+	}
+
+	return "", nil
+}
+
+// mergePorts adds the given service ports to the MultiClusterService ports if not already present
+func mergePorts(ports []v1alpha1.PortSpec, svcPorts []corev1.ServicePort) []v1alpha1.PortSpec {
+	var portSpecs []v1alpha1.PortSpec
+	for _, svcPort := range svcPorts {
+		portSpecs = append(portSpecs, v1alpha1.PortSpec{
+			Port:     uint32(svcPort.Port),
+			Protocol: string(svcPort.Protocol),
+		})
+	}
+	return mergePortSpecs(ports, portSpecs)
+}
+
+// mergePortSpecs adds the given ports to the MultiClusterService ports if not already present
+func mergePortSpecs(ports []v1alpha1.PortSpec, newPorts []v1alpha1.PortSpec) []v1alpha1.PortSpec {
+	for _, port := range newPorts {
+		found := false
+		for _, p := range ports {
+			if p == port {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// sortClusters sorts the clusters of the given spec by name so that specs can be compared
+func sortClusters(spec *v1alpha1.MultiClusterServiceSpec) {
+	sort.Slice(spec.Clusters, func(i, j int) bool {
+		return spec.Clusters[i].Name < spec.Clusters[j].Name
+	})
+}
+
+// isSpecUpToDate returns a boolean indicating if the existing spec matches the desired spec,
+// ignoring the failover policy which is configured by the user
+func isSpecUpToDate(existing, desired v1alpha1.MultiClusterServiceSpec) bool {
+	return existing.ServiceAccount == desired.ServiceAccount &&
+		reflect.DeepEqual(existing.Clusters, desired.Clusters) &&
+		reflect.DeepEqual(existing.Ports, desired.Ports)
+}
+
+// newPeerClientFromKubeconfig returns a Kubernetes client for the peer cluster with the given kubeconfig
+func newPeerClientFromKubeconfig(kubeconfig []byte) (kubernetes.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
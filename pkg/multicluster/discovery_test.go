@@ -0,0 +1,353 @@
+package multicluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	fakeConfig "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+)
+
+const testOSMNamespace = "osm-system"
+
+func newPeerSecret(name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testOSMNamespace,
+			Labels:    map[string]string{constants.MulticlusterPeerLabel: "true"},
+		},
+		Data: data,
+	}
+}
+
+func newExportedService(name, namespace string, port int32) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{constants.MulticlusterExportLabel: "true"},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": name},
+			Ports:    []corev1.ServicePort{{Port: port, Protocol: corev1.ProtocolTCP}},
+		},
+	}
+}
+
+func newPod(name, namespace, app, serviceAccount string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": app},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: serviceAccount,
+		},
+	}
+}
+
+func TestDiscoverySync(t *testing.T) {
+	assert := tassert.New(t)
+
+	gatewaySvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: gatewayServiceName, Namespace: testOSMNamespace},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "20.0.0.1"}},
+			},
+		},
+	}
+	unexportedSvc := newExportedService("internal", "ns1", 80)
+	unexportedSvc.Labels = nil
+
+	peers := map[string]kubernetes.Interface{
+		"alpha": fake.NewSimpleClientset(
+			newExportedService("bookstore", "ns1", 80),
+			newPod("bookstore-1", "ns1", "bookstore", "bookstore"),
+			unexportedSvc,
+			newPod("internal-1", "ns1", "internal", "internal"),
+		),
+		"beta": fake.NewSimpleClientset(
+			gatewaySvc,
+			newExportedService("bookstore", "ns1", 80),
+			newPod("bookstore-1", "ns1", "bookstore", "bookstore"),
+			newExportedService("bookwarehouse", "ns2", 8080),
+		),
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		newPeerSecret("alpha", map[string][]byte{
+			peerKubeconfigKey:     []byte("alpha"),
+			peerGatewayAddressKey: []byte("10.0.0.1:15443"),
+		}),
+		newPeerSecret("beta-secret", map[string][]byte{
+			peerKubeconfigKey:  []byte("beta"),
+			peerClusterNameKey: []byte("beta"),
+		}),
+	)
+	configClient := fakeConfig.NewSimpleClientset(
+		&v1alpha1.MultiClusterService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "stale",
+				Namespace: "ns1",
+				Labels:    map[string]string{constants.MulticlusterManagedByLabel: discoveryManagerName},
+			},
+			Spec: v1alpha1.MultiClusterServiceSpec{
+				ServiceAccount: "stale",
+				Clusters:       []v1alpha1.ClusterSpec{{Name: "alpha", Address: "10.0.0.1:15443"}},
+			},
+		},
+		&v1alpha1.MultiClusterService{
+			ObjectMeta: metav1.ObjectMeta{Name: "user-authored", Namespace: "ns1"},
+		},
+	)
+
+	d := NewDiscovery(kubeClient, configClient, testOSMNamespace)
+	d.newPeerClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+		return peers[string(kubeconfig)], nil
+	}
+
+	assert.Nil(d.Sync())
+
+	mcsList, err := configClient.ConfigV1alpha1().MultiClusterServices(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	assert.Nil(err)
+	assert.Len(mcsList.Items, 2)
+
+	// The bookwarehouse service does not have pods in the peer cluster and is not imported.
+	// The stale MultiClusterService is deleted, while the one authored by the user is retained.
+	bookstore, err := configClient.ConfigV1alpha1().MultiClusterServices("ns1").Get(context.Background(), "bookstore", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(discoveryManagerName, bookstore.Labels[constants.MulticlusterManagedByLabel])
+	assert.Equal(v1alpha1.MultiClusterServiceSpec{
+		ServiceAccount: "bookstore",
+		Ports:          []v1alpha1.PortSpec{{Port: 80, Protocol: "TCP"}},
+		Clusters: []v1alpha1.ClusterSpec{
+			{Name: "alpha", Address: "10.0.0.1:15443"},
+			{Name: "beta", Address: "20.0.0.1:15443"},
+		},
+	}, bookstore.Spec)
+
+	_, err = configClient.ConfigV1alpha1().MultiClusterServices("ns1").Get(context.Background(), "user-authored", metav1.GetOptions{})
+	assert.Nil(err)
+
+	// The service is no longer exported by the beta cluster, and the alpha cluster is unreachable
+	bookstore.Spec.Failover = &v1alpha1.FailoverSpec{Mode: v1alpha1.ActiveActiveMode}
+	_, err = configClient.ConfigV1alpha1().MultiClusterServices("ns1").Update(context.Background(), bookstore, metav1.UpdateOptions{})
+	assert.Nil(err)
+	err = peers["beta"].CoreV1().Services("ns1").Delete(context.Background(), "bookstore", metav1.DeleteOptions{})
+	assert.Nil(err)
+	d.newPeerClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+		if string(kubeconfig) == "alpha" {
+			return nil, errors.New("unreachable")
+		}
+		return peers[string(kubeconfig)], nil
+	}
+
+	assert.Nil(d.Sync())
+
+	bookstore, err = configClient.ConfigV1alpha1().MultiClusterServices("ns1").Get(context.Background(), "bookstore", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal([]v1alpha1.ClusterSpec{{Name: "alpha", Address: "10.0.0.1:15443"}}, bookstore.Spec.Clusters)
+	assert.Equal(&v1alpha1.FailoverSpec{Mode: v1alpha1.ActiveActiveMode}, bookstore.Spec.Failover)
+
+	// The alpha cluster is reachable but no longer exports the service
+	err = peers["alpha"].CoreV1().Services("ns1").Delete(context.Background(), "bookstore", metav1.DeleteOptions{})
+	assert.Nil(err)
+	d.newPeerClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+		return peers[string(kubeconfig)], nil
+	}
+
+	assert.Nil(d.Sync())
+
+	_, err = configClient.ConfigV1alpha1().MultiClusterServices("ns1").Get(context.Background(), "bookstore", metav1.GetOptions{})
+	assert.NotNil(err)
+}
+
+func TestDiscoverySyncPartialImport(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Listing the pods of the second exported service fails midway through importing the services of the peer cluster
+	peer := fake.NewSimpleClientset(
+		newExportedService("bookstore", "ns1", 80),
+		newPod("bookstore-1", "ns1", "bookstore", "bookstore"),
+		newExportedService("bookwarehouse", "ns2", 8080),
+		newPod("bookwarehouse-1", "ns2", "bookwarehouse", "bookwarehouse"),
+	)
+	podLists := 0
+	peer.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		podLists++
+		if podLists == 2 {
+			return true, nil, errors.New("unavailable")
+		}
+		return false, nil, nil
+	})
+
+	kubeClient := fake.NewSimpleClientset(
+		newPeerSecret("alpha", map[string][]byte{
+			peerKubeconfigKey:     []byte("alpha"),
+			peerGatewayAddressKey: []byte("10.0.0.1:15443"),
+		}),
+	)
+	existingSpec := v1alpha1.MultiClusterServiceSpec{
+		ServiceAccount: "bookstore",
+		Ports:          []v1alpha1.PortSpec{{Port: 80, Protocol: "TCP"}},
+		Clusters:       []v1alpha1.ClusterSpec{{Name: "alpha", Address: "10.0.0.1:15443"}},
+	}
+	configClient := fakeConfig.NewSimpleClientset(
+		&v1alpha1.MultiClusterService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bookstore",
+				Namespace: "ns1",
+				Labels:    map[string]string{constants.MulticlusterManagedByLabel: discoveryManagerName},
+			},
+			Spec: existingSpec,
+		},
+	)
+
+	d := NewDiscovery(kubeClient, configClient, testOSMNamespace)
+	d.newPeerClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+		return peer, nil
+	}
+
+	assert.Nil(d.Sync())
+
+	// The services of the peer cluster are retained as is, without duplicating its cluster nor importing
+	// the services imported before the failure
+	mcsList, err := configClient.ConfigV1alpha1().MultiClusterServices(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	assert.Nil(err)
+	assert.Len(mcsList.Items, 1)
+	assert.Equal(existingSpec, mcsList.Items[0].Spec)
+}
+
+func TestDiscoverySyncConflict(t *testing.T) {
+	assert := tassert.New(t)
+
+	peer := fake.NewSimpleClientset(
+		newExportedService("bookstore", "ns1", 80),
+		newPod("bookstore-1", "ns1", "bookstore", "bookstore"),
+	)
+	kubeClient := fake.NewSimpleClientset(
+		newPeerSecret("alpha", map[string][]byte{
+			peerKubeconfigKey:     []byte("alpha"),
+			peerGatewayAddressKey: []byte("10.0.0.1:15443"),
+		}),
+	)
+	configClient := fakeConfig.NewSimpleClientset(
+		&v1alpha1.MultiClusterService{
+			ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "ns1"},
+		},
+	)
+
+	d := NewDiscovery(kubeClient, configClient, testOSMNamespace)
+	d.newPeerClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+		return peer, nil
+	}
+
+	// The conflict with the MultiClusterService authored by the user is tracked until it is resolved
+	assert.Nil(d.Sync())
+	assert.Equal(map[types.NamespacedName]bool{{Namespace: "ns1", Name: "bookstore"}: true}, d.conflicts)
+
+	err := configClient.ConfigV1alpha1().MultiClusterServices("ns1").Delete(context.Background(), "bookstore", metav1.DeleteOptions{})
+	assert.Nil(err)
+
+	assert.Nil(d.Sync())
+	assert.Empty(d.conflicts)
+	bookstore, err := configClient.ConfigV1alpha1().MultiClusterServices("ns1").Get(context.Background(), "bookstore", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(discoveryManagerName, bookstore.Labels[constants.MulticlusterManagedByLabel])
+}
+
+func TestGetPeerCluster(t *testing.T) {
+	testCases := []struct {
+		name            string
+		secret          *corev1.Secret
+		expectedName    string
+		expectedAddress string
+		expectError     bool
+	}{
+		{
+			name: "cluster name defaults to the secret name",
+			secret: newPeerSecret("alpha", map[string][]byte{
+				peerKubeconfigKey:     []byte("kubeconfig"),
+				peerGatewayAddressKey: []byte("10.0.0.1:15443"),
+			}),
+			expectedName:    "alpha",
+			expectedAddress: "10.0.0.1:15443",
+			expectError:     false,
+		},
+		{
+			name: "missing kubeconfig",
+			secret: newPeerSecret("alpha", map[string][]byte{
+				peerGatewayAddressKey: []byte("10.0.0.1:15443"),
+			}),
+			expectedName: "alpha",
+			expectError:  true,
+		},
+		{
+			name: "invalid gateway address",
+			secret: newPeerSecret("alpha", map[string][]byte{
+				peerKubeconfigKey:     []byte("kubeconfig"),
+				peerGatewayAddressKey: []byte("10.0.0.1"),
+			}),
+			expectedName:    "alpha",
+			expectedAddress: "10.0.0.1",
+			expectError:     true,
+		},
+		{
+			name: "gateway service without an external IP",
+			secret: newPeerSecret("alpha", map[string][]byte{
+				peerKubeconfigKey: []byte("kubeconfig"),
+			}),
+			expectedName: "alpha",
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			d := NewDiscovery(fake.NewSimpleClientset(), fakeConfig.NewSimpleClientset(), testOSMNamespace)
+			d.newPeerClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+				return fake.NewSimpleClientset(&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: gatewayServiceName, Namespace: testOSMNamespace},
+				}), nil
+			}
+
+			peer, err := d.getPeerCluster(tc.secret)
+			assert.Equal(tc.expectError, err != nil)
+			assert.Equal(tc.expectedName, peer.name)
+			assert.Equal(tc.expectedAddress, peer.gatewayAddress)
+		})
+	}
+}
+
+func TestIsServiceExported(t *testing.T) {
+	assert := tassert.New(t)
+
+	svc := &corev1.Service{}
+	assert.False(isServiceExported(svc))
+
+	svc.Annotations = map[string]string{constants.MulticlusterExportLabel: "true"}
+	assert.True(isServiceExported(svc))
+
+	svc.Annotations = nil
+	svc.Labels = map[string]string{constants.MulticlusterExportLabel: "false"}
+	assert.False(isServiceExported(svc))
+
+	svc.Labels[constants.MulticlusterExportLabel] = "true"
+	assert.True(isServiceExported(svc))
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy"
 )
 
+// GatewayServiceAccountName is the name of the service account of the multicluster gateway. The gateway's
+// certificate is issued for this service account, so that it has its own identity in the mesh.
+const GatewayServiceAccountName = "osm-multicluster-gateway"
+
 // GetMulticlusterGatewaySubjectCommonName creates a unique certificate.CommonName
 // specifically for a Multicluster Gateway. Each gateway will have its own unique
 // cert. The kind of Envoy (gateway) is encoded in the cert CN by convention.
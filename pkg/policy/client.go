@@ -1,8 +1,13 @@
 package policy
 
 import (
+	"reflect"
+	"sort"
+
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 
 	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
@@ -13,12 +18,21 @@ import (
 	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
 const (
-	// egressSourceKindSvcAccount is the ServiceAccount kind for a source defined in Egress policy
-	egressSourceKindSvcAccount = "ServiceAccount"
+	// EgressSourceKindSvcAccount is the ServiceAccount kind for a source defined in Egress policy
+	EgressSourceKindSvcAccount = "ServiceAccount"
+
+	// EgressSourceKindNamespace is the Namespace kind for a source defined in Egress policy, selecting
+	// all the workloads in the namespace
+	EgressSourceKindNamespace = "Namespace"
+
+	// EgressSourceKindPod is the Pod kind for a source defined in Egress policy, selecting the pods
+	// matching a label selector in the namespace
+	EgressSourceKindPod = "Pod"
 )
 
 // NewPolicyController returns a policy.Controller interface related to functionality provided by the resources in the policy.openservicemesh.io API group
@@ -71,6 +85,10 @@ func newClient(kubeController k8s.Controller, policyClient policyClientset.Inter
 		return client, errors.Errorf("Could not start %s informer clients: %s", policyV1alpha1.SchemeGroupVersion, err)
 	}
 
+	if msgBroker != nil {
+		go client.watchEgressSourcePods(msgBroker, stop)
+	}
+
 	return client, err
 }
 
@@ -108,6 +126,63 @@ func (c client) run(stop <-chan struct{}) error {
 	return nil
 }
 
+// watchEgressSourcePods updates the proxies when a pod selected by a Pod source of an Egress policy is added or
+// deleted, or when its labels change such that it is selected or no longer selected, until the stop channel is closed.
+// Label changes of the other pods do not change the Egress policies the proxies are subject to.
+func (c client) watchEgressSourcePods(msgBroker *messaging.Broker, stop <-chan struct{}) {
+	kubePubSub := msgBroker.GetKubeEventPubSub()
+	podChan := kubePubSub.Sub(announcements.PodAdded.String(), announcements.PodUpdated.String(), announcements.PodDeleted.String())
+	defer msgBroker.Unsub(kubePubSub, podChan)
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case msg := <-podChan:
+			psubMessage, ok := msg.(events.PubSubMessage)
+			if !ok {
+				log.Error().Msgf("Error casting to events.PubSubMessage, got type %T", msg)
+				continue
+			}
+			if c.isEgressSourcePodEvent(psubMessage) {
+				log.Debug().Msgf("Pod selected by an Egress Pod source changed (%s), updating proxies", psubMessage.Kind)
+				msgBroker.GetQueue().AddRateLimited(events.PubSubMessage{
+					Kind: announcements.ProxyUpdate,
+				})
+			}
+		}
+	}
+}
+
+// isEgressSourcePodEvent returns true if the given pod event changes the pods selected by the Pod sources of the
+// Egress policies
+func (c client) isEgressSourcePodEvent(msg events.PubSubMessage) bool {
+	prevPod, _ := msg.OldObj.(*corev1.Pod)
+	newPod, _ := msg.NewObj.(*corev1.Pod)
+	if msg.Kind == announcements.PodUpdated && prevPod != nil && newPod != nil && reflect.DeepEqual(prevPod.Labels, newPod.Labels) {
+		return false
+	}
+
+	for _, egress := range c.ListEgressPolicies() {
+		for _, sourceSpec := range egress.Spec.Sources {
+			if sourceSpec.Kind != EgressSourceKindPod {
+				continue
+			}
+			selector, err := getEgressSourcePodSelector(sourceSpec)
+			if err != nil {
+				continue
+			}
+			for _, pod := range []*corev1.Pod{prevPod, newPod} {
+				if pod != nil && pod.Namespace == sourceSpec.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 // ListEgressPoliciesForSourceIdentity lists the Egress policies for the given source identity based on service accounts
 func (c client) ListEgressPoliciesForSourceIdentity(source identity.K8sServiceAccount) []*policyV1alpha1.Egress {
 	var policies []*policyV1alpha1.Egress
@@ -120,8 +195,9 @@ func (c client) ListEgressPoliciesForSourceIdentity(source identity.K8sServiceAc
 		}
 
 		for _, sourceSpec := range egressPolicy.Spec.Sources {
-			if sourceSpec.Kind == egressSourceKindSvcAccount && sourceSpec.Name == source.Name && sourceSpec.Namespace == source.Namespace {
+			if c.isEgressSource(sourceSpec, source) {
 				policies = append(policies, egressPolicy)
+				break
 			}
 		}
 	}
@@ -129,6 +205,166 @@ func (c client) ListEgressPoliciesForSourceIdentity(source identity.K8sServiceAc
 	return policies
 }
 
+// ListEgressSourceIdentities lists the service identities of the sources the given Egress policy applies to
+func (c client) ListEgressSourceIdentities(egress *policyV1alpha1.Egress) []identity.K8sServiceAccount {
+	sourceSet := make(map[identity.K8sServiceAccount]bool)
+
+	for _, sourceSpec := range egress.Spec.Sources {
+		switch sourceSpec.Kind {
+		case EgressSourceKindSvcAccount:
+			sourceSet[identity.K8sServiceAccount{Name: sourceSpec.Name, Namespace: sourceSpec.Namespace}] = true
+
+		case EgressSourceKindNamespace:
+			for _, sa := range c.kubeController.ListServiceAccounts() {
+				if sa.Namespace == sourceSpec.Name {
+					sourceSet[identity.K8sServiceAccount{Name: sa.Name, Namespace: sa.Namespace}] = true
+				}
+			}
+
+		case EgressSourceKindPod:
+			podSources, err := c.listEgressPodSourceIdentities(sourceSpec)
+			if err != nil {
+				log.Debug().Err(err).Msgf("Ignoring Pod source of Egress policy %s/%s", egress.Namespace, egress.Name)
+				continue
+			}
+			for _, source := range podSources {
+				sourceSet[source] = true
+			}
+		}
+	}
+
+	var sources []identity.K8sServiceAccount
+	for source := range sourceSet {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].String() < sources[j].String()
+	})
+
+	return sources
+}
+
+// isEgressSource returns true if the given Egress source selects the workloads of the given service account
+func (c client) isEgressSource(sourceSpec policyV1alpha1.EgressSourceSpec, source identity.K8sServiceAccount) bool {
+	switch sourceSpec.Kind {
+	case EgressSourceKindSvcAccount:
+		return sourceSpec.Name == source.Name && sourceSpec.Namespace == source.Namespace
+
+	case EgressSourceKindNamespace:
+		return sourceSpec.Name == source.Namespace
+
+	case EgressSourceKindPod:
+		if sourceSpec.Namespace != source.Namespace {
+			return false
+		}
+		podSources, err := c.listEgressPodSourceIdentities(sourceSpec)
+		if err != nil {
+			return false
+		}
+		for _, podSource := range podSources {
+			if podSource == source {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ValidateEgressSources validates that the sources of the given Egress policy can be applied to the workloads they select
+func (c client) ValidateEgressSources(egress *policyV1alpha1.Egress) error {
+	for _, sourceSpec := range egress.Spec.Sources {
+		if sourceSpec.Kind != EgressSourceKindPod {
+			continue
+		}
+		if _, err := c.listEgressPodSourceIdentities(sourceSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listEgressPodSourceIdentities lists the service identities of the pods selected by the given Egress source of the
+// Pod kind. Since the egress configuration is programmed per service identity, an error is returned if a selected
+// pod shares its service account with a pod that is not selected, which would otherwise be subject to the policy.
+func (c client) listEgressPodSourceIdentities(sourceSpec policyV1alpha1.EgressSourceSpec) ([]identity.K8sServiceAccount, error) {
+	selector, err := getEgressSourcePodSelector(sourceSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool)
+	unselected := make(map[string]bool)
+	for _, pod := range c.kubeController.ListPods() {
+		if pod.Namespace != sourceSpec.Namespace {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			selected[pod.Spec.ServiceAccountName] = true
+		} else {
+			unselected[pod.Spec.ServiceAccountName] = true
+		}
+	}
+
+	var sources []identity.K8sServiceAccount
+	for serviceAccount := range selected {
+		if unselected[serviceAccount] {
+			return nil, errors.Errorf("Pod selector %s in namespace %s selects pods of service account %s that is also used by pods it does not select, "+
+				"the pods selected by a Pod source must not share their service account with other pods",
+				selector, sourceSpec.Namespace, serviceAccount)
+		}
+		sources = append(sources, identity.K8sServiceAccount{Name: serviceAccount, Namespace: sourceSpec.Namespace})
+	}
+	return sources, nil
+}
+
+// getEgressSourcePodSelector returns the label selector of the given Egress source of the Pod kind
+func getEgressSourcePodSelector(sourceSpec policyV1alpha1.EgressSourceSpec) (labels.Selector, error) {
+	if sourceSpec.PodSelector == nil {
+		return nil, errors.Errorf("Pod selector not specified in Egress source for namespace %s", sourceSpec.Namespace)
+	}
+	// This should not fail because the validating webhook will prevent invalid selectors
+	selector, err := metav1.LabelSelectorAsSelector(sourceSpec.PodSelector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid pod selector specified in Egress source for namespace %s", sourceSpec.Namespace)
+	}
+	return selector, nil
+}
+
+// ListEgressPolicies lists the Egress policies in the monitored namespaces
+func (c client) ListEgressPolicies() []*policyV1alpha1.Egress {
+	var policies []*policyV1alpha1.Egress
+
+	for _, egressIface := range c.caches.egress.List() {
+		egressPolicy := egressIface.(*policyV1alpha1.Egress)
+
+		if !c.kubeController.IsMonitoredNamespace(egressPolicy.Namespace) {
+			continue
+		}
+
+		policies = append(policies, egressPolicy)
+	}
+
+	return policies
+}
+
+// ListIngressBackendPolicies lists the IngressBackend policies in the monitored namespaces
+func (c client) ListIngressBackendPolicies() []*policyV1alpha1.IngressBackend {
+	var policies []*policyV1alpha1.IngressBackend
+
+	for _, ingressBackendIface := range c.caches.ingressBackend.List() {
+		ingressBackend := ingressBackendIface.(*policyV1alpha1.IngressBackend)
+
+		if !c.kubeController.IsMonitoredNamespace(ingressBackend.Namespace) {
+			continue
+		}
+
+		policies = append(policies, ingressBackend)
+	}
+
+	return policies
+}
+
 // GetIngressBackendPolicy returns the IngressBackend policy for the given backend MeshService
 func (c client) GetIngressBackendPolicy(svc service.MeshService) *policyV1alpha1.IngressBackend {
 	for _, ingressBackendIface := range c.caches.ingressBackend.List() {
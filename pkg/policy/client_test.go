@@ -6,13 +6,16 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	fakePolicyClient "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
 
+	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
@@ -146,6 +149,70 @@ func TestListEgressPoliciesForSourceIdentity(t *testing.T) {
 	}
 }
 
+func TestListEgressPolicies(t *testing.T) {
+	a := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	monitoredEgress := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "egress-1",
+			Namespace: "test",
+		},
+	}
+	unmonitoredEgress := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "egress-2",
+			Namespace: "unmonitored",
+		},
+	}
+
+	c, err := newClient(mockKubeController, fakePolicyClient.NewSimpleClientset(), nil, nil)
+	a.Nil(err)
+	a.NotNil(c)
+
+	_ = c.caches.egress.Add(monitoredEgress)
+	_ = c.caches.egress.Add(unmonitoredEgress)
+
+	a.ElementsMatch([]*policyV1alpha1.Egress{monitoredEgress}, c.ListEgressPolicies())
+}
+
+func TestListIngressBackendPolicies(t *testing.T) {
+	a := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	monitoredIngressBackend := &policyV1alpha1.IngressBackend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-backend-1",
+			Namespace: "test",
+		},
+	}
+	unmonitoredIngressBackend := &policyV1alpha1.IngressBackend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-backend-2",
+			Namespace: "unmonitored",
+		},
+	}
+
+	c, err := newClient(mockKubeController, fakePolicyClient.NewSimpleClientset(), nil, nil)
+	a.Nil(err)
+	a.NotNil(c)
+
+	_ = c.caches.ingressBackend.Add(monitoredIngressBackend)
+	_ = c.caches.ingressBackend.Add(unmonitoredIngressBackend)
+
+	a.ElementsMatch([]*policyV1alpha1.IngressBackend{monitoredIngressBackend}, c.ListIngressBackendPolicies())
+}
+
 func TestGetIngressBackendPolicy(t *testing.T) {
 	testCases := []struct {
 		name                   string
@@ -333,3 +400,217 @@ func TestGetIngressBackendPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestListEgressPoliciesForNamespaceAndPodSources(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().ListPods().Return([]*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "foo", Labels: map[string]string{"app": "foo"}},
+			Spec:       corev1.PodSpec{ServiceAccountName: "sa-1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-2", Namespace: "foo", Labels: map[string]string{"app": "bar"}},
+			Spec:       corev1.PodSpec{ServiceAccountName: "sa-2"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-3", Namespace: "bar", Labels: map[string]string{"app": "foo"}},
+			Spec:       corev1.PodSpec{ServiceAccountName: "sa-1"},
+		},
+	}).AnyTimes()
+	mockKubeController.EXPECT().ListServiceAccounts().Return([]*corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sa-1", Namespace: "tenant"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sa-2", Namespace: "tenant"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sa-1", Namespace: "foo"}},
+	}).AnyTimes()
+
+	namespaceEgress := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-egress", Namespace: "test"},
+		Spec: policyV1alpha1.EgressSpec{
+			Sources: []policyV1alpha1.EgressSourceSpec{{Kind: EgressSourceKindNamespace, Name: "tenant"}},
+			Hosts:   []string{"foo.com"},
+			Ports:   []policyV1alpha1.PortSpec{{Number: 80, Protocol: "http"}},
+		},
+	}
+	podEgress := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-egress", Namespace: "test"},
+		Spec: policyV1alpha1.EgressSpec{
+			Sources: []policyV1alpha1.EgressSourceSpec{
+				{
+					Kind:        EgressSourceKindPod,
+					Namespace:   "foo",
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				},
+			},
+			Hosts: []string{"bar.com"},
+			Ports: []policyV1alpha1.PortSpec{{Number: 80, Protocol: "http"}},
+		},
+	}
+
+	a := assert.New(t)
+	c, err := newClient(mockKubeController, fakePolicyClient.NewSimpleClientset(), nil, nil)
+	a.Nil(err)
+	_ = c.caches.egress.Add(namespaceEgress)
+	_ = c.caches.egress.Add(podEgress)
+
+	testCases := []struct {
+		source           identity.K8sServiceAccount
+		expectedEgresses []*policyV1alpha1.Egress
+	}{
+		{
+			source:           identity.K8sServiceAccount{Name: "sa-2", Namespace: "tenant"},
+			expectedEgresses: []*policyV1alpha1.Egress{namespaceEgress},
+		},
+		{
+			source:           identity.K8sServiceAccount{Name: "sa-1", Namespace: "foo"},
+			expectedEgresses: []*policyV1alpha1.Egress{podEgress},
+		},
+		{
+			// The pod of sa-2 in namespace foo is not selected by the label selector
+			source:           identity.K8sServiceAccount{Name: "sa-2", Namespace: "foo"},
+			expectedEgresses: nil,
+		},
+		{
+			// The pod of sa-1 in namespace bar is not in the namespace of the pod selector
+			source:           identity.K8sServiceAccount{Name: "sa-1", Namespace: "bar"},
+			expectedEgresses: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source.String(), func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expectedEgresses, c.ListEgressPoliciesForSourceIdentity(tc.source))
+		})
+	}
+
+	a.Equal([]identity.K8sServiceAccount{{Name: "sa-1", Namespace: "tenant"}, {Name: "sa-2", Namespace: "tenant"}},
+		c.ListEgressSourceIdentities(namespaceEgress))
+	a.Equal([]identity.K8sServiceAccount{{Name: "sa-1", Namespace: "foo"}}, c.ListEgressSourceIdentities(podEgress))
+}
+
+func TestPodSourceSharingServiceAccount(t *testing.T) {
+	a := assert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().ListPods().Return([]*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "foo", Labels: map[string]string{"app": "foo"}},
+			Spec:       corev1.PodSpec{ServiceAccountName: "sa-1"},
+		},
+		{
+			// Shares the service account of pod-1 without being selected
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-2", Namespace: "foo", Labels: map[string]string{"app": "bar"}},
+			Spec:       corev1.PodSpec{ServiceAccountName: "sa-1"},
+		},
+	}).AnyTimes()
+
+	podEgress := &policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-egress", Namespace: "test"},
+		Spec: policyV1alpha1.EgressSpec{
+			Sources: []policyV1alpha1.EgressSourceSpec{
+				{
+					Kind:        EgressSourceKindPod,
+					Namespace:   "foo",
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				},
+			},
+			Hosts: []string{"bar.com"},
+			Ports: []policyV1alpha1.PortSpec{{Number: 80, Protocol: "http"}},
+		},
+	}
+
+	c, err := newClient(mockKubeController, fakePolicyClient.NewSimpleClientset(), nil, nil)
+	a.Nil(err)
+	_ = c.caches.egress.Add(podEgress)
+
+	// The policy does not apply to the service account shared with an unselected pod
+	a.NotNil(c.ValidateEgressSources(podEgress))
+	a.Empty(c.ListEgressSourceIdentities(podEgress))
+	a.Empty(c.ListEgressPoliciesForSourceIdentity(identity.K8sServiceAccount{Name: "sa-1", Namespace: "foo"}))
+}
+
+func TestIsEgressSourcePodEvent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+
+	c, err := newClient(mockKubeController, fakePolicyClient.NewSimpleClientset(), nil, nil)
+	assert.Nil(t, err)
+	_ = c.caches.egress.Add(&policyV1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-egress", Namespace: "test"},
+		Spec: policyV1alpha1.EgressSpec{
+			Sources: []policyV1alpha1.EgressSourceSpec{
+				{
+					Kind:        EgressSourceKindPod,
+					Namespace:   "foo",
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				},
+			},
+			Hosts: []string{"bar.com"},
+			Ports: []policyV1alpha1.PortSpec{{Number: 80, Protocol: "http"}},
+		},
+	})
+
+	newPod := func(namespace string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace, Labels: labels}}
+	}
+
+	testCases := []struct {
+		name     string
+		msg      events.PubSubMessage
+		expected bool
+	}{
+		{
+			name:     "selected pod added",
+			msg:      events.PubSubMessage{Kind: announcements.PodAdded, NewObj: newPod("foo", map[string]string{"app": "foo"})},
+			expected: true,
+		},
+		{
+			name:     "unselected pod added",
+			msg:      events.PubSubMessage{Kind: announcements.PodAdded, NewObj: newPod("foo", map[string]string{"app": "bar"})},
+			expected: false,
+		},
+		{
+			name:     "pod in another namespace added",
+			msg:      events.PubSubMessage{Kind: announcements.PodAdded, NewObj: newPod("bar", map[string]string{"app": "foo"})},
+			expected: false,
+		},
+		{
+			name:     "selected pod deleted",
+			msg:      events.PubSubMessage{Kind: announcements.PodDeleted, OldObj: newPod("foo", map[string]string{"app": "foo"})},
+			expected: true,
+		},
+		{
+			name: "pod labels updated to be selected",
+			msg: events.PubSubMessage{Kind: announcements.PodUpdated,
+				OldObj: newPod("foo", map[string]string{"app": "bar"}), NewObj: newPod("foo", map[string]string{"app": "foo"})},
+			expected: true,
+		},
+		{
+			name: "labels of an unselected pod updated",
+			msg: events.PubSubMessage{Kind: announcements.PodUpdated,
+				OldObj: newPod("foo", map[string]string{"app": "bar"}), NewObj: newPod("foo", map[string]string{"app": "baz"})},
+			expected: false,
+		},
+		{
+			name: "selected pod updated without label changes",
+			msg: events.PubSubMessage{Kind: announcements.PodUpdated,
+				OldObj: newPod("foo", map[string]string{"app": "foo"}), NewObj: newPod("foo", map[string]string{"app": "foo"})},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, c.isEgressSourcePodEvent(tc.msg))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package policy
 
 import (
+	"strings"
+
 	mapset "github.com/deckarep/golang-set"
 	"github.com/pkg/errors"
 
@@ -29,3 +31,33 @@ func DetectIngressBackendConflicts(x policyv1alpha1.IngressBackend, y policyv1al
 
 	return conflicts
 }
+
+// DetectEgressConflicts detects conflicts between the given Egress resources. Egress policies conflict
+// when they apply to the same source and specify a different protocol for the same port.
+func DetectEgressConflicts(x policyv1alpha1.Egress, y policyv1alpha1.Egress) []error {
+	var conflicts []error // multiple conflicts could exist
+
+	xSources := mapset.NewSet()
+	for _, source := range x.Spec.Sources {
+		xSources.Add(source)
+	}
+	ySources := mapset.NewSet()
+	for _, source := range y.Spec.Sources {
+		ySources.Add(source)
+	}
+	if xSources.Intersect(ySources).Cardinality() == 0 {
+		return nil
+	}
+
+	for _, xPort := range x.Spec.Ports {
+		for _, yPort := range y.Spec.Ports {
+			if xPort.Number == yPort.Number && !strings.EqualFold(xPort.Protocol, yPort.Protocol) {
+				err := errors.Errorf("Port %d specified with protocol %s in %s and protocol %s in %s conflicts",
+					xPort.Number, xPort.Protocol, x.Name, yPort.Protocol, y.Name)
+				conflicts = append(conflicts, err)
+			}
+		}
+	}
+
+	return conflicts
+}
@@ -197,3 +197,50 @@ func TestDetectIngressBackendConflicts(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectEgressConflicts(t *testing.T) {
+	source := policyv1alpha1.EgressSourceSpec{Kind: "ServiceAccount", Name: "client", Namespace: "test"}
+	otherSource := policyv1alpha1.EgressSourceSpec{Kind: "ServiceAccount", Name: "other", Namespace: "test"}
+
+	testCases := []struct {
+		name              string
+		xSources          []policyv1alpha1.EgressSourceSpec
+		xPorts            []policyv1alpha1.PortSpec
+		ySources          []policyv1alpha1.EgressSourceSpec
+		yPorts            []policyv1alpha1.PortSpec
+		conflictsExpected int
+	}{
+		{
+			name:              "same source and port with different protocols",
+			xSources:          []policyv1alpha1.EgressSourceSpec{source},
+			xPorts:            []policyv1alpha1.PortSpec{{Number: 80, Protocol: "http"}, {Number: 443, Protocol: "https"}},
+			ySources:          []policyv1alpha1.EgressSourceSpec{source, otherSource},
+			yPorts:            []policyv1alpha1.PortSpec{{Number: 80, Protocol: "tcp"}, {Number: 443, Protocol: "HTTPS"}},
+			conflictsExpected: 1,
+		},
+		{
+			name:              "different sources",
+			xSources:          []policyv1alpha1.EgressSourceSpec{source},
+			xPorts:            []policyv1alpha1.PortSpec{{Number: 80, Protocol: "http"}},
+			ySources:          []policyv1alpha1.EgressSourceSpec{otherSource},
+			yPorts:            []policyv1alpha1.PortSpec{{Number: 80, Protocol: "tcp"}},
+			conflictsExpected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			x := policyv1alpha1.Egress{
+				ObjectMeta: metav1.ObjectMeta{Name: "egress-1", Namespace: "test"},
+				Spec:       policyv1alpha1.EgressSpec{Sources: tc.xSources, Ports: tc.xPorts},
+			}
+			y := policyv1alpha1.Egress{
+				ObjectMeta: metav1.ObjectMeta{Name: "egress-2", Namespace: "test"},
+				Spec:       policyv1alpha1.EgressSpec{Sources: tc.ySources, Ports: tc.yPorts},
+			}
+			a.Len(DetectEgressConflicts(x, y), tc.conflictsExpected)
+		})
+	}
+}
@@ -50,6 +50,20 @@ func (mr *MockControllerMockRecorder) GetIngressBackendPolicy(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressBackendPolicy", reflect.TypeOf((*MockController)(nil).GetIngressBackendPolicy), arg0)
 }
 
+// ListEgressPolicies mocks base method.
+func (m *MockController) ListEgressPolicies() []*v1alpha1.Egress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEgressPolicies")
+	ret0, _ := ret[0].([]*v1alpha1.Egress)
+	return ret0
+}
+
+// ListEgressPolicies indicates an expected call of ListEgressPolicies.
+func (mr *MockControllerMockRecorder) ListEgressPolicies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEgressPolicies", reflect.TypeOf((*MockController)(nil).ListEgressPolicies))
+}
+
 // ListEgressPoliciesForSourceIdentity mocks base method.
 func (m *MockController) ListEgressPoliciesForSourceIdentity(arg0 identity.K8sServiceAccount) []*v1alpha1.Egress {
 	m.ctrl.T.Helper()
@@ -63,3 +77,45 @@ func (mr *MockControllerMockRecorder) ListEgressPoliciesForSourceIdentity(arg0 i
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEgressPoliciesForSourceIdentity", reflect.TypeOf((*MockController)(nil).ListEgressPoliciesForSourceIdentity), arg0)
 }
+
+// ListEgressSourceIdentities mocks base method.
+func (m *MockController) ListEgressSourceIdentities(arg0 *v1alpha1.Egress) []identity.K8sServiceAccount {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEgressSourceIdentities", arg0)
+	ret0, _ := ret[0].([]identity.K8sServiceAccount)
+	return ret0
+}
+
+// ListEgressSourceIdentities indicates an expected call of ListEgressSourceIdentities.
+func (mr *MockControllerMockRecorder) ListEgressSourceIdentities(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEgressSourceIdentities", reflect.TypeOf((*MockController)(nil).ListEgressSourceIdentities), arg0)
+}
+
+// ListIngressBackendPolicies mocks base method.
+func (m *MockController) ListIngressBackendPolicies() []*v1alpha1.IngressBackend {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngressBackendPolicies")
+	ret0, _ := ret[0].([]*v1alpha1.IngressBackend)
+	return ret0
+}
+
+// ListIngressBackendPolicies indicates an expected call of ListIngressBackendPolicies.
+func (mr *MockControllerMockRecorder) ListIngressBackendPolicies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngressBackendPolicies", reflect.TypeOf((*MockController)(nil).ListIngressBackendPolicies))
+}
+
+// ValidateEgressSources mocks base method.
+func (m *MockController) ValidateEgressSources(arg0 *v1alpha1.Egress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateEgressSources", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateEgressSources indicates an expected call of ValidateEgressSources.
+func (mr *MockControllerMockRecorder) ValidateEgressSources(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateEgressSources", reflect.TypeOf((*MockController)(nil).ValidateEgressSources), arg0)
+}
@@ -40,6 +40,18 @@ type Controller interface {
 	// ListEgressPoliciesForSourceIdentity lists the Egress policies for the given source identity
 	ListEgressPoliciesForSourceIdentity(identity.K8sServiceAccount) []*policyV1alpha1.Egress
 
+	// ListEgressSourceIdentities lists the service identities of the sources the given Egress policy applies to
+	ListEgressSourceIdentities(*policyV1alpha1.Egress) []identity.K8sServiceAccount
+
+	// ValidateEgressSources validates that the sources of the given Egress policy can be applied to the workloads they select
+	ValidateEgressSources(*policyV1alpha1.Egress) error
+
+	// ListEgressPolicies lists the Egress policies in the monitored namespaces
+	ListEgressPolicies() []*policyV1alpha1.Egress
+
+	// ListIngressBackendPolicies lists the IngressBackend policies in the monitored namespaces
+	ListIngressBackendPolicies() []*policyV1alpha1.IngressBackend
+
 	// GetIngressBackendPolicy returns the IngressBackend policy for the given backend MeshService
 	GetIngressBackendPolicy(service.MeshService) *policyV1alpha1.IngressBackend
 }
@@ -0,0 +1,200 @@
+package policy
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+// ValidateIngressBackend validates the specification of the given IngressBackend policy
+func ValidateIngressBackend(ingressBackend *policyv1alpha1.IngressBackend) error {
+	for _, backend := range ingressBackend.Spec.Backends {
+		// Validate port
+		switch strings.ToLower(backend.Port.Protocol) {
+		case constants.ProtocolHTTP:
+			// Valid
+
+		case constants.ProtocolHTTPS:
+			// Valid
+			// If mTLS is enabled, verify there is an AuthenticatedPrincipal specified
+			authenticatedSourceFound := false
+			for _, source := range ingressBackend.Spec.Sources {
+				if source.Kind == policyv1alpha1.KindAuthenticatedPrincipal {
+					authenticatedSourceFound = true
+					break
+				}
+			}
+
+			if backend.TLS.SkipClientCertValidation && !authenticatedSourceFound {
+				return errors.Errorf("HTTPS ingress with client certificate validation enabled must specify at least one 'AuthenticatedPrincipal` source")
+			}
+
+		default:
+			return errors.Errorf("Expected 'port.protocol' to be 'http' or 'https', got: %s", backend.Port.Protocol)
+		}
+	}
+
+	// Validate sources
+	for _, source := range ingressBackend.Spec.Sources {
+		switch source.Kind {
+		// Add validation for source kinds here
+		case policyv1alpha1.KindIPRange:
+			if _, _, err := net.ParseCIDR(source.Name); err != nil {
+				return errors.Errorf("Invalid 'source.Name' value specified for IPRange. Expected CIDR notation 'a.b.c.d/x', got '%s'", source.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateEgress validates the specification of the given Egress policy
+func ValidateEgress(egress *policyv1alpha1.Egress) error {
+	for _, source := range egress.Spec.Sources {
+		if err := validateEgressSource(source); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range egress.Spec.Matches {
+		if m.Kind != "HTTPRouteGroup" {
+			return errors.Errorf("Expected 'Matches.Kind' to be 'HTTPRouteGroup', got: %s", m.Kind)
+		}
+
+		if *m.APIGroup != "specs.smi-spec.io/v1alpha4" {
+			return errors.Errorf("Expected 'Matches.APIGroup' to be 'specs.smi-spec.io/v1alpha4', got: %s", *m.APIGroup)
+		}
+	}
+
+	for _, ipAddress := range egress.Spec.IPAddresses {
+		if _, err := utils.ParseIPRange(ipAddress); err != nil {
+			return errors.Errorf("Invalid 'IPAddresses' value '%s'. Expected an IPv4 or IPv6 address or CIDR range", ipAddress)
+		}
+	}
+
+	hasWildcardHost := false
+	for _, host := range egress.Spec.Hosts {
+		if err := validateEgressHost(host); err != nil {
+			return err
+		}
+		hasWildcardHost = hasWildcardHost || utils.IsWildcardHost(host)
+	}
+
+	for _, port := range egress.Spec.Ports {
+		if hasWildcardHost && (strings.EqualFold(port.Protocol, constants.ProtocolTCP) || strings.EqualFold(port.Protocol, constants.ProtocolTCPServerFirst)) {
+			return errors.Errorf("Wildcard 'Hosts' are not supported for TCP port %d, since they cannot be resolved to IP addresses", port.Number)
+		}
+		if port.TLS == nil {
+			continue
+		}
+		if !strings.EqualFold(port.Protocol, constants.ProtocolHTTP) {
+			return errors.Errorf("TLS origination is only supported for HTTP ports, got protocol %s for port %d", port.Protocol, port.Number)
+		}
+		if port.TLS.CASecretName == "" {
+			return errors.Errorf("Expected 'Ports.TLS.CASecretName' to be set for port %d", port.Number)
+		}
+		if len(egress.Spec.Hosts) == 0 {
+			return errors.Errorf("TLS origination for port %d requires 'Hosts' to be specified", port.Number)
+		}
+		if hasWildcardHost {
+			return errors.Errorf("TLS origination for port %d is not supported with wildcard 'Hosts'", port.Number)
+		}
+	}
+
+	return nil
+}
+
+// ValidateEgressWithEgressGateway validates that the given Egress policy can be enforced by the egress gateway.
+// The egress gateway determines the external destination of a connection from the host requested by the sidecar,
+// so TCP ports, wildcard hosts and policies without hosts cannot be routed through it, and would otherwise bypass it.
+func ValidateEgressWithEgressGateway(egress *policyv1alpha1.Egress) error {
+	if len(egress.Spec.Hosts) == 0 {
+		return errors.New("Egress policies without 'Hosts' are not supported when the egress gateway is enabled")
+	}
+
+	for _, host := range egress.Spec.Hosts {
+		if utils.IsWildcardHost(host) {
+			return errors.Errorf("Wildcard host %s is not supported when the egress gateway is enabled", host)
+		}
+	}
+
+	for _, port := range egress.Spec.Ports {
+		if strings.EqualFold(port.Protocol, constants.ProtocolTCP) || strings.EqualFold(port.Protocol, constants.ProtocolTCPServerFirst) {
+			return errors.Errorf("TCP port %d is not supported when the egress gateway is enabled", port.Number)
+		}
+	}
+
+	return nil
+}
+
+// validateEgressSource validates a source in an Egress policy based on its kind
+func validateEgressSource(source policyv1alpha1.EgressSourceSpec) error {
+	switch source.Kind {
+	case EgressSourceKindSvcAccount:
+		if source.Name == "" || source.Namespace == "" {
+			return errors.Errorf("Expected 'Sources.Name' and 'Sources.Namespace' to be set for source of kind %s", source.Kind)
+		}
+		if source.PodSelector != nil {
+			return errors.Errorf("'Sources.PodSelector' is not applicable to source of kind %s", source.Kind)
+		}
+
+	case EgressSourceKindNamespace:
+		if source.Name == "" {
+			return errors.Errorf("Expected 'Sources.Name' to be set to the namespace for source of kind %s", source.Kind)
+		}
+		if source.Namespace != "" || source.PodSelector != nil {
+			return errors.Errorf("'Sources.Namespace' and 'Sources.PodSelector' are not applicable to source of kind %s", source.Kind)
+		}
+
+	case EgressSourceKindPod:
+		if source.Namespace == "" || source.PodSelector == nil {
+			return errors.Errorf("Expected 'Sources.Namespace' and 'Sources.PodSelector' to be set for source of kind %s", source.Kind)
+		}
+		if source.Name != "" {
+			return errors.Errorf("'Sources.Name' is not applicable to source of kind %s", source.Kind)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(source.PodSelector); err != nil {
+			return errors.Errorf("Invalid 'Sources.PodSelector' for source of kind %s: %s", source.Kind, err)
+		}
+
+	default:
+		return errors.Errorf("Invalid 'Sources.Kind' value '%s'. Expected one of %s, %s, %s", source.Kind,
+			EgressSourceKindSvcAccount, EgressSourceKindNamespace, EgressSourceKindPod)
+	}
+
+	return nil
+}
+
+// validateEgressHost validates a host in an Egress policy. A wildcard is only allowed as the leftmost label
+// of the host, ex. '*.example.com', and must be followed by at least 2 labels so the host does not match
+// every subdomain of a top level domain.
+func validateEgressHost(host string) error {
+	if !strings.Contains(host, "*") {
+		return nil
+	}
+	if !utils.IsWildcardHost(host) {
+		return errors.Errorf("Invalid 'Hosts' value '%s'. A wildcard is only allowed as the leftmost label, ex. '*.example.com'", host)
+	}
+
+	domain := strings.TrimPrefix(host, "*.")
+	if strings.Contains(domain, "*") {
+		return errors.Errorf("Invalid 'Hosts' value '%s'. Only a single wildcard is allowed", host)
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return errors.Errorf("Invalid 'Hosts' value '%s'. A wildcard must be followed by at least 2 labels, ex. '*.example.com'", host)
+	}
+	for _, label := range labels {
+		if label == "" {
+			return errors.Errorf("Invalid 'Hosts' value '%s'. Labels must not be empty", host)
+		}
+	}
+
+	return nil
+}
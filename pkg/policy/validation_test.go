@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+)
+
+func TestValidateEgressHost(t *testing.T) {
+	testCases := []struct {
+		host        string
+		expectedErr bool
+	}{
+		{host: "example.com", expectedErr: false},
+		{host: "*.example.com", expectedErr: false},
+		{host: "*.foo.example.com", expectedErr: false},
+		{host: "*", expectedErr: true},
+		{host: "*.com", expectedErr: true},
+		{host: "*example.com", expectedErr: true},
+		{host: "foo.*.com", expectedErr: true},
+		{host: "*.*.example.com", expectedErr: true},
+		{host: "*.example..com", expectedErr: true},
+		{host: "*.", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			err := validateEgressHost(tc.host)
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestValidateEgressSource(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	invalidSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}},
+	}
+
+	testCases := []struct {
+		name        string
+		source      policyv1alpha1.EgressSourceSpec
+		expectedErr bool
+	}{
+		{
+			name:        "valid ServiceAccount source",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindSvcAccount, Name: "sa", Namespace: "test"},
+			expectedErr: false,
+		},
+		{
+			name:        "ServiceAccount source without namespace",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindSvcAccount, Name: "sa"},
+			expectedErr: true,
+		},
+		{
+			name:        "ServiceAccount source with pod selector",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindSvcAccount, Name: "sa", Namespace: "test", PodSelector: selector},
+			expectedErr: true,
+		},
+		{
+			name:        "valid Namespace source",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindNamespace, Name: "test"},
+			expectedErr: false,
+		},
+		{
+			name:        "Namespace source without name",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindNamespace},
+			expectedErr: true,
+		},
+		{
+			name:        "Namespace source with namespace",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindNamespace, Name: "test", Namespace: "test"},
+			expectedErr: true,
+		},
+		{
+			name:        "valid Pod source",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindPod, Namespace: "test", PodSelector: selector},
+			expectedErr: false,
+		},
+		{
+			name:        "Pod source without pod selector",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindPod, Namespace: "test"},
+			expectedErr: true,
+		},
+		{
+			name:        "Pod source with name",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindPod, Name: "pod", Namespace: "test", PodSelector: selector},
+			expectedErr: true,
+		},
+		{
+			name:        "Pod source with invalid pod selector",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: EgressSourceKindPod, Namespace: "test", PodSelector: invalidSelector},
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported kind",
+			source:      policyv1alpha1.EgressSourceSpec{Kind: "Deployment", Name: "foo", Namespace: "test"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			err := validateEgressSource(tc.source)
+			assert.Equal(tc.expectedErr, err != nil, err)
+		})
+	}
+}
+
+func TestValidateEgressWithEgressGateway(t *testing.T) {
+	testCases := []struct {
+		name        string
+		spec        policyv1alpha1.EgressSpec
+		expectedErr bool
+	}{
+		{
+			name: "HTTP and HTTPS ports with hosts",
+			spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"example.com"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 80, Protocol: "http"}, {Number: 443, Protocol: "https"}},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "HTTPS port without hosts",
+			spec: policyv1alpha1.EgressSpec{
+				IPAddresses: []string{"10.0.0.0/24"},
+				Ports:       []policyv1alpha1.PortSpec{{Number: 443, Protocol: "https"}},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "TCP port with hosts",
+			spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"example.com"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 5432, Protocol: "tcp"}},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "server-first TCP port with hosts",
+			spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"example.com"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 3306, Protocol: "tcp-server-first"}},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "HTTP and HTTPS ports with a wildcard host",
+			spec: policyv1alpha1.EgressSpec{
+				Hosts: []string{"example.com", "*.example.com"},
+				Ports: []policyv1alpha1.PortSpec{{Number: 80, Protocol: "http"}, {Number: 443, Protocol: "https"}},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			err := ValidateEgressWithEgressGateway(&policyv1alpha1.Egress{Spec: tc.spec})
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package policystatus
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/policy"
+)
+
+// reconcileEgress reconciles the status of the given Egress, given all the Egress policies, and returns a boolean
+// indicating if the resulting configuration is pending acknowledgement by its proxies
+func (r *Reconciler) reconcileEgress(egress *policyv1alpha1.Egress, egresses []*policyv1alpha1.Egress) bool {
+	conditions := policyConditions{
+		accepted:     getAcceptedCondition(egress, r.validateEgress(egress)),
+		resolvedRefs: r.getEgressResolvedRefsCondition(egress),
+		conflicted:   getEgressConflictedCondition(egress, egresses),
+	}
+
+	var programmed metav1.Condition
+	if conditions.isCommitted() {
+		programmed = r.getProgrammedCondition(egress, egress.Status.Conditions, r.listEgressProxies(egress))
+	} else {
+		programmed = newCondition(egress, policyv1alpha1.ConditionProgrammed, metav1.ConditionFalse, policyv1alpha1.ReasonInvalid,
+			"The policy cannot be programmed until it is accepted, its references are resolved and it does not conflict with another policy")
+	}
+
+	status := policyv1alpha1.EgressStatus{
+		Conditions: setConditions(egress.Status.Conditions, conditions.accepted, conditions.resolvedRefs, conditions.conflicted, programmed),
+	}
+	status.CurrentStatus, status.Reason = conditions.getCurrentStatus()
+	pending := conditions.isCommitted() && programmed.Status != metav1.ConditionTrue
+	if equality.Semantic.DeepEqual(egress.Status, status) {
+		return pending
+	}
+
+	// Note: The original pointer returned by cache.Store must not be modified for thread safety.
+	egressWithStatus := egress.DeepCopy()
+	egressWithStatus.Status = status
+	if _, err := r.kubeController.UpdateStatus(egressWithStatus); err != nil {
+		log.Error().Err(err).Msgf("Error updating status for Egress %s/%s", egress.Namespace, egress.Name)
+	}
+	return pending
+}
+
+// validateEgress validates the given Egress, that its sources can be applied to the workloads they select, and that
+// it can be enforced by the egress gateway when it is enabled
+func (r *Reconciler) validateEgress(egress *policyv1alpha1.Egress) error {
+	if err := policy.ValidateEgress(egress); err != nil {
+		return err
+	}
+
+	if err := r.policyController.ValidateEgressSources(egress); err != nil {
+		return err
+	}
+
+	if featureFlags := r.configurator.GetFeatureFlags(); featureFlags.EnableEgressPolicy && featureFlags.EnableEgressGateway {
+		return policy.ValidateEgressWithEgressGateway(egress)
+	}
+	return nil
+}
+
+// getEgressResolvedRefsCondition returns the ResolvedRefs condition of the given Egress, checking that its source
+// service accounts and the secrets referenced by its TLS configuration exist
+func (r *Reconciler) getEgressResolvedRefsCondition(egress *policyv1alpha1.Egress) metav1.Condition {
+	serviceAccounts := make(map[identity.K8sServiceAccount]bool)
+	for _, sa := range r.kubeController.ListServiceAccounts() {
+		serviceAccounts[identity.K8sServiceAccount{Name: sa.Name, Namespace: sa.Namespace}] = true
+	}
+
+	for _, source := range egress.Spec.Sources {
+		switch source.Kind {
+		case policy.EgressSourceKindSvcAccount:
+			sourceSA := identity.K8sServiceAccount{Name: source.Name, Namespace: source.Namespace}
+			if !serviceAccounts[sourceSA] {
+				return newCondition(egress, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionFalse, policyv1alpha1.ReasonSourceNotFound,
+					fmt.Sprintf("Source service account %s not found", sourceSA))
+			}
+
+		case policy.EgressSourceKindNamespace, policy.EgressSourceKindPod:
+			namespace := source.Namespace
+			if source.Kind == policy.EgressSourceKindNamespace {
+				namespace = source.Name
+			}
+			if !r.kubeController.IsMonitoredNamespace(namespace) {
+				return newCondition(egress, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionFalse, policyv1alpha1.ReasonSourceNotFound,
+					fmt.Sprintf("Source namespace %s is not monitored by the mesh", namespace))
+			}
+		}
+	}
+
+	for _, port := range egress.Spec.Ports {
+		if port.TLS == nil {
+			continue
+		}
+		for _, secretName := range []string{port.TLS.CASecretName, port.TLS.ClientCertSecretName} {
+			if secretName == "" {
+				continue
+			}
+			if r.kubeController.GetSecret(secretName, egress.Namespace) == nil {
+				return newCondition(egress, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionFalse, policyv1alpha1.ReasonSecretNotFound,
+					fmt.Sprintf("Secret %s/%s referenced by port %d not found", egress.Namespace, secretName, port.Number))
+			}
+		}
+	}
+
+	return newCondition(egress, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionTrue, policyv1alpha1.ReasonResolved,
+		"All references are resolved")
+}
+
+// getEgressConflictedCondition returns the Conflicted condition of the given Egress, given all the Egress policies
+func getEgressConflictedCondition(egress *policyv1alpha1.Egress, egresses []*policyv1alpha1.Egress) metav1.Condition {
+	var conflicts []string
+	for _, other := range egresses {
+		if other.UID == egress.UID {
+			continue
+		}
+		for _, err := range policy.DetectEgressConflicts(*egress, *other) {
+			conflicts = append(conflicts, err.Error())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return newCondition(egress, policyv1alpha1.ConditionConflicted, metav1.ConditionTrue, policyv1alpha1.ReasonConflicts,
+			strings.Join(conflicts, "; "))
+	}
+	return newCondition(egress, policyv1alpha1.ConditionConflicted, metav1.ConditionFalse, policyv1alpha1.ReasonNoConflicts,
+		"The policy does not conflict with another policy")
+}
+
+// listEgressProxies lists the connected proxies of the sources of the given Egress
+func (r *Reconciler) listEgressProxies(egress *policyv1alpha1.Egress) []*envoy.Proxy {
+	sources := make(map[identity.K8sServiceAccount]bool)
+	for _, source := range r.policyController.ListEgressSourceIdentities(egress) {
+		sources[source] = true
+	}
+
+	var proxies []*envoy.Proxy
+	for cn, proxy := range r.proxyLister.ListConnectedProxies() {
+		proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(cn)
+		if err != nil {
+			log.Error().Err(err).Str("proxy", proxy.String()).Msg("Error getting the identity of proxy")
+			continue
+		}
+		if sources[proxyIdentity.ToK8sServiceAccount()] {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
@@ -0,0 +1,183 @@
+package policystatus
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// reconcileIngressBackend reconciles the status of the given IngressBackend, given all the IngressBackend policies,
+// and returns a boolean indicating if the resulting configuration is pending acknowledgement by its proxies
+func (r *Reconciler) reconcileIngressBackend(ingressBackend *policyv1alpha1.IngressBackend, ingressBackends []*policyv1alpha1.IngressBackend) bool {
+	conditions := policyConditions{
+		accepted:     getAcceptedCondition(ingressBackend, policy.ValidateIngressBackend(ingressBackend)),
+		resolvedRefs: r.getIngressBackendResolvedRefsCondition(ingressBackend),
+		conflicted:   getIngressBackendConflictedCondition(ingressBackend, ingressBackends),
+	}
+
+	var programmed metav1.Condition
+	if conditions.isCommitted() {
+		programmed = r.getProgrammedCondition(ingressBackend, ingressBackend.Status.Conditions, r.listIngressBackendProxies(ingressBackend))
+	} else {
+		programmed = newCondition(ingressBackend, policyv1alpha1.ConditionProgrammed, metav1.ConditionFalse, policyv1alpha1.ReasonInvalid,
+			"The policy cannot be programmed until it is accepted, its references are resolved and it does not conflict with another policy")
+	}
+
+	status := policyv1alpha1.IngressBackendStatus{
+		Conditions: setConditions(ingressBackend.Status.Conditions, conditions.accepted, conditions.resolvedRefs, conditions.conflicted, programmed),
+	}
+	status.CurrentStatus, status.Reason = conditions.getCurrentStatus()
+	pending := conditions.isCommitted() && programmed.Status != metav1.ConditionTrue
+	if equality.Semantic.DeepEqual(ingressBackend.Status, status) {
+		return pending
+	}
+
+	// Note: The original pointer returned by cache.Store must not be modified for thread safety.
+	ingressBackendWithStatus := ingressBackend.DeepCopy()
+	ingressBackendWithStatus.Status = status
+	if _, err := r.kubeController.UpdateStatus(ingressBackendWithStatus); err != nil {
+		log.Error().Err(err).Msgf("Error updating status for IngressBackend %s/%s", ingressBackend.Namespace, ingressBackend.Name)
+	}
+	return pending
+}
+
+// getAcceptedCondition returns the Accepted condition of the given policy given the error validating it
+func getAcceptedCondition(obj metav1.Object, validationErr error) metav1.Condition {
+	if validationErr != nil {
+		return newCondition(obj, policyv1alpha1.ConditionAccepted, metav1.ConditionFalse, policyv1alpha1.ReasonInvalid, validationErr.Error())
+	}
+	return newCondition(obj, policyv1alpha1.ConditionAccepted, metav1.ConditionTrue, policyv1alpha1.ReasonValid, "The policy is valid")
+}
+
+// getIngressBackendResolvedRefsCondition returns the ResolvedRefs condition of the given IngressBackend, checking
+// that its backends exist and serve the specified ports, and that its source services have endpoints
+func (r *Reconciler) getIngressBackendResolvedRefsCondition(ingressBackend *policyv1alpha1.IngressBackend) metav1.Condition {
+	for _, backend := range ingressBackend.Spec.Backends {
+		backendSvc := service.MeshService{Name: backend.Name, Namespace: ingressBackend.Namespace}
+		k8sSvc := r.kubeController.GetService(backendSvc)
+		if k8sSvc == nil {
+			return newCondition(ingressBackend, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionFalse, policyv1alpha1.ReasonBackendNotFound,
+				fmt.Sprintf("Backend service %s not found", backendSvc))
+		}
+
+		endpoints, _ := r.kubeController.GetEndpoints(backendSvc)
+		if !isServiceTargetPort(k8sSvc, endpoints, backend.Port.Number) {
+			return newCondition(ingressBackend, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionFalse, policyv1alpha1.ReasonInvalidPort,
+				fmt.Sprintf("Port %d is not a target port of backend service %s", backend.Port.Number, backendSvc))
+		}
+	}
+
+	for _, source := range ingressBackend.Spec.Sources {
+		if source.Kind != policyv1alpha1.KindService {
+			continue
+		}
+		sourceSvc := service.MeshService{Name: source.Name, Namespace: source.Namespace}
+		endpoints, err := r.kubeController.GetEndpoints(sourceSvc)
+		if err != nil || !hasEndpointAddresses(endpoints) {
+			return newCondition(ingressBackend, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionFalse, policyv1alpha1.ReasonSourceNotFound,
+				fmt.Sprintf("Endpoints not found for source service %s", sourceSvc))
+		}
+	}
+
+	return newCondition(ingressBackend, policyv1alpha1.ConditionResolvedRefs, metav1.ConditionTrue, policyv1alpha1.ReasonResolved,
+		"All references are resolved")
+}
+
+// getIngressBackendConflictedCondition returns the Conflicted condition of the given IngressBackend, given all the
+// IngressBackend policies
+func getIngressBackendConflictedCondition(ingressBackend *policyv1alpha1.IngressBackend, ingressBackends []*policyv1alpha1.IngressBackend) metav1.Condition {
+	var conflicts []string
+	for _, other := range ingressBackends {
+		if other.UID == ingressBackend.UID || other.Namespace != ingressBackend.Namespace {
+			continue
+		}
+		for _, err := range policy.DetectIngressBackendConflicts(*ingressBackend, *other) {
+			conflicts = append(conflicts, err.Error())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return newCondition(ingressBackend, policyv1alpha1.ConditionConflicted, metav1.ConditionTrue, policyv1alpha1.ReasonConflicts,
+			strings.Join(conflicts, "; "))
+	}
+	return newCondition(ingressBackend, policyv1alpha1.ConditionConflicted, metav1.ConditionFalse, policyv1alpha1.ReasonNoConflicts,
+		"The policy does not conflict with another policy")
+}
+
+// listIngressBackendProxies lists the connected proxies of the backends of the given IngressBackend
+func (r *Reconciler) listIngressBackendProxies(ingressBackend *policyv1alpha1.IngressBackend) []*envoy.Proxy {
+	backendNames := make(map[string]bool)
+	for _, backend := range ingressBackend.Spec.Backends {
+		backendNames[backend.Name] = true
+	}
+
+	var proxies []*envoy.Proxy
+	for _, proxy := range r.proxyLister.ListConnectedProxies() {
+		services, err := r.proxyLister.ListProxyServices(proxy)
+		if err != nil {
+			log.Error().Err(err).Str("proxy", proxy.String()).Msg("Error listing services for proxy")
+			continue
+		}
+		for _, svc := range services {
+			if svc.Namespace == ingressBackend.Namespace && backendNames[svc.Name] {
+				proxies = append(proxies, proxy)
+				break
+			}
+		}
+	}
+
+	return proxies
+}
+
+// isServiceTargetPort returns true if the given port is a target port of the given service
+func isServiceTargetPort(svc *corev1.Service, endpoints *corev1.Endpoints, port int) bool {
+	hasNamedTargetPort := false
+	for _, servicePort := range svc.Spec.Ports {
+		switch {
+		case servicePort.TargetPort.Type == intstr.String:
+			hasNamedTargetPort = true
+		case servicePort.TargetPort.IntValue() == 0:
+			// The target port defaults to the port
+			if int(servicePort.Port) == port {
+				return true
+			}
+		case servicePort.TargetPort.IntValue() == port:
+			return true
+		}
+	}
+
+	// Named target ports are resolved using the endpoints
+	if hasNamedTargetPort && endpoints != nil {
+		for _, subset := range endpoints.Subsets {
+			for _, endpointPort := range subset.Ports {
+				if int(endpointPort.Port) == port {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// hasEndpointAddresses returns true if the given endpoints have at least one address
+func hasEndpointAddresses(endpoints *corev1.Endpoints) bool {
+	if endpoints == nil {
+		return false
+	}
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
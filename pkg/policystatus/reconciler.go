@@ -0,0 +1,268 @@
+package policystatus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// NewReconciler returns a new Reconciler for the status of the resources in the policy.openservicemesh.io API group
+func NewReconciler(kubeClient kubernetes.Interface, kubeController k8s.Controller, policyController policy.Controller, proxyLister proxyLister, cfg configurator.Configurator, msgBroker *messaging.Broker) *Reconciler {
+	return &Reconciler{
+		kubeClient:          kubeClient,
+		kubeController:      kubeController,
+		policyController:    policyController,
+		proxyLister:         proxyLister,
+		configurator:        cfg,
+		msgBroker:           msgBroker,
+		observedGenerations: make(map[types.UID]observedGeneration),
+	}
+}
+
+// reconcileTriggers are the events that can change the status of the policies
+var reconcileTriggers = []announcements.Kind{
+	announcements.IngressBackendAdded, announcements.IngressBackendUpdated, announcements.IngressBackendDeleted,
+	announcements.EgressAdded, announcements.EgressUpdated, announcements.EgressDeleted,
+	announcements.ServiceAdded, announcements.ServiceUpdated, announcements.ServiceDeleted,
+	announcements.EndpointAdded, announcements.EndpointUpdated, announcements.EndpointDeleted,
+	announcements.ServiceAccountAdded, announcements.ServiceAccountUpdated, announcements.ServiceAccountDeleted,
+	announcements.PodAdded, announcements.PodUpdated, announcements.PodDeleted,
+	announcements.SecretAdded, announcements.SecretUpdated, announcements.SecretDeleted,
+	announcements.NamespaceAdded, announcements.NamespaceUpdated, announcements.NamespaceDeleted,
+	announcements.MeshConfigUpdated,
+}
+
+// Run reconciles the status of the policies when the resources they depend on change, until the stop channel is
+// closed. The status is also reconciled periodically while the configuration resulting from a policy is pending
+// acknowledgement by its proxies. Only one controller replica must run the reconciler at a time, see
+// k8s.RunWithLeaderElection. The acknowledgements of the proxies are only known to the replica they are connected
+// to, so the Programmed condition is Unknown while more than one controller replica runs.
+func (r *Reconciler) Run(stop <-chan struct{}) {
+	var topics []string
+	for _, kind := range reconcileTriggers {
+		topics = append(topics, kind.String())
+	}
+	kubePubSub := r.msgBroker.GetKubeEventPubSub()
+	triggerChan := kubePubSub.Sub(topics...)
+	defer r.msgBroker.Unsub(kubePubSub, triggerChan)
+
+	// The status is reconciled upon start
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	next := time.Now()
+
+	// schedule schedules the next reconciliation after the given delay, unless it is already scheduled sooner
+	schedule := func(delay time.Duration) {
+		at := time.Now().Add(delay)
+		if !next.IsZero() && !next.After(at) {
+			return
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(delay)
+		next = at
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case <-triggerChan:
+			schedule(reconcileDelay)
+
+		case <-timer.C:
+			next = time.Time{}
+			if pending := r.reconcile(); pending {
+				schedule(pendingRetryInterval)
+			}
+		}
+	}
+}
+
+// reconcile reconciles the status of every policy, and returns a boolean indicating if the configuration resulting
+// from a policy is pending acknowledgement by its proxies
+func (r *Reconciler) reconcile() bool {
+	seen := make(map[types.UID]bool)
+	pending := false
+	r.proxyServices = make(map[*envoy.Proxy][]service.MeshService)
+	r.multipleReplicas = r.hasMultipleControllerReplicas()
+
+	ingressBackends := r.policyController.ListIngressBackendPolicies()
+	for _, ingressBackend := range ingressBackends {
+		seen[ingressBackend.UID] = true
+		if r.reconcileIngressBackend(ingressBackend, ingressBackends) {
+			pending = true
+		}
+	}
+
+	egresses := r.policyController.ListEgressPolicies()
+	for _, egress := range egresses {
+		seen[egress.UID] = true
+		if r.reconcileEgress(egress, egresses) {
+			pending = true
+		}
+	}
+
+	// Forget the policies that no longer exist
+	for uid := range r.observedGenerations {
+		if !seen[uid] {
+			delete(r.observedGenerations, uid)
+		}
+	}
+
+	return pending
+}
+
+// hasMultipleControllerReplicas returns a boolean indicating if more than one replica of the controller runs, in which
+// case the proxies are not all connected to the replica running the reconciler. Multiple replicas are assumed when the
+// controller Deployment cannot be fetched.
+func (r *Reconciler) hasMultipleControllerReplicas() bool {
+	osmNamespace := r.configurator.GetOSMNamespace()
+	deployment, err := r.kubeClient.AppsV1().Deployments(osmNamespace).Get(context.Background(), constants.OSMControllerName, metav1.GetOptions{})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error fetching Deployment %s/%s, assuming multiple controller replicas", osmNamespace, constants.OSMControllerName)
+		return true
+	}
+	return deployment.Status.Replicas > 1
+}
+
+// listProxyServices lists the services the given proxy belongs to, once per reconciliation
+func (r *Reconciler) listProxyServices(proxy *envoy.Proxy) ([]service.MeshService, error) {
+	if services, ok := r.proxyServices[proxy]; ok {
+		return services, nil
+	}
+	services, err := r.proxyLister.ListProxyServices(proxy)
+	if err != nil {
+		return nil, err
+	}
+	if r.proxyServices != nil {
+		r.proxyServices[proxy] = services
+	}
+	return services, nil
+}
+
+// getObservedAt returns the time the current generation of the given policy was first observed
+func (r *Reconciler) getObservedAt(obj metav1.Object) time.Time {
+	observed, ok := r.observedGenerations[obj.GetUID()]
+	if !ok || observed.generation != obj.GetGeneration() {
+		observed = observedGeneration{
+			generation: obj.GetGeneration(),
+			observedAt: time.Now(),
+		}
+		r.observedGenerations[obj.GetUID()] = observed
+	}
+	return observed.observedAt
+}
+
+// getProgrammedCondition returns the Programmed condition of the given policy applying to the given proxies.
+// Once the configuration resulting from a generation of the policy has been programmed, the condition does not
+// change until the generation changes.
+func (r *Reconciler) getProgrammedCondition(obj metav1.Object, conditions []metav1.Condition, proxies []*envoy.Proxy) metav1.Condition {
+	observedAt := r.getObservedAt(obj)
+
+	existing := meta.FindStatusCondition(conditions, policyv1alpha1.ConditionProgrammed)
+	if existing != nil && existing.Status == metav1.ConditionTrue && existing.ObservedGeneration == obj.GetGeneration() {
+		return *existing
+	}
+
+	if r.multipleReplicas {
+		return newCondition(obj, policyv1alpha1.ConditionProgrammed, metav1.ConditionUnknown, policyv1alpha1.ReasonMultipleReplicas,
+			"More than one controller replica is running, the acknowledgements of the proxies connected to the other replicas are unknown")
+	}
+
+	if len(proxies) == 0 {
+		return newCondition(obj, policyv1alpha1.ConditionProgrammed, metav1.ConditionFalse, policyv1alpha1.ReasonNoProxies,
+			"No connected proxy is subject to the policy")
+	}
+
+	pending := 0
+	for _, proxy := range proxies {
+		if !proxy.GetLastSentTime().After(observedAt) || !proxy.IsConfigAcknowledged() {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return newCondition(obj, policyv1alpha1.ConditionProgrammed, metav1.ConditionFalse, policyv1alpha1.ReasonPending,
+			fmt.Sprintf("%d of %d proxies have not acknowledged the configuration", pending, len(proxies)))
+	}
+
+	return newCondition(obj, policyv1alpha1.ConditionProgrammed, metav1.ConditionTrue, policyv1alpha1.ReasonProgrammed,
+		fmt.Sprintf("%d proxies have acknowledged the configuration", len(proxies)))
+}
+
+// newCondition returns a new condition for the current generation of the given policy
+func newCondition(obj metav1.Object, conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// policyConditions is the type used to represent the conditions of a policy
+type policyConditions struct {
+	accepted     metav1.Condition
+	resolvedRefs metav1.Condition
+	conflicted   metav1.Condition
+}
+
+// isCommitted returns true if the policy is valid, its references are resolved and it does not conflict with
+// another policy
+func (c policyConditions) isCommitted() bool {
+	return c.accepted.Status == metav1.ConditionTrue && c.resolvedRefs.Status == metav1.ConditionTrue &&
+		c.conflicted.Status == metav1.ConditionFalse
+}
+
+// getCurrentStatus returns the CurrentStatus and Reason fields summarizing the conditions
+func (c policyConditions) getCurrentStatus() (string, string) {
+	if c.isCommitted() {
+		return policyv1alpha1.StatusCommitted, committedReason
+	}
+
+	var reasons []string
+	for _, condition := range []metav1.Condition{c.accepted, c.resolvedRefs} {
+		if condition.Status != metav1.ConditionTrue {
+			reasons = append(reasons, condition.Message)
+		}
+	}
+	if c.conflicted.Status != metav1.ConditionFalse {
+		reasons = append(reasons, c.conflicted.Message)
+	}
+	return policyv1alpha1.StatusError, strings.Join(reasons, "; ")
+}
+
+// setConditions returns a copy of the given conditions updated with the given conditions. The last transition
+// time of a condition only changes when its status changes.
+func setConditions(existing []metav1.Condition, conditions ...metav1.Condition) []metav1.Condition {
+	updated := make([]metav1.Condition, len(existing))
+	for i := range existing {
+		existing[i].DeepCopyInto(&updated[i])
+	}
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&updated, condition)
+	}
+	return updated
+}
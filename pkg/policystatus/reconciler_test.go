@@ -0,0 +1,568 @@
+package policystatus
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+type fakeProxyLister struct {
+	proxies  map[certificate.CommonName]*envoy.Proxy
+	services map[*envoy.Proxy][]service.MeshService
+}
+
+func (f fakeProxyLister) ListConnectedProxies() map[certificate.CommonName]*envoy.Proxy {
+	return f.proxies
+}
+
+func (f fakeProxyLister) ListProxyServices(p *envoy.Proxy) ([]service.MeshService, error) {
+	return f.services[p], nil
+}
+
+func newTestProxy(t *testing.T, serviceAccount, namespace string) (certificate.CommonName, *envoy.Proxy) {
+	cn := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, serviceAccount, namespace)
+	proxy, err := envoy.NewProxy(cn, "", &net.IPAddr{IP: net.ParseIP("10.0.0.1")})
+	tassert.Nil(t, err)
+	return cn, proxy
+}
+
+func newTestIngressBackend(port int, sourceName string) *policyv1alpha1.IngressBackend {
+	return &policyv1alpha1.IngressBackend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ingress-backend",
+			Namespace:  "test",
+			UID:        types.UID("ingress-backend-uid"),
+			Generation: 1,
+		},
+		Spec: policyv1alpha1.IngressBackendSpec{
+			Backends: []policyv1alpha1.BackendSpec{
+				{
+					Name: "backend",
+					Port: policyv1alpha1.PortSpec{Number: port, Protocol: "http"},
+				},
+			},
+			Sources: []policyv1alpha1.IngressSourceSpec{
+				{Kind: policyv1alpha1.KindService, Name: sourceName, Namespace: "ingress"},
+			},
+		},
+	}
+}
+
+func TestReconcileIngressBackend(t *testing.T) {
+	backendSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "test"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(8080)}},
+		},
+	}
+	sourceEndpoints := &corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}}}},
+	}
+
+	testCases := []struct {
+		name                  string
+		ingressBackend        *policyv1alpha1.IngressBackend
+		others                []*policyv1alpha1.IngressBackend
+		backendSvc            *corev1.Service
+		proxyAcked            bool
+		expectedCurrentStatus string
+		expectedConditions    map[string]string // condition type -> reason
+	}{
+		{
+			name:                  "backend service not found",
+			ingressBackend:        newTestIngressBackend(8080, "ingress-svc"),
+			backendSvc:            nil,
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionAccepted:     policyv1alpha1.ReasonValid,
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonBackendNotFound,
+				policyv1alpha1.ConditionConflicted:   policyv1alpha1.ReasonNoConflicts,
+				policyv1alpha1.ConditionProgrammed:   policyv1alpha1.ReasonInvalid,
+			},
+		},
+		{
+			name:                  "backend port is not a target port",
+			ingressBackend:        newTestIngressBackend(9090, "ingress-svc"),
+			backendSvc:            backendSvc,
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonInvalidPort,
+			},
+		},
+		{
+			name:                  "source service without endpoints",
+			ingressBackend:        newTestIngressBackend(8080, "unknown"),
+			backendSvc:            backendSvc,
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonSourceNotFound,
+			},
+		},
+		{
+			name:           "conflicting policies",
+			ingressBackend: newTestIngressBackend(8080, "ingress-svc"),
+			others: func() []*policyv1alpha1.IngressBackend {
+				other := newTestIngressBackend(8080, "ingress-svc")
+				other.Name = "other"
+				other.UID = types.UID("other-uid")
+				return []*policyv1alpha1.IngressBackend{other}
+			}(),
+			backendSvc:            backendSvc,
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonResolved,
+				policyv1alpha1.ConditionConflicted:   policyv1alpha1.ReasonConflicts,
+				policyv1alpha1.ConditionProgrammed:   policyv1alpha1.ReasonInvalid,
+			},
+		},
+		{
+			name:                  "configuration pending acknowledgement",
+			ingressBackend:        newTestIngressBackend(8080, "ingress-svc"),
+			backendSvc:            backendSvc,
+			proxyAcked:            false,
+			expectedCurrentStatus: policyv1alpha1.StatusCommitted,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionAccepted:     policyv1alpha1.ReasonValid,
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonResolved,
+				policyv1alpha1.ConditionConflicted:   policyv1alpha1.ReasonNoConflicts,
+				policyv1alpha1.ConditionProgrammed:   policyv1alpha1.ReasonPending,
+			},
+		},
+		{
+			name:                  "configuration programmed",
+			ingressBackend:        newTestIngressBackend(8080, "ingress-svc"),
+			backendSvc:            backendSvc,
+			proxyAcked:            true,
+			expectedCurrentStatus: policyv1alpha1.StatusCommitted,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionProgrammed: policyv1alpha1.ReasonProgrammed,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			mockKubeController.EXPECT().GetService(service.MeshService{Name: "backend", Namespace: "test"}).Return(tc.backendSvc).AnyTimes()
+			mockKubeController.EXPECT().GetEndpoints(service.MeshService{Name: "backend", Namespace: "test"}).Return(nil, nil).AnyTimes()
+			mockKubeController.EXPECT().GetEndpoints(service.MeshService{Name: "ingress-svc", Namespace: "ingress"}).Return(sourceEndpoints, nil).AnyTimes()
+			mockKubeController.EXPECT().GetEndpoints(service.MeshService{Name: "unknown", Namespace: "ingress"}).Return(nil, nil).AnyTimes()
+
+			var updated *policyv1alpha1.IngressBackend
+			mockKubeController.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(obj interface{}) (metav1.Object, error) {
+				updated = obj.(*policyv1alpha1.IngressBackend)
+				return updated, nil
+			}).Times(1)
+
+			cn, proxy := newTestProxy(t, "backend", "test")
+			lister := fakeProxyLister{
+				proxies:  map[certificate.CommonName]*envoy.Proxy{cn: proxy},
+				services: map[*envoy.Proxy][]service.MeshService{proxy: {{Name: "backend", Namespace: "test"}}},
+			}
+
+			r := NewReconciler(nil, mockKubeController, nil, lister, nil, nil)
+			// Observe the policy before the configuration is sent to the proxy
+			r.getObservedAt(tc.ingressBackend)
+			time.Sleep(time.Millisecond)
+			proxy.IncrementLastSentVersion(envoy.TypeLDS)
+			if tc.proxyAcked {
+				proxy.SetLastAppliedVersion(envoy.TypeLDS, proxy.GetLastSentVersion(envoy.TypeLDS))
+			}
+
+			r.reconcileIngressBackend(tc.ingressBackend, append([]*policyv1alpha1.IngressBackend{tc.ingressBackend}, tc.others...))
+
+			a.NotNil(updated)
+			a.Equal(tc.expectedCurrentStatus, updated.Status.CurrentStatus)
+			for conditionType, reason := range tc.expectedConditions {
+				condition := meta.FindStatusCondition(updated.Status.Conditions, conditionType)
+				a.NotNil(condition, conditionType)
+				a.Equal(reason, condition.Reason, conditionType)
+				a.Equal(tc.ingressBackend.Generation, condition.ObservedGeneration)
+			}
+			// The policy in the cache must not be modified
+			a.Empty(tc.ingressBackend.Status.Conditions)
+		})
+	}
+}
+
+func TestReconcileIngressBackendUnchangedStatus(t *testing.T) {
+	a := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockKubeController := k8s.NewMockController(mockCtrl)
+
+	ingressBackend := newTestIngressBackend(8080, "ingress-svc")
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+
+	var updated *policyv1alpha1.IngressBackend
+	mockKubeController.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(obj interface{}) (metav1.Object, error) {
+		updated = obj.(*policyv1alpha1.IngressBackend)
+		return updated, nil
+	}).Times(1)
+
+	r := NewReconciler(nil, mockKubeController, nil, fakeProxyLister{}, nil, nil)
+	r.reconcileIngressBackend(ingressBackend, []*policyv1alpha1.IngressBackend{ingressBackend})
+	a.NotNil(updated)
+
+	// Reconciling the updated policy must not update its status again
+	r.reconcileIngressBackend(updated, []*policyv1alpha1.IngressBackend{updated})
+}
+
+func TestReconcileEgress(t *testing.T) {
+	newEgress := func(sourceName string, caSecretName string) *policyv1alpha1.Egress {
+		return &policyv1alpha1.Egress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "egress",
+				Namespace:  "test",
+				UID:        types.UID("egress-uid"),
+				Generation: 2,
+			},
+			Spec: policyv1alpha1.EgressSpec{
+				Sources: []policyv1alpha1.EgressSourceSpec{
+					{Kind: policy.EgressSourceKindSvcAccount, Name: sourceName, Namespace: "test"},
+				},
+				Hosts: []string{"foo.com"},
+				Ports: []policyv1alpha1.PortSpec{
+					{Number: 80, Protocol: "http", TLS: &policyv1alpha1.EgressTLSSpec{CASecretName: caSecretName}},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                  string
+		egress                *policyv1alpha1.Egress
+		sourcesErr            error
+		enableEgressGateway   bool
+		expectedCurrentStatus string
+		expectedConditions    map[string]string // condition type -> reason
+	}{
+		{
+			name:                  "source service account not found",
+			egress:                newEgress("unknown", "ca"),
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonSourceNotFound,
+				policyv1alpha1.ConditionProgrammed:   policyv1alpha1.ReasonInvalid,
+			},
+		},
+		{
+			name: "source namespace not monitored",
+			egress: func() *policyv1alpha1.Egress {
+				egress := newEgress("client", "ca")
+				egress.Spec.Sources = []policyv1alpha1.EgressSourceSpec{{Kind: policy.EgressSourceKindNamespace, Name: "unmonitored"}}
+				return egress
+			}(),
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonSourceNotFound,
+			},
+		},
+		{
+			name: "pod selector source in monitored namespace",
+			egress: func() *policyv1alpha1.Egress {
+				egress := newEgress("client", "ca")
+				egress.Spec.Sources = []policyv1alpha1.EgressSourceSpec{{
+					Kind:        policy.EgressSourceKindPod,
+					Namespace:   "test",
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}},
+				}}
+				return egress
+			}(),
+			expectedCurrentStatus: policyv1alpha1.StatusCommitted,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonResolved,
+				policyv1alpha1.ConditionProgrammed:   policyv1alpha1.ReasonPending,
+			},
+		},
+		{
+			name: "pod selector source sharing a service account with unselected pods",
+			egress: func() *policyv1alpha1.Egress {
+				egress := newEgress("client", "ca")
+				egress.Spec.Sources = []policyv1alpha1.EgressSourceSpec{{
+					Kind:        policy.EgressSourceKindPod,
+					Namespace:   "test",
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}},
+				}}
+				return egress
+			}(),
+			sourcesErr:            errors.New("shared service account"),
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionAccepted:   policyv1alpha1.ReasonInvalid,
+				policyv1alpha1.ConditionProgrammed: policyv1alpha1.ReasonInvalid,
+			},
+		},
+		{
+			name: "TCP port with the egress gateway enabled",
+			egress: func() *policyv1alpha1.Egress {
+				egress := newEgress("client", "ca")
+				egress.Spec.Ports = append(egress.Spec.Ports, policyv1alpha1.PortSpec{Number: 5432, Protocol: "tcp"})
+				return egress
+			}(),
+			enableEgressGateway:   true,
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionAccepted:   policyv1alpha1.ReasonInvalid,
+				policyv1alpha1.ConditionProgrammed: policyv1alpha1.ReasonInvalid,
+			},
+		},
+		{
+			name:                  "TLS secret not found",
+			egress:                newEgress("client", "unknown"),
+			expectedCurrentStatus: policyv1alpha1.StatusError,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonSecretNotFound,
+			},
+		},
+		{
+			name:                  "configuration pending acknowledgement",
+			egress:                newEgress("client", "ca"),
+			expectedCurrentStatus: policyv1alpha1.StatusCommitted,
+			expectedConditions: map[string]string{
+				policyv1alpha1.ConditionAccepted:     policyv1alpha1.ReasonValid,
+				policyv1alpha1.ConditionResolvedRefs: policyv1alpha1.ReasonResolved,
+				policyv1alpha1.ConditionConflicted:   policyv1alpha1.ReasonNoConflicts,
+				policyv1alpha1.ConditionProgrammed:   policyv1alpha1.ReasonPending,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			mockKubeController.EXPECT().ListServiceAccounts().Return([]*corev1.ServiceAccount{
+				{ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "test"}},
+			}).AnyTimes()
+			mockKubeController.EXPECT().GetSecret("ca", "test").Return(&corev1.Secret{}).AnyTimes()
+			mockKubeController.EXPECT().GetSecret("unknown", "test").Return(nil).AnyTimes()
+			mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+			mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+			var updated *policyv1alpha1.Egress
+			mockKubeController.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(obj interface{}) (metav1.Object, error) {
+				updated = obj.(*policyv1alpha1.Egress)
+				return updated, nil
+			}).Times(1)
+
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockPolicyController.EXPECT().ValidateEgressSources(tc.egress).Return(tc.sourcesErr).AnyTimes()
+			mockPolicyController.EXPECT().ListEgressSourceIdentities(tc.egress).Return([]identity.K8sServiceAccount{
+				{Name: "client", Namespace: "test"},
+			}).AnyTimes()
+
+			cn, proxy := newTestProxy(t, "client", "test")
+			lister := fakeProxyLister{proxies: map[certificate.CommonName]*envoy.Proxy{cn: proxy}}
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{
+				EnableEgressPolicy:  true,
+				EnableEgressGateway: tc.enableEgressGateway,
+			}).AnyTimes()
+
+			r := NewReconciler(nil, mockKubeController, mockPolicyController, lister, mockConfigurator, nil)
+			r.reconcileEgress(tc.egress, []*policyv1alpha1.Egress{tc.egress})
+
+			a.NotNil(updated)
+			a.Equal(tc.expectedCurrentStatus, updated.Status.CurrentStatus)
+			for conditionType, reason := range tc.expectedConditions {
+				condition := meta.FindStatusCondition(updated.Status.Conditions, conditionType)
+				a.NotNil(condition, conditionType)
+				a.Equal(reason, condition.Reason, conditionType)
+				a.Equal(tc.egress.Generation, condition.ObservedGeneration)
+			}
+		})
+	}
+}
+
+func TestGetProgrammedCondition(t *testing.T) {
+	a := tassert.New(t)
+	r := NewReconciler(nil, nil, nil, fakeProxyLister{}, nil, nil)
+	obj := &metav1.ObjectMeta{UID: types.UID("uid"), Generation: 1}
+
+	_, proxy := newTestProxy(t, "sa", "ns")
+	// A configuration acknowledged before the generation was observed does not program it
+	proxy.SetLastAppliedVersion(envoy.TypeCDS, proxy.IncrementLastSentVersion(envoy.TypeCDS))
+	time.Sleep(time.Millisecond)
+
+	condition := r.getProgrammedCondition(obj, nil, nil)
+	a.Equal(metav1.ConditionFalse, condition.Status)
+	a.Equal(policyv1alpha1.ReasonNoProxies, condition.Reason)
+
+	condition = r.getProgrammedCondition(obj, nil, []*envoy.Proxy{proxy})
+	a.Equal(policyv1alpha1.ReasonPending, condition.Reason)
+
+	time.Sleep(time.Millisecond)
+	proxy.SetLastAppliedVersion(envoy.TypeCDS, proxy.IncrementLastSentVersion(envoy.TypeCDS))
+	condition = r.getProgrammedCondition(obj, nil, []*envoy.Proxy{proxy})
+	a.Equal(metav1.ConditionTrue, condition.Status)
+	a.Equal(policyv1alpha1.ReasonProgrammed, condition.Reason)
+
+	// The condition is sticky for the generation
+	proxy.IncrementLastSentVersion(envoy.TypeCDS)
+	a.Equal(condition, r.getProgrammedCondition(obj, []metav1.Condition{condition}, []*envoy.Proxy{proxy}))
+
+	// A new generation must be programmed again
+	obj.Generation = 2
+	time.Sleep(time.Millisecond)
+	condition = r.getProgrammedCondition(obj, []metav1.Condition{condition}, []*envoy.Proxy{proxy})
+	a.Equal(policyv1alpha1.ReasonPending, condition.Reason)
+
+	// The acknowledgements are unknown while the proxies may be connected to other controller replicas
+	r.multipleReplicas = true
+	condition = r.getProgrammedCondition(obj, []metav1.Condition{condition}, []*envoy.Proxy{proxy})
+	a.Equal(metav1.ConditionUnknown, condition.Status)
+	a.Equal(policyv1alpha1.ReasonMultipleReplicas, condition.Reason)
+}
+
+func TestHasMultipleControllerReplicas(t *testing.T) {
+	testCases := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		expected   bool
+	}{
+		{
+			name:       "controller Deployment not found",
+			deployment: nil,
+			expected:   true,
+		},
+		{
+			name:       "single controller replica",
+			deployment: newTestControllerDeployment(1),
+			expected:   false,
+		},
+		{
+			name:       "multiple controller replicas",
+			deployment: newTestControllerDeployment(2),
+			expected:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			kubeClient := fake.NewSimpleClientset()
+			if tc.deployment != nil {
+				kubeClient = fake.NewSimpleClientset(tc.deployment)
+			}
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+
+			r := NewReconciler(kubeClient, nil, nil, fakeProxyLister{}, mockConfigurator, nil)
+			a.Equal(tc.expected, r.hasMultipleControllerReplicas())
+		})
+	}
+}
+
+func newTestControllerDeployment(replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.OSMControllerName, Namespace: "osm-system"},
+		Status:     appsv1.DeploymentStatus{Replicas: replicas},
+	}
+}
+
+func TestIsServiceTargetPort(t *testing.T) {
+	a := tassert.New(t)
+
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Port: 90},
+				{Port: 443, TargetPort: intstr.FromString("https")},
+			},
+		},
+	}
+	endpoints := &corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{{Ports: []corev1.EndpointPort{{Name: "https", Port: 8443}}}},
+	}
+
+	a.True(isServiceTargetPort(svc, endpoints, 8080))
+	a.True(isServiceTargetPort(svc, endpoints, 90))
+	a.True(isServiceTargetPort(svc, endpoints, 8443))
+	a.False(isServiceTargetPort(svc, endpoints, 80))
+	a.False(isServiceTargetPort(svc, nil, 8443))
+}
+
+func TestRunReconcilesOnEvents(t *testing.T) {
+	a := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	msgBroker := messaging.NewBroker(stop)
+
+	reconciled := make(chan struct{}, 10)
+	mockPolicyController := policy.NewMockController(mockCtrl)
+	mockPolicyController.EXPECT().ListIngressBackendPolicies().Return(nil).AnyTimes()
+	mockPolicyController.EXPECT().ListEgressPolicies().DoAndReturn(func() []*policyv1alpha1.Egress {
+		reconciled <- struct{}{}
+		return nil
+	}).AnyTimes()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+
+	r := NewReconciler(fake.NewSimpleClientset(newTestControllerDeployment(1)), nil, mockPolicyController, fakeProxyLister{}, mockConfigurator, msgBroker)
+	go r.Run(stop)
+
+	// The status is reconciled upon start
+	select {
+	case <-reconciled:
+	case <-time.After(5 * time.Second):
+		a.Fail("the status was not reconciled upon start")
+	}
+
+	// Events are coalesced, and no reconciliation happens in the absence of events and pending policies
+	for i := 0; i < 5; i++ {
+		msgBroker.GetKubeEventPubSub().Pub(events.PubSubMessage{Kind: announcements.EgressUpdated}, announcements.EgressUpdated.String())
+	}
+	select {
+	case <-reconciled:
+	case <-time.After(5 * time.Second):
+		a.Fail("the status was not reconciled upon an event")
+	}
+	select {
+	case <-reconciled:
+		a.Fail("the status was reconciled more than once for coalesced events")
+	case <-time.After(2 * reconcileDelay):
+	}
+}
@@ -0,0 +1,77 @@
+// Package policystatus implements the reconciler of the status of the resources in the policy.openservicemesh.io
+// API group. The status conditions report whether a policy is valid, whether the resources it references exist,
+// whether it conflicts with another policy, and whether the resulting configuration has been acknowledged by the
+// proxies it applies to.
+package policystatus
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/messaging"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+var (
+	log = logger.New("policy-status-reconciler")
+)
+
+const (
+	// LeaseName is the name of the Lease used to elect the controller replica reconciling the status of the policies
+	LeaseName = "osm-policy-status"
+
+	// reconcileDelay is the delay after an event before the status of the policies is reconciled, coalescing the
+	// events observed within it
+	reconcileDelay = 1 * time.Second
+
+	// pendingRetryInterval is the interval at which the status of the policies is reconciled while the configuration
+	// resulting from a policy has not been acknowledged by its proxies, which is not signaled by an event
+	pendingRetryInterval = 5 * time.Second
+
+	// committedReason is the reason of a policy that has been accepted without errors
+	committedReason = "successfully committed by the system"
+)
+
+// proxyLister is the interface used to list the connected proxies and the services they belong to
+type proxyLister interface {
+	// ListConnectedProxies lists the proxies connected to the control plane
+	ListConnectedProxies() map[certificate.CommonName]*envoy.Proxy
+
+	// ListProxyServices lists the services the given proxy belongs to
+	ListProxyServices(*envoy.Proxy) ([]service.MeshService, error)
+}
+
+// observedGeneration is the type used to record the time a generation of a policy was first observed
+type observedGeneration struct {
+	generation int64
+	observedAt time.Time
+}
+
+// Reconciler is the type used to reconcile the status of the resources in the policy.openservicemesh.io API group
+type Reconciler struct {
+	kubeClient       kubernetes.Interface
+	kubeController   k8s.Controller
+	policyController policy.Controller
+	proxyLister      proxyLister
+	configurator     configurator.Configurator
+	msgBroker        *messaging.Broker
+
+	// observedGenerations records the time the current generation of each policy was first observed. The
+	// configuration resulting from a generation is programmed once every proxy the policy applies to has
+	// acknowledged a configuration sent after it was observed.
+	observedGenerations map[types.UID]observedGeneration
+
+	// proxyServices caches the services of the connected proxies during a reconciliation
+	proxyServices map[*envoy.Proxy][]service.MeshService
+
+	// multipleReplicas records whether more than one controller replica runs during a reconciliation
+	multipleReplicas bool
+}
@@ -148,6 +148,7 @@ func (c *client) GetServicesForServiceIdentity(svcIdentity identity.ServiceIdent
 func (c *client) getServicesByLabels(podLabels map[string]string, targetNamespace string) []service.MeshService {
 	var finalList []service.MeshService
 	serviceList := c.kubeController.ListServices()
+	enableProtocolSniffing := c.meshConfigurator.GetFeatureFlags().EnableProtocolSniffing
 
 	for _, svc := range serviceList {
 		// TODO: #1684 Introduce APIs to dynamically allow applying selectors, instead of callers implementing
@@ -163,7 +164,7 @@ func (c *client) getServicesByLabels(podLabels map[string]string, targetNamespac
 		}
 		selector := labels.Set(svcRawSelector).AsSelector()
 		if selector.Matches(labels.Set(podLabels)) {
-			finalList = append(finalList, k8s.ServiceToMeshServices(c.kubeController, *svc)...)
+			finalList = append(finalList, k8s.ServiceToMeshServices(c.kubeController, *svc, enableProtocolSniffing)...)
 		}
 	}
 
@@ -187,18 +188,26 @@ func (c *client) GetResolvableEndpointsForService(svc service.MeshService) []end
 		return c.ListEndpointsForService(svc)
 	}
 
-	// Cluster IP is present
-	ip := net.ParseIP(kubeService.Spec.ClusterIP)
-	if ip == nil {
-		log.Error().Msgf("[%s] Could not parse Cluster IP %s", c.GetID(), kubeService.Spec.ClusterIP)
-		return nil
+	// Cluster IP is present. Dual-stack services are assigned a Cluster IP per IP family.
+	clusterIPs := kubeService.Spec.ClusterIPs
+	if len(clusterIPs) == 0 {
+		clusterIPs = []string{kubeService.Spec.ClusterIP}
 	}
 
-	for _, svcPort := range kubeService.Spec.Ports {
-		endpoints = append(endpoints, endpoint.Endpoint{
-			IP:   ip,
-			Port: endpoint.Port(svcPort.Port),
-		})
+	for _, clusterIP := range clusterIPs {
+		ip := net.ParseIP(clusterIP)
+		if ip == nil {
+			// The other Cluster IPs of the service remain resolvable
+			log.Error().Msgf("[%s] Could not parse Cluster IP %s of service %s, skipping it", c.GetID(), clusterIP, svc)
+			continue
+		}
+
+		for _, svcPort := range kubeService.Spec.Ports {
+			endpoints = append(endpoints, endpoint.Endpoint{
+				IP:   ip,
+				Port: endpoint.Port(svcPort.Port),
+			})
+		}
 	}
 
 	return endpoints
@@ -207,8 +216,9 @@ func (c *client) GetResolvableEndpointsForService(svc service.MeshService) []end
 // ListServices returns a list of services that are part of monitored namespaces
 func (c *client) ListServices() []service.MeshService {
 	var services []service.MeshService
+	enableProtocolSniffing := c.meshConfigurator.GetFeatureFlags().EnableProtocolSniffing
 	for _, svc := range c.kubeController.ListServices() {
-		services = append(services, k8s.ServiceToMeshServices(c.kubeController, *svc)...)
+		services = append(services, k8s.ServiceToMeshServices(c.kubeController, *svc, enableProtocolSniffing)...)
 	}
 	return services
 }
@@ -163,6 +163,60 @@ var _ = Describe("Test Kube client Provider (w/o kubecontroller)", func() {
 		}))
 	})
 
+	It("GetResolvableEndpoints should properly return endpoints for each ClusterIP of a dual-stack service", func() {
+		mockKubeController.EXPECT().GetService(tests.BookbuyerService).Return(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tests.BookbuyerService.Name,
+				Namespace: tests.BookbuyerService.Namespace,
+			},
+			Spec: corev1.ServiceSpec{
+				ClusterIP:  "192.168.0.1",
+				ClusterIPs: []string{"192.168.0.1", "fd00::1"},
+				Ports: []corev1.ServicePort{{
+					Name:     "servicePort",
+					Protocol: corev1.ProtocolTCP,
+					Port:     tests.ServicePort,
+				}},
+			},
+		})
+
+		Expect(c.GetResolvableEndpointsForService(tests.BookbuyerService)).To(Equal([]endpoint.Endpoint{
+			{
+				IP:   net.IPv4(192, 168, 0, 1),
+				Port: tests.ServicePort,
+			},
+			{
+				IP:   net.ParseIP("fd00::1"),
+				Port: tests.ServicePort,
+			},
+		}))
+	})
+
+	It("GetResolvableEndpoints should skip the ClusterIPs that cannot be parsed", func() {
+		mockKubeController.EXPECT().GetService(tests.BookbuyerService).Return(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tests.BookbuyerService.Name,
+				Namespace: tests.BookbuyerService.Namespace,
+			},
+			Spec: corev1.ServiceSpec{
+				ClusterIP:  "192.168.0.1",
+				ClusterIPs: []string{"192.168.0.1", "not-an-ip"},
+				Ports: []corev1.ServicePort{{
+					Name:     "servicePort",
+					Protocol: corev1.ProtocolTCP,
+					Port:     tests.ServicePort,
+				}},
+			},
+		})
+
+		Expect(c.GetResolvableEndpointsForService(tests.BookbuyerService)).To(Equal([]endpoint.Endpoint{
+			{
+				IP:   net.IPv4(192, 168, 0, 1),
+				Port: tests.ServicePort,
+			},
+		}))
+	})
+
 	It("GetResolvableEndpoints should properly return actual endpoints without ClusterIP when ClusterIP is not set", func() {
 		// Expect the individual pod endpoints, when no cluster IP is assigned to the service
 		mockKubeController.EXPECT().GetService(meshSvc).Return(&corev1.Service{
@@ -340,10 +394,13 @@ func TestGetServicesForServiceIdentity(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 			c := &client{
-				kubeController: mockKubeController,
+				kubeController:   mockKubeController,
+				meshConfigurator: mockConfigurator,
 			}
 
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
 			mockKubeController.EXPECT().ListPods().Return(tc.pods)
 			mockKubeController.EXPECT().ListServices().Return(tc.services)
 			mockKubeController.EXPECT().GetEndpoints(gomock.Any()).Return(nil, nil).AnyTimes()
@@ -488,8 +545,8 @@ func TestGetMultiClusterServiceEndpointsForServiceAccount(t *testing.T) {
 		{
 			IP:       net.ParseIP("1.2.3.4"),
 			Port:     8080,
-			Weight:   0,
-			Priority: 0,
+			Weight:   1,
+			Priority: 1,
 			Zone:     "remote-cluster-1",
 		},
 		{
@@ -3,7 +3,6 @@ package kube
 import (
 	"net"
 	"strconv"
-	"strings"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -11,7 +10,15 @@ import (
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
-const portIPSeparator = `:`
+const (
+	// defaultRemoteClusterPriority is the locality priority of a remote cluster's endpoints
+	// when a priority is not specified. The local cluster's endpoints have the highest priority 0.
+	defaultRemoteClusterPriority = endpoint.Priority(1)
+
+	// minRemoteClusterWeight is the minimum load balancing weight of a remote cluster's endpoints. Envoy rejects
+	// localities with a weight of 0, and an endpoint without a weight is considered local to the cluster.
+	minRemoteClusterWeight = endpoint.Weight(1)
+)
 
 // getMulticlusterEndpoints returns the endpoints for multicluster services if such exist.
 func (c *client) getMulticlusterEndpoints(svc service.MeshService) []endpoint.Endpoint {
@@ -47,11 +54,16 @@ func (c *client) getMultiClusterServiceEndpointsForServiceAccount(serviceAccount
 				continue
 			}
 
+			weight := minRemoteClusterWeight
+			if cluster.Weight > int(minRemoteClusterWeight) {
+				weight = endpoint.Weight(cluster.Weight)
+			}
+
 			ep := endpoint.Endpoint{
 				IP:       ip,
 				Port:     endpoint.Port(port),
-				Weight:   endpoint.Weight(cluster.Weight),
-				Priority: endpoint.Priority(cluster.Priority),
+				Weight:   weight,
+				Priority: getRemoteClusterPriority(svc.Spec, cluster),
 				Zone:     cluster.Name,
 			}
 			endpoints = append(endpoints, ep)
@@ -61,14 +73,44 @@ func (c *client) getMultiClusterServiceEndpointsForServiceAccount(serviceAccount
 	return endpoints
 }
 
+// getRemoteClusterPriority returns the locality priority of the given remote cluster's endpoints based on
+// the failover policy of the multicluster service. Remote clusters share the priority of the local cluster
+// in the ActiveActive mode, and otherwise only receive traffic when the endpoints of higher priorities are unhealthy.
+func getRemoteClusterPriority(spec v1alpha1.MultiClusterServiceSpec, cluster v1alpha1.ClusterSpec) endpoint.Priority {
+	mode := v1alpha1.FailoverOnlyMode
+	if spec.Failover != nil && spec.Failover.Mode != "" {
+		mode = spec.Failover.Mode
+	}
+
+	switch mode {
+	case v1alpha1.ActiveActiveMode:
+		return endpoint.Priority(0)
+
+	case v1alpha1.PreferredClustersMode:
+		// Preferred clusters are tried in order, followed by the remaining clusters
+		for i, name := range spec.Failover.PreferredClusters {
+			if name == cluster.Name {
+				return defaultRemoteClusterPriority + endpoint.Priority(i)
+			}
+		}
+		return defaultRemoteClusterPriority + endpoint.Priority(len(spec.Failover.PreferredClusters))
+
+	default:
+		if cluster.Priority > 0 {
+			return endpoint.Priority(cluster.Priority)
+		}
+		return defaultRemoteClusterPriority
+	}
+}
+
 func getIPPort(cluster v1alpha1.ClusterSpec) (ip net.IP, port int, err error) {
-	tokens := strings.Split(cluster.Address, portIPSeparator)
-	if len(tokens) != 2 {
-		log.Error().Err(errParseMulticlusterServiceIP).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Invalid address format %s. It should have IP address and port number separated by %s", cluster.Address, portIPSeparator)
+	// IPv6 addresses are enclosed in square brackets, ex. [fd00::1]:15443
+	ipStr, portStr, err := net.SplitHostPort(cluster.Address)
+	if err != nil {
+		log.Error().Err(errParseMulticlusterServiceIP).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Invalid address format %s. It should have IP address and port number separated by ':'", cluster.Address)
 		return nil, 0, errParseMulticlusterServiceIP
 	}
 
-	ipStr, portStr := tokens[0], tokens[1]
 	port, err = strconv.Atoi(portStr)
 	if err != nil {
 		log.Error().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Invalid port number format %s for cluster address: %s", portStr, cluster.Address)
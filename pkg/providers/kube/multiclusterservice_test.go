@@ -49,9 +49,11 @@ func TestHelperFunctions(t *testing.T) {
 	mockKubeController.EXPECT().ListServiceIdentitiesForService(tests.BookbuyerService).Return(toReturnIdentities, nil).AnyTimes()
 
 	expectedEndpoint := []endpoint.Endpoint{{
-		IP:   net.IPv4(1, 2, 3, 4),
-		Port: 5678,
-		Zone: "alpha",
+		IP:       net.IPv4(1, 2, 3, 4),
+		Port:     5678,
+		Weight:   1,
+		Zone:     "alpha",
+		Priority: 1,
 	}}
 
 	toReturnServices := []v1alpha1.MultiClusterService{{
@@ -89,4 +91,74 @@ func TestHelperFunctions(t *testing.T) {
 	expectedPort := 5678
 	assert.Equal(actualIP, expectedIP)
 	assert.Equal(actualPort, expectedPort)
+
+	// IPv6 address
+	clusterSpec.Address = "[fd00::1]:5678"
+	actualIP, actualPort, err = getIPPort(clusterSpec)
+	assert.Nil(err)
+	assert.Equal(net.ParseIP("fd00::1"), actualIP)
+	assert.Equal(5678, actualPort)
+
+	// IPv6 address without square brackets
+	clusterSpec.Address = "fd00::1:5678"
+	_, _, err = getIPPort(clusterSpec)
+	assert.NotNil(err)
+}
+
+func TestGetRemoteClusterPriority(t *testing.T) {
+	alpha := v1alpha1.ClusterSpec{Name: "alpha"}
+	beta := v1alpha1.ClusterSpec{Name: "beta", Priority: 3}
+	gamma := v1alpha1.ClusterSpec{Name: "gamma"}
+
+	testCases := []struct {
+		name     string
+		failover *v1alpha1.FailoverSpec
+		cluster  v1alpha1.ClusterSpec
+		expected endpoint.Priority
+	}{
+		{
+			name:     "no failover policy defaults to the remote cluster priority",
+			failover: nil,
+			cluster:  alpha,
+			expected: 1,
+		},
+		{
+			name:     "failover only mode honors the cluster priority",
+			failover: &v1alpha1.FailoverSpec{Mode: v1alpha1.FailoverOnlyMode},
+			cluster:  beta,
+			expected: 3,
+		},
+		{
+			name:     "active-active mode shares the local cluster priority",
+			failover: &v1alpha1.FailoverSpec{Mode: v1alpha1.ActiveActiveMode},
+			cluster:  beta,
+			expected: 0,
+		},
+		{
+			name:     "preferred cluster is prioritized by its position",
+			failover: &v1alpha1.FailoverSpec{Mode: v1alpha1.PreferredClustersMode, PreferredClusters: []string{"gamma", "alpha"}},
+			cluster:  alpha,
+			expected: 2,
+		},
+		{
+			name:     "cluster not preferred has the lowest priority",
+			failover: &v1alpha1.FailoverSpec{Mode: v1alpha1.PreferredClustersMode, PreferredClusters: []string{"gamma", "alpha"}},
+			cluster:  beta,
+			expected: 3,
+		},
+		{
+			name:     "first preferred cluster",
+			failover: &v1alpha1.FailoverSpec{Mode: v1alpha1.PreferredClustersMode, PreferredClusters: []string{"gamma", "alpha"}},
+			cluster:  gamma,
+			expected: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			spec := v1alpha1.MultiClusterServiceSpec{Failover: tc.failover}
+			assert.Equal(tc.expected, getRemoteClusterPriority(spec, tc.cluster))
+		})
+	}
 }
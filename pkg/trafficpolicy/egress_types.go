@@ -1,5 +1,11 @@
 package trafficpolicy
 
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
 // EgressTrafficPolicy is the type used to represent the different egress traffic policy configurations
 // applicable to a client of Egress destinations.
 type EgressTrafficPolicy struct {
@@ -37,6 +43,32 @@ type EgressClusterConfig struct {
 
 	// Port defines the port number of the external cluster's endpoint
 	Port int
+
+	// ViaEgressGateway defines if the traffic for the external cluster is routed through the egress gateway.
+	// If set, the cluster's address is the egress gateway, which proxies the traffic to `Host` and `Port`.
+	// +optional
+	ViaEgressGateway bool
+
+	// TLS defines the TLS origination configuration for the external cluster.
+	// If specified, the plaintext traffic routed to the cluster is upgraded to TLS.
+	// +optional
+	TLS *EgressTLSConfig
+}
+
+// EgressTLSConfig is the type used to represent the TLS origination configuration for an external cluster
+type EgressTLSConfig struct {
+	// SNI defines the Server Name Indication used in the TLS handshake with the external host.
+	// The certificate presented by the external host is validated against the SNI.
+	SNI string
+
+	// CACertSecret defines the secret containing the CA bundle used to validate the certificate
+	// presented by the external host
+	CACertSecret types.NamespacedName
+
+	// ClientCertSecret defines the secret containing the client certificate presented to the external
+	// host for mutual TLS
+	// +optional
+	ClientCertSecret *types.NamespacedName
 }
 
 // EgressHTTPRouteConfig is the type used to represent an HTTP route configuration along with associated routing rules
@@ -62,3 +94,33 @@ type EgressHTTPRoutingRule struct {
 	// AllowedDestinationIPRanges defines the destination IP ranges allowed for the `Route` defined in the routing rule.
 	AllowedDestinationIPRanges []string
 }
+
+// EgressGatewayTrafficPolicy is the type used to represent the traffic policy configurations applicable to the
+// egress gateway, which enforces the Egress policies for the Egress traffic routed through it by sidecars.
+type EgressGatewayTrafficPolicy struct {
+	// Upstreams defines the list of external destinations the egress gateway proxies traffic to.
+	Upstreams []*EgressGatewayUpstream
+}
+
+// EgressGatewayUpstream is the type used to represent an external destination the egress gateway proxies traffic to
+type EgressGatewayUpstream struct {
+	// Host defines the DNS resolvable hostname of the external destination
+	Host string
+
+	// Port defines the port number of the external destination
+	Port int
+
+	// ClusterName defines the name of the egress gateway's cluster for the external destination
+	ClusterName string
+
+	// ServerName defines the SNI used by sidecars to request the external destination from the egress gateway
+	ServerName string
+
+	// AllowedSourceIdentities defines the list of source identities allowed to access the external destination
+	// by Egress policies.
+	AllowedSourceIdentities []identity.ServiceIdentity
+
+	// TLS defines the TLS origination configuration for the external destination
+	// +optional
+	TLS *EgressTLSConfig
+}
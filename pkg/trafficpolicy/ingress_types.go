@@ -1,5 +1,12 @@
 package trafficpolicy
 
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
 // IngressTrafficPolicy defines the ingress traffic match and routes for a given backend
 type IngressTrafficPolicy struct {
 	TrafficMatches    []*IngressTrafficMatch
@@ -14,4 +21,65 @@ type IngressTrafficMatch struct {
 	SourceIPRanges           []string
 	ServerNames              []string
 	SkipClientCertValidation bool
+
+	// AllowedServiceIdentities and ClusterName are only set for TCP ingress, where the identity of the downstream
+	// is authorized by an RBAC filter and the traffic is proxied to the given local cluster.
+	AllowedServiceIdentities []identity.ServiceIdentity
+	ClusterName              string
+}
+
+// IngressGatewayTrafficPolicy is the type used to represent the traffic policy configurations applicable to the
+// ingress gateway, which proxies the ingress traffic admitted by Gateway API resources to mesh backends.
+type IngressGatewayTrafficPolicy struct {
+	// Listeners defines the list of listeners on the ingress gateway, one per port
+	Listeners []*IngressGatewayListener
+
+	// HTTPRouteConfigsPerPort defines the HTTP route configurations per listener port for the HTTP
+	// traffic accepted by the ingress gateway. Each hostname has its own route configuration.
+	HTTPRouteConfigsPerPort map[int][]*OutboundTrafficPolicy
+
+	// Upstreams defines the list of mesh backends the ingress gateway proxies traffic to
+	Upstreams []*IngressGatewayUpstream
+}
+
+// IngressGatewayListener is the type used to represent a port the ingress gateway accepts traffic on
+type IngressGatewayListener struct {
+	// Port defines the port number the listener accepts traffic on
+	Port int
+
+	// FilterChains defines the list of filter chains used to match the traffic on the port
+	FilterChains []*IngressGatewayFilterChain
+}
+
+// IngressGatewayFilterChain is the type used to represent the traffic matched by a listener of the ingress gateway
+type IngressGatewayFilterChain struct {
+	// Name defines the name of the filter chain
+	Name string
+
+	// Protocol defines the protocol of the matched traffic: 'http' for plaintext HTTP, 'https' for HTTP
+	// over TLS terminated by the gateway, and 'tcp' for TLS traffic routed based on its SNI
+	Protocol string
+
+	// ServerNames defines the list of SNI hostnames matched by the filter chain. An empty list matches any SNI.
+	// +optional
+	ServerNames []string
+
+	// CertificateSecrets defines the list of secrets holding the certificates presented to clients when
+	// the gateway terminates TLS. TLS traffic is passed through to the backend when unspecified.
+	// +optional
+	CertificateSecrets []types.NamespacedName
+
+	// WeightedClusters defines the backends the TCP traffic is proxied to.
+	// Only set for the 'tcp' protocol, HTTP traffic is routed using the HTTP route configurations of the port.
+	// +optional
+	WeightedClusters []service.WeightedCluster
+}
+
+// IngressGatewayUpstream is the type used to represent a mesh backend the ingress gateway proxies traffic to
+type IngressGatewayUpstream struct {
+	// Service defines the backend mesh service
+	Service service.MeshService
+
+	// Protocol defines the protocol of the traffic proxied to the backend: 'http' or 'tcp'
+	Protocol string
 }
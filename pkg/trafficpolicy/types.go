@@ -3,6 +3,8 @@
 package trafficpolicy
 
 import (
+	"time"
+
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/protobuf/ptypes/duration"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -49,6 +51,7 @@ type RouteWeightedClusters struct {
 	HTTPRouteMatch   HTTPRouteMatch `json:"http_route_match:omitempty"`
 	WeightedClusters mapset.Set     `json:"weighted_clusters:omitempty"`
 	RetryPolicy      RetryPolicy
+	HashPolicy       *HashPolicy
 }
 
 // RetryPolicy is a struct of the RetryPolicy
@@ -58,6 +61,60 @@ type RetryPolicy struct {
 	PerTryTimeout *duration.Duration      `json:"per_try_timeout,omitempty"`
 }
 
+// LoadBalancerAlgorithm is the type used to represent the load balancing algorithm used to pick an upstream endpoint
+type LoadBalancerAlgorithm string
+
+const (
+	// RoundRobinLoadBalancer is the load balancing algorithm that selects endpoints in a round robin order
+	RoundRobinLoadBalancer LoadBalancerAlgorithm = "round-robin"
+
+	// RingHashLoadBalancer is the consistent-hash load balancing algorithm based on a hash ring
+	RingHashLoadBalancer LoadBalancerAlgorithm = "ring-hash"
+
+	// MaglevLoadBalancer is the consistent-hash load balancing algorithm based on Maglev
+	MaglevLoadBalancer LoadBalancerAlgorithm = "maglev"
+)
+
+// HashKeyKind is the type used to represent the request attribute used to compute the hash for consistent-hash load balancing
+type HashKeyKind string
+
+const (
+	// HashKeyHeader computes the hash using the value of a request header
+	HashKeyHeader HashKeyKind = "header"
+
+	// HashKeyCookie computes the hash using the value of an HTTP cookie
+	HashKeyCookie HashKeyKind = "cookie"
+
+	// HashKeySourceIP computes the hash using the downstream connection's source IP address
+	HashKeySourceIP HashKeyKind = "source-ip"
+)
+
+// HashPolicy is the type used to represent how the hash key for consistent-hash load balancing is computed
+type HashPolicy struct {
+	// Kind is the kind of request attribute used to compute the hash
+	Kind HashKeyKind `json:"kind"`
+
+	// Name is the name of the header or cookie used to compute the hash.
+	// It is unset when Kind is HashKeySourceIP.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CookieTTL is the TTL of the cookie generated by the proxy when the cookie is absent
+	// in the request. A cookie is only generated when CookieTTL is set.
+	// +optional
+	CookieTTL time.Duration `json:"cookie_ttl,omitempty"`
+}
+
+// LoadBalancerConfig is the type used to represent the load balancing configuration for an upstream service
+type LoadBalancerConfig struct {
+	// Algorithm is the load balancing algorithm
+	Algorithm LoadBalancerAlgorithm `json:"algorithm"`
+
+	// HashPolicy defines how the hash key is computed for consistent-hash load balancing algorithms
+	// +optional
+	HashPolicy *HashPolicy `json:"hash_policy,omitempty"`
+}
+
 // InboundTrafficPolicy is a struct that associates incoming traffic on a set of Hostnames with a list of Rules
 type InboundTrafficPolicy struct {
 	Name      string   `json:"name:omitempty"`
@@ -123,6 +180,31 @@ type InboundMeshTrafficPolicy struct {
 	ClustersConfigs []*MeshClusterConfig
 }
 
+// MulticlusterGatewayTrafficPolicy is the type used to represent the traffic policy configurations applicable
+// to the multicluster gateway in HTTP mode, in which the gateway terminates mTLS for HTTP services to enforce
+// their HTTP routes and RBAC policies before re-originating mTLS to the local endpoints of the services.
+type MulticlusterGatewayTrafficPolicy struct {
+	// HTTPUpstreams defines the list of HTTP services whose traffic is terminated by the gateway.
+	HTTPUpstreams []*MulticlusterGatewayHTTPUpstream
+
+	// HTTPRoutePolicies defines the HTTP route configurations of the upstream services,
+	// with routes directing traffic to the gateway's clusters for the upstream services.
+	HTTPRoutePolicies []*InboundTrafficPolicy
+}
+
+// MulticlusterGatewayHTTPUpstream is the type used to represent an HTTP service whose traffic is terminated
+// by the multicluster gateway.
+type MulticlusterGatewayHTTPUpstream struct {
+	// Service is the upstream HTTP service
+	Service service.MeshService
+
+	// Identity is the service identity the gateway presents to downstream clients of the service
+	Identity identity.ServiceIdentity
+
+	// ClusterName is the name of the gateway's cluster that re-originates mTLS to the service
+	ClusterName string
+}
+
 // MeshClusterConfig is the type used to represent a cluster configuration for that is programmed
 // for either:
 // 1. A downstream to connect to an upstream cluster, OR
@@ -147,6 +229,37 @@ type MeshClusterConfig struct {
 	// EnableEnvoyActiveHealthChecks enables Envoy's active health checks for the cluster
 	// +optional
 	EnableEnvoyActiveHealthChecks bool
+
+	// LoadBalancer is the load balancing configuration for the cluster.
+	// Round robin load balancing is used when unset.
+	// +optional
+	LoadBalancer *LoadBalancerConfig
+
+	// EnableLocalityWeightedLb enables distributing traffic across localities of the
+	// same priority based on their weights. This is used to share traffic between the
+	// local and remote clusters in multicluster scenarios.
+	// +optional
+	EnableLocalityWeightedLb bool
+
+	// OutlierDetection is the passive health checking configuration for the cluster
+	// +optional
+	OutlierDetection *OutlierDetection
+}
+
+// OutlierDetection is the type used to represent the passive health checking configuration used to eject
+// unhealthy endpoints from a cluster's load balancing pool
+type OutlierDetection struct {
+	// ConsecutiveErrors is the number of consecutive gateway or 5xx errors after which an endpoint is ejected
+	ConsecutiveErrors uint32
+
+	// Interval is the time interval between ejection analysis sweeps
+	Interval time.Duration
+
+	// BaseEjectionTime is the base duration an endpoint is ejected for
+	BaseEjectionTime time.Duration
+
+	// MaxEjectionPercent is the maximum percentage of endpoints that can be ejected
+	MaxEjectionPercent uint32
 }
 
 // TrafficMatch is the type used to represent attributes used to match traffic
@@ -182,4 +295,14 @@ type TrafficMatch struct {
 	// route traffic to. This is used by TCP based mesh clusters.
 	// +optional
 	WeightedClusters []service.WeightedCluster
+
+	// HashPolicy defines how the hash key is computed for consistent-hash load
+	// balancing of TCP connections. Only a source IP based hash key is applicable.
+	// +optional
+	HashPolicy *HashPolicy
+
+	// RedisSharding defines if the Redis commands are routed to the endpoints of the
+	// upstream service based on their key. Only applicable to the 'redis' protocol.
+	// +optional
+	RedisSharding bool
 }
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// ipv4SingleIPPrefixLen is the prefix length of an IPv4 CIDR matching a single IP address
+	ipv4SingleIPPrefixLen = 32
+
+	// ipv6SingleIPPrefixLen is the prefix length of an IPv6 CIDR matching a single IP address
+	ipv6SingleIPPrefixLen = 128
+)
+
+// GetCIDRForIP returns the CIDR range matching only the given IP address, based on its IP family.
+// Ex. 10.0.0.1 => 10.0.0.1/32, fd00::1 => fd00::1/128
+func GetCIDRForIP(ip net.IP) string {
+	if ip.To4() != nil {
+		return fmt.Sprintf("%s/%d", ip, ipv4SingleIPPrefixLen)
+	}
+	return fmt.Sprintf("%s/%d", ip, ipv6SingleIPPrefixLen)
+}
+
+// ParseIPRange returns the given IP range in CIDR notation. The IP range can either be a CIDR range,
+// or a single IPv4 or IPv6 address, in which case the CIDR range matching only that address is returned.
+func ParseIPRange(ipRange string) (string, error) {
+	if _, _, err := net.ParseCIDR(ipRange); err == nil {
+		return ipRange, nil
+	}
+
+	ip := net.ParseIP(ipRange)
+	if ip == nil {
+		return "", errors.Errorf("invalid IP range %s, must be a CIDR range or an IP address", ipRange)
+	}
+	return GetCIDRForIP(ip), nil
+}
+
+// GetLocalhostIPAddress returns the loopback IP address of the IP family of the given IP address, the IPv4 loopback
+// address if the IP address is unknown. Ex. 10.0.0.1 => 127.0.0.1, fd00::1 => ::1
+func GetLocalhostIPAddress(ip net.IP) string {
+	if ip == nil || ip.To4() != nil {
+		return constants.LocalhostIPAddress
+	}
+	return constants.LocalhostIPv6Address
+}
+
+// IsIPv6 returns a boolean indicating if the given IP address is an IPv6 address
+func IsIPv6(ip net.IP) bool {
+	return ip != nil && ip.To4() == nil
+}
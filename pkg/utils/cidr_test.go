@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"net"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestGetCIDRForIP(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("10.0.0.1/32", GetCIDRForIP(net.ParseIP("10.0.0.1")))
+	assert.Equal("fd00::1/128", GetCIDRForIP(net.ParseIP("fd00::1")))
+	assert.Equal("10.0.0.1/32", GetCIDRForIP(net.ParseIP("::ffff:10.0.0.1")))
+}
+
+func TestParseIPRange(t *testing.T) {
+	testCases := []struct {
+		ipRange       string
+		expectedRange string
+		expectErr     bool
+	}{
+		{
+			ipRange:       "10.0.0.0/24",
+			expectedRange: "10.0.0.0/24",
+			expectErr:     false,
+		},
+		{
+			ipRange:       "fd00::/64",
+			expectedRange: "fd00::/64",
+			expectErr:     false,
+		},
+		{
+			ipRange:       "10.0.0.1",
+			expectedRange: "10.0.0.1/32",
+			expectErr:     false,
+		},
+		{
+			ipRange:       "fd00::1",
+			expectedRange: "fd00::1/128",
+			expectErr:     false,
+		},
+		{
+			ipRange:       "10.0.0.1/33",
+			expectedRange: "",
+			expectErr:     true,
+		},
+		{
+			ipRange:       "foo",
+			expectedRange: "",
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ipRange, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := ParseIPRange(tc.ipRange)
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedRange, actual)
+		})
+	}
+}
+
+func TestGetLocalhostIPAddress(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("127.0.0.1", GetLocalhostIPAddress(net.ParseIP("10.0.0.1")))
+	assert.Equal("127.0.0.1", GetLocalhostIPAddress(net.ParseIP("::ffff:10.0.0.1")))
+	assert.Equal("127.0.0.1", GetLocalhostIPAddress(nil))
+	assert.Equal("::1", GetLocalhostIPAddress(net.ParseIP("fd00::1")))
+}
+
+func TestIsIPv6(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.False(IsIPv6(net.ParseIP("10.0.0.1")))
+	assert.False(IsIPv6(net.ParseIP("::ffff:10.0.0.1")))
+	assert.False(IsIPv6(nil))
+	assert.True(IsIPv6(net.ParseIP("fd00::1")))
+}
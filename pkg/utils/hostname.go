@@ -0,0 +1,12 @@
+package utils
+
+import "strings"
+
+// wildcardHostPrefix is the prefix of a hostname that matches any subdomain of the domain following it
+const wildcardHostPrefix = "*."
+
+// IsWildcardHost returns a boolean indicating if the given host is a wildcard host of the form `*.example.com`,
+// which matches any subdomain of the domain following the wildcard label.
+func IsWildcardHost(host string) bool {
+	return strings.HasPrefix(host, wildcardHostPrefix)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestIsWildcardHost(t *testing.T) {
+	assert := tassert.New(t)
+
+	testCases := []struct {
+		host     string
+		expected bool
+	}{
+		{"*.example.com", true},
+		{"*.foo.example.com", true},
+		{"example.com", false},
+		{"foo*.example.com", false},
+		{"*example.com", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, IsWildcardHost(tc.host), tc.host)
+	}
+}
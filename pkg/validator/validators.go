@@ -14,7 +14,7 @@ import (
 	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 
-	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/policy"
 )
 
 // validateFunc is a function type that accepts an AdmissionRequest and returns an AdmissionResponse.
@@ -81,41 +81,8 @@ func ingressBackendValidator(req *admissionv1.AdmissionRequest) (*admissionv1.Ad
 		return nil, err
 	}
 
-	for _, backend := range ingressBackend.Spec.Backends {
-		// Validate port
-		switch strings.ToLower(backend.Port.Protocol) {
-		case constants.ProtocolHTTP:
-			// Valid
-
-		case constants.ProtocolHTTPS:
-			// Valid
-			// If mTLS is enabled, verify there is an AuthenticatedPrincipal specified
-			authenticatedSourceFound := false
-			for _, source := range ingressBackend.Spec.Sources {
-				if source.Kind == policyv1alpha1.KindAuthenticatedPrincipal {
-					authenticatedSourceFound = true
-					break
-				}
-			}
-
-			if backend.TLS.SkipClientCertValidation && !authenticatedSourceFound {
-				return nil, errors.Errorf("HTTPS ingress with client certificate validation enabled must specify at least one 'AuthenticatedPrincipal` source")
-			}
-
-		default:
-			return nil, errors.Errorf("Expected 'port.protocol' to be 'http' or 'https', got: %s", backend.Port.Protocol)
-		}
-	}
-
-	// Validate sources
-	for _, source := range ingressBackend.Spec.Sources {
-		switch source.Kind {
-		// Add validation for source kinds here
-		case policyv1alpha1.KindIPRange:
-			if _, _, err := net.ParseCIDR(source.Name); err != nil {
-				return nil, errors.Errorf("Invalid 'source.Name' value specified for IPRange. Expected CIDR notation 'a.b.c.d/x', got '%s'", source.Name)
-			}
-		}
+	if err := policy.ValidateIngressBackend(ingressBackend); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -128,14 +95,8 @@ func egressValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionR
 		return nil, err
 	}
 
-	for _, m := range egress.Spec.Matches {
-		if m.Kind != "HTTPRouteGroup" {
-			return nil, errors.Errorf("Expected 'Matches.Kind' to be 'HTTPRouteGroup', got: %s", m.Kind)
-		}
-
-		if *m.APIGroup != "specs.smi-spec.io/v1alpha4" {
-			return nil, errors.Errorf("Expected 'Matches.APIGroup' to be 'specs.smi-spec.io/v1alpha4', got: %s", *m.APIGroup)
-		}
+	if err := policy.ValidateEgress(egress); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -160,16 +121,69 @@ func MultiClusterServiceValidator(req *admissionv1.AdmissionRequest) (*admission
 		if len(strings.TrimSpace(cluster.Address)) == 0 {
 			return nil, errors.Errorf("Cluster address %s is not valid", cluster.Address)
 		}
-		clusterAddress := strings.Split(cluster.Address, ":")
-		if net.ParseIP(clusterAddress[0]) == nil {
+		host, port, err := net.SplitHostPort(cluster.Address)
+		if err != nil {
+			return nil, errors.Errorf("Error parsing address %s", cluster.Address)
+		}
+		if net.ParseIP(host) == nil {
 			return nil, errors.Errorf("Error parsing IP address %s", cluster.Address)
 		}
-		_, err := strconv.ParseUint(clusterAddress[1], 10, 32)
+		_, err = strconv.ParseUint(port, 10, 32)
 		if err != nil {
 			return nil, errors.Errorf("Error parsing port value %s", cluster.Address)
 		}
 		clusterNames[cluster.Name] = true
 	}
 
+	if err := validateFailoverSpec(config.Spec.Failover, clusterNames); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
+
+// validateFailoverSpec validates the failover policy of a MultiClusterService against the clusters it references.
+func validateFailoverSpec(failover *configv1alpha1.FailoverSpec, clusterNames map[string]bool) error {
+	if failover == nil {
+		return nil
+	}
+
+	switch failover.Mode {
+	case "", configv1alpha1.FailoverOnlyMode, configv1alpha1.ActiveActiveMode:
+		if len(failover.PreferredClusters) > 0 {
+			return errors.Errorf("Preferred clusters can only be specified in the %s failover mode", configv1alpha1.PreferredClustersMode)
+		}
+
+	case configv1alpha1.PreferredClustersMode:
+		if len(failover.PreferredClusters) == 0 {
+			return errors.Errorf("Preferred clusters must be specified in the %s failover mode", configv1alpha1.PreferredClustersMode)
+		}
+		preferred := make(map[string]bool)
+		for _, name := range failover.PreferredClusters {
+			if !clusterNames[name] {
+				return errors.Errorf("Preferred cluster %s is not a cluster of the service", name)
+			}
+			if preferred[name] {
+				return errors.Errorf("Preferred cluster %s is specified more than once", name)
+			}
+			preferred[name] = true
+		}
+
+	default:
+		return errors.Errorf("Invalid failover mode %s", failover.Mode)
+	}
+
+	if od := failover.OutlierDetection; od != nil {
+		if od.MaxEjectionPercent > 100 {
+			return errors.Errorf("Max ejection percent %d must not exceed 100", od.MaxEjectionPercent)
+		}
+		if od.Interval != nil && od.Interval.Duration <= 0 {
+			return errors.Errorf("Outlier detection interval %s must be positive", od.Interval.Duration)
+		}
+		if od.BaseEjectionTime != nil && od.BaseEjectionTime.Duration <= 0 {
+			return errors.Errorf("Outlier detection base ejection time %s must be positive", od.BaseEjectionTime.Duration)
+		}
+	}
+
+	return nil
+}
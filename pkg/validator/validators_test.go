@@ -372,6 +372,254 @@ func TestEgressValidator(t *testing.T) {
 			expResp:   nil,
 			expErrStr: "",
 		},
+		{
+			name: "Egress with IPv4 and IPv6 addresses passes",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"ipAddresses": ["10.0.0.0/24", "10.0.1.1", "fd00::/64", "fd00:1::1"]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "Egress with invalid IP address fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"ipAddresses": ["fd00::/129"]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "Invalid 'IPAddresses' value 'fd00::/129'. Expected an IPv4 or IPv6 address or CIDR range",
+		},
+		{
+			name: "Egress with TLS origination on an HTTP port passes",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"hosts": ["api.partner.com"],
+							"ports": [{"number": 80, "protocol": "http", "tls": {"caSecretName": "partner-ca"}}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "Egress with TLS origination on an HTTPS port fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"hosts": ["api.partner.com"],
+							"ports": [{"number": 443, "protocol": "https", "tls": {"caSecretName": "partner-ca"}}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "TLS origination is only supported for HTTP ports, got protocol https for port 443",
+		},
+		{
+			name: "Egress with TLS origination without a CA secret fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"hosts": ["api.partner.com"],
+							"ports": [{"number": 80, "protocol": "http", "tls": {"sni": "api.partner.com"}}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "Expected 'Ports.TLS.CASecretName' to be set for port 80",
+		},
+		{
+			name: "Egress with TLS origination without hosts fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"ipAddresses": ["10.0.0.0/24"],
+							"ports": [{"number": 80, "protocol": "http", "tls": {"caSecretName": "partner-ca"}}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "TLS origination for port 80 requires 'Hosts' to be specified",
+		},
+		{
+			name: "Egress with a wildcard host passes",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"hosts": ["*.example.com"],
+							"ports": [{"number": 80, "protocol": "http"}, {"number": 443, "protocol": "https"}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "Egress with a wildcard host not in the leftmost label fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"hosts": ["foo.*.com"],
+							"ports": [{"number": 80, "protocol": "http"}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "Invalid 'Hosts' value 'foo.*.com'. A wildcard is only allowed as the leftmost label, ex. '*.example.com'",
+		},
+		{
+			name: "Egress with TLS origination for a wildcard host fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"hosts": ["*.example.com"],
+							"ports": [{"number": 80, "protocol": "http", "tls": {"caSecretName": "partner-ca"}}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "TLS origination for port 80 is not supported with wildcard 'Hosts'",
+		},
+		{
+			name: "Egress with a wildcard host for a TCP port fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "Egress",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "Egress",
+						"spec": {
+							"hosts": ["*.example.com"],
+							"ports": [{"number": 5432, "protocol": "tcp"}]
+						}
+					}
+					`),
+				},
+			},
+
+			expResp:   nil,
+			expErrStr: "Wildcard 'Hosts' are not supported for TCP port 5432, since they cannot be resolved to IP addresses",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -380,6 +628,7 @@ func TestEgressValidator(t *testing.T) {
 
 			resp, err := egressValidator(tc.input)
 			assert.Equal(tc.expResp, resp)
+			assert.Equal(tc.expErrStr != "", err != nil)
 			if err != nil {
 				assert.Equal(tc.expErrStr, err.Error())
 			}
@@ -556,6 +805,296 @@ func TestMulticlusterServiceValidator(t *testing.T) {
 			expResp:   nil,
 			expErrStr: "Error parsing port value 0.0.0.0:a",
 		},
+		{
+			name: "MultiClusterService with IPv6 address passes",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "test",
+								"address": "[fd00::1]:15443"
+							}]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "MultiClusterService with IPv6 address without brackets fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "test",
+								"address": "fd00::1:15443"
+							}]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Error parsing address fd00::1:15443",
+		},
+		{
+			name: "MultiClusterService with preferred clusters failover passes",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "alpha",
+								"address": "0.0.0.0:8080"
+							},
+							{
+								"name": "beta",
+								"address": "0.0.0.1:8080"
+							}],
+							"failover": {
+								"mode": "PreferredClusters",
+								"preferredClusters": ["beta", "alpha"]
+							}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "MultiClusterService with invalid failover mode fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "alpha",
+								"address": "0.0.0.0:8080"
+							},
+							{
+								"name": "beta",
+								"address": "0.0.0.1:8080"
+							}],
+							"failover": {
+								"mode": "RoundRobin"
+							}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid failover mode RoundRobin",
+		},
+		{
+			name: "MultiClusterService with preferred clusters in the active-active mode fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "alpha",
+								"address": "0.0.0.0:8080"
+							},
+							{
+								"name": "beta",
+								"address": "0.0.0.1:8080"
+							}],
+							"failover": {
+								"mode": "ActiveActive",
+								"preferredClusters": ["beta"]
+							}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Preferred clusters can only be specified in the PreferredClusters failover mode",
+		},
+		{
+			name: "MultiClusterService without preferred clusters in the preferred clusters mode fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "alpha",
+								"address": "0.0.0.0:8080"
+							},
+							{
+								"name": "beta",
+								"address": "0.0.0.1:8080"
+							}],
+							"failover": {
+								"mode": "PreferredClusters"
+							}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Preferred clusters must be specified in the PreferredClusters failover mode",
+		},
+		{
+			name: "MultiClusterService with unknown preferred cluster fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "alpha",
+								"address": "0.0.0.0:8080"
+							},
+							{
+								"name": "beta",
+								"address": "0.0.0.1:8080"
+							}],
+							"failover": {
+								"mode": "PreferredClusters",
+								"preferredClusters": ["gamma"]
+							}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Preferred cluster gamma is not a cluster of the service",
+		},
+		{
+			name: "MultiClusterService with duplicate preferred clusters fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "alpha",
+								"address": "0.0.0.0:8080"
+							},
+							{
+								"name": "beta",
+								"address": "0.0.0.1:8080"
+							}],
+							"failover": {
+								"mode": "PreferredClusters",
+								"preferredClusters": ["beta", "beta"]
+							}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Preferred cluster beta is specified more than once",
+		},
+		{
+			name: "MultiClusterService with invalid max ejection percent fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "config.openservicemesh.io",
+					Kind:    "MultiClusterService",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "MultiClusterService",
+						"spec": {
+							"clusters": [{
+								"name": "alpha",
+								"address": "0.0.0.0:8080"
+							},
+							{
+								"name": "beta",
+								"address": "0.0.0.1:8080"
+							}],
+							"failover": {
+								"mode": "FailoverOnly",
+								"outlierDetection": {
+									"maxEjectionPercent": 150
+								}
+							}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Max ejection percent 150 must not exceed 100",
+		},
 	}
 
 	for _, tc := range testCases {